@@ -0,0 +1,27 @@
+package main
+
+import (
+	"RnD-service/internal/adapter/postgres"
+	"RnD-service/internal/adapter/provider"
+	"RnD-service/internal/adapter/webhook"
+	"RnD-service/internal/service"
+	"RnD-service/pkg/config"
+	"RnD-service/pkg/netguard"
+
+	"github.com/sirupsen/logrus"
+)
+
+func newAlertService(cfg *config.Config, db *postgres.PostgresRepo, providers []provider.RateProvider, allowlist netguard.Allowlist, log *logrus.Logger) *service.AlertService {
+	notifier := webhook.NewClient(log,
+		webhook.WithAllowlist(allowlist),
+		webhook.WithTimeout(cfg.Alerts.Webhook.Timeout),
+		webhook.WithRetry(cfg.Alerts.Webhook.MaxAttempts, cfg.Alerts.Webhook.BaseDelay, cfg.Alerts.Webhook.MaxDelay),
+		webhook.WithUserAgent(cfg.Alerts.Webhook.UserAgent),
+	)
+	alertService := service.NewAlertService(db, db, notifier, log)
+	alertService.SetCalendar(provider.SourceCBR, provider.CBRCalendar())
+	for _, p := range providers {
+		alertService.SetCalendar(p.Name(), p.Calendar())
+	}
+	return alertService
+}
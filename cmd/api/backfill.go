@@ -0,0 +1,116 @@
+package main
+
+import (
+	"RnD-service/internal/adapter/cbr"
+	"RnD-service/internal/adapter/postgres"
+	"RnD-service/internal/adapter/provider"
+	"RnD-service/internal/service"
+	"RnD-service/internal/usecase"
+	"RnD-service/pkg/config"
+	"RnD-service/pkg/logger"
+	"context"
+	"encoding/json"
+	"flag"
+	"log"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// runBackfill implements `rnd-service backfill -from YYYY-MM-DD [-to YYYY-MM-DD] [-codes USD,EUR] [-job ID]`.
+// Progress is stored in backfill_jobs, so an interrupted run can be continued with -job.
+func runBackfill(args []string) {
+	fs := flag.NewFlagSet("backfill", flag.ExitOnError)
+	fromStr := fs.String("from", "", "first date to backfill, YYYY-MM-DD")
+	toStr := fs.String("to", "", "last date to backfill, YYYY-MM-DD (default: today)")
+	codesStr := fs.String("codes", "", "comma-separated char codes to keep (default: all)")
+	jobID := fs.Int64("job", 0, "resume an existing job instead of creating a new one")
+	fs.Parse(args)
+
+	if *jobID == 0 && *fromStr == "" {
+		fs.Usage()
+		os.Exit(2)
+	}
+
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		log.Fatalf("Failed to load config: %v", err)
+	}
+
+	log := logger.Init(cfg.Log.Level, cfg.Log.Format)
+	log.AddHook(logger.NewRedactHook(cfg.Postgres.Password, logger.URLPassword(cfg.CBR.Proxy)))
+
+	dbPool, err := postgres.InitDBPool(*cfg, log)
+	if err != nil {
+		log.Fatalf("Failed to initialize db pools")
+	}
+	defer dbPool.Close()
+
+	cbrClient, err := newCBRClient(cfg, log)
+	if err != nil {
+		log.Fatalf("Failed to initialize CBR client: %v", err)
+	}
+
+	db := postgres.NewPostgresRepo(dbPool, log)
+	backfillService := newBackfillService(cfg, cbrClient, dbPool, db, log)
+	backfillUsecase := usecase.NewBackfillUsecase(backfillService, log)
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	var result *usecase.BackfillJobResponse
+	if *jobID != 0 {
+		result, err = backfillUsecase.RunExistingBackfill(ctx, *jobID)
+	} else {
+		from, parseErr := time.Parse("2006-01-02", *fromStr)
+		if parseErr != nil {
+			log.Fatalf("Invalid -from: %v", parseErr)
+		}
+		to := time.Now().Truncate(24 * time.Hour)
+		if *toStr != "" {
+			to, parseErr = time.Parse("2006-01-02", *toStr)
+			if parseErr != nil {
+				log.Fatalf("Invalid -to: %v", parseErr)
+			}
+		}
+		var codes []string
+		if *codesStr != "" {
+			codes = strings.Split(*codesStr, ",")
+		}
+		result, err = backfillUsecase.RunBackfill(ctx, from, to, codes)
+	}
+
+	if result != nil {
+		out, _ := json.MarshalIndent(result, "", "  ")
+		os.Stdout.Write(append(out, '\n'))
+	}
+	if err != nil {
+		log.Fatalf("Backfill failed: %v", err)
+	}
+	log.Info("Backfill finished")
+}
+
+// newBackfillService locks each job in the database, so the server replicas
+// and the CLI never run the same job at once.
+func newBackfillService(cfg *config.Config, cbrClient *cbr.Client, pool postgres.Pool, db *postgres.PostgresRepo, log *logrus.Logger) *service.BackfillService {
+	backfillService := service.NewBackfillService(provider.NewCBRProvider(cbrClient, log), db, db, backfillOptions(cfg), log)
+	backfillService.SetLocker(func(name string) postgres.Locker {
+		return postgres.NewAdvisoryLock(pool, name, log)
+	})
+	return backfillService
+}
+
+func backfillOptions(cfg *config.Config) service.BackfillOptions {
+	opts := service.BackfillOptions{
+		ChunkDays:   cfg.Backfill.ChunkDays,
+		Concurrency: cfg.Backfill.Concurrency,
+	}
+	if cfg.Backfill.RequestsPerSecond > 0 {
+		opts.RequestInterval = time.Duration(float64(time.Second) / cfg.Backfill.RequestsPerSecond)
+	}
+	return opts
+}
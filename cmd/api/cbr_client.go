@@ -0,0 +1,42 @@
+package main
+
+import (
+	"RnD-service/internal/adapter/cbr"
+	"RnD-service/pkg/config"
+
+	"github.com/sirupsen/logrus"
+)
+
+func newCBRClient(cfg *config.Config, log *logrus.Logger, extra ...cbr.Option) (*cbr.Client, error) {
+	return newCBRClientAt(cfg, log, cfg.CBR.BaseURL, cfg.CBR.DailyInfoURL, extra...)
+}
+
+// newCBRClientAt shares the transport and resilience settings with the main client.
+func newCBRClientAt(cfg *config.Config, log *logrus.Logger, baseURL, dailyInfoURL string, extra ...cbr.Option) (*cbr.Client, error) {
+	opts := []cbr.Option{
+		cbr.WithBaseURL(baseURL),
+		cbr.WithDailyInfoURL(dailyInfoURL),
+		cbr.WithTimeout(cfg.CBR.Timeout),
+		cbr.WithConnectTimeout(cfg.CBR.ConnectTimeout),
+		cbr.WithResponseHeaderTimeout(cfg.CBR.ResponseHeaderTimeout),
+		cbr.WithUserAgent(cfg.CBR.UserAgent),
+		cbr.WithProxy(cfg.CBR.Proxy),
+		cbr.WithCABundle(cfg.CBR.CABundle),
+		cbr.WithPolicy(cbr.Policy{
+			Retry: cbr.RetryPolicy{
+				MaxAttempts: cfg.CBR.Retry.MaxAttempts,
+				BaseDelay:   cfg.CBR.Retry.BaseDelay,
+				MaxDelay:    cfg.CBR.Retry.MaxDelay,
+			},
+			CircuitBreaker: cbr.BreakerPolicy{
+				FailureThreshold: cfg.CBR.CircuitBreaker.FailureThreshold,
+				OpenTimeout:      cfg.CBR.CircuitBreaker.OpenTimeout,
+			},
+			RateLimit: cbr.RateLimitPolicy{
+				RequestsPerSecond: cfg.CBR.RateLimit.RequestsPerSecond,
+				Burst:             cfg.CBR.RateLimit.Burst,
+			},
+		}),
+	}
+	return cbr.NewClient(log, append(opts, extra...)...)
+}
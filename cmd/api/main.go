@@ -3,11 +3,16 @@ package main
 import (
 	"RnD-service/internal/adapter/cbr"
 	"RnD-service/internal/adapter/postgres"
+	"RnD-service/internal/adapter/provider"
 	"RnD-service/internal/handler"
 	"RnD-service/internal/service"
 	"RnD-service/internal/usecase"
+	"RnD-service/migrations"
 	"RnD-service/pkg/config"
 	"RnD-service/pkg/logger"
+	"RnD-service/pkg/metrics"
+	"RnD-service/pkg/netguard"
+	"RnD-service/pkg/tracing"
 	"context"
 	"log"
 	"net/http"
@@ -18,53 +23,227 @@ import (
 
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
-	"github.com/robfig/cron/v3"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
 )
 
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "backfill" {
+		runBackfill(os.Args[2:])
+		return
+	}
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		runMigrate(os.Args[2:])
+		return
+	}
+
 	cfg, err := config.LoadConfig()
 	if err != nil {
 		log.Fatalf("Failed to load config: %v", err)
 	}
 
-	log := logger.Init(cfg.Log.Level)
+	log := logger.Init(cfg.Log.Level, cfg.Log.Format)
+	redact := logger.NewRedactHook(cfg.Postgres.Password, cfg.Admin.Token, logger.URLPassword(cfg.CBR.Proxy))
+	log.AddHook(redact)
 
 	log.Info("Starting app...")
 
+	// prometheus instrumentation, wired into the db pool, CBR clients, rate service and router
+	var (
+		appMetrics *metrics.Metrics
+		poolOpts   []postgres.PoolOption
+		cbrOpts    []cbr.Option
+	)
+	if cfg.Metrics.Enabled {
+		appMetrics = metrics.New()
+		poolOpts = append(poolOpts, postgres.WithQueryTracer(appMetrics.QueryTracer()))
+		cbrOpts = append(cbrOpts, cbr.WithObserver(appMetrics))
+	}
+
+	// opentelemetry spans for the gin router, use cases, rate service, CBR requests and db queries
+	var tracerProvider *sdktrace.TracerProvider
+	if cfg.Tracing.Enabled {
+		tracerProvider, err = tracing.Init(context.Background(), tracing.Options{
+			ServiceName: cfg.Tracing.ServiceName,
+			Endpoint:    cfg.Tracing.Endpoint,
+			Insecure:    cfg.Tracing.Insecure,
+			SampleRatio: cfg.Tracing.SampleRatio,
+		})
+		if err != nil {
+			log.Fatalf("Failed to initialize tracing: %v", err)
+		}
+		log.AddHook(tracing.LogHook{})
+		poolOpts = append(poolOpts, postgres.WithQueryTracer(tracing.QueryTracer(tracing.Tracer())))
+	}
+
 	// initialize db pools
-	dbPool, err := postgres.InitDBPool(*cfg, log)
+	dbPool, err := postgres.InitDBPool(*cfg, log, poolOpts...)
 	if err != nil {
 		log.Fatalf("Failed to initialize db pools")
 	}
+	if appMetrics != nil {
+		appMetrics.RegisterPool(dbPool)
+	}
+
+	migrator, err := postgres.NewMigrator(dbPool, migrations.FS, log)
+	if err != nil {
+		log.Fatalf("Failed to load migrations: %v", err)
+	}
+	if cfg.Postgres.AutoMigrate {
+		if err := migrator.Up(context.Background()); err != nil {
+			log.Fatalf("Failed to migrate database: %v", err)
+		}
+	} else if err := migrator.CheckVersion(context.Background()); err != nil {
+		log.Fatalf("Database schema check failed: %v", err)
+	}
 
 	// initialize adapters
-	cbrClient := cbr.NewClient(log)
+	cbrClient, err := newCBRClient(cfg, log, cbrOpts...)
+	if err != nil {
+		log.Fatalf("Failed to initialize CBR client: %v", err)
+	}
+	providers, err := newRateProviders(cfg, log, cbrOpts...)
+	if err != nil {
+		log.Fatalf("Failed to initialize rate providers: %v", err)
+	}
 	log.Info("Initialized API")
 
 	db := postgres.NewPostgresRepo(dbPool, log)
 	log.Info("Initialized database pool")
 
 	// initialize service
-	currencyService := service.NewRateService(cbrClient, db, log)
+	currencyService := service.NewRateService(cbrClient, db, log, providers...)
+	if err := currencyService.SetFallbacks(cfg.Fallback); err != nil {
+		log.Fatalf("Invalid fallback config: %v", err)
+	}
+	if appMetrics != nil {
+		currencyService.SetObserver(appMetrics)
+		if err := currencyService.SeedObserver(context.Background()); err != nil {
+			log.WithError(err).Warn("Rate metrics start empty until the first sync")
+		}
+	}
+	if tracerProvider != nil {
+		currencyService.SetTracer(tracing.Tracer())
+	}
+
+	var alertService *service.AlertService
+	webhookAllowlist, err := netguard.ParseAllowlist(cfg.Alerts.Webhook.AllowedNetworks)
+	if err != nil {
+		log.Fatalf("Invalid alerts.webhook.allowed_networks: %v", err)
+	}
+	if cfg.Alerts.Enabled {
+		alertService = newAlertService(cfg, db, providers, webhookAllowlist, log)
+		alertService.SetRedactHook(redact)
+		currencyService.SetAlerts(alertService)
+	}
 	log.Info("Initialized service layer")
 
 	// initialize usecase
-	currencyUsecase := usecase.NewCurrencyUsecase(currencyService, log)
+	rounding, err := usecase.NewRounding(cfg.Conversion.Scale, cfg.Conversion.RateScale, cfg.Conversion.Rounding)
+	if err != nil {
+		log.Fatalf("Invalid conversion config: %v", err)
+	}
+	currencyUsecase := usecase.NewCurrencyUsecase(currencyService, rounding, log)
+	if tracerProvider != nil {
+		currencyUsecase.SetTracer(tracing.Tracer())
+	}
 	log.Info("Initialized usecase layer")
 
 	currencyHandler := handler.NewRateHandler(currencyUsecase, log)
 
-	r := gin.Default()
+	metalUsecase := usecase.NewMetalPriceUsecase(service.NewMetalService(cbrClient, db, log), log)
+	metalHandler := handler.NewMetalHandler(metalUsecase, log)
+
+	indicatorUsecase := usecase.NewIndicatorRateUsecase(service.NewIndicatorService(cbrClient, db, log), log)
+	indicatorHandler := handler.NewIndicatorHandler(indicatorUsecase, log)
+
+	backfillService := newBackfillService(cfg, cbrClient, dbPool, db, log)
+	backfillHandler := handler.NewBackfillHandler(usecase.NewBackfillUsecase(backfillService, log), log)
+
+	var reconciliationUsecase *usecase.RateReconciliationUsecase
+	if cfg.Reconciliation.Enabled {
+		reconciliationService, err := newReconciliationService(cfg, cbrClient, providers, db, log)
+		if err != nil {
+			log.Fatalf("Invalid reconciliation config: %v", err)
+		}
+		reconciliationUsecase = usecase.NewRateReconciliationUsecase(reconciliationService, log)
+	}
+
+	// scheduled jobs, run on schedule by the replica holding the scheduler lock
+	jobs := map[string]func(ctx context.Context) error{
+		jobCBRRates:      currencyUsecase.FetchAndStoreRatesFromCBR,
+		jobCBRCatalog:    currencyService.SyncCurrencyCatalog,
+		jobCBRMetals:     metalUsecase.FetchAndStoreMetalPricesFromCBR,
+		jobCBRIndicators: indicatorUsecase.SyncIndicatorsFromCBR,
+	}
+	if cfg.ECB.Enabled {
+		jobs[jobECBRates] = func(ctx context.Context) error {
+			return currencyUsecase.FetchAndStoreRates(ctx, provider.SourceECB)
+		}
+	}
+	if cfg.NBK.Enabled {
+		jobs[jobNBKRates] = func(ctx context.Context) error {
+			return currencyUsecase.FetchAndStoreRates(ctx, provider.SourceNBK)
+		}
+	}
+	if reconciliationUsecase != nil {
+		jobs[jobReconcile] = func(ctx context.Context) error {
+			_, err := reconciliationUsecase.Reconcile(ctx, time.Time{})
+			return err
+		}
+	}
+	scheduler, err := newScheduler(cfg, dbPool, db, jobs, log)
+	if err != nil {
+		log.Fatalf("Invalid scheduler config: %v", err)
+	}
+	jobHandler := handler.NewJobHandler(usecase.NewScheduledJobUsecase(scheduler, log), log)
+
+	healthService := service.NewHealthService(db, migrator, provider.SourceCBR, provider.CBRCalendar(), cfg.Health.MaxRateLag, log)
+	healthService.SetSyncJob(scheduler, jobCBRRates)
+	healthHandler := handler.NewHealthHandler(usecase.NewHealthCheckUsecase(healthService, log), log)
+
+	var alertHandler *handler.AlertHandler
+	if alertService != nil {
+		alertUsecase := usecase.NewRateAlertUsecase(alertService, currencyService, log)
+		alertUsecase.SetWebhookAllowlist(webhookAllowlist)
+		alertHandler = handler.NewAlertHandler(alertUsecase, log)
+	}
+
+	r := gin.New()
+	r.Use(gin.Recovery())
+
+	// request ID for every log line of a request, then the access log in the app log format
+	r.Use(handler.RequestIDMiddleware())
+	r.Use(handler.AccessLogMiddleware(log))
 
 	// cors middleware
 	r.Use(cors.New(cors.Config{
 		AllowOrigins:     []string{"http://localhost:8080", "http://127.0.0.1:8080"},
-		AllowMethods:     []string{"GET", "POST", "OPTIONS"},
-		AllowHeaders:     []string{"Origin", "Content-Type", "Accept"},
-		ExposeHeaders:    []string{"Content-Length"},
+		AllowMethods:     []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
+		AllowHeaders:     []string{"Origin", "Content-Type", "Accept", handler.RequestIDHeader},
+		ExposeHeaders:    []string{"Content-Length", handler.RequestIDHeader},
 		AllowCredentials: false,
 	}))
 
+	// server span per request, continuing a trace propagated by the caller
+	if tracerProvider != nil {
+		r.Use(otelgin.Middleware(cfg.Tracing.ServiceName))
+	}
+
+	// request count and latency per route, registered before the error middleware to see mapped statuses
+	if appMetrics != nil {
+		r.Use(appMetrics.Middleware())
+		r.GET("/metrics", gin.WrapH(appMetrics.Handler()))
+	}
+
+	// maps domain errors to HTTP status and error code
+	r.Use(handler.ErrorMiddleware(log))
+
+	// orchestrator probes and the dashboard status
+	r.GET("/livez", healthHandler.Livez)
+	r.GET("/readyz", healthHandler.Readyz)
+	r.GET("/health/details", healthHandler.GetHealthDetails)
+
 	// dashboard usage
 	r.Static("/static", "./static")
 
@@ -73,42 +252,60 @@ func main() {
 		c.File("./static/index.html")
 	})
 
-	r.GET("/currency/rates", currencyHandler.StoreRatesFromCBR)          // api fetching
-	r.GET("/currency/rate", currencyHandler.GetHistoricalRateByCharCode) // post req by char code n date
+	r.GET("/currency/rates", currencyHandler.StoreRatesFromCBR)                // api fetching
+	r.GET("/currency/rate", currencyHandler.GetHistoricalRateByCharCode)       // post req by char code n date
+	r.GET("/currency/rates/history", currencyHandler.GetRateHistoryByCharCode) // rates series for date range
+	r.GET("/currency/stats", currencyHandler.GetRateStats)                     // statistics over stored history
+	r.GET("/currency/convert", currencyHandler.ConvertCurrency)                // cross-currency conversion
+	r.POST("/currency/convert/batch", currencyHandler.ConvertBatch)            // batch conversion
+	r.GET("/currency/list", currencyHandler.GetCurrencyList)                   // CBR currency catalog
 
-	// task sheduler
-	c := cron.New()
+	r.GET("/metals/price", metalHandler.GetMetalPrice)                // precious metal price by code n date
+	r.GET("/metals/price/history", metalHandler.GetMetalPriceHistory) // metal prices for date range
 
-	// every day 10 AM Moscow
-	_, err = c.AddFunc("0 13 * * *", func() {
-		log.Info("Auto updating valutes...")
-		ctx := context.Background()
-		err := currencyUsecase.FetchAndStoreRatesFromCBR(ctx)
-		if err != nil {
-			log.Errorf("Error by update valutes: %v", err)
-		} else {
-			log.Info("Successfylu update valutes")
-		}
-	})
+	r.GET("/indicators/keyrate", indicatorHandler.GetKeyRateHistory) // CBR key rate for date range
+	r.GET("/indicators/ruonia", indicatorHandler.GetRuoniaHistory)   // RUONIA for date range
 
-	if err != nil {
-		log.Fatalf("Error by add task to shedule: %v", err)
+	// admin endpoints require the configured bearer token
+	if cfg.Admin.Token == "" {
+		log.Warn("admin.token is not set, /admin endpoints reject all requests")
 	}
+	admin := r.Group("/admin", handler.AdminAuthMiddleware(cfg.Admin.Token))
 
-	c.Start()
-	log.Info("Sheduler initialized. Course updating every day in 10 AM")
+	// historical backfill jobs
+	admin.POST("/backfill", backfillHandler.StartBackfill)
+	admin.GET("/backfill", backfillHandler.ListBackfillJobs)
+	admin.GET("/backfill/:id", backfillHandler.GetBackfillJob)
+	admin.POST("/backfill/:id/resume", backfillHandler.ResumeBackfill)
 
-	go func() {
-		log.Info("Updating valutes...")
-		time.Sleep(2 * time.Second)
-		ctx := context.Background()
-		err := currencyUsecase.FetchAndStoreRatesFromCBR(ctx)
-		if err != nil {
-			log.Errorf("Error updating valutes by server start: %v", err)
-		} else {
-			log.Info("Successfuly updatet valutes by server start")
-		}
-	}()
+	// scheduled jobs and their last runs
+	admin.GET("/jobs", jobHandler.ListJobs)
+	admin.POST("/jobs/:name/run", jobHandler.RunJob)
+
+	// cross-source rate reconciliation
+	if reconciliationUsecase != nil {
+		reconciliationHandler := handler.NewReconciliationHandler(reconciliationUsecase, log)
+		admin.POST("/reconcile", reconciliationHandler.Reconcile)
+		admin.GET("/discrepancies", reconciliationHandler.GetDiscrepancies)
+	}
+
+	// rate change alerts
+	if alertHandler != nil {
+		admin.POST("/alerts/rules", alertHandler.CreateAlertRule)
+		admin.GET("/alerts/rules", alertHandler.ListAlertRules)
+		admin.GET("/alerts/rules/:id", alertHandler.GetAlertRule)
+		admin.PUT("/alerts/rules/:id", alertHandler.UpdateAlertRule)
+		admin.DELETE("/alerts/rules/:id", alertHandler.DeleteAlertRule)
+		admin.GET("/alerts", alertHandler.GetAlerts)
+		admin.GET("/alerts/dead-letters", alertHandler.GetDeadLetters)
+	}
+
+	scheduler.Start()
+	log.Info("Scheduler started")
+
+	if err := backfillService.ResumeUnfinished(context.Background()); err != nil {
+		log.Errorf("Error resuming backfill jobs: %v", err)
+	}
 
 	srv := &http.Server{
 		Addr:    ":8080",
@@ -135,8 +332,25 @@ func main() {
 	}
 	log.Info("Server stopped")
 
-	c.Stop()
-	log.Info("Sheduler stopped")
+	scheduler.Shutdown()
+	log.Info("Scheduler stopped")
+
+	backfillService.Shutdown()
+	log.Info("Backfill jobs stopped")
+
+	if alertService != nil {
+		alertService.Shutdown()
+		log.Info("Alert deliveries stopped")
+	}
+
+	if tracerProvider != nil {
+		flushCtx, flushCancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer flushCancel()
+		if err := tracerProvider.Shutdown(flushCtx); err != nil {
+			log.Errorf("Error flushing traces: %v", err)
+		}
+		log.Info("Tracer stopped")
+	}
 
 	log.Info("Gracefuly shutdowned")
 }
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"RnD-service/internal/adapter/postgres"
+	"RnD-service/migrations"
+	"RnD-service/pkg/config"
+	"RnD-service/pkg/logger"
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
+)
+
+const migrateUsage = `usage: rnd-service migrate <command>
+
+commands:
+  up                 apply all pending migrations
+  down [-steps N]    revert the last N migrations (default 1)
+  status             print the schema version and pending migrations
+  force VERSION      mark VERSION (0 or a known migration) as applied and clean
+                     without running it
+`
+
+// runMigrate implements `rnd-service migrate up|down|status|force` on the
+// migrations embedded into the binary.
+func runMigrate(args []string) {
+	if len(args) == 0 {
+		fmt.Fprint(os.Stderr, migrateUsage)
+		os.Exit(2)
+	}
+
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		log.Fatalf("Failed to load config: %v", err)
+	}
+
+	log := logger.Init(cfg.Log.Level, cfg.Log.Format)
+	log.AddHook(logger.NewRedactHook(cfg.Postgres.Password))
+
+	dbPool, err := postgres.InitDBPool(*cfg, log)
+	if err != nil {
+		log.Fatalf("Failed to initialize db pools")
+	}
+	defer dbPool.Close()
+
+	migrator, err := postgres.NewMigrator(dbPool, migrations.FS, log)
+	if err != nil {
+		log.Fatalf("Failed to load migrations: %v", err)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	switch args[0] {
+	case "up":
+		err = migrator.Up(ctx)
+	case "down":
+		fs := flag.NewFlagSet("migrate down", flag.ExitOnError)
+		steps := fs.Int("steps", 1, "number of migrations to revert")
+		fs.Parse(args[1:])
+		err = migrator.Down(ctx, *steps)
+	case "status":
+		var status *postgres.MigrationStatus
+		status, err = migrator.Status(ctx)
+		if err == nil {
+			printMigrationStatus(status)
+		}
+	case "force":
+		if len(args) != 2 {
+			fmt.Fprint(os.Stderr, migrateUsage)
+			os.Exit(2)
+		}
+		version, parseErr := strconv.ParseInt(args[1], 10, 64)
+		if parseErr != nil {
+			log.Fatalf("Invalid version %q: %v", args[1], parseErr)
+		}
+		err = migrator.Force(ctx, version)
+	default:
+		fmt.Fprint(os.Stderr, migrateUsage)
+		os.Exit(2)
+	}
+
+	if err != nil {
+		log.Fatalf("Migrate %s failed: %v", args[0], err)
+	}
+}
+
+func printMigrationStatus(status *postgres.MigrationStatus) {
+	dirty := ""
+	if status.Dirty {
+		dirty = " (dirty)"
+	}
+	fmt.Printf("version: %d%s\nlatest:  %d\n", status.Version, dirty, status.Latest)
+	for _, m := range status.Applied {
+		fmt.Printf("  applied  %03d_%s\n", m.Version, m.Name)
+	}
+	for _, m := range status.Pending {
+		fmt.Printf("  pending  %03d_%s\n", m.Version, m.Name)
+	}
+}
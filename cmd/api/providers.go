@@ -0,0 +1,83 @@
+package main
+
+import (
+	"RnD-service/internal/adapter/cbr"
+	"RnD-service/internal/adapter/ecb"
+	"RnD-service/internal/adapter/nbk"
+	"RnD-service/internal/adapter/postgres"
+	"RnD-service/internal/adapter/provider"
+	"RnD-service/internal/service"
+	"RnD-service/pkg/config"
+	"fmt"
+
+	"github.com/shopspring/decimal"
+	"github.com/sirupsen/logrus"
+)
+
+// newRateProviders returns the optional providers next to CBR, which the rate service always registers.
+func newRateProviders(cfg *config.Config, log *logrus.Logger, cbrOpts ...cbr.Option) ([]provider.RateProvider, error) {
+	var providers []provider.RateProvider
+
+	if cfg.CBR.Mirror.BaseURL != "" {
+		dailyInfoURL := cfg.CBR.Mirror.DailyInfoURL
+		if dailyInfoURL == "" {
+			dailyInfoURL = cfg.CBR.DailyInfoURL
+		}
+		mirrorClient, err := newCBRClientAt(cfg, log, cfg.CBR.Mirror.BaseURL, dailyInfoURL, cbrOpts...)
+		if err != nil {
+			return nil, fmt.Errorf("CBR mirror: %w", err)
+		}
+		providers = append(providers, provider.NewCBRMirrorProvider(provider.SourceCBRMirror, mirrorClient, log))
+	}
+
+	if cfg.ECB.Enabled {
+		ecbClient, err := ecb.NewClient(log,
+			ecb.WithBaseURL(cfg.ECB.BaseURL),
+			ecb.WithTimeout(cfg.ECB.Timeout),
+			ecb.WithUserAgent(cfg.ECB.UserAgent),
+		)
+		if err != nil {
+			return nil, fmt.Errorf("ECB: %w", err)
+		}
+		providers = append(providers, provider.NewECBProvider(ecbClient, log))
+	}
+
+	if cfg.NBK.Enabled {
+		nbkClient, err := nbk.NewClient(log,
+			nbk.WithBaseURL(cfg.NBK.BaseURL),
+			nbk.WithTimeout(cfg.NBK.Timeout),
+			nbk.WithUserAgent(cfg.NBK.UserAgent),
+			nbk.WithRetry(cfg.NBK.Retry.MaxAttempts, cfg.NBK.Retry.BaseDelay, cfg.NBK.Retry.MaxDelay),
+			nbk.WithRateLimit(cfg.NBK.RateLimit.RequestsPerSecond, cfg.NBK.RateLimit.Burst),
+		)
+		if err != nil {
+			return nil, fmt.Errorf("NBK: %w", err)
+		}
+		providers = append(providers, provider.NewNBKProvider(nbkClient, log))
+	}
+
+	return providers, nil
+}
+
+func newReconciliationService(cfg *config.Config, cbrClient cbr.CbrClient, providers []provider.RateProvider, repo postgres.DiscrepancyRepository, log *logrus.Logger) (*service.ReconciliationService, error) {
+	byName := map[string]provider.RateProvider{
+		provider.SourceCBR: provider.NewCBRProvider(cbrClient, log),
+	}
+	for _, p := range providers {
+		byName[p.Name()] = p
+	}
+
+	primary, ok := byName[cfg.Reconciliation.Primary]
+	if !ok {
+		return nil, fmt.Errorf("primary source %q is not enabled", cfg.Reconciliation.Primary)
+	}
+	secondary, ok := byName[cfg.Reconciliation.Secondary]
+	if !ok {
+		return nil, fmt.Errorf("secondary source %q is not enabled", cfg.Reconciliation.Secondary)
+	}
+	if primary.Name() == secondary.Name() {
+		return nil, fmt.Errorf("primary and secondary source are both %q", primary.Name())
+	}
+
+	return service.NewReconciliationService(primary, secondary, repo, decimal.NewFromFloat(cfg.Reconciliation.ThresholdPercent), log), nil
+}
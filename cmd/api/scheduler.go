@@ -0,0 +1,82 @@
+package main
+
+import (
+	"RnD-service/internal/adapter/postgres"
+	"RnD-service/internal/service"
+	"RnD-service/pkg/config"
+	"context"
+	"fmt"
+	"os"
+	"slices"
+	"time"
+
+	// the alpine image ships no zoneinfo for scheduler.timezone
+	_ "time/tzdata"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	jobCBRRates      = "cbr_rates"
+	jobCBRCatalog    = "cbr_catalog"
+	jobCBRMetals     = "cbr_metals"
+	jobCBRIndicators = "cbr_indicators"
+	jobECBRates      = "ecb_rates"
+	jobNBKRates      = "nbk_rates"
+	jobReconcile     = "reconcile"
+)
+
+// jobNames are the jobs scheduler.jobs may configure, in registration order.
+var jobNames = []string{jobCBRRates, jobCBRCatalog, jobCBRMetals, jobCBRIndicators, jobECBRates, jobNBKRates, jobReconcile}
+
+// newScheduler registers jobs with their settings from scheduler.jobs. Jobs of
+// disabled features are missing from jobs and skipped; a configured name that
+// is not a job fails, so a typo does not leave a job unscheduled.
+func newScheduler(cfg *config.Config, pool postgres.Pool, repo postgres.JobRepository, jobs map[string]func(ctx context.Context) error, log *logrus.Logger) (*service.SchedulerService, error) {
+	location, err := time.LoadLocation(cfg.Scheduler.Timezone)
+	if err != nil {
+		return nil, fmt.Errorf("timezone %q: %w", cfg.Scheduler.Timezone, err)
+	}
+	for name := range cfg.Scheduler.Jobs {
+		if !slices.Contains(jobNames, name) {
+			return nil, fmt.Errorf("unknown job %q, expected one of %v", name, jobNames)
+		}
+	}
+
+	instance, err := os.Hostname()
+	if err != nil {
+		instance = fmt.Sprintf("pid-%d", os.Getpid())
+	}
+
+	scheduler := service.NewSchedulerService(repo, func(name string) postgres.Locker {
+		return postgres.NewAdvisoryLock(pool, name, log)
+	}, service.SchedulerOptions{
+		Location:            location,
+		LeaderCheckInterval: cfg.Scheduler.LeaderCheckInterval,
+		MaxAttempts:         cfg.Scheduler.Retry.MaxAttempts,
+		BaseDelay:           cfg.Scheduler.Retry.BaseDelay,
+		MaxDelay:            cfg.Scheduler.Retry.MaxDelay,
+		Instance:            instance,
+	}, log)
+
+	for _, name := range jobNames {
+		run, ok := jobs[name]
+		if !ok {
+			continue
+		}
+		jobCfg := cfg.Scheduler.Jobs[name]
+		if !jobCfg.Enabled {
+			log.Infof("Job %s is disabled", name)
+			continue
+		}
+		if err := scheduler.AddJob(service.Job{
+			Name:       name,
+			Schedule:   jobCfg.Schedule,
+			RunOnStart: jobCfg.RunOnStart,
+			Run:        run,
+		}); err != nil {
+			return nil, err
+		}
+	}
+	return scheduler, nil
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"RnD-service/pkg/fakecbr"
+	"RnD-service/pkg/logger"
+	"context"
+	"flag"
+	"io/fs"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+)
+
+func main() {
+	addr := flag.String("addr", ":8090", "listen address")
+	fixturesDir := flag.String("fixtures", "", "directory with daily/YYYY-MM-DD.xml fixtures (default: bundled January 2023 sample)")
+	weekendShift := flag.Bool("weekend-shift", true, "serve Saturday's rates for Sunday and Monday like CBR")
+	latency := flag.Duration("latency", 0, "delay added to every response")
+	jitter := flag.Duration("jitter", 0, "random extra delay up to this value")
+	errorRate := flag.Float64("error-rate", 0, "share of requests (0..1) answered with -error-status")
+	errorStatus := flag.Int("error-status", http.StatusServiceUnavailable, "HTTP status for injected errors")
+	seed := flag.Uint64("seed", 1, "seed for jitter and error injection")
+	logLevel := flag.String("log-level", "info", "log level")
+	flag.Parse()
+
+	log := logger.Init(*logLevel, "text")
+
+	var fixtures fs.FS = fakecbr.DefaultFixtures
+	if *fixturesDir != "" {
+		fixtures = os.DirFS(*fixturesDir)
+	}
+
+	server, err := fakecbr.NewServer(fakecbr.Config{
+		Fixtures:     fixtures,
+		WeekendShift: *weekendShift,
+		Latency:      *latency,
+		Jitter:       *jitter,
+		ErrorRate:    *errorRate,
+		ErrorStatus:  *errorStatus,
+		Seed:         *seed,
+	}, log)
+	if err != nil {
+		log.Fatalf("Failed to initialize fake CBR: %v", err)
+	}
+
+	srv := &http.Server{
+		Addr:    *addr,
+		Handler: server,
+	}
+
+	go func() {
+		log.Infof("Fake CBR listening on %s, base URL http://localhost%s/scripts", *addr, *addr)
+		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			log.Fatalf("listen: %s\n", err)
+		}
+	}()
+
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+	<-quit
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := srv.Shutdown(ctx); err != nil {
+		log.Fatal("Error server shutdown:", err)
+	}
+	log.Info("Fake CBR stopped")
+}
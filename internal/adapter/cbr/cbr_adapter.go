@@ -3,6 +3,8 @@ package cbr
 import (
 	"bytes"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/xml"
 	"errors"
 	"fmt"
@@ -12,104 +14,162 @@ import (
 	"time"
 
 	"golang.org/x/text/encoding/charmap"
+	"golang.org/x/time/rate"
 
 	"github.com/sirupsen/logrus"
 )
 
 type Client struct {
-	httpClient *http.Client
-	baseURL    string
-	logger     *logrus.Logger
+	httpClient   *http.Client
+	baseURL      string
+	dailyInfoURL string
+	userAgent    string
+	policy       Policy
+	breaker      *circuitBreaker
+	limiter      *rate.Limiter
+	observer     FetchObserver
+	logger       *logrus.Logger
 }
 
-func NewClient(logger *logrus.Logger) *Client {
-	return &Client{
-		httpClient: &http.Client{
-			Timeout: 30 * time.Second,
-			Transport: &http.Transport{
-				ResponseHeaderTimeout: 30 * time.Second,
-			},
-		},
-		baseURL: "https://www.cbr.ru/scripts",
-		logger:  logger,
+func NewClient(logger *logrus.Logger, opts ...Option) (*Client, error) {
+	o := defaultOptions()
+	for _, opt := range opts {
+		if err := opt(&o); err != nil {
+			return nil, fmt.Errorf("configure CBR client: %w", err)
+		}
 	}
+
+	return &Client{
+		httpClient:   o.httpClient(),
+		baseURL:      o.baseURL,
+		dailyInfoURL: o.dailyInfoURL,
+		userAgent:    o.userAgent,
+		policy:       o.policy,
+		breaker:      newCircuitBreaker(o.policy.CircuitBreaker),
+		limiter:      newLimiter(o.policy.RateLimit),
+		observer:     o.observer,
+		logger:       logger,
+	}, nil
 }
 
 func (c *Client) FetchRates(ctx context.Context, date string) (*ValCurs, error) {
+	logger := c.logger.WithContext(ctx)
 	url := fmt.Sprintf("%s/XML_daily.asp?date_req=%s", c.baseURL, date)
 
-	c.logger.Infof("Fetching rates from URL: %s", url)
+	var valCurs ValCurs
+	if err := c.fetchXML(ctx, url, &valCurs); err != nil {
+		return nil, err
+	}
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
-	if err != nil {
-		c.logger.Errorf("Failed to create request: %v", err)
-		return nil, fmt.Errorf("create request: %w", err)
+	logger.Infof("Successfully parsed %d currencies", len(valCurs.Valutes))
+	logger.Debugf("First valute: CharCode=%s, Value=%s", valCurs.Valutes[0].CharCode, valCurs.Valutes[0].Value)
+
+	return &valCurs, nil
+}
+
+func (c *Client) FetchDynamicRates(ctx context.Context, valuteID, dateFrom, dateTo string) (*ValCursDynamic, error) {
+	logger := c.logger.WithContext(ctx)
+	url := fmt.Sprintf("%s/XML_dynamic.asp?date_req1=%s&date_req2=%s&VAL_NM_RQ=%s", c.baseURL, dateFrom, dateTo, valuteID)
+
+	var valCurs ValCursDynamic
+	if err := c.fetchXML(ctx, url, &valCurs); err != nil {
+		return nil, err
 	}
 
-	// Заголовки для имитации браузера
-	req.Header.Set("User-Agent", "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/91.0.4472.124 Safari/537.36")
-	req.Header.Set("Accept", "text/html,application/xhtml+xml,application/xml;q=0.9,*/*;q=0.8")
-	req.Header.Set("Accept-Language", "ru-RU,ru;q=0.9,en-US;q=0.8,en;q=0.7")
-	req.Header.Set("Accept-Encoding", "identity")
+	logger.Infof("Successfully parsed %d dynamic records for %s", len(valCurs.Records), valuteID)
+	if len(valCurs.Records) == 0 {
+		logger.Warnf("No records found in dynamic response for %s", valuteID)
+	}
 
-	resp, err := c.httpClient.Do(req)
-	if err != nil {
-		c.logger.Errorf("Failed to fetch by API: %v", err)
-		return nil, fmt.Errorf("fetch error: %w", err)
+	return &valCurs, nil
+}
+
+// FetchCurrencyCatalog loads the CBR currency directory: d=0 lists currencies
+// with daily rates, d=1 those with monthly rates.
+func (c *Client) FetchCurrencyCatalog(ctx context.Context, monthly bool) (*Valuta, error) {
+	logger := c.logger.WithContext(ctx)
+	d := 0
+	if monthly {
+		d = 1
 	}
-	defer resp.Body.Close()
+	url := fmt.Sprintf("%s/XML_val.asp?d=%d", c.baseURL, d)
 
-	c.logger.Infof("Response status: %d", resp.StatusCode)
+	var valuta Valuta
+	if err := c.fetchXML(ctx, url, &valuta); err != nil {
+		return nil, err
+	}
 
-	if resp.StatusCode != http.StatusOK {
-		body, err := io.ReadAll(resp.Body)
-		if err != nil {
-			return nil, fmt.Errorf("error read response body: %w", err)
-		}
-		c.logger.Debugf("Response body length: %d", len(body))
+	logger.Infof("Successfully parsed %d catalog items (d=%d)", len(valuta.Items), d)
+	if len(valuta.Items) == 0 {
+		logger.Warnf("No items found in currency catalog response (d=%d)", d)
 	}
 
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		c.logger.Errorf("Failed to read response body: %v", err)
-		return nil, fmt.Errorf("read response body: %w", err)
+	return &valuta, nil
+}
+
+// FetchMetalPrices loads precious metal prices for every trading day between
+// dateFrom and dateTo (DD/MM/YYYY, inclusive).
+func (c *Client) FetchMetalPrices(ctx context.Context, dateFrom, dateTo string) (*Metall, error) {
+	logger := c.logger.WithContext(ctx)
+	url := fmt.Sprintf("%s/XML_metall.asp?date_req1=%s&date_req2=%s", c.baseURL, dateFrom, dateTo)
+
+	var metall Metall
+	if err := c.fetchXML(ctx, url, &metall); err != nil {
+		return nil, err
 	}
-	if len(body) == 0 {
-		c.logger.Error("Empty response body from CBR")
-		return nil, errors.New("empty response body")
+
+	logger.Infof("Successfully parsed %d metal price records for %s - %s", len(metall.Records), dateFrom, dateTo)
+	if len(metall.Records) == 0 {
+		logger.Warnf("No metal prices found for %s - %s", dateFrom, dateTo)
+	}
+
+	return &metall, nil
+}
+
+func (c *Client) fetchXML(ctx context.Context, url string, v any) error {
+	body, err := c.fetchWithRetry(ctx, url, c.getRequest(url))
+	if err != nil {
+		return err
 	}
 
-	c.logger.Debugf("Response body length: %d bytes", len(body))
-	c.logger.Debugf("First 200 chars: %s", string(body)[:min(200, len(body))])
+	return c.decodeXML(ctx, url, body, v)
+}
+
+func (c *Client) decodeXML(ctx context.Context, url string, body []byte, v any) error {
+	logger := c.logger.WithContext(ctx)
+	logger.Debugf("Response body length: %d bytes", len(body))
+	logger.Debugf("First 200 chars: %s", string(body)[:min(200, len(body))])
 
 	decoder := xml.NewDecoder(bytes.NewReader(body))
 	decoder.CharsetReader = func(charset string, input io.Reader) (io.Reader, error) {
 		lower := strings.ToLower(charset)
 		if lower == "windows-1251" || lower == "cp1251" {
-			c.logger.Debugf("Using charset: %s", charset)
+			logger.Debugf("Using charset: %s", charset)
 			return charmap.Windows1251.NewDecoder().Reader(input), nil
 		}
-		c.logger.Errorf("Unsupported charset: %s", charset)
+		logger.Errorf("Unsupported charset: %s", charset)
 		return nil, fmt.Errorf("unsupported charset: %s", charset)
 	}
 
-	var valCurs ValCurs
-	if err := decoder.Decode(&valCurs); err != nil {
-		c.logger.Errorf("Failed to parse XML CBR: %v", err)
-		c.logger.Debugf("First 500 chars: %s", string(body)[:min(500, len(body))])
-		return nil, fmt.Errorf("parse XML: %w", err)
+	if err := decoder.Decode(v); err != nil {
+		logger.Errorf("Failed to parse XML CBR: %v", err)
+		logger.Debugf("First 500 chars: %s", string(body)[:min(500, len(body))])
+		return newUpstreamError(url, http.StatusOK, body, fmt.Errorf("%w: parse XML: %w", ErrInvalidPayload, err))
 	}
 
-	c.logger.Infof("Successfully parsed %d currencies", len(valCurs.Valutes))
+	if p, ok := v.(payload); ok {
+		if err := p.validate(); err != nil {
+			logger.Errorf("CBR response from %s failed validation: %v", url, err)
+			return newUpstreamError(url, http.StatusOK, body, err)
+		}
+	}
 
-	c.logger.Infof("Successfully parsed %d currencies", len(valCurs.Valutes))
-	if len(valCurs.Valutes) > 0 {
-		c.logger.Debugf("First valute: CharCode=%s, Value=%s", valCurs.Valutes[0].CharCode, valCurs.Valutes[0].Value)
-	} else {
-		c.logger.Warn("No valutes found in parsed response")
+	if h, ok := v.(hashedPayload); ok {
+		sum := sha256.Sum256(body)
+		h.setPayloadHash(hex.EncodeToString(sum[:]))
 	}
 
-	return &valCurs, nil
+	return nil
 }
 
 func min(a, b int) int {
@@ -118,3 +178,135 @@ func min(a, b int) int {
 	}
 	return b
 }
+
+// requestFunc builds a fresh request for every attempt, so request bodies can be re-sent.
+type requestFunc func(ctx context.Context) (*http.Request, error)
+
+// fetchWithRetry is only used for read-only calls (GET and DailyInfo queries),
+// so every attempt is safe to repeat. Only transient failures are retried; each
+// attempt goes through the circuit breaker and the rate limiter.
+func (c *Client) fetchWithRetry(ctx context.Context, url string, newRequest requestFunc) ([]byte, error) {
+	logger := c.logger.WithContext(ctx)
+	maxAttempts := max(c.policy.Retry.MaxAttempts, 1)
+
+	for attempt := 1; ; attempt++ {
+		if err := c.limiter.Wait(ctx); err != nil {
+			return nil, fmt.Errorf("wait for rate limiter: %w", err)
+		}
+		if err := c.breaker.allow(); err != nil {
+			logger.Warnf("CBR circuit breaker is open, skipping request to %s", url)
+			return nil, err
+		}
+
+		started := time.Now()
+		body, err := c.doRequest(ctx, url, newRequest)
+		if c.observer != nil {
+			c.observer.ObserveFetch(url, time.Since(started), len(body), err)
+		}
+		if err == nil {
+			c.breaker.success()
+			return body, nil
+		}
+		if ctx.Err() != nil {
+			c.breaker.release()
+			return nil, err
+		}
+		if !isTransient(err) {
+			c.breaker.success()
+			return nil, err
+		}
+
+		if c.breaker.failure() {
+			logger.Errorf("CBR circuit breaker opened for %s after: %v", c.policy.CircuitBreaker.OpenTimeout, err)
+		}
+		if attempt >= maxAttempts {
+			return nil, fmt.Errorf("giving up after %d attempts: %w", attempt, err)
+		}
+
+		delay := c.policy.Retry.backoff(attempt)
+		var ue *UpstreamError
+		if errors.As(err, &ue) && ue.RetryAfter > delay {
+			if c.policy.Retry.MaxDelay > 0 && ue.RetryAfter > c.policy.Retry.MaxDelay {
+				logger.Warnf("CBR asked to retry after %s, more than max delay %s, giving up", ue.RetryAfter, c.policy.Retry.MaxDelay)
+				return nil, err
+			}
+			delay = ue.RetryAfter
+		}
+
+		logger.Warnf("CBR request failed (attempt %d/%d): %v, retrying in %s", attempt, maxAttempts, err, delay)
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, fmt.Errorf("retry cancelled: %w", ctx.Err())
+		case <-timer.C:
+		}
+	}
+}
+
+func (c *Client) getRequest(url string) requestFunc {
+	return func(ctx context.Context) (*http.Request, error) {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		// Заголовки для имитации браузера
+		req.Header.Set("User-Agent", c.userAgent)
+		req.Header.Set("Accept", "text/html,application/xhtml+xml,application/xml;q=0.9,*/*;q=0.8")
+		req.Header.Set("Accept-Language", "ru-RU,ru;q=0.9,en-US;q=0.8,en;q=0.7")
+		req.Header.Set("Accept-Encoding", "identity")
+		return req, nil
+	}
+}
+
+func (c *Client) doRequest(ctx context.Context, url string, newRequest requestFunc) ([]byte, error) {
+	logger := c.logger.WithContext(ctx)
+	logger.Infof("Fetching rates from URL: %s", url)
+
+	req, err := newRequest(ctx)
+	if err != nil {
+		logger.Errorf("Failed to create request: %v", err)
+		return nil, fmt.Errorf("create request: %w", err)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		logger.Errorf("Failed to fetch by API: %v", err)
+		return nil, fmt.Errorf("fetch error: %w", err)
+	}
+	defer resp.Body.Close()
+
+	logger.Infof("Response status: %d", resp.StatusCode)
+
+	if resp.StatusCode != http.StatusOK {
+		// read the whole (bounded) body so the connection can be reused and SOAP faults parsed
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 64<<10))
+
+		cause := fmt.Errorf("%w %d", ErrUnexpectedStatus, resp.StatusCode)
+		if fault := parseSOAPFault(body); fault != nil {
+			cause = fmt.Errorf("%w: %s", ErrSOAPFault, fault)
+		}
+		ue := newUpstreamError(url, resp.StatusCode, body, cause)
+		ue.RetryAfter = parseRetryAfter(resp.Header.Get("Retry-After"), time.Now())
+		logger.Errorf("CBR responded with status %d: %s", resp.StatusCode, ue.Snippet)
+		return nil, ue
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		logger.Errorf("Failed to read response body: %v", err)
+		return nil, fmt.Errorf("read response body: %w", err)
+	}
+	if len(body) == 0 {
+		logger.Error("Empty response body from CBR")
+		return nil, newUpstreamError(url, resp.StatusCode, nil, fmt.Errorf("%w: empty response body", ErrNoData))
+	}
+	if isHTMLPage(body) {
+		logger.Errorf("CBR returned an HTML page instead of XML: %s", snippet(body))
+		return nil, newUpstreamError(url, resp.StatusCode, body, ErrErrorPage)
+	}
+
+	return body, nil
+}
@@ -5,36 +5,58 @@ package cbr
 
 import (
 	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/xml"
 	"fmt"
 	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
 	"testing"
 
+	"RnD-service/pkg/tracing"
+
 	"golang.org/x/text/encoding/charmap"
 
+	"github.com/shopspring/decimal"
+	"github.com/sirupsen/logrus/hooks/test"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace/noop"
 )
 
 func TestValute_GetValue_Success(t *testing.T) {
 	v := Valute{Value: "90,1234"}
 	value, err := v.GetValue()
 	require.NoError(t, err)
-	assert.Equal(t, 90.1234, value)
+	assert.Equal(t, "90.1234", value.String())
 }
 
 func TestValute_GetValue_CommaReplacement(t *testing.T) {
 	v := Valute{Value: "1234,56"}
 	value, err := v.GetValue()
 	require.NoError(t, err)
-	assert.Equal(t, 1234.56, value)
+	assert.Equal(t, "1234.56", value.String())
 }
 
 func TestValute_GetValue_Invalid(t *testing.T) {
 	v := Valute{Value: "invalid"}
 	_, err := v.GetValue()
 	assert.Error(t, err)
-	assert.Contains(t, err.Error(), "strconv.ParseFloat")
+	assert.Contains(t, err.Error(), "can't convert invalid to decimal")
+}
+
+func TestValute_GetValue_KeepsAllDigits(t *testing.T) {
+	v := Valute{Value: "11,2345"}
+	value, err := v.GetValue()
+	require.NoError(t, err)
+	assert.True(t, value.Equal(decimal.RequireFromString("11.2345")))
+	assert.Equal(t, int32(-4), value.Exponent())
 }
 
 func TestValCurs_XMLUnmarshal(t *testing.T) {
@@ -101,3 +123,224 @@ func TestValCurs_XMLMarshal(t *testing.T) {
 	assert.Contains(t, string(data), `<Value>90,1234</Value>`)
 	assert.Contains(t, string(data), `<VunitRate>90,1234</VunitRate>`)
 }
+
+func TestRecord_GetValue_Success(t *testing.T) {
+	r := Record{Value: "28,6200"}
+	value, err := r.GetValue()
+	require.NoError(t, err)
+	assert.Equal(t, "28.62", value.String())
+}
+
+func TestValCursDynamic_XMLUnmarshal(t *testing.T) {
+	xmlData := `<?xml version="1.0" encoding="windows-1251"?>
+	<ValCurs ID="R01235" DateRange1="02.03.2001" DateRange2="14.03.2001" name="Foreign Currency Market Dynamic">
+		<Record Date="02.03.2001" Id="R01235">
+			<Nominal>1</Nominal>
+			<Value>28,6200</Value>
+			<VunitRate>28,62</VunitRate>
+		</Record>
+		<Record Date="03.03.2001" Id="R01235">
+			<Nominal>1</Nominal>
+			<Value>28,6500</Value>
+			<VunitRate>28,65</VunitRate>
+		</Record>
+	</ValCurs>`
+
+	decoder := xml.NewDecoder(bytes.NewReader([]byte(xmlData)))
+	decoder.CharsetReader = func(charset string, input io.Reader) (io.Reader, error) {
+		if charset == "windows-1251" {
+			return charmap.Windows1251.NewDecoder().Reader(input), nil
+		}
+		return nil, fmt.Errorf("unsupported charset: %s", charset)
+	}
+
+	var vc ValCursDynamic
+	err := decoder.Decode(&vc)
+	require.NoError(t, err)
+	assert.Equal(t, "R01235", vc.ID)
+	assert.Equal(t, "02.03.2001", vc.DateRange1)
+	assert.Equal(t, "14.03.2001", vc.DateRange2)
+	require.Len(t, vc.Records, 2)
+	assert.Equal(t, "02.03.2001", vc.Records[0].Date)
+	assert.Equal(t, "R01235", vc.Records[0].ID)
+	assert.Equal(t, 1, vc.Records[0].Nominal)
+	assert.Equal(t, "28,6500", vc.Records[1].Value)
+}
+
+func TestClient_FetchDynamicRates(t *testing.T) {
+	var gotQuery url.Values
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/XML_dynamic.asp", r.URL.Path)
+		gotQuery = r.URL.Query()
+		w.Header().Set("Content-Type", "application/xml; charset=windows-1251")
+		fmt.Fprint(w, `<?xml version="1.0" encoding="windows-1251"?><ValCurs ID="R01235" DateRange1="02.03.2001" DateRange2="03.03.2001" name="Foreign Currency Market Dynamic"><Record Date="02.03.2001" Id="R01235"><Nominal>1</Nominal><Value>28,6200</Value></Record></ValCurs>`)
+	}))
+	defer srv.Close()
+
+	logger, _ := test.NewNullLogger()
+	client, err := NewClient(logger, WithBaseURL(srv.URL))
+	require.NoError(t, err)
+
+	vc, err := client.FetchDynamicRates(context.Background(), "R01235", "02/03/2001", "03/03/2001")
+	require.NoError(t, err)
+	assert.Equal(t, "02/03/2001", gotQuery.Get("date_req1"))
+	assert.Equal(t, "03/03/2001", gotQuery.Get("date_req2"))
+	assert.Equal(t, "R01235", gotQuery.Get("VAL_NM_RQ"))
+	require.Len(t, vc.Records, 1)
+	assert.Equal(t, "28,6200", vc.Records[0].Value)
+	assert.Len(t, vc.PayloadHash, 64)
+}
+
+func TestClient_FetchDynamicRates_ZeroValue(t *testing.T) {
+	srv := serveFixture(t, http.StatusOK, `<?xml version="1.0" encoding="windows-1251"?><ValCurs ID="R01235" DateRange1="02.03.2001" DateRange2="03.03.2001" name="Foreign Currency Market Dynamic"><Record Date="02.03.2001" Id="R01235"><Nominal>1</Nominal><Value>0</Value></Record></ValCurs>`)
+	client := newTestClient(t, srv, noRetryPolicy())
+
+	_, err := client.FetchDynamicRates(context.Background(), "R01235", "02/03/2001", "03/03/2001")
+	assert.ErrorIs(t, err, ErrInvalidPayload)
+	assert.ErrorContains(t, err, "non-positive Value")
+}
+
+func TestClient_FetchRates_PayloadHash(t *testing.T) {
+	body := `<?xml version="1.0" encoding="windows-1251"?><ValCurs Date="02.03.2001" name="Foreign Currency Market"><Valute ID="R01235"><NumCode>840</NumCode><CharCode>USD</CharCode><Nominal>1</Nominal><Name>US Dollar</Name><Value>28,6200</Value></Valute></ValCurs>`
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/xml; charset=windows-1251")
+		fmt.Fprint(w, body)
+	}))
+	defer srv.Close()
+
+	logger, _ := test.NewNullLogger()
+	client, err := NewClient(logger, WithBaseURL(srv.URL))
+	require.NoError(t, err)
+
+	vc, err := client.FetchRates(context.Background(), "02/03/2001")
+	require.NoError(t, err)
+	sum := sha256.Sum256([]byte(body))
+	assert.Equal(t, hex.EncodeToString(sum[:]), vc.PayloadHash)
+}
+
+func TestClient_TracesRequests(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	provider := tracing.NewProvider(recorder, "RnD-service", 1)
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	t.Cleanup(func() {
+		otel.SetTracerProvider(noop.NewTracerProvider())
+		otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator())
+	})
+
+	var traceparent string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		traceparent = r.Header.Get("traceparent")
+		fmt.Fprint(w, dailyPayload)
+	}))
+	defer srv.Close()
+
+	logger, _ := test.NewNullLogger()
+	client, err := NewClient(logger, WithBaseURL(srv.URL))
+	require.NoError(t, err)
+
+	ctx, parent := tracing.Start(context.Background(), provider.Tracer("test"), "RateService.fetchDaily")
+	_, err = client.FetchRates(ctx, "02/01/2006")
+	parent.End()
+	require.NoError(t, err)
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 2)
+	assert.Equal(t, "CBR GET XML_daily.asp", spans[0].Name())
+	assert.Equal(t, parent.SpanContext().SpanID(), spans[0].Parent().SpanID())
+	assert.Contains(t, traceparent, parent.SpanContext().TraceID().String())
+}
+
+func TestClient_FetchCurrencyCatalog(t *testing.T) {
+	payload := `<?xml version="1.0" encoding="windows-1251"?>
+<Valuta name="Foreign Currency Market Lib">
+	<Item ID="R01235"><Name>Доллар США</Name><EngName>US Dollar</EngName><Nominal>1</Nominal><ParentCode>R01235    </ParentCode><ISO_Num_Code>840</ISO_Num_Code><ISO_Char_Code>USD</ISO_Char_Code></Item>
+	<Item ID="R01436"><Name>Литовский лит</Name><EngName>Lithuanian Lita</EngName><Nominal>1</Nominal><ParentCode>R01435    </ParentCode><ISO_Num_Code></ISO_Num_Code><ISO_Char_Code></ISO_Char_Code></Item>
+</Valuta>`
+	encoded, err := charmap.Windows1251.NewEncoder().String(payload)
+	require.NoError(t, err)
+
+	var gotD []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/XML_val.asp", r.URL.Path)
+		gotD = append(gotD, r.URL.Query().Get("d"))
+		w.Header().Set("Content-Type", "application/xml; charset=windows-1251")
+		fmt.Fprint(w, encoded)
+	}))
+	defer srv.Close()
+
+	logger, _ := test.NewNullLogger()
+	client, err := NewClient(logger, WithBaseURL(srv.URL))
+	require.NoError(t, err)
+
+	valuta, err := client.FetchCurrencyCatalog(context.Background(), false)
+	require.NoError(t, err)
+	require.Len(t, valuta.Items, 2)
+	assert.Equal(t, ValutaItem{
+		ID:          "R01235",
+		Name:        "Доллар США",
+		EngName:     "US Dollar",
+		Nominal:     1,
+		ParentCode:  "R01235    ",
+		ISONumCode:  "840",
+		ISOCharCode: "USD",
+	}, valuta.Items[0])
+	assert.Empty(t, valuta.Items[1].ISOCharCode)
+
+	_, err = client.FetchCurrencyCatalog(context.Background(), true)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"0", "1"}, gotD)
+}
+
+func TestClient_FetchMetalPrices(t *testing.T) {
+	payload := `<?xml version="1.0" encoding="windows-1251"?>
+<Metall FromDate="20250731" ToDate="20250801" name="Precious metals quotations">
+	<Record Date="31.07.2025" Code="1"><Buy>8563,47</Buy><Sell>8563,47</Sell></Record>
+	<Record Date="31.07.2025" Code="2"><Buy>96,12</Buy><Sell>96,12</Sell></Record>
+	<Record Date="01.08.2025" Code="3"><Buy>3400,5</Buy><Sell>3400,5</Sell></Record>
+	<Record Date="01.08.2025" Code="4"><Buy>2961,5</Buy><Sell>2961,5</Sell></Record>
+</Metall>`
+
+	var gotQuery url.Values
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/XML_metall.asp", r.URL.Path)
+		gotQuery = r.URL.Query()
+		w.Header().Set("Content-Type", "application/xml; charset=windows-1251")
+		fmt.Fprint(w, payload)
+	}))
+	defer srv.Close()
+
+	logger, _ := test.NewNullLogger()
+	client, err := NewClient(logger, WithBaseURL(srv.URL))
+	require.NoError(t, err)
+
+	metall, err := client.FetchMetalPrices(context.Background(), "31/07/2025", "01/08/2025")
+	require.NoError(t, err)
+	assert.Equal(t, "31/07/2025", gotQuery.Get("date_req1"))
+	assert.Equal(t, "01/08/2025", gotQuery.Get("date_req2"))
+	require.Len(t, metall.Records, 4)
+	assert.Equal(t, "AU", metall.Records[0].MetalCode())
+	assert.Equal(t, "PD", metall.Records[3].MetalCode())
+
+	sell, err := metall.Records[1].GetSell()
+	require.NoError(t, err)
+	assert.Equal(t, "96.12", sell.String())
+}
+
+func TestClient_FetchMetalPrices_InvalidPrice(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `<Metall FromDate="20250731" ToDate="20250731" name="Precious metals quotations"><Record Date="31.07.2025" Code="1"><Buy>n/a</Buy><Sell>8563,47</Sell></Record></Metall>`)
+	}))
+	defer srv.Close()
+
+	logger, _ := test.NewNullLogger()
+	client, err := NewClient(logger, WithBaseURL(srv.URL))
+	require.NoError(t, err)
+
+	_, err = client.FetchMetalPrices(context.Background(), "31/07/2025", "31/07/2025")
+	assert.ErrorIs(t, err, ErrInvalidPayload)
+}
+
+func TestMetallRecord_UnknownCode(t *testing.T) {
+	assert.Equal(t, "", MetallRecord{Code: 7}.MetalCode())
+}
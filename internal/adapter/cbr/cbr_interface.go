@@ -1,7 +1,24 @@
 package cbr
 
-import "context"
+import (
+	"context"
+	"time"
+)
 
 type CbrClient interface {
 	FetchRates(ctx context.Context, date string) (*ValCurs, error)
+	FetchDynamicRates(ctx context.Context, valuteID, dateFrom, dateTo string) (*ValCursDynamic, error)
+	FetchCurrencyCatalog(ctx context.Context, monthly bool) (*Valuta, error)
+	FetchMetalPrices(ctx context.Context, dateFrom, dateTo string) (*Metall, error)
+}
+
+// FetchObserver is told about every request attempt, retries included; size is
+// the body length of a successful response.
+type FetchObserver interface {
+	ObserveFetch(url string, duration time.Duration, size int, err error)
+}
+
+type DailyInfoClient interface {
+	FetchKeyRate(ctx context.Context, dateFrom, dateTo time.Time) (*KeyRateResponse, error)
+	FetchRuonia(ctx context.Context, dateFrom, dateTo time.Time) (*RuoniaResponse, error)
 }
@@ -0,0 +1,180 @@
+package cbr
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const keyRatePayload = `<?xml version="1.0" encoding="utf-8"?>
+<soap:Envelope xmlns:soap="http://schemas.xmlsoap.org/soap/envelope/" xmlns:xsi="http://www.w3.org/2001/XMLSchema-instance" xmlns:xsd="http://www.w3.org/2001/XMLSchema">
+  <soap:Body>
+    <KeyRateResponse xmlns="http://web.cbr.ru/">
+      <KeyRateResult>
+        <xs:schema id="KeyRate" xmlns="" xmlns:xs="http://www.w3.org/2001/XMLSchema" xmlns:msdata="urn:schemas-microsoft-com:xml-msdata"><xs:element name="KeyRate" msdata:IsDataSet="true"/></xs:schema>
+        <diffgr:diffgram xmlns:msdata="urn:schemas-microsoft-com:xml-msdata" xmlns:diffgr="urn:schemas-microsoft-com:xml-diffgram-v1">
+          <KeyRate xmlns="">
+            <KR diffgr:id="KR1" msdata:rowOrder="0"><DT>2025-07-29T00:00:00+03:00</DT><Rate>18.00</Rate></KR>
+            <KR diffgr:id="KR2" msdata:rowOrder="1"><DT>2025-07-25T00:00:00+03:00</DT><Rate>20.00</Rate></KR>
+          </KeyRate>
+        </diffgr:diffgram>
+      </KeyRateResult>
+    </KeyRateResponse>
+  </soap:Body>
+</soap:Envelope>`
+
+const ruoniaPayload = `<?xml version="1.0" encoding="utf-8"?>
+<soap:Envelope xmlns:soap="http://schemas.xmlsoap.org/soap/envelope/">
+  <soap:Body>
+    <RuoniaResponse xmlns="http://web.cbr.ru/">
+      <RuoniaResult>
+        <diffgr:diffgram xmlns:msdata="urn:schemas-microsoft-com:xml-msdata" xmlns:diffgr="urn:schemas-microsoft-com:xml-diffgram-v1">
+          <Ruonia xmlns="">
+            <ro diffgr:id="ro1" msdata:rowOrder="0"><D0>2025-07-28T00:00:00+03:00</D0><ruo>17.85</ruo><vol>512.34</vol><DateUpdate>2025-07-29T14:05:00+03:00</DateUpdate></ro>
+            <ro diffgr:id="ro2" msdata:rowOrder="1"><D0>2025-07-25T00:00:00+03:00</D0><ruo>19.80</ruo><vol></vol><DateUpdate>2025-07-28T14:05:00+03:00</DateUpdate></ro>
+          </Ruonia>
+        </diffgr:diffgram>
+      </RuoniaResult>
+    </RuoniaResponse>
+  </soap:Body>
+</soap:Envelope>`
+
+const soapFaultPayload = `<?xml version="1.0" encoding="utf-8"?>
+<soap:Envelope xmlns:soap="http://schemas.xmlsoap.org/soap/envelope/">
+  <soap:Body>
+    <soap:Fault><faultcode>soap:Client</faultcode><faultstring>Server was unable to read request. ---> The string was not recognized as a valid DateTime.</faultstring><detail /></soap:Fault>
+  </soap:Body>
+</soap:Envelope>`
+
+func newDailyInfoClient(t *testing.T, handler http.HandlerFunc) *Client {
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+
+	logger, _ := test.NewNullLogger()
+	client, err := NewClient(logger, WithDailyInfoURL(srv.URL+"/DailyInfoWebServ/DailyInfo.asmx"), WithPolicy(fastPolicy()))
+	require.NoError(t, err)
+	return client
+}
+
+func TestClient_FetchKeyRate(t *testing.T) {
+	var gotBody string
+	client := newDailyInfoClient(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "/DailyInfoWebServ/DailyInfo.asmx", r.URL.Path)
+		assert.Equal(t, `"http://web.cbr.ru/KeyRate"`, r.Header.Get("SOAPAction"))
+		assert.Equal(t, "text/xml; charset=utf-8", r.Header.Get("Content-Type"))
+		body, _ := io.ReadAll(r.Body)
+		gotBody = string(body)
+		w.Header().Set("Content-Type", "text/xml; charset=utf-8")
+		fmt.Fprint(w, keyRatePayload)
+	})
+
+	from := time.Date(2025, 7, 25, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2025, 7, 29, 0, 0, 0, 0, time.UTC)
+	resp, err := client.FetchKeyRate(context.Background(), from, to)
+	require.NoError(t, err)
+
+	assert.Contains(t, gotBody, `<KeyRate xmlns="http://web.cbr.ru/">`)
+	assert.Contains(t, gotBody, `<fromDate>2025-07-25T00:00:00</fromDate>`)
+	assert.Contains(t, gotBody, `<ToDate>2025-07-29T00:00:00</ToDate>`)
+
+	records := resp.Records()
+	require.Len(t, records, 2)
+	date, err := records[0].GetDate()
+	require.NoError(t, err)
+	assert.Equal(t, to, date, "Moscow midnight keeps its calendar date")
+	rate, err := records[1].GetRate()
+	require.NoError(t, err)
+	assert.Equal(t, "20", rate.String())
+}
+
+func TestClient_FetchRuonia(t *testing.T) {
+	client := newDailyInfoClient(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, `"http://web.cbr.ru/Ruonia"`, r.Header.Get("SOAPAction"))
+		fmt.Fprint(w, ruoniaPayload)
+	})
+
+	resp, err := client.FetchRuonia(context.Background(), time.Date(2025, 7, 25, 0, 0, 0, 0, time.UTC), time.Date(2025, 7, 28, 0, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+
+	records := resp.Records()
+	require.Len(t, records, 2)
+	rate, err := records[0].GetRate()
+	require.NoError(t, err)
+	assert.Equal(t, "17.85", rate.String())
+	volume, err := records[0].GetVolume()
+	require.NoError(t, err)
+	assert.Equal(t, "512.34", volume.String())
+	volume, err = records[1].GetVolume()
+	require.NoError(t, err)
+	assert.True(t, volume.IsZero())
+}
+
+func TestClient_DailyInfoEmptyRange(t *testing.T) {
+	client := newDailyInfoClient(t, func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `<?xml version="1.0" encoding="utf-8"?><soap:Envelope xmlns:soap="http://schemas.xmlsoap.org/soap/envelope/"><soap:Body><KeyRateResponse xmlns="http://web.cbr.ru/"><KeyRateResult><diffgr:diffgram xmlns:diffgr="urn:schemas-microsoft-com:xml-diffgram-v1" /></KeyRateResult></KeyRateResponse></soap:Body></soap:Envelope>`)
+	})
+
+	resp, err := client.FetchKeyRate(context.Background(), time.Date(2025, 7, 26, 0, 0, 0, 0, time.UTC), time.Date(2025, 7, 27, 0, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+	assert.Empty(t, resp.Records())
+}
+
+func TestClient_DailyInfoSOAPFault(t *testing.T) {
+	var calls int32
+	client := newDailyInfoClient(t, func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.Header().Set("Content-Type", "text/xml; charset=utf-8")
+		w.WriteHeader(http.StatusInternalServerError)
+		fmt.Fprint(w, soapFaultPayload)
+	})
+
+	_, err := client.FetchKeyRate(context.Background(), time.Date(2025, 7, 25, 0, 0, 0, 0, time.UTC), time.Date(2025, 7, 29, 0, 0, 0, 0, time.UTC))
+	require.Error(t, err)
+	assert.ErrorIs(t, err, ErrSOAPFault)
+	assert.NotErrorIs(t, err, ErrUnexpectedStatus)
+	assert.ErrorContains(t, err, "soap:Client: Server was unable to read request")
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls), "a SOAP fault is not retried")
+}
+
+func TestClient_DailyInfoRetriesServerErrors(t *testing.T) {
+	var calls int32
+	client := newDailyInfoClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		assert.Contains(t, string(body), "<fromDate>", "request body is re-sent on retry")
+		fmt.Fprint(w, ruoniaPayload)
+	})
+
+	resp, err := client.FetchRuonia(context.Background(), time.Date(2025, 7, 25, 0, 0, 0, 0, time.UTC), time.Date(2025, 7, 28, 0, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+	assert.Len(t, resp.Records(), 2)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&calls))
+}
+
+func TestClient_DailyInfoInvalidPayload(t *testing.T) {
+	client := newDailyInfoClient(t, func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `<soap:Envelope xmlns:soap="http://schemas.xmlsoap.org/soap/envelope/"><soap:Body><Other /></soap:Body></soap:Envelope>`)
+	})
+
+	_, err := client.FetchKeyRate(context.Background(), time.Date(2025, 7, 25, 0, 0, 0, 0, time.UTC), time.Date(2025, 7, 29, 0, 0, 0, 0, time.UTC))
+	assert.ErrorIs(t, err, ErrInvalidPayload)
+}
+
+func TestWithDailyInfoURL_Invalid(t *testing.T) {
+	logger, _ := test.NewNullLogger()
+	_, err := NewClient(logger, WithDailyInfoURL("not a url"))
+	assert.Error(t, err)
+}
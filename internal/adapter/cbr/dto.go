@@ -2,15 +2,71 @@ package cbr
 
 import (
 	"encoding/xml"
-	"strconv"
+	"fmt"
 	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
 )
 
+// payload is implemented by responses that can be checked right after decoding.
+type payload interface {
+	validate() error
+}
+
+// hashedPayload is implemented by responses that keep the SHA-256 of the raw
+// body they were decoded from, so stored rates can be traced to it.
+type hashedPayload interface {
+	setPayloadHash(hash string)
+}
+
 type ValCurs struct {
 	XMLName xml.Name `xml:"ValCurs"`
 	Date    string   `xml:"Date,attr"`
 	Name    string   `xml:"name,attr"`
 	Valutes []Valute `xml:"Valute"`
+	// CBR reports bad or unknown parameters as text inside the root element,
+	// e.g. <ValCurs>Error in parameters</ValCurs>.
+	Message     string `xml:",chardata"`
+	PayloadHash string `xml:"-"`
+}
+
+func (v *ValCurs) setPayloadHash(hash string) {
+	v.PayloadHash = hash
+}
+
+// validate checks everything convertCBRResponse relies on.
+func (v *ValCurs) validate() error {
+	if msg := strings.TrimSpace(v.Message); msg != "" {
+		return fmt.Errorf("%w: %s", ErrNoData, msg)
+	}
+	if v.Date == "" {
+		return fmt.Errorf("%w: ValCurs has no Date attribute", ErrInvalidPayload)
+	}
+	if _, err := time.Parse("02.01.2006", v.Date); err != nil {
+		return fmt.Errorf("%w: ValCurs Date %q is not DD.MM.YYYY", ErrInvalidPayload, v.Date)
+	}
+	if len(v.Valutes) == 0 {
+		return fmt.Errorf("%w: no Valute elements for %s", ErrNoData, v.Date)
+	}
+
+	for i, valute := range v.Valutes {
+		if strings.TrimSpace(valute.CharCode) == "" {
+			return fmt.Errorf("%w: Valute #%d (ID=%s) has no CharCode", ErrInvalidPayload, i+1, valute.ID)
+		}
+		if valute.Nominal <= 0 {
+			return fmt.Errorf("%w: Valute %s has non-positive Nominal %d", ErrInvalidPayload, valute.CharCode, valute.Nominal)
+		}
+		value, err := valute.GetValue()
+		if err != nil {
+			return fmt.Errorf("%w: Valute %s has invalid Value %q", ErrInvalidPayload, valute.CharCode, valute.Value)
+		}
+		if !value.IsPositive() {
+			return fmt.Errorf("%w: Valute %s has non-positive Value %q", ErrInvalidPayload, valute.CharCode, valute.Value)
+		}
+	}
+
+	return nil
 }
 
 type Valute struct {
@@ -23,7 +79,256 @@ type Valute struct {
 	VunitRate string `xml:"VunitRate"`
 }
 
-func (v Valute) GetValue() (float64, error) {
-	valueStr := strings.Replace(v.Value, ",", ".", -1)
-	return strconv.ParseFloat(valueStr, 64)
+func (v Valute) GetValue() (decimal.Decimal, error) {
+	return parseDecimal(v.Value)
+}
+
+type ValCursDynamic struct {
+	XMLName     xml.Name `xml:"ValCurs"`
+	ID          string   `xml:"ID,attr"`
+	DateRange1  string   `xml:"DateRange1,attr"`
+	DateRange2  string   `xml:"DateRange2,attr"`
+	Name        string   `xml:"name,attr"`
+	Records     []Record `xml:"Record"`
+	Message     string   `xml:",chardata"`
+	PayloadHash string   `xml:"-"`
+}
+
+func (v *ValCursDynamic) setPayloadHash(hash string) {
+	v.PayloadHash = hash
+}
+
+// validate allows an empty range: CBR has no records for ranges without publications.
+func (v *ValCursDynamic) validate() error {
+	if msg := strings.TrimSpace(v.Message); msg != "" {
+		return fmt.Errorf("%w: %s", ErrNoData, msg)
+	}
+
+	for i, record := range v.Records {
+		if _, err := time.Parse("02.01.2006", record.Date); err != nil {
+			return fmt.Errorf("%w: Record #%d has invalid Date %q", ErrInvalidPayload, i+1, record.Date)
+		}
+		if record.Nominal <= 0 {
+			return fmt.Errorf("%w: Record %s has non-positive Nominal %d", ErrInvalidPayload, record.Date, record.Nominal)
+		}
+		value, err := record.GetValue()
+		if err != nil {
+			return fmt.Errorf("%w: Record %s has invalid Value %q", ErrInvalidPayload, record.Date, record.Value)
+		}
+		if !value.IsPositive() {
+			return fmt.Errorf("%w: Record %s has non-positive Value %q", ErrInvalidPayload, record.Date, record.Value)
+		}
+	}
+
+	return nil
+}
+
+type Record struct {
+	Date      string `xml:"Date,attr"`
+	ID        string `xml:"Id,attr"`
+	Nominal   int    `xml:"Nominal"`
+	Value     string `xml:"Value"`
+	VunitRate string `xml:"VunitRate"`
+}
+
+func (r Record) GetValue() (decimal.Decimal, error) {
+	return parseDecimal(r.Value)
+}
+
+type Valuta struct {
+	XMLName xml.Name     `xml:"Valuta"`
+	Name    string       `xml:"name,attr"`
+	Items   []ValutaItem `xml:"Item"`
+}
+
+type ValutaItem struct {
+	ID          string `xml:"ID,attr"`
+	Name        string `xml:"Name"`
+	EngName     string `xml:"EngName"`
+	Nominal     int    `xml:"Nominal"`
+	ParentCode  string `xml:"ParentCode"`
+	ISONumCode  string `xml:"ISO_Num_Code"`
+	ISOCharCode string `xml:"ISO_Char_Code"`
+}
+
+// Metall is the XML_metall.asp response: CBR discount prices of precious
+// metals in roubles per gram, one Record per metal and trading day.
+type Metall struct {
+	XMLName  xml.Name       `xml:"Metall"`
+	FromDate string         `xml:"FromDate,attr"`
+	ToDate   string         `xml:"ToDate,attr"`
+	Name     string         `xml:"name,attr"`
+	Records  []MetallRecord `xml:"Record"`
+	Message  string         `xml:",chardata"`
+}
+
+type MetallRecord struct {
+	Date string `xml:"Date,attr"`
+	Code int    `xml:"Code,attr"`
+	Buy  string `xml:"Buy"`
+	Sell string `xml:"Sell"`
+}
+
+// metalCodes maps CBR numeric metal codes to the codes used in our API.
+var metalCodes = map[int]string{
+	1: "AU",
+	2: "AG",
+	3: "PT",
+	4: "PD",
+}
+
+// MetalCode returns AU, AG, PT or PD, or "" for codes CBR may add later.
+func (r MetallRecord) MetalCode() string {
+	return metalCodes[r.Code]
+}
+
+func (r MetallRecord) GetBuy() (decimal.Decimal, error) {
+	return parseDecimal(r.Buy)
+}
+
+func (r MetallRecord) GetSell() (decimal.Decimal, error) {
+	return parseDecimal(r.Sell)
+}
+
+// validate allows an empty range: there are no prices on weekends and holidays.
+func (v *Metall) validate() error {
+	if msg := strings.TrimSpace(v.Message); msg != "" {
+		return fmt.Errorf("%w: %s", ErrNoData, msg)
+	}
+
+	for i, record := range v.Records {
+		if _, err := time.Parse("02.01.2006", record.Date); err != nil {
+			return fmt.Errorf("%w: Record #%d has invalid Date %q", ErrInvalidPayload, i+1, record.Date)
+		}
+		if _, err := record.GetBuy(); err != nil {
+			return fmt.Errorf("%w: Record %s/%d has invalid Buy %q", ErrInvalidPayload, record.Date, record.Code, record.Buy)
+		}
+		if _, err := record.GetSell(); err != nil {
+			return fmt.Errorf("%w: Record %s/%d has invalid Sell %q", ErrInvalidPayload, record.Date, record.Code, record.Sell)
+		}
+	}
+
+	return nil
+}
+
+// KeyRateResponse is the DailyInfo KeyRate SOAP response: a .NET DataSet with
+// one KR row per business day, newest first.
+type KeyRateResponse struct {
+	XMLName xml.Name       `xml:"Envelope"`
+	Result  *KeyRateResult `xml:"Body>KeyRateResponse>KeyRateResult"`
+}
+
+type KeyRateResult struct {
+	Rates []KeyRateRecord `xml:"diffgram>KeyRate>KR"`
+}
+
+type KeyRateRecord struct {
+	Date string `xml:"DT"`
+	Rate string `xml:"Rate"`
+}
+
+func (r KeyRateRecord) GetDate() (time.Time, error) {
+	return parseDailyInfoDate(r.Date)
+}
+
+func (r KeyRateRecord) GetRate() (decimal.Decimal, error) {
+	return parseDecimal(r.Rate)
+}
+
+// Records returns the rows, or nil for an empty range.
+func (v *KeyRateResponse) Records() []KeyRateRecord {
+	if v.Result == nil {
+		return nil
+	}
+	return v.Result.Rates
+}
+
+func (v *KeyRateResponse) validate() error {
+	if v.Result == nil {
+		return fmt.Errorf("%w: no KeyRateResult in response", ErrInvalidPayload)
+	}
+	for i, record := range v.Result.Rates {
+		if _, err := record.GetDate(); err != nil {
+			return fmt.Errorf("%w: KR #%d has invalid DT %q", ErrInvalidPayload, i+1, record.Date)
+		}
+		if _, err := record.GetRate(); err != nil {
+			return fmt.Errorf("%w: KR %s has invalid Rate %q", ErrInvalidPayload, record.Date, record.Rate)
+		}
+	}
+	return nil
+}
+
+// RuoniaResponse is the DailyInfo Ruonia SOAP response. RUONIA for a day is
+// published on the next business day; ruo is the rate in percent, vol the
+// volume of deals in billions of roubles.
+type RuoniaResponse struct {
+	XMLName xml.Name      `xml:"Envelope"`
+	Result  *RuoniaResult `xml:"Body>RuoniaResponse>RuoniaResult"`
+}
+
+type RuoniaResult struct {
+	Rates []RuoniaRecord `xml:"diffgram>Ruonia>ro"`
+}
+
+type RuoniaRecord struct {
+	Date       string `xml:"D0"`
+	Rate       string `xml:"ruo"`
+	Volume     string `xml:"vol"`
+	DateUpdate string `xml:"DateUpdate"`
+}
+
+func (r RuoniaRecord) GetDate() (time.Time, error) {
+	return parseDailyInfoDate(r.Date)
+}
+
+func (r RuoniaRecord) GetRate() (decimal.Decimal, error) {
+	return parseDecimal(r.Rate)
+}
+
+// GetVolume returns zero when CBR did not publish the volume.
+func (r RuoniaRecord) GetVolume() (decimal.Decimal, error) {
+	if strings.TrimSpace(r.Volume) == "" {
+		return decimal.Zero, nil
+	}
+	return parseDecimal(r.Volume)
+}
+
+func (v *RuoniaResponse) Records() []RuoniaRecord {
+	if v.Result == nil {
+		return nil
+	}
+	return v.Result.Rates
+}
+
+func (v *RuoniaResponse) validate() error {
+	if v.Result == nil {
+		return fmt.Errorf("%w: no RuoniaResult in response", ErrInvalidPayload)
+	}
+	for i, record := range v.Result.Rates {
+		if _, err := record.GetDate(); err != nil {
+			return fmt.Errorf("%w: ro #%d has invalid D0 %q", ErrInvalidPayload, i+1, record.Date)
+		}
+		if _, err := record.GetRate(); err != nil {
+			return fmt.Errorf("%w: ro %s has invalid ruo %q", ErrInvalidPayload, record.Date, record.Rate)
+		}
+		if _, err := record.GetVolume(); err != nil {
+			return fmt.Errorf("%w: ro %s has invalid vol %q", ErrInvalidPayload, record.Date, record.Volume)
+		}
+	}
+	return nil
+}
+
+// parseDailyInfoDate keeps the calendar date of an xs:dateTime such as
+// 2023-01-10T00:00:00+03:00, which is midnight in Moscow.
+func parseDailyInfoDate(value string) (time.Time, error) {
+	t, err := time.Parse(time.RFC3339, strings.TrimSpace(value))
+	if err != nil {
+		return time.Time{}, err
+	}
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC), nil
+}
+
+func parseDecimal(value string) (decimal.Decimal, error) {
+	valueStr := strings.Replace(strings.TrimSpace(value), ",", ".", -1)
+	return decimal.NewFromString(valueStr)
 }
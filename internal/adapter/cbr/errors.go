@@ -0,0 +1,85 @@
+package cbr
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"golang.org/x/text/encoding/charmap"
+)
+
+var (
+	ErrCircuitOpen      = errors.New("CBR circuit breaker is open")
+	ErrUnexpectedStatus = errors.New("unexpected HTTP status")
+	ErrErrorPage        = errors.New("CBR returned an HTML error page")
+	ErrNoData           = errors.New("CBR returned no data")
+	ErrInvalidPayload   = errors.New("invalid CBR payload")
+	ErrSOAPFault        = errors.New("CBR DailyInfo returned a SOAP fault")
+)
+
+const snippetLimit = 200
+
+// UpstreamError describes a CBR response that could not be used.
+// Err is one of ErrUnexpectedStatus, ErrErrorPage, ErrNoData, ErrInvalidPayload
+// or ErrSOAPFault, possibly wrapped with details.
+type UpstreamError struct {
+	URL        string
+	StatusCode int
+	Snippet    string
+	RetryAfter time.Duration
+	Err        error
+}
+
+func newUpstreamError(url string, statusCode int, body []byte, err error) *UpstreamError {
+	return &UpstreamError{
+		URL:        url,
+		StatusCode: statusCode,
+		Snippet:    snippet(body),
+		Err:        err,
+	}
+}
+
+func (e *UpstreamError) Error() string {
+	msg := fmt.Sprintf("CBR %s (status %d): %v", e.URL, e.StatusCode, e.Err)
+	if e.Snippet != "" {
+		msg += fmt.Sprintf(", body: %q", e.Snippet)
+	}
+	return msg
+}
+
+func (e *UpstreamError) Unwrap() error {
+	return e.Err
+}
+
+// snippet turns the start of a response body into a single readable line.
+// CBR answers in windows-1251, so bodies that are not valid UTF-8 are decoded first.
+func snippet(body []byte) string {
+	if len(body) > 4*snippetLimit {
+		body = body[:4*snippetLimit]
+	}
+	text := string(body)
+	if !utf8.ValidString(text) {
+		if decoded, err := charmap.Windows1251.NewDecoder().String(text); err == nil {
+			text = decoded
+		}
+	}
+	text = strings.Join(strings.Fields(text), " ")
+
+	if utf8.RuneCountInString(text) > snippetLimit {
+		text = string([]rune(text)[:snippetLimit]) + "..."
+	}
+	return text
+}
+
+// isHTMLPage detects the HTML pages CBR serves instead of XML during
+// maintenance or when a request is blocked.
+func isHTMLPage(body []byte) bool {
+	head := bytes.ToLower(bytes.TrimLeft(body, "\ufeff \t\r\n"))
+	if len(head) > 512 {
+		head = head[:512]
+	}
+	return bytes.HasPrefix(head, []byte("<!doctype html")) || bytes.Contains(head, []byte("<html"))
+}
@@ -0,0 +1,225 @@
+package cbr
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"golang.org/x/text/encoding/charmap"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const cbrErrorPage = `<!DOCTYPE html>
+<html lang="ru">
+<head><title>Банк России</title></head>
+<body><h1>Сервис временно недоступен</h1><p>Ведутся технические работы.</p></body>
+</html>`
+
+func serveFixture(t *testing.T, status int, body string) *httptest.Server {
+	encoded, err := charmap.Windows1251.NewEncoder().String(body)
+	require.NoError(t, err)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(status)
+		fmt.Fprint(w, encoded)
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func noRetryPolicy() Policy {
+	return Policy{Retry: RetryPolicy{MaxAttempts: 1}}
+}
+
+func TestClient_FetchRates_FailureShapes(t *testing.T) {
+	tests := []struct {
+		name     string
+		status   int
+		body     string
+		target   error
+		snippet  string
+		contains string
+	}{
+		{
+			name:    "503 with HTML error page",
+			status:  http.StatusServiceUnavailable,
+			body:    cbrErrorPage,
+			target:  ErrUnexpectedStatus,
+			snippet: "Сервис временно недоступен",
+		},
+		{
+			name:   "404 with empty body",
+			status: http.StatusNotFound,
+			target: ErrUnexpectedStatus,
+		},
+		{
+			name:    "200 with HTML error page",
+			status:  http.StatusOK,
+			body:    cbrErrorPage,
+			target:  ErrErrorPage,
+			snippet: "Ведутся технические работы",
+		},
+		{
+			name:   "empty body",
+			status: http.StatusOK,
+			target: ErrNoData,
+		},
+		{
+			name:     "error in parameters",
+			status:   http.StatusOK,
+			body:     `<?xml version="1.0" encoding="windows-1251"?><ValCurs>Error in parameters</ValCurs>`,
+			target:   ErrNoData,
+			contains: "Error in parameters",
+		},
+		{
+			name:     "no valutes",
+			status:   http.StatusOK,
+			body:     `<?xml version="1.0" encoding="windows-1251"?><ValCurs Date="01.01.1990" name="Foreign Currency Market"></ValCurs>`,
+			target:   ErrNoData,
+			contains: "no Valute elements",
+		},
+		{
+			name:     "missing date",
+			status:   http.StatusOK,
+			body:     `<?xml version="1.0" encoding="windows-1251"?><ValCurs name="Foreign Currency Market"><Valute ID="R01235"><CharCode>USD</CharCode><Nominal>1</Nominal><Value>90,1234</Value></Valute></ValCurs>`,
+			target:   ErrInvalidPayload,
+			contains: "no Date attribute",
+		},
+		{
+			name:     "malformed date",
+			status:   http.StatusOK,
+			body:     `<?xml version="1.0" encoding="windows-1251"?><ValCurs Date="2006-01-02" name="Foreign Currency Market"><Valute ID="R01235"><CharCode>USD</CharCode><Nominal>1</Nominal><Value>90,1234</Value></Valute></ValCurs>`,
+			target:   ErrInvalidPayload,
+			contains: "not DD.MM.YYYY",
+		},
+		{
+			name:     "missing char code",
+			status:   http.StatusOK,
+			body:     `<?xml version="1.0" encoding="windows-1251"?><ValCurs Date="02.01.2006" name="Foreign Currency Market"><Valute ID="R01235"><Nominal>1</Nominal><Value>90,1234</Value></Valute></ValCurs>`,
+			target:   ErrInvalidPayload,
+			contains: "Valute #1 (ID=R01235) has no CharCode",
+		},
+		{
+			name:     "missing nominal",
+			status:   http.StatusOK,
+			body:     `<?xml version="1.0" encoding="windows-1251"?><ValCurs Date="02.01.2006" name="Foreign Currency Market"><Valute ID="R01235"><CharCode>USD</CharCode><Value>90,1234</Value></Valute></ValCurs>`,
+			target:   ErrInvalidPayload,
+			contains: "non-positive Nominal",
+		},
+		{
+			name:     "invalid value",
+			status:   http.StatusOK,
+			body:     `<?xml version="1.0" encoding="windows-1251"?><ValCurs Date="02.01.2006" name="Foreign Currency Market"><Valute ID="R01235"><CharCode>USD</CharCode><Nominal>1</Nominal><Value>н/д</Value></Valute></ValCurs>`,
+			target:   ErrInvalidPayload,
+			contains: `invalid Value "н/д"`,
+		},
+		{
+			name:     "zero value",
+			status:   http.StatusOK,
+			body:     `<?xml version="1.0" encoding="windows-1251"?><ValCurs Date="02.01.2006" name="Foreign Currency Market"><Valute ID="R01235"><CharCode>USD</CharCode><Nominal>1</Nominal><Value>0,0000</Value></Valute></ValCurs>`,
+			target:   ErrInvalidPayload,
+			contains: `non-positive Value "0,0000"`,
+		},
+		{
+			name:     "truncated XML",
+			status:   http.StatusOK,
+			body:     `<?xml version="1.0" encoding="windows-1251"?><ValCurs Date="02.01.2006" name="Foreign Currency Market"><Valute ID="R01235">`,
+			target:   ErrInvalidPayload,
+			contains: "parse XML",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := serveFixture(t, tt.status, tt.body)
+			client := newTestClient(t, srv, noRetryPolicy())
+
+			vc, err := client.FetchRates(context.Background(), "02/01/2006")
+			require.Error(t, err)
+			assert.Nil(t, vc)
+			assert.ErrorIs(t, err, tt.target)
+
+			var ue *UpstreamError
+			require.True(t, errors.As(err, &ue))
+			assert.Equal(t, tt.status, ue.StatusCode)
+			assert.Equal(t, srv.URL+"/XML_daily.asp?date_req=02/01/2006", ue.URL)
+			if tt.snippet != "" {
+				assert.Contains(t, ue.Snippet, tt.snippet)
+			}
+			if tt.contains != "" {
+				assert.Contains(t, err.Error(), tt.contains)
+			}
+		})
+	}
+}
+
+func TestClient_FetchDynamicRates_FailureShapes(t *testing.T) {
+	t.Run("error in parameters", func(t *testing.T) {
+		srv := serveFixture(t, http.StatusOK, `<?xml version="1.0" encoding="windows-1251"?><ValCurs>Error in parameters</ValCurs>`)
+		client := newTestClient(t, srv, noRetryPolicy())
+
+		_, err := client.FetchDynamicRates(context.Background(), "R99999", "02/03/2001", "03/03/2001")
+		assert.ErrorIs(t, err, ErrNoData)
+	})
+
+	t.Run("empty range is not an error", func(t *testing.T) {
+		srv := serveFixture(t, http.StatusOK, `<?xml version="1.0" encoding="windows-1251"?><ValCurs ID="R01235" DateRange1="04.03.2001" DateRange2="04.03.2001" name="Foreign Currency Market Dynamic"></ValCurs>`)
+		client := newTestClient(t, srv, noRetryPolicy())
+
+		vc, err := client.FetchDynamicRates(context.Background(), "R01235", "04/03/2001", "04/03/2001")
+		require.NoError(t, err)
+		assert.Empty(t, vc.Records)
+	})
+
+	t.Run("invalid record", func(t *testing.T) {
+		srv := serveFixture(t, http.StatusOK, `<?xml version="1.0" encoding="windows-1251"?><ValCurs ID="R01235" DateRange1="02.03.2001" DateRange2="03.03.2001" name="Foreign Currency Market Dynamic"><Record Date="02.03.2001" Id="R01235"><Nominal>1</Nominal><Value></Value></Record></ValCurs>`)
+		client := newTestClient(t, srv, noRetryPolicy())
+
+		_, err := client.FetchDynamicRates(context.Background(), "R01235", "02/03/2001", "03/03/2001")
+		assert.ErrorIs(t, err, ErrInvalidPayload)
+		assert.ErrorContains(t, err, "Record 02.03.2001 has invalid Value")
+	})
+}
+
+func TestClient_ErrorPageIsRetried(t *testing.T) {
+	calls := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls == 1 {
+			fmt.Fprint(w, "<html><body>Please wait...</body></html>")
+			return
+		}
+		fmt.Fprint(w, dailyPayload)
+	}))
+	defer srv.Close()
+
+	client := newTestClient(t, srv, fastPolicy())
+
+	vc, err := client.FetchRates(context.Background(), "02/01/2006")
+	require.NoError(t, err)
+	assert.Len(t, vc.Valutes, 1)
+	assert.Equal(t, 2, calls)
+}
+
+func TestSnippet(t *testing.T) {
+	encoded, err := charmap.Windows1251.NewEncoder().String("Ошибка:\n\t  нет данных")
+	require.NoError(t, err)
+	assert.Equal(t, "Ошибка: нет данных", snippet([]byte(encoded)))
+
+	long := snippet([]byte(strings.Repeat("a", 1000)))
+	assert.Equal(t, strings.Repeat("a", snippetLimit)+"...", long)
+
+	assert.Empty(t, snippet(nil))
+}
+
+func TestUpstreamError_Error(t *testing.T) {
+	err := newUpstreamError("https://www.cbr.ru/scripts/XML_daily.asp", 502, []byte("Bad Gateway"), fmt.Errorf("%w 502", ErrUnexpectedStatus))
+	assert.Equal(t, `CBR https://www.cbr.ru/scripts/XML_daily.asp (status 502): unexpected HTTP status 502, body: "Bad Gateway"`, err.Error())
+	assert.ErrorIs(t, err, ErrUnexpectedStatus)
+}
@@ -0,0 +1,207 @@
+package cbr
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+)
+
+const (
+	DefaultBaseURL      = "https://www.cbr.ru/scripts"
+	DefaultDailyInfoURL = "https://www.cbr.ru/DailyInfoWebServ/DailyInfo.asmx"
+	// DefaultUserAgent mimics a browser: www.cbr.ru rejects some non-browser clients.
+	DefaultUserAgent = "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/91.0.4472.124 Safari/537.36"
+)
+
+type options struct {
+	baseURL               string
+	dailyInfoURL          string
+	timeout               time.Duration
+	connectTimeout        time.Duration
+	responseHeaderTimeout time.Duration
+	userAgent             string
+	proxy                 *url.URL
+	rootCAs               *x509.CertPool
+	policy                Policy
+	observer              FetchObserver
+}
+
+func defaultOptions() options {
+	return options{
+		baseURL:               DefaultBaseURL,
+		dailyInfoURL:          DefaultDailyInfoURL,
+		timeout:               30 * time.Second,
+		connectTimeout:        10 * time.Second,
+		responseHeaderTimeout: 30 * time.Second,
+		userAgent:             DefaultUserAgent,
+		policy:                DefaultPolicy,
+	}
+}
+
+// Option configures a Client. Empty strings and zero durations keep the defaults,
+// so options can be fed straight from config.
+type Option func(*options) error
+
+// WithBaseURL points the client at a CBR mirror or a fake server.
+func WithBaseURL(baseURL string) Option {
+	return func(o *options) error {
+		if baseURL == "" {
+			return nil
+		}
+		u, err := url.Parse(baseURL)
+		if err != nil || u.Scheme == "" || u.Host == "" {
+			return fmt.Errorf("invalid base URL %q", baseURL)
+		}
+		o.baseURL = strings.TrimRight(baseURL, "/")
+		return nil
+	}
+}
+
+// WithDailyInfoURL sets the DailyInfo SOAP endpoint used for key rate and RUONIA.
+func WithDailyInfoURL(endpoint string) Option {
+	return func(o *options) error {
+		if endpoint == "" {
+			return nil
+		}
+		u, err := url.Parse(endpoint)
+		if err != nil || u.Scheme == "" || u.Host == "" {
+			return fmt.Errorf("invalid DailyInfo URL %q", endpoint)
+		}
+		o.dailyInfoURL = endpoint
+		return nil
+	}
+}
+
+// WithTimeout limits a single request, including reading the body.
+func WithTimeout(d time.Duration) Option {
+	return func(o *options) error {
+		if d > 0 {
+			o.timeout = d
+		}
+		return nil
+	}
+}
+
+func WithConnectTimeout(d time.Duration) Option {
+	return func(o *options) error {
+		if d > 0 {
+			o.connectTimeout = d
+		}
+		return nil
+	}
+}
+
+func WithResponseHeaderTimeout(d time.Duration) Option {
+	return func(o *options) error {
+		if d > 0 {
+			o.responseHeaderTimeout = d
+		}
+		return nil
+	}
+}
+
+func WithUserAgent(userAgent string) Option {
+	return func(o *options) error {
+		if userAgent != "" {
+			o.userAgent = userAgent
+		}
+		return nil
+	}
+}
+
+// WithProxy sends requests through an HTTP(S) proxy, e.g. "http://proxy.local:3128",
+// in place of the one from HTTP_PROXY, HTTPS_PROXY and NO_PROXY.
+func WithProxy(proxyURL string) Option {
+	return func(o *options) error {
+		if proxyURL == "" {
+			return nil
+		}
+		u, err := url.Parse(proxyURL)
+		if err != nil || u.Scheme == "" || u.Host == "" {
+			// the URL may carry credentials, so it is not echoed back
+			return errors.New("invalid proxy URL, expected scheme://[user:password@]host:port")
+		}
+		o.proxy = u
+		return nil
+	}
+}
+
+// WithCABundle trusts the PEM certificates in path in addition to the system
+// roots, for mirrors behind a corporate CA.
+func WithCABundle(path string) Option {
+	return func(o *options) error {
+		if path == "" {
+			return nil
+		}
+		pem, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("read CA bundle: %w", err)
+		}
+
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return fmt.Errorf("no certificates found in CA bundle %s", path)
+		}
+		o.rootCAs = pool
+		return nil
+	}
+}
+
+func WithPolicy(policy Policy) Option {
+	return func(o *options) error {
+		o.policy = policy
+		return nil
+	}
+}
+
+// WithObserver reports every request attempt, e.g. to metrics.
+func WithObserver(observer FetchObserver) Option {
+	return func(o *options) error {
+		o.observer = observer
+		return nil
+	}
+}
+
+func (o options) httpClient() *http.Client {
+	// every attempt gets a client span and propagates the trace to the server
+	return &http.Client{
+		Timeout: o.timeout,
+		Transport: otelhttp.NewTransport(o.transport(), otelhttp.WithSpanNameFormatter(func(_ string, r *http.Request) string {
+			return "CBR " + r.Method + " " + path.Base(r.URL.Path)
+		})),
+	}
+}
+
+func (o options) transport() *http.Transport {
+	transport := &http.Transport{
+		Proxy: http.ProxyFromEnvironment,
+		DialContext: (&net.Dialer{
+			Timeout:   o.connectTimeout,
+			KeepAlive: 30 * time.Second,
+		}).DialContext,
+		TLSHandshakeTimeout:   o.connectTimeout,
+		ResponseHeaderTimeout: o.responseHeaderTimeout,
+		MaxIdleConnsPerHost:   4,
+		IdleConnTimeout:       90 * time.Second,
+	}
+	if o.proxy != nil {
+		transport.Proxy = http.ProxyURL(o.proxy)
+	}
+	if o.rootCAs != nil {
+		transport.TLSClientConfig = &tls.Config{RootCAs: o.rootCAs, MinVersion: tls.VersionTLS12}
+	}
+	return transport
+}
@@ -0,0 +1,142 @@
+package cbr
+
+import (
+	"context"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewClient_Defaults(t *testing.T) {
+	logger, _ := test.NewNullLogger()
+	client, err := NewClient(logger)
+	require.NoError(t, err)
+
+	assert.Equal(t, DefaultBaseURL, client.baseURL)
+	assert.Equal(t, DefaultUserAgent, client.userAgent)
+	assert.Equal(t, 30*time.Second, client.httpClient.Timeout)
+	assert.Equal(t, DefaultPolicy, client.policy)
+}
+
+func TestNewClient_InvalidOptions(t *testing.T) {
+	logger, _ := test.NewNullLogger()
+
+	_, err := NewClient(logger, WithBaseURL("cbr.ru/scripts"))
+	assert.ErrorContains(t, err, "invalid base URL")
+
+	_, err = NewClient(logger, WithProxy("://proxy"))
+	assert.ErrorContains(t, err, "invalid proxy URL")
+
+	_, err = NewClient(logger, WithProxy("user:s3cr3t@proxy.local:3128"))
+	assert.ErrorContains(t, err, "invalid proxy URL")
+	assert.NotContains(t, err.Error(), "s3cr3t")
+
+	_, err = NewClient(logger, WithCABundle(filepath.Join(t.TempDir(), "missing.pem")))
+	assert.ErrorContains(t, err, "read CA bundle")
+
+	garbage := filepath.Join(t.TempDir(), "garbage.pem")
+	require.NoError(t, os.WriteFile(garbage, []byte("not a certificate"), 0o600))
+	_, err = NewClient(logger, WithCABundle(garbage))
+	assert.ErrorContains(t, err, "no certificates found")
+}
+
+func TestClient_UserAgentAndBaseURL(t *testing.T) {
+	var gotUA, gotPath string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotUA = r.UserAgent()
+		gotPath = r.URL.Path
+		fmt.Fprint(w, dailyPayload)
+	}))
+	defer srv.Close()
+
+	logger, _ := test.NewNullLogger()
+	client, err := NewClient(logger, WithBaseURL(srv.URL+"/mirror/scripts/"), WithUserAgent("RnD-service/1.0"))
+	require.NoError(t, err)
+
+	_, err = client.FetchRates(context.Background(), "02/01/2006")
+	require.NoError(t, err)
+	assert.Equal(t, "RnD-service/1.0", gotUA)
+	assert.Equal(t, "/mirror/scripts/XML_daily.asp", gotPath)
+}
+
+func TestClient_Proxy(t *testing.T) {
+	var gotURL string
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotURL = r.URL.String()
+		fmt.Fprint(w, dailyPayload)
+	}))
+	defer proxy.Close()
+
+	logger, _ := test.NewNullLogger()
+	client, err := NewClient(logger, WithBaseURL("http://cbr.internal/scripts"), WithProxy(proxy.URL), WithPolicy(noRetryPolicy()))
+	require.NoError(t, err)
+
+	_, err = client.FetchRates(context.Background(), "02/01/2006")
+	require.NoError(t, err)
+	assert.Equal(t, "http://cbr.internal/scripts/XML_daily.asp?date_req=02/01/2006", gotURL)
+}
+
+func TestOptions_ProxyFromEnvironmentByDefault(t *testing.T) {
+	transport := defaultOptions().transport()
+	require.NotNil(t, transport.Proxy)
+	assert.Equal(t, reflect.ValueOf(http.ProxyFromEnvironment).Pointer(), reflect.ValueOf(transport.Proxy).Pointer())
+
+	o := defaultOptions()
+	require.NoError(t, WithProxy("http://proxy.local:3128")(&o))
+	req := httptest.NewRequest(http.MethodGet, "https://www.cbr.ru/scripts/XML_daily.asp", nil)
+	proxyURL, err := o.transport().Proxy(req)
+	require.NoError(t, err)
+	assert.Equal(t, "http://proxy.local:3128", proxyURL.String())
+}
+
+func TestClient_CABundle(t *testing.T) {
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, dailyPayload)
+	}))
+	defer srv.Close()
+
+	bundle := filepath.Join(t.TempDir(), "ca.pem")
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: srv.Certificate().Raw})
+	require.NoError(t, os.WriteFile(bundle, certPEM, 0o600))
+
+	logger, _ := test.NewNullLogger()
+
+	untrusted, err := NewClient(logger, WithBaseURL(srv.URL), WithPolicy(noRetryPolicy()))
+	require.NoError(t, err)
+	_, err = untrusted.FetchRates(context.Background(), "02/01/2006")
+	assert.ErrorContains(t, err, "certificate")
+
+	trusted, err := NewClient(logger, WithBaseURL(srv.URL), WithCABundle(bundle), WithPolicy(noRetryPolicy()))
+	require.NoError(t, err)
+	vc, err := trusted.FetchRates(context.Background(), "02/01/2006")
+	require.NoError(t, err)
+	assert.Len(t, vc.Valutes, 1)
+}
+
+func TestClient_Timeout(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-time.After(time.Second):
+		case <-r.Context().Done():
+		}
+	}))
+	defer srv.Close()
+
+	logger, _ := test.NewNullLogger()
+	client, err := NewClient(logger, WithBaseURL(srv.URL), WithTimeout(50*time.Millisecond), WithPolicy(noRetryPolicy()))
+	require.NoError(t, err)
+
+	_, err = client.FetchRates(context.Background(), "02/01/2006")
+	require.Error(t, err)
+	assert.True(t, isTransient(err))
+}
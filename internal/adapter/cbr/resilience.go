@@ -0,0 +1,231 @@
+package cbr
+
+import (
+	"errors"
+	"io"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+// Policy controls how the client behaves when CBR is slow or failing.
+type Policy struct {
+	Retry          RetryPolicy
+	CircuitBreaker BreakerPolicy
+	RateLimit      RateLimitPolicy
+}
+
+// RetryPolicy retries transient failures with exponential backoff and jitter.
+// MaxAttempts counts the first request, so 1 disables retries.
+type RetryPolicy struct {
+	MaxAttempts int
+	BaseDelay   time.Duration
+	MaxDelay    time.Duration
+}
+
+// BreakerPolicy opens the circuit after FailureThreshold consecutive transient
+// failures and lets a single probe through once OpenTimeout has passed.
+// A zero threshold disables the breaker.
+type BreakerPolicy struct {
+	FailureThreshold int
+	OpenTimeout      time.Duration
+}
+
+// RateLimitPolicy caps outgoing requests, retries included.
+// A zero rate disables the limiter.
+type RateLimitPolicy struct {
+	RequestsPerSecond float64
+	Burst             int
+}
+
+var DefaultPolicy = Policy{
+	Retry: RetryPolicy{
+		MaxAttempts: 4,
+		BaseDelay:   500 * time.Millisecond,
+		MaxDelay:    10 * time.Second,
+	},
+	CircuitBreaker: BreakerPolicy{
+		FailureThreshold: 5,
+		OpenTimeout:      30 * time.Second,
+	},
+	RateLimit: RateLimitPolicy{
+		RequestsPerSecond: 5,
+		Burst:             1,
+	},
+}
+
+// backoff returns the delay before the retry that follows the given attempt:
+// base * 2^(attempt-1) capped at MaxDelay, with the upper half jittered.
+func (p RetryPolicy) backoff(attempt int) time.Duration {
+	d := p.BaseDelay
+	for i := 1; i < attempt && d < p.MaxDelay; i++ {
+		d *= 2
+	}
+	if p.MaxDelay > 0 && d > p.MaxDelay {
+		d = p.MaxDelay
+	}
+	if d <= 0 {
+		return 0
+	}
+	half := d / 2
+	return half + rand.N(d-half+1)
+}
+
+func newLimiter(p RateLimitPolicy) *rate.Limiter {
+	if p.RequestsPerSecond <= 0 {
+		return rate.NewLimiter(rate.Inf, 0)
+	}
+	return rate.NewLimiter(rate.Limit(p.RequestsPerSecond), max(p.Burst, 1))
+}
+
+// isTransient reports whether a failed request is worth repeating: network
+// errors, timeouts, truncated bodies, HTML error pages, 408, 429 and 5xx
+// other than 501.
+func isTransient(err error) bool {
+	var ue *UpstreamError
+	if errors.As(err, &ue) {
+		if errors.Is(ue, ErrErrorPage) {
+			return true
+		}
+		if !errors.Is(ue, ErrUnexpectedStatus) {
+			return false
+		}
+		switch {
+		case ue.StatusCode == http.StatusRequestTimeout, ue.StatusCode == http.StatusTooManyRequests:
+			return true
+		case ue.StatusCode >= 500 && ue.StatusCode != http.StatusNotImplemented:
+			return true
+		}
+		return false
+	}
+
+	var netErr net.Error
+	return errors.As(err, &netErr) || errors.Is(err, io.ErrUnexpectedEOF)
+}
+
+// parseRetryAfter accepts both forms allowed by RFC 9110: delay-seconds and HTTP-date.
+func parseRetryAfter(value string, now time.Time) time.Duration {
+	if value == "" {
+		return 0
+	}
+	if secs, err := strconv.Atoi(value); err == nil {
+		if secs < 0 {
+			return 0
+		}
+		return time.Duration(secs) * time.Second
+	}
+	if t, err := http.ParseTime(value); err == nil && t.After(now) {
+		return t.Sub(now)
+	}
+	return 0
+}
+
+type breakerState int
+
+const (
+	breakerClosed breakerState = iota
+	breakerOpen
+	breakerHalfOpen
+)
+
+func (s breakerState) String() string {
+	switch s {
+	case breakerOpen:
+		return "open"
+	case breakerHalfOpen:
+		return "half-open"
+	default:
+		return "closed"
+	}
+}
+
+type circuitBreaker struct {
+	policy BreakerPolicy
+	now    func() time.Time
+
+	mu       sync.Mutex
+	state    breakerState
+	failures int
+	openedAt time.Time
+	probing  bool
+}
+
+func newCircuitBreaker(policy BreakerPolicy) *circuitBreaker {
+	return &circuitBreaker{policy: policy, now: time.Now}
+}
+
+// allow returns ErrCircuitOpen while the circuit is open. After OpenTimeout
+// exactly one caller is let through as a probe; its outcome decides whether
+// the circuit closes again.
+func (b *circuitBreaker) allow() error {
+	if b.policy.FailureThreshold <= 0 {
+		return nil
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case breakerOpen:
+		if b.now().Sub(b.openedAt) < b.policy.OpenTimeout {
+			return ErrCircuitOpen
+		}
+		b.state = breakerHalfOpen
+		b.probing = true
+	case breakerHalfOpen:
+		if b.probing {
+			return ErrCircuitOpen
+		}
+		b.probing = true
+	}
+	return nil
+}
+
+// success reports that CBR answered, even if the answer was an error on our side.
+func (b *circuitBreaker) success() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.state = breakerClosed
+	b.failures = 0
+	b.probing = false
+}
+
+// failure records a transient failure and reports whether it opened the circuit.
+func (b *circuitBreaker) failure() bool {
+	if b.policy.FailureThreshold <= 0 {
+		return false
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.failures++
+	b.probing = false
+	if b.state == breakerHalfOpen || (b.state == breakerClosed && b.failures >= b.policy.FailureThreshold) {
+		b.state = breakerOpen
+		b.openedAt = b.now()
+		return true
+	}
+	return false
+}
+
+// release gives up a probe slot without an outcome, e.g. when the caller's context is cancelled.
+func (b *circuitBreaker) release() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.probing = false
+}
+
+func (b *circuitBreaker) currentState() breakerState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.state
+}
@@ -0,0 +1,261 @@
+package cbr
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const dailyPayload = `<?xml version="1.0" encoding="windows-1251"?><ValCurs Date="02.01.2006" name="Foreign Currency Market"><Valute ID="R01235"><NumCode>840</NumCode><CharCode>USD</CharCode><Nominal>1</Nominal><Name>US Dollar</Name><Value>90,1234</Value></Valute></ValCurs>`
+
+func fastPolicy() Policy {
+	return Policy{
+		Retry:          RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond, MaxDelay: 10 * time.Millisecond},
+		CircuitBreaker: BreakerPolicy{FailureThreshold: 10, OpenTimeout: time.Minute},
+	}
+}
+
+// newFlakyServer answers with the given statuses in order and with the daily payload afterwards.
+func newFlakyServer(t *testing.T, statuses []int, headers http.Header) (*httptest.Server, *int32) {
+	var calls int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := int(atomic.AddInt32(&calls, 1))
+		if n <= len(statuses) {
+			for k, v := range headers {
+				w.Header()[k] = v
+			}
+			w.WriteHeader(statuses[n-1])
+			fmt.Fprint(w, "<html><body>Service Unavailable</body></html>")
+			return
+		}
+		w.Header().Set("Content-Type", "application/xml; charset=windows-1251")
+		fmt.Fprint(w, dailyPayload)
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &calls
+}
+
+func newTestClient(t *testing.T, srv *httptest.Server, policy Policy) *Client {
+	logger, _ := test.NewNullLogger()
+	client, err := NewClient(logger, WithBaseURL(srv.URL), WithPolicy(policy))
+	require.NoError(t, err)
+	return client
+}
+
+func TestClient_RetriesTransientFailures(t *testing.T) {
+	srv, calls := newFlakyServer(t, []int{http.StatusServiceUnavailable, http.StatusBadGateway}, nil)
+	client := newTestClient(t, srv, fastPolicy())
+
+	vc, err := client.FetchRates(context.Background(), "02/01/2006")
+	require.NoError(t, err)
+	assert.Len(t, vc.Valutes, 1)
+	assert.Equal(t, int32(3), atomic.LoadInt32(calls))
+}
+
+type recordedFetch struct {
+	url  string
+	size int
+	err  error
+}
+
+type recordingObserver struct {
+	fetches []recordedFetch
+}
+
+func (o *recordingObserver) ObserveFetch(url string, _ time.Duration, size int, err error) {
+	o.fetches = append(o.fetches, recordedFetch{url: url, size: size, err: err})
+}
+
+func TestClient_ObservesEveryAttempt(t *testing.T) {
+	srv, _ := newFlakyServer(t, []int{http.StatusServiceUnavailable}, nil)
+	observer := &recordingObserver{}
+	logger, _ := test.NewNullLogger()
+	client, err := NewClient(logger, WithBaseURL(srv.URL), WithPolicy(fastPolicy()), WithObserver(observer))
+	require.NoError(t, err)
+
+	_, err = client.FetchRates(context.Background(), "02/01/2006")
+	require.NoError(t, err)
+
+	require.Len(t, observer.fetches, 2)
+	assert.Equal(t, srv.URL+"/XML_daily.asp?date_req=02/01/2006", observer.fetches[0].url)
+	assert.Error(t, observer.fetches[0].err)
+	assert.NoError(t, observer.fetches[1].err)
+	assert.Equal(t, len(dailyPayload), observer.fetches[1].size)
+}
+
+func TestClient_GivesUpAfterMaxAttempts(t *testing.T) {
+	srv, calls := newFlakyServer(t, []int{500, 500, 500, 500}, nil)
+	client := newTestClient(t, srv, fastPolicy())
+
+	_, err := client.FetchRates(context.Background(), "02/01/2006")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "giving up after 3 attempts")
+
+	var se *UpstreamError
+	require.True(t, errors.As(err, &se))
+	assert.Equal(t, http.StatusInternalServerError, se.StatusCode)
+	assert.Equal(t, int32(3), atomic.LoadInt32(calls))
+}
+
+func TestClient_DoesNotRetryClientErrors(t *testing.T) {
+	srv, calls := newFlakyServer(t, []int{http.StatusNotFound}, nil)
+	client := newTestClient(t, srv, fastPolicy())
+
+	_, err := client.FetchRates(context.Background(), "02/01/2006")
+	require.Error(t, err)
+	assert.Equal(t, int32(1), atomic.LoadInt32(calls))
+	assert.Equal(t, breakerClosed, client.breaker.currentState())
+}
+
+func TestClient_HonorsRetryAfter(t *testing.T) {
+	srv, calls := newFlakyServer(t, []int{http.StatusTooManyRequests}, http.Header{"Retry-After": {"1"}})
+	policy := fastPolicy()
+	policy.Retry.MaxDelay = 2 * time.Second
+	client := newTestClient(t, srv, policy)
+
+	start := time.Now()
+	_, err := client.FetchRates(context.Background(), "02/01/2006")
+	require.NoError(t, err)
+	assert.GreaterOrEqual(t, time.Since(start), time.Second)
+	assert.Equal(t, int32(2), atomic.LoadInt32(calls))
+}
+
+func TestClient_RetryAfterBeyondMaxDelay(t *testing.T) {
+	srv, calls := newFlakyServer(t, []int{http.StatusServiceUnavailable}, http.Header{"Retry-After": {"120"}})
+	client := newTestClient(t, srv, fastPolicy())
+
+	_, err := client.FetchRates(context.Background(), "02/01/2006")
+	require.Error(t, err)
+	assert.Equal(t, int32(1), atomic.LoadInt32(calls))
+}
+
+func TestClient_CircuitBreakerFailsFast(t *testing.T) {
+	srv, calls := newFlakyServer(t, []int{503, 503, 503, 503}, nil)
+	policy := fastPolicy()
+	policy.Retry.MaxAttempts = 1
+	policy.CircuitBreaker = BreakerPolicy{FailureThreshold: 2, OpenTimeout: time.Minute}
+	client := newTestClient(t, srv, policy)
+
+	for i := 0; i < 2; i++ {
+		_, err := client.FetchRates(context.Background(), "02/01/2006")
+		require.Error(t, err)
+		assert.NotErrorIs(t, err, ErrCircuitOpen)
+	}
+
+	_, err := client.FetchRates(context.Background(), "02/01/2006")
+	assert.ErrorIs(t, err, ErrCircuitOpen)
+	assert.Equal(t, int32(2), atomic.LoadInt32(calls))
+}
+
+func TestClient_RateLimiter(t *testing.T) {
+	srv, calls := newFlakyServer(t, nil, nil)
+	policy := fastPolicy()
+	policy.RateLimit = RateLimitPolicy{RequestsPerSecond: 20, Burst: 1}
+	client := newTestClient(t, srv, policy)
+
+	start := time.Now()
+	for i := 0; i < 4; i++ {
+		_, err := client.FetchRates(context.Background(), "02/01/2006")
+		require.NoError(t, err)
+	}
+	// the first request uses the burst, the other three wait 50ms each
+	assert.GreaterOrEqual(t, time.Since(start), 140*time.Millisecond)
+	assert.Equal(t, int32(4), atomic.LoadInt32(calls))
+}
+
+func TestClient_RetryStopsOnContextCancel(t *testing.T) {
+	srv, calls := newFlakyServer(t, []int{503, 503, 503}, nil)
+	policy := fastPolicy()
+	policy.Retry = RetryPolicy{MaxAttempts: 3, BaseDelay: time.Minute, MaxDelay: time.Minute}
+	client := newTestClient(t, srv, policy)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	_, err := client.FetchRates(ctx, "02/01/2006")
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Equal(t, int32(1), atomic.LoadInt32(calls))
+}
+
+func TestCircuitBreaker_HalfOpen(t *testing.T) {
+	now := time.Date(2025, 8, 1, 12, 0, 0, 0, time.UTC)
+	b := newCircuitBreaker(BreakerPolicy{FailureThreshold: 2, OpenTimeout: 30 * time.Second})
+	b.now = func() time.Time { return now }
+
+	require.NoError(t, b.allow())
+	assert.False(t, b.failure())
+	assert.True(t, b.failure())
+	assert.Equal(t, breakerOpen, b.currentState())
+	assert.ErrorIs(t, b.allow(), ErrCircuitOpen)
+
+	now = now.Add(31 * time.Second)
+	require.NoError(t, b.allow(), "first caller after the timeout is the probe")
+	assert.Equal(t, breakerHalfOpen, b.currentState())
+	assert.ErrorIs(t, b.allow(), ErrCircuitOpen, "only one probe at a time")
+
+	assert.True(t, b.failure(), "failed probe reopens the circuit")
+	assert.ErrorIs(t, b.allow(), ErrCircuitOpen)
+
+	now = now.Add(31 * time.Second)
+	require.NoError(t, b.allow())
+	b.success()
+	assert.Equal(t, breakerClosed, b.currentState())
+	require.NoError(t, b.allow())
+}
+
+func TestCircuitBreaker_Disabled(t *testing.T) {
+	b := newCircuitBreaker(BreakerPolicy{})
+	for i := 0; i < 10; i++ {
+		assert.False(t, b.failure())
+	}
+	assert.NoError(t, b.allow())
+}
+
+func TestRetryPolicy_Backoff(t *testing.T) {
+	p := RetryPolicy{BaseDelay: 100 * time.Millisecond, MaxDelay: time.Second}
+
+	for i := 0; i < 100; i++ {
+		d := p.backoff(1)
+		assert.GreaterOrEqual(t, d, 50*time.Millisecond)
+		assert.LessOrEqual(t, d, 100*time.Millisecond)
+
+		d = p.backoff(3)
+		assert.GreaterOrEqual(t, d, 200*time.Millisecond)
+		assert.LessOrEqual(t, d, 400*time.Millisecond)
+
+		d = p.backoff(50)
+		assert.GreaterOrEqual(t, d, 500*time.Millisecond)
+		assert.LessOrEqual(t, d, time.Second)
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2025, 8, 1, 12, 0, 0, 0, time.UTC)
+
+	assert.Equal(t, 5*time.Second, parseRetryAfter("5", now))
+	assert.Equal(t, 90*time.Second, parseRetryAfter(now.Add(90*time.Second).Format(http.TimeFormat), now))
+	assert.Zero(t, parseRetryAfter(now.Add(-time.Minute).Format(http.TimeFormat), now))
+	assert.Zero(t, parseRetryAfter("", now))
+	assert.Zero(t, parseRetryAfter("-1", now))
+	assert.Zero(t, parseRetryAfter("soon", now))
+}
+
+func TestIsTransient(t *testing.T) {
+	assert.True(t, isTransient(&UpstreamError{StatusCode: 503, Err: ErrUnexpectedStatus}))
+	assert.True(t, isTransient(&UpstreamError{StatusCode: 429, Err: ErrUnexpectedStatus}))
+	assert.True(t, isTransient(&UpstreamError{StatusCode: 408, Err: ErrUnexpectedStatus}))
+	assert.False(t, isTransient(&UpstreamError{StatusCode: 501, Err: ErrUnexpectedStatus}))
+	assert.False(t, isTransient(&UpstreamError{StatusCode: 404, Err: ErrUnexpectedStatus}))
+	assert.True(t, isTransient(&UpstreamError{StatusCode: 200, Err: ErrErrorPage}))
+	assert.False(t, isTransient(&UpstreamError{StatusCode: 200, Err: ErrNoData}))
+	assert.False(t, isTransient(&UpstreamError{StatusCode: 200, Err: ErrInvalidPayload}))
+}
@@ -0,0 +1,67 @@
+package ecb
+
+import (
+	"encoding/xml"
+	"fmt"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// Feed is one of the eurofxref files ECB publishes around 16:00 CET on TARGET business days.
+type Feed string
+
+const (
+	FeedDaily   Feed = "eurofxref-daily.xml"
+	Feed90Days  Feed = "eurofxref-hist-90d.xml"
+	FeedHistory Feed = "eurofxref-hist.xml"
+)
+
+// Envelope is a eurofxref file: one Cube per day, newest first. Rates are
+// quoted as units of currency per 1 EUR.
+type Envelope struct {
+	XMLName xml.Name  `xml:"Envelope"`
+	Sender  string    `xml:"Sender>name"`
+	Days    []DayCube `xml:"Cube>Cube"`
+	// PayloadHash is the SHA-256 of the raw feed the envelope was parsed from.
+	PayloadHash string `xml:"-"`
+}
+
+type DayCube struct {
+	Time  string `xml:"time,attr"`
+	Rates []Rate `xml:"Cube"`
+}
+
+type Rate struct {
+	Currency string `xml:"currency,attr"`
+	Rate     string `xml:"rate,attr"`
+}
+
+func (d DayCube) GetDate() (time.Time, error) {
+	return time.Parse("2006-01-02", d.Time)
+}
+
+func (r Rate) GetRate() (decimal.Decimal, error) {
+	return decimal.NewFromString(r.Rate)
+}
+
+func (e *Envelope) validate() error {
+	if len(e.Days) == 0 {
+		return fmt.Errorf("%w: no dated Cube in response", ErrInvalidPayload)
+	}
+	for _, day := range e.Days {
+		if _, err := day.GetDate(); err != nil {
+			return fmt.Errorf("%w: Cube has invalid time %q", ErrInvalidPayload, day.Time)
+		}
+		for _, rate := range day.Rates {
+			value, err := rate.GetRate()
+			if err != nil || !value.IsPositive() {
+				return fmt.Errorf("%w: %s on %s has invalid rate %q", ErrInvalidPayload, rate.Currency, day.Time, rate.Rate)
+			}
+			if len(rate.Currency) != 3 {
+				return fmt.Errorf("%w: invalid currency %q on %s", ErrInvalidPayload, rate.Currency, day.Time)
+			}
+		}
+	}
+	return nil
+}
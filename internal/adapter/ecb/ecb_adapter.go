@@ -0,0 +1,81 @@
+package ecb
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/sirupsen/logrus"
+)
+
+// maxBodySize covers the full history feed, which grows by about 1.5KB a day.
+const maxBodySize = 32 << 20
+
+type Client struct {
+	httpClient *http.Client
+	baseURL    string
+	userAgent  string
+	logger     *logrus.Logger
+}
+
+func NewClient(logger *logrus.Logger, opts ...Option) (*Client, error) {
+	o := defaultOptions()
+	for _, opt := range opts {
+		if err := opt(&o); err != nil {
+			return nil, fmt.Errorf("configure ECB client: %w", err)
+		}
+	}
+
+	return &Client{
+		httpClient: &http.Client{Timeout: o.timeout},
+		baseURL:    o.baseURL,
+		userAgent:  o.userAgent,
+		logger:     logger,
+	}, nil
+}
+
+func (c *Client) FetchRates(ctx context.Context, feed Feed) (*Envelope, error) {
+	url := fmt.Sprintf("%s/%s", c.baseURL, feed)
+	c.logger.Infof("Fetching ECB reference rates from %s", url)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("create request: %w", err)
+	}
+	req.Header.Set("User-Agent", c.userAgent)
+	req.Header.Set("Accept", "application/xml")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		c.logger.WithError(err).Errorf("ECB request to %s failed", url)
+		return nil, fmt.Errorf("ECB %s: %w", url, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		c.logger.Errorf("ECB %s answered with status %d", url, resp.StatusCode)
+		return nil, fmt.Errorf("ECB %s: %w %d", url, ErrUnexpectedStatus, resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxBodySize))
+	if err != nil {
+		return nil, fmt.Errorf("ECB %s: read body: %w", url, err)
+	}
+
+	var envelope Envelope
+	if err := xml.Unmarshal(body, &envelope); err != nil {
+		return nil, fmt.Errorf("ECB %s: %w: %w", url, ErrInvalidPayload, err)
+	}
+	if err := envelope.validate(); err != nil {
+		return nil, fmt.Errorf("ECB %s: %w", url, err)
+	}
+	sum := sha256.Sum256(body)
+	envelope.PayloadHash = hex.EncodeToString(sum[:])
+
+	c.logger.Infof("Successfully parsed %d days of ECB reference rates", len(envelope.Days))
+	return &envelope, nil
+}
@@ -0,0 +1,97 @@
+package ecb
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newFixtureClient serves the recorded feeds in testdata. The 90-day feed is
+// trimmed to its last six business days.
+func newFixtureClient(t *testing.T) *Client {
+	srv := httptest.NewServer(http.StripPrefix("/stats/eurofxref", http.FileServer(http.Dir("testdata"))))
+	t.Cleanup(srv.Close)
+
+	logger, _ := test.NewNullLogger()
+	client, err := NewClient(logger, WithBaseURL(srv.URL+"/stats/eurofxref"))
+	require.NoError(t, err)
+	return client
+}
+
+func TestClient_FetchDaily(t *testing.T) {
+	client := newFixtureClient(t)
+
+	envelope, err := client.FetchRates(context.Background(), FeedDaily)
+	require.NoError(t, err)
+	assert.Equal(t, "European Central Bank", envelope.Sender)
+	assert.Len(t, envelope.PayloadHash, 64)
+	require.Len(t, envelope.Days, 1)
+
+	date, err := envelope.Days[0].GetDate()
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2025, 8, 1, 0, 0, 0, 0, time.UTC), date)
+	require.Len(t, envelope.Days[0].Rates, 30)
+	assert.Equal(t, "USD", envelope.Days[0].Rates[0].Currency)
+	rate, err := envelope.Days[0].Rates[0].GetRate()
+	require.NoError(t, err)
+	assert.Equal(t, "1.1404", rate.String())
+}
+
+func TestClient_FetchHistory(t *testing.T) {
+	client := newFixtureClient(t)
+
+	envelope, err := client.FetchRates(context.Background(), Feed90Days)
+	require.NoError(t, err)
+	require.Len(t, envelope.Days, 6)
+	assert.Equal(t, "2025-08-01", envelope.Days[0].Time, "newest first")
+	assert.Equal(t, "2025-07-25", envelope.Days[5].Time)
+}
+
+func TestClient_UnexpectedStatus(t *testing.T) {
+	client := newFixtureClient(t)
+
+	_, err := client.FetchRates(context.Background(), FeedHistory)
+	assert.ErrorIs(t, err, ErrUnexpectedStatus, "the full history is not recorded")
+}
+
+func TestClient_InvalidPayload(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+	}{
+		{"not xml", "<html>maintenance</html"},
+		{"no cubes", `<gesmes:Envelope xmlns:gesmes="http://www.gesmes.org/xml/2002-08-01"><Cube></Cube></gesmes:Envelope>`},
+		{"bad rate", `<gesmes:Envelope xmlns:gesmes="http://www.gesmes.org/xml/2002-08-01"><Cube><Cube time="2025-08-01"><Cube currency="USD" rate="n/a"/></Cube></Cube></gesmes:Envelope>`},
+		{"zero rate", `<gesmes:Envelope xmlns:gesmes="http://www.gesmes.org/xml/2002-08-01"><Cube><Cube time="2025-08-01"><Cube currency="USD" rate="0"/></Cube></Cube></gesmes:Envelope>`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			require.NoError(t, os.WriteFile(filepath.Join(dir, string(FeedDaily)), []byte(tt.body), 0o644))
+			srv := httptest.NewServer(http.FileServer(http.Dir(dir)))
+			t.Cleanup(srv.Close)
+
+			logger, _ := test.NewNullLogger()
+			client, err := NewClient(logger, WithBaseURL(srv.URL))
+			require.NoError(t, err)
+
+			_, err = client.FetchRates(context.Background(), FeedDaily)
+			assert.ErrorIs(t, err, ErrInvalidPayload)
+		})
+	}
+}
+
+func TestWithBaseURL_Invalid(t *testing.T) {
+	logger, _ := test.NewNullLogger()
+	_, err := NewClient(logger, WithBaseURL("not a url"))
+	assert.Error(t, err)
+}
@@ -0,0 +1,7 @@
+package ecb
+
+import "context"
+
+type EcbClient interface {
+	FetchRates(ctx context.Context, feed Feed) (*Envelope, error)
+}
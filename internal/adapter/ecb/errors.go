@@ -0,0 +1,8 @@
+package ecb
+
+import "errors"
+
+var (
+	ErrUnexpectedStatus = errors.New("unexpected HTTP status from ECB")
+	ErrInvalidPayload   = errors.New("invalid ECB payload")
+)
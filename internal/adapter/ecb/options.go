@@ -0,0 +1,62 @@
+package ecb
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const DefaultBaseURL = "https://www.ecb.europa.eu/stats/eurofxref"
+
+type options struct {
+	baseURL   string
+	timeout   time.Duration
+	userAgent string
+}
+
+func defaultOptions() options {
+	return options{
+		baseURL:   DefaultBaseURL,
+		timeout:   30 * time.Second,
+		userAgent: "RnD-service",
+	}
+}
+
+// Option configures a Client. Empty strings and zero durations keep the defaults.
+type Option func(*options) error
+
+// WithBaseURL points the client at a mirror or a fake server.
+func WithBaseURL(baseURL string) Option {
+	return func(o *options) error {
+		if baseURL == "" {
+			return nil
+		}
+		u, err := url.Parse(baseURL)
+		if err != nil || u.Scheme == "" || u.Host == "" {
+			return fmt.Errorf("invalid base URL %q", baseURL)
+		}
+		o.baseURL = strings.TrimRight(baseURL, "/")
+		return nil
+	}
+}
+
+// WithTimeout limits a single request, including reading the body.
+// The full history feed is several megabytes.
+func WithTimeout(d time.Duration) Option {
+	return func(o *options) error {
+		if d > 0 {
+			o.timeout = d
+		}
+		return nil
+	}
+}
+
+func WithUserAgent(userAgent string) Option {
+	return func(o *options) error {
+		if userAgent != "" {
+			o.userAgent = userAgent
+		}
+		return nil
+	}
+}
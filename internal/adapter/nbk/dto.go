@@ -0,0 +1,64 @@
+package nbk
+
+import (
+	"encoding/xml"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// Rates is the get_rates.cfm feed: official KZT rates for a date, each item
+// the price of Quant units in tenge. Non-working days come without items.
+type Rates struct {
+	XMLName xml.Name `xml:"rates"`
+	Date    string   `xml:"date"`
+	Items   []Item   `xml:"item"`
+	// PayloadHash is the SHA-256 of the raw feed the rates were parsed from.
+	PayloadHash string `xml:"-"`
+}
+
+type Item struct {
+	FullName    string `xml:"fullname"`
+	Title       string `xml:"title"`
+	Description string `xml:"description"`
+	Quant       string `xml:"quant"`
+}
+
+func (r Rates) GetDate() (time.Time, error) {
+	return time.Parse("02.01.2006", strings.TrimSpace(r.Date))
+}
+
+func (i Item) GetCharCode() string {
+	return strings.TrimSpace(i.Title)
+}
+
+func (i Item) GetValue() (decimal.Decimal, error) {
+	return decimal.NewFromString(strings.TrimSpace(i.Description))
+}
+
+func (i Item) GetQuant() (int, error) {
+	return strconv.Atoi(strings.TrimSpace(i.Quant))
+}
+
+func (r *Rates) validate() error {
+	if _, err := r.GetDate(); err != nil {
+		return fmt.Errorf("%w: invalid date %q", ErrInvalidPayload, r.Date)
+	}
+	for _, item := range r.Items {
+		if len(item.GetCharCode()) != 3 {
+			return fmt.Errorf("%w: invalid currency %q on %s", ErrInvalidPayload, item.Title, r.Date)
+		}
+		value, err := item.GetValue()
+		if err != nil || !value.IsPositive() {
+			return fmt.Errorf("%w: %s on %s has invalid rate %q", ErrInvalidPayload, item.Title, r.Date, item.Description)
+		}
+		quant, err := item.GetQuant()
+		if err != nil || quant <= 0 {
+			return fmt.Errorf("%w: %s on %s has invalid quant %q", ErrInvalidPayload, item.Title, r.Date, item.Quant)
+		}
+	}
+	return nil
+}
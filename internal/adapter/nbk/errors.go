@@ -0,0 +1,25 @@
+package nbk
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	ErrUnexpectedStatus = errors.New("unexpected HTTP status from NBK")
+	ErrInvalidPayload   = errors.New("invalid NBK payload")
+)
+
+// StatusError is a non-200 answer; it matches ErrUnexpectedStatus.
+type StatusError struct {
+	URL        string
+	StatusCode int
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("NBK %s: %s %d", e.URL, ErrUnexpectedStatus, e.StatusCode)
+}
+
+func (e *StatusError) Unwrap() error {
+	return ErrUnexpectedStatus
+}
@@ -0,0 +1,138 @@
+package nbk
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"golang.org/x/time/rate"
+)
+
+const maxBodySize = 1 << 20
+
+type Client struct {
+	httpClient  *http.Client
+	baseURL     string
+	userAgent   string
+	maxAttempts int
+	baseDelay   time.Duration
+	maxDelay    time.Duration
+	limiter     *rate.Limiter
+	logger      *logrus.Logger
+}
+
+func NewClient(logger *logrus.Logger, opts ...Option) (*Client, error) {
+	o := defaultOptions()
+	for _, opt := range opts {
+		if err := opt(&o); err != nil {
+			return nil, fmt.Errorf("configure NBK client: %w", err)
+		}
+	}
+
+	return &Client{
+		httpClient:  &http.Client{Timeout: o.timeout},
+		baseURL:     o.baseURL,
+		userAgent:   o.userAgent,
+		maxAttempts: o.maxAttempts,
+		baseDelay:   o.baseDelay,
+		maxDelay:    o.maxDelay,
+		limiter:     rate.NewLimiter(rate.Limit(o.requestsPerSecond), o.burst),
+		logger:      logger,
+	}, nil
+}
+
+// FetchRates returns the official rates set for date. Network errors, 408,
+// 429 and 5xx are retried with exponential backoff; every attempt waits for
+// the rate limiter.
+func (c *Client) FetchRates(ctx context.Context, date time.Time) (*Rates, error) {
+	endpoint := fmt.Sprintf("%s/get_rates.cfm?%s", c.baseURL, url.Values{"fdate": {date.Format("02.01.2006")}}.Encode())
+	logger := c.logger.WithContext(ctx)
+
+	for attempt := 1; ; attempt++ {
+		if err := c.limiter.Wait(ctx); err != nil {
+			return nil, fmt.Errorf("wait for rate limiter: %w", err)
+		}
+		rates, err := c.fetchRates(ctx, endpoint)
+		if err == nil {
+			return rates, nil
+		}
+		if ctx.Err() != nil || !retryable(err) || attempt >= c.maxAttempts {
+			return nil, err
+		}
+
+		delay := c.backoff(attempt)
+		logger.Warnf("NBK request failed (attempt %d/%d): %v, retrying in %s", attempt, c.maxAttempts, err, delay)
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("retry cancelled: %w", ctx.Err())
+		case <-time.After(delay):
+		}
+	}
+}
+
+func (c *Client) fetchRates(ctx context.Context, endpoint string) (*Rates, error) {
+	c.logger.WithContext(ctx).Infof("Fetching NBK rates from %s", endpoint)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("create request: %w", err)
+	}
+	req.Header.Set("User-Agent", c.userAgent)
+	req.Header.Set("Accept", "application/xml")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		c.logger.WithContext(ctx).WithError(err).Errorf("NBK request to %s failed", endpoint)
+		return nil, fmt.Errorf("NBK %s: %w", endpoint, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		c.logger.WithContext(ctx).Errorf("NBK %s answered with status %d", endpoint, resp.StatusCode)
+		return nil, &StatusError{URL: endpoint, StatusCode: resp.StatusCode}
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxBodySize))
+	if err != nil {
+		return nil, fmt.Errorf("NBK %s: read body: %w", endpoint, err)
+	}
+
+	var rates Rates
+	if err := xml.Unmarshal(body, &rates); err != nil {
+		return nil, fmt.Errorf("NBK %s: %w: %w", endpoint, ErrInvalidPayload, err)
+	}
+	if err := rates.validate(); err != nil {
+		return nil, fmt.Errorf("NBK %s: %w", endpoint, err)
+	}
+	sum := sha256.Sum256(body)
+	rates.PayloadHash = hex.EncodeToString(sum[:])
+
+	c.logger.WithContext(ctx).Infof("Successfully parsed %d NBK rates for %s", len(rates.Items), rates.Date)
+	return &rates, nil
+}
+
+func (c *Client) backoff(attempt int) time.Duration {
+	d := c.baseDelay
+	for i := 1; i < attempt && d < c.maxDelay; i++ {
+		d *= 2
+	}
+	return min(d, c.maxDelay)
+}
+
+// retryable reports whether NBK may answer the same request later: network
+// errors, 408, 429 and 5xx. Invalid payloads and other statuses will not change.
+func retryable(err error) bool {
+	var se *StatusError
+	if errors.As(err, &se) {
+		return se.StatusCode == http.StatusRequestTimeout || se.StatusCode == http.StatusTooManyRequests || se.StatusCode >= 500
+	}
+	return !errors.Is(err, ErrInvalidPayload)
+}
@@ -0,0 +1,152 @@
+package nbk
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fixtureHandler serves testdata/rates_<YYYY-MM-DD>.xml for ?fdate=DD.MM.YYYY:
+// 31 July and 1 August 2025 and Saturday 2 August, which has no items.
+func fixtureHandler(dir string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		date, err := time.Parse("02.01.2006", r.URL.Query().Get("fdate"))
+		if r.URL.Path != "/rss/get_rates.cfm" || err != nil {
+			http.NotFound(w, r)
+			return
+		}
+		http.ServeFile(w, r, filepath.Join(dir, "rates_"+date.Format("2006-01-02")+".xml"))
+	})
+}
+
+func newFixtureClient(t *testing.T) *Client {
+	srv := httptest.NewServer(fixtureHandler("testdata"))
+	t.Cleanup(srv.Close)
+
+	logger, _ := test.NewNullLogger()
+	client, err := NewClient(logger, WithBaseURL(srv.URL+"/rss"))
+	require.NoError(t, err)
+	return client
+}
+
+func TestClient_FetchRates(t *testing.T) {
+	client := newFixtureClient(t)
+
+	rates, err := client.FetchRates(context.Background(), time.Date(2025, 8, 1, 0, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+	assert.Len(t, rates.PayloadHash, 64)
+	date, err := rates.GetDate()
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2025, 8, 1, 0, 0, 0, 0, time.UTC), date)
+	require.Len(t, rates.Items, 8)
+
+	usd := rates.Items[6]
+	assert.Equal(t, "USD", usd.GetCharCode())
+	assert.Equal(t, "ДОЛЛАР США", usd.FullName)
+	value, err := usd.GetValue()
+	require.NoError(t, err)
+	assert.Equal(t, "541.95", value.String())
+
+	quant, err := rates.Items[7].GetQuant()
+	require.NoError(t, err)
+	assert.Equal(t, 100, quant)
+}
+
+func TestClient_FetchRates_NonWorkingDay(t *testing.T) {
+	client := newFixtureClient(t)
+
+	rates, err := client.FetchRates(context.Background(), time.Date(2025, 8, 2, 0, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+	assert.Empty(t, rates.Items)
+}
+
+func TestClient_UnexpectedStatus(t *testing.T) {
+	client := newFixtureClient(t)
+
+	_, err := client.FetchRates(context.Background(), time.Date(2024, 1, 5, 0, 0, 0, 0, time.UTC))
+	assert.ErrorIs(t, err, ErrUnexpectedStatus)
+}
+
+func TestClient_RetriesTransientFailures(t *testing.T) {
+	attempts := 0
+	fixtures := fixtureHandler("testdata")
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		if attempts == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		fixtures.ServeHTTP(w, r)
+	}))
+	t.Cleanup(srv.Close)
+
+	logger, _ := test.NewNullLogger()
+	client, err := NewClient(logger, WithBaseURL(srv.URL+"/rss"), WithRetry(3, time.Millisecond, time.Millisecond))
+	require.NoError(t, err)
+
+	rates, err := client.FetchRates(context.Background(), time.Date(2025, 8, 1, 0, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+	assert.NotEmpty(t, rates.Items)
+	assert.Equal(t, 2, attempts)
+}
+
+func TestClient_DoesNotRetryNotFound(t *testing.T) {
+	attempts := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		http.NotFound(w, r)
+	}))
+	t.Cleanup(srv.Close)
+
+	logger, _ := test.NewNullLogger()
+	client, err := NewClient(logger, WithBaseURL(srv.URL), WithRetry(3, time.Millisecond, time.Millisecond))
+	require.NoError(t, err)
+
+	_, err = client.FetchRates(context.Background(), time.Date(2025, 8, 1, 0, 0, 0, 0, time.UTC))
+	var se *StatusError
+	require.ErrorAs(t, err, &se)
+	assert.Equal(t, http.StatusNotFound, se.StatusCode)
+	assert.Equal(t, 1, attempts)
+}
+
+func TestClient_InvalidPayload(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+	}{
+		{"not xml", "<html>maintenance</html"},
+		{"no date", `<rates><item><title>USD</title><description>541.95</description><quant>1</quant></item></rates>`},
+		{"bad rate", `<rates><date>01.08.2025</date><item><title>USD</title><description>n/a</description><quant>1</quant></item></rates>`},
+		{"zero rate", `<rates><date>01.08.2025</date><item><title>USD</title><description>0</description><quant>1</quant></item></rates>`},
+		{"zero quant", `<rates><date>01.08.2025</date><item><title>USD</title><description>541.95</description><quant>0</quant></item></rates>`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Write([]byte(tt.body))
+			}))
+			t.Cleanup(srv.Close)
+
+			logger, _ := test.NewNullLogger()
+			client, err := NewClient(logger, WithBaseURL(srv.URL))
+			require.NoError(t, err)
+
+			_, err = client.FetchRates(context.Background(), time.Date(2025, 8, 1, 0, 0, 0, 0, time.UTC))
+			assert.ErrorIs(t, err, ErrInvalidPayload)
+		})
+	}
+}
+
+func TestWithBaseURL_Invalid(t *testing.T) {
+	logger, _ := test.NewNullLogger()
+	_, err := NewClient(logger, WithBaseURL("not a url"))
+	assert.Error(t, err)
+}
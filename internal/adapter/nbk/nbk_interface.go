@@ -0,0 +1,10 @@
+package nbk
+
+import (
+	"context"
+	"time"
+)
+
+type NbkClient interface {
+	FetchRates(ctx context.Context, date time.Time) (*Rates, error)
+}
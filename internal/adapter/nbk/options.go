@@ -0,0 +1,100 @@
+package nbk
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const DefaultBaseURL = "https://nationalbank.kz/rss"
+
+type options struct {
+	baseURL           string
+	timeout           time.Duration
+	userAgent         string
+	maxAttempts       int
+	baseDelay         time.Duration
+	maxDelay          time.Duration
+	requestsPerSecond float64
+	burst             int
+}
+
+func defaultOptions() options {
+	return options{
+		baseURL:           DefaultBaseURL,
+		timeout:           30 * time.Second,
+		userAgent:         "RnD-service",
+		maxAttempts:       3,
+		baseDelay:         500 * time.Millisecond,
+		maxDelay:          5 * time.Second,
+		requestsPerSecond: 10,
+		burst:             1,
+	}
+}
+
+// Option configures a Client. Empty strings and zero durations keep the defaults.
+type Option func(*options) error
+
+// WithBaseURL points the client at a mirror or a fake server.
+func WithBaseURL(baseURL string) Option {
+	return func(o *options) error {
+		if baseURL == "" {
+			return nil
+		}
+		u, err := url.Parse(baseURL)
+		if err != nil || u.Scheme == "" || u.Host == "" {
+			return fmt.Errorf("invalid base URL %q", baseURL)
+		}
+		o.baseURL = strings.TrimRight(baseURL, "/")
+		return nil
+	}
+}
+
+func WithTimeout(d time.Duration) Option {
+	return func(o *options) error {
+		if d > 0 {
+			o.timeout = d
+		}
+		return nil
+	}
+}
+
+func WithUserAgent(userAgent string) Option {
+	return func(o *options) error {
+		if userAgent != "" {
+			o.userAgent = userAgent
+		}
+		return nil
+	}
+}
+
+// WithRetry sets how often a request is attempted, the first one included,
+// and the bounds of the exponential backoff between attempts.
+func WithRetry(maxAttempts int, baseDelay, maxDelay time.Duration) Option {
+	return func(o *options) error {
+		if maxAttempts > 0 {
+			o.maxAttempts = maxAttempts
+		}
+		if baseDelay > 0 {
+			o.baseDelay = baseDelay
+		}
+		if maxDelay > 0 {
+			o.maxDelay = maxDelay
+		}
+		return nil
+	}
+}
+
+// WithRateLimit caps outgoing requests, retries included, shared by all callers of the client.
+func WithRateLimit(requestsPerSecond float64, burst int) Option {
+	return func(o *options) error {
+		if requestsPerSecond > 0 {
+			o.requestsPerSecond = requestsPerSecond
+		}
+		if burst > 0 {
+			o.burst = burst
+		}
+		return nil
+	}
+}
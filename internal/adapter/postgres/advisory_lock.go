@@ -0,0 +1,96 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"sync"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/sirupsen/logrus"
+)
+
+// AdvisoryLock is a Postgres advisory lock named by a string. It is held by
+// an open transaction, so a replica that dies or loses its connection
+// releases it without any cleanup.
+type AdvisoryLock struct {
+	pool   Pool
+	name   string
+	key    int64
+	logger *logrus.Logger
+
+	mu sync.Mutex
+	tx pgx.Tx
+}
+
+func NewAdvisoryLock(pool Pool, name string, logger *logrus.Logger) *AdvisoryLock {
+	return &AdvisoryLock{pool: pool, name: name, key: advisoryLockKey(name), logger: logger}
+}
+
+// advisoryLockKey maps a lock name to the bigint key Postgres expects.
+func advisoryLockKey(name string) int64 {
+	h := fnv.New64a()
+	h.Write([]byte("rnd:" + name))
+	return int64(h.Sum64())
+}
+
+func (l *AdvisoryLock) TryAcquire(ctx context.Context) (bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	logger := l.logger.WithContext(ctx).WithField("lock", l.name)
+	if l.tx != nil {
+		_, err := l.tx.Exec(ctx, "SELECT 1")
+		if err == nil {
+			return true, nil
+		}
+		logger.WithError(err).Warn("Lost advisory lock connection")
+		l.rollback(ctx, l.tx)
+		l.tx = nil
+	}
+
+	tx, err := l.pool.Begin(ctx)
+	if err != nil {
+		return false, fmt.Errorf("begin lock tx: %w", err)
+	}
+
+	var acquired bool
+	if err := tx.QueryRow(ctx, "SELECT pg_try_advisory_xact_lock($1)", l.key).Scan(&acquired); err != nil {
+		l.rollback(ctx, tx)
+		return false, fmt.Errorf("acquire lock %s: %w", l.name, err)
+	}
+	if !acquired {
+		l.rollback(ctx, tx)
+		logger.Debug("Advisory lock is held elsewhere")
+		return false, nil
+	}
+
+	l.tx = tx
+	logger.Debug("Acquired advisory lock")
+	return true, nil
+}
+
+func (l *AdvisoryLock) Release(ctx context.Context) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.tx == nil {
+		return nil
+	}
+	err := l.rollback(ctx, l.tx)
+	l.tx = nil
+	if err != nil {
+		return fmt.Errorf("release lock %s: %w", l.name, err)
+	}
+	l.logger.WithContext(ctx).WithField("lock", l.name).Debug("Released advisory lock")
+	return nil
+}
+
+func (l *AdvisoryLock) rollback(ctx context.Context, tx pgx.Tx) error {
+	if err := tx.Rollback(ctx); err != nil && !errors.Is(err, pgx.ErrTxClosed) {
+		l.logger.WithContext(ctx).WithError(err).WithField("lock", l.name).Error("Failed to roll back advisory lock tx")
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,102 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"io"
+	"regexp"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	pgxmock "github.com/pashagolub/pgxmock/v4"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const tryLockSQL = "SELECT pg_try_advisory_xact_lock($1)"
+
+func setupAdvisoryLock(t *testing.T, name string) (*AdvisoryLock, pgxmock.PgxPoolIface) {
+	mock, err := pgxmock.NewPool()
+	require.NoError(t, err)
+
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	return NewAdvisoryLock(mock, name, logger), mock
+}
+
+func TestAdvisoryLockKey(t *testing.T) {
+	assert.Equal(t, advisoryLockKey("scheduler"), advisoryLockKey("scheduler"))
+	assert.NotEqual(t, advisoryLockKey("scheduler"), advisoryLockKey("job:cbr_rates"))
+	assert.NotEqual(t, migrationLockID, advisoryLockKey("scheduler"))
+}
+
+func TestAdvisoryLock_AcquireAndRelease(t *testing.T) {
+	ctx := context.Background()
+	lock, mock := setupAdvisoryLock(t, "scheduler")
+	defer mock.Close()
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(tryLockSQL)).
+		WithArgs(advisoryLockKey("scheduler")).
+		WillReturnRows(pgxmock.NewRows([]string{"acquired"}).AddRow(true))
+	mock.ExpectExec(regexp.QuoteMeta("SELECT 1")).WillReturnResult(pgconn.NewCommandTag("SELECT 1"))
+	mock.ExpectRollback()
+
+	acquired, err := lock.TryAcquire(ctx)
+	require.NoError(t, err)
+	assert.True(t, acquired)
+
+	// a second call only checks the held lock
+	acquired, err = lock.TryAcquire(ctx)
+	require.NoError(t, err)
+	assert.True(t, acquired)
+
+	require.NoError(t, lock.Release(ctx))
+	require.NoError(t, lock.Release(ctx))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAdvisoryLock_HeldElsewhere(t *testing.T) {
+	ctx := context.Background()
+	lock, mock := setupAdvisoryLock(t, "scheduler")
+	defer mock.Close()
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(tryLockSQL)).
+		WithArgs(advisoryLockKey("scheduler")).
+		WillReturnRows(pgxmock.NewRows([]string{"acquired"}).AddRow(false))
+	mock.ExpectRollback()
+
+	acquired, err := lock.TryAcquire(ctx)
+	require.NoError(t, err)
+	assert.False(t, acquired)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAdvisoryLock_ReacquiresAfterLostConnection(t *testing.T) {
+	ctx := context.Background()
+	lock, mock := setupAdvisoryLock(t, "scheduler")
+	defer mock.Close()
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(tryLockSQL)).
+		WithArgs(advisoryLockKey("scheduler")).
+		WillReturnRows(pgxmock.NewRows([]string{"acquired"}).AddRow(true))
+	mock.ExpectExec(regexp.QuoteMeta("SELECT 1")).WillReturnError(errors.New("conn closed"))
+	mock.ExpectRollback()
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(tryLockSQL)).
+		WithArgs(advisoryLockKey("scheduler")).
+		WillReturnRows(pgxmock.NewRows([]string{"acquired"}).AddRow(false))
+	mock.ExpectRollback()
+
+	acquired, err := lock.TryAcquire(ctx)
+	require.NoError(t, err)
+	require.True(t, acquired)
+
+	acquired, err = lock.TryAcquire(ctx)
+	require.NoError(t, err)
+	assert.False(t, acquired)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
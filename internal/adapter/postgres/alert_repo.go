@@ -0,0 +1,336 @@
+package postgres
+
+import (
+	"RnD-service/internal/entity"
+	"context"
+	"errors"
+	"fmt"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/jackc/pgx/v5"
+	"github.com/sirupsen/logrus"
+)
+
+var (
+	alertRuleColumns  = []string{"id", "source", "char_code", "threshold_type", "threshold", "direction", "webhook_url", "secret", "enabled", "created_at", "updated_at"}
+	alertColumns      = []string{"id", "rule_id", "source", "char_code", "date", "previous_date", "previous_value", "value", "change", "change_percent", "status", "attempts", "last_error", "triggered_at", "delivered_at"}
+	deadLetterColumns = []string{"id", "alert_id", "url", "payload", "attempts", "last_error", "created_at"}
+)
+
+func (r *PostgresRepo) CreateAlertRule(ctx context.Context, rule *entity.AlertRule) (int64, error) {
+	logger := r.logger.WithContext(ctx)
+	logger.WithFields(logrus.Fields{"source": rule.Source, "char_code": rule.CharCode}).Info("Creating alert rule")
+
+	query, args, err := psql.Insert("alert_rules").
+		Columns(alertRuleColumns[1:]...).
+		Values(rule.Source, rule.CharCode, rule.ThresholdType, rule.Threshold, rule.Direction, rule.WebhookURL, rule.Secret, rule.Enabled, rule.CreatedAt, rule.UpdatedAt).
+		Suffix("RETURNING id").
+		ToSql()
+	if err != nil {
+		logger.WithError(err).Error("Failed to build insert query for alert rule")
+		return 0, fmt.Errorf("build insert: %w", err)
+	}
+
+	var id int64
+	if err := r.pool.QueryRow(ctx, query, args...).Scan(&id); err != nil {
+		logger.WithError(err).Error("Failed to insert alert rule")
+		return 0, fmt.Errorf("insert alert rule: %w", err)
+	}
+
+	logger.WithField("rule_id", id).Info("Successfully created alert rule")
+	return id, nil
+}
+
+func (r *PostgresRepo) UpdateAlertRule(ctx context.Context, rule *entity.AlertRule) error {
+	logger := r.logger.WithContext(ctx)
+	query, args, err := psql.Update("alert_rules").
+		Set("source", rule.Source).
+		Set("char_code", rule.CharCode).
+		Set("threshold_type", rule.ThresholdType).
+		Set("threshold", rule.Threshold).
+		Set("direction", rule.Direction).
+		Set("webhook_url", rule.WebhookURL).
+		Set("secret", rule.Secret).
+		Set("enabled", rule.Enabled).
+		Set("updated_at", rule.UpdatedAt).
+		Where(sq.Eq{"id": rule.ID}).
+		ToSql()
+	if err != nil {
+		logger.WithError(err).Error("Failed to build update query for alert rule")
+		return fmt.Errorf("build update: %w", err)
+	}
+
+	ct, err := r.pool.Exec(ctx, query, args...)
+	if err != nil {
+		logger.WithError(err).WithField("rule_id", rule.ID).Error("Failed to update alert rule")
+		return fmt.Errorf("update alert rule: %w", err)
+	}
+	if ct.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+
+	logger.WithField("rule_id", rule.ID).Info("Successfully updated alert rule")
+	return nil
+}
+
+func (r *PostgresRepo) DeleteAlertRule(ctx context.Context, id int64) error {
+	logger := r.logger.WithContext(ctx)
+	query, args, err := psql.Delete("alert_rules").
+		Where(sq.Eq{"id": id}).
+		ToSql()
+	if err != nil {
+		logger.WithError(err).Error("Failed to build delete query for alert rule")
+		return fmt.Errorf("build delete: %w", err)
+	}
+
+	ct, err := r.pool.Exec(ctx, query, args...)
+	if err != nil {
+		logger.WithError(err).WithField("rule_id", id).Error("Failed to delete alert rule")
+		return fmt.Errorf("delete alert rule: %w", err)
+	}
+	if ct.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+
+	logger.WithField("rule_id", id).Info("Successfully deleted alert rule")
+	return nil
+}
+
+func (r *PostgresRepo) GetAlertRule(ctx context.Context, id int64) (*entity.AlertRule, error) {
+	logger := r.logger.WithContext(ctx)
+	query, args, err := psql.
+		Select(alertRuleColumns...).
+		From("alert_rules").
+		Where(sq.Eq{"id": id}).
+		ToSql()
+	if err != nil {
+		logger.WithError(err).Error("Failed to build select query for alert rule")
+		return nil, fmt.Errorf("build select: %w", err)
+	}
+
+	rule, err := scanAlertRule(r.pool.QueryRow(ctx, query, args...))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		logger.WithError(err).WithField("rule_id", id).Error("Failed to query alert rule")
+		return nil, fmt.Errorf("query scan: %w", err)
+	}
+	return rule, nil
+}
+
+func (r *PostgresRepo) ListAlertRules(ctx context.Context) ([]entity.AlertRule, error) {
+	return r.listAlertRules(ctx, psql.
+		Select(alertRuleColumns...).
+		From("alert_rules").
+		OrderBy("id ASC"))
+}
+
+func (r *PostgresRepo) ListEnabledAlertRules(ctx context.Context, source string) ([]entity.AlertRule, error) {
+	return r.listAlertRules(ctx, psql.
+		Select(alertRuleColumns...).
+		From("alert_rules").
+		Where(sq.Eq{"source": source, "enabled": true}).
+		OrderBy("id ASC"))
+}
+
+func (r *PostgresRepo) listAlertRules(ctx context.Context, builder sq.SelectBuilder) ([]entity.AlertRule, error) {
+	logger := r.logger.WithContext(ctx)
+	query, args, err := builder.ToSql()
+	if err != nil {
+		logger.WithError(err).Error("Failed to build select query for alert rules")
+		return nil, fmt.Errorf("build select: %w", err)
+	}
+
+	rows, err := r.pool.Query(ctx, query, args...)
+	if err != nil {
+		logger.WithError(err).Error("Failed to query alert rules")
+		return nil, fmt.Errorf("query alert rules: %w", err)
+	}
+	defer rows.Close()
+
+	var rules []entity.AlertRule
+	for rows.Next() {
+		rule, err := scanAlertRule(rows)
+		if err != nil {
+			logger.WithError(err).Error("Failed to scan alert rule row")
+			return nil, fmt.Errorf("scan row: %w", err)
+		}
+		rules = append(rules, *rule)
+	}
+	if err := rows.Err(); err != nil {
+		logger.WithError(err).Error("Failed to iterate alert rule rows")
+		return nil, fmt.Errorf("iterate rows: %w", err)
+	}
+	return rules, nil
+}
+
+func scanAlertRule(row pgx.Row) (*entity.AlertRule, error) {
+	var rule entity.AlertRule
+	if err := row.Scan(
+		&rule.ID,
+		&rule.Source,
+		&rule.CharCode,
+		&rule.ThresholdType,
+		&rule.Threshold,
+		&rule.Direction,
+		&rule.WebhookURL,
+		&rule.Secret,
+		&rule.Enabled,
+		&rule.CreatedAt,
+		&rule.UpdatedAt,
+	); err != nil {
+		return nil, err
+	}
+	return &rule, nil
+}
+
+// CreateAlert records alert once per rule and date; created is false when the
+// rule already fired for that date, e.g. when the same publication is stored again.
+func (r *PostgresRepo) CreateAlert(ctx context.Context, alert *entity.Alert) (id int64, created bool, err error) {
+	logger := r.logger.WithContext(ctx)
+	query, args, err := psql.Insert("alerts").
+		Columns(alertColumns[1:]...).
+		Values(alert.RuleID, alert.Source, alert.CharCode, alert.Date, alert.PreviousDate, alert.PreviousValue, alert.Value, alert.Change, alert.ChangePercent, alert.Status, alert.Attempts, alert.LastError, alert.TriggeredAt, alert.DeliveredAt).
+		Suffix("ON CONFLICT (rule_id, date) DO NOTHING RETURNING id").
+		ToSql()
+	if err != nil {
+		logger.WithError(err).Error("Failed to build insert query for alert")
+		return 0, false, fmt.Errorf("build insert: %w", err)
+	}
+
+	if err := r.pool.QueryRow(ctx, query, args...).Scan(&id); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			logger.WithFields(logrus.Fields{"rule_id": alert.RuleID, "date": alert.Date.Format("2006-01-02")}).Debug("Alert already recorded")
+			return 0, false, nil
+		}
+		logger.WithError(err).Error("Failed to insert alert")
+		return 0, false, fmt.Errorf("insert alert: %w", err)
+	}
+	return id, true, nil
+}
+
+func (r *PostgresRepo) UpdateAlertDelivery(ctx context.Context, alert *entity.Alert) error {
+	logger := r.logger.WithContext(ctx)
+	query, args, err := psql.Update("alerts").
+		Set("status", alert.Status).
+		Set("attempts", alert.Attempts).
+		Set("last_error", alert.LastError).
+		Set("delivered_at", alert.DeliveredAt).
+		Where(sq.Eq{"id": alert.ID}).
+		ToSql()
+	if err != nil {
+		logger.WithError(err).Error("Failed to build update query for alert")
+		return fmt.Errorf("build update: %w", err)
+	}
+
+	ct, err := r.pool.Exec(ctx, query, args...)
+	if err != nil {
+		logger.WithError(err).WithField("alert_id", alert.ID).Error("Failed to update alert delivery")
+		return fmt.Errorf("update alert delivery: %w", err)
+	}
+	if ct.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (r *PostgresRepo) ListAlerts(ctx context.Context, dateFrom, dateTo string) ([]entity.Alert, error) {
+	logger := r.logger.WithContext(ctx)
+	fields := logrus.Fields{"from": dateFrom, "to": dateTo}
+	logger.WithFields(fields).Info("Getting alerts by date range")
+
+	query, args, err := psql.
+		Select(alertColumns...).
+		From("alerts").
+		Where(sq.GtOrEq{"date": dateFrom}).
+		Where(sq.LtOrEq{"date": dateTo}).
+		OrderBy("date ASC", "id ASC").
+		ToSql()
+	if err != nil {
+		logger.WithError(err).Error("Failed to build select query for alerts")
+		return nil, fmt.Errorf("build select: %w", err)
+	}
+
+	rows, err := r.pool.Query(ctx, query, args...)
+	if err != nil {
+		logger.WithError(err).WithFields(fields).Error("Failed to query alerts")
+		return nil, fmt.Errorf("query alerts: %w", err)
+	}
+	defer rows.Close()
+
+	var alerts []entity.Alert
+	for rows.Next() {
+		var a entity.Alert
+		if err := rows.Scan(&a.ID, &a.RuleID, &a.Source, &a.CharCode, &a.Date, &a.PreviousDate, &a.PreviousValue, &a.Value, &a.Change, &a.ChangePercent, &a.Status, &a.Attempts, &a.LastError, &a.TriggeredAt, &a.DeliveredAt); err != nil {
+			logger.WithError(err).Error("Failed to scan alert row")
+			return nil, fmt.Errorf("scan row: %w", err)
+		}
+		alerts = append(alerts, a)
+	}
+	if err := rows.Err(); err != nil {
+		logger.WithError(err).Error("Failed to iterate alert rows")
+		return nil, fmt.Errorf("iterate rows: %w", err)
+	}
+
+	logger.WithFields(fields).Infof("Successfully retrieved %d alerts", len(alerts))
+	return alerts, nil
+}
+
+func (r *PostgresRepo) StoreDeadLetter(ctx context.Context, letter *entity.WebhookDeadLetter) error {
+	logger := r.logger.WithContext(ctx)
+	query, args, err := psql.Insert("webhook_dead_letters").
+		Columns(deadLetterColumns[1:]...).
+		Values(letter.AlertID, letter.URL, string(letter.Payload), letter.Attempts, letter.LastError, letter.CreatedAt).
+		ToSql()
+	if err != nil {
+		logger.WithError(err).Error("Failed to build insert query for webhook dead letter")
+		return fmt.Errorf("build insert: %w", err)
+	}
+
+	if _, err := r.pool.Exec(ctx, query, args...); err != nil {
+		logger.WithError(err).WithField("alert_id", letter.AlertID).Error("Failed to insert webhook dead letter")
+		return fmt.Errorf("insert webhook dead letter: %w", err)
+	}
+
+	logger.WithField("alert_id", letter.AlertID).Warn("Stored undeliverable webhook in dead letters")
+	return nil
+}
+
+func (r *PostgresRepo) ListDeadLetters(ctx context.Context, limit uint64) ([]entity.WebhookDeadLetter, error) {
+	logger := r.logger.WithContext(ctx)
+	query, args, err := psql.
+		Select(deadLetterColumns...).
+		From("webhook_dead_letters").
+		OrderBy("id DESC").
+		Limit(limit).
+		ToSql()
+	if err != nil {
+		logger.WithError(err).Error("Failed to build select query for webhook dead letters")
+		return nil, fmt.Errorf("build select: %w", err)
+	}
+
+	rows, err := r.pool.Query(ctx, query, args...)
+	if err != nil {
+		logger.WithError(err).Error("Failed to query webhook dead letters")
+		return nil, fmt.Errorf("query webhook dead letters: %w", err)
+	}
+	defer rows.Close()
+
+	var letters []entity.WebhookDeadLetter
+	for rows.Next() {
+		var l entity.WebhookDeadLetter
+		var payload string
+		if err := rows.Scan(&l.ID, &l.AlertID, &l.URL, &payload, &l.Attempts, &l.LastError, &l.CreatedAt); err != nil {
+			logger.WithError(err).Error("Failed to scan webhook dead letter row")
+			return nil, fmt.Errorf("scan row: %w", err)
+		}
+		l.Payload = []byte(payload)
+		letters = append(letters, l)
+	}
+	if err := rows.Err(); err != nil {
+		logger.WithError(err).Error("Failed to iterate webhook dead letter rows")
+		return nil, fmt.Errorf("iterate rows: %w", err)
+	}
+	return letters, nil
+}
@@ -0,0 +1,182 @@
+package postgres
+
+import (
+	"context"
+	"regexp"
+	"testing"
+	"time"
+
+	"RnD-service/internal/entity"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/jackc/pgx/v5/pgconn"
+	pgxmock "github.com/pashagolub/pgxmock/v4"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func sampleAlertRule() *entity.AlertRule {
+	now := time.Date(2025, 8, 1, 12, 0, 0, 0, time.UTC)
+	return &entity.AlertRule{
+		Source:        "cbr",
+		CharCode:      "USD",
+		ThresholdType: entity.AlertThresholdPercent,
+		Threshold:     decimal.RequireFromString("0.5"),
+		Direction:     entity.AlertDirectionBoth,
+		WebhookURL:    "https://hooks.example.com/rates",
+		Secret:        "s3cret",
+		Enabled:       true,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}
+}
+
+func TestCreateAlertRule(t *testing.T) {
+	ctx := context.Background()
+	repo, mock := setupTestRepo(t)
+	defer mock.Close()
+
+	rule := sampleAlertRule()
+	query, args, err := psql.Insert("alert_rules").
+		Columns(alertRuleColumns[1:]...).
+		Values(rule.Source, rule.CharCode, rule.ThresholdType, rule.Threshold, rule.Direction, rule.WebhookURL, rule.Secret, rule.Enabled, rule.CreatedAt, rule.UpdatedAt).
+		Suffix("RETURNING id").
+		ToSql()
+	require.NoError(t, err)
+
+	mock.ExpectQuery(regexp.QuoteMeta(query)).
+		WithArgs(args...).
+		WillReturnRows(pgxmock.NewRows([]string{"id"}).AddRow(int64(4)))
+
+	id, err := repo.CreateAlertRule(ctx, rule)
+	require.NoError(t, err)
+	assert.Equal(t, int64(4), id)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDeleteAlertRule_NotFound(t *testing.T) {
+	ctx := context.Background()
+	repo, mock := setupTestRepo(t)
+	defer mock.Close()
+
+	query, args, err := psql.Delete("alert_rules").Where(squirrel.Eq{"id": int64(4)}).ToSql()
+	require.NoError(t, err)
+
+	mock.ExpectExec(regexp.QuoteMeta(query)).
+		WithArgs(args...).
+		WillReturnResult(pgconn.NewCommandTag("DELETE 0"))
+
+	assert.ErrorIs(t, repo.DeleteAlertRule(ctx, 4), ErrNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestListEnabledAlertRules(t *testing.T) {
+	ctx := context.Background()
+	repo, mock := setupTestRepo(t)
+	defer mock.Close()
+
+	query, args, err := psql.
+		Select(alertRuleColumns...).
+		From("alert_rules").
+		Where(squirrel.Eq{"source": "cbr", "enabled": true}).
+		OrderBy("id ASC").
+		ToSql()
+	require.NoError(t, err)
+
+	rule := sampleAlertRule()
+	mock.ExpectQuery(regexp.QuoteMeta(query)).
+		WithArgs(args...).
+		WillReturnRows(pgxmock.NewRows(alertRuleColumns).
+			AddRow(int64(4), rule.Source, rule.CharCode, rule.ThresholdType, "0.5", rule.Direction, rule.WebhookURL, rule.Secret, true, rule.CreatedAt, rule.UpdatedAt))
+
+	rules, err := repo.ListEnabledAlertRules(ctx, "cbr")
+	require.NoError(t, err)
+	require.Len(t, rules, 1)
+	assert.Equal(t, int64(4), rules[0].ID)
+	assert.Equal(t, "0.5", rules[0].Threshold.String())
+	assert.Equal(t, "s3cret", rules[0].Secret)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func sampleAlert() *entity.Alert {
+	return &entity.Alert{
+		RuleID:        4,
+		Source:        "cbr",
+		CharCode:      "USD",
+		Date:          time.Date(2025, 8, 5, 0, 0, 0, 0, time.UTC),
+		PreviousDate:  time.Date(2025, 8, 2, 0, 0, 0, 0, time.UTC),
+		PreviousValue: decimal.RequireFromString("79.7653"),
+		Value:         decimal.RequireFromString("80.0613"),
+		Change:        decimal.RequireFromString("0.296"),
+		ChangePercent: decimal.RequireFromString("0.3711"),
+		Status:        entity.AlertPending,
+		TriggeredAt:   time.Date(2025, 8, 5, 12, 0, 0, 0, time.UTC),
+	}
+}
+
+func expectAlertInsert(t *testing.T, mock pgxmock.PgxPoolIface, a *entity.Alert) *pgxmock.ExpectedQuery {
+	query, args, err := psql.Insert("alerts").
+		Columns(alertColumns[1:]...).
+		Values(a.RuleID, a.Source, a.CharCode, a.Date, a.PreviousDate, a.PreviousValue, a.Value, a.Change, a.ChangePercent, a.Status, a.Attempts, a.LastError, a.TriggeredAt, a.DeliveredAt).
+		Suffix("ON CONFLICT (rule_id, date) DO NOTHING RETURNING id").
+		ToSql()
+	require.NoError(t, err)
+	return mock.ExpectQuery(regexp.QuoteMeta(query)).WithArgs(args...)
+}
+
+func TestCreateAlert(t *testing.T) {
+	ctx := context.Background()
+	repo, mock := setupTestRepo(t)
+	defer mock.Close()
+
+	alert := sampleAlert()
+	expectAlertInsert(t, mock, alert).WillReturnRows(pgxmock.NewRows([]string{"id"}).AddRow(int64(11)))
+
+	id, created, err := repo.CreateAlert(ctx, alert)
+	require.NoError(t, err)
+	assert.True(t, created)
+	assert.Equal(t, int64(11), id)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCreateAlert_AlreadyRecorded(t *testing.T) {
+	ctx := context.Background()
+	repo, mock := setupTestRepo(t)
+	defer mock.Close()
+
+	alert := sampleAlert()
+	expectAlertInsert(t, mock, alert).WillReturnRows(pgxmock.NewRows([]string{"id"}))
+
+	_, created, err := repo.CreateAlert(ctx, alert)
+	require.NoError(t, err)
+	assert.False(t, created)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestStoreDeadLetter(t *testing.T) {
+	ctx := context.Background()
+	repo, mock := setupTestRepo(t)
+	defer mock.Close()
+
+	letter := &entity.WebhookDeadLetter{
+		AlertID:   11,
+		URL:       "https://hooks.example.com/rates",
+		Payload:   []byte(`{"id":11}`),
+		Attempts:  5,
+		LastError: "webhook https://hooks.example.com/rates answered 503",
+		CreatedAt: time.Date(2025, 8, 5, 12, 5, 0, 0, time.UTC),
+	}
+	query, args, err := psql.Insert("webhook_dead_letters").
+		Columns(deadLetterColumns[1:]...).
+		Values(letter.AlertID, letter.URL, `{"id":11}`, letter.Attempts, letter.LastError, letter.CreatedAt).
+		ToSql()
+	require.NoError(t, err)
+
+	mock.ExpectExec(regexp.QuoteMeta(query)).
+		WithArgs(args...).
+		WillReturnResult(pgconn.NewCommandTag("INSERT 0 1"))
+
+	assert.NoError(t, repo.StoreDeadLetter(ctx, letter))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
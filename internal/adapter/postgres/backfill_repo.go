@@ -0,0 +1,165 @@
+package postgres
+
+import (
+	"RnD-service/internal/entity"
+	"context"
+	"errors"
+	"fmt"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/jackc/pgx/v5"
+	"github.com/sirupsen/logrus"
+)
+
+var backfillColumns = []string{"id", "date_from", "date_to", "char_codes", "status", "next_date", "days_total", "days_done", "error", "created_at", "updated_at", "finished_at"}
+
+func (r *PostgresRepo) CreateBackfillJob(ctx context.Context, job *entity.BackfillJob) (int64, error) {
+	logger := r.logger.WithContext(ctx)
+	logger.WithFields(logrus.Fields{"from": job.DateFrom.Format("2006-01-02"), "to": job.DateTo.Format("2006-01-02")}).Info("Creating backfill job")
+
+	query, args, err := psql.Insert("backfill_jobs").
+		Columns("date_from", "date_to", "char_codes", "status", "next_date", "days_total", "days_done", "error", "created_at", "updated_at").
+		Values(job.DateFrom, job.DateTo, nonNilCodes(job.CharCodes), job.Status, job.NextDate, job.DaysTotal, job.DaysDone, job.Error, job.CreatedAt, job.UpdatedAt).
+		Suffix("RETURNING id").
+		ToSql()
+	if err != nil {
+		logger.WithError(err).Error("Failed to build insert query for backfill job")
+		return 0, fmt.Errorf("build insert: %w", err)
+	}
+
+	var id int64
+	if err := r.pool.QueryRow(ctx, query, args...).Scan(&id); err != nil {
+		logger.WithError(err).Error("Failed to insert backfill job")
+		return 0, fmt.Errorf("insert backfill job: %w", err)
+	}
+
+	logger.WithField("job_id", id).Info("Successfully created backfill job")
+	return id, nil
+}
+
+func (r *PostgresRepo) UpdateBackfillJob(ctx context.Context, job *entity.BackfillJob) error {
+	logger := r.logger.WithContext(ctx)
+	query, args, err := psql.Update("backfill_jobs").
+		Set("status", job.Status).
+		Set("next_date", job.NextDate).
+		Set("days_done", job.DaysDone).
+		Set("error", job.Error).
+		Set("updated_at", job.UpdatedAt).
+		Set("finished_at", job.FinishedAt).
+		Where(sq.Eq{"id": job.ID}).
+		ToSql()
+	if err != nil {
+		logger.WithError(err).Error("Failed to build update query for backfill job")
+		return fmt.Errorf("build update: %w", err)
+	}
+
+	ct, err := r.pool.Exec(ctx, query, args...)
+	if err != nil {
+		logger.WithError(err).WithField("job_id", job.ID).Error("Failed to update backfill job")
+		return fmt.Errorf("update backfill job: %w", err)
+	}
+	if ct.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+
+	logger.WithFields(logrus.Fields{"job_id": job.ID, "status": job.Status, "days_done": job.DaysDone}).Debug("Updated backfill job progress")
+	return nil
+}
+
+func (r *PostgresRepo) GetBackfillJob(ctx context.Context, id int64) (*entity.BackfillJob, error) {
+	logger := r.logger.WithContext(ctx)
+	query, args, err := psql.
+		Select(backfillColumns...).
+		From("backfill_jobs").
+		Where(sq.Eq{"id": id}).
+		ToSql()
+	if err != nil {
+		logger.WithError(err).Error("Failed to build select query for backfill job")
+		return nil, fmt.Errorf("build select: %w", err)
+	}
+
+	job, err := scanBackfillJob(r.pool.QueryRow(ctx, query, args...))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		logger.WithError(err).WithField("job_id", id).Error("Failed to query backfill job")
+		return nil, fmt.Errorf("query scan: %w", err)
+	}
+	return job, nil
+}
+
+func (r *PostgresRepo) ListBackfillJobs(ctx context.Context, limit uint64) ([]entity.BackfillJob, error) {
+	return r.listBackfillJobs(ctx, psql.
+		Select(backfillColumns...).
+		From("backfill_jobs").
+		OrderBy("id DESC").
+		Limit(limit))
+}
+
+func (r *PostgresRepo) ListUnfinishedBackfillJobs(ctx context.Context) ([]entity.BackfillJob, error) {
+	return r.listBackfillJobs(ctx, psql.
+		Select(backfillColumns...).
+		From("backfill_jobs").
+		Where(sq.Eq{"status": []string{entity.BackfillPending, entity.BackfillRunning}}).
+		OrderBy("id ASC"))
+}
+
+func (r *PostgresRepo) listBackfillJobs(ctx context.Context, builder sq.SelectBuilder) ([]entity.BackfillJob, error) {
+	logger := r.logger.WithContext(ctx)
+	query, args, err := builder.ToSql()
+	if err != nil {
+		logger.WithError(err).Error("Failed to build select query for backfill jobs")
+		return nil, fmt.Errorf("build select: %w", err)
+	}
+
+	rows, err := r.pool.Query(ctx, query, args...)
+	if err != nil {
+		logger.WithError(err).Error("Failed to query backfill jobs")
+		return nil, fmt.Errorf("query backfill jobs: %w", err)
+	}
+	defer rows.Close()
+
+	var jobs []entity.BackfillJob
+	for rows.Next() {
+		job, err := scanBackfillJob(rows)
+		if err != nil {
+			logger.WithError(err).Error("Failed to scan backfill job row")
+			return nil, fmt.Errorf("scan row: %w", err)
+		}
+		jobs = append(jobs, *job)
+	}
+	if err := rows.Err(); err != nil {
+		logger.WithError(err).Error("Failed to iterate backfill job rows")
+		return nil, fmt.Errorf("iterate rows: %w", err)
+	}
+	return jobs, nil
+}
+
+func scanBackfillJob(row pgx.Row) (*entity.BackfillJob, error) {
+	var job entity.BackfillJob
+	if err := row.Scan(
+		&job.ID,
+		&job.DateFrom,
+		&job.DateTo,
+		&job.CharCodes,
+		&job.Status,
+		&job.NextDate,
+		&job.DaysTotal,
+		&job.DaysDone,
+		&job.Error,
+		&job.CreatedAt,
+		&job.UpdatedAt,
+		&job.FinishedAt,
+	); err != nil {
+		return nil, err
+	}
+	return &job, nil
+}
+
+func nonNilCodes(codes []string) []string {
+	if codes == nil {
+		return []string{}
+	}
+	return codes
+}
@@ -0,0 +1,199 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"RnD-service/internal/entity"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	pgxmock "github.com/pashagolub/pgxmock/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateBackfillJob(t *testing.T) {
+	ctx := context.Background()
+	repo, mock := setupTestRepo(t)
+	defer mock.Close()
+
+	now := time.Now().UTC()
+	from := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2023, 1, 31, 0, 0, 0, 0, time.UTC)
+	job := &entity.BackfillJob{
+		DateFrom:  from,
+		DateTo:    to,
+		Status:    entity.BackfillPending,
+		NextDate:  from,
+		DaysTotal: 31,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+
+	query, args, err := psql.Insert("backfill_jobs").
+		Columns("date_from", "date_to", "char_codes", "status", "next_date", "days_total", "days_done", "error", "created_at", "updated_at").
+		Values(from, to, []string{}, entity.BackfillPending, from, 31, 0, "", now, now).
+		Suffix("RETURNING id").
+		ToSql()
+	require.NoError(t, err)
+
+	mock.ExpectQuery(regexp.QuoteMeta(query)).
+		WithArgs(args...).
+		WillReturnRows(pgxmock.NewRows([]string{"id"}).AddRow(int64(7)))
+
+	id, err := repo.CreateBackfillJob(ctx, job)
+	require.NoError(t, err)
+	assert.Equal(t, int64(7), id)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUpdateBackfillJob(t *testing.T) {
+	ctx := context.Background()
+	repo, mock := setupTestRepo(t)
+	defer mock.Close()
+
+	now := time.Now().UTC()
+	job := &entity.BackfillJob{
+		ID:         7,
+		Status:     entity.BackfillCompleted,
+		NextDate:   time.Date(2023, 2, 1, 0, 0, 0, 0, time.UTC),
+		DaysDone:   31,
+		UpdatedAt:  now,
+		FinishedAt: &now,
+	}
+
+	query, args, err := psql.Update("backfill_jobs").
+		Set("status", job.Status).
+		Set("next_date", job.NextDate).
+		Set("days_done", job.DaysDone).
+		Set("error", job.Error).
+		Set("updated_at", job.UpdatedAt).
+		Set("finished_at", job.FinishedAt).
+		Where(squirrel.Eq{"id": job.ID}).
+		ToSql()
+	require.NoError(t, err)
+
+	mock.ExpectExec(regexp.QuoteMeta(query)).
+		WithArgs(args...).
+		WillReturnResult(pgconn.NewCommandTag("UPDATE 1"))
+
+	err = repo.UpdateBackfillJob(ctx, job)
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUpdateBackfillJob_NotFound(t *testing.T) {
+	ctx := context.Background()
+	repo, mock := setupTestRepo(t)
+	defer mock.Close()
+
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE backfill_jobs")).
+		WithArgs(pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), int64(42)).
+		WillReturnResult(pgconn.NewCommandTag("UPDATE 0"))
+
+	err := repo.UpdateBackfillJob(ctx, &entity.BackfillJob{ID: 42})
+	assert.Equal(t, ErrNotFound, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetBackfillJob(t *testing.T) {
+	ctx := context.Background()
+	repo, mock := setupTestRepo(t)
+	defer mock.Close()
+
+	now := time.Now().UTC()
+	from := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2023, 1, 31, 0, 0, 0, 0, time.UTC)
+
+	query, args, err := psql.
+		Select(backfillColumns...).
+		From("backfill_jobs").
+		Where(squirrel.Eq{"id": int64(7)}).
+		ToSql()
+	require.NoError(t, err)
+
+	mock.ExpectQuery(regexp.QuoteMeta(query)).
+		WithArgs(args...).
+		WillReturnRows(pgxmock.NewRows(backfillColumns).
+			AddRow(int64(7), from, to, []string{"USD"}, entity.BackfillRunning, from.AddDate(0, 0, 10), 31, 10, "", now, now, (*time.Time)(nil)))
+
+	job, err := repo.GetBackfillJob(ctx, 7)
+	require.NoError(t, err)
+	assert.Equal(t, &entity.BackfillJob{
+		ID:        7,
+		DateFrom:  from,
+		DateTo:    to,
+		CharCodes: []string{"USD"},
+		Status:    entity.BackfillRunning,
+		NextDate:  from.AddDate(0, 0, 10),
+		DaysTotal: 31,
+		DaysDone:  10,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}, job)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetBackfillJob_NotFound(t *testing.T) {
+	ctx := context.Background()
+	repo, mock := setupTestRepo(t)
+	defer mock.Close()
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT")).
+		WithArgs(int64(7)).
+		WillReturnError(pgx.ErrNoRows)
+
+	job, err := repo.GetBackfillJob(ctx, 7)
+	assert.Nil(t, job)
+	assert.Equal(t, ErrNotFound, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestListUnfinishedBackfillJobs(t *testing.T) {
+	ctx := context.Background()
+	repo, mock := setupTestRepo(t)
+	defer mock.Close()
+
+	now := time.Now().UTC()
+	from := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	query, args, err := psql.
+		Select(backfillColumns...).
+		From("backfill_jobs").
+		Where(squirrel.Eq{"status": []string{entity.BackfillPending, entity.BackfillRunning}}).
+		OrderBy("id ASC").
+		ToSql()
+	require.NoError(t, err)
+
+	mock.ExpectQuery(regexp.QuoteMeta(query)).
+		WithArgs(args...).
+		WillReturnRows(pgxmock.NewRows(backfillColumns).
+			AddRow(int64(1), from, from, []string{}, entity.BackfillPending, from, 1, 0, "", now, now, (*time.Time)(nil)).
+			AddRow(int64(2), from, from, []string{}, entity.BackfillRunning, from, 1, 0, "", now, now, (*time.Time)(nil)))
+
+	jobs, err := repo.ListUnfinishedBackfillJobs(ctx)
+	require.NoError(t, err)
+	require.Len(t, jobs, 2)
+	assert.Equal(t, int64(2), jobs[1].ID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestListBackfillJobs_Error(t *testing.T) {
+	ctx := context.Background()
+	repo, mock := setupTestRepo(t)
+	defer mock.Close()
+
+	expectedErr := errors.New("database error")
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT")).
+		WillReturnError(expectedErr)
+
+	jobs, err := repo.ListBackfillJobs(ctx, 20)
+	assert.Nil(t, jobs)
+	assert.ErrorContains(t, err, expectedErr.Error())
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -6,12 +6,30 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/multitracer"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/sirupsen/logrus"
 )
 
-func InitDBPool(cfg config.Config, logger *logrus.Logger) (*pgxpool.Pool, error) {
+// PoolOption adjusts the pool config before the pool is created.
+type PoolOption func(*pgxpool.Config)
+
+// WithQueryTracer traces every query on the pool's connections; tracers from
+// several options are all called.
+func WithQueryTracer(tracer pgx.QueryTracer) PoolOption {
+	return func(c *pgxpool.Config) {
+		if c.ConnConfig.Tracer != nil {
+			c.ConnConfig.Tracer = multitracer.New(c.ConnConfig.Tracer, tracer)
+			return
+		}
+		c.ConnConfig.Tracer = tracer
+	}
+}
+
+func InitDBPool(cfg config.Config, logger *logrus.Logger, opts ...PoolOption) (*pgxpool.Pool, error) {
 	dsn := BuildDSN(cfg)
+	logger.Infof("Connecting to %s", RedactDSN(dsn))
 
 	poolConfig, err := pgxpool.ParseConfig(dsn)
 	if err != nil {
@@ -22,6 +40,9 @@ func InitDBPool(cfg config.Config, logger *logrus.Logger) (*pgxpool.Pool, error)
 	poolConfig.MinConns = 5
 	poolConfig.MaxConnLifetime = 5 * time.Minute
 	poolConfig.HealthCheckPeriod = time.Minute
+	for _, opt := range opts {
+		opt(poolConfig)
+	}
 
 	const maxRetries = 5
 	var pool *pgxpool.Pool
@@ -0,0 +1,37 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type countingTracer struct {
+	started int
+}
+
+func (t *countingTracer) TraceQueryStart(ctx context.Context, _ *pgx.Conn, _ pgx.TraceQueryStartData) context.Context {
+	t.started++
+	return ctx
+}
+
+func (t *countingTracer) TraceQueryEnd(context.Context, *pgx.Conn, pgx.TraceQueryEndData) {}
+
+func TestWithQueryTracer_CombinesTracers(t *testing.T) {
+	poolConfig, err := pgxpool.ParseConfig("postgres://user@localhost:5432/db")
+	require.NoError(t, err)
+
+	first, second := &countingTracer{}, &countingTracer{}
+	WithQueryTracer(first)(poolConfig)
+	assert.Same(t, first, poolConfig.ConnConfig.Tracer)
+
+	WithQueryTracer(second)(poolConfig)
+	poolConfig.ConnConfig.Tracer.TraceQueryStart(context.Background(), nil, pgx.TraceQueryStartData{SQL: "SELECT 1"})
+
+	assert.Equal(t, 1, first.started)
+	assert.Equal(t, 1, second.started)
+}
@@ -0,0 +1,86 @@
+package postgres
+
+import (
+	"RnD-service/internal/entity"
+	"context"
+	"fmt"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/jackc/pgx/v5"
+	"github.com/sirupsen/logrus"
+)
+
+var discrepancyColumns = []string{"date", "char_code", "primary_source", "secondary_source", "base", "primary_value", "secondary_value", "diff_percent", "detected_at"}
+
+// StoreDiscrepancies upserts, so reconciling the same date twice keeps the latest values.
+func (r *PostgresRepo) StoreDiscrepancies(ctx context.Context, discrepancies []entity.RateDiscrepancy) error {
+	r.logger.WithContext(ctx).Infof("Start storing %d rate discrepancies", len(discrepancies))
+
+	if len(discrepancies) == 0 {
+		return nil
+	}
+
+	batch := &pgx.Batch{}
+	for _, d := range discrepancies {
+		query, args, err := psql.Insert("rate_discrepancies").
+			Columns(discrepancyColumns...).
+			Values(d.Date, d.CharCode, d.PrimarySource, d.SecondarySource, d.Base, d.PrimaryValue, d.SecondaryValue, d.DiffPercent, d.DetectedAt).
+			Suffix(`
+                ON CONFLICT (date, char_code, primary_source, secondary_source) DO UPDATE SET
+                    base = EXCLUDED.base,
+                    primary_value = EXCLUDED.primary_value,
+                    secondary_value = EXCLUDED.secondary_value,
+                    diff_percent = EXCLUDED.diff_percent,
+                    detected_at = EXCLUDED.detected_at
+            `).
+			ToSql()
+		if err != nil {
+			return fmt.Errorf("build insert for %s discrepancy on %s: %w", d.CharCode, d.Date.Format("2006-01-02"), err)
+		}
+		batch.Queue(query, args...)
+	}
+
+	return r.storeBatch(ctx, "rate discrepancies", batch)
+}
+
+func (r *PostgresRepo) ListDiscrepancies(ctx context.Context, dateFrom, dateTo string) ([]entity.RateDiscrepancy, error) {
+	logger := r.logger.WithContext(ctx)
+	fields := logrus.Fields{"from": dateFrom, "to": dateTo}
+	logger.WithFields(fields).Info("Getting rate discrepancies by date range")
+
+	query, args, err := psql.
+		Select(discrepancyColumns...).
+		From("rate_discrepancies").
+		Where(sq.GtOrEq{"date": dateFrom}).
+		Where(sq.LtOrEq{"date": dateTo}).
+		OrderBy("date ASC", "char_code ASC").
+		ToSql()
+	if err != nil {
+		logger.WithError(err).Error("Failed to build select query for rate discrepancies")
+		return nil, fmt.Errorf("build select: %w", err)
+	}
+
+	rows, err := r.pool.Query(ctx, query, args...)
+	if err != nil {
+		logger.WithError(err).WithFields(fields).Error("Failed to query rate discrepancies")
+		return nil, fmt.Errorf("query rate discrepancies: %w", err)
+	}
+	defer rows.Close()
+
+	var discrepancies []entity.RateDiscrepancy
+	for rows.Next() {
+		var d entity.RateDiscrepancy
+		if err := rows.Scan(&d.Date, &d.CharCode, &d.PrimarySource, &d.SecondarySource, &d.Base, &d.PrimaryValue, &d.SecondaryValue, &d.DiffPercent, &d.DetectedAt); err != nil {
+			logger.WithError(err).Error("Failed to scan rate discrepancy row")
+			return nil, fmt.Errorf("scan row: %w", err)
+		}
+		discrepancies = append(discrepancies, d)
+	}
+	if err := rows.Err(); err != nil {
+		logger.WithError(err).Error("Failed to iterate rate discrepancy rows")
+		return nil, fmt.Errorf("iterate rows: %w", err)
+	}
+
+	logger.WithFields(fields).Infof("Successfully retrieved %d rate discrepancies", len(discrepancies))
+	return discrepancies, nil
+}
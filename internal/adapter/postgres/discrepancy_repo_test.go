@@ -0,0 +1,87 @@
+package postgres
+
+import (
+	"context"
+	"regexp"
+	"testing"
+	"time"
+
+	"RnD-service/internal/entity"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/jackc/pgx/v5/pgconn"
+	pgxmock "github.com/pashagolub/pgxmock/v4"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const discrepancyUpsert = `
+                ON CONFLICT (date, char_code, primary_source, secondary_source) DO UPDATE SET
+                    base = EXCLUDED.base,
+                    primary_value = EXCLUDED.primary_value,
+                    secondary_value = EXCLUDED.secondary_value,
+                    diff_percent = EXCLUDED.diff_percent,
+                    detected_at = EXCLUDED.detected_at
+            `
+
+func TestStoreDiscrepancies(t *testing.T) {
+	ctx := context.Background()
+	repo, mock := setupTestRepo(t)
+	defer mock.Close()
+
+	d := entity.RateDiscrepancy{
+		Date:            time.Date(2025, 8, 1, 0, 0, 0, 0, time.UTC),
+		CharCode:        "USD",
+		PrimarySource:   "cbr",
+		SecondarySource: "ecb",
+		Base:            "EUR",
+		PrimaryValue:    decimal.RequireFromString("0.86"),
+		SecondaryValue:  decimal.RequireFromString("0.8769"),
+		DiffPercent:     decimal.RequireFromString("1.9272"),
+		DetectedAt:      time.Now().UTC(),
+	}
+	query, args, err := psql.Insert("rate_discrepancies").
+		Columns(discrepancyColumns...).
+		Values(d.Date, d.CharCode, d.PrimarySource, d.SecondarySource, d.Base, d.PrimaryValue, d.SecondaryValue, d.DiffPercent, d.DetectedAt).
+		Suffix(discrepancyUpsert).
+		ToSql()
+	require.NoError(t, err)
+
+	mock.ExpectBegin()
+	mock.ExpectBatch().ExpectExec(regexp.QuoteMeta(query)).
+		WithArgs(args...).
+		WillReturnResult(pgconn.NewCommandTag("INSERT 0 1"))
+	mock.ExpectCommit()
+
+	assert.NoError(t, repo.StoreDiscrepancies(ctx, []entity.RateDiscrepancy{d}))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestListDiscrepancies(t *testing.T) {
+	ctx := context.Background()
+	repo, mock := setupTestRepo(t)
+	defer mock.Close()
+
+	query, args, err := psql.
+		Select(discrepancyColumns...).
+		From("rate_discrepancies").
+		Where(squirrel.GtOrEq{"date": "2025-08-01"}).
+		Where(squirrel.LtOrEq{"date": "2025-08-04"}).
+		OrderBy("date ASC", "char_code ASC").
+		ToSql()
+	require.NoError(t, err)
+
+	now := time.Now().UTC()
+	mock.ExpectQuery(regexp.QuoteMeta(query)).
+		WithArgs(args...).
+		WillReturnRows(pgxmock.NewRows(discrepancyColumns).
+			AddRow(time.Date(2025, 8, 1, 0, 0, 0, 0, time.UTC), "USD", "cbr", "ecb", "EUR", "0.86", "0.8769", "1.9272", now))
+
+	discrepancies, err := repo.ListDiscrepancies(ctx, "2025-08-01", "2025-08-04")
+	require.NoError(t, err)
+	require.Len(t, discrepancies, 1)
+	assert.Equal(t, "ecb", discrepancies[0].SecondarySource)
+	assert.Equal(t, "1.9272", discrepancies[0].DiffPercent.String())
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
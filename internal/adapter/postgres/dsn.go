@@ -3,6 +3,7 @@ package postgres
 import (
 	"RnD-service/pkg/config"
 	"fmt"
+	"net/url"
 )
 
 func BuildDSN(cfg config.Config) string {
@@ -16,3 +17,12 @@ func BuildDSN(cfg config.Config) string {
 		cfg.Postgres.SSLMode,
 	)
 }
+
+// RedactDSN masks the password, so the DSN can be logged.
+func RedactDSN(dsn string) string {
+	u, err := url.Parse(dsn)
+	if err != nil {
+		return "<unparsable dsn>"
+	}
+	return u.Redacted()
+}
@@ -0,0 +1,40 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+)
+
+func (r *PostgresRepo) Ping(ctx context.Context) error {
+	if err := r.pool.Ping(ctx); err != nil {
+		r.logger.WithContext(ctx).WithError(err).Error("Database ping failed")
+		return fmt.Errorf("ping database: %w", err)
+	}
+	return nil
+}
+
+func (r *PostgresRepo) GetLatestRateDate(ctx context.Context, source string) (time.Time, error) {
+	logger := r.logger.WithContext(ctx)
+	query, args, err := psql.
+		Select("MAX(effective_date)").
+		From("latest_rates").
+		Where(sq.Eq{"source": source}).
+		ToSql()
+	if err != nil {
+		logger.WithError(err).Error("Failed to build latest rate date query")
+		return time.Time{}, fmt.Errorf("build select: %w", err)
+	}
+
+	var latest *time.Time
+	if err := r.pool.QueryRow(ctx, query, args...).Scan(&latest); err != nil {
+		logger.WithError(err).WithField("source", source).Error("Failed to query latest rate date")
+		return time.Time{}, fmt.Errorf("query latest rate date: %w", err)
+	}
+	if latest == nil {
+		return time.Time{}, ErrNotFound
+	}
+	return *latest, nil
+}
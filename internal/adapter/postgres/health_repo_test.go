@@ -0,0 +1,82 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"RnD-service/pkg/logger"
+
+	"github.com/Masterminds/squirrel"
+	pgxmock "github.com/pashagolub/pgxmock/v4"
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPing(t *testing.T) {
+	ctx := context.Background()
+	repo, mock := setupTestRepo(t)
+	defer mock.Close()
+
+	mock.ExpectPing()
+	mock.ExpectPing().WillReturnError(errors.New("connection refused"))
+
+	assert.NoError(t, repo.Ping(ctx))
+	assert.ErrorContains(t, repo.Ping(ctx), "connection refused")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPing_LogsWithRequestID(t *testing.T) {
+	repo, mock := setupTestRepo(t)
+	defer mock.Close()
+	repo.logger.AddHook(logger.ContextHook{})
+	hook := test.NewLocal(repo.logger)
+
+	mock.ExpectPing().WillReturnError(errors.New("connection refused"))
+
+	ctx := logger.WithRequestID(context.Background(), "req-42")
+	require.Error(t, repo.Ping(ctx))
+	require.NotNil(t, hook.LastEntry())
+	assert.Equal(t, "req-42", hook.LastEntry().Data["request_id"])
+}
+
+func expectLatestRateDate(t *testing.T, mock pgxmock.PgxPoolIface, source string) *pgxmock.ExpectedQuery {
+	query, args, err := psql.
+		Select("MAX(effective_date)").
+		From("latest_rates").
+		Where(squirrel.Eq{"source": source}).
+		ToSql()
+	require.NoError(t, err)
+	return mock.ExpectQuery(regexp.QuoteMeta(query)).WithArgs(args...)
+}
+
+func TestGetLatestRateDate(t *testing.T) {
+	ctx := context.Background()
+	repo, mock := setupTestRepo(t)
+	defer mock.Close()
+
+	latest := time.Date(2025, 8, 5, 0, 0, 0, 0, time.UTC)
+	expectLatestRateDate(t, mock, "cbr").
+		WillReturnRows(pgxmock.NewRows([]string{"max"}).AddRow(&latest))
+
+	date, err := repo.GetLatestRateDate(ctx, "cbr")
+	require.NoError(t, err)
+	assert.Equal(t, latest, date)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetLatestRateDate_Empty(t *testing.T) {
+	ctx := context.Background()
+	repo, mock := setupTestRepo(t)
+	defer mock.Close()
+
+	expectLatestRateDate(t, mock, "ecb").
+		WillReturnRows(pgxmock.NewRows([]string{"max"}).AddRow(nil))
+
+	_, err := repo.GetLatestRateDate(ctx, "ecb")
+	assert.ErrorIs(t, err, ErrNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
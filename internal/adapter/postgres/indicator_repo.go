@@ -0,0 +1,190 @@
+package postgres
+
+import (
+	"RnD-service/internal/entity"
+	"context"
+	"fmt"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/jackc/pgx/v5"
+	"github.com/sirupsen/logrus"
+	"go.uber.org/multierr"
+)
+
+func (r *PostgresRepo) StoreKeyRates(ctx context.Context, rates []entity.KeyRate) error {
+	r.logger.WithContext(ctx).Infof("Start storing %d key rates", len(rates))
+
+	if len(rates) == 0 {
+		return nil
+	}
+
+	batch := &pgx.Batch{}
+	for _, rate := range rates {
+		query, args, err := psql.Insert("key_rates").
+			Columns("date", "rate", "updated_at").
+			Values(rate.Date, rate.Rate, rate.UpdatedAt).
+			Suffix(`
+                ON CONFLICT (date) DO UPDATE SET
+                    rate = EXCLUDED.rate,
+                    updated_at = EXCLUDED.updated_at
+            `).
+			ToSql()
+		if err != nil {
+			return fmt.Errorf("build insert for key rate on %s: %w", rate.Date.Format("2006-01-02"), err)
+		}
+		batch.Queue(query, args...)
+	}
+
+	return r.storeBatch(ctx, "key rates", batch)
+}
+
+func (r *PostgresRepo) StoreRuoniaRates(ctx context.Context, rates []entity.RuoniaRate) error {
+	r.logger.WithContext(ctx).Infof("Start storing %d RUONIA rates", len(rates))
+
+	if len(rates) == 0 {
+		return nil
+	}
+
+	batch := &pgx.Batch{}
+	for _, rate := range rates {
+		query, args, err := psql.Insert("ruonia_rates").
+			Columns("date", "rate", "volume", "updated_at").
+			Values(rate.Date, rate.Rate, rate.Volume, rate.UpdatedAt).
+			Suffix(`
+                ON CONFLICT (date) DO UPDATE SET
+                    rate = EXCLUDED.rate,
+                    volume = EXCLUDED.volume,
+                    updated_at = EXCLUDED.updated_at
+            `).
+			ToSql()
+		if err != nil {
+			return fmt.Errorf("build insert for RUONIA on %s: %w", rate.Date.Format("2006-01-02"), err)
+		}
+		batch.Queue(query, args...)
+	}
+
+	return r.storeBatch(ctx, "RUONIA rates", batch)
+}
+
+func (r *PostgresRepo) storeBatch(ctx context.Context, what string, batch *pgx.Batch) error {
+	logger := r.logger.WithContext(ctx)
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		logger.WithError(err).Errorf("Failed to begin transaction for %s", what)
+		return fmt.Errorf("begin tx: %w", err)
+	}
+
+	br := tx.SendBatch(ctx, batch)
+
+	var batchErrs error
+	for i := 0; i < batch.Len(); i++ {
+		if _, err := br.Exec(); err != nil {
+			batchErrs = multierr.Append(batchErrs, err)
+			logger.WithError(err).Errorf("Failed batch exec for %s item %d", what, i)
+		}
+	}
+
+	if err := br.Close(); err != nil {
+		batchErrs = multierr.Append(batchErrs, err)
+		logger.WithError(err).Errorf("Failed to close batch results for %s", what)
+	}
+
+	if batchErrs != nil {
+		if rbErr := tx.Rollback(ctx); rbErr != nil {
+			logger.WithError(rbErr).Errorf("Failed to rollback %s tx", what)
+		}
+		return fmt.Errorf("batch exec/close errors for %s: %w", what, batchErrs)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		logger.WithError(err).Errorf("Failed to commit %s tx", what)
+		return fmt.Errorf("commit tx: %w", err)
+	}
+
+	logger.Infof("Successfully stored %d %s", batch.Len(), what)
+	return nil
+}
+
+func (r *PostgresRepo) GetKeyRates(ctx context.Context, dateFrom, dateTo string) ([]entity.KeyRate, error) {
+	logger := r.logger.WithContext(ctx)
+	fields := logrus.Fields{"from": dateFrom, "to": dateTo}
+	logger.WithFields(fields).Info("Getting key rates by date range")
+
+	query, args, err := psql.
+		Select("date", "rate", "updated_at").
+		From("key_rates").
+		Where(sq.GtOrEq{"date": dateFrom}).
+		Where(sq.LtOrEq{"date": dateTo}).
+		OrderBy("date ASC").
+		ToSql()
+	if err != nil {
+		logger.WithError(err).Error("Failed to build select query for key rates")
+		return nil, fmt.Errorf("build select: %w", err)
+	}
+
+	rows, err := r.pool.Query(ctx, query, args...)
+	if err != nil {
+		logger.WithError(err).WithFields(fields).Error("Failed to query key rates")
+		return nil, fmt.Errorf("query key rates: %w", err)
+	}
+	defer rows.Close()
+
+	var rates []entity.KeyRate
+	for rows.Next() {
+		var rate entity.KeyRate
+		if err := rows.Scan(&rate.Date, &rate.Rate, &rate.UpdatedAt); err != nil {
+			logger.WithError(err).Error("Failed to scan key rate row")
+			return nil, fmt.Errorf("scan row: %w", err)
+		}
+		rates = append(rates, rate)
+	}
+	if err := rows.Err(); err != nil {
+		logger.WithError(err).Error("Failed to iterate key rate rows")
+		return nil, fmt.Errorf("iterate rows: %w", err)
+	}
+
+	logger.WithFields(fields).Infof("Successfully retrieved %d key rates", len(rates))
+	return rates, nil
+}
+
+func (r *PostgresRepo) GetRuoniaRates(ctx context.Context, dateFrom, dateTo string) ([]entity.RuoniaRate, error) {
+	logger := r.logger.WithContext(ctx)
+	fields := logrus.Fields{"from": dateFrom, "to": dateTo}
+	logger.WithFields(fields).Info("Getting RUONIA rates by date range")
+
+	query, args, err := psql.
+		Select("date", "rate", "volume", "updated_at").
+		From("ruonia_rates").
+		Where(sq.GtOrEq{"date": dateFrom}).
+		Where(sq.LtOrEq{"date": dateTo}).
+		OrderBy("date ASC").
+		ToSql()
+	if err != nil {
+		logger.WithError(err).Error("Failed to build select query for RUONIA rates")
+		return nil, fmt.Errorf("build select: %w", err)
+	}
+
+	rows, err := r.pool.Query(ctx, query, args...)
+	if err != nil {
+		logger.WithError(err).WithFields(fields).Error("Failed to query RUONIA rates")
+		return nil, fmt.Errorf("query RUONIA rates: %w", err)
+	}
+	defer rows.Close()
+
+	var rates []entity.RuoniaRate
+	for rows.Next() {
+		var rate entity.RuoniaRate
+		if err := rows.Scan(&rate.Date, &rate.Rate, &rate.Volume, &rate.UpdatedAt); err != nil {
+			logger.WithError(err).Error("Failed to scan RUONIA row")
+			return nil, fmt.Errorf("scan row: %w", err)
+		}
+		rates = append(rates, rate)
+	}
+	if err := rows.Err(); err != nil {
+		logger.WithError(err).Error("Failed to iterate RUONIA rows")
+		return nil, fmt.Errorf("iterate rows: %w", err)
+	}
+
+	logger.WithFields(fields).Infof("Successfully retrieved %d RUONIA rates", len(rates))
+	return rates, nil
+}
@@ -0,0 +1,176 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"RnD-service/internal/entity"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/jackc/pgx/v5/pgconn"
+	pgxmock "github.com/pashagolub/pgxmock/v4"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const keyRateUpsert = `
+                ON CONFLICT (date) DO UPDATE SET
+                    rate = EXCLUDED.rate,
+                    updated_at = EXCLUDED.updated_at
+            `
+
+const ruoniaUpsert = `
+                ON CONFLICT (date) DO UPDATE SET
+                    rate = EXCLUDED.rate,
+                    volume = EXCLUDED.volume,
+                    updated_at = EXCLUDED.updated_at
+            `
+
+func TestStoreKeyRates(t *testing.T) {
+	ctx := context.Background()
+	repo, mock := setupTestRepo(t)
+	defer mock.Close()
+
+	now := time.Now().UTC()
+	rates := []entity.KeyRate{
+		{Date: time.Date(2025, 7, 25, 0, 0, 0, 0, time.UTC), Rate: decimal.RequireFromString("20"), UpdatedAt: now},
+		{Date: time.Date(2025, 7, 28, 0, 0, 0, 0, time.UTC), Rate: decimal.RequireFromString("18"), UpdatedAt: now},
+	}
+
+	mock.ExpectBegin()
+	eb := mock.ExpectBatch()
+	for _, rate := range rates {
+		query, args, err := psql.Insert("key_rates").
+			Columns("date", "rate", "updated_at").
+			Values(rate.Date, rate.Rate, rate.UpdatedAt).
+			Suffix(keyRateUpsert).
+			ToSql()
+		require.NoError(t, err)
+
+		eb.ExpectExec(regexp.QuoteMeta(query)).
+			WithArgs(args...).
+			WillReturnResult(pgconn.NewCommandTag("INSERT 0 1"))
+	}
+	mock.ExpectCommit()
+
+	assert.NoError(t, repo.StoreKeyRates(ctx, rates))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestStoreRuoniaRates_ErrorInBatch(t *testing.T) {
+	ctx := context.Background()
+	repo, mock := setupTestRepo(t)
+	defer mock.Close()
+
+	rate := entity.RuoniaRate{Date: time.Date(2025, 7, 28, 0, 0, 0, 0, time.UTC), Rate: decimal.RequireFromString("17.85"), Volume: decimal.RequireFromString("512.34"), UpdatedAt: time.Now().UTC()}
+	query, args, err := psql.Insert("ruonia_rates").
+		Columns("date", "rate", "volume", "updated_at").
+		Values(rate.Date, rate.Rate, rate.Volume, rate.UpdatedAt).
+		Suffix(ruoniaUpsert).
+		ToSql()
+	require.NoError(t, err)
+
+	expectedErr := errors.New("check constraint violated")
+	mock.ExpectBegin()
+	mock.ExpectBatch().ExpectExec(regexp.QuoteMeta(query)).
+		WithArgs(args...).
+		WillReturnError(expectedErr)
+	mock.ExpectRollback()
+
+	err = repo.StoreRuoniaRates(ctx, []entity.RuoniaRate{rate})
+	assert.ErrorContains(t, err, expectedErr.Error())
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestStoreIndicators_Empty(t *testing.T) {
+	repo, mock := setupTestRepo(t)
+	defer mock.Close()
+
+	assert.NoError(t, repo.StoreKeyRates(context.Background(), nil))
+	assert.NoError(t, repo.StoreRuoniaRates(context.Background(), nil))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetKeyRates(t *testing.T) {
+	ctx := context.Background()
+	repo, mock := setupTestRepo(t)
+	defer mock.Close()
+
+	now := time.Now().UTC()
+	query, args, err := psql.
+		Select("date", "rate", "updated_at").
+		From("key_rates").
+		Where(squirrel.GtOrEq{"date": "2025-07-25"}).
+		Where(squirrel.LtOrEq{"date": "2025-07-28"}).
+		OrderBy("date ASC").
+		ToSql()
+	require.NoError(t, err)
+
+	mock.ExpectQuery(regexp.QuoteMeta(query)).
+		WithArgs(args...).
+		WillReturnRows(pgxmock.NewRows([]string{"date", "rate", "updated_at"}).
+			AddRow(time.Date(2025, 7, 25, 0, 0, 0, 0, time.UTC), "20", now).
+			AddRow(time.Date(2025, 7, 28, 0, 0, 0, 0, time.UTC), "18", now))
+
+	rates, err := repo.GetKeyRates(ctx, "2025-07-25", "2025-07-28")
+	require.NoError(t, err)
+	require.Len(t, rates, 2)
+	assert.Equal(t, "18", rates[1].Rate.String())
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetRuoniaRates(t *testing.T) {
+	ctx := context.Background()
+	repo, mock := setupTestRepo(t)
+	defer mock.Close()
+
+	now := time.Now().UTC()
+	query, args, err := psql.
+		Select("date", "rate", "volume", "updated_at").
+		From("ruonia_rates").
+		Where(squirrel.GtOrEq{"date": "2025-07-28"}).
+		Where(squirrel.LtOrEq{"date": "2025-07-28"}).
+		OrderBy("date ASC").
+		ToSql()
+	require.NoError(t, err)
+
+	mock.ExpectQuery(regexp.QuoteMeta(query)).
+		WithArgs(args...).
+		WillReturnRows(pgxmock.NewRows([]string{"date", "rate", "volume", "updated_at"}).
+			AddRow(time.Date(2025, 7, 28, 0, 0, 0, 0, time.UTC), "17.85", "512.34", now))
+
+	rates, err := repo.GetRuoniaRates(ctx, "2025-07-28", "2025-07-28")
+	require.NoError(t, err)
+	require.Len(t, rates, 1)
+	assert.Equal(t, "512.34", rates[0].Volume.String())
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetRuoniaRates_Error(t *testing.T) {
+	ctx := context.Background()
+	repo, mock := setupTestRepo(t)
+	defer mock.Close()
+
+	query, args, err := psql.
+		Select("date", "rate", "volume", "updated_at").
+		From("ruonia_rates").
+		Where(squirrel.GtOrEq{"date": "2025-07-28"}).
+		Where(squirrel.LtOrEq{"date": "2025-07-29"}).
+		OrderBy("date ASC").
+		ToSql()
+	require.NoError(t, err)
+
+	expectedErr := errors.New("database error")
+	mock.ExpectQuery(regexp.QuoteMeta(query)).
+		WithArgs(args...).
+		WillReturnError(expectedErr)
+
+	rates, err := repo.GetRuoniaRates(ctx, "2025-07-28", "2025-07-29")
+	assert.Nil(t, rates)
+	assert.ErrorContains(t, err, expectedErr.Error())
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
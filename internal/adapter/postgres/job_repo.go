@@ -0,0 +1,167 @@
+package postgres
+
+import (
+	"RnD-service/internal/entity"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/jackc/pgx/v5"
+	"github.com/sirupsen/logrus"
+)
+
+var jobRunColumns = []string{"id", "job", "triggered_by", "instance", "status", "attempts", "error", "started_at", "finished_at"}
+
+func (r *PostgresRepo) CreateJobRun(ctx context.Context, run *entity.JobRun) (int64, error) {
+	logger := r.logger.WithContext(ctx)
+	query, args, err := psql.Insert("job_runs").
+		Columns(jobRunColumns[1:]...).
+		Values(run.Job, run.Trigger, run.Instance, run.Status, run.Attempts, run.Error, run.StartedAt, run.FinishedAt).
+		Suffix("RETURNING id").
+		ToSql()
+	if err != nil {
+		logger.WithError(err).Error("Failed to build insert query for job run")
+		return 0, fmt.Errorf("build insert: %w", err)
+	}
+
+	var id int64
+	if err := r.pool.QueryRow(ctx, query, args...).Scan(&id); err != nil {
+		logger.WithError(err).WithField("job", run.Job).Error("Failed to insert job run")
+		return 0, fmt.Errorf("insert job run: %w", err)
+	}
+
+	logger.WithFields(logrus.Fields{"job": run.Job, "run_id": id}).Debug("Created job run")
+	return id, nil
+}
+
+func (r *PostgresRepo) UpdateJobRun(ctx context.Context, run *entity.JobRun) error {
+	logger := r.logger.WithContext(ctx)
+	query, args, err := psql.Update("job_runs").
+		Set("status", run.Status).
+		Set("attempts", run.Attempts).
+		Set("error", run.Error).
+		Set("finished_at", run.FinishedAt).
+		Where(sq.Eq{"id": run.ID}).
+		ToSql()
+	if err != nil {
+		logger.WithError(err).Error("Failed to build update query for job run")
+		return fmt.Errorf("build update: %w", err)
+	}
+
+	ct, err := r.pool.Exec(ctx, query, args...)
+	if err != nil {
+		logger.WithError(err).WithField("run_id", run.ID).Error("Failed to update job run")
+		return fmt.Errorf("update job run: %w", err)
+	}
+	if ct.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+
+	logger.WithFields(logrus.Fields{"run_id": run.ID, "status": run.Status, "attempts": run.Attempts}).Debug("Updated job run")
+	return nil
+}
+
+func (r *PostgresRepo) FailInterruptedJobRuns(ctx context.Context, job string, at time.Time) error {
+	logger := r.logger.WithContext(ctx)
+	query, args, err := psql.Update("job_runs").
+		Set("status", entity.JobRunFailed).
+		Set("error", "interrupted").
+		Set("finished_at", at).
+		Where(sq.Eq{"job": job, "status": entity.JobRunRunning}).
+		ToSql()
+	if err != nil {
+		logger.WithError(err).Error("Failed to build update query for interrupted job runs")
+		return fmt.Errorf("build update: %w", err)
+	}
+
+	ct, err := r.pool.Exec(ctx, query, args...)
+	if err != nil {
+		logger.WithError(err).WithField("job", job).Error("Failed to fail interrupted job runs")
+		return fmt.Errorf("update job runs: %w", err)
+	}
+	if n := ct.RowsAffected(); n > 0 {
+		logger.WithField("job", job).Warnf("Marked %d interrupted runs as failed", n)
+	}
+	return nil
+}
+
+func (r *PostgresRepo) ListLatestJobRuns(ctx context.Context) ([]entity.JobRun, error) {
+	logger := r.logger.WithContext(ctx)
+	query, args, err := psql.
+		Select(jobRunColumns...).
+		Options("DISTINCT ON (job)").
+		From("job_runs").
+		OrderBy("job", "started_at DESC", "id DESC").
+		ToSql()
+	if err != nil {
+		logger.WithError(err).Error("Failed to build select query for job runs")
+		return nil, fmt.Errorf("build select: %w", err)
+	}
+
+	rows, err := r.pool.Query(ctx, query, args...)
+	if err != nil {
+		logger.WithError(err).Error("Failed to query job runs")
+		return nil, fmt.Errorf("query job runs: %w", err)
+	}
+	defer rows.Close()
+
+	var runs []entity.JobRun
+	for rows.Next() {
+		run, err := scanJobRun(rows)
+		if err != nil {
+			logger.WithError(err).Error("Failed to scan job run row")
+			return nil, fmt.Errorf("scan row: %w", err)
+		}
+		runs = append(runs, *run)
+	}
+	if err := rows.Err(); err != nil {
+		logger.WithError(err).Error("Failed to iterate job run rows")
+		return nil, fmt.Errorf("iterate rows: %w", err)
+	}
+	return runs, nil
+}
+
+func (r *PostgresRepo) GetLastFinishedJobRun(ctx context.Context, job string) (*entity.JobRun, error) {
+	logger := r.logger.WithContext(ctx)
+	query, args, err := psql.
+		Select(jobRunColumns...).
+		From("job_runs").
+		Where(sq.And{sq.Eq{"job": job}, sq.NotEq{"finished_at": nil}}).
+		OrderBy("finished_at DESC").
+		Limit(1).
+		ToSql()
+	if err != nil {
+		logger.WithError(err).Error("Failed to build select query for job run")
+		return nil, fmt.Errorf("build select: %w", err)
+	}
+
+	run, err := scanJobRun(r.pool.QueryRow(ctx, query, args...))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		logger.WithError(err).WithField("job", job).Error("Failed to query last job run")
+		return nil, fmt.Errorf("query scan: %w", err)
+	}
+	return run, nil
+}
+
+func scanJobRun(row pgx.Row) (*entity.JobRun, error) {
+	var run entity.JobRun
+	if err := row.Scan(
+		&run.ID,
+		&run.Job,
+		&run.Trigger,
+		&run.Instance,
+		&run.Status,
+		&run.Attempts,
+		&run.Error,
+		&run.StartedAt,
+		&run.FinishedAt,
+	); err != nil {
+		return nil, err
+	}
+	return &run, nil
+}
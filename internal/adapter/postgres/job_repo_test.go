@@ -0,0 +1,144 @@
+package postgres
+
+import (
+	"context"
+	"regexp"
+	"testing"
+	"time"
+
+	"RnD-service/internal/entity"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/jackc/pgx/v5/pgconn"
+	pgxmock "github.com/pashagolub/pgxmock/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateJobRun(t *testing.T) {
+	ctx := context.Background()
+	repo, mock := setupTestRepo(t)
+	defer mock.Close()
+
+	run := &entity.JobRun{
+		Job:       "cbr_rates",
+		Trigger:   entity.JobTriggerSchedule,
+		Instance:  "api-1",
+		Status:    entity.JobRunRunning,
+		StartedAt: time.Date(2025, 8, 5, 13, 0, 0, 0, time.UTC),
+	}
+	query, args, err := psql.Insert("job_runs").
+		Columns(jobRunColumns[1:]...).
+		Values(run.Job, run.Trigger, run.Instance, run.Status, run.Attempts, run.Error, run.StartedAt, run.FinishedAt).
+		Suffix("RETURNING id").
+		ToSql()
+	require.NoError(t, err)
+
+	mock.ExpectQuery(regexp.QuoteMeta(query)).
+		WithArgs(args...).
+		WillReturnRows(pgxmock.NewRows([]string{"id"}).AddRow(int64(7)))
+
+	id, err := repo.CreateJobRun(ctx, run)
+	require.NoError(t, err)
+	assert.Equal(t, int64(7), id)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUpdateJobRun_NotFound(t *testing.T) {
+	ctx := context.Background()
+	repo, mock := setupTestRepo(t)
+	defer mock.Close()
+
+	finished := time.Date(2025, 8, 5, 13, 1, 0, 0, time.UTC)
+	run := &entity.JobRun{ID: 7, Status: entity.JobRunSucceeded, Attempts: 1, FinishedAt: &finished}
+	query, args, err := psql.Update("job_runs").
+		Set("status", run.Status).
+		Set("attempts", run.Attempts).
+		Set("error", run.Error).
+		Set("finished_at", run.FinishedAt).
+		Where(squirrel.Eq{"id": run.ID}).
+		ToSql()
+	require.NoError(t, err)
+
+	mock.ExpectExec(regexp.QuoteMeta(query)).
+		WithArgs(args...).
+		WillReturnResult(pgconn.NewCommandTag("UPDATE 0"))
+
+	assert.ErrorIs(t, repo.UpdateJobRun(ctx, run), ErrNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestFailInterruptedJobRuns(t *testing.T) {
+	ctx := context.Background()
+	repo, mock := setupTestRepo(t)
+	defer mock.Close()
+
+	at := time.Date(2025, 8, 5, 13, 0, 0, 0, time.UTC)
+	query, args, err := psql.Update("job_runs").
+		Set("status", entity.JobRunFailed).
+		Set("error", "interrupted").
+		Set("finished_at", at).
+		Where(squirrel.Eq{"job": "cbr_rates", "status": entity.JobRunRunning}).
+		ToSql()
+	require.NoError(t, err)
+
+	mock.ExpectExec(regexp.QuoteMeta(query)).
+		WithArgs(args...).
+		WillReturnResult(pgconn.NewCommandTag("UPDATE 1"))
+
+	assert.NoError(t, repo.FailInterruptedJobRuns(ctx, "cbr_rates", at))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestListLatestJobRuns(t *testing.T) {
+	ctx := context.Background()
+	repo, mock := setupTestRepo(t)
+	defer mock.Close()
+
+	query, _, err := psql.
+		Select(jobRunColumns...).
+		Options("DISTINCT ON (job)").
+		From("job_runs").
+		OrderBy("job", "started_at DESC", "id DESC").
+		ToSql()
+	require.NoError(t, err)
+	assert.Contains(t, query, "SELECT DISTINCT ON (job) id")
+
+	started := time.Date(2025, 8, 5, 13, 0, 0, 0, time.UTC)
+	finished := started.Add(time.Minute)
+	mock.ExpectQuery(regexp.QuoteMeta(query)).
+		WillReturnRows(pgxmock.NewRows(jobRunColumns).
+			AddRow(int64(7), "cbr_rates", entity.JobTriggerSchedule, "api-1", entity.JobRunSucceeded, 1, "", started, &finished).
+			AddRow(int64(8), "ecb_rates", entity.JobTriggerManual, "api-2", entity.JobRunRunning, 2, "timeout", started, nil))
+
+	runs, err := repo.ListLatestJobRuns(ctx)
+	require.NoError(t, err)
+	require.Len(t, runs, 2)
+	assert.Equal(t, finished, *runs[0].FinishedAt)
+	assert.Nil(t, runs[1].FinishedAt)
+	assert.Equal(t, "timeout", runs[1].Error)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetLastFinishedJobRun_NotFound(t *testing.T) {
+	ctx := context.Background()
+	repo, mock := setupTestRepo(t)
+	defer mock.Close()
+
+	query, args, err := psql.
+		Select(jobRunColumns...).
+		From("job_runs").
+		Where(squirrel.And{squirrel.Eq{"job": "cbr_rates"}, squirrel.NotEq{"finished_at": nil}}).
+		OrderBy("finished_at DESC").
+		Limit(1).
+		ToSql()
+	require.NoError(t, err)
+
+	mock.ExpectQuery(regexp.QuoteMeta(query)).
+		WithArgs(args...).
+		WillReturnRows(pgxmock.NewRows(jobRunColumns))
+
+	_, err = repo.GetLastFinishedJobRun(ctx, "cbr_rates")
+	assert.ErrorIs(t, err, ErrNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
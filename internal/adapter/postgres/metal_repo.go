@@ -0,0 +1,149 @@
+package postgres
+
+import (
+	"RnD-service/internal/entity"
+	"context"
+	"errors"
+	"fmt"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/jackc/pgx/v5"
+	"github.com/sirupsen/logrus"
+	"go.uber.org/multierr"
+)
+
+var metalColumns = []string{"code", "date", "buy", "sell", "updated_at"}
+
+func (r *PostgresRepo) StoreMetalPrices(ctx context.Context, prices []entity.MetalPrice) error {
+	logger := r.logger.WithContext(ctx)
+	logger.Infof("Start storing %d metal prices", len(prices))
+
+	if len(prices) == 0 {
+		return nil
+	}
+
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		logger.WithError(err).Error("Failed to begin transaction for metal prices")
+		return fmt.Errorf("begin tx: %w", err)
+	}
+
+	batch := &pgx.Batch{}
+	for _, price := range prices {
+		query, args, err := psql.Insert("metal_prices").
+			Columns(metalColumns...).
+			Values(price.Code, price.Date, price.Buy, price.Sell, price.UpdatedAt).
+			Suffix("ON CONFLICT (code, date) DO NOTHING").
+			ToSql()
+		if err != nil {
+			return fmt.Errorf("build insert for %s on %s: %w", price.Code, price.Date.Format("2006-01-02"), err)
+		}
+		batch.Queue(query, args...)
+	}
+
+	br := tx.SendBatch(ctx, batch)
+
+	var batchErrs error
+	var inserted int64
+	for i := 0; i < batch.Len(); i++ {
+		ct, err := br.Exec()
+		if err != nil {
+			batchErrs = multierr.Append(batchErrs, err)
+			logger.WithError(err).Errorf("Failed batch exec for metal price %d", i)
+		} else {
+			inserted += ct.RowsAffected()
+		}
+	}
+
+	if err := br.Close(); err != nil {
+		batchErrs = multierr.Append(batchErrs, err)
+		logger.WithError(err).Error("Failed to close batch results for metal prices")
+	}
+
+	if batchErrs != nil {
+		if rbErr := tx.Rollback(ctx); rbErr != nil {
+			logger.WithError(rbErr).Error("Failed to rollback metal prices tx")
+		}
+		return fmt.Errorf("batch exec/close errors for metal prices: %w", batchErrs)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		logger.WithError(err).Error("Failed to commit metal prices tx")
+		return fmt.Errorf("commit tx: %w", err)
+	}
+
+	logger.Infof("Successfully stored %d metal prices", inserted)
+	return nil
+}
+
+func (r *PostgresRepo) GetMetalPrice(ctx context.Context, code, date string) (*entity.MetalPrice, error) {
+	logger := r.logger.WithContext(ctx)
+	logger.WithFields(logrus.Fields{"code": code, "date": date}).Info("Getting metal price by code and date")
+
+	query, args, err := psql.
+		Select(metalColumns...).
+		From("metal_prices").
+		Where(sq.Eq{"code": code, "date": date}).
+		Limit(1).
+		ToSql()
+	if err != nil {
+		logger.WithError(err).Error("Failed to build select query for metal price")
+		return nil, fmt.Errorf("build select: %w", err)
+	}
+
+	var price entity.MetalPrice
+	err = r.pool.QueryRow(ctx, query, args...).
+		Scan(&price.Code, &price.Date, &price.Buy, &price.Sell, &price.UpdatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			logger.WithFields(logrus.Fields{"code": code, "date": date}).Debug("Metal price not found in DB")
+			return nil, ErrNotFound
+		}
+		logger.WithError(err).WithFields(logrus.Fields{"code": code, "date": date}).Error("Failed to query metal price")
+		return nil, fmt.Errorf("query scan: %w", err)
+	}
+
+	return &price, nil
+}
+
+func (r *PostgresRepo) GetMetalPricesByDateRange(ctx context.Context, code, dateFrom, dateTo string) ([]entity.MetalPrice, error) {
+	logger := r.logger.WithContext(ctx)
+	logger.WithFields(logrus.Fields{"code": code, "from": dateFrom, "to": dateTo}).Info("Getting metal prices by date range")
+
+	query, args, err := psql.
+		Select(metalColumns...).
+		From("metal_prices").
+		Where(sq.Eq{"code": code}).
+		Where(sq.GtOrEq{"date": dateFrom}).
+		Where(sq.LtOrEq{"date": dateTo}).
+		OrderBy("date ASC").
+		ToSql()
+	if err != nil {
+		logger.WithError(err).Error("Failed to build select query for metal prices range")
+		return nil, fmt.Errorf("build select: %w", err)
+	}
+
+	rows, err := r.pool.Query(ctx, query, args...)
+	if err != nil {
+		logger.WithError(err).WithFields(logrus.Fields{"code": code, "from": dateFrom, "to": dateTo}).Error("Failed to query metal prices range")
+		return nil, fmt.Errorf("query metal prices range: %w", err)
+	}
+	defer rows.Close()
+
+	var prices []entity.MetalPrice
+	for rows.Next() {
+		var price entity.MetalPrice
+		if err := rows.Scan(&price.Code, &price.Date, &price.Buy, &price.Sell, &price.UpdatedAt); err != nil {
+			logger.WithError(err).Error("Failed to scan metal price row")
+			return nil, fmt.Errorf("scan row: %w", err)
+		}
+		prices = append(prices, price)
+	}
+	if err := rows.Err(); err != nil {
+		logger.WithError(err).Error("Failed to iterate metal price rows")
+		return nil, fmt.Errorf("iterate rows: %w", err)
+	}
+
+	logger.WithFields(logrus.Fields{"code": code, "from": dateFrom, "to": dateTo}).Infof("Successfully retrieved %d metal prices", len(prices))
+	return prices, nil
+}
@@ -0,0 +1,199 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"RnD-service/internal/entity"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	pgxmock "github.com/pashagolub/pgxmock/v4"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStoreMetalPrices(t *testing.T) {
+	ctx := context.Background()
+	repo, mock := setupTestRepo(t)
+	defer mock.Close()
+
+	now := time.Now().UTC()
+	date := time.Date(2025, 8, 1, 0, 0, 0, 0, time.UTC)
+	prices := []entity.MetalPrice{
+		{Code: "AU", Date: date, Buy: decimal.RequireFromString("8563.47"), Sell: decimal.RequireFromString("8563.47"), UpdatedAt: now},
+		{Code: "AG", Date: date, Buy: decimal.RequireFromString("96.12"), Sell: decimal.RequireFromString("96.12"), UpdatedAt: now},
+	}
+
+	mock.ExpectBegin()
+	eb := mock.ExpectBatch()
+	for _, price := range prices {
+		query, args, err := psql.Insert("metal_prices").
+			Columns("code", "date", "buy", "sell", "updated_at").
+			Values(price.Code, price.Date, price.Buy, price.Sell, price.UpdatedAt).
+			Suffix("ON CONFLICT (code, date) DO NOTHING").
+			ToSql()
+		require.NoError(t, err)
+
+		eb.ExpectExec(regexp.QuoteMeta(query)).
+			WithArgs(args...).
+			WillReturnResult(pgconn.NewCommandTag("INSERT 0 1"))
+	}
+	mock.ExpectCommit()
+
+	err := repo.StoreMetalPrices(ctx, prices)
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestStoreMetalPrices_ErrorInBatch(t *testing.T) {
+	ctx := context.Background()
+	repo, mock := setupTestRepo(t)
+	defer mock.Close()
+
+	now := time.Now().UTC()
+	date := time.Date(2025, 8, 1, 0, 0, 0, 0, time.UTC)
+	price := entity.MetalPrice{Code: "AU", Date: date, Buy: decimal.RequireFromString("8563.47"), Sell: decimal.RequireFromString("8563.47"), UpdatedAt: now}
+
+	query, args, err := psql.Insert("metal_prices").
+		Columns("code", "date", "buy", "sell", "updated_at").
+		Values(price.Code, price.Date, price.Buy, price.Sell, price.UpdatedAt).
+		Suffix("ON CONFLICT (code, date) DO NOTHING").
+		ToSql()
+	require.NoError(t, err)
+
+	expectedErr := errors.New("insert error")
+	mock.ExpectBegin()
+	mock.ExpectBatch().ExpectExec(regexp.QuoteMeta(query)).
+		WithArgs(args...).
+		WillReturnError(expectedErr)
+	mock.ExpectRollback()
+
+	err = repo.StoreMetalPrices(ctx, []entity.MetalPrice{price})
+	assert.ErrorContains(t, err, expectedErr.Error())
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestStoreMetalPrices_Empty(t *testing.T) {
+	repo, mock := setupTestRepo(t)
+	defer mock.Close()
+
+	assert.NoError(t, repo.StoreMetalPrices(context.Background(), nil))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetMetalPrice(t *testing.T) {
+	ctx := context.Background()
+	repo, mock := setupTestRepo(t)
+	defer mock.Close()
+
+	date := time.Date(2025, 8, 1, 0, 0, 0, 0, time.UTC)
+	now := time.Now().UTC()
+
+	query, args, err := psql.
+		Select("code", "date", "buy", "sell", "updated_at").
+		From("metal_prices").
+		Where(squirrel.Eq{"code": "AU", "date": "2025-08-01"}).
+		Limit(1).
+		ToSql()
+	require.NoError(t, err)
+
+	mock.ExpectQuery(regexp.QuoteMeta(query)).
+		WithArgs(args...).
+		WillReturnRows(pgxmock.NewRows([]string{"code", "date", "buy", "sell", "updated_at"}).
+			AddRow("AU", date, "8563.47", "8563.47", now))
+
+	price, err := repo.GetMetalPrice(ctx, "AU", "2025-08-01")
+	require.NoError(t, err)
+	assert.Equal(t, "AU", price.Code)
+	assert.Equal(t, date, price.Date)
+	assert.Equal(t, "8563.47", price.Buy.String())
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetMetalPrice_NotFound(t *testing.T) {
+	ctx := context.Background()
+	repo, mock := setupTestRepo(t)
+	defer mock.Close()
+
+	query, args, err := psql.
+		Select("code", "date", "buy", "sell", "updated_at").
+		From("metal_prices").
+		Where(squirrel.Eq{"code": "PD", "date": "2025-08-02"}).
+		Limit(1).
+		ToSql()
+	require.NoError(t, err)
+
+	mock.ExpectQuery(regexp.QuoteMeta(query)).
+		WithArgs(args...).
+		WillReturnError(pgx.ErrNoRows)
+
+	price, err := repo.GetMetalPrice(ctx, "PD", "2025-08-02")
+	assert.Nil(t, price)
+	assert.ErrorIs(t, err, ErrNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetMetalPricesByDateRange(t *testing.T) {
+	ctx := context.Background()
+	repo, mock := setupTestRepo(t)
+	defer mock.Close()
+
+	day1 := time.Date(2025, 7, 31, 0, 0, 0, 0, time.UTC)
+	day2 := time.Date(2025, 8, 1, 0, 0, 0, 0, time.UTC)
+	now := time.Now().UTC()
+
+	query, args, err := psql.
+		Select("code", "date", "buy", "sell", "updated_at").
+		From("metal_prices").
+		Where(squirrel.Eq{"code": "AG"}).
+		Where(squirrel.GtOrEq{"date": "2025-07-31"}).
+		Where(squirrel.LtOrEq{"date": "2025-08-01"}).
+		OrderBy("date ASC").
+		ToSql()
+	require.NoError(t, err)
+
+	mock.ExpectQuery(regexp.QuoteMeta(query)).
+		WithArgs(args...).
+		WillReturnRows(pgxmock.NewRows([]string{"code", "date", "buy", "sell", "updated_at"}).
+			AddRow("AG", day1, "95.8", "95.8", now).
+			AddRow("AG", day2, "96.12", "96.12", now))
+
+	prices, err := repo.GetMetalPricesByDateRange(ctx, "AG", "2025-07-31", "2025-08-01")
+	require.NoError(t, err)
+	require.Len(t, prices, 2)
+	assert.Equal(t, day1, prices[0].Date)
+	assert.Equal(t, "96.12", prices[1].Sell.String())
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetMetalPricesByDateRange_Error(t *testing.T) {
+	ctx := context.Background()
+	repo, mock := setupTestRepo(t)
+	defer mock.Close()
+
+	query, args, err := psql.
+		Select("code", "date", "buy", "sell", "updated_at").
+		From("metal_prices").
+		Where(squirrel.Eq{"code": "AG"}).
+		Where(squirrel.GtOrEq{"date": "2025-07-31"}).
+		Where(squirrel.LtOrEq{"date": "2025-08-01"}).
+		OrderBy("date ASC").
+		ToSql()
+	require.NoError(t, err)
+
+	expectedErr := errors.New("database error")
+	mock.ExpectQuery(regexp.QuoteMeta(query)).
+		WithArgs(args...).
+		WillReturnError(expectedErr)
+
+	prices, err := repo.GetMetalPricesByDateRange(ctx, "AG", "2025-07-31", "2025-08-01")
+	assert.Nil(t, prices)
+	assert.ErrorContains(t, err, expectedErr.Error())
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,350 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/sirupsen/logrus"
+)
+
+var (
+	ErrSchemaTooNew = errors.New("database schema is newer than this binary")
+	ErrSchemaDirty  = errors.New("database schema is dirty")
+)
+
+// migrationLockID keys the advisory lock held while migrating, so replicas
+// starting together apply each migration once.
+const migrationLockID int64 = 0x726e645f6d6967 // "rnd_mig"
+
+// createSchemaMigrationsSQL matches the table used by golang-migrate, so
+// databases migrated with the migrate/migrate image keep their version.
+const createSchemaMigrationsSQL = `CREATE TABLE IF NOT EXISTS schema_migrations (version BIGINT NOT NULL PRIMARY KEY, dirty BOOLEAN NOT NULL)`
+
+var migrationFileRe = regexp.MustCompile(`^(\d+)_(.+)\.(up|down)\.sql$`)
+
+type Migration struct {
+	Version int64
+	Name    string
+	Up      string
+	Down    string
+}
+
+type MigrationStatus struct {
+	Version int64
+	Dirty   bool
+	Latest  int64
+	Applied []Migration
+	Pending []Migration
+}
+
+type Migrator struct {
+	pool       Pool
+	migrations []Migration
+	logger     *logrus.Logger
+}
+
+func NewMigrator(pool Pool, fsys fs.FS, logger *logrus.Logger) (*Migrator, error) {
+	migrations, err := LoadMigrations(fsys)
+	if err != nil {
+		return nil, err
+	}
+	return &Migrator{pool: pool, migrations: migrations, logger: logger}, nil
+}
+
+// LoadMigrations reads NNN_name.up.sql and NNN_name.down.sql pairs from the
+// root of fsys, ordered by version.
+func LoadMigrations(fsys fs.FS) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, fmt.Errorf("read migrations: %w", err)
+	}
+
+	byVersion := make(map[int64]*Migration)
+	for _, e := range entries {
+		m := migrationFileRe.FindStringSubmatch(e.Name())
+		if e.IsDir() || m == nil {
+			continue
+		}
+		version, err := strconv.ParseInt(m[1], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("migration %s: %w", e.Name(), err)
+		}
+		body, err := fs.ReadFile(fsys, path.Join(".", e.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("read migration %s: %w", e.Name(), err)
+		}
+
+		mig, ok := byVersion[version]
+		if !ok {
+			mig = &Migration{Version: version, Name: m[2]}
+			byVersion[version] = mig
+		} else if mig.Name != m[2] {
+			return nil, fmt.Errorf("migration %d has two names: %s and %s", version, mig.Name, m[2])
+		}
+		if m[3] == "up" {
+			mig.Up = string(body)
+		} else {
+			mig.Down = string(body)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, mig := range byVersion {
+		if mig.Up == "" {
+			return nil, fmt.Errorf("migration %d_%s has no up script", mig.Version, mig.Name)
+		}
+		if mig.Down == "" {
+			return nil, fmt.Errorf("migration %d_%s has no down script", mig.Version, mig.Name)
+		}
+		migrations = append(migrations, *mig)
+	}
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+	return migrations, nil
+}
+
+// Latest returns the version of the newest known migration, 0 if there are none.
+func (m *Migrator) Latest() int64 {
+	if len(m.migrations) == 0 {
+		return 0
+	}
+	return m.migrations[len(m.migrations)-1].Version
+}
+
+// Up applies all pending migrations, each in its own transaction.
+func (m *Migrator) Up(ctx context.Context) error {
+	return m.locked(ctx, func() error {
+		version, dirty, err := m.version(ctx)
+		if err != nil {
+			return err
+		}
+		if err := m.checkApplicable(version, dirty); err != nil {
+			return err
+		}
+
+		applied := 0
+		for _, mig := range m.migrations {
+			if mig.Version <= version {
+				continue
+			}
+			if err := m.apply(ctx, mig.Version, mig.Name, mig.Up, mig.Version); err != nil {
+				return err
+			}
+			applied++
+		}
+
+		if applied == 0 {
+			m.logger.Infof("Database schema is up to date at version %d", version)
+		} else {
+			m.logger.Infof("Applied %d migrations, database schema is at version %d", applied, m.Latest())
+		}
+		return nil
+	})
+}
+
+// Down reverts the last steps applied migrations.
+func (m *Migrator) Down(ctx context.Context, steps int) error {
+	if steps <= 0 {
+		return fmt.Errorf("steps must be positive, got %d", steps)
+	}
+	return m.locked(ctx, func() error {
+		version, dirty, err := m.version(ctx)
+		if err != nil {
+			return err
+		}
+		if err := m.checkApplicable(version, dirty); err != nil {
+			return err
+		}
+
+		for i := len(m.migrations) - 1; i >= 0 && steps > 0; i-- {
+			mig := m.migrations[i]
+			if mig.Version > version {
+				continue
+			}
+			var previous int64
+			if i > 0 {
+				previous = m.migrations[i-1].Version
+			}
+			if err := m.apply(ctx, mig.Version, mig.Name+" (down)", mig.Down, previous); err != nil {
+				return err
+			}
+			version = previous
+			steps--
+		}
+
+		m.logger.Infof("Database schema is at version %d", version)
+		return nil
+	})
+}
+
+// Force records version as applied and clean without running any migration,
+// to recover after a failed migration was fixed by hand. Version must be 0 or
+// one of the embedded migrations.
+func (m *Migrator) Force(ctx context.Context, version int64) error {
+	if version < 0 {
+		return fmt.Errorf("version must not be negative, got %d", version)
+	}
+	if version > m.Latest() {
+		return fmt.Errorf("version %d is newer than the latest known migration %d", version, m.Latest())
+	}
+	if version > 0 && !m.known(version) {
+		return fmt.Errorf("version %d matches no migration", version)
+	}
+	return m.locked(ctx, func() error {
+		tx, err := m.pool.Begin(ctx)
+		if err != nil {
+			return fmt.Errorf("begin tx: %w", err)
+		}
+		if err := setVersion(ctx, tx, version); err != nil {
+			if rbErr := tx.Rollback(ctx); rbErr != nil {
+				m.logger.WithError(rbErr).Error("Failed to rollback tx")
+			}
+			return err
+		}
+		if err := tx.Commit(ctx); err != nil {
+			return fmt.Errorf("commit tx: %w", err)
+		}
+		m.logger.Warnf("Forced database schema version to %d", version)
+		return nil
+	})
+}
+
+func (m *Migrator) known(version int64) bool {
+	for _, mig := range m.migrations {
+		if mig.Version == version {
+			return true
+		}
+	}
+	return false
+}
+
+func (m *Migrator) Status(ctx context.Context) (*MigrationStatus, error) {
+	if _, err := m.pool.Exec(ctx, createSchemaMigrationsSQL); err != nil {
+		return nil, fmt.Errorf("create schema_migrations: %w", err)
+	}
+	version, dirty, err := m.version(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	status := &MigrationStatus{Version: version, Dirty: dirty, Latest: m.Latest()}
+	for _, mig := range m.migrations {
+		if mig.Version <= version {
+			status.Applied = append(status.Applied, mig)
+		} else {
+			status.Pending = append(status.Pending, mig)
+		}
+	}
+	return status, nil
+}
+
+// CheckVersion refuses a schema this binary does not know or a dirty one; an
+// older schema is only reported, since it may be migrated later.
+func (m *Migrator) CheckVersion(ctx context.Context) error {
+	status, err := m.Status(ctx)
+	if err != nil {
+		return err
+	}
+	if err := m.checkApplicable(status.Version, status.Dirty); err != nil {
+		return err
+	}
+	if len(status.Pending) > 0 {
+		m.logger.Warnf("Database schema is at version %d, %d migrations up to version %d are pending", status.Version, len(status.Pending), status.Latest)
+	}
+	return nil
+}
+
+func (m *Migrator) checkApplicable(version int64, dirty bool) error {
+	if dirty {
+		return fmt.Errorf("%w at version %d, fix it and run `migrate force`", ErrSchemaDirty, version)
+	}
+	if version > m.Latest() {
+		return fmt.Errorf("%w: database is at version %d, latest known is %d", ErrSchemaTooNew, version, m.Latest())
+	}
+	return nil
+}
+
+// locked runs fn while holding the migration advisory lock. The lock is
+// transaction-scoped, so it is released even if the connection is lost.
+func (m *Migrator) locked(ctx context.Context, fn func() error) error {
+	lockTx, err := m.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("begin lock tx: %w", err)
+	}
+	defer func() {
+		if err := lockTx.Rollback(ctx); err != nil && !errors.Is(err, pgx.ErrTxClosed) {
+			m.logger.WithError(err).Error("Failed to release migration lock")
+		}
+	}()
+
+	m.logger.Debug("Waiting for migration lock")
+	if _, err := lockTx.Exec(ctx, "SELECT pg_advisory_xact_lock($1)", migrationLockID); err != nil {
+		return fmt.Errorf("acquire migration lock: %w", err)
+	}
+	if _, err := m.pool.Exec(ctx, createSchemaMigrationsSQL); err != nil {
+		return fmt.Errorf("create schema_migrations: %w", err)
+	}
+	return fn()
+}
+
+func (m *Migrator) version(ctx context.Context) (int64, bool, error) {
+	var version int64
+	var dirty bool
+	err := m.pool.QueryRow(ctx, "SELECT version, dirty FROM schema_migrations LIMIT 1").Scan(&version, &dirty)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, fmt.Errorf("read schema version: %w", err)
+	}
+	return version, dirty, nil
+}
+
+// apply runs script and records target as the schema version in one transaction.
+func (m *Migrator) apply(ctx context.Context, version int64, name, script string, target int64) error {
+	m.logger.Infof("Applying migration %d_%s", version, name)
+
+	tx, err := m.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("begin tx: %w", err)
+	}
+	if _, err := tx.Exec(ctx, script); err != nil {
+		if rbErr := tx.Rollback(ctx); rbErr != nil {
+			m.logger.WithError(rbErr).Error("Failed to rollback tx")
+		}
+		m.logger.WithError(err).Errorf("Migration %d_%s failed", version, name)
+		return fmt.Errorf("migration %d_%s: %w", version, name, err)
+	}
+	if err := setVersion(ctx, tx, target); err != nil {
+		if rbErr := tx.Rollback(ctx); rbErr != nil {
+			m.logger.WithError(rbErr).Error("Failed to rollback tx")
+		}
+		return err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("commit migration %d_%s: %w", version, name, err)
+	}
+	return nil
+}
+
+// setVersion stores version, or no row for version 0 as golang-migrate does.
+func setVersion(ctx context.Context, tx pgx.Tx, version int64) error {
+	if _, err := tx.Exec(ctx, "DELETE FROM schema_migrations"); err != nil {
+		return fmt.Errorf("clear schema version: %w", err)
+	}
+	if version == 0 {
+		return nil
+	}
+	if _, err := tx.Exec(ctx, "INSERT INTO schema_migrations (version, dirty) VALUES ($1, false)", version); err != nil {
+		return fmt.Errorf("set schema version: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,250 @@
+package postgres
+
+import (
+	"context"
+	"io"
+	"regexp"
+	"testing"
+	"testing/fstest"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	pgxmock "github.com/pashagolub/pgxmock/v4"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testMigrations = fstest.MapFS{
+	"001_create_a.up.sql":   {Data: []byte("CREATE TABLE a (id INT);")},
+	"001_create_a.down.sql": {Data: []byte("DROP TABLE a;")},
+	"002_create_b.up.sql":   {Data: []byte("CREATE TABLE b (id INT);")},
+	"002_create_b.down.sql": {Data: []byte("DROP TABLE b;")},
+	"migrations.go":         {Data: []byte("package migrations")},
+}
+
+func setupTestMigrator(t *testing.T) (*Migrator, pgxmock.PgxPoolIface) {
+	mock, err := pgxmock.NewPool()
+	require.NoError(t, err)
+
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+
+	migrator, err := NewMigrator(mock, testMigrations, logger)
+	require.NoError(t, err)
+	return migrator, mock
+}
+
+func expectLock(mock pgxmock.PgxPoolIface) {
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("SELECT pg_advisory_xact_lock($1)")).
+		WithArgs(migrationLockID).
+		WillReturnResult(pgconn.NewCommandTag("SELECT 1"))
+	mock.ExpectExec(regexp.QuoteMeta(createSchemaMigrationsSQL)).
+		WillReturnResult(pgconn.NewCommandTag("CREATE TABLE"))
+}
+
+func expectVersion(mock pgxmock.PgxPoolIface, version int64, dirty bool) {
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT version, dirty FROM schema_migrations LIMIT 1")).
+		WillReturnRows(pgxmock.NewRows([]string{"version", "dirty"}).AddRow(version, dirty))
+}
+
+func expectApply(mock pgxmock.PgxPoolIface, script string, target int64) {
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(script)).WillReturnResult(pgconn.NewCommandTag("OK"))
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM schema_migrations")).WillReturnResult(pgconn.NewCommandTag("DELETE 1"))
+	if target > 0 {
+		mock.ExpectExec(regexp.QuoteMeta("INSERT INTO schema_migrations (version, dirty) VALUES ($1, false)")).
+			WithArgs(target).
+			WillReturnResult(pgconn.NewCommandTag("INSERT 0 1"))
+	}
+	mock.ExpectCommit()
+}
+
+func TestLoadMigrations(t *testing.T) {
+	migrations, err := LoadMigrations(testMigrations)
+	require.NoError(t, err)
+	require.Len(t, migrations, 2)
+	assert.Equal(t, Migration{Version: 1, Name: "create_a", Up: "CREATE TABLE a (id INT);", Down: "DROP TABLE a;"}, migrations[0])
+	assert.Equal(t, int64(2), migrations[1].Version)
+}
+
+func TestLoadMigrations_MissingDown(t *testing.T) {
+	_, err := LoadMigrations(fstest.MapFS{
+		"001_create_a.up.sql": {Data: []byte("CREATE TABLE a (id INT);")},
+	})
+	assert.ErrorContains(t, err, "no down script")
+}
+
+func TestMigrator_Up(t *testing.T) {
+	ctx := context.Background()
+	migrator, mock := setupTestMigrator(t)
+	defer mock.Close()
+
+	expectLock(mock)
+	expectVersion(mock, 1, false)
+	expectApply(mock, "CREATE TABLE b (id INT);", 2)
+	mock.ExpectRollback()
+
+	require.NoError(t, migrator.Up(ctx))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMigrator_Up_FreshDatabase(t *testing.T) {
+	ctx := context.Background()
+	migrator, mock := setupTestMigrator(t)
+	defer mock.Close()
+
+	expectLock(mock)
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT version, dirty FROM schema_migrations LIMIT 1")).
+		WillReturnRows(pgxmock.NewRows([]string{"version", "dirty"}))
+	expectApply(mock, "CREATE TABLE a (id INT);", 1)
+	expectApply(mock, "CREATE TABLE b (id INT);", 2)
+	mock.ExpectRollback()
+
+	require.NoError(t, migrator.Up(ctx))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMigrator_Up_FailedMigrationRollsBack(t *testing.T) {
+	ctx := context.Background()
+	migrator, mock := setupTestMigrator(t)
+	defer mock.Close()
+
+	expectLock(mock)
+	expectVersion(mock, 1, false)
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("CREATE TABLE b (id INT);")).WillReturnError(assert.AnError)
+	mock.ExpectRollback()
+	mock.ExpectRollback()
+
+	err := migrator.Up(ctx)
+	assert.ErrorIs(t, err, assert.AnError)
+	assert.ErrorContains(t, err, "migration 2_create_b")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMigrator_Up_RefusesNewerSchema(t *testing.T) {
+	ctx := context.Background()
+	migrator, mock := setupTestMigrator(t)
+	defer mock.Close()
+
+	expectLock(mock)
+	expectVersion(mock, 3, false)
+	mock.ExpectRollback()
+
+	assert.ErrorIs(t, migrator.Up(ctx), ErrSchemaTooNew)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMigrator_Up_RefusesDirtySchema(t *testing.T) {
+	ctx := context.Background()
+	migrator, mock := setupTestMigrator(t)
+	defer mock.Close()
+
+	expectLock(mock)
+	expectVersion(mock, 1, true)
+	mock.ExpectRollback()
+
+	assert.ErrorIs(t, migrator.Up(ctx), ErrSchemaDirty)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMigrator_Down(t *testing.T) {
+	ctx := context.Background()
+	migrator, mock := setupTestMigrator(t)
+	defer mock.Close()
+
+	expectLock(mock)
+	expectVersion(mock, 2, false)
+	expectApply(mock, "DROP TABLE b;", 1)
+	expectApply(mock, "DROP TABLE a;", 0)
+	mock.ExpectRollback()
+
+	require.NoError(t, migrator.Down(ctx, 5))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMigrator_Force(t *testing.T) {
+	ctx := context.Background()
+	migrator, mock := setupTestMigrator(t)
+	defer mock.Close()
+
+	expectLock(mock)
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM schema_migrations")).WillReturnResult(pgconn.NewCommandTag("DELETE 1"))
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO schema_migrations (version, dirty) VALUES ($1, false)")).
+		WithArgs(int64(1)).
+		WillReturnResult(pgconn.NewCommandTag("INSERT 0 1"))
+	mock.ExpectCommit()
+	mock.ExpectRollback()
+
+	require.NoError(t, migrator.Force(ctx, 1))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMigrator_Force_UnknownVersion(t *testing.T) {
+	ctx := context.Background()
+	migrations := fstest.MapFS{
+		"001_create_a.up.sql":   {Data: []byte("CREATE TABLE a (id INT);")},
+		"001_create_a.down.sql": {Data: []byte("DROP TABLE a;")},
+		"003_create_c.up.sql":   {Data: []byte("CREATE TABLE c (id INT);")},
+		"003_create_c.down.sql": {Data: []byte("DROP TABLE c;")},
+	}
+	mock, err := pgxmock.NewPool()
+	require.NoError(t, err)
+	defer mock.Close()
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	migrator, err := NewMigrator(mock, migrations, logger)
+	require.NoError(t, err)
+
+	assert.ErrorContains(t, migrator.Force(ctx, -1), "must not be negative")
+	assert.ErrorContains(t, migrator.Force(ctx, 4), "newer than the latest known migration 3")
+	assert.ErrorContains(t, migrator.Force(ctx, 2), "matches no migration")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMigrator_Force_Zero(t *testing.T) {
+	ctx := context.Background()
+	migrator, mock := setupTestMigrator(t)
+	defer mock.Close()
+
+	expectLock(mock)
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM schema_migrations")).WillReturnResult(pgconn.NewCommandTag("DELETE 1"))
+	mock.ExpectCommit()
+	mock.ExpectRollback()
+
+	require.NoError(t, migrator.Force(ctx, 0))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMigrator_Status(t *testing.T) {
+	ctx := context.Background()
+	migrator, mock := setupTestMigrator(t)
+	defer mock.Close()
+
+	mock.ExpectExec(regexp.QuoteMeta(createSchemaMigrationsSQL)).WillReturnResult(pgconn.NewCommandTag("CREATE TABLE"))
+	expectVersion(mock, 1, false)
+
+	status, err := migrator.Status(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), status.Version)
+	assert.Equal(t, int64(2), status.Latest)
+	require.Len(t, status.Applied, 1)
+	require.Len(t, status.Pending, 1)
+	assert.Equal(t, "create_b", status.Pending[0].Name)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMigrator_CheckVersion_NewerSchema(t *testing.T) {
+	ctx := context.Background()
+	migrator, mock := setupTestMigrator(t)
+	defer mock.Close()
+
+	mock.ExpectExec(regexp.QuoteMeta(createSchemaMigrationsSQL)).WillReturnResult(pgconn.NewCommandTag("CREATE TABLE"))
+	expectVersion(mock, 7, false)
+
+	assert.ErrorIs(t, migrator.CheckVersion(ctx), ErrSchemaTooNew)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -6,7 +6,6 @@ import (
 	"errors"
 	"fmt"
 	"strings"
-	"time"
 
 	sq "github.com/Masterminds/squirrel"
 	"github.com/jackc/pgx/v5"
@@ -31,130 +30,382 @@ func NewPostgresRepo(pool Pool, logger *logrus.Logger) *PostgresRepo {
 	}
 }
 
+var rateColumns = []string{"source", "char_code", "effective_date", "name", "nominal", "value", "num_code", "fetched_at", "payload_hash"}
+
+// StoreRates upserts publications by (source, char_code, Date) and, in the
+// same batch, moves latest_rates forward to any newer or revised publication.
+// A stored publication is rewritten only when it comes from a different
+// payload, so fetched_at tells when the stored version was first seen.
 func (r *PostgresRepo) StoreRates(ctx context.Context, rates []entity.Currency) error {
-	r.logger.Info("Start storing currency rates")
+	logger := r.logger.WithContext(ctx)
+	logger.Infof("Start storing %d currency rates", len(rates))
+
+	if len(rates) == 0 {
+		return nil
+	}
 
 	tx, err := r.pool.Begin(ctx)
 	if err != nil {
-		r.logger.WithError(err).Error("Failed to begin transaction")
+		logger.WithError(err).Error("Failed to begin transaction")
 		return fmt.Errorf("begin tx: %w", err)
 	}
 
 	batch := &pgx.Batch{}
 	for _, rate := range rates {
-		query, args, err := psql.Insert("currency_rates").
-			Columns("char_code", "name", "nominal", "value", "num_code", "updated_at").
-			Values(rate.CharCode, rate.Name, rate.Nominal, rate.Value, rate.NumCode, rate.UpdatedAt).
+		query, args, err := psql.Insert("rates").
+			Columns(rateColumns...).
+			Values(rate.Source, rate.CharCode, rate.Date, rate.Name, rate.Nominal, rate.Value, rate.NumCode, rate.UpdatedAt, nullIfEmpty(rate.PayloadHash)).
 			Suffix(`
-                ON CONFLICT (char_code) DO UPDATE SET
+                ON CONFLICT (source, char_code, effective_date) DO UPDATE SET
                     name = EXCLUDED.name,
                     nominal = EXCLUDED.nominal,
                     value = EXCLUDED.value,
                     num_code = EXCLUDED.num_code,
-                    updated_at = EXCLUDED.updated_at
+                    fetched_at = EXCLUDED.fetched_at,
+                    payload_hash = EXCLUDED.payload_hash
+                WHERE rates.payload_hash IS DISTINCT FROM EXCLUDED.payload_hash
             `).
 			ToSql()
 		if err != nil {
-			return fmt.Errorf("build insert for %s: %w", rate.CharCode, err)
+			if rbErr := tx.Rollback(ctx); rbErr != nil {
+				logger.WithError(rbErr).Error("Failed to rollback tx")
+			}
+			return fmt.Errorf("build insert for %s on %s: %w", rate.CharCode, rate.Date.Format("2006-01-02"), err)
 		}
 		batch.Queue(query, args...)
+
+		latestQuery, latestArgs, err := latestRateUpsert(rate)
+		if err != nil {
+			if rbErr := tx.Rollback(ctx); rbErr != nil {
+				logger.WithError(rbErr).Error("Failed to rollback tx")
+			}
+			return fmt.Errorf("build latest rate upsert for %s on %s: %w", rate.CharCode, rate.Date.Format("2006-01-02"), err)
+		}
+		batch.Queue(latestQuery, latestArgs...)
 	}
 
 	br := tx.SendBatch(ctx, batch)
 
 	var batchErrs error
+	var changed int64
+	// statements alternate: the rates upsert, then the latest_rates one
 	for i := 0; i < batch.Len(); i++ {
-		_, err := br.Exec()
+		ct, err := br.Exec()
 		if err != nil {
 			batchErrs = multierr.Append(batchErrs, err)
-			r.logger.WithError(err).Errorf("Failed batch exec for rate %d", i)
+			logger.WithError(err).Errorf("Failed batch exec for rate %d", i/2)
+		} else if i%2 == 0 {
+			changed += ct.RowsAffected()
 		}
 	}
 
 	if err := br.Close(); err != nil {
 		batchErrs = multierr.Append(batchErrs, err)
-		r.logger.WithError(err).Error("Failed to close batch results")
+		logger.WithError(err).Error("Failed to close batch results")
 	}
 
 	if batchErrs != nil {
 		if rbErr := tx.Rollback(ctx); rbErr != nil {
-			r.logger.WithError(rbErr).Error("Failed to rollback tx after batch errors")
+			logger.WithError(rbErr).Error("Failed to rollback tx after batch errors")
 		}
 		return fmt.Errorf("batch exec/close errors: %w", batchErrs)
 	}
 
 	if err := tx.Commit(ctx); err != nil {
-		r.logger.WithError(err).Error("Failed to commit tx")
+		logger.WithError(err).Error("Failed to commit tx")
 		return fmt.Errorf("commit tx: %w", err)
 	}
 
-	r.logger.Info("Successfully stored all currency rates")
+	logger.Infof("Successfully stored %d currency rates (%d new or revised)", len(rates), changed)
 	return nil
 }
 
-func (r *PostgresRepo) GetRateByCharCode(ctx context.Context, charCode string) (*entity.Currency, error) {
-	r.logger.WithField("char_code", charCode).Info("Getting currency rate by char code")
+// latestRateUpsert replaces the latest_rates row of the currency when rate is
+// newer, or the same publication from a different payload.
+func latestRateUpsert(rate entity.Currency) (string, []interface{}, error) {
+	return psql.Insert("latest_rates").
+		Columns(rateColumns...).
+		Values(rate.Source, rate.CharCode, rate.Date, rate.Name, rate.Nominal, rate.Value, rate.NumCode, rate.UpdatedAt, nullIfEmpty(rate.PayloadHash)).
+		Suffix(`
+                ON CONFLICT (source, char_code) DO UPDATE SET
+                    effective_date = EXCLUDED.effective_date,
+                    name = EXCLUDED.name,
+                    nominal = EXCLUDED.nominal,
+                    value = EXCLUDED.value,
+                    num_code = EXCLUDED.num_code,
+                    fetched_at = EXCLUDED.fetched_at,
+                    payload_hash = EXCLUDED.payload_hash
+                WHERE latest_rates.effective_date < EXCLUDED.effective_date
+                    OR (latest_rates.effective_date = EXCLUDED.effective_date
+                        AND latest_rates.payload_hash IS DISTINCT FROM EXCLUDED.payload_hash)
+            `).
+		ToSql()
+}
+
+// GetRateByCharCode returns the newest stored publication from latest_rates.
+func (r *PostgresRepo) GetRateByCharCode(ctx context.Context, source, charCode string) (*entity.Currency, error) {
+	logger := r.logger.WithContext(ctx)
+	logger.WithFields(logrus.Fields{"source": source, "char_code": charCode}).Info("Getting latest currency rate by char code")
 
 	query, args, err := psql.
-		Select("char_code", "name", "nominal", "value").
-		From("currency_rates").
-		Where(sq.Eq{"char_code": charCode}).
+		Select(rateColumns...).
+		From("latest_rates").
+		Where(sq.Eq{"source": source, "char_code": strings.ToUpper(charCode)}).
 		Limit(1).
 		ToSql()
+	if err != nil {
+		logger.WithError(err).Error("Failed to build select query")
+		return nil, fmt.Errorf("build select: %w", err)
+	}
+
+	rate, err := scanRate(r.pool.QueryRow(ctx, query, args...))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		logger.WithError(err).WithFields(logrus.Fields{"source": source, "char_code": charCode}).Error("Failed to query latest rate")
+		return nil, fmt.Errorf("query latest rate: %w", err)
+	}
+
+	logger.WithFields(logrus.Fields{
+		"char_code":      rate.CharCode,
+		"value":          rate.Value,
+		"nominal":        rate.Nominal,
+		"effective_date": rate.Date.Format("2006-01-02"),
+	}).Info("Successfully retrieved latest currency rate")
 
+	return rate, nil
+}
+
+func (r *PostgresRepo) GetLatestRateSummaries(ctx context.Context) ([]entity.LatestRateSummary, error) {
+	logger := r.logger.WithContext(ctx)
+	query, args, err := psql.
+		Select("source", "MAX(effective_date)", "MAX(fetched_at)").
+		From("latest_rates").
+		GroupBy("source").
+		OrderBy("source").
+		ToSql()
 	if err != nil {
-		r.logger.WithError(err).Error("Failed to build select query")
+		logger.WithError(err).Error("Failed to build select query for latest rate summaries")
 		return nil, fmt.Errorf("build select: %w", err)
 	}
 
-	var rate entity.Currency
-	err = r.pool.QueryRow(ctx, query, args...).
-		Scan(
-			&rate.CharCode,
-			&rate.Name,
-			&rate.Nominal,
-			&rate.Value,
-		)
+	rows, err := r.pool.Query(ctx, query, args...)
+	if err != nil {
+		logger.WithError(err).Error("Failed to query latest rate summaries")
+		return nil, fmt.Errorf("query latest rate summaries: %w", err)
+	}
+	defer rows.Close()
+
+	var summaries []entity.LatestRateSummary
+	for rows.Next() {
+		var s entity.LatestRateSummary
+		if err := rows.Scan(&s.Source, &s.LatestDate, &s.FetchedAt); err != nil {
+			logger.WithError(err).Error("Failed to scan latest rate summary row")
+			return nil, fmt.Errorf("scan row: %w", err)
+		}
+		summaries = append(summaries, s)
+	}
+	if err := rows.Err(); err != nil {
+		logger.WithError(err).Error("Failed to iterate latest rate summary rows")
+		return nil, fmt.Errorf("iterate rows: %w", err)
+	}
+	return summaries, nil
+}
+
+func (r *PostgresRepo) GetRateByCharCodeAndDate(ctx context.Context, source, charCode, date string) (*entity.Currency, error) {
+	logger := r.logger.WithContext(ctx)
+	logger.WithFields(logrus.Fields{"source": source, "char_code": charCode, "date": date}).Info("Getting currency rate published on or before date")
+	query, args, err := psql.
+		Select(rateColumns...).
+		From("rates").
+		Where(sq.Eq{"source": source, "char_code": strings.ToUpper(charCode)}).
+		Where(sq.LtOrEq{"effective_date": date}).
+		OrderBy("effective_date DESC").
+		Limit(1).
+		ToSql()
+	if err != nil {
+		logger.WithError(err).Error("Failed to build select query for historical rate")
+		return nil, fmt.Errorf("build select: %w", err)
+	}
+
+	rate, err := scanRate(r.pool.QueryRow(ctx, query, args...))
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
+			logger.WithFields(logrus.Fields{"char_code": charCode, "date": date}).Debug("Historical rate not found in DB")
 			return nil, ErrNotFound
 		}
-		r.logger.WithError(err).WithFields(logrus.Fields{"char_code": charCode}).Error("Failed to query historical rate")
-		return nil, fmt.Errorf("query historical rate: %w", err)
+		logger.WithError(err).WithFields(logrus.Fields{"char_code": charCode, "date": date}).Error("Failed to query historical rate")
+		return nil, fmt.Errorf("query scan: %w", err)
+	}
+	logger.WithFields(logrus.Fields{
+		"char_code":      rate.CharCode,
+		"value":          rate.Value,
+		"nominal":        rate.Nominal,
+		"effective_date": rate.Date.Format("2006-01-02"),
+	}).Info("Successfully retrieved historical currency rate")
+	return rate, nil
+}
+
+// GetRatesByCharCodesAndDate returns, per char code, the latest publication on
+// or before date; codes without one are left out.
+func (r *PostgresRepo) GetRatesByCharCodesAndDate(ctx context.Context, source string, charCodes []string, date string) ([]entity.Currency, error) {
+	logger := r.logger.WithContext(ctx)
+	fields := logrus.Fields{"source": source, "char_codes": charCodes, "date": date}
+	logger.WithFields(fields).Info("Getting currency rates published on or before date")
+
+	codes := make([]string, 0, len(charCodes))
+	for _, code := range charCodes {
+		codes = append(codes, strings.ToUpper(code))
+	}
+	query, args, err := psql.
+		Select(rateColumns...).
+		Options("DISTINCT ON (char_code)").
+		From("rates").
+		Where(sq.Eq{"source": source, "char_code": codes}).
+		Where(sq.LtOrEq{"effective_date": date}).
+		OrderBy("char_code", "effective_date DESC").
+		ToSql()
+	if err != nil {
+		logger.WithError(err).Error("Failed to build select query for historical rates")
+		return nil, fmt.Errorf("build select: %w", err)
 	}
 
-	r.logger.WithFields(logrus.Fields{
-		"char_code": rate.CharCode,
-		"name":      rate.Name,
-		"value":     rate.Value,
-		"nominal":   rate.Nominal,
-	}).Info("Successfully retrieved currency rate")
+	rows, err := r.pool.Query(ctx, query, args...)
+	if err != nil {
+		logger.WithError(err).WithFields(fields).Error("Failed to query historical rates")
+		return nil, fmt.Errorf("query historical rates: %w", err)
+	}
+	defer rows.Close()
 
+	var rates []entity.Currency
+	for rows.Next() {
+		rate, err := scanRate(rows)
+		if err != nil {
+			logger.WithError(err).Error("Failed to scan historical rate row")
+			return nil, fmt.Errorf("scan row: %w", err)
+		}
+		rates = append(rates, *rate)
+	}
+	if err := rows.Err(); err != nil {
+		logger.WithError(err).Error("Failed to iterate historical rate rows")
+		return nil, fmt.Errorf("iterate rows: %w", err)
+	}
+
+	logger.WithFields(fields).Infof("Successfully retrieved %d historical rates", len(rates))
+	return rates, nil
+}
+
+func (r *PostgresRepo) GetRatesByCharCodeAndDateRange(ctx context.Context, source, charCode, dateFrom, dateTo string) ([]entity.Currency, error) {
+	logger := r.logger.WithContext(ctx)
+	logger.WithFields(logrus.Fields{"source": source, "char_code": charCode, "from": dateFrom, "to": dateTo}).Info("Getting historical currency rates by char code and date range")
+	query, args, err := psql.
+		Select(rateColumns...).
+		From("rates").
+		Where(sq.Eq{"source": source, "char_code": strings.ToUpper(charCode)}).
+		Where(sq.GtOrEq{"effective_date": dateFrom}).
+		Where(sq.LtOrEq{"effective_date": dateTo}).
+		OrderBy("effective_date ASC").
+		ToSql()
+	if err != nil {
+		logger.WithError(err).Error("Failed to build select query for historical rates range")
+		return nil, fmt.Errorf("build select: %w", err)
+	}
+
+	rows, err := r.pool.Query(ctx, query, args...)
+	if err != nil {
+		logger.WithError(err).WithFields(logrus.Fields{"char_code": charCode, "from": dateFrom, "to": dateTo}).Error("Failed to query historical rates range")
+		return nil, fmt.Errorf("query historical rates range: %w", err)
+	}
+	defer rows.Close()
+
+	var rates []entity.Currency
+	for rows.Next() {
+		rate, err := scanRate(rows)
+		if err != nil {
+			logger.WithError(err).Error("Failed to scan historical rate row")
+			return nil, fmt.Errorf("scan row: %w", err)
+		}
+		rates = append(rates, *rate)
+	}
+	if err := rows.Err(); err != nil {
+		logger.WithError(err).Error("Failed to iterate historical rate rows")
+		return nil, fmt.Errorf("iterate rows: %w", err)
+	}
+
+	logger.WithFields(logrus.Fields{"char_code": charCode, "from": dateFrom, "to": dateTo}).Infof("Successfully retrieved %d historical rates", len(rates))
+	return rates, nil
+}
+
+func scanRate(row pgx.Row) (*entity.Currency, error) {
+	var rate entity.Currency
+	var numCode, payloadHash *string
+	if err := row.Scan(
+		&rate.Source,
+		&rate.CharCode,
+		&rate.Date,
+		&rate.Name,
+		&rate.Nominal,
+		&rate.Value,
+		&numCode,
+		&rate.UpdatedAt,
+		&payloadHash,
+	); err != nil {
+		return nil, err
+	}
+	if numCode != nil {
+		rate.NumCode = *numCode
+	}
+	if payloadHash != nil {
+		rate.PayloadHash = *payloadHash
+	}
 	return &rate, nil
 }
 
-func (r *PostgresRepo) StoreHistoricalRates(ctx context.Context, date time.Time, rates []entity.Currency) error {
-	r.logger.WithField("date", date.Format("2006-01-02")).Info("Start storing historical currency rates")
+// nullIfEmpty stores unknown text as NULL rather than an empty string.
+func nullIfEmpty(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
 
-	if len(rates) == 0 {
+func (r *PostgresRepo) StoreCurrencies(ctx context.Context, currencies []entity.CurrencyInfo) error {
+	logger := r.logger.WithContext(ctx)
+	logger.Info("Start storing currency catalog")
+
+	if len(currencies) == 0 {
 		return nil
 	}
 
 	tx, err := r.pool.Begin(ctx)
 	if err != nil {
-		r.logger.WithError(err).Error("Failed to begin transaction for historical rates")
+		logger.WithError(err).Error("Failed to begin transaction for currency catalog")
 		return fmt.Errorf("begin tx: %w", err)
 	}
 
 	batch := &pgx.Batch{}
-	for _, rate := range rates {
-		query, args, err := psql.Insert("historical_currency_rates").
-			Columns("char_code", "date", "name", "nominal", "value", "num_code").
-			Values(rate.CharCode, date, rate.Name, rate.Nominal, rate.Value, rate.NumCode).
-			Suffix("ON CONFLICT (char_code, date) DO NOTHING").
+	for _, c := range currencies {
+		query, args, err := psql.Insert("currencies").
+			Columns("cbr_id", "char_code", "num_code", "name", "eng_name", "nominal", "parent_code", "monthly", "updated_at").
+			Values(c.ID, c.CharCode, c.NumCode, c.Name, c.EngName, c.Nominal, c.ParentCode, c.Monthly, c.UpdatedAt).
+			Suffix(`
+                ON CONFLICT (cbr_id) DO UPDATE SET
+                    char_code = EXCLUDED.char_code,
+                    num_code = EXCLUDED.num_code,
+                    name = EXCLUDED.name,
+                    eng_name = EXCLUDED.eng_name,
+                    nominal = EXCLUDED.nominal,
+                    parent_code = EXCLUDED.parent_code,
+                    monthly = EXCLUDED.monthly,
+                    updated_at = EXCLUDED.updated_at
+            `).
 			ToSql()
 		if err != nil {
-			return fmt.Errorf("build insert for %s on %s: %w", rate.CharCode, date, err)
+			if rbErr := tx.Rollback(ctx); rbErr != nil {
+				logger.WithError(rbErr).Error("Failed to rollback currency catalog tx")
+			}
+			return fmt.Errorf("build insert for %s: %w", c.ID, err)
 		}
 		batch.Queue(query, args...)
 	}
@@ -162,73 +413,123 @@ func (r *PostgresRepo) StoreHistoricalRates(ctx context.Context, date time.Time,
 	br := tx.SendBatch(ctx, batch)
 
 	var batchErrs error
-	var inserted int64
 	for i := 0; i < batch.Len(); i++ {
-		ct, err := br.Exec()
+		_, err := br.Exec()
 		if err != nil {
 			batchErrs = multierr.Append(batchErrs, err)
-			r.logger.WithError(err).Errorf("Failed batch exec for historical rate %d", i)
-		} else {
-			inserted += ct.RowsAffected()
+			logger.WithError(err).Errorf("Failed batch exec for catalog item %d", i)
 		}
 	}
 
 	if err := br.Close(); err != nil {
 		batchErrs = multierr.Append(batchErrs, err)
-		r.logger.WithError(err).Error("Failed to close batch results for historical")
+		logger.WithError(err).Error("Failed to close batch results for currency catalog")
 	}
 
 	if batchErrs != nil {
 		if rbErr := tx.Rollback(ctx); rbErr != nil {
-			r.logger.WithError(rbErr).Error("Failed to rollback historical tx")
+			logger.WithError(rbErr).Error("Failed to rollback currency catalog tx")
 		}
-		return fmt.Errorf("batch exec/close errors for historical: %w", batchErrs)
+		return fmt.Errorf("batch exec/close errors for currency catalog: %w", batchErrs)
 	}
 
 	if err := tx.Commit(ctx); err != nil {
-		r.logger.WithError(err).Error("Failed to commit historical tx")
+		logger.WithError(err).Error("Failed to commit currency catalog tx")
 		return fmt.Errorf("commit tx: %w", err)
 	}
 
-	r.logger.WithField("date", date.Format("2006-01-02")).Infof("Successfully stored %d historical rates", inserted)
+	logger.Infof("Successfully stored %d catalog currencies", len(currencies))
 	return nil
 }
 
-func (r *PostgresRepo) GetRateByCharCodeAndDate(ctx context.Context, charCode, date string) (*entity.Currency, error) {
-	r.logger.WithFields(logrus.Fields{"char_code": charCode, "date": date}).Info("Getting historical currency rate by char code and date")
+func (r *PostgresRepo) GetCurrencies(ctx context.Context) ([]entity.CurrencyInfo, error) {
+	logger := r.logger.WithContext(ctx)
+	logger.Info("Getting currency catalog")
+	query, args, err := psql.
+		Select(catalogColumns...).
+		From("currencies").
+		OrderBy("char_code ASC", "monthly ASC", "cbr_id ASC").
+		ToSql()
+	if err != nil {
+		logger.WithError(err).Error("Failed to build select query for currency catalog")
+		return nil, fmt.Errorf("build select: %w", err)
+	}
+
+	rows, err := r.pool.Query(ctx, query, args...)
+	if err != nil {
+		logger.WithError(err).Error("Failed to query currency catalog")
+		return nil, fmt.Errorf("query currency catalog: %w", err)
+	}
+	defer rows.Close()
+
+	var currencies []entity.CurrencyInfo
+	for rows.Next() {
+		c, err := scanCurrencyInfo(rows)
+		if err != nil {
+			logger.WithError(err).Error("Failed to scan currency catalog row")
+			return nil, fmt.Errorf("scan row: %w", err)
+		}
+		currencies = append(currencies, *c)
+	}
+	if err := rows.Err(); err != nil {
+		logger.WithError(err).Error("Failed to iterate currency catalog rows")
+		return nil, fmt.Errorf("iterate rows: %w", err)
+	}
+
+	logger.Infof("Successfully retrieved %d catalog currencies", len(currencies))
+	return currencies, nil
+}
+
+func (r *PostgresRepo) GetCurrencyByCharCode(ctx context.Context, charCode string) (*entity.CurrencyInfo, error) {
+	logger := r.logger.WithContext(ctx)
+	logger.WithField("char_code", charCode).Info("Getting catalog currency by char code")
+	// daily-quoted entries win over monthly ones sharing the same ISO code
 	query, args, err := psql.
-		Select("char_code", "name", "nominal", "value", "date").
-		From("historical_currency_rates").
-		Where(sq.Eq{"char_code": strings.ToUpper(charCode), "date": date}).
+		Select(catalogColumns...).
+		From("currencies").
+		Where(sq.Eq{"char_code": strings.ToUpper(charCode)}).
+		OrderBy("monthly ASC", "cbr_id ASC").
 		Limit(1).
 		ToSql()
 	if err != nil {
-		r.logger.WithError(err).Error("Failed to build select query for historical rate")
+		logger.WithError(err).Error("Failed to build select query for catalog currency")
 		return nil, fmt.Errorf("build select: %w", err)
 	}
-	var rate entity.Currency
-	err = r.pool.QueryRow(ctx, query, args...).
-		Scan(
-			&rate.CharCode,
-			&rate.Name,
-			&rate.Nominal,
-			&rate.Value,
-			&rate.Date,
-		)
+
+	c, err := scanCurrencyInfo(r.pool.QueryRow(ctx, query, args...))
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
-			r.logger.WithFields(logrus.Fields{"char_code": charCode, "date": date}).Debug("Historical rate not found in DB")
+			logger.WithField("char_code", charCode).Debug("Currency not found in catalog")
 			return nil, ErrNotFound
 		}
-		r.logger.WithError(err).WithFields(logrus.Fields{"char_code": charCode, "date": date}).Error("Failed to query historical rate")
+		logger.WithError(err).WithField("char_code", charCode).Error("Failed to query catalog currency")
 		return nil, fmt.Errorf("query scan: %w", err)
 	}
-	r.logger.WithFields(logrus.Fields{
-		"char_code": rate.CharCode,
-		"name":      rate.Name,
-		"value":     rate.Value,
-		"nominal":   rate.Nominal,
-		"date":      rate.Date,
-	}).Info("Successfully retrieved historical currency rate")
-	return &rate, nil
+
+	logger.WithFields(logrus.Fields{"char_code": c.CharCode, "cbr_id": c.ID}).Info("Successfully retrieved catalog currency")
+	return c, nil
+}
+
+var catalogColumns = []string{"cbr_id", "char_code", "num_code", "name", "eng_name", "nominal", "parent_code", "monthly", "updated_at"}
+
+func scanCurrencyInfo(row pgx.Row) (*entity.CurrencyInfo, error) {
+	var c entity.CurrencyInfo
+	var numCode *string
+	if err := row.Scan(
+		&c.ID,
+		&c.CharCode,
+		&numCode,
+		&c.Name,
+		&c.EngName,
+		&c.Nominal,
+		&c.ParentCode,
+		&c.Monthly,
+		&c.UpdatedAt,
+	); err != nil {
+		return nil, err
+	}
+	if numCode != nil {
+		c.NumCode = *numCode
+	}
+	return &c, nil
 }
@@ -6,17 +6,104 @@ import (
 	"time"
 
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 )
 
+// PostgresRepository keeps rates as publications in one table keyed by
+// (source, char_code, effective_date); a rate's Date is its effective_date.
 type PostgresRepository interface {
 	StoreRates(ctx context.Context, rates []entity.Currency) error
-	GetRateByCharCode(ctx context.Context, charCode string) (*entity.Currency, error)
+	GetRateByCharCode(ctx context.Context, source, charCode string) (*entity.Currency, error)
+	// GetRateByCharCodeAndDate returns the latest publication on or before date;
+	// the caller decides whether a newer publication could be in effect on date.
+	GetRateByCharCodeAndDate(ctx context.Context, source, charCode, date string) (*entity.Currency, error)
+	// GetRatesByCharCodesAndDate does the same for several codes in one query.
+	GetRatesByCharCodesAndDate(ctx context.Context, source string, charCodes []string, date string) ([]entity.Currency, error)
+	GetRatesByCharCodeAndDateRange(ctx context.Context, source, charCode, dateFrom, dateTo string) ([]entity.Currency, error)
+	// GetLatestRateSummaries returns one summary per source with stored rates.
+	GetLatestRateSummaries(ctx context.Context) ([]entity.LatestRateSummary, error)
+	GetRateStats(ctx context.Context, source, charCode, dateFrom, dateTo string) (*entity.RateStats, error)
+	// StoreRateGaps and GetRateGaps keep the days a range answer had no rate for.
+	StoreRateGaps(ctx context.Context, source, charCode string, dates []time.Time, checkedAt time.Time) error
+	GetRateGaps(ctx context.Context, source, charCode, dateFrom, dateTo string) ([]time.Time, error)
 
-	StoreHistoricalRates(ctx context.Context, date time.Time, rates []entity.Currency) error
-	GetRateByCharCodeAndDate(ctx context.Context, charCode, date string) (*entity.Currency, error)
+	StoreCurrencies(ctx context.Context, currencies []entity.CurrencyInfo) error
+	GetCurrencies(ctx context.Context) ([]entity.CurrencyInfo, error)
+	GetCurrencyByCharCode(ctx context.Context, charCode string) (*entity.CurrencyInfo, error)
+}
+
+type BackfillRepository interface {
+	CreateBackfillJob(ctx context.Context, job *entity.BackfillJob) (int64, error)
+	UpdateBackfillJob(ctx context.Context, job *entity.BackfillJob) error
+	GetBackfillJob(ctx context.Context, id int64) (*entity.BackfillJob, error)
+	ListBackfillJobs(ctx context.Context, limit uint64) ([]entity.BackfillJob, error)
+	ListUnfinishedBackfillJobs(ctx context.Context) ([]entity.BackfillJob, error)
+}
+
+type MetalRepository interface {
+	StoreMetalPrices(ctx context.Context, prices []entity.MetalPrice) error
+	GetMetalPrice(ctx context.Context, code, date string) (*entity.MetalPrice, error)
+	GetMetalPricesByDateRange(ctx context.Context, code, dateFrom, dateTo string) ([]entity.MetalPrice, error)
+}
+
+// IndicatorRepository upserts: CBR may revise RUONIA after publication.
+type IndicatorRepository interface {
+	StoreKeyRates(ctx context.Context, rates []entity.KeyRate) error
+	GetKeyRates(ctx context.Context, dateFrom, dateTo string) ([]entity.KeyRate, error)
+	StoreRuoniaRates(ctx context.Context, rates []entity.RuoniaRate) error
+	GetRuoniaRates(ctx context.Context, dateFrom, dateTo string) ([]entity.RuoniaRate, error)
+}
+
+type DiscrepancyRepository interface {
+	StoreDiscrepancies(ctx context.Context, discrepancies []entity.RateDiscrepancy) error
+	ListDiscrepancies(ctx context.Context, dateFrom, dateTo string) ([]entity.RateDiscrepancy, error)
+}
+
+type AlertRepository interface {
+	CreateAlertRule(ctx context.Context, rule *entity.AlertRule) (int64, error)
+	UpdateAlertRule(ctx context.Context, rule *entity.AlertRule) error
+	DeleteAlertRule(ctx context.Context, id int64) error
+	GetAlertRule(ctx context.Context, id int64) (*entity.AlertRule, error)
+	ListAlertRules(ctx context.Context) ([]entity.AlertRule, error)
+	ListEnabledAlertRules(ctx context.Context, source string) ([]entity.AlertRule, error)
+
+	CreateAlert(ctx context.Context, alert *entity.Alert) (id int64, created bool, err error)
+	UpdateAlertDelivery(ctx context.Context, alert *entity.Alert) error
+	ListAlerts(ctx context.Context, dateFrom, dateTo string) ([]entity.Alert, error)
+	StoreDeadLetter(ctx context.Context, letter *entity.WebhookDeadLetter) error
+	ListDeadLetters(ctx context.Context, limit uint64) ([]entity.WebhookDeadLetter, error)
+}
+
+type HealthRepository interface {
+	Ping(ctx context.Context) error
+	// GetLatestRateDate returns ErrNotFound while no rate of the source is stored.
+	GetLatestRateDate(ctx context.Context, source string) (time.Time, error)
+}
+
+type JobRepository interface {
+	CreateJobRun(ctx context.Context, run *entity.JobRun) (int64, error)
+	UpdateJobRun(ctx context.Context, run *entity.JobRun) error
+	// FailInterruptedJobRuns closes runs of job left running by a replica
+	// that stopped mid-run; call it only while holding the job's lock.
+	FailInterruptedJobRuns(ctx context.Context, job string, at time.Time) error
+	// ListLatestJobRuns returns the most recent run of every job.
+	ListLatestJobRuns(ctx context.Context) ([]entity.JobRun, error)
+	// GetLastFinishedJobRun returns ErrNotFound while job has not finished once.
+	GetLastFinishedJobRun(ctx context.Context, job string) (*entity.JobRun, error)
+}
+
+// Locker is a lock shared by every replica using the database.
+type Locker interface {
+	// TryAcquire reports whether the lock is held without waiting for it;
+	// calling it again while holding the lock checks it is still held.
+	TryAcquire(ctx context.Context) (bool, error)
+	Release(ctx context.Context) error
 }
 
 type Pool interface {
 	Begin(ctx context.Context) (pgx.Tx, error)
+	Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error)
+	Query(ctx context.Context, query string, args ...any) (pgx.Rows, error)
 	QueryRow(ctx context.Context, query string, args ...any) pgx.Row
+	Ping(ctx context.Context) error
 }
@@ -14,6 +14,7 @@ import (
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgconn"
 	pgxmock "github.com/pashagolub/pgxmock/v4"
+	"github.com/shopspring/decimal"
 	"github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -36,27 +37,36 @@ func TestGetRateByCharCode(t *testing.T) {
 	defer mock.Close()
 
 	charCode := "USD"
+	date := time.Date(2025, 8, 2, 0, 0, 0, 0, time.UTC)
+	fetchedAt := time.Date(2025, 8, 1, 12, 0, 0, 0, time.UTC)
+	numCode := "840"
+	hash := "ab12"
 	expected := &entity.Currency{
-		CharCode: charCode,
-		Name:     "US Dollar",
-		Nominal:  1,
-		Value:    90.5,
+		Source:      "cbr",
+		CharCode:    charCode,
+		Name:        "US Dollar",
+		Nominal:     1,
+		Value:       decimal.RequireFromString("90.5"),
+		NumCode:     numCode,
+		Date:        date,
+		UpdatedAt:   fetchedAt,
+		PayloadHash: hash,
 	}
 
 	query, args, err := psql.
-		Select("char_code", "name", "nominal", "value").
-		From("currency_rates").
-		Where(squirrel.Eq{"char_code": charCode}).
+		Select(rateColumns...).
+		From("latest_rates").
+		Where(squirrel.Eq{"source": "cbr", "char_code": charCode}).
 		Limit(1).
 		ToSql()
 	require.NoError(t, err)
 
 	mock.ExpectQuery(regexp.QuoteMeta(query)).
 		WithArgs(args...).
-		WillReturnRows(pgxmock.NewRows([]string{"char_code", "name", "nominal", "value"}).
-			AddRow(expected.CharCode, expected.Name, expected.Nominal, expected.Value))
+		WillReturnRows(pgxmock.NewRows(rateColumns).
+			AddRow(expected.Source, expected.CharCode, date, expected.Name, expected.Nominal, expected.Value, &numCode, fetchedAt, &hash))
 
-	result, err := repo.GetRateByCharCode(ctx, charCode)
+	result, err := repo.GetRateByCharCode(ctx, "cbr", charCode)
 	assert.NoError(t, err)
 	assert.Equal(t, expected, result)
 	assert.NoError(t, mock.ExpectationsWereMet())
@@ -70,9 +80,9 @@ func TestGetRateByCharCode_NotFound(t *testing.T) {
 	charCode := "USD"
 
 	query, args, err := psql.
-		Select("char_code", "name", "nominal", "value").
-		From("currency_rates").
-		Where(squirrel.Eq{"char_code": charCode}).
+		Select(rateColumns...).
+		From("latest_rates").
+		Where(squirrel.Eq{"source": "cbr", "char_code": charCode}).
 		Limit(1).
 		ToSql()
 	require.NoError(t, err)
@@ -81,7 +91,7 @@ func TestGetRateByCharCode_NotFound(t *testing.T) {
 		WithArgs(args...).
 		WillReturnError(pgx.ErrNoRows)
 
-	result, err := repo.GetRateByCharCode(ctx, charCode)
+	result, err := repo.GetRateByCharCode(ctx, "cbr", charCode)
 	assert.Nil(t, result)
 	assert.Equal(t, ErrNotFound, err)
 	assert.NoError(t, mock.ExpectationsWereMet())
@@ -95,9 +105,9 @@ func TestGetRateByCharCode_Error(t *testing.T) {
 	charCode := "USD"
 
 	query, args, err := psql.
-		Select("char_code", "name", "nominal", "value").
-		From("currency_rates").
-		Where(squirrel.Eq{"char_code": charCode}).
+		Select(rateColumns...).
+		From("latest_rates").
+		Where(squirrel.Eq{"source": "cbr", "char_code": charCode}).
 		Limit(1).
 		ToSql()
 	require.NoError(t, err)
@@ -107,61 +117,106 @@ func TestGetRateByCharCode_Error(t *testing.T) {
 		WithArgs(args...).
 		WillReturnError(expectedErr)
 
-	result, err := repo.GetRateByCharCode(ctx, charCode)
+	result, err := repo.GetRateByCharCode(ctx, "cbr", charCode)
 	assert.Nil(t, result)
 	assert.ErrorContains(t, err, expectedErr.Error())
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
-func TestStoreRates(t *testing.T) {
-	ctx := context.Background()
-	repo, mock := setupTestRepo(t)
-	defer mock.Close()
-
-	now := time.Now().UTC()
-	rates := []entity.Currency{
+func sampleRates() []entity.Currency {
+	date := time.Date(2025, 8, 2, 0, 0, 0, 0, time.UTC)
+	now := time.Date(2025, 8, 1, 12, 0, 0, 0, time.UTC)
+	return []entity.Currency{
 		{
-			CharCode:  "USD",
-			Name:      "US Dollar",
-			Nominal:   1,
-			Value:     90.5,
-			NumCode:   "840",
-			UpdatedAt: now,
+			Source:      "cbr",
+			CharCode:    "USD",
+			Name:        "US Dollar",
+			Nominal:     1,
+			Value:       decimal.RequireFromString("90.5"),
+			NumCode:     "840",
+			Date:        date,
+			UpdatedAt:   now,
+			PayloadHash: "ab12",
 		},
 		{
+			Source:    "ecb",
 			CharCode:  "EUR",
 			Name:      "Euro",
 			Nominal:   1,
-			Value:     100.2,
+			Value:     decimal.RequireFromString("100.2"),
 			NumCode:   "978",
+			Date:      date.AddDate(0, 0, -1),
 			UpdatedAt: now,
 		},
 	}
+}
 
-	mock.ExpectBegin()
-
-	eb := mock.ExpectBatch()
-
-	for _, rate := range rates {
-		query, args, err := psql.Insert("currency_rates").
-			Columns("char_code", "name", "nominal", "value", "num_code", "updated_at").
-			Values(rate.CharCode, rate.Name, rate.Nominal, rate.Value, rate.NumCode, rate.UpdatedAt).
-			Suffix(`
-                ON CONFLICT (char_code) DO UPDATE SET
+func expectRateUpsert(t *testing.T, eb *pgxmock.ExpectedBatch, rate entity.Currency) *pgxmock.ExpectedExec {
+	t.Helper()
+	var hash *string
+	if rate.PayloadHash != "" {
+		hash = &rate.PayloadHash
+	}
+	query, args, err := psql.Insert("rates").
+		Columns(rateColumns...).
+		Values(rate.Source, rate.CharCode, rate.Date, rate.Name, rate.Nominal, rate.Value, rate.NumCode, rate.UpdatedAt, hash).
+		Suffix(`
+                ON CONFLICT (source, char_code, effective_date) DO UPDATE SET
                     name = EXCLUDED.name,
                     nominal = EXCLUDED.nominal,
                     value = EXCLUDED.value,
                     num_code = EXCLUDED.num_code,
-                    updated_at = EXCLUDED.updated_at
+                    fetched_at = EXCLUDED.fetched_at,
+                    payload_hash = EXCLUDED.payload_hash
+                WHERE rates.payload_hash IS DISTINCT FROM EXCLUDED.payload_hash
             `).
-			ToSql()
-		require.NoError(t, err)
+		ToSql()
+	require.NoError(t, err)
 
-		eb.ExpectExec(regexp.QuoteMeta(query)).
-			WithArgs(args...).
-			WillReturnResult(pgconn.NewCommandTag("INSERT 0 1"))
+	return eb.ExpectExec(regexp.QuoteMeta(query)).WithArgs(args...)
+}
+
+func expectLatestRateUpsert(t *testing.T, eb *pgxmock.ExpectedBatch, rate entity.Currency) *pgxmock.ExpectedExec {
+	t.Helper()
+	query, args, err := latestRateUpsert(rate)
+	require.NoError(t, err)
+	return eb.ExpectExec(regexp.QuoteMeta(query)).WithArgs(args...)
+}
+
+func TestStoreRates(t *testing.T) {
+	ctx := context.Background()
+	repo, mock := setupTestRepo(t)
+	defer mock.Close()
+
+	rates := sampleRates()
+
+	mock.ExpectBegin()
+	eb := mock.ExpectBatch()
+	for _, rate := range rates {
+		expectRateUpsert(t, eb, rate).WillReturnResult(pgconn.NewCommandTag("INSERT 0 1"))
+		expectLatestRateUpsert(t, eb, rate).WillReturnResult(pgconn.NewCommandTag("INSERT 0 1"))
 	}
+	mock.ExpectCommit()
+
+	err := repo.StoreRates(ctx, rates)
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
 
+func TestStoreRates_Unchanged(t *testing.T) {
+	ctx := context.Background()
+	repo, mock := setupTestRepo(t)
+	defer mock.Close()
+
+	rates := sampleRates()
+
+	mock.ExpectBegin()
+	eb := mock.ExpectBatch()
+	for _, rate := range rates {
+		// same payload hash: ON CONFLICT ... WHERE leaves the rows alone
+		expectRateUpsert(t, eb, rate).WillReturnResult(pgconn.NewCommandTag("INSERT 0 0"))
+		expectLatestRateUpsert(t, eb, rate).WillReturnResult(pgconn.NewCommandTag("INSERT 0 0"))
+	}
 	mock.ExpectCommit()
 
 	err := repo.StoreRates(ctx, rates)
@@ -174,109 +229,142 @@ func TestStoreRates_ErrorInBatch(t *testing.T) {
 	repo, mock := setupTestRepo(t)
 	defer mock.Close()
 
-	now := time.Now().UTC()
-	rates := []entity.Currency{
-		{
-			CharCode:  "USD",
-			Name:      "US Dollar",
-			Nominal:   1,
-			Value:     90.5,
-			NumCode:   "840",
-			UpdatedAt: now,
-		},
-		{
-			CharCode:  "EUR",
-			Name:      "Euro",
-			Nominal:   1,
-			Value:     100.2,
-			NumCode:   "978",
-			UpdatedAt: now,
-		},
-	}
+	rates := sampleRates()
 
 	mock.ExpectBegin()
-
 	eb := mock.ExpectBatch()
 
 	// First insert succeeds
-	query1, args1, err := psql.Insert("currency_rates").
-		Columns("char_code", "name", "nominal", "value", "num_code", "updated_at").
-		Values(rates[0].CharCode, rates[0].Name, rates[0].Nominal, rates[0].Value, rates[0].NumCode, rates[0].UpdatedAt).
-		Suffix(`
-                ON CONFLICT (char_code) DO UPDATE SET
-                    name = EXCLUDED.name,
-                    nominal = EXCLUDED.nominal,
-                    value = EXCLUDED.value,
-                    num_code = EXCLUDED.num_code,
-                    updated_at = EXCLUDED.updated_at
-            `).
-		ToSql()
-	require.NoError(t, err)
-
-	eb.ExpectExec(regexp.QuoteMeta(query1)).
-		WithArgs(args1...).
-		WillReturnResult(pgconn.NewCommandTag("INSERT 0 1"))
+	expectRateUpsert(t, eb, rates[0]).WillReturnResult(pgconn.NewCommandTag("INSERT 0 1"))
+	expectLatestRateUpsert(t, eb, rates[0]).WillReturnResult(pgconn.NewCommandTag("INSERT 0 1"))
 
 	// Second insert fails
-	query2, args2, err := psql.Insert("currency_rates").
-		Columns("char_code", "name", "nominal", "value", "num_code", "updated_at").
-		Values(rates[1].CharCode, rates[1].Name, rates[1].Nominal, rates[1].Value, rates[1].NumCode, rates[1].UpdatedAt).
-		Suffix(`
-                ON CONFLICT (char_code) DO UPDATE SET
-                    name = EXCLUDED.name,
-                    nominal = EXCLUDED.nominal,
-                    value = EXCLUDED.value,
-                    num_code = EXCLUDED.num_code,
-                    updated_at = EXCLUDED.updated_at
-            `).
-		ToSql()
-	require.NoError(t, err)
-
 	expectedErr := errors.New("insert error")
-	eb.ExpectExec(regexp.QuoteMeta(query2)).
-		WithArgs(args2...).
-		WillReturnError(expectedErr)
+	expectRateUpsert(t, eb, rates[1]).WillReturnError(expectedErr)
+	expectLatestRateUpsert(t, eb, rates[1]).WillReturnResult(pgconn.NewCommandTag("INSERT 0 1"))
 
 	mock.ExpectRollback()
 
-	err = repo.StoreRates(ctx, rates)
+	err := repo.StoreRates(ctx, rates)
 	assert.Error(t, err)
 	assert.ErrorContains(t, err, expectedErr.Error())
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
+func TestStoreRates_LatestRateError(t *testing.T) {
+	ctx := context.Background()
+	repo, mock := setupTestRepo(t)
+	defer mock.Close()
+
+	rates := sampleRates()
+
+	mock.ExpectBegin()
+	eb := mock.ExpectBatch()
+	expectedErr := errors.New("latest rate error")
+	expectRateUpsert(t, eb, rates[0]).WillReturnResult(pgconn.NewCommandTag("INSERT 0 1"))
+	expectLatestRateUpsert(t, eb, rates[0]).WillReturnError(expectedErr)
+	expectRateUpsert(t, eb, rates[1]).WillReturnResult(pgconn.NewCommandTag("INSERT 0 1"))
+	expectLatestRateUpsert(t, eb, rates[1]).WillReturnResult(pgconn.NewCommandTag("INSERT 0 1"))
+	mock.ExpectRollback()
+
+	err := repo.StoreRates(ctx, rates)
+	assert.ErrorContains(t, err, expectedErr.Error())
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestStoreRates_EmptyRates(t *testing.T) {
+	ctx := context.Background()
+	repo, mock := setupTestRepo(t)
+	defer mock.Close()
+
+	// No expectations since early return
+	err := repo.StoreRates(ctx, []entity.Currency{})
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func expectLatestRateSummaries(t *testing.T, mock pgxmock.PgxPoolIface) *pgxmock.ExpectedQuery {
+	query, args, err := psql.
+		Select("source", "MAX(effective_date)", "MAX(fetched_at)").
+		From("latest_rates").
+		GroupBy("source").
+		OrderBy("source").
+		ToSql()
+	require.NoError(t, err)
+	return mock.ExpectQuery(regexp.QuoteMeta(query)).WithArgs(args...)
+}
+
+func TestGetLatestRateSummaries(t *testing.T) {
+	ctx := context.Background()
+	repo, mock := setupTestRepo(t)
+	defer mock.Close()
+
+	cbrDate := time.Date(2025, 8, 5, 0, 0, 0, 0, time.UTC)
+	cbrFetched := time.Date(2025, 8, 4, 12, 1, 0, 0, time.UTC)
+	ecbDate := time.Date(2025, 8, 4, 0, 0, 0, 0, time.UTC)
+	ecbFetched := time.Date(2025, 8, 4, 16, 5, 0, 0, time.UTC)
+	expectLatestRateSummaries(t, mock).
+		WillReturnRows(pgxmock.NewRows([]string{"source", "max", "max"}).
+			AddRow("cbr", cbrDate, cbrFetched).
+			AddRow("ecb", ecbDate, ecbFetched))
+
+	summaries, err := repo.GetLatestRateSummaries(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []entity.LatestRateSummary{
+		{Source: "cbr", LatestDate: cbrDate, FetchedAt: cbrFetched},
+		{Source: "ecb", LatestDate: ecbDate, FetchedAt: ecbFetched},
+	}, summaries)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetLatestRateSummaries_QueryError(t *testing.T) {
+	ctx := context.Background()
+	repo, mock := setupTestRepo(t)
+	defer mock.Close()
+
+	expectLatestRateSummaries(t, mock).WillReturnError(errors.New("connection refused"))
+
+	_, err := repo.GetLatestRateSummaries(ctx)
+	assert.ErrorContains(t, err, "query latest rate summaries")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
 func TestGetRateByCharCodeAndDate(t *testing.T) {
 	ctx := context.Background()
 	repo, mock := setupTestRepo(t)
 	defer mock.Close()
 
 	charCode := "usd" // will be uppercased
-	dateStr := "2025-08-02"
-	date, err := time.Parse("2006-01-02", dateStr)
-	require.NoError(t, err)
+	dateStr := "2025-08-04"
+	published := time.Date(2025, 8, 2, 0, 0, 0, 0, time.UTC)
+	fetchedAt := time.Date(2025, 8, 1, 12, 0, 0, 0, time.UTC)
 
 	expected := &entity.Currency{
-		CharCode: "USD",
-		Name:     "US Dollar",
-		Nominal:  1,
-		Value:    90.5,
-		Date:     date,
+		Source:    "cbr",
+		CharCode:  "USD",
+		Name:      "US Dollar",
+		Nominal:   1,
+		Value:     decimal.RequireFromString("90.5"),
+		Date:      published,
+		UpdatedAt: fetchedAt,
 	}
 
 	query, args, err := psql.
-		Select("char_code", "name", "nominal", "value", "date").
-		From("historical_currency_rates").
-		Where(squirrel.Eq{"char_code": "USD", "date": dateStr}).
+		Select(rateColumns...).
+		From("rates").
+		Where(squirrel.Eq{"source": "cbr", "char_code": "USD"}).
+		Where(squirrel.LtOrEq{"effective_date": dateStr}).
+		OrderBy("effective_date DESC").
 		Limit(1).
 		ToSql()
 	require.NoError(t, err)
 
 	mock.ExpectQuery(regexp.QuoteMeta(query)).
 		WithArgs(args...).
-		WillReturnRows(pgxmock.NewRows([]string{"char_code", "name", "nominal", "value", "date"}).
-			AddRow(expected.CharCode, expected.Name, expected.Nominal, expected.Value, expected.Date))
+		WillReturnRows(pgxmock.NewRows(rateColumns).
+			AddRow(expected.Source, expected.CharCode, published, expected.Name, expected.Nominal, expected.Value, (*string)(nil), fetchedAt, (*string)(nil)))
 
-	result, err := repo.GetRateByCharCodeAndDate(ctx, charCode, dateStr)
+	result, err := repo.GetRateByCharCodeAndDate(ctx, "cbr", charCode, dateStr)
 	assert.NoError(t, err)
 	assert.Equal(t, expected, result)
 	assert.NoError(t, mock.ExpectationsWereMet())
@@ -291,9 +379,11 @@ func TestGetRateByCharCodeAndDate_NotFound(t *testing.T) {
 	dateStr := "2025-08-02"
 
 	query, args, err := psql.
-		Select("char_code", "name", "nominal", "value", "date").
-		From("historical_currency_rates").
-		Where(squirrel.Eq{"char_code": "USD", "date": dateStr}).
+		Select(rateColumns...).
+		From("rates").
+		Where(squirrel.Eq{"source": "cbr", "char_code": "USD"}).
+		Where(squirrel.LtOrEq{"effective_date": dateStr}).
+		OrderBy("effective_date DESC").
 		Limit(1).
 		ToSql()
 	require.NoError(t, err)
@@ -302,7 +392,7 @@ func TestGetRateByCharCodeAndDate_NotFound(t *testing.T) {
 		WithArgs(args...).
 		WillReturnError(pgx.ErrNoRows)
 
-	result, err := repo.GetRateByCharCodeAndDate(ctx, charCode, dateStr)
+	result, err := repo.GetRateByCharCodeAndDate(ctx, "cbr", charCode, dateStr)
 	assert.Nil(t, result)
 	assert.Equal(t, ErrNotFound, err)
 	assert.NoError(t, mock.ExpectationsWereMet())
@@ -317,9 +407,11 @@ func TestGetRateByCharCodeAndDate_Error(t *testing.T) {
 	dateStr := "2025-08-02"
 
 	query, args, err := psql.
-		Select("char_code", "name", "nominal", "value", "date").
-		From("historical_currency_rates").
-		Where(squirrel.Eq{"char_code": "USD", "date": dateStr}).
+		Select(rateColumns...).
+		From("rates").
+		Where(squirrel.Eq{"source": "cbr", "char_code": "USD"}).
+		Where(squirrel.LtOrEq{"effective_date": dateStr}).
+		OrderBy("effective_date DESC").
 		Limit(1).
 		ToSql()
 	require.NoError(t, err)
@@ -329,44 +421,129 @@ func TestGetRateByCharCodeAndDate_Error(t *testing.T) {
 		WithArgs(args...).
 		WillReturnError(expectedErr)
 
-	result, err := repo.GetRateByCharCodeAndDate(ctx, charCode, dateStr)
+	result, err := repo.GetRateByCharCodeAndDate(ctx, "cbr", charCode, dateStr)
 	assert.Nil(t, result)
 	assert.ErrorContains(t, err, expectedErr.Error())
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
-func TestStoreHistoricalRates(t *testing.T) {
+func TestGetRatesByCharCodesAndDate(t *testing.T) {
 	ctx := context.Background()
 	repo, mock := setupTestRepo(t)
 	defer mock.Close()
 
-	date := time.Date(2025, 8, 2, 0, 0, 0, 0, time.UTC)
-	rates := []entity.Currency{
-		{
-			CharCode: "USD",
-			Name:     "US Dollar",
-			Nominal:  1,
-			Value:    90.5,
-			NumCode:  "840",
-		},
-		{
-			CharCode: "EUR",
-			Name:     "Euro",
-			Nominal:  1,
-			Value:    100.2,
-			NumCode:  "978",
-		},
+	eurDate := time.Date(2025, 8, 2, 0, 0, 0, 0, time.UTC)
+	usdDate := time.Date(2025, 8, 1, 0, 0, 0, 0, time.UTC)
+	fetchedAt := time.Date(2025, 8, 2, 15, 0, 0, 0, time.UTC)
+
+	query, args, err := psql.
+		Select(rateColumns...).
+		Options("DISTINCT ON (char_code)").
+		From("rates").
+		Where(squirrel.Eq{"source": "cbr", "char_code": []string{"USD", "EUR"}}).
+		Where(squirrel.LtOrEq{"effective_date": "2025-08-03"}).
+		OrderBy("char_code", "effective_date DESC").
+		ToSql()
+	require.NoError(t, err)
+
+	mock.ExpectQuery(regexp.QuoteMeta(query)).
+		WithArgs(args...).
+		WillReturnRows(pgxmock.NewRows(rateColumns).
+			AddRow("cbr", "EUR", eurDate, "Euro", 1, "92.1", (*string)(nil), fetchedAt, (*string)(nil)).
+			AddRow("cbr", "USD", usdDate, "US Dollar", 1, "79.7653", (*string)(nil), fetchedAt, (*string)(nil)))
+
+	result, err := repo.GetRatesByCharCodesAndDate(ctx, "cbr", []string{"usd", "EUR"}, "2025-08-03")
+	require.NoError(t, err)
+	require.Len(t, result, 2)
+	assert.Equal(t, "EUR", result[0].CharCode)
+	assert.Equal(t, eurDate, result[0].Date)
+	assert.Equal(t, usdDate, result[1].Date)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetRatesByCharCodeAndDateRange(t *testing.T) {
+	ctx := context.Background()
+	repo, mock := setupTestRepo(t)
+	defer mock.Close()
+
+	from := "2025-08-01"
+	to := "2025-08-02"
+	day1 := time.Date(2025, 8, 1, 0, 0, 0, 0, time.UTC)
+	day2 := time.Date(2025, 8, 2, 0, 0, 0, 0, time.UTC)
+	fetchedAt := time.Date(2025, 8, 2, 15, 0, 0, 0, time.UTC)
+	numCode := "840"
+	hash := "ab12"
+
+	query, args, err := psql.
+		Select(rateColumns...).
+		From("rates").
+		Where(squirrel.Eq{"source": "ecb", "char_code": "USD"}).
+		Where(squirrel.GtOrEq{"effective_date": from}).
+		Where(squirrel.LtOrEq{"effective_date": to}).
+		OrderBy("effective_date ASC").
+		ToSql()
+	require.NoError(t, err)
+
+	mock.ExpectQuery(regexp.QuoteMeta(query)).
+		WithArgs(args...).
+		WillReturnRows(pgxmock.NewRows(rateColumns).
+			AddRow("ecb", "USD", day1, "US Dollar", 1, "90.5", &numCode, fetchedAt, &hash).
+			AddRow("ecb", "USD", day2, "US Dollar", 1, "91", (*string)(nil), fetchedAt, (*string)(nil)))
+
+	result, err := repo.GetRatesByCharCodeAndDateRange(ctx, "ecb", "usd", from, to)
+	require.NoError(t, err)
+	require.Len(t, result, 2)
+	assert.Equal(t, entity.Currency{CharCode: "USD", Name: "US Dollar", Nominal: 1, Value: decimal.RequireFromString("90.5"), NumCode: "840", Date: day1, UpdatedAt: fetchedAt, Source: "ecb", PayloadHash: hash}, result[0])
+	assert.Equal(t, entity.Currency{CharCode: "USD", Name: "US Dollar", Nominal: 1, Value: decimal.RequireFromString("91"), Date: day2, UpdatedAt: fetchedAt, Source: "ecb"}, result[1])
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetRatesByCharCodeAndDateRange_Error(t *testing.T) {
+	ctx := context.Background()
+	repo, mock := setupTestRepo(t)
+	defer mock.Close()
+
+	from := "2025-08-01"
+	to := "2025-08-02"
+
+	query, args, err := psql.
+		Select(rateColumns...).
+		From("rates").
+		Where(squirrel.Eq{"source": "ecb", "char_code": "USD"}).
+		Where(squirrel.GtOrEq{"effective_date": from}).
+		Where(squirrel.LtOrEq{"effective_date": to}).
+		OrderBy("effective_date ASC").
+		ToSql()
+	require.NoError(t, err)
+
+	expectedErr := errors.New("database error")
+	mock.ExpectQuery(regexp.QuoteMeta(query)).
+		WithArgs(args...).
+		WillReturnError(expectedErr)
+
+	result, err := repo.GetRatesByCharCodeAndDateRange(ctx, "ecb", "USD", from, to)
+	assert.Nil(t, result)
+	assert.ErrorContains(t, err, expectedErr.Error())
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestStoreCurrencies(t *testing.T) {
+	ctx := context.Background()
+	repo, mock := setupTestRepo(t)
+	defer mock.Close()
+
+	now := time.Now().UTC()
+	currencies := []entity.CurrencyInfo{
+		{ID: "R01235", CharCode: "USD", NumCode: "840", Name: "Доллар США", EngName: "US Dollar", Nominal: 1, ParentCode: "R01235", UpdatedAt: now},
+		{ID: "R01010", CharCode: "AUD", NumCode: "36", Name: "Австралийский доллар", EngName: "Australian Dollar", Nominal: 1, ParentCode: "R01010", UpdatedAt: now},
 	}
 
 	mock.ExpectBegin()
-
 	eb := mock.ExpectBatch()
-
-	for _, rate := range rates {
-		query, args, err := psql.Insert("historical_currency_rates").
-			Columns("char_code", "date", "name", "nominal", "value", "num_code").
-			Values(rate.CharCode, date, rate.Name, rate.Nominal, rate.Value, rate.NumCode).
-			Suffix("ON CONFLICT (char_code, date) DO NOTHING").
+	for _, c := range currencies {
+		query, args, err := psql.Insert("currencies").
+			Columns(catalogColumns...).
+			Values(c.ID, c.CharCode, c.NumCode, c.Name, c.EngName, c.Nominal, c.ParentCode, c.Monthly, c.UpdatedAt).
 			ToSql()
 		require.NoError(t, err)
 
@@ -374,84 +551,101 @@ func TestStoreHistoricalRates(t *testing.T) {
 			WithArgs(args...).
 			WillReturnResult(pgconn.NewCommandTag("INSERT 0 1"))
 	}
-
 	mock.ExpectCommit()
 
-	err := repo.StoreHistoricalRates(ctx, date, rates)
+	err := repo.StoreCurrencies(ctx, currencies)
 	assert.NoError(t, err)
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
-func TestStoreHistoricalRates_ErrorInBatch(t *testing.T) {
+func TestStoreCurrencies_Empty(t *testing.T) {
 	ctx := context.Background()
 	repo, mock := setupTestRepo(t)
 	defer mock.Close()
 
-	date := time.Date(2025, 8, 2, 0, 0, 0, 0, time.UTC)
-	rates := []entity.Currency{
-		{
-			CharCode: "USD",
-			Name:     "US Dollar",
-			Nominal:  1,
-			Value:    90.5,
-			NumCode:  "840",
-		},
-		{
-			CharCode: "EUR",
-			Name:     "Euro",
-			Nominal:  1,
-			Value:    100.2,
-			NumCode:  "978",
-		},
-	}
+	err := repo.StoreCurrencies(ctx, nil)
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
 
-	mock.ExpectBegin()
+func TestGetCurrencies(t *testing.T) {
+	ctx := context.Background()
+	repo, mock := setupTestRepo(t)
+	defer mock.Close()
 
-	eb := mock.ExpectBatch()
+	now := time.Now().UTC()
+	numCode := "840"
 
-	// First insert succeeds
-	query1, args1, err := psql.Insert("historical_currency_rates").
-		Columns("char_code", "date", "name", "nominal", "value", "num_code").
-		Values(rates[0].CharCode, date, rates[0].Name, rates[0].Nominal, rates[0].Value, rates[0].NumCode).
-		Suffix("ON CONFLICT (char_code, date) DO NOTHING").
+	query, _, err := psql.
+		Select(catalogColumns...).
+		From("currencies").
+		OrderBy("char_code ASC", "monthly ASC", "cbr_id ASC").
 		ToSql()
 	require.NoError(t, err)
 
-	eb.ExpectExec(regexp.QuoteMeta(query1)).
-		WithArgs(args1...).
-		WillReturnResult(pgconn.NewCommandTag("INSERT 0 1"))
+	mock.ExpectQuery(regexp.QuoteMeta(query)).
+		WillReturnRows(pgxmock.NewRows(catalogColumns).
+			AddRow("R01235", "USD", &numCode, "Доллар США", "US Dollar", 1, "R01235", false, now).
+			AddRow("R01436", "XXX", (*string)(nil), "Тест", "Test", 1, "R01436", true, now))
 
-	// Second insert fails
-	query2, args2, err := psql.Insert("historical_currency_rates").
-		Columns("char_code", "date", "name", "nominal", "value", "num_code").
-		Values(rates[1].CharCode, date, rates[1].Name, rates[1].Nominal, rates[1].Value, rates[1].NumCode).
-		Suffix("ON CONFLICT (char_code, date) DO NOTHING").
-		ToSql()
+	result, err := repo.GetCurrencies(ctx)
 	require.NoError(t, err)
+	require.Len(t, result, 2)
+	assert.Equal(t, entity.CurrencyInfo{ID: "R01235", CharCode: "USD", NumCode: "840", Name: "Доллар США", EngName: "US Dollar", Nominal: 1, ParentCode: "R01235", UpdatedAt: now}, result[0])
+	assert.Empty(t, result[1].NumCode)
+	assert.True(t, result[1].Monthly)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
 
-	expectedErr := errors.New("insert error")
-	eb.ExpectExec(regexp.QuoteMeta(query2)).
-		WithArgs(args2...).
-		WillReturnError(expectedErr)
+func TestGetCurrencyByCharCode(t *testing.T) {
+	ctx := context.Background()
+	repo, mock := setupTestRepo(t)
+	defer mock.Close()
 
-	mock.ExpectRollback()
+	now := time.Now().UTC()
+	numCode := "840"
 
-	err = repo.StoreHistoricalRates(ctx, date, rates)
-	assert.Error(t, err)
-	assert.ErrorContains(t, err, expectedErr.Error())
+	query, args, err := psql.
+		Select(catalogColumns...).
+		From("currencies").
+		Where(squirrel.Eq{"char_code": "USD"}).
+		OrderBy("monthly ASC", "cbr_id ASC").
+		Limit(1).
+		ToSql()
+	require.NoError(t, err)
+
+	mock.ExpectQuery(regexp.QuoteMeta(query)).
+		WithArgs(args...).
+		WillReturnRows(pgxmock.NewRows(catalogColumns).
+			AddRow("R01235", "USD", &numCode, "Доллар США", "US Dollar", 1, "R01235", false, now))
+
+	result, err := repo.GetCurrencyByCharCode(ctx, "usd")
+	require.NoError(t, err)
+	assert.Equal(t, "R01235", result.ID)
+	assert.Equal(t, "840", result.NumCode)
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
-func TestStoreHistoricalRates_EmptyRates(t *testing.T) {
+func TestGetCurrencyByCharCode_NotFound(t *testing.T) {
 	ctx := context.Background()
 	repo, mock := setupTestRepo(t)
 	defer mock.Close()
 
-	date := time.Date(2025, 8, 2, 0, 0, 0, 0, time.UTC)
-	rates := []entity.Currency{}
+	query, args, err := psql.
+		Select(catalogColumns...).
+		From("currencies").
+		Where(squirrel.Eq{"char_code": "XYZ"}).
+		OrderBy("monthly ASC", "cbr_id ASC").
+		Limit(1).
+		ToSql()
+	require.NoError(t, err)
 
-	// No expectations since early return
-	err := repo.StoreHistoricalRates(ctx, date, rates)
-	assert.NoError(t, err)
+	mock.ExpectQuery(regexp.QuoteMeta(query)).
+		WithArgs(args...).
+		WillReturnError(pgx.ErrNoRows)
+
+	result, err := repo.GetCurrencyByCharCode(ctx, "XYZ")
+	assert.Nil(t, result)
+	assert.Equal(t, ErrNotFound, err)
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
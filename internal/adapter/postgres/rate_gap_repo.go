@@ -0,0 +1,76 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/sirupsen/logrus"
+)
+
+// StoreRateGaps records days the source answered a range request for charCode
+// without a rate, e.g. holidays missing from its calendar.
+func (r *PostgresRepo) StoreRateGaps(ctx context.Context, source, charCode string, dates []time.Time, checkedAt time.Time) error {
+	logger := r.logger.WithContext(ctx)
+	if len(dates) == 0 {
+		return nil
+	}
+
+	insert := psql.Insert("rate_gaps").Columns("source", "char_code", "date", "checked_at")
+	for _, date := range dates {
+		insert = insert.Values(source, strings.ToUpper(charCode), date, checkedAt)
+	}
+	query, args, err := insert.Suffix("ON CONFLICT (source, char_code, date) DO NOTHING").ToSql()
+	if err != nil {
+		logger.WithError(err).Error("Failed to build insert query for rate gaps")
+		return fmt.Errorf("build insert: %w", err)
+	}
+
+	if _, err := r.pool.Exec(ctx, query, args...); err != nil {
+		logger.WithError(err).WithFields(logrus.Fields{"source": source, "char_code": charCode}).Error("Failed to store rate gaps")
+		return fmt.Errorf("store rate gaps: %w", err)
+	}
+
+	logger.Infof("Stored %d %s rate gaps for %s", len(dates), source, charCode)
+	return nil
+}
+
+func (r *PostgresRepo) GetRateGaps(ctx context.Context, source, charCode, dateFrom, dateTo string) ([]time.Time, error) {
+	logger := r.logger.WithContext(ctx)
+	query, args, err := psql.
+		Select("date").
+		From("rate_gaps").
+		Where(sq.Eq{"source": source, "char_code": strings.ToUpper(charCode)}).
+		Where(sq.GtOrEq{"date": dateFrom}).
+		Where(sq.LtOrEq{"date": dateTo}).
+		OrderBy("date ASC").
+		ToSql()
+	if err != nil {
+		logger.WithError(err).Error("Failed to build select query for rate gaps")
+		return nil, fmt.Errorf("build select: %w", err)
+	}
+
+	rows, err := r.pool.Query(ctx, query, args...)
+	if err != nil {
+		logger.WithError(err).WithFields(logrus.Fields{"char_code": charCode, "from": dateFrom, "to": dateTo}).Error("Failed to query rate gaps")
+		return nil, fmt.Errorf("query rate gaps: %w", err)
+	}
+	defer rows.Close()
+
+	var dates []time.Time
+	for rows.Next() {
+		var date time.Time
+		if err := rows.Scan(&date); err != nil {
+			logger.WithError(err).Error("Failed to scan rate gap row")
+			return nil, fmt.Errorf("scan row: %w", err)
+		}
+		dates = append(dates, date)
+	}
+	if err := rows.Err(); err != nil {
+		logger.WithError(err).Error("Failed to iterate rate gap rows")
+		return nil, fmt.Errorf("iterate rows: %w", err)
+	}
+	return dates, nil
+}
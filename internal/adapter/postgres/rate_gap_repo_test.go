@@ -0,0 +1,73 @@
+package postgres
+
+import (
+	"context"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/jackc/pgx/v5/pgconn"
+	pgxmock "github.com/pashagolub/pgxmock/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStoreRateGaps(t *testing.T) {
+	ctx := context.Background()
+	repo, mock := setupTestRepo(t)
+	defer mock.Close()
+
+	first := time.Date(2026, 1, 2, 0, 0, 0, 0, time.UTC)
+	second := time.Date(2026, 1, 3, 0, 0, 0, 0, time.UTC)
+	checkedAt := time.Date(2026, 1, 20, 12, 0, 0, 0, time.UTC)
+
+	query, args, err := psql.Insert("rate_gaps").
+		Columns("source", "char_code", "date", "checked_at").
+		Values("cbr", "USD", first, checkedAt).
+		Values("cbr", "USD", second, checkedAt).
+		Suffix("ON CONFLICT (source, char_code, date) DO NOTHING").
+		ToSql()
+	require.NoError(t, err)
+	mock.ExpectExec(regexp.QuoteMeta(query)).
+		WithArgs(args...).
+		WillReturnResult(pgconn.NewCommandTag("INSERT 0 2"))
+
+	err = repo.StoreRateGaps(ctx, "cbr", "usd", []time.Time{first, second}, checkedAt)
+	require.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestStoreRateGaps_Empty(t *testing.T) {
+	repo, mock := setupTestRepo(t)
+	defer mock.Close()
+
+	require.NoError(t, repo.StoreRateGaps(context.Background(), "cbr", "USD", nil, time.Now()))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetRateGaps(t *testing.T) {
+	ctx := context.Background()
+	repo, mock := setupTestRepo(t)
+	defer mock.Close()
+
+	query, args, err := psql.
+		Select("date").
+		From("rate_gaps").
+		Where(squirrel.Eq{"source": "cbr", "char_code": "USD"}).
+		Where(squirrel.GtOrEq{"date": "2026-01-01"}).
+		Where(squirrel.LtOrEq{"date": "2026-01-31"}).
+		OrderBy("date ASC").
+		ToSql()
+	require.NoError(t, err)
+
+	gap := time.Date(2026, 1, 2, 0, 0, 0, 0, time.UTC)
+	mock.ExpectQuery(regexp.QuoteMeta(query)).
+		WithArgs(args...).
+		WillReturnRows(pgxmock.NewRows([]string{"date"}).AddRow(gap))
+
+	dates, err := repo.GetRateGaps(ctx, "cbr", "USD", "2026-01-01", "2026-01-31")
+	require.NoError(t, err)
+	assert.Equal(t, []time.Time{gap}, dates)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,128 @@
+package postgres
+
+import (
+	"RnD-service/internal/entity"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/jackc/pgx/v5"
+	"github.com/sirupsen/logrus"
+)
+
+// statsColumns aggregate rateSeries. The median averages the two middle
+// values, percentile_cont would turn the numeric into a float.
+var statsColumns = []string{
+	"COUNT(*)",
+	"MIN(effective_date)",
+	"MAX(effective_date)",
+	"MIN(unit)",
+	"MAX(unit)",
+	"AVG(unit)",
+	"(percentile_disc(0.5) WITHIN GROUP (ORDER BY unit) + percentile_disc(0.5) WITHIN GROUP (ORDER BY unit DESC)) / 2",
+	"(array_agg(unit ORDER BY effective_date))[1]",
+	"(array_agg(unit ORDER BY effective_date DESC))[1]",
+	"COALESCE(stddev_samp((unit / NULLIF(prev, 0) - 1) * 100), 0)",
+}
+
+// rateSeries yields per-unit values of the publications in the range with the
+// previous publication's value alongside.
+func rateSeries(source, charCode, dateFrom, dateTo string) sq.SelectBuilder {
+	return psql.
+		Select("effective_date", "value / nominal AS unit", "LAG(value / nominal) OVER (ORDER BY effective_date) AS prev").
+		From("rates").
+		Where(sq.Eq{"source": source, "char_code": strings.ToUpper(charCode)}).
+		Where(sq.GtOrEq{"effective_date": dateFrom}).
+		Where(sq.LtOrEq{"effective_date": dateTo})
+}
+
+// periodAverages groups rateSeries by month or quarter.
+func periodAverages(series sq.SelectBuilder, period string) sq.SelectBuilder {
+	return psql.
+		Select(fmt.Sprintf("date_trunc('%s', effective_date::timestamp)::date AS period_start", period), "AVG(unit)", "COUNT(*)").
+		FromSelect(series, "s").
+		GroupBy("period_start").
+		OrderBy("period_start ASC")
+}
+
+// GetRateStats returns ErrNotFound if no publication falls within the range.
+func (r *PostgresRepo) GetRateStats(ctx context.Context, source, charCode, dateFrom, dateTo string) (*entity.RateStats, error) {
+	logger := r.logger.WithContext(ctx)
+	fields := logrus.Fields{"source": source, "char_code": charCode, "from": dateFrom, "to": dateTo}
+	logger.WithFields(fields).Info("Getting rate statistics by char code and date range")
+
+	series := rateSeries(source, charCode, dateFrom, dateTo)
+	query, args, err := psql.
+		Select(statsColumns...).
+		FromSelect(series, "s").
+		Having("COUNT(*) > 0").
+		ToSql()
+	if err != nil {
+		logger.WithError(err).Error("Failed to build rate statistics query")
+		return nil, fmt.Errorf("build select: %w", err)
+	}
+
+	stats := entity.RateStats{Source: source, CharCode: strings.ToUpper(charCode)}
+	err = r.pool.QueryRow(ctx, query, args...).Scan(
+		&stats.Count,
+		&stats.FirstDate,
+		&stats.LastDate,
+		&stats.Min,
+		&stats.Max,
+		&stats.Mean,
+		&stats.Median,
+		&stats.First,
+		&stats.Last,
+		&stats.Volatility,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		logger.WithError(err).WithFields(fields).Error("Failed to query rate statistics")
+		return nil, fmt.Errorf("query rate statistics: %w", err)
+	}
+
+	if stats.Monthly, err = r.getPeriodAverages(ctx, series, "month"); err != nil {
+		return nil, err
+	}
+	if stats.Quarterly, err = r.getPeriodAverages(ctx, series, "quarter"); err != nil {
+		return nil, err
+	}
+
+	logger.WithFields(fields).Infof("Successfully computed rate statistics over %d publications", stats.Count)
+	return &stats, nil
+}
+
+func (r *PostgresRepo) getPeriodAverages(ctx context.Context, series sq.SelectBuilder, period string) ([]entity.PeriodAverage, error) {
+	logger := r.logger.WithContext(ctx)
+	query, args, err := periodAverages(series, period).ToSql()
+	if err != nil {
+		logger.WithError(err).Errorf("Failed to build %s averages query", period)
+		return nil, fmt.Errorf("build select: %w", err)
+	}
+
+	rows, err := r.pool.Query(ctx, query, args...)
+	if err != nil {
+		logger.WithError(err).Errorf("Failed to query %s averages", period)
+		return nil, fmt.Errorf("query %s averages: %w", period, err)
+	}
+	defer rows.Close()
+
+	var averages []entity.PeriodAverage
+	for rows.Next() {
+		var a entity.PeriodAverage
+		if err := rows.Scan(&a.Start, &a.Average, &a.Count); err != nil {
+			logger.WithError(err).Errorf("Failed to scan %s average row", period)
+			return nil, fmt.Errorf("scan row: %w", err)
+		}
+		averages = append(averages, a)
+	}
+	if err := rows.Err(); err != nil {
+		logger.WithError(err).Errorf("Failed to iterate %s average rows", period)
+		return nil, fmt.Errorf("iterate rows: %w", err)
+	}
+	return averages, nil
+}
@@ -0,0 +1,97 @@
+package postgres
+
+import (
+	"context"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/Masterminds/squirrel"
+	pgxmock "github.com/pashagolub/pgxmock/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func statsSeries() squirrel.SelectBuilder {
+	return psql.
+		Select("effective_date", "value / nominal AS unit", "LAG(value / nominal) OVER (ORDER BY effective_date) AS prev").
+		From("rates").
+		Where(squirrel.Eq{"source": "cbr", "char_code": "USD"}).
+		Where(squirrel.GtOrEq{"effective_date": "2025-01-01"}).
+		Where(squirrel.LtOrEq{"effective_date": "2025-06-30"})
+}
+
+func expectPeriodAverages(t *testing.T, mock pgxmock.PgxPoolIface, period string, rows *pgxmock.Rows) {
+	query, args, err := psql.
+		Select("date_trunc('"+period+"', effective_date::timestamp)::date AS period_start", "AVG(unit)", "COUNT(*)").
+		FromSelect(statsSeries(), "s").
+		GroupBy("period_start").
+		OrderBy("period_start ASC").
+		ToSql()
+	require.NoError(t, err)
+	mock.ExpectQuery(regexp.QuoteMeta(query)).WithArgs(args...).WillReturnRows(rows)
+}
+
+func TestGetRateStats(t *testing.T) {
+	ctx := context.Background()
+	repo, mock := setupTestRepo(t)
+	defer mock.Close()
+
+	query, args, err := psql.
+		Select(statsColumns...).
+		FromSelect(statsSeries(), "s").
+		Having("COUNT(*) > 0").
+		ToSql()
+	require.NoError(t, err)
+
+	first := time.Date(2025, 1, 11, 0, 0, 0, 0, time.UTC)
+	last := time.Date(2025, 6, 28, 0, 0, 0, 0, time.UTC)
+	mock.ExpectQuery(regexp.QuoteMeta(query)).
+		WithArgs(args...).
+		WillReturnRows(pgxmock.NewRows([]string{"count", "min_date", "max_date", "min", "max", "avg", "median", "first", "last", "volatility"}).
+			AddRow(115, first, last, "78.1", "101.6797", "89.35", "88.7", "101.6797", "78.5", "0.61"))
+
+	january := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	april := time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC)
+	expectPeriodAverages(t, mock, "month", pgxmock.NewRows([]string{"period_start", "avg", "count"}).
+		AddRow(january, "100.5", 15).
+		AddRow(time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC), "97.2", 20))
+	expectPeriodAverages(t, mock, "quarter", pgxmock.NewRows([]string{"period_start", "avg", "count"}).
+		AddRow(january, "95.1", 55).
+		AddRow(april, "82.4", 60))
+
+	stats, err := repo.GetRateStats(ctx, "cbr", "usd", "2025-01-01", "2025-06-30")
+	require.NoError(t, err)
+	assert.Equal(t, "USD", stats.CharCode)
+	assert.Equal(t, 115, stats.Count)
+	assert.Equal(t, first, stats.FirstDate)
+	assert.Equal(t, "88.7", stats.Median.String())
+	assert.Equal(t, "0.61", stats.Volatility.String())
+	require.Len(t, stats.Monthly, 2)
+	assert.Equal(t, "97.2", stats.Monthly[1].Average.String())
+	require.Len(t, stats.Quarterly, 2)
+	assert.Equal(t, april, stats.Quarterly[1].Start)
+	assert.Equal(t, 60, stats.Quarterly[1].Count)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetRateStats_NoPublications(t *testing.T) {
+	ctx := context.Background()
+	repo, mock := setupTestRepo(t)
+	defer mock.Close()
+
+	query, args, err := psql.
+		Select(statsColumns...).
+		FromSelect(statsSeries(), "s").
+		Having("COUNT(*) > 0").
+		ToSql()
+	require.NoError(t, err)
+
+	mock.ExpectQuery(regexp.QuoteMeta(query)).
+		WithArgs(args...).
+		WillReturnRows(pgxmock.NewRows([]string{"count"}))
+
+	_, err = repo.GetRateStats(ctx, "cbr", "USD", "2025-01-01", "2025-06-30")
+	assert.ErrorIs(t, err, ErrNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,112 @@
+package provider
+
+import "time"
+
+type weekdayCalendar struct {
+	weekdays map[time.Weekday]bool
+	holiday  func(date time.Time) bool
+	// daysAhead is how long before its date a publication is released
+	daysAhead int
+}
+
+func (c weekdayCalendar) IsPublicationDay(date time.Time) bool {
+	if !c.weekdays[date.Weekday()] {
+		return false
+	}
+	return c.holiday == nil || !c.holiday(date)
+}
+
+func (c weekdayCalendar) PublishedOn(date time.Time) time.Time {
+	return date.AddDate(0, 0, -c.daysAhead)
+}
+
+// CBRCalendar: CBR sets rates on working days for the next day, so rates are
+// dated Tuesday to Saturday and Saturday's rate holds on Sunday and Monday.
+// A date after a Russian public holiday has no rates either; days off moved
+// by government decree each year are not listed.
+func CBRCalendar() Calendar {
+	return weekdayCalendar{
+		weekdays: map[time.Weekday]bool{
+			time.Tuesday: true, time.Wednesday: true, time.Thursday: true, time.Friday: true, time.Saturday: true,
+		},
+		holiday: func(date time.Time) bool {
+			return isRussianHoliday(date.AddDate(0, 0, -1))
+		},
+		daysAhead: 1,
+	}
+}
+
+// TARGETCalendar: ECB publishes reference rates on TARGET2 business days.
+func TARGETCalendar() Calendar {
+	return weekdayCalendar{
+		weekdays: map[time.Weekday]bool{
+			time.Monday: true, time.Tuesday: true, time.Wednesday: true, time.Thursday: true, time.Friday: true,
+		},
+		holiday: isTARGETHoliday,
+	}
+}
+
+// NBKCalendar: the National Bank of Kazakhstan sets official rates on working
+// days; weekend dates carry no rates. Kazakh holidays are not listed.
+func NBKCalendar() Calendar {
+	return weekdayCalendar{
+		weekdays: map[time.Weekday]bool{
+			time.Monday: true, time.Tuesday: true, time.Wednesday: true, time.Thursday: true, time.Friday: true,
+		},
+	}
+}
+
+// isRussianHoliday reports the public holidays of the Russian Labour Code:
+// the New Year break of 1-8 January, 23 February, 8 March, 1 and 9 May,
+// 12 June and 4 November.
+func isRussianHoliday(date time.Time) bool {
+	switch date.Month() {
+	case time.January:
+		return date.Day() <= 8
+	case time.February:
+		return date.Day() == 23
+	case time.March:
+		return date.Day() == 8
+	case time.May:
+		return date.Day() == 1 || date.Day() == 9
+	case time.June:
+		return date.Day() == 12
+	case time.November:
+		return date.Day() == 4
+	}
+	return false
+}
+
+// isTARGETHoliday reports the TARGET2 closing days: New Year's Day, Good Friday,
+// Easter Monday, Labour Day and 25-26 December.
+func isTARGETHoliday(date time.Time) bool {
+	switch {
+	case date.Month() == time.January && date.Day() == 1,
+		date.Month() == time.May && date.Day() == 1,
+		date.Month() == time.December && (date.Day() == 25 || date.Day() == 26):
+		return true
+	}
+
+	easter := easterSunday(date.Year())
+	day := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.UTC)
+	return day.Equal(easter.AddDate(0, 0, -2)) || day.Equal(easter.AddDate(0, 0, 1))
+}
+
+// easterSunday uses the anonymous Gregorian algorithm.
+func easterSunday(year int) time.Time {
+	a := year % 19
+	b := year / 100
+	c := year % 100
+	d := b / 4
+	e := b % 4
+	f := (b + 8) / 25
+	g := (b - f + 1) / 3
+	h := (19*a + b - d - g + 15) % 30
+	i := c / 4
+	k := c % 4
+	l := (32 + 2*e + 2*i - h - k) % 7
+	m := (a + 11*h + 22*l) / 451
+	month := (h + l - 7*m + 114) / 31
+	day := (h+l-7*m+114)%31 + 1
+	return time.Date(year, time.Month(month), day, 0, 0, 0, 0, time.UTC)
+}
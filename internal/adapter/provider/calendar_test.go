@@ -0,0 +1,84 @@
+package provider
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEasterSunday(t *testing.T) {
+	assert.Equal(t, time.Date(2024, 3, 31, 0, 0, 0, 0, time.UTC), easterSunday(2024))
+	assert.Equal(t, time.Date(2025, 4, 20, 0, 0, 0, 0, time.UTC), easterSunday(2025))
+	assert.Equal(t, time.Date(2026, 4, 5, 0, 0, 0, 0, time.UTC), easterSunday(2026))
+}
+
+func TestTARGETCalendar(t *testing.T) {
+	cal := TARGETCalendar()
+
+	tests := []struct {
+		date time.Time
+		want bool
+	}{
+		{time.Date(2025, 8, 1, 0, 0, 0, 0, time.UTC), true},
+		{time.Date(2025, 8, 2, 0, 0, 0, 0, time.UTC), false},
+		{time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), false},
+		{time.Date(2025, 4, 18, 0, 0, 0, 0, time.UTC), false},
+		{time.Date(2025, 4, 21, 0, 0, 0, 0, time.UTC), false},
+		{time.Date(2025, 5, 1, 0, 0, 0, 0, time.UTC), false},
+		{time.Date(2025, 12, 26, 0, 0, 0, 0, time.UTC), false},
+		{time.Date(2025, 12, 24, 0, 0, 0, 0, time.UTC), true},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, cal.IsPublicationDay(tt.date), tt.date.Format("2006-01-02"))
+	}
+}
+
+func TestCBRCalendar(t *testing.T) {
+	cal := CBRCalendar()
+
+	assert.True(t, cal.IsPublicationDay(time.Date(2025, 8, 5, 0, 0, 0, 0, time.UTC)))
+	assert.True(t, cal.IsPublicationDay(time.Date(2025, 8, 2, 0, 0, 0, 0, time.UTC)))
+	assert.False(t, cal.IsPublicationDay(time.Date(2025, 8, 3, 0, 0, 0, 0, time.UTC)))
+	assert.False(t, cal.IsPublicationDay(time.Date(2025, 8, 4, 0, 0, 0, 0, time.UTC)))
+}
+
+func TestCBRCalendar_RussianHolidays(t *testing.T) {
+	cal := CBRCalendar()
+
+	tests := []struct {
+		date time.Time
+		want bool
+	}{
+		// 2025-12-31 is set on Tuesday 30 December, then nothing until Friday 9 January
+		{time.Date(2025, 12, 31, 0, 0, 0, 0, time.UTC), true},
+		{time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC), true},
+		{time.Date(2026, 1, 2, 0, 0, 0, 0, time.UTC), false},
+		{time.Date(2026, 1, 6, 0, 0, 0, 0, time.UTC), false},
+		{time.Date(2026, 1, 9, 0, 0, 0, 0, time.UTC), false},
+		{time.Date(2026, 1, 10, 0, 0, 0, 0, time.UTC), true},
+		{time.Date(2025, 6, 13, 0, 0, 0, 0, time.UTC), false},
+		{time.Date(2025, 11, 5, 0, 0, 0, 0, time.UTC), false},
+		{time.Date(2025, 11, 6, 0, 0, 0, 0, time.UTC), true},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, cal.IsPublicationDay(tt.date), tt.date.Format("2006-01-02"))
+	}
+}
+
+func TestNBKCalendar(t *testing.T) {
+	cal := NBKCalendar()
+
+	assert.True(t, cal.IsPublicationDay(time.Date(2025, 8, 1, 0, 0, 0, 0, time.UTC)))
+	assert.False(t, cal.IsPublicationDay(time.Date(2025, 8, 2, 0, 0, 0, 0, time.UTC)))
+	assert.True(t, cal.IsPublicationDay(time.Date(2025, 8, 4, 0, 0, 0, 0, time.UTC)))
+}
+
+func TestCalendar_PublishedOn(t *testing.T) {
+	saturday := time.Date(2025, 8, 2, 0, 0, 0, 0, time.UTC)
+
+	assert.Equal(t, time.Date(2025, 8, 1, 0, 0, 0, 0, time.UTC), CBRCalendar().PublishedOn(saturday), "Saturday's rates are set on Friday")
+	assert.Equal(t, time.Date(2025, 8, 1, 0, 0, 0, 0, time.UTC), TARGETCalendar().PublishedOn(time.Date(2025, 8, 1, 0, 0, 0, 0, time.UTC)))
+}
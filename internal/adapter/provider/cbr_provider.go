@@ -0,0 +1,211 @@
+package provider
+
+import (
+	"RnD-service/internal/adapter/cbr"
+	"RnD-service/internal/entity"
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"go.uber.org/multierr"
+)
+
+const (
+	SourceCBR       = "cbr"
+	SourceCBRMirror = "cbr_mirror"
+	SourceECB       = "ecb"
+	SourceNBK       = "nbk"
+)
+
+type CBRProvider struct {
+	name   string
+	client cbr.CbrClient
+	logger *logrus.Logger
+}
+
+func NewCBRProvider(client cbr.CbrClient, logger *logrus.Logger) *CBRProvider {
+	return NewCBRMirrorProvider(SourceCBR, client, logger)
+}
+
+// NewCBRMirrorProvider serves CBR publications from another endpoint, such as
+// an internal mirror, under its own source name.
+func NewCBRMirrorProvider(name string, client cbr.CbrClient, logger *logrus.Logger) *CBRProvider {
+	return &CBRProvider{
+		name:   name,
+		client: client,
+		logger: logger,
+	}
+}
+
+func (p *CBRProvider) Name() string {
+	return p.name
+}
+
+func (p *CBRProvider) BaseCurrency() string {
+	return "RUB"
+}
+
+func (p *CBRProvider) Calendar() Calendar {
+	return CBRCalendar()
+}
+
+func (p *CBRProvider) FetchDaily(ctx context.Context, date time.Time) ([]entity.Currency, error) {
+	logger := p.logger.WithContext(ctx)
+	cbrDateStr := date.Format("02/01/2006")
+	logger.Infof("Fetching %s rates for %s", p.name, cbrDateStr)
+
+	resp, err := p.client.FetchRates(ctx, cbrDateStr)
+	if err != nil {
+		return nil, fmt.Errorf("fetch CBR rates for %s: %w", cbrDateStr, err)
+	}
+
+	rates, err := convertCBRResponse(logger, *resp, date)
+	if err != nil {
+		return nil, fmt.Errorf("convert CBR rates for %s: %w", cbrDateStr, err)
+	}
+	return p.withSource(rates), nil
+}
+
+func (p *CBRProvider) FetchRange(ctx context.Context, charCode string, from, to time.Time) ([]entity.Currency, error) {
+	logger := p.logger.WithContext(ctx)
+
+	// XML_dynamic.asp expects the CBR internal valute ID, so resolve it from the daily list first
+	cbrDateStr := to.Format("02/01/2006")
+	resp, err := p.client.FetchRates(ctx, cbrDateStr)
+	if err != nil {
+		return nil, fmt.Errorf("fetch CBR rates for %s: %w", cbrDateStr, err)
+	}
+
+	var valute *cbr.Valute
+	for i := range resp.Valutes {
+		if resp.Valutes[i].CharCode == charCode {
+			valute = &resp.Valutes[i]
+			break
+		}
+	}
+	if valute == nil {
+		logger.Warnf("Currency code %s not found in CBR rates for date %s", charCode, cbrDateStr)
+		return nil, fmt.Errorf("%w: %s on %s", ErrCurrencyNotQuoted, charCode, to.Format("2006-01-02"))
+	}
+
+	dynResp, err := p.client.FetchDynamicRates(ctx, valute.ID, from.Format("02/01/2006"), cbrDateStr)
+	if err != nil {
+		return nil, fmt.Errorf("fetch CBR dynamic rates for %s: %w", valute.ID, err)
+	}
+
+	rates, err := convertCBRDynamicResponse(logger, *dynResp, *valute)
+	if err != nil {
+		return nil, fmt.Errorf("convert CBR dynamic rates for %s: %w", valute.ID, err)
+	}
+	return p.withSource(rates), nil
+}
+
+func (p *CBRProvider) withSource(rates []entity.Currency) []entity.Currency {
+	for i := range rates {
+		rates[i].Source = p.name
+	}
+	return rates
+}
+
+// convertCBRResponse dates the rates with requested when CBR omits the date.
+func convertCBRResponse(logger logrus.FieldLogger, resp cbr.ValCurs, requested time.Time) ([]entity.Currency, error) {
+	var result []entity.Currency
+	var errs []error
+
+	if len(resp.Valutes) == 0 {
+		logger.Warn("No valutes found in response")
+		return result, nil
+	}
+
+	var respDate time.Time
+	if resp.Date != "" {
+		var err error
+		respDate, err = time.Parse("02.01.2006", resp.Date)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse CBR response date '%s': %w", resp.Date, err)
+		}
+	} else {
+		respDate = requested.Truncate(24 * time.Hour)
+		logger.Warn("No date in CBR response, using requested date")
+	}
+
+	skipped := 0
+	for _, valute := range resp.Valutes {
+		value, err := valute.GetValue()
+		if err != nil {
+			logger.Debugf("Skipped %s due to parse error: %v", valute.CharCode, err)
+			skipped++
+			continue
+		}
+		if value.IsZero() {
+			logger.Debugf("Skipped %s due to zero value", valute.CharCode)
+			skipped++
+			continue
+		}
+
+		rate := entity.Currency{
+			CharCode:    valute.CharCode,
+			Name:        valute.Name,
+			Nominal:     valute.Nominal,
+			Value:       value,
+			NumCode:     valute.NumCode,
+			Date:        respDate,
+			Source:      SourceCBR,
+			PayloadHash: resp.PayloadHash,
+		}
+		result = append(result, rate)
+	}
+
+	logger.Infof("Converted %d valid rates out of %d (skipped %d)", len(result), len(resp.Valutes), skipped)
+
+	if len(result) == 0 && len(resp.Valutes) > 0 {
+		errs = append(errs, fmt.Errorf("all %d valutes were skipped", len(resp.Valutes)))
+	}
+
+	if len(errs) > 0 {
+		return result, multierr.Combine(errs...)
+	}
+	return result, nil
+}
+
+func convertCBRDynamicResponse(logger logrus.FieldLogger, resp cbr.ValCursDynamic, valute cbr.Valute) ([]entity.Currency, error) {
+	var result []entity.Currency
+
+	skipped := 0
+	for _, record := range resp.Records {
+		date, err := time.Parse("02.01.2006", record.Date)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse CBR record date '%s': %w", record.Date, err)
+		}
+		value, err := record.GetValue()
+		if err != nil {
+			logger.Debugf("Skipped %s record on %s due to parse error: %v", valute.CharCode, record.Date, err)
+			skipped++
+			continue
+		}
+		if value.IsZero() {
+			logger.Debugf("Skipped %s record on %s due to zero value", valute.CharCode, record.Date)
+			skipped++
+			continue
+		}
+
+		result = append(result, entity.Currency{
+			CharCode:    valute.CharCode,
+			Name:        valute.Name,
+			Nominal:     record.Nominal,
+			Value:       value,
+			NumCode:     valute.NumCode,
+			Date:        date,
+			Source:      SourceCBR,
+			PayloadHash: resp.PayloadHash,
+		})
+	}
+
+	logger.Infof("Converted %d valid dynamic records out of %d (skipped %d)", len(result), len(resp.Records), skipped)
+
+	if len(result) == 0 && len(resp.Records) > 0 {
+		return result, fmt.Errorf("all %d records were skipped", len(resp.Records))
+	}
+	return result, nil
+}
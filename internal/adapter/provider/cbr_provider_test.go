@@ -0,0 +1,169 @@
+package provider
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"RnD-service/internal/adapter/cbr"
+	"RnD-service/pkg/fakecbr"
+
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// setupCBRProvider replays the recorded fakecbr fixtures for 10-12 January 2023.
+func setupCBRProvider(t *testing.T) *CBRProvider {
+	logger, _ := test.NewNullLogger()
+	server, err := fakecbr.NewServer(fakecbr.Config{
+		Fixtures: fakecbr.DefaultFixtures,
+		Now:      func() time.Time { return time.Date(2023, 1, 12, 15, 0, 0, 0, time.UTC) },
+	}, logger)
+	require.NoError(t, err)
+
+	srv := httptest.NewServer(server)
+	t.Cleanup(srv.Close)
+
+	client, err := cbr.NewClient(logger, cbr.WithBaseURL(srv.URL+"/scripts"))
+	require.NoError(t, err)
+	return NewCBRProvider(client, logger)
+}
+
+func TestCBRProvider_FetchDaily(t *testing.T) {
+	p := setupCBRProvider(t)
+
+	rates, err := p.FetchDaily(context.Background(), time.Date(2023, 1, 12, 0, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+	require.Len(t, rates, 2)
+	assert.Equal(t, "USD", rates[0].CharCode)
+	assert.Equal(t, "Доллар США", rates[0].Name)
+	assert.Equal(t, "69.0202", rates[0].Value.String())
+	assert.Equal(t, time.Date(2023, 1, 12, 0, 0, 0, 0, time.UTC), rates[0].Date)
+	assert.Equal(t, SourceCBR, rates[0].Source)
+}
+
+func TestCBRProvider_FetchRange(t *testing.T) {
+	p := setupCBRProvider(t)
+
+	rates, err := p.FetchRange(context.Background(), "EUR", time.Date(2023, 1, 10, 0, 0, 0, 0, time.UTC), time.Date(2023, 1, 12, 0, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+	require.Len(t, rates, 3)
+	assert.Equal(t, "EUR", rates[0].CharCode)
+	assert.Equal(t, "74.4096", rates[0].Value.String())
+	assert.Equal(t, time.Date(2023, 1, 10, 0, 0, 0, 0, time.UTC), rates[0].Date)
+	assert.Equal(t, time.Date(2023, 1, 12, 0, 0, 0, 0, time.UTC), rates[2].Date)
+}
+
+func TestCBRProvider_FetchRange_NotQuoted(t *testing.T) {
+	p := setupCBRProvider(t)
+
+	_, err := p.FetchRange(context.Background(), "XXX", time.Date(2023, 1, 10, 0, 0, 0, 0, time.UTC), time.Date(2023, 1, 12, 0, 0, 0, 0, time.UTC))
+	assert.ErrorIs(t, err, ErrCurrencyNotQuoted)
+}
+
+func TestCBRMirrorProvider_TagsRatesWithItsName(t *testing.T) {
+	p := setupCBRProvider(t)
+	mirror := NewCBRMirrorProvider(SourceCBRMirror, p.client, p.logger)
+
+	rates, err := mirror.FetchDaily(context.Background(), time.Date(2023, 1, 12, 0, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+	require.NotEmpty(t, rates)
+	assert.Equal(t, SourceCBRMirror, mirror.Name())
+	assert.Equal(t, SourceCBRMirror, rates[0].Source)
+
+	rates, err = mirror.FetchRange(context.Background(), "EUR", time.Date(2023, 1, 10, 0, 0, 0, 0, time.UTC), time.Date(2023, 1, 12, 0, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+	require.NotEmpty(t, rates)
+	assert.Equal(t, SourceCBRMirror, rates[0].Source)
+}
+
+func TestCBRProvider_Metadata(t *testing.T) {
+	p := NewCBRProvider(nil, nil)
+
+	assert.Equal(t, "cbr", p.Name())
+	assert.Equal(t, "RUB", p.BaseCurrency())
+	assert.False(t, p.Calendar().IsPublicationDay(time.Date(2025, 8, 4, 0, 0, 0, 0, time.UTC)), "Monday uses Saturday's rates")
+	assert.True(t, p.Calendar().IsPublicationDay(time.Date(2025, 8, 2, 0, 0, 0, 0, time.UTC)))
+}
+
+func TestConvertCBRResponse(t *testing.T) {
+	logger, hook := test.NewNullLogger()
+	now := time.Now()
+	sampleResp := cbr.ValCurs{
+		Valutes: []cbr.Valute{
+			{CharCode: "USD", Name: "US Dollar", Nominal: 1, Value: "90,5", NumCode: "840"},
+			{CharCode: "EUR", Name: "Euro", Nominal: 1, Value: "100.2", NumCode: "978"},
+			{CharCode: "INVALID", Name: "Invalid", Nominal: 1, Value: "abc", NumCode: "000"},
+			{CharCode: "ZERO", Name: "Zero", Nominal: 1, Value: "0", NumCode: "000"},
+		},
+		Date: now.Format("02.01.2006"),
+	}
+
+	rates, err := convertCBRResponse(logger, sampleResp, now)
+	assert.NoError(t, err)
+	assert.Len(t, rates, 2)
+
+	assert.Equal(t, "USD", rates[0].CharCode)
+	assert.Equal(t, "90.5", rates[0].Value.String())
+	assert.Equal(t, "EUR", rates[1].CharCode)
+	assert.Equal(t, "100.2", rates[1].Value.String())
+	assert.Equal(t, "Converted 2 valid rates out of 4 (skipped 2)", hook.LastEntry().Message)
+}
+
+func TestConvertCBRResponse_NoDateUsesRequested(t *testing.T) {
+	logger, _ := test.NewNullLogger()
+	requested := time.Date(2025, 8, 1, 0, 0, 0, 0, time.UTC)
+	sampleResp := cbr.ValCurs{
+		Valutes: []cbr.Valute{
+			{CharCode: "USD", Name: "US Dollar", Nominal: 1, Value: "90,5", NumCode: "840"},
+		},
+	}
+
+	rates, err := convertCBRResponse(logger, sampleResp, requested)
+	require.NoError(t, err)
+	require.Len(t, rates, 1)
+	assert.Equal(t, requested, rates[0].Date)
+}
+
+func TestConvertCBRResponse_NoValutes(t *testing.T) {
+	logger, _ := test.NewNullLogger()
+	sampleResp := cbr.ValCurs{Valutes: []cbr.Valute{}}
+	rates, err := convertCBRResponse(logger, sampleResp, time.Now())
+	assert.NoError(t, err)
+	assert.Empty(t, rates)
+}
+
+func TestConvertCBRResponse_AllSkipped(t *testing.T) {
+	logger, _ := test.NewNullLogger()
+	sampleResp := cbr.ValCurs{
+		Valutes: []cbr.Valute{
+			{CharCode: "INVALID", Name: "Invalid", Nominal: 1, Value: "abc", NumCode: "000"},
+		},
+	}
+
+	rates, err := convertCBRResponse(logger, sampleResp, time.Now())
+	assert.Error(t, err)
+	assert.Empty(t, rates)
+}
+
+func TestConvertCBRDynamicResponse(t *testing.T) {
+	logger, _ := test.NewNullLogger()
+	valute := cbr.Valute{ID: "R01235", CharCode: "USD", Name: "US Dollar", NumCode: "840"}
+	resp := cbr.ValCursDynamic{
+		Records: []cbr.Record{
+			{Date: "01.08.2025", Nominal: 1, Value: "90,5"},
+			{Date: "02.08.2025", Nominal: 1, Value: "abc"},
+		},
+	}
+
+	rates, err := convertCBRDynamicResponse(logger, resp, valute)
+	require.NoError(t, err)
+	require.Len(t, rates, 1)
+	assert.Equal(t, "USD", rates[0].CharCode)
+	assert.Equal(t, "US Dollar", rates[0].Name)
+	assert.Equal(t, "90.5", rates[0].Value.String())
+	assert.Equal(t, time.Date(2025, 8, 1, 0, 0, 0, 0, time.UTC), rates[0].Date)
+	assert.Equal(t, SourceCBR, rates[0].Source)
+}
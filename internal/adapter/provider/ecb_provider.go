@@ -0,0 +1,195 @@
+package provider
+
+import (
+	"RnD-service/internal/adapter/ecb"
+	"RnD-service/internal/entity"
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// ecbDailyFeedDays and ecb90DaysFeedDays bound the age of dates served from
+	// the small feeds; anything older needs the full history file.
+	ecbDailyFeedDays  = 3
+	ecb90DaysFeedDays = 85
+)
+
+type ECBProvider struct {
+	client ecb.EcbClient
+	logger *logrus.Logger
+	now    func() time.Time
+}
+
+func NewECBProvider(client ecb.EcbClient, logger *logrus.Logger) *ECBProvider {
+	return &ECBProvider{
+		client: client,
+		logger: logger,
+		now:    time.Now,
+	}
+}
+
+func (p *ECBProvider) Name() string {
+	return SourceECB
+}
+
+func (p *ECBProvider) BaseCurrency() string {
+	return "EUR"
+}
+
+func (p *ECBProvider) Calendar() Calendar {
+	return TARGETCalendar()
+}
+
+// FetchDaily returns the latest reference rates published on or before date.
+func (p *ECBProvider) FetchDaily(ctx context.Context, date time.Time) ([]entity.Currency, error) {
+	date = date.Truncate(24 * time.Hour)
+
+	if p.ageInDays(date) <= ecbDailyFeedDays {
+		env, err := p.client.FetchRates(ctx, ecb.FeedDaily)
+		if err != nil {
+			return nil, fmt.Errorf("fetch ECB daily rates: %w", err)
+		}
+		day, err := latestDayCube(env, date)
+		if err != nil {
+			return nil, err
+		}
+		if day != nil {
+			return convertECBDay(*day, "", env.PayloadHash)
+		}
+		p.logger.Debugf("ECB daily feed is newer than %s, falling back to history", date.Format("2006-01-02"))
+	}
+
+	feed := p.historyFeed(date)
+	p.logger.Infof("Fetching ECB rates for %s from %s", date.Format("2006-01-02"), feed)
+	env, err := p.client.FetchRates(ctx, feed)
+	if err != nil {
+		return nil, fmt.Errorf("fetch ECB rates from %s: %w", feed, err)
+	}
+	day, err := latestDayCube(env, date)
+	if err != nil {
+		return nil, err
+	}
+	if day == nil {
+		p.logger.Warnf("No ECB rates published on or before %s", date.Format("2006-01-02"))
+		return nil, nil
+	}
+	return convertECBDay(*day, "", env.PayloadHash)
+}
+
+func (p *ECBProvider) FetchRange(ctx context.Context, charCode string, from, to time.Time) ([]entity.Currency, error) {
+	from = from.Truncate(24 * time.Hour)
+	to = to.Truncate(24 * time.Hour)
+
+	feed := p.historyFeed(from)
+	p.logger.Infof("Fetching ECB %s rates for %s - %s from %s", charCode, from.Format("2006-01-02"), to.Format("2006-01-02"), feed)
+	env, err := p.client.FetchRates(ctx, feed)
+	if err != nil {
+		return nil, fmt.Errorf("fetch ECB rates from %s: %w", feed, err)
+	}
+
+	var result []entity.Currency
+	quoted := false
+	// feeds list days newest first, callers expect ascending dates
+	for i := len(env.Days) - 1; i >= 0; i-- {
+		day := env.Days[i]
+		date, err := day.GetDate()
+		if err != nil {
+			return nil, fmt.Errorf("parse ECB cube date '%s': %w", day.Time, err)
+		}
+		rates, err := convertECBDay(day, charCode, env.PayloadHash)
+		if err != nil {
+			return nil, err
+		}
+		if len(rates) > 0 {
+			quoted = true
+		}
+		if date.Before(from) || date.After(to) {
+			continue
+		}
+		result = append(result, rates...)
+	}
+
+	if !quoted {
+		p.logger.Warnf("Currency code %s is not quoted by ECB", charCode)
+		return nil, fmt.Errorf("%w: %s", ErrCurrencyNotQuoted, charCode)
+	}
+	return result, nil
+}
+
+func (p *ECBProvider) ageInDays(date time.Time) int {
+	return int(p.now().Truncate(24*time.Hour).Sub(date).Hours() / 24)
+}
+
+func (p *ECBProvider) historyFeed(date time.Time) ecb.Feed {
+	if p.ageInDays(date) <= ecb90DaysFeedDays {
+		return ecb.Feed90Days
+	}
+	return ecb.FeedHistory
+}
+
+// latestDayCube returns the newest cube dated on or before date, or nil if
+// the feed has none.
+func latestDayCube(env *ecb.Envelope, date time.Time) (*ecb.DayCube, error) {
+	var latest *ecb.DayCube
+	var latestDate time.Time
+	for i := range env.Days {
+		d, err := env.Days[i].GetDate()
+		if err != nil {
+			return nil, fmt.Errorf("parse ECB cube date '%s': %w", env.Days[i].Time, err)
+		}
+		if d.After(date) {
+			continue
+		}
+		if latest == nil || d.After(latestDate) {
+			latest = &env.Days[i]
+			latestDate = d
+		}
+	}
+	return latest, nil
+}
+
+// convertECBDay turns "units per 1 EUR" into EUR per Nominal units, picking
+// the smallest power of ten nominal not below units (10 USD, 1000 JPY), so the
+// value is at least 1 and four decimal places keep five significant digits.
+// An empty charCode converts the whole cube.
+func convertECBDay(day ecb.DayCube, charCode, payloadHash string) ([]entity.Currency, error) {
+	date, err := day.GetDate()
+	if err != nil {
+		return nil, fmt.Errorf("parse ECB cube date '%s': %w", day.Time, err)
+	}
+
+	var result []entity.Currency
+	ten := decimal.NewFromInt(10)
+	for _, rate := range day.Rates {
+		if charCode != "" && rate.Currency != charCode {
+			continue
+		}
+		units, err := rate.GetRate()
+		if err != nil {
+			return nil, fmt.Errorf("parse ECB rate for %s on %s: %w", rate.Currency, day.Time, err)
+		}
+		if !units.IsPositive() {
+			continue
+		}
+
+		nominal := decimal.NewFromInt(1)
+		for nominal.LessThan(units) {
+			nominal = nominal.Mul(ten)
+		}
+
+		result = append(result, entity.Currency{
+			CharCode:    rate.Currency,
+			Name:        rate.Currency,
+			Nominal:     int(nominal.IntPart()),
+			Value:       nominal.DivRound(units, 4),
+			Date:        date,
+			Source:      SourceECB,
+			PayloadHash: payloadHash,
+		})
+	}
+	return result, nil
+}
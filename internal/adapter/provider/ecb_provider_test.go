@@ -0,0 +1,117 @@
+package provider
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"RnD-service/internal/adapter/ecb"
+
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// setupECBProvider serves the recorded eurofxref files from the ecb package
+// testdata; the full history file is deliberately absent.
+func setupECBProvider(t *testing.T, now time.Time) *ECBProvider {
+	srv := httptest.NewServer(http.FileServer(http.Dir("../ecb/testdata")))
+	t.Cleanup(srv.Close)
+
+	logger, _ := test.NewNullLogger()
+	client, err := ecb.NewClient(logger, ecb.WithBaseURL(srv.URL))
+	require.NoError(t, err)
+
+	p := NewECBProvider(client, logger)
+	p.now = func() time.Time { return now }
+	return p
+}
+
+func TestECBProvider_FetchDaily_Today(t *testing.T) {
+	p := setupECBProvider(t, time.Date(2025, 8, 1, 17, 0, 0, 0, time.UTC))
+
+	rates, err := p.FetchDaily(context.Background(), time.Date(2025, 8, 1, 0, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+	require.Len(t, rates, 30)
+
+	usd := rates[0]
+	assert.Equal(t, "USD", usd.CharCode)
+	assert.Equal(t, 10, usd.Nominal)
+	assert.Equal(t, "8.7689", usd.Value.String())
+	assert.Equal(t, time.Date(2025, 8, 1, 0, 0, 0, 0, time.UTC), usd.Date)
+	assert.Equal(t, SourceECB, usd.Source)
+
+	jpy := rates[1]
+	assert.Equal(t, "JPY", jpy.CharCode)
+	assert.Equal(t, 1000, jpy.Nominal)
+	assert.Equal(t, "5.8858", jpy.Value.String())
+}
+
+func TestECBProvider_FetchDaily_Weekend(t *testing.T) {
+	p := setupECBProvider(t, time.Date(2025, 8, 3, 12, 0, 0, 0, time.UTC))
+
+	rates, err := p.FetchDaily(context.Background(), time.Date(2025, 8, 3, 0, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+	require.NotEmpty(t, rates)
+	assert.Equal(t, time.Date(2025, 8, 1, 0, 0, 0, 0, time.UTC), rates[0].Date)
+}
+
+func TestECBProvider_FetchDaily_PastDateUses90DayFeed(t *testing.T) {
+	p := setupECBProvider(t, time.Date(2025, 8, 1, 17, 0, 0, 0, time.UTC))
+
+	rates, err := p.FetchDaily(context.Background(), time.Date(2025, 7, 27, 0, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+	require.NotEmpty(t, rates)
+	assert.Equal(t, time.Date(2025, 7, 25, 0, 0, 0, 0, time.UTC), rates[0].Date)
+}
+
+func TestECBProvider_FetchDaily_HistoryUnavailable(t *testing.T) {
+	p := setupECBProvider(t, time.Date(2025, 8, 1, 17, 0, 0, 0, time.UTC))
+
+	_, err := p.FetchDaily(context.Background(), time.Date(2024, 1, 5, 0, 0, 0, 0, time.UTC))
+	assert.ErrorIs(t, err, ecb.ErrUnexpectedStatus)
+}
+
+func TestECBProvider_FetchRange(t *testing.T) {
+	p := setupECBProvider(t, time.Date(2025, 8, 1, 17, 0, 0, 0, time.UTC))
+
+	rates, err := p.FetchRange(context.Background(), "USD", time.Date(2025, 7, 28, 0, 0, 0, 0, time.UTC), time.Date(2025, 7, 30, 0, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+	require.Len(t, rates, 3)
+	assert.Equal(t, time.Date(2025, 7, 28, 0, 0, 0, 0, time.UTC), rates[0].Date)
+	assert.Equal(t, time.Date(2025, 7, 30, 0, 0, 0, 0, time.UTC), rates[2].Date)
+	for _, rate := range rates {
+		assert.Equal(t, "USD", rate.CharCode)
+	}
+}
+
+func TestECBProvider_FetchRange_NotQuoted(t *testing.T) {
+	p := setupECBProvider(t, time.Date(2025, 8, 1, 17, 0, 0, 0, time.UTC))
+
+	_, err := p.FetchRange(context.Background(), "RUB", time.Date(2025, 7, 28, 0, 0, 0, 0, time.UTC), time.Date(2025, 7, 30, 0, 0, 0, 0, time.UTC))
+	assert.ErrorIs(t, err, ErrCurrencyNotQuoted)
+}
+
+func TestConvertECBDay_Nominal(t *testing.T) {
+	day := ecb.DayCube{Time: "2025-08-01", Rates: []ecb.Rate{
+		{Currency: "GBP", Rate: "0.86085"},
+		{Currency: "IDR", Rate: "18770.55"},
+		{Currency: "BGN", Rate: "1.9558"},
+		{Currency: "DKK", Rate: "10"},
+	}}
+
+	rates, err := convertECBDay(day, "", "")
+	require.NoError(t, err)
+	require.Len(t, rates, 4)
+	assert.Equal(t, 1, rates[0].Nominal)
+	assert.Equal(t, "1.1616", rates[0].Value.String())
+	assert.Equal(t, 100000, rates[1].Nominal)
+	assert.Equal(t, "5.3275", rates[1].Value.String())
+	// every value is at least 1, so NUMERIC(20, 4) keeps five significant digits
+	assert.Equal(t, 10, rates[2].Nominal)
+	assert.Equal(t, "5.113", rates[2].Value.String())
+	assert.Equal(t, 10, rates[3].Nominal)
+	assert.Equal(t, "1", rates[3].Value.String())
+}
@@ -0,0 +1,7 @@
+package provider
+
+import "errors"
+
+var (
+	ErrCurrencyNotQuoted = errors.New("currency is not quoted by the provider")
+)
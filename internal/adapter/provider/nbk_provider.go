@@ -0,0 +1,198 @@
+package provider
+
+import (
+	"RnD-service/internal/adapter/nbk"
+	"RnD-service/internal/entity"
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// nbkLookbackDays bounds the walk back from a non-working date to the last
+	// one with rates, covering the longest Kazakh holidays.
+	nbkLookbackDays = 10
+	// nbkMaxRangeDays caps a range, which costs one request per working day.
+	nbkMaxRangeDays = 366
+	// nbkRangeConcurrency days of a range are fetched at once; the client's
+	// rate limiter paces them.
+	nbkRangeConcurrency = 4
+	// nbkRangeTimeout bounds a whole range, retries included.
+	nbkRangeTimeout = 2 * time.Minute
+)
+
+// NBKProvider serves the official KZT rates of the National Bank of
+// Kazakhstan. The feed has no range query, so ranges are fetched day by day.
+type NBKProvider struct {
+	client nbk.NbkClient
+	logger *logrus.Logger
+}
+
+func NewNBKProvider(client nbk.NbkClient, logger *logrus.Logger) *NBKProvider {
+	return &NBKProvider{
+		client: client,
+		logger: logger,
+	}
+}
+
+func (p *NBKProvider) Name() string {
+	return SourceNBK
+}
+
+func (p *NBKProvider) BaseCurrency() string {
+	return "KZT"
+}
+
+func (p *NBKProvider) Calendar() Calendar {
+	return NBKCalendar()
+}
+
+// FetchDaily returns the rates of the last working day on or before date.
+func (p *NBKProvider) FetchDaily(ctx context.Context, date time.Time) ([]entity.Currency, error) {
+	date = date.Truncate(24 * time.Hour)
+	calendar := p.Calendar()
+
+	for d := date; !d.Before(date.AddDate(0, 0, -nbkLookbackDays)); d = d.AddDate(0, 0, -1) {
+		if !calendar.IsPublicationDay(d) {
+			continue
+		}
+		rates, err := p.client.FetchRates(ctx, d)
+		if err != nil {
+			return nil, fmt.Errorf("fetch NBK rates for %s: %w", d.Format("2006-01-02"), err)
+		}
+		if len(rates.Items) > 0 {
+			return convertNBKRates(rates, "")
+		}
+		p.logger.WithContext(ctx).Debugf("NBK set no rates for %s, trying the day before", d.Format("2006-01-02"))
+	}
+
+	p.logger.WithContext(ctx).Warnf("No NBK rates set on or before %s", date.Format("2006-01-02"))
+	return nil, nil
+}
+
+func (p *NBKProvider) FetchRange(ctx context.Context, charCode string, from, to time.Time) ([]entity.Currency, error) {
+	from = from.Truncate(24 * time.Hour)
+	to = to.Truncate(24 * time.Hour)
+	logger := p.logger.WithContext(ctx)
+	logger.Infof("Fetching NBK %s rates for %s - %s", charCode, from.Format("2006-01-02"), to.Format("2006-01-02"))
+
+	if days := int(to.Sub(from).Hours()/24) + 1; days > nbkMaxRangeDays {
+		return nil, fmt.Errorf("NBK range of %d days exceeds %d", days, nbkMaxRangeDays)
+	}
+
+	calendar := p.Calendar()
+	var days []time.Time
+	for d := from; !d.After(to); d = d.AddDate(0, 0, 1) {
+		if calendar.IsPublicationDay(d) {
+			days = append(days, d)
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, nbkRangeTimeout)
+	defer cancel()
+
+	// the feed has one page per day: fetch them concurrently, the first
+	// failure cancels the rest
+	pages := make([]*nbk.Rates, len(days))
+	sem := make(chan struct{}, nbkRangeConcurrency)
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		firstErr error
+	)
+	for i, d := range days {
+		select {
+		case <-ctx.Done():
+		case sem <- struct{}{}:
+		}
+		if ctx.Err() != nil {
+			break
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+			rates, err := p.client.FetchRates(ctx, d)
+			if err != nil {
+				mu.Lock()
+				if firstErr == nil {
+					firstErr = fmt.Errorf("fetch NBK rates for %s: %w", d.Format("2006-01-02"), err)
+				}
+				mu.Unlock()
+				cancel()
+				return
+			}
+			pages[i] = rates
+		}()
+	}
+	wg.Wait()
+
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, fmt.Errorf("fetch NBK rates for %s - %s: %w", from.Format("2006-01-02"), to.Format("2006-01-02"), err)
+	}
+
+	var result []entity.Currency
+	published := false
+	for _, rates := range pages {
+		if len(rates.Items) > 0 {
+			published = true
+		}
+		converted, err := convertNBKRates(rates, charCode)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, converted...)
+	}
+
+	if published && len(result) == 0 {
+		logger.Warnf("Currency code %s is not quoted by NBK", charCode)
+		return nil, fmt.Errorf("%w: %s", ErrCurrencyNotQuoted, charCode)
+	}
+	return result, nil
+}
+
+// convertNBKRates keeps the NBK quant as the nominal: the price of Quant units
+// in tenge. An empty charCode converts every item.
+func convertNBKRates(rates *nbk.Rates, charCode string) ([]entity.Currency, error) {
+	date, err := rates.GetDate()
+	if err != nil {
+		return nil, fmt.Errorf("parse NBK date '%s': %w", rates.Date, err)
+	}
+
+	var result []entity.Currency
+	for _, item := range rates.Items {
+		code := item.GetCharCode()
+		if charCode != "" && code != charCode {
+			continue
+		}
+		value, err := item.GetValue()
+		if err != nil {
+			return nil, fmt.Errorf("parse NBK rate for %s on %s: %w", code, rates.Date, err)
+		}
+		quant, err := item.GetQuant()
+		if err != nil {
+			return nil, fmt.Errorf("parse NBK quant for %s on %s: %w", code, rates.Date, err)
+		}
+		if !value.IsPositive() || quant <= 0 {
+			continue
+		}
+
+		result = append(result, entity.Currency{
+			CharCode:    code,
+			Name:        item.FullName,
+			Nominal:     quant,
+			Value:       value,
+			Date:        date,
+			Source:      SourceNBK,
+			PayloadHash: rates.PayloadHash,
+		})
+	}
+	return result, nil
+}
@@ -0,0 +1,134 @@
+package provider
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"RnD-service/internal/adapter/nbk"
+
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// setupNBKProvider serves the get_rates.cfm fixtures from the nbk
+// package testdata: 31 July and 1 August 2025 and an empty Saturday.
+func setupNBKProvider(t *testing.T) (*NBKProvider, *[]string) {
+	var (
+		mu        sync.Mutex
+		requested []string
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		date, err := time.Parse("02.01.2006", r.URL.Query().Get("fdate"))
+		if err != nil {
+			http.NotFound(w, r)
+			return
+		}
+		mu.Lock()
+		requested = append(requested, date.Format("2006-01-02"))
+		mu.Unlock()
+		http.ServeFile(w, r, filepath.Join("../nbk/testdata", "rates_"+date.Format("2006-01-02")+".xml"))
+	}))
+	t.Cleanup(srv.Close)
+
+	logger, _ := test.NewNullLogger()
+	client, err := nbk.NewClient(logger, nbk.WithBaseURL(srv.URL))
+	require.NoError(t, err)
+	return NewNBKProvider(client, logger), &requested
+}
+
+func TestNBKProvider_FetchDaily(t *testing.T) {
+	p, _ := setupNBKProvider(t)
+
+	rates, err := p.FetchDaily(context.Background(), time.Date(2025, 8, 1, 0, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+	require.Len(t, rates, 8)
+
+	usd := rates[6]
+	assert.Equal(t, "USD", usd.CharCode)
+	assert.Equal(t, "ДОЛЛАР США", usd.Name)
+	assert.Equal(t, 1, usd.Nominal)
+	assert.Equal(t, "541.95", usd.Value.String())
+	assert.Equal(t, time.Date(2025, 8, 1, 0, 0, 0, 0, time.UTC), usd.Date)
+	assert.Equal(t, SourceNBK, usd.Source)
+	assert.Len(t, usd.PayloadHash, 64)
+
+	uzs := rates[7]
+	assert.Equal(t, "UZS", uzs.CharCode)
+	assert.Equal(t, 100, uzs.Nominal)
+	assert.Equal(t, "4.3", uzs.Value.String())
+}
+
+func TestNBKProvider_FetchDaily_Weekend(t *testing.T) {
+	p, requested := setupNBKProvider(t)
+
+	rates, err := p.FetchDaily(context.Background(), time.Date(2025, 8, 3, 0, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+	require.NotEmpty(t, rates)
+	assert.Equal(t, time.Date(2025, 8, 1, 0, 0, 0, 0, time.UTC), rates[0].Date)
+	assert.Equal(t, []string{"2025-08-01"}, *requested, "weekend dates are not requested")
+}
+
+func TestNBKProvider_FetchDaily_Unavailable(t *testing.T) {
+	p, _ := setupNBKProvider(t)
+
+	_, err := p.FetchDaily(context.Background(), time.Date(2024, 1, 5, 0, 0, 0, 0, time.UTC))
+	assert.ErrorIs(t, err, nbk.ErrUnexpectedStatus)
+}
+
+func TestNBKProvider_FetchRange(t *testing.T) {
+	p, requested := setupNBKProvider(t)
+
+	rates, err := p.FetchRange(context.Background(), "USD", time.Date(2025, 7, 31, 0, 0, 0, 0, time.UTC), time.Date(2025, 8, 3, 0, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+	require.Len(t, rates, 2)
+	assert.Equal(t, time.Date(2025, 7, 31, 0, 0, 0, 0, time.UTC), rates[0].Date)
+	assert.Equal(t, "542.81", rates[0].Value.String())
+	assert.Equal(t, time.Date(2025, 8, 1, 0, 0, 0, 0, time.UTC), rates[1].Date)
+	assert.ElementsMatch(t, []string{"2025-07-31", "2025-08-01"}, *requested)
+}
+
+func TestNBKProvider_FetchRange_DayUnavailable(t *testing.T) {
+	p, _ := setupNBKProvider(t)
+
+	// no fixture for 30 July
+	_, err := p.FetchRange(context.Background(), "USD", time.Date(2025, 7, 30, 0, 0, 0, 0, time.UTC), time.Date(2025, 8, 1, 0, 0, 0, 0, time.UTC))
+	assert.ErrorIs(t, err, nbk.ErrUnexpectedStatus)
+	assert.ErrorContains(t, err, "2025-07-30")
+}
+
+func TestNBKProvider_FetchRange_TooLong(t *testing.T) {
+	p, requested := setupNBKProvider(t)
+
+	_, err := p.FetchRange(context.Background(), "USD", time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(2025, 8, 1, 0, 0, 0, 0, time.UTC))
+	assert.ErrorContains(t, err, "exceeds 366")
+	assert.Empty(t, *requested)
+}
+
+func TestNBKProvider_FetchRange_NotQuoted(t *testing.T) {
+	p, _ := setupNBKProvider(t)
+
+	_, err := p.FetchRange(context.Background(), "ZAR", time.Date(2025, 7, 31, 0, 0, 0, 0, time.UTC), time.Date(2025, 8, 1, 0, 0, 0, 0, time.UTC))
+	assert.ErrorIs(t, err, ErrCurrencyNotQuoted)
+}
+
+func TestConvertNBKRates_SkipsNonPositive(t *testing.T) {
+	rates := &nbk.Rates{
+		Date: "01.08.2025",
+		Items: []nbk.Item{
+			{Title: "USD", Description: "541.95", Quant: "1"},
+			{Title: "EUR", Description: "-1", Quant: "1"},
+			{Title: "UZS", Description: "4.3", Quant: "0"},
+		},
+	}
+
+	converted, err := convertNBKRates(rates, "")
+	require.NoError(t, err)
+	require.Len(t, converted, 1)
+	assert.Equal(t, "USD", converted[0].CharCode)
+}
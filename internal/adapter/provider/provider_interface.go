@@ -0,0 +1,30 @@
+package provider
+
+import (
+	"RnD-service/internal/entity"
+	"context"
+	"time"
+)
+
+// RateProvider is a source of official exchange rates. Rates are returned
+// as entity.Currency: Value is the price of Nominal units in BaseCurrency,
+// Date is the publication the rate belongs to and Source is Name.
+type RateProvider interface {
+	// Name is the key stored with every rate and accepted as ?source=.
+	Name() string
+	BaseCurrency() string
+	Calendar() Calendar
+	// FetchDaily returns the publication in effect on date, which may be an
+	// earlier one when the provider did not publish on date.
+	FetchDaily(ctx context.Context, date time.Time) ([]entity.Currency, error)
+	// FetchRange returns charCode rates for every publication between from and to.
+	FetchRange(ctx context.Context, charCode string, from, to time.Time) ([]entity.Currency, error)
+}
+
+// Calendar tells which dates a provider publishes rates for.
+type Calendar interface {
+	IsPublicationDay(date time.Time) bool
+	// PublishedOn is the day the publication dated date is released: the day
+	// before for CBR, which sets rates on D for D+1, the same day for ECB.
+	PublishedOn(date time.Time) time.Time
+}
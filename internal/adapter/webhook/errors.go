@@ -0,0 +1,22 @@
+package webhook
+
+import (
+	"errors"
+	"fmt"
+)
+
+var ErrUnexpectedStatus = errors.New("unexpected HTTP status from webhook")
+
+// StatusError is returned when the receiver answers with a non-2xx status.
+type StatusError struct {
+	URL        string
+	StatusCode int
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("%s %s: %d", ErrUnexpectedStatus, e.URL, e.StatusCode)
+}
+
+func (e *StatusError) Unwrap() error {
+	return ErrUnexpectedStatus
+}
@@ -0,0 +1,69 @@
+package webhook
+
+import (
+	"RnD-service/pkg/netguard"
+	"time"
+)
+
+type options struct {
+	timeout     time.Duration
+	maxAttempts int
+	baseDelay   time.Duration
+	maxDelay    time.Duration
+	userAgent   string
+	allowlist   netguard.Allowlist
+}
+
+func defaultOptions() options {
+	return options{
+		timeout:     10 * time.Second,
+		maxAttempts: 5,
+		baseDelay:   time.Second,
+		maxDelay:    time.Minute,
+		userAgent:   "RnD-service",
+	}
+}
+
+// Option configures a Client. Zero values keep the defaults.
+type Option func(*options)
+
+// WithTimeout limits a single delivery attempt.
+func WithTimeout(d time.Duration) Option {
+	return func(o *options) {
+		if d > 0 {
+			o.timeout = d
+		}
+	}
+}
+
+// WithRetry sets how often a delivery is attempted, the first one included,
+// and the bounds of the exponential backoff between attempts.
+func WithRetry(maxAttempts int, baseDelay, maxDelay time.Duration) Option {
+	return func(o *options) {
+		if maxAttempts > 0 {
+			o.maxAttempts = maxAttempts
+		}
+		if baseDelay > 0 {
+			o.baseDelay = baseDelay
+		}
+		if maxDelay > 0 {
+			o.maxDelay = maxDelay
+		}
+	}
+}
+
+func WithUserAgent(userAgent string) Option {
+	return func(o *options) {
+		if userAgent != "" {
+			o.userAgent = userAgent
+		}
+	}
+}
+
+// WithAllowlist lets deliveries reach the listed internal networks; other
+// loopback, private and link-local addresses are refused when dialing.
+func WithAllowlist(allowlist netguard.Allowlist) Option {
+	return func(o *options) {
+		o.allowlist = allowlist
+	}
+}
@@ -0,0 +1,156 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	neturl "net/url"
+	"strconv"
+	"time"
+
+	"RnD-service/pkg/logger"
+	"RnD-service/pkg/netguard"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	HeaderTimestamp = "X-Webhook-Timestamp"
+	HeaderSignature = "X-Webhook-Signature"
+)
+
+type Client struct {
+	httpClient  *http.Client
+	maxAttempts int
+	baseDelay   time.Duration
+	maxDelay    time.Duration
+	userAgent   string
+	logger      *logrus.Logger
+	now         func() time.Time
+}
+
+func NewClient(logger *logrus.Logger, opts ...Option) *Client {
+	o := defaultOptions()
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	// the address is checked when dialing, so redirects and host names
+	// resolving to internal networks are refused as well; a proxy would hide
+	// the destination, so deliveries go direct
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = (&net.Dialer{
+		Timeout:   30 * time.Second,
+		KeepAlive: 30 * time.Second,
+		Control:   o.allowlist.Control,
+	}).DialContext
+
+	return &Client{
+		httpClient:  &http.Client{Timeout: o.timeout, Transport: transport},
+		maxAttempts: o.maxAttempts,
+		baseDelay:   o.baseDelay,
+		maxDelay:    o.maxDelay,
+		userAgent:   o.userAgent,
+		logger:      logger,
+		now:         time.Now,
+	}
+}
+
+// Sign returns the value of the signature header for body sent at timestamp:
+// "sha256=" and the hex HMAC-SHA256 of "<timestamp>.<body>" keyed with secret.
+// Receivers recompute it and should reject stale timestamps.
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Deliver POSTs payload to url until the receiver answers 2xx, retrying
+// network errors, 408, 429 and 5xx with exponential backoff. It returns the
+// number of attempts made. The userinfo and query of url are masked in logs
+// and errors.
+func (c *Client) Deliver(ctx context.Context, url, secret string, payload []byte) (int, error) {
+	safeURL := logger.RedactURL(url)
+	var err error
+	for attempt := 1; attempt <= c.maxAttempts; attempt++ {
+		err = c.post(ctx, url, safeURL, secret, payload)
+		if err == nil {
+			c.logger.Infof("Delivered webhook to %s on attempt %d", safeURL, attempt)
+			return attempt, nil
+		}
+		if !retryable(err) || attempt == c.maxAttempts {
+			c.logger.WithError(err).Errorf("Webhook delivery to %s failed after %d attempts", safeURL, attempt)
+			return attempt, err
+		}
+
+		delay := c.backoff(attempt)
+		c.logger.WithError(err).Warnf("Webhook delivery to %s failed on attempt %d, retrying in %s", safeURL, attempt, delay)
+		select {
+		case <-ctx.Done():
+			return attempt, fmt.Errorf("%w (last error: %w)", ctx.Err(), err)
+		case <-time.After(delay):
+		}
+	}
+	return c.maxAttempts, err
+}
+
+func (c *Client) post(ctx context.Context, url, safeURL, secret string, payload []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("create request: %w", redactURLError(err, safeURL))
+	}
+	timestamp := c.now().Unix()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", c.userAgent)
+	req.Header.Set(HeaderTimestamp, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(HeaderSignature, Sign(secret, timestamp, payload))
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("webhook %s: %w", safeURL, redactURLError(err, safeURL))
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return &StatusError{URL: safeURL, StatusCode: resp.StatusCode}
+	}
+	return nil
+}
+
+// redactURLError replaces the URL that net/http puts into its errors.
+func redactURLError(err error, safeURL string) error {
+	var urlErr *neturl.Error
+	if errors.As(err, &urlErr) {
+		urlErr.URL = safeURL
+	}
+	return err
+}
+
+func (c *Client) backoff(attempt int) time.Duration {
+	d := c.baseDelay
+	for i := 1; i < attempt && d < c.maxDelay; i++ {
+		d *= 2
+	}
+	return min(d, c.maxDelay)
+}
+
+// retryable reports whether the receiver may accept the same request later;
+// other 4xx answers will not change on retry.
+func retryable(err error) bool {
+	var se *StatusError
+	if !errors.As(err, &se) {
+		return !errors.Is(err, context.Canceled) && !errors.Is(err, netguard.ErrForbiddenAddress)
+	}
+	return se.StatusCode == http.StatusRequestTimeout || se.StatusCode == http.StatusTooManyRequests || se.StatusCode >= 500
+}
@@ -0,0 +1,7 @@
+package webhook
+
+import "context"
+
+type Notifier interface {
+	Deliver(ctx context.Context, url, secret string, payload []byte) (int, error)
+}
@@ -0,0 +1,68 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+const (
+	AlertThresholdPercent  = "percent"
+	AlertThresholdAbsolute = "absolute"
+
+	AlertDirectionUp   = "up"
+	AlertDirectionDown = "down"
+	AlertDirectionBoth = "both"
+
+	AlertPending   = "pending"
+	AlertDelivered = "delivered"
+	AlertFailed    = "failed"
+)
+
+// AlertRule fires when the per-unit rate of CharCode from Source moves by at
+// least Threshold, in percent or in the source's base currency, from the
+// previous publication in Direction.
+type AlertRule struct {
+	ID            int64           `db:"id" json:"id"`
+	Source        string          `db:"source" json:"source"`
+	CharCode      string          `db:"char_code" json:"char_code"`
+	ThresholdType string          `db:"threshold_type" json:"threshold_type"`
+	Threshold     decimal.Decimal `db:"threshold" json:"threshold"`
+	Direction     string          `db:"direction" json:"direction"`
+	WebhookURL    string          `db:"webhook_url" json:"webhook_url"`
+	Secret        string          `db:"secret" json:"-"`
+	Enabled       bool            `db:"enabled" json:"enabled"`
+	CreatedAt     time.Time       `db:"created_at" json:"created_at"`
+	UpdatedAt     time.Time       `db:"updated_at" json:"updated_at"`
+}
+
+// Alert is a triggered rule for the publication of Date compared with the one
+// of PreviousDate; values are per unit of CharCode.
+type Alert struct {
+	ID            int64           `db:"id" json:"id"`
+	RuleID        int64           `db:"rule_id" json:"rule_id"`
+	Source        string          `db:"source" json:"source"`
+	CharCode      string          `db:"char_code" json:"char_code"`
+	Date          time.Time       `db:"date" json:"date"`
+	PreviousDate  time.Time       `db:"previous_date" json:"previous_date"`
+	PreviousValue decimal.Decimal `db:"previous_value" json:"previous_value"`
+	Value         decimal.Decimal `db:"value" json:"value"`
+	Change        decimal.Decimal `db:"change" json:"change"`
+	ChangePercent decimal.Decimal `db:"change_percent" json:"change_percent"`
+	Status        string          `db:"status" json:"status"`
+	Attempts      int             `db:"attempts" json:"attempts"`
+	LastError     string          `db:"last_error" json:"last_error,omitempty"`
+	TriggeredAt   time.Time       `db:"triggered_at" json:"triggered_at"`
+	DeliveredAt   *time.Time      `db:"delivered_at" json:"delivered_at,omitempty"`
+}
+
+// WebhookDeadLetter keeps a webhook payload that could not be delivered.
+type WebhookDeadLetter struct {
+	ID        int64     `db:"id" json:"id"`
+	AlertID   int64     `db:"alert_id" json:"alert_id"`
+	URL       string    `db:"url" json:"url"`
+	Payload   []byte    `db:"payload" json:"payload"`
+	Attempts  int       `db:"attempts" json:"attempts"`
+	LastError string    `db:"last_error" json:"last_error"`
+	CreatedAt time.Time `db:"created_at" json:"created_at"`
+}
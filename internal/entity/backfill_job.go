@@ -0,0 +1,25 @@
+package entity
+
+import "time"
+
+const (
+	BackfillPending   = "pending"
+	BackfillRunning   = "running"
+	BackfillCompleted = "completed"
+	BackfillFailed    = "failed"
+)
+
+type BackfillJob struct {
+	ID         int64      `db:"id" json:"id"`
+	DateFrom   time.Time  `db:"date_from" json:"date_from"`
+	DateTo     time.Time  `db:"date_to" json:"date_to"`
+	CharCodes  []string   `db:"char_codes" json:"char_codes,omitempty"`
+	Status     string     `db:"status" json:"status"`
+	NextDate   time.Time  `db:"next_date" json:"next_date"`
+	DaysTotal  int        `db:"days_total" json:"days_total"`
+	DaysDone   int        `db:"days_done" json:"days_done"`
+	Error      string     `db:"error" json:"error,omitempty"`
+	CreatedAt  time.Time  `db:"created_at" json:"created_at"`
+	UpdatedAt  time.Time  `db:"updated_at" json:"updated_at"`
+	FinishedAt *time.Time `db:"finished_at" json:"finished_at,omitempty"`
+}
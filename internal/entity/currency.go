@@ -1,14 +1,45 @@
 package entity
 
-import "time"
+import (
+	"time"
 
+	"github.com/shopspring/decimal"
+)
+
+// Currency is stored with Date as its effective_date. EffectiveDate is set only
+// on rates answered for a requested Date: it is the publication in effect on
+// Date, earlier than Date on days the source does not publish. PayloadHash is
+// the SHA-256 of the upstream response the rate was parsed from.
 type Currency struct {
-	ID        string    `db:"id" json:"id,omitempty"`
-	CharCode  string    `db:"char_code" json:"char_code"`
-	Name      string    `db:"name" json:"name,omitempty"`
-	Nominal   int       `db:"nominal" json:"nominal,omitempty"`
-	Value     float64   `db:"value" json:"value"`
-	NumCode   string    `db:"num_code" json:"num_code,omitempty"`
-	UpdatedAt time.Time `db:"updated_at" json:"updated_at,omitempty"`
-	Date      time.Time `db:"date" json:"date,omitempty"`
+	ID            string          `db:"id" json:"id,omitempty"`
+	CharCode      string          `db:"char_code" json:"char_code"`
+	Name          string          `db:"name" json:"name,omitempty"`
+	Nominal       int             `db:"nominal" json:"nominal,omitempty"`
+	Value         decimal.Decimal `db:"value" json:"value"`
+	NumCode       string          `db:"num_code" json:"num_code,omitempty"`
+	UpdatedAt     time.Time       `db:"fetched_at" json:"updated_at,omitempty"`
+	Date          time.Time       `db:"effective_date" json:"date,omitempty"`
+	EffectiveDate time.Time       `db:"-" json:"effective_date,omitempty"`
+	Source        string          `db:"source" json:"source,omitempty"`
+	PayloadHash   string          `db:"payload_hash" json:"-"`
+}
+
+type CurrencyInfo struct {
+	ID         string    `db:"cbr_id" json:"cbr_id"`
+	CharCode   string    `db:"char_code" json:"char_code"`
+	NumCode    string    `db:"num_code" json:"num_code,omitempty"`
+	Name       string    `db:"name" json:"name"`
+	EngName    string    `db:"eng_name" json:"eng_name"`
+	Nominal    int       `db:"nominal" json:"nominal"`
+	ParentCode string    `db:"parent_code" json:"parent_code"`
+	Monthly    bool      `db:"monthly" json:"monthly"`
+	UpdatedAt  time.Time `db:"updated_at" json:"updated_at,omitempty"`
+}
+
+// LatestRateSummary is the newest stored publication date of Source and the
+// last time one of its latest rates was fetched.
+type LatestRateSummary struct {
+	Source     string    `db:"source"`
+	LatestDate time.Time `db:"effective_date"`
+	FetchedAt  time.Time `db:"fetched_at"`
 }
@@ -5,6 +5,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/shopspring/decimal"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -16,7 +17,7 @@ func TestCurrency_MarshalJSON(t *testing.T) {
 		CharCode:  "USD",
 		Name:      "US Dollar",
 		Nominal:   1,
-		Value:     90.5,
+		Value:     decimal.RequireFromString("90.5"),
 		NumCode:   "840",
 		UpdatedAt: now,
 		Date:      now,
@@ -25,25 +26,25 @@ func TestCurrency_MarshalJSON(t *testing.T) {
 	data, err := json.Marshal(currency)
 	require.NoError(t, err)
 
-	expected := `{"id":"123","char_code":"USD","name":"US Dollar","nominal":1,"value":90.5,"num_code":"840","updated_at":"2025-08-02T00:00:00Z","date":"2025-08-02T00:00:00Z"}`
+	expected := `{"id":"123","char_code":"USD","name":"US Dollar","nominal":1,"value":"90.5","num_code":"840","updated_at":"2025-08-02T00:00:00Z","date":"2025-08-02T00:00:00Z","effective_date":"0001-01-01T00:00:00Z"}`
 	assert.JSONEq(t, expected, string(data))
 }
 
 func TestCurrency_MarshalJSON_OmitEmpty(t *testing.T) {
 	currency := Currency{
 		CharCode: "USD",
-		Value:    90.5,
+		Value:    decimal.RequireFromString("90.5"),
 	}
 
 	data, err := json.Marshal(currency)
 	require.NoError(t, err)
 
-	expected := `{"char_code":"USD","value":90.5,"updated_at":"0001-01-01T00:00:00Z","date":"0001-01-01T00:00:00Z"}`
+	expected := `{"char_code":"USD","value":"90.5","updated_at":"0001-01-01T00:00:00Z","date":"0001-01-01T00:00:00Z","effective_date":"0001-01-01T00:00:00Z"}`
 	assert.JSONEq(t, expected, string(data))
 }
 
 func TestCurrency_UnmarshalJSON(t *testing.T) {
-	jsonData := `{"id":"123","char_code":"USD","name":"US Dollar","nominal":1,"value":90.5,"num_code":"840","updated_at":"2025-08-02T00:00:00Z","date":"2025-08-02T00:00:00Z"}`
+	jsonData := `{"id":"123","char_code":"USD","name":"US Dollar","nominal":1,"value":"90.5","num_code":"840","updated_at":"2025-08-02T00:00:00Z","date":"2025-08-02T00:00:00Z"}`
 
 	var currency Currency
 	err := json.Unmarshal([]byte(jsonData), &currency)
@@ -55,7 +56,7 @@ func TestCurrency_UnmarshalJSON(t *testing.T) {
 		CharCode:  "USD",
 		Name:      "US Dollar",
 		Nominal:   1,
-		Value:     90.5,
+		Value:     decimal.RequireFromString("90.5"),
 		NumCode:   "840",
 		UpdatedAt: now,
 		Date:      now,
@@ -64,7 +65,7 @@ func TestCurrency_UnmarshalJSON(t *testing.T) {
 }
 
 func TestCurrency_UnmarshalJSON_Partial(t *testing.T) {
-	jsonData := `{"char_code":"USD","value":90.5}`
+	jsonData := `{"char_code":"USD","value":"90.5"}`
 
 	var currency Currency
 	err := json.Unmarshal([]byte(jsonData), &currency)
@@ -72,7 +73,7 @@ func TestCurrency_UnmarshalJSON_Partial(t *testing.T) {
 
 	expected := Currency{
 		CharCode: "USD",
-		Value:    90.5,
+		Value:    decimal.RequireFromString("90.5"),
 	}
 	assert.Equal(t, expected, currency)
 }
@@ -83,6 +84,16 @@ func TestCurrency_MarshalJSON_ZeroValues(t *testing.T) {
 	data, err := json.Marshal(currency)
 	require.NoError(t, err)
 
-	expected := `{"char_code":"","value":0,"updated_at":"0001-01-01T00:00:00Z","date":"0001-01-01T00:00:00Z"}`
+	expected := `{"char_code":"","value":"0","updated_at":"0001-01-01T00:00:00Z","date":"0001-01-01T00:00:00Z","effective_date":"0001-01-01T00:00:00Z"}`
 	assert.JSONEq(t, expected, string(data))
 }
+
+func TestCurrency_UnmarshalJSON_NumericValue(t *testing.T) {
+	jsonData := `{"char_code":"USD","value":69.0202}`
+
+	var currency Currency
+	err := json.Unmarshal([]byte(jsonData), &currency)
+	require.NoError(t, err)
+
+	assert.Equal(t, "69.0202", currency.Value.String())
+}
@@ -0,0 +1,22 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// RateDiscrepancy is a currency whose rates from two sources for Date differ by
+// more than the reconciliation threshold. Both values are per unit of CharCode
+// in Base; DiffPercent is relative to SecondaryValue.
+type RateDiscrepancy struct {
+	Date            time.Time       `db:"date" json:"date"`
+	CharCode        string          `db:"char_code" json:"char_code"`
+	PrimarySource   string          `db:"primary_source" json:"primary_source"`
+	SecondarySource string          `db:"secondary_source" json:"secondary_source"`
+	Base            string          `db:"base" json:"base"`
+	PrimaryValue    decimal.Decimal `db:"primary_value" json:"primary_value"`
+	SecondaryValue  decimal.Decimal `db:"secondary_value" json:"secondary_value"`
+	DiffPercent     decimal.Decimal `db:"diff_percent" json:"diff_percent"`
+	DetectedAt      time.Time       `db:"detected_at" json:"detected_at"`
+}
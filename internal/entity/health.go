@@ -0,0 +1,49 @@
+package entity
+
+import "time"
+
+// HealthReport is what the service depends on to serve rates, checked at
+// CheckedAt. Ready means the database answers, every migration is applied and
+// the rates are not stale.
+type HealthReport struct {
+	CheckedAt time.Time
+	Ready     bool
+	Database  DatabaseHealth
+	Schema    SchemaHealth
+	Rates     RateFreshness
+	Sync      SyncStatus
+}
+
+type DatabaseHealth struct {
+	OK      bool
+	Latency time.Duration
+	Error   string
+}
+
+type SchemaHealth struct {
+	OK      bool
+	Version int64
+	Latest  int64
+	Dirty   bool
+	Error   string
+}
+
+// RateFreshness compares the newest stored rate date of Source with the newest
+// date its calendar has published by now; Stale when Lag exceeds MaxLag.
+type RateFreshness struct {
+	Source       string
+	LatestDate   time.Time
+	ExpectedDate time.Time
+	Lag          time.Duration
+	MaxLag       time.Duration
+	Stale        bool
+	Error        string
+}
+
+// SyncStatus is the outcome of the last scheduled or startup rate sync and the
+// next scheduled run; zero times mean none yet.
+type SyncStatus struct {
+	LastAt    time.Time
+	LastError string
+	NextAt    time.Time
+}
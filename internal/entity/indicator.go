@@ -0,0 +1,23 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// KeyRate is the CBR key rate in percent per annum in effect on Date.
+type KeyRate struct {
+	Date      time.Time       `db:"date" json:"date"`
+	Rate      decimal.Decimal `db:"rate" json:"rate"`
+	UpdatedAt time.Time       `db:"updated_at" json:"updated_at,omitempty"`
+}
+
+// RuoniaRate is the RUONIA overnight rate in percent for deals made on Date;
+// Volume is in billions of roubles.
+type RuoniaRate struct {
+	Date      time.Time       `db:"date" json:"date"`
+	Rate      decimal.Decimal `db:"rate" json:"rate"`
+	Volume    decimal.Decimal `db:"volume" json:"volume"`
+	UpdatedAt time.Time       `db:"updated_at" json:"updated_at,omitempty"`
+}
@@ -0,0 +1,49 @@
+package entity
+
+import "time"
+
+const (
+	JobRunRunning   = "running"
+	JobRunSucceeded = "succeeded"
+	JobRunFailed    = "failed"
+)
+
+const (
+	JobTriggerSchedule = "schedule"
+	JobTriggerStartup  = "startup"
+	JobTriggerManual   = "manual"
+)
+
+// JobRun is one execution of a scheduled job; Attempts counts the retries
+// made within it.
+type JobRun struct {
+	ID         int64      `db:"id" json:"id"`
+	Job        string     `db:"job" json:"job"`
+	Trigger    string     `db:"triggered_by" json:"triggered_by"`
+	Instance   string     `db:"instance" json:"instance"`
+	Status     string     `db:"status" json:"status"`
+	Attempts   int        `db:"attempts" json:"attempts"`
+	Error      string     `db:"error" json:"error,omitempty"`
+	StartedAt  time.Time  `db:"started_at" json:"started_at"`
+	FinishedAt *time.Time `db:"finished_at" json:"finished_at,omitempty"`
+}
+
+// JobInfo is a configured job as seen by one replica. NextRun is zero for a
+// job without a schedule.
+type JobInfo struct {
+	Name       string
+	Schedule   string
+	RunOnStart bool
+	NextRun    time.Time
+	Running    bool
+	LastRun    *JobRun
+}
+
+// SchedulerStatus lists the jobs of the replica Instance; only the Leader
+// replica runs them on schedule.
+type SchedulerStatus struct {
+	Instance string
+	Leader   bool
+	Timezone string
+	Jobs     []JobInfo
+}
@@ -0,0 +1,31 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// Precious metal codes used by the API; CBR itself numbers them 1-4.
+const (
+	MetalGold      = "AU"
+	MetalSilver    = "AG"
+	MetalPlatinum  = "PT"
+	MetalPalladium = "PD"
+)
+
+var MetalNames = map[string]string{
+	MetalGold:      "Золото",
+	MetalSilver:    "Серебро",
+	MetalPlatinum:  "Платина",
+	MetalPalladium: "Палладий",
+}
+
+// MetalPrice is the CBR discount price in roubles per gram.
+type MetalPrice struct {
+	Code      string          `db:"code" json:"code"`
+	Date      time.Time       `db:"date" json:"date"`
+	Buy       decimal.Decimal `db:"buy" json:"buy"`
+	Sell      decimal.Decimal `db:"sell" json:"sell"`
+	UpdatedAt time.Time       `db:"updated_at" json:"updated_at,omitempty"`
+}
@@ -0,0 +1,36 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// RateStats summarizes the publications of CharCode between From and To. All
+// values are per unit of CharCode (value / nominal), so a nominal change does
+// not distort them. Volatility is the sample standard deviation of the percent
+// change between consecutive publications.
+type RateStats struct {
+	Source     string          `json:"source"`
+	CharCode   string          `json:"char_code"`
+	Count      int             `json:"count"`
+	FirstDate  time.Time       `json:"first_date"`
+	LastDate   time.Time       `json:"last_date"`
+	Min        decimal.Decimal `json:"min"`
+	Max        decimal.Decimal `json:"max"`
+	Mean       decimal.Decimal `json:"mean"`
+	Median     decimal.Decimal `json:"median"`
+	First      decimal.Decimal `json:"first"`
+	Last       decimal.Decimal `json:"last"`
+	Volatility decimal.Decimal `json:"volatility"`
+	Monthly    []PeriodAverage `json:"monthly"`
+	Quarterly  []PeriodAverage `json:"quarterly"`
+}
+
+// PeriodAverage is the mean per-unit rate over the publications of the month
+// or quarter starting at Start.
+type PeriodAverage struct {
+	Start   time.Time       `json:"start"`
+	Average decimal.Decimal `json:"average"`
+	Count   int             `json:"count"`
+}
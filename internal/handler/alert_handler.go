@@ -0,0 +1,149 @@
+package handler
+
+import (
+	"RnD-service/internal/usecase"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+type AlertHandler struct {
+	usecase usecase.AlertUsecase
+	logger  *logrus.Logger
+}
+
+func NewAlertHandler(usecase usecase.AlertUsecase, logger *logrus.Logger) *AlertHandler {
+	return &AlertHandler{
+		usecase: usecase,
+		logger:  logger,
+	}
+}
+
+func (h *AlertHandler) CreateAlertRule(c *gin.Context) {
+	var req AlertRuleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(fmt.Errorf("%w: %v", ErrInvalidRequest, err))
+		return
+	}
+
+	result, err := h.usecase.CreateAlertRule(c.Request.Context(), toAlertRuleInput(req))
+	if err != nil {
+		c.Error(fmt.Errorf("create alert rule for %s: %w", req.CharCode, err))
+		return
+	}
+
+	c.JSON(http.StatusCreated, result)
+}
+
+func (h *AlertHandler) UpdateAlertRule(c *gin.Context) {
+	id, err := parseRuleID(c)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	var req AlertRuleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(fmt.Errorf("%w: %v", ErrInvalidRequest, err))
+		return
+	}
+
+	result, err := h.usecase.UpdateAlertRule(c.Request.Context(), id, toAlertRuleInput(req))
+	if err != nil {
+		c.Error(fmt.Errorf("update alert rule %d: %w", id, err))
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+func (h *AlertHandler) DeleteAlertRule(c *gin.Context) {
+	id, err := parseRuleID(c)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	if err := h.usecase.DeleteAlertRule(c.Request.Context(), id); err != nil {
+		c.Error(fmt.Errorf("delete alert rule %d: %w", id, err))
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (h *AlertHandler) GetAlertRule(c *gin.Context) {
+	id, err := parseRuleID(c)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	result, err := h.usecase.GetAlertRule(c.Request.Context(), id)
+	if err != nil {
+		c.Error(fmt.Errorf("get alert rule %d: %w", id, err))
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+func (h *AlertHandler) ListAlertRules(c *gin.Context) {
+	result, err := h.usecase.ListAlertRules(c.Request.Context())
+	if err != nil {
+		c.Error(fmt.Errorf("list alert rules: %w", err))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"rules": result})
+}
+
+func (h *AlertHandler) GetAlerts(c *gin.Context) {
+	from, to, err := parseDateRange(c, h.logger)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	result, err := h.usecase.GetAlerts(c.Request.Context(), from, to)
+	if err != nil {
+		c.Error(fmt.Errorf("get alerts for from=%s, to=%s: %w", from.Format("2006-01-02"), to.Format("2006-01-02"), err))
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+func (h *AlertHandler) GetDeadLetters(c *gin.Context) {
+	result, err := h.usecase.GetDeadLetters(c.Request.Context())
+	if err != nil {
+		c.Error(fmt.Errorf("get webhook dead letters: %w", err))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"dead_letters": result})
+}
+
+func toAlertRuleInput(req AlertRuleRequest) usecase.AlertRuleInput {
+	return usecase.AlertRuleInput{
+		Source:        req.Source,
+		CharCode:      req.CharCode,
+		ThresholdType: req.ThresholdType,
+		Threshold:     req.Threshold,
+		Direction:     req.Direction,
+		WebhookURL:    req.WebhookURL,
+		Secret:        req.Secret,
+		Enabled:       req.Enabled,
+	}
+}
+
+func parseRuleID(c *gin.Context) (int64, error) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || id <= 0 {
+		return 0, fmt.Errorf("%w: rule id must be a positive integer", ErrInvalidRequest)
+	}
+	return id, nil
+}
@@ -0,0 +1,205 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"RnD-service/internal/usecase"
+
+	"github.com/gin-gonic/gin"
+	"github.com/shopspring/decimal"
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type mockAlertUsecase struct {
+	mock.Mock
+}
+
+func (m *mockAlertUsecase) CreateAlertRule(ctx context.Context, input usecase.AlertRuleInput) (*usecase.AlertRuleResponse, error) {
+	args := m.Called(ctx, input)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*usecase.AlertRuleResponse), args.Error(1)
+}
+
+func (m *mockAlertUsecase) UpdateAlertRule(ctx context.Context, id int64, input usecase.AlertRuleInput) (*usecase.AlertRuleResponse, error) {
+	args := m.Called(ctx, id, input)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*usecase.AlertRuleResponse), args.Error(1)
+}
+
+func (m *mockAlertUsecase) DeleteAlertRule(ctx context.Context, id int64) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *mockAlertUsecase) GetAlertRule(ctx context.Context, id int64) (*usecase.AlertRuleResponse, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*usecase.AlertRuleResponse), args.Error(1)
+}
+
+func (m *mockAlertUsecase) ListAlertRules(ctx context.Context) ([]usecase.AlertRuleResponse, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]usecase.AlertRuleResponse), args.Error(1)
+}
+
+func (m *mockAlertUsecase) GetAlerts(ctx context.Context, dateFrom, dateTo time.Time) (*usecase.AlertListResponse, error) {
+	args := m.Called(ctx, dateFrom, dateTo)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*usecase.AlertListResponse), args.Error(1)
+}
+
+func (m *mockAlertUsecase) GetDeadLetters(ctx context.Context) ([]usecase.DeadLetterResponse, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]usecase.DeadLetterResponse), args.Error(1)
+}
+
+func serveAlerts(h *AlertHandler, method, route, target, body string, handle gin.HandlerFunc) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	_, r := gin.CreateTestContext(w)
+	r.Use(ErrorMiddleware(h.logger))
+	r.Handle(method, route, handle)
+	r.ServeHTTP(w, httptest.NewRequest(method, target, strings.NewReader(body)))
+	return w
+}
+
+func setupAlertHandler() (*AlertHandler, *mockAlertUsecase) {
+	mockUsecase := new(mockAlertUsecase)
+	logger, _ := test.NewNullLogger()
+	return NewAlertHandler(mockUsecase, logger), mockUsecase
+}
+
+func TestCreateAlertRule_Created(t *testing.T) {
+	h, mockUsecase := setupAlertHandler()
+
+	input := usecase.AlertRuleInput{
+		CharCode:      "USD",
+		ThresholdType: "percent",
+		Threshold:     decimal.RequireFromString("0.5"),
+		Direction:     "both",
+		WebhookURL:    "https://hooks.example.com/rates",
+	}
+	expected := &usecase.AlertRuleResponse{ID: 5, Source: "cbr", CharCode: "USD", Threshold: decimal.RequireFromString("0.5"), Secret: "generated", Enabled: true}
+	mockUsecase.On("CreateAlertRule", mock.Anything, mock.MatchedBy(func(in usecase.AlertRuleInput) bool {
+		return in.CharCode == input.CharCode && in.Threshold.Equal(input.Threshold) && in.Enabled == nil && in.WebhookURL == input.WebhookURL
+	})).Return(expected, nil)
+
+	body := `{"char_code":"USD","threshold_type":"percent","threshold":0.5,"direction":"both","webhook_url":"https://hooks.example.com/rates"}`
+	w := serveAlerts(h, http.MethodPost, "/admin/alerts/rules", "/admin/alerts/rules", body, h.CreateAlertRule)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	var response usecase.AlertRuleResponse
+	json.Unmarshal(w.Body.Bytes(), &response)
+	assert.Equal(t, int64(5), response.ID)
+	assert.Equal(t, "generated", response.Secret)
+	assert.True(t, response.Threshold.Equal(expected.Threshold))
+}
+
+func TestCreateAlertRule_InvalidBody(t *testing.T) {
+	h, mockUsecase := setupAlertHandler()
+
+	w := serveAlerts(h, http.MethodPost, "/admin/alerts/rules", "/admin/alerts/rules", `{"char_code":"USD"}`, h.CreateAlertRule)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	var response ErrorResponse
+	json.Unmarshal(w.Body.Bytes(), &response)
+	assert.Equal(t, CodeInvalidRequest, response.Code)
+	mockUsecase.AssertNotCalled(t, "CreateAlertRule", mock.Anything, mock.Anything)
+}
+
+func TestCreateAlertRule_InvalidRule(t *testing.T) {
+	h, mockUsecase := setupAlertHandler()
+
+	mockUsecase.On("CreateAlertRule", mock.Anything, mock.Anything).Return(nil, fmt.Errorf("%w: threshold must be positive", usecase.ErrInvalidAlertRule))
+
+	body := `{"char_code":"USD","threshold_type":"percent","threshold":0,"direction":"both","webhook_url":"https://hooks.example.com/rates"}`
+	w := serveAlerts(h, http.MethodPost, "/admin/alerts/rules", "/admin/alerts/rules", body, h.CreateAlertRule)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	var response ErrorResponse
+	json.Unmarshal(w.Body.Bytes(), &response)
+	assert.Equal(t, CodeInvalidRequest, response.Code)
+}
+
+func TestUpdateAlertRule_NotFound(t *testing.T) {
+	h, mockUsecase := setupAlertHandler()
+
+	mockUsecase.On("UpdateAlertRule", mock.Anything, int64(9), mock.Anything).Return(nil, fmt.Errorf("%w: 9", usecase.ErrAlertRuleNotFound))
+
+	body := `{"char_code":"USD","threshold_type":"absolute","threshold":"1.5","direction":"down","webhook_url":"https://hooks.example.com/rates"}`
+	w := serveAlerts(h, http.MethodPut, "/admin/alerts/rules/:id", "/admin/alerts/rules/9", body, h.UpdateAlertRule)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	var response ErrorResponse
+	json.Unmarshal(w.Body.Bytes(), &response)
+	assert.Equal(t, CodeNotFound, response.Code)
+}
+
+func TestDeleteAlertRule(t *testing.T) {
+	h, mockUsecase := setupAlertHandler()
+
+	mockUsecase.On("DeleteAlertRule", mock.Anything, int64(5)).Return(nil)
+
+	w := serveAlerts(h, http.MethodDelete, "/admin/alerts/rules/:id", "/admin/alerts/rules/5", "", h.DeleteAlertRule)
+
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	mockUsecase.AssertExpectations(t)
+}
+
+func TestGetAlertRule_InvalidID(t *testing.T) {
+	h, mockUsecase := setupAlertHandler()
+
+	w := serveAlerts(h, http.MethodGet, "/admin/alerts/rules/:id", "/admin/alerts/rules/0", "", h.GetAlertRule)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	mockUsecase.AssertNotCalled(t, "GetAlertRule", mock.Anything, mock.Anything)
+}
+
+func TestGetAlerts(t *testing.T) {
+	h, mockUsecase := setupAlertHandler()
+
+	from := time.Date(2025, 8, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2025, 8, 5, 0, 0, 0, 0, time.UTC)
+	expected := &usecase.AlertListResponse{From: "2025-08-01", To: "2025-08-05", Alerts: []usecase.AlertResponse{{ID: 11, RuleID: 5, CharCode: "USD", Status: "delivered"}}}
+	mockUsecase.On("GetAlerts", mock.Anything, from, to).Return(expected, nil)
+
+	w := serveAlerts(h, http.MethodGet, "/admin/alerts", "/admin/alerts?from=2025-08-01&to=2025-08-05", "", h.GetAlerts)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var response usecase.AlertListResponse
+	json.Unmarshal(w.Body.Bytes(), &response)
+	assert.Equal(t, expected.Alerts[0].ID, response.Alerts[0].ID)
+}
+
+func TestGetDeadLetters(t *testing.T) {
+	h, mockUsecase := setupAlertHandler()
+
+	mockUsecase.On("GetDeadLetters", mock.Anything).Return([]usecase.DeadLetterResponse{{ID: 1, AlertID: 11, Payload: json.RawMessage(`{"id":11}`)}}, nil)
+
+	w := serveAlerts(h, http.MethodGet, "/admin/alerts/dead-letters", "/admin/alerts/dead-letters", "", h.GetDeadLetters)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"payload":{"id":11}`)
+}
@@ -0,0 +1,105 @@
+package handler
+
+import (
+	"RnD-service/internal/usecase"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+type BackfillHandler struct {
+	usecase usecase.BackfillJobUsecase
+	logger  *logrus.Logger
+}
+
+func NewBackfillHandler(usecase usecase.BackfillJobUsecase, logger *logrus.Logger) *BackfillHandler {
+	return &BackfillHandler{
+		usecase: usecase,
+		logger:  logger,
+	}
+}
+
+func (h *BackfillHandler) StartBackfill(c *gin.Context) {
+	var req BackfillRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(fmt.Errorf("%w: %v", ErrInvalidRequest, err))
+		return
+	}
+
+	from, err := parseDate(req.From)
+	if err != nil {
+		c.Error(fmt.Errorf("'from': %w", err))
+		return
+	}
+
+	to := time.Now().Truncate(24 * time.Hour)
+	if req.To != "" {
+		to, err = parseDate(req.To)
+		if err != nil {
+			c.Error(fmt.Errorf("'to': %w", err))
+			return
+		}
+	}
+
+	result, err := h.usecase.StartBackfill(c.Request.Context(), from, to, req.CharCodes)
+	if err != nil {
+		c.Error(fmt.Errorf("start backfill from=%s to=%s: %w", req.From, req.To, err))
+		return
+	}
+
+	c.JSON(http.StatusAccepted, result)
+}
+
+func (h *BackfillHandler) ResumeBackfill(c *gin.Context) {
+	id, err := parseJobID(c)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	result, err := h.usecase.ResumeBackfill(c.Request.Context(), id)
+	if err != nil {
+		c.Error(fmt.Errorf("resume backfill job %d: %w", id, err))
+		return
+	}
+
+	c.JSON(http.StatusAccepted, result)
+}
+
+func (h *BackfillHandler) GetBackfillJob(c *gin.Context) {
+	id, err := parseJobID(c)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	result, err := h.usecase.GetBackfillJob(c.Request.Context(), id)
+	if err != nil {
+		c.Error(fmt.Errorf("get backfill job %d: %w", id, err))
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+func (h *BackfillHandler) ListBackfillJobs(c *gin.Context) {
+	result, err := h.usecase.ListBackfillJobs(c.Request.Context())
+	if err != nil {
+		c.Error(fmt.Errorf("list backfill jobs: %w", err))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"jobs": result})
+}
+
+func parseJobID(c *gin.Context) (int64, error) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || id <= 0 {
+		return 0, fmt.Errorf("%w: job id must be a positive integer", ErrInvalidRequest)
+	}
+	return id, nil
+}
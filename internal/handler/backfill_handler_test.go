@@ -0,0 +1,160 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"RnD-service/internal/usecase"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type mockBackfillUsecase struct {
+	mock.Mock
+}
+
+func (m *mockBackfillUsecase) StartBackfill(ctx context.Context, dateFrom, dateTo time.Time, charCodes []string) (*usecase.BackfillJobResponse, error) {
+	args := m.Called(ctx, dateFrom, dateTo, charCodes)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*usecase.BackfillJobResponse), args.Error(1)
+}
+
+func (m *mockBackfillUsecase) ResumeBackfill(ctx context.Context, id int64) (*usecase.BackfillJobResponse, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*usecase.BackfillJobResponse), args.Error(1)
+}
+
+func (m *mockBackfillUsecase) GetBackfillJob(ctx context.Context, id int64) (*usecase.BackfillJobResponse, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*usecase.BackfillJobResponse), args.Error(1)
+}
+
+func (m *mockBackfillUsecase) ListBackfillJobs(ctx context.Context) ([]usecase.BackfillJobResponse, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]usecase.BackfillJobResponse), args.Error(1)
+}
+
+func serveBackfill(h *BackfillHandler, method, route, target, body string, handle gin.HandlerFunc) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	_, r := gin.CreateTestContext(w)
+	r.Use(ErrorMiddleware(h.logger))
+	r.Handle(method, route, handle)
+	r.ServeHTTP(w, httptest.NewRequest(method, target, strings.NewReader(body)))
+	return w
+}
+
+func setupBackfillHandler() (*BackfillHandler, *mockBackfillUsecase) {
+	mockUsecase := new(mockBackfillUsecase)
+	logger, _ := test.NewNullLogger()
+	return NewBackfillHandler(mockUsecase, logger), mockUsecase
+}
+
+func TestStartBackfill_Accepted(t *testing.T) {
+	h, mockUsecase := setupBackfillHandler()
+
+	from := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2023, 1, 31, 0, 0, 0, 0, time.UTC)
+	expected := &usecase.BackfillJobResponse{ID: 1, From: "2023-01-01", To: "2023-01-31", Status: "pending", DaysTotal: 31}
+	mockUsecase.On("StartBackfill", mock.Anything, from, to, []string{"USD"}).Return(expected, nil)
+
+	w := serveBackfill(h, http.MethodPost, "/admin/backfill", "/admin/backfill", `{"from":"2023-01-01","to":"2023-01-31","char_codes":["USD"]}`, h.StartBackfill)
+
+	assert.Equal(t, http.StatusAccepted, w.Code)
+	var response usecase.BackfillJobResponse
+	json.Unmarshal(w.Body.Bytes(), &response)
+	assert.Equal(t, expected.ID, response.ID)
+	assert.Equal(t, "pending", response.Status)
+	mockUsecase.AssertExpectations(t)
+}
+
+func TestStartBackfill_InvalidBody(t *testing.T) {
+	h, mockUsecase := setupBackfillHandler()
+
+	w := serveBackfill(h, http.MethodPost, "/admin/backfill", "/admin/backfill", `{"to":"2023-01-31"}`, h.StartBackfill)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	var response ErrorResponse
+	json.Unmarshal(w.Body.Bytes(), &response)
+	assert.Equal(t, CodeInvalidRequest, response.Code)
+	mockUsecase.AssertNotCalled(t, "StartBackfill", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestStartBackfill_InvalidDate(t *testing.T) {
+	h, _ := setupBackfillHandler()
+
+	w := serveBackfill(h, http.MethodPost, "/admin/backfill", "/admin/backfill", `{"from":"01.01.2023"}`, h.StartBackfill)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	var response ErrorResponse
+	json.Unmarshal(w.Body.Bytes(), &response)
+	assert.Equal(t, CodeInvalidDate, response.Code)
+}
+
+func TestGetBackfillJob_NotFound(t *testing.T) {
+	h, mockUsecase := setupBackfillHandler()
+
+	mockUsecase.On("GetBackfillJob", mock.Anything, int64(9)).Return(nil, fmt.Errorf("%w: 9", usecase.ErrBackfillJobNotFound))
+
+	w := serveBackfill(h, http.MethodGet, "/admin/backfill/:id", "/admin/backfill/9", "", h.GetBackfillJob)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	var response ErrorResponse
+	json.Unmarshal(w.Body.Bytes(), &response)
+	assert.Equal(t, CodeNotFound, response.Code)
+}
+
+func TestGetBackfillJob_InvalidID(t *testing.T) {
+	h, _ := setupBackfillHandler()
+
+	w := serveBackfill(h, http.MethodGet, "/admin/backfill/:id", "/admin/backfill/abc", "", h.GetBackfillJob)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestResumeBackfill_Conflict(t *testing.T) {
+	h, mockUsecase := setupBackfillHandler()
+
+	mockUsecase.On("ResumeBackfill", mock.Anything, int64(3)).Return(nil, fmt.Errorf("%w: 3", usecase.ErrBackfillJobRunning))
+
+	w := serveBackfill(h, http.MethodPost, "/admin/backfill/:id/resume", "/admin/backfill/3/resume", "", h.ResumeBackfill)
+
+	assert.Equal(t, http.StatusConflict, w.Code)
+	var response ErrorResponse
+	json.Unmarshal(w.Body.Bytes(), &response)
+	assert.Equal(t, CodeConflict, response.Code)
+}
+
+func TestListBackfillJobs(t *testing.T) {
+	h, mockUsecase := setupBackfillHandler()
+
+	mockUsecase.On("ListBackfillJobs", mock.Anything).Return([]usecase.BackfillJobResponse{{ID: 2}, {ID: 1}}, nil)
+
+	w := serveBackfill(h, http.MethodGet, "/admin/backfill", "/admin/backfill", "", h.ListBackfillJobs)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var response struct {
+		Jobs []usecase.BackfillJobResponse `json:"jobs"`
+	}
+	json.Unmarshal(w.Body.Bytes(), &response)
+	assert.Len(t, response.Jobs, 2)
+}
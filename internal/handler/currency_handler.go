@@ -2,15 +2,18 @@ package handler
 
 import (
 	"RnD-service/internal/usecase"
+	"fmt"
 	"net/http"
-	"strconv"
-	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/shopspring/decimal"
 	"github.com/sirupsen/logrus"
 )
 
+// maxBatchItems bounds a batch conversion request.
+const maxBatchItems = 1000
+
 type CurrencyHandler struct {
 	usecase usecase.RateUsecase
 	logger  *logrus.Logger
@@ -24,9 +27,8 @@ func NewRateHandler(usecase usecase.RateUsecase, logger *logrus.Logger) *Currenc
 }
 
 func (h *CurrencyHandler) StoreRatesFromCBR(c *gin.Context) {
-	if err := h.usecase.FetchAndStoreRatesFromCBR(c.Request.Context()); err != nil {
-		h.logger.Errorf("Failed to fetch and store rates: %v", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch rates"})
+	if err := h.usecase.FetchAndStoreRates(c.Request.Context(), c.Query("source")); err != nil {
+		c.Error(fmt.Errorf("fetch and store rates: %w", err))
 		return
 	}
 
@@ -37,61 +39,241 @@ func (h *CurrencyHandler) GetHistoricalRateByCharCode(c *gin.Context) {
 	valCode := c.Query("val")
 	amountStr := c.Query("amount")
 	dateStr := c.Query("date")
+	source := c.Query("source")
 
 	if valCode == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "missing required query parameter 'val'"})
+		c.Error(fmt.Errorf("%w 'val'", ErrMissingParameter))
 		return
 	}
 
 	var date time.Time
-	var err error
 	if dateStr == "" {
 		date = time.Now().Truncate(24 * time.Hour)
 		h.logger.Debugf("Date parameter not provided, using default (today): %s", date.Format("2006-01-02"))
 	} else {
-		date, err = time.Parse("2006-01-02", dateStr)
+		parsedDate, err := parseDate(dateStr)
 		if err != nil {
-			h.logger.WithError(err).Errorf("Invalid date format: %s", dateStr)
-			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid date format, expected YYYY-MM-DD"})
+			c.Error(err)
 			return
 		}
-		date = date.Truncate(24 * time.Hour)
+		date = parsedDate
 	}
 
-	amount := 1.0
-	if amountStr != "" {
-		parsedAmount, err := strconv.ParseFloat(amountStr, 64)
-		if err != nil || parsedAmount <= 0 {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid 'amount' parameter, must be a positive number"})
-			return
-		}
-		amount = parsedAmount
+	amount, err := parseAmount(amountStr)
+	if err != nil {
+		c.Error(err)
+		return
 	}
 
+	// CBR publishes tomorrow's rates today, so tomorrow is left to the usecase
 	today := time.Now().Truncate(24 * time.Hour)
-	if date.After(today) {
+	if date.After(today.AddDate(0, 0, 1)) {
 		h.logger.Debugf("Requested future date: %s, canceling...", date.Format("2006-01-02"))
-		c.JSON(http.StatusBadRequest, gin.H{"error": "cannot fetch rates for future dates"})
+		c.Error(usecase.ErrFutureDate)
 		return
 	}
 
-	result, err := h.usecase.GetHistoricalRateByCharCode(c.Request.Context(), valCode, date, amount)
+	result, err := h.usecase.GetHistoricalRateByCharCode(c.Request.Context(), source, valCode, date, amount)
 	if err != nil {
-		statusCode := http.StatusInternalServerError
-		errorMsg := err.Error()
-		if strings.Contains(errorMsg, "invalid char code") || strings.Contains(errorMsg, "invalid date format") || strings.Contains(errorMsg, "invalid 'amount'") {
-			statusCode = http.StatusBadRequest
-		} else if strings.Contains(errorMsg, "not found") {
-			statusCode = http.StatusNotFound
-		} else if strings.Contains(errorMsg, "future dates") {
-			statusCode = http.StatusBadRequest
-		} else if strings.Contains(errorMsg, "no rates available") {
-			statusCode = http.StatusNotFound
+		c.Error(fmt.Errorf("get historical rate for val=%s, date=%s: %w", valCode, date.Format("2006-01-02"), err))
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+func (h *CurrencyHandler) GetRateHistoryByCharCode(c *gin.Context) {
+	valCode := c.Query("val")
+	fromStr := c.Query("from")
+	toStr := c.Query("to")
+	source := c.Query("source")
+
+	if valCode == "" {
+		c.Error(fmt.Errorf("%w 'val'", ErrMissingParameter))
+		return
+	}
+	if fromStr == "" {
+		c.Error(fmt.Errorf("%w 'from'", ErrMissingParameter))
+		return
+	}
+
+	from, err := parseDate(fromStr)
+	if err != nil {
+		c.Error(fmt.Errorf("'from': %w", err))
+		return
+	}
+
+	var to time.Time
+	if toStr == "" {
+		to = time.Now().Truncate(24 * time.Hour)
+		h.logger.Debugf("'to' parameter not provided, using default (today): %s", to.Format("2006-01-02"))
+	} else {
+		to, err = parseDate(toStr)
+		if err != nil {
+			c.Error(fmt.Errorf("'to': %w", err))
+			return
 		}
-		h.logger.WithError(err).Errorf("Failed to get historical rate for val=%s, date=%s, amount=%.2f", valCode, date.Format("2006-01-02"), amount)
-		c.JSON(statusCode, gin.H{"error": errorMsg})
+	}
+
+	result, err := h.usecase.GetRateHistoryByCharCode(c.Request.Context(), source, valCode, from, to)
+	if err != nil {
+		c.Error(fmt.Errorf("get rate history for val=%s, from=%s, to=%s: %w", valCode, fromStr, toStr, err))
 		return
 	}
 
 	c.JSON(http.StatusOK, result)
 }
+
+func (h *CurrencyHandler) GetRateStats(c *gin.Context) {
+	valCode := c.Query("val")
+	if valCode == "" {
+		c.Error(fmt.Errorf("%w 'val'", ErrMissingParameter))
+		return
+	}
+
+	from, to, err := parseDateRange(c, h.logger)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	result, err := h.usecase.GetRateStats(c.Request.Context(), c.Query("source"), valCode, from, to)
+	if err != nil {
+		c.Error(fmt.Errorf("get rate stats for val=%s, from=%s, to=%s: %w", valCode, from.Format("2006-01-02"), to.Format("2006-01-02"), err))
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+func (h *CurrencyHandler) ConvertCurrency(c *gin.Context) {
+	from := c.Query("from")
+	to := c.Query("to")
+	amountStr := c.Query("amount")
+	dateStr := c.Query("date")
+	source := c.Query("source")
+
+	if from == "" || to == "" {
+		c.Error(fmt.Errorf("%w 'from' and 'to'", ErrMissingParameter))
+		return
+	}
+
+	var date time.Time
+	if dateStr != "" {
+		parsedDate, err := parseDate(dateStr)
+		if err != nil {
+			c.Error(err)
+			return
+		}
+		date = parsedDate
+	}
+
+	amount, err := parseAmount(amountStr)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	result, err := h.usecase.ConvertCurrency(c.Request.Context(), source, from, to, amount, date)
+	if err != nil {
+		c.Error(fmt.Errorf("convert from=%s to=%s, date=%s: %w", from, to, dateStr, err))
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// ConvertBatch converts a JSON array of items with the optional 'source' query
+// parameter. It answers 200 with a result or an error per item; only a
+// malformed body or an unknown source fail the whole request.
+func (h *CurrencyHandler) ConvertBatch(c *gin.Context) {
+	var req []ConvertItemRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(fmt.Errorf("%w: %v", ErrInvalidRequest, err))
+		return
+	}
+	if len(req) == 0 || len(req) > maxBatchItems {
+		c.Error(fmt.Errorf("%w: batch must contain 1 to %d items, got %d", ErrInvalidRequest, maxBatchItems, len(req)))
+		return
+	}
+
+	response := BatchConvertResponse{
+		Count:   len(req),
+		Results: make([]BatchConvertResult, len(req)),
+	}
+
+	// items with an unparsable date never reach the usecase
+	items := make([]usecase.ConversionItem, 0, len(req))
+	positions := make([]int, 0, len(req))
+	for i, item := range req {
+		response.Results[i].Index = i
+
+		var date time.Time
+		if item.Date != "" {
+			parsedDate, err := parseDate(item.Date)
+			if err != nil {
+				_, e := errorResponse(err)
+				response.Results[i].Error = &e
+				continue
+			}
+			date = parsedDate
+		}
+
+		items = append(items, usecase.ConversionItem{From: item.From, To: item.To, Amount: item.Amount, Date: date})
+		positions = append(positions, i)
+	}
+
+	results, err := h.usecase.ConvertBatch(c.Request.Context(), c.Query("source"), items)
+	if err != nil {
+		c.Error(fmt.Errorf("convert batch of %d items: %w", len(req), err))
+		return
+	}
+
+	for j, result := range results {
+		i := positions[j]
+		if result.Err != nil {
+			_, e := errorResponse(result.Err)
+			response.Results[i].Error = &e
+			continue
+		}
+		response.Results[i].Result = result.Result
+	}
+	for _, result := range response.Results {
+		if result.Error != nil {
+			response.Failed++
+		}
+	}
+
+	h.logger.Infof("Converted batch of %d items, %d failed", response.Count, response.Failed)
+	c.JSON(http.StatusOK, response)
+}
+
+func (h *CurrencyHandler) GetCurrencyList(c *gin.Context) {
+	result, err := h.usecase.GetCurrencyList(c.Request.Context())
+	if err != nil {
+		c.Error(fmt.Errorf("get currency list: %w", err))
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+func parseDate(dateStr string) (time.Time, error) {
+	date, err := time.Parse("2006-01-02", dateStr)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%w: %q", ErrInvalidDateFormat, dateStr)
+	}
+	return date.Truncate(24 * time.Hour), nil
+}
+
+func parseAmount(amountStr string) (decimal.Decimal, error) {
+	if amountStr == "" {
+		return decimal.NewFromInt(1), nil
+	}
+
+	amount, err := decimal.NewFromString(amountStr)
+	if err != nil || !amount.IsPositive() {
+		return decimal.Decimal{}, usecase.ErrInvalidAmount
+	}
+	return amount, nil
+}
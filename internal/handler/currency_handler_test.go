@@ -4,14 +4,18 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 
+	"RnD-service/internal/adapter/cbr"
 	"RnD-service/internal/usecase"
 
 	"github.com/gin-gonic/gin"
+	"github.com/shopspring/decimal"
 	"github.com/sirupsen/logrus"
 	"github.com/sirupsen/logrus/hooks/test"
 	"github.com/stretchr/testify/assert"
@@ -27,22 +31,67 @@ func (m *mockRateUsecase) FetchAndStoreRatesFromCBR(ctx context.Context) error {
 	return args.Error(0)
 }
 
-func (m *mockRateUsecase) GetHistoricalRateByCharCode(ctx context.Context, charCode string, date time.Time, amount float64) (*usecase.CurrencyResponse, error) {
-	args := m.Called(ctx, charCode, date, amount)
+func (m *mockRateUsecase) FetchAndStoreRates(ctx context.Context, source string) error {
+	args := m.Called(ctx, source)
+	return args.Error(0)
+}
+
+func (m *mockRateUsecase) GetHistoricalRateByCharCode(ctx context.Context, source, charCode string, date time.Time, amount decimal.Decimal) (*usecase.CurrencyResponse, error) {
+	args := m.Called(ctx, source, charCode, date, amount)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
 	return args.Get(0).(*usecase.CurrencyResponse), args.Error(1)
 }
 
-func (m *mockRateUsecase) GetRateByCharCode(ctx context.Context, charCode string, amount float64) (*usecase.CurrencyResponse, error) {
-	args := m.Called(ctx, charCode, amount)
+func (m *mockRateUsecase) GetRateByCharCode(ctx context.Context, source, charCode string, amount decimal.Decimal) (*usecase.CurrencyResponse, error) {
+	args := m.Called(ctx, source, charCode, amount)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
 	return args.Get(0).(*usecase.CurrencyResponse), args.Error(1)
 }
 
+func (m *mockRateUsecase) GetRateHistoryByCharCode(ctx context.Context, source, charCode string, dateFrom, dateTo time.Time) (*usecase.RateHistoryResponse, error) {
+	args := m.Called(ctx, source, charCode, dateFrom, dateTo)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*usecase.RateHistoryResponse), args.Error(1)
+}
+
+func (m *mockRateUsecase) GetRateStats(ctx context.Context, source, charCode string, dateFrom, dateTo time.Time) (*usecase.RateStatsResponse, error) {
+	args := m.Called(ctx, source, charCode, dateFrom, dateTo)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*usecase.RateStatsResponse), args.Error(1)
+}
+
+func (m *mockRateUsecase) ConvertCurrency(ctx context.Context, source, from, to string, amount decimal.Decimal, date time.Time) (*usecase.ConversionResponse, error) {
+	args := m.Called(ctx, source, from, to, amount, date)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*usecase.ConversionResponse), args.Error(1)
+}
+
+func (m *mockRateUsecase) ConvertBatch(ctx context.Context, source string, items []usecase.ConversionItem) ([]usecase.ConversionResult, error) {
+	args := m.Called(ctx, source, items)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]usecase.ConversionResult), args.Error(1)
+}
+
+func (m *mockRateUsecase) GetCurrencyList(ctx context.Context) (*usecase.CurrencyListResponse, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*usecase.CurrencyListResponse), args.Error(1)
+}
+
 func setupTestHandler() (*CurrencyHandler, *mockRateUsecase, *logrus.Logger, *test.Hook) {
 	mockUsecase := new(mockRateUsecase)
 	logger, hook := test.NewNullLogger()
@@ -50,17 +99,21 @@ func setupTestHandler() (*CurrencyHandler, *mockRateUsecase, *logrus.Logger, *te
 	return handler, mockUsecase, logger, hook
 }
 
+func performRequest(handler *CurrencyHandler, h gin.HandlerFunc, target string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	_, r := gin.CreateTestContext(w)
+	r.Use(ErrorMiddleware(handler.logger))
+	r.GET("/", h)
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, target, nil))
+	return w
+}
+
 func TestStoreRatesFromCBR_Success(t *testing.T) {
 	handler, mockUsecase, _, _ := setupTestHandler()
 
-	mockUsecase.On("FetchAndStoreRatesFromCBR", mock.Anything).Return(nil)
+	mockUsecase.On("FetchAndStoreRates", mock.Anything, "").Return(nil)
 
-	w := httptest.NewRecorder()
-	c, _ := gin.CreateTestContext(w)
-	// Initialize the Request field to avoid nil pointer dereference
-	c.Request, _ = http.NewRequest("GET", "/", nil)
-
-	handler.StoreRatesFromCBR(c)
+	w := performRequest(handler, handler.StoreRatesFromCBR, "/")
 
 	assert.Equal(t, http.StatusOK, w.Code)
 	var response map[string]string
@@ -74,19 +127,15 @@ func TestStoreRatesFromCBR_Error(t *testing.T) {
 	handler, mockUsecase, _, _ := setupTestHandler()
 
 	expectedErr := errors.New("usecase error")
-	mockUsecase.On("FetchAndStoreRatesFromCBR", mock.Anything).Return(expectedErr)
-
-	w := httptest.NewRecorder()
-	c, _ := gin.CreateTestContext(w)
-	// Initialize the Request field
-	c.Request, _ = http.NewRequest("GET", "/", nil)
+	mockUsecase.On("FetchAndStoreRates", mock.Anything, "").Return(expectedErr)
 
-	handler.StoreRatesFromCBR(c)
+	w := performRequest(handler, handler.StoreRatesFromCBR, "/")
 
 	assert.Equal(t, http.StatusInternalServerError, w.Code)
 	var response map[string]string
 	json.Unmarshal(w.Body.Bytes(), &response)
-	assert.Equal(t, "Failed to fetch rates", response["error"])
+	assert.Equal(t, "internal server error", response["error"])
+	assert.Equal(t, CodeInternal, response["code"])
 
 	mockUsecase.AssertExpectations(t)
 }
@@ -94,82 +143,77 @@ func TestStoreRatesFromCBR_Error(t *testing.T) {
 func TestGetHistoricalRateByCharCode_MissingVal(t *testing.T) {
 	handler, _, _, _ := setupTestHandler()
 
-	w := httptest.NewRecorder()
-	c, _ := gin.CreateTestContext(w)
-	// Initialize the Request field
-	c.Request, _ = http.NewRequest("GET", "/", nil)
-
-	handler.GetHistoricalRateByCharCode(c)
+	w := performRequest(handler, handler.GetHistoricalRateByCharCode, "/")
 
 	assert.Equal(t, http.StatusBadRequest, w.Code)
 	var response map[string]string
 	json.Unmarshal(w.Body.Bytes(), &response)
+	assert.Equal(t, CodeMissingParameter, response["code"])
 	assert.Contains(t, response["error"], "missing required query parameter 'val'")
 }
 
 func TestGetHistoricalRateByCharCode_InvalidDate(t *testing.T) {
 	handler, _, _, _ := setupTestHandler()
 
-	w := httptest.NewRecorder()
-	c, _ := gin.CreateTestContext(w)
-	c.Request, _ = http.NewRequest("GET", "/?val=USD&date=invalid", nil)
-
-	handler.GetHistoricalRateByCharCode(c)
+	w := performRequest(handler, handler.GetHistoricalRateByCharCode, "/?val=USD&date=invalid")
 
 	assert.Equal(t, http.StatusBadRequest, w.Code)
 	var response map[string]string
 	json.Unmarshal(w.Body.Bytes(), &response)
-	assert.Contains(t, response["error"], "invalid date format")
+	assert.Equal(t, CodeInvalidDate, response["code"])
 }
 
 func TestGetHistoricalRateByCharCode_InvalidAmount(t *testing.T) {
 	handler, _, _, _ := setupTestHandler()
 
-	w := httptest.NewRecorder()
-	c, _ := gin.CreateTestContext(w)
-	c.Request, _ = http.NewRequest("GET", "/?val=USD&amount=invalid", nil)
-
-	handler.GetHistoricalRateByCharCode(c)
+	w := performRequest(handler, handler.GetHistoricalRateByCharCode, "/?val=USD&amount=invalid")
 
 	assert.Equal(t, http.StatusBadRequest, w.Code)
 	var response map[string]string
 	json.Unmarshal(w.Body.Bytes(), &response)
-	assert.Contains(t, response["error"], "invalid 'amount' parameter")
+	assert.Equal(t, CodeInvalidAmount, response["code"])
 }
 
 func TestGetHistoricalRateByCharCode_FutureDate(t *testing.T) {
 	handler, _, _, _ := setupTestHandler()
 
-	futureDate := time.Now().Add(24 * time.Hour).Format("2006-01-02")
-
-	w := httptest.NewRecorder()
-	c, _ := gin.CreateTestContext(w)
-	c.Request, _ = http.NewRequest("GET", "/?val=USD&date="+futureDate, nil)
+	futureDate := time.Now().Add(48 * time.Hour).Format("2006-01-02")
 
-	handler.GetHistoricalRateByCharCode(c)
+	w := performRequest(handler, handler.GetHistoricalRateByCharCode, "/?val=USD&date="+futureDate)
 
 	assert.Equal(t, http.StatusBadRequest, w.Code)
 	var response map[string]string
 	json.Unmarshal(w.Body.Bytes(), &response)
-	assert.Contains(t, response["error"], "cannot fetch rates for future dates")
+	assert.Equal(t, CodeFutureDate, response["code"])
+}
+
+func TestGetHistoricalRateByCharCode_NoDataUpstream(t *testing.T) {
+	handler, mockUsecase, _, _ := setupTestHandler()
+
+	noData := fmt.Errorf("%w: cbr has no data: %w", usecase.ErrRateNotFound, fmt.Errorf("%w: no Valute elements for 01.01.1991", cbr.ErrNoData))
+	mockUsecase.On("GetHistoricalRateByCharCode", mock.Anything, "", "USD", mock.AnythingOfType("time.Time"), decimal.NewFromInt(1)).Return((*usecase.CurrencyResponse)(nil), noData)
+
+	w := performRequest(handler, handler.GetHistoricalRateByCharCode, "/?val=USD&date=1991-01-01")
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	var response map[string]string
+	json.Unmarshal(w.Body.Bytes(), &response)
+	assert.Equal(t, CodeNotFound, response["code"])
 }
 
 func TestGetHistoricalRateByCharCode_UsecaseError(t *testing.T) {
 	handler, mockUsecase, _, _ := setupTestHandler()
 
 	expectedErr := errors.New("usecase error")
-	mockUsecase.On("GetHistoricalRateByCharCode", mock.Anything, "USD", mock.AnythingOfType("time.Time"), 1.0).Return((*usecase.CurrencyResponse)(nil), expectedErr)
+	mockUsecase.On("GetHistoricalRateByCharCode", mock.Anything, "", "USD", mock.AnythingOfType("time.Time"), decimal.NewFromInt(1)).Return((*usecase.CurrencyResponse)(nil), expectedErr)
 
-	w := httptest.NewRecorder()
-	c, _ := gin.CreateTestContext(w)
-	c.Request, _ = http.NewRequest("GET", "/?val=USD", nil)
-
-	handler.GetHistoricalRateByCharCode(c)
+	w := performRequest(handler, handler.GetHistoricalRateByCharCode, "/?val=USD")
 
 	assert.Equal(t, http.StatusInternalServerError, w.Code)
 	var response map[string]string
 	json.Unmarshal(w.Body.Bytes(), &response)
-	assert.Equal(t, expectedErr.Error(), response["error"])
+	assert.Equal(t, "internal server error", response["error"])
+	assert.Equal(t, CodeInternal, response["code"])
 
 	mockUsecase.AssertExpectations(t)
 }
@@ -177,16 +221,15 @@ func TestGetHistoricalRateByCharCode_UsecaseError(t *testing.T) {
 func TestGetHistoricalRateByCharCode_NotFoundError(t *testing.T) {
 	handler, mockUsecase, _, _ := setupTestHandler()
 
-	expectedErr := errors.New("not found")
-	mockUsecase.On("GetHistoricalRateByCharCode", mock.Anything, "USD", mock.AnythingOfType("time.Time"), 1.0).Return((*usecase.CurrencyResponse)(nil), expectedErr)
-
-	w := httptest.NewRecorder()
-	c, _ := gin.CreateTestContext(w)
-	c.Request, _ = http.NewRequest("GET", "/?val=USD", nil)
+	expectedErr := fmt.Errorf("%w: currency code USD for date 2025-08-01", usecase.ErrRateNotFound)
+	mockUsecase.On("GetHistoricalRateByCharCode", mock.Anything, "", "USD", mock.AnythingOfType("time.Time"), decimal.NewFromInt(1)).Return((*usecase.CurrencyResponse)(nil), expectedErr)
 
-	handler.GetHistoricalRateByCharCode(c)
+	w := performRequest(handler, handler.GetHistoricalRateByCharCode, "/?val=USD")
 
 	assert.Equal(t, http.StatusNotFound, w.Code)
+	var response map[string]string
+	json.Unmarshal(w.Body.Bytes(), &response)
+	assert.Equal(t, CodeNotFound, response["code"])
 
 	mockUsecase.AssertExpectations(t)
 }
@@ -196,15 +239,11 @@ func TestGetHistoricalRateByCharCode_Success(t *testing.T) {
 
 	expectedResponse := &usecase.CurrencyResponse{
 		CharCode: "USD",
-		ValueRUB: 90.5,
+		ValueRUB: decimal.RequireFromString("90.5"),
 	}
-	mockUsecase.On("GetHistoricalRateByCharCode", mock.Anything, "USD", mock.AnythingOfType("time.Time"), 1.0).Return(expectedResponse, nil)
-
-	w := httptest.NewRecorder()
-	c, _ := gin.CreateTestContext(w)
-	c.Request, _ = http.NewRequest("GET", "/?val=USD", nil)
+	mockUsecase.On("GetHistoricalRateByCharCode", mock.Anything, "", "USD", mock.AnythingOfType("time.Time"), decimal.NewFromInt(1)).Return(expectedResponse, nil)
 
-	handler.GetHistoricalRateByCharCode(c)
+	w := performRequest(handler, handler.GetHistoricalRateByCharCode, "/?val=USD")
 
 	assert.Equal(t, http.StatusOK, w.Code)
 	var response usecase.CurrencyResponse
@@ -218,18 +257,14 @@ func TestGetHistoricalRateByCharCode_WithAmountAndDate(t *testing.T) {
 	handler, mockUsecase, _, _ := setupTestHandler()
 
 	date := time.Date(2025, 8, 1, 0, 0, 0, 0, time.UTC)
-	amount := 2.0
+	amount := decimal.NewFromInt(2)
 	expectedResponse := &usecase.CurrencyResponse{
 		CharCode: "USD",
-		ValueRUB: 181.0,
+		ValueRUB: decimal.RequireFromString("181"),
 	}
-	mockUsecase.On("GetHistoricalRateByCharCode", mock.Anything, "USD", date, amount).Return(expectedResponse, nil)
+	mockUsecase.On("GetHistoricalRateByCharCode", mock.Anything, "", "USD", date, amount).Return(expectedResponse, nil)
 
-	w := httptest.NewRecorder()
-	c, _ := gin.CreateTestContext(w)
-	c.Request, _ = http.NewRequest("GET", "/?val=USD&amount=2&date=2025-08-01", nil)
-
-	handler.GetHistoricalRateByCharCode(c)
+	w := performRequest(handler, handler.GetHistoricalRateByCharCode, "/?val=USD&amount=2&date=2025-08-01")
 
 	assert.Equal(t, http.StatusOK, w.Code)
 	var response usecase.CurrencyResponse
@@ -238,3 +273,404 @@ func TestGetHistoricalRateByCharCode_WithAmountAndDate(t *testing.T) {
 
 	mockUsecase.AssertExpectations(t)
 }
+
+func TestGetRateHistoryByCharCode_MissingFrom(t *testing.T) {
+	handler, _, _, _ := setupTestHandler()
+
+	w := performRequest(handler, handler.GetRateHistoryByCharCode, "/?val=USD")
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	var response map[string]string
+	json.Unmarshal(w.Body.Bytes(), &response)
+	assert.Equal(t, CodeMissingParameter, response["code"])
+	assert.Contains(t, response["error"], "missing required query parameter 'from'")
+}
+
+func TestGetRateHistoryByCharCode_InvalidDate(t *testing.T) {
+	handler, _, _, _ := setupTestHandler()
+
+	w := performRequest(handler, handler.GetRateHistoryByCharCode, "/?val=USD&from=2025-08-01&to=invalid")
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	var response map[string]string
+	json.Unmarshal(w.Body.Bytes(), &response)
+	assert.Equal(t, CodeInvalidDate, response["code"])
+	assert.Contains(t, response["error"], "'to'")
+}
+
+func TestGetRateHistoryByCharCode_InvalidRange(t *testing.T) {
+	handler, mockUsecase, _, _ := setupTestHandler()
+
+	from := time.Date(2025, 8, 2, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2025, 8, 1, 0, 0, 0, 0, time.UTC)
+	expectedErr := fmt.Errorf("%w: 'from' is after 'to'", usecase.ErrInvalidDateRange)
+	mockUsecase.On("GetRateHistoryByCharCode", mock.Anything, "", "USD", from, to).Return((*usecase.RateHistoryResponse)(nil), expectedErr)
+
+	w := performRequest(handler, handler.GetRateHistoryByCharCode, "/?val=USD&from=2025-08-02&to=2025-08-01")
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	var response map[string]string
+	json.Unmarshal(w.Body.Bytes(), &response)
+	assert.Equal(t, CodeInvalidDateRange, response["code"])
+
+	mockUsecase.AssertExpectations(t)
+}
+
+func TestGetRateHistoryByCharCode_Success(t *testing.T) {
+	handler, mockUsecase, _, _ := setupTestHandler()
+
+	from := time.Date(2025, 8, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2025, 8, 2, 0, 0, 0, 0, time.UTC)
+	expectedResponse := &usecase.RateHistoryResponse{
+		CharCode: "USD",
+		From:     "2025-08-01",
+		To:       "2025-08-02",
+		Rates: []usecase.RatePoint{
+			{Date: "2025-08-01", Nominal: 1, Value: decimal.RequireFromString("90.5"), ValueRUB: decimal.RequireFromString("90.5")},
+			{Date: "2025-08-02", Nominal: 1, Value: decimal.RequireFromString("91"), ValueRUB: decimal.RequireFromString("91")},
+		},
+	}
+	mockUsecase.On("GetRateHistoryByCharCode", mock.Anything, "", "USD", from, to).Return(expectedResponse, nil)
+
+	w := performRequest(handler, handler.GetRateHistoryByCharCode, "/?val=USD&from=2025-08-01&to=2025-08-02")
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var response usecase.RateHistoryResponse
+	json.Unmarshal(w.Body.Bytes(), &response)
+	assert.Equal(t, expectedResponse, &response)
+
+	mockUsecase.AssertExpectations(t)
+}
+
+func TestConvertCurrency_MissingParams(t *testing.T) {
+	handler, _, _, _ := setupTestHandler()
+
+	w := performRequest(handler, handler.ConvertCurrency, "/?from=USD")
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	var response map[string]string
+	json.Unmarshal(w.Body.Bytes(), &response)
+	assert.Equal(t, CodeMissingParameter, response["code"])
+}
+
+func TestConvertCurrency_InvalidAmount(t *testing.T) {
+	handler, _, _, _ := setupTestHandler()
+
+	w := performRequest(handler, handler.ConvertCurrency, "/?from=USD&to=EUR&amount=-5")
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	var response map[string]string
+	json.Unmarshal(w.Body.Bytes(), &response)
+	assert.Equal(t, CodeInvalidAmount, response["code"])
+}
+
+func TestConvertCurrency_NotFoundError(t *testing.T) {
+	handler, mockUsecase, _, _ := setupTestHandler()
+
+	expectedErr := fmt.Errorf("%w: currency code XXX for date 2025-08-01", usecase.ErrRateNotFound)
+	mockUsecase.On("ConvertCurrency", mock.Anything, "", "USD", "XXX", decimal.NewFromInt(1), time.Date(2025, 8, 1, 0, 0, 0, 0, time.UTC)).Return((*usecase.ConversionResponse)(nil), expectedErr)
+
+	w := performRequest(handler, handler.ConvertCurrency, "/?from=USD&to=XXX&date=2025-08-01")
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	var response map[string]string
+	json.Unmarshal(w.Body.Bytes(), &response)
+	assert.Equal(t, CodeNotFound, response["code"])
+
+	mockUsecase.AssertExpectations(t)
+}
+
+func TestConvertCurrency_Success(t *testing.T) {
+	handler, mockUsecase, _, _ := setupTestHandler()
+
+	expectedResponse := &usecase.ConversionResponse{
+		From:   "USD",
+		To:     "EUR",
+		Amount: decimal.NewFromInt(100),
+		Rate:   decimal.RequireFromString("0.8"),
+		Result: decimal.NewFromInt(80),
+		Date:   "2025-08-01",
+	}
+	mockUsecase.On("ConvertCurrency", mock.Anything, "", "USD", "EUR", decimal.NewFromInt(100), time.Date(2025, 8, 1, 0, 0, 0, 0, time.UTC)).Return(expectedResponse, nil)
+
+	w := performRequest(handler, handler.ConvertCurrency, "/?from=USD&to=EUR&amount=100&date=2025-08-01")
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var response usecase.ConversionResponse
+	json.Unmarshal(w.Body.Bytes(), &response)
+	assert.Equal(t, expectedResponse, &response)
+
+	mockUsecase.AssertExpectations(t)
+}
+
+func TestGetHistoricalRateByCharCode_AmountKeepsPrecision(t *testing.T) {
+	handler, mockUsecase, _, _ := setupTestHandler()
+
+	amount := decimal.RequireFromString("0.10000000000000000001")
+	expectedResponse := &usecase.CurrencyResponse{
+		CharCode: "USD",
+		ValueRUB: decimal.RequireFromString("9.05"),
+		Source:   "cbr",
+		Base:     "RUB",
+	}
+	mockUsecase.On("GetHistoricalRateByCharCode", mock.Anything, "", "USD", mock.AnythingOfType("time.Time"), amount).Return(expectedResponse, nil)
+
+	w := performRequest(handler, handler.GetHistoricalRateByCharCode, "/?val=USD&amount=0.10000000000000000001")
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"char_name":"USD","value_rub":"9.05","source":"cbr","base":"RUB","fallback":false}`, w.Body.String())
+
+	mockUsecase.AssertExpectations(t)
+}
+
+func TestConvertCurrency_PassesSource(t *testing.T) {
+	handler, mockUsecase, _, _ := setupTestHandler()
+
+	date := time.Date(2025, 8, 1, 0, 0, 0, 0, time.UTC)
+	expectedResponse := &usecase.ConversionResponse{From: "EUR", To: "USD", Amount: decimal.NewFromInt(1), Rate: decimal.RequireFromString("1.1404"), Result: decimal.RequireFromString("1.1404"), Date: "2025-08-01", Source: "ecb"}
+	mockUsecase.On("ConvertCurrency", mock.Anything, "ecb", "EUR", "USD", decimal.NewFromInt(1), date).Return(expectedResponse, nil)
+
+	w := performRequest(handler, handler.ConvertCurrency, "/?from=EUR&to=USD&date=2025-08-01&source=ecb")
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var response usecase.ConversionResponse
+	json.Unmarshal(w.Body.Bytes(), &response)
+	assert.Equal(t, "ecb", response.Source)
+
+	mockUsecase.AssertExpectations(t)
+}
+
+func TestErrorMiddleware_MapsDomainErrors(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		wantStatus int
+		wantCode   string
+	}{
+		{"invalid char code", fmt.Errorf("%w: usd", usecase.ErrInvalidCharCode), http.StatusBadRequest, CodeInvalidCharCode},
+		{"unknown currency", fmt.Errorf("%w: XYZ", usecase.ErrUnknownCurrency), http.StatusBadRequest, CodeUnknownCurrency},
+		{"future date", usecase.ErrFutureDate, http.StatusBadRequest, CodeFutureDate},
+		{"unknown source", fmt.Errorf("%w: nbk", usecase.ErrUnknownSource), http.StatusBadRequest, CodeUnknownSource},
+		{"not found", fmt.Errorf("wrapped: %w", usecase.ErrRateNotFound), http.StatusNotFound, CodeNotFound},
+		{"no data upstream", fmt.Errorf("%w: cbr has no data: %w", usecase.ErrRateNotFound, cbr.ErrNoData), http.StatusNotFound, CodeNotFound},
+		{"upstream unavailable", fmt.Errorf("%w: timeout", usecase.ErrUpstreamUnavailable), http.StatusBadGateway, CodeUpstreamUnavailable},
+		{"upstream date mismatch", fmt.Errorf("%w", usecase.ErrUpstreamDateMismatch), http.StatusBadGateway, CodeUpstreamDateMismatch},
+		{"unknown", errors.New("boom"), http.StatusInternalServerError, CodeInternal},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logger, hook := test.NewNullLogger()
+			handler := NewRateHandler(new(mockRateUsecase), logger)
+
+			w := performRequest(handler, func(c *gin.Context) { c.Error(tt.err) }, "/")
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+			var response ErrorResponse
+			json.Unmarshal(w.Body.Bytes(), &response)
+			assert.Equal(t, tt.wantCode, response.Code)
+			assert.NotEmpty(t, response.Error)
+			assert.Len(t, hook.Entries, 1)
+		})
+	}
+}
+
+func TestErrorMiddleware_NoErrors(t *testing.T) {
+	handler, _, _, hook := setupTestHandler()
+
+	w := performRequest(handler, func(c *gin.Context) { c.JSON(http.StatusOK, gin.H{"ok": true}) }, "/")
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"ok":true}`, w.Body.String())
+	assert.Empty(t, hook.Entries)
+}
+
+func TestGetCurrencyList_Success(t *testing.T) {
+	handler, mockUsecase, _, _ := setupTestHandler()
+
+	expectedResponse := &usecase.CurrencyListResponse{
+		Count: 1,
+		Currencies: []usecase.CurrencyListItem{
+			{CharCode: "USD", NumCode: "840", CbrID: "R01235", Name: "Доллар США", EngName: "US Dollar", Nominal: 1, ParentCode: "R01235"},
+		},
+	}
+	mockUsecase.On("GetCurrencyList", mock.Anything).Return(expectedResponse, nil)
+
+	w := performRequest(handler, handler.GetCurrencyList, "/")
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var response usecase.CurrencyListResponse
+	json.Unmarshal(w.Body.Bytes(), &response)
+	assert.Equal(t, expectedResponse, &response)
+
+	mockUsecase.AssertExpectations(t)
+}
+
+func TestGetCurrencyList_UpstreamError(t *testing.T) {
+	handler, mockUsecase, _, _ := setupTestHandler()
+
+	mockUsecase.On("GetCurrencyList", mock.Anything).Return(nil, fmt.Errorf("%w: timeout", usecase.ErrUpstreamUnavailable))
+
+	w := performRequest(handler, handler.GetCurrencyList, "/")
+
+	assert.Equal(t, http.StatusBadGateway, w.Code)
+	var response ErrorResponse
+	json.Unmarshal(w.Body.Bytes(), &response)
+	assert.Equal(t, CodeUpstreamUnavailable, response.Code)
+}
+
+func postBatch(handler *CurrencyHandler, target, body string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	_, r := gin.CreateTestContext(w)
+	r.Use(ErrorMiddleware(handler.logger))
+	r.POST("/currency/convert/batch", handler.ConvertBatch)
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, target, strings.NewReader(body)))
+	return w
+}
+
+func TestConvertBatch_PerItemResults(t *testing.T) {
+	handler, mockUsecase, _, _ := setupTestHandler()
+
+	date := time.Date(2025, 8, 1, 0, 0, 0, 0, time.UTC)
+	converted := &usecase.ConversionResponse{From: "USD", To: "EUR", Amount: decimal.NewFromInt(100), Rate: decimal.RequireFromString("0.8"), Result: decimal.NewFromInt(80), Date: "2025-08-01", Source: "cbr"}
+	mockUsecase.On("ConvertBatch", mock.Anything, "", mock.MatchedBy(func(items []usecase.ConversionItem) bool {
+		return len(items) == 2 && items[0].From == "USD" && items[0].Date.Equal(date) && items[0].Amount.Equal(decimal.NewFromInt(100)) &&
+			items[1].From == "XYZ" && items[1].Date.IsZero()
+	})).Return([]usecase.ConversionResult{
+		{Result: converted},
+		{Err: fmt.Errorf("%w: XYZ", usecase.ErrUnknownCurrency)},
+	}, nil)
+
+	body := `[
+		{"from":"USD","to":"EUR","amount":100,"date":"2025-08-01"},
+		{"from":"USD","to":"EUR","amount":1,"date":"01.08.2025"},
+		{"from":"XYZ","to":"EUR","amount":"5"}
+	]`
+	w := postBatch(handler, "/currency/convert/batch", body)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var response BatchConvertResponse
+	json.Unmarshal(w.Body.Bytes(), &response)
+	assert.Equal(t, 3, response.Count)
+	assert.Equal(t, 2, response.Failed)
+	if assert.Len(t, response.Results, 3) {
+		assert.Equal(t, 0, response.Results[0].Index)
+		assert.Nil(t, response.Results[0].Error)
+		assert.True(t, response.Results[0].Result.Result.Equal(decimal.NewFromInt(80)))
+
+		assert.Equal(t, 1, response.Results[1].Index)
+		assert.Nil(t, response.Results[1].Result)
+		assert.Equal(t, CodeInvalidDate, response.Results[1].Error.Code)
+
+		assert.Equal(t, 2, response.Results[2].Index)
+		assert.Equal(t, CodeUnknownCurrency, response.Results[2].Error.Code)
+	}
+
+	mockUsecase.AssertExpectations(t)
+}
+
+func TestConvertBatch_InternalErrorIsMaskedPerItem(t *testing.T) {
+	handler, mockUsecase, _, _ := setupTestHandler()
+
+	mockUsecase.On("ConvertBatch", mock.Anything, "ecb", mock.Anything).Return([]usecase.ConversionResult{
+		{Err: errors.New("connection refused")},
+	}, nil)
+
+	w := postBatch(handler, "/currency/convert/batch?source=ecb", `[{"from":"USD","to":"EUR","amount":1}]`)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.NotContains(t, w.Body.String(), "connection refused")
+	var response BatchConvertResponse
+	json.Unmarshal(w.Body.Bytes(), &response)
+	assert.Equal(t, 1, response.Failed)
+	assert.Equal(t, CodeInternal, response.Results[0].Error.Code)
+}
+
+func TestConvertBatch_InvalidBody(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+	}{
+		{"not an array", `{"from":"USD","to":"EUR"}`},
+		{"empty", `[]`},
+		{"too many items", "[" + strings.TrimSuffix(strings.Repeat(`{"from":"USD","to":"EUR","amount":1},`, maxBatchItems+1), ",") + "]"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler, mockUsecase, _, _ := setupTestHandler()
+
+			w := postBatch(handler, "/currency/convert/batch", tt.body)
+
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+			var response ErrorResponse
+			json.Unmarshal(w.Body.Bytes(), &response)
+			assert.Equal(t, CodeInvalidRequest, response.Code)
+			mockUsecase.AssertNotCalled(t, "ConvertBatch", mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestConvertBatch_UnknownSource(t *testing.T) {
+	handler, mockUsecase, _, _ := setupTestHandler()
+
+	mockUsecase.On("ConvertBatch", mock.Anything, "nbk", mock.Anything).Return(nil, fmt.Errorf("%w: nbk", usecase.ErrUnknownSource))
+
+	w := postBatch(handler, "/currency/convert/batch?source=nbk", `[{"from":"USD","to":"EUR","amount":1}]`)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	var response ErrorResponse
+	json.Unmarshal(w.Body.Bytes(), &response)
+	assert.Equal(t, CodeUnknownSource, response.Code)
+}
+
+func TestGetRateStats_Success(t *testing.T) {
+	handler, mockUsecase, _, _ := setupTestHandler()
+
+	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2025, 6, 30, 0, 0, 0, 0, time.UTC)
+	expected := &usecase.RateStatsResponse{
+		CharCode:  "USD",
+		Source:    "cbr",
+		Base:      "RUB",
+		Count:     115,
+		Mean:      decimal.RequireFromString("89.351235"),
+		Quarterly: []usecase.PeriodAverageResponse{{Period: "2025-Q1", Average: decimal.RequireFromString("95.1"), Count: 55}},
+	}
+	mockUsecase.On("GetRateStats", mock.Anything, "cbr", "USD", from, to).Return(expected, nil)
+
+	w := performRequest(handler, handler.GetRateStats, "/?val=USD&from=2025-01-01&to=2025-06-30&source=cbr")
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var response usecase.RateStatsResponse
+	json.Unmarshal(w.Body.Bytes(), &response)
+	assert.Equal(t, 115, response.Count)
+	assert.True(t, response.Mean.Equal(expected.Mean))
+	assert.Equal(t, "2025-Q1", response.Quarterly[0].Period)
+
+	mockUsecase.AssertExpectations(t)
+}
+
+func TestGetRateStats_MissingParams(t *testing.T) {
+	handler, mockUsecase, _, _ := setupTestHandler()
+
+	for _, target := range []string{"/?from=2025-01-01", "/?val=USD"} {
+		w := performRequest(handler, handler.GetRateStats, target)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		var response ErrorResponse
+		json.Unmarshal(w.Body.Bytes(), &response)
+		assert.Equal(t, CodeMissingParameter, response.Code)
+	}
+	mockUsecase.AssertNotCalled(t, "GetRateStats", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestGetRateStats_NotFound(t *testing.T) {
+	handler, mockUsecase, _, _ := setupTestHandler()
+
+	mockUsecase.On("GetRateStats", mock.Anything, "", "USD", mock.Anything, mock.Anything).Return(nil, fmt.Errorf("%w: no cbr rates", usecase.ErrRateNotFound))
+
+	w := performRequest(handler, handler.GetRateStats, "/?val=USD&from=2025-01-01")
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
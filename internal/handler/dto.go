@@ -1,6 +1,53 @@
 package handler
 
-type GetRateRequest struct {
-	CharCode string  `json:"char_code" binding:"required"`
-	Amount   float64 `json:"amount" binding:"required,gt=0"`
+import (
+	"RnD-service/internal/usecase"
+
+	"github.com/shopspring/decimal"
+)
+
+// ConvertItemRequest is one item of a batch conversion; Date defaults to today.
+// Items are validated one by one so that a bad item fails only itself.
+type ConvertItemRequest struct {
+	From   string          `json:"from"`
+	To     string          `json:"to"`
+	Amount decimal.Decimal `json:"amount"`
+	Date   string          `json:"date"`
+}
+
+type BatchConvertResponse struct {
+	Count   int                  `json:"count"`
+	Failed  int                  `json:"failed"`
+	Results []BatchConvertResult `json:"results"`
+}
+
+// BatchConvertResult carries either Result or Error for the item at Index.
+type BatchConvertResult struct {
+	Index  int                         `json:"index"`
+	Result *usecase.ConversionResponse `json:"result,omitempty"`
+	Error  *ErrorResponse              `json:"error,omitempty"`
+}
+
+type BackfillRequest struct {
+	From      string   `json:"from" binding:"required"`
+	To        string   `json:"to"`
+	CharCodes []string `json:"char_codes"`
+}
+
+// AlertRuleRequest is the body of rule create and replace requests; Source
+// defaults to cbr and Enabled to true.
+type AlertRuleRequest struct {
+	Source        string          `json:"source"`
+	CharCode      string          `json:"char_code" binding:"required"`
+	ThresholdType string          `json:"threshold_type" binding:"required"`
+	Threshold     decimal.Decimal `json:"threshold"`
+	Direction     string          `json:"direction" binding:"required"`
+	WebhookURL    string          `json:"webhook_url" binding:"required"`
+	Secret        string          `json:"secret"`
+	Enabled       *bool           `json:"enabled"`
+}
+
+type ErrorResponse struct {
+	Error string `json:"error"`
+	Code  string `json:"code"`
 }
@@ -0,0 +1,112 @@
+package handler
+
+import (
+	"RnD-service/internal/usecase"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+var (
+	ErrMissingParameter  = errors.New("missing required query parameter")
+	ErrInvalidDateFormat = errors.New("invalid date format, expected YYYY-MM-DD")
+	ErrInvalidRequest    = errors.New("invalid request")
+	ErrUnauthorized      = errors.New("missing or invalid admin token")
+)
+
+const (
+	CodeMissingParameter     = "missing_parameter"
+	CodeInvalidRequest       = "invalid_request"
+	CodeUnauthorized         = "unauthorized"
+	CodeInvalidDate          = "invalid_date"
+	CodeInvalidCharCode      = "invalid_char_code"
+	CodeUnknownCurrency      = "unknown_currency"
+	CodeUnknownMetal         = "unknown_metal"
+	CodeUnknownSource        = "unknown_source"
+	CodeInvalidAmount        = "invalid_amount"
+	CodeInvalidDateRange     = "invalid_date_range"
+	CodeFutureDate           = "future_date"
+	CodeNotFound             = "not_found"
+	CodeConflict             = "conflict"
+	CodeUpstreamUnavailable  = "upstream_unavailable"
+	CodeUpstreamDateMismatch = "upstream_date_mismatch"
+	CodeInternal             = "internal_error"
+)
+
+type errorMapping struct {
+	target error
+	status int
+	code   string
+}
+
+var errorMappings = []errorMapping{
+	{ErrMissingParameter, http.StatusBadRequest, CodeMissingParameter},
+	{ErrInvalidDateFormat, http.StatusBadRequest, CodeInvalidDate},
+	{ErrInvalidRequest, http.StatusBadRequest, CodeInvalidRequest},
+	{ErrUnauthorized, http.StatusUnauthorized, CodeUnauthorized},
+	{usecase.ErrInvalidCharCode, http.StatusBadRequest, CodeInvalidCharCode},
+	{usecase.ErrUnknownCurrency, http.StatusBadRequest, CodeUnknownCurrency},
+	{usecase.ErrUnknownMetal, http.StatusBadRequest, CodeUnknownMetal},
+	{usecase.ErrUnknownSource, http.StatusBadRequest, CodeUnknownSource},
+	{usecase.ErrInvalidAmount, http.StatusBadRequest, CodeInvalidAmount},
+	{usecase.ErrInvalidDateRange, http.StatusBadRequest, CodeInvalidDateRange},
+	{usecase.ErrFutureDate, http.StatusBadRequest, CodeFutureDate},
+	{usecase.ErrRateNotFound, http.StatusNotFound, CodeNotFound},
+	{usecase.ErrBackfillJobNotFound, http.StatusNotFound, CodeNotFound},
+	{usecase.ErrBackfillJobRunning, http.StatusConflict, CodeConflict},
+	{usecase.ErrInvalidAlertRule, http.StatusBadRequest, CodeInvalidRequest},
+	{usecase.ErrAlertRuleNotFound, http.StatusNotFound, CodeNotFound},
+	{usecase.ErrJobNotFound, http.StatusNotFound, CodeNotFound},
+	{usecase.ErrJobRunning, http.StatusConflict, CodeConflict},
+	{usecase.ErrUpstreamUnavailable, http.StatusBadGateway, CodeUpstreamUnavailable},
+	{usecase.ErrUpstreamDateMismatch, http.StatusBadGateway, CodeUpstreamDateMismatch},
+}
+
+// ErrorMiddleware renders the last error attached with c.Error as an ErrorResponse.
+func ErrorMiddleware(logger *logrus.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+
+		if len(c.Errors) == 0 || c.Writer.Written() {
+			return
+		}
+
+		err := c.Errors.Last().Err
+		status, response := errorResponse(err)
+
+		entry := logger.WithContext(c.Request.Context()).WithError(err).WithFields(logrus.Fields{
+			"method": c.Request.Method,
+			"path":   c.FullPath(),
+			"status": status,
+			"code":   response.Code,
+		})
+		if status >= http.StatusInternalServerError {
+			entry.Error("Request failed")
+		} else {
+			entry.Warn("Request rejected")
+		}
+
+		c.AbortWithStatusJSON(status, response)
+	}
+}
+
+// errorResponse renders err without leaking internal errors to the client.
+func errorResponse(err error) (int, ErrorResponse) {
+	status, code := mapError(err)
+	message := err.Error()
+	if code == CodeInternal {
+		message = "internal server error"
+	}
+	return status, ErrorResponse{Error: message, Code: code}
+}
+
+func mapError(err error) (int, string) {
+	for _, m := range errorMappings {
+		if errors.Is(err, m.target) {
+			return m.status, m.code
+		}
+	}
+	return http.StatusInternalServerError, CodeInternal
+}
@@ -0,0 +1,42 @@
+package handler
+
+import (
+	"RnD-service/internal/usecase"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+type HealthHandler struct {
+	usecase usecase.HealthUsecase
+	logger  *logrus.Logger
+}
+
+func NewHealthHandler(usecase usecase.HealthUsecase, logger *logrus.Logger) *HealthHandler {
+	return &HealthHandler{
+		usecase: usecase,
+		logger:  logger,
+	}
+}
+
+// Livez only tells the process serves requests; it must not depend on the
+// database, or an outage would get every instance restarted.
+func (h *HealthHandler) Livez(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"status": usecase.HealthOK})
+}
+
+func (h *HealthHandler) Readyz(c *gin.Context) {
+	result := h.usecase.GetReadiness(c.Request.Context())
+
+	status := http.StatusOK
+	if !result.Ready {
+		status = http.StatusServiceUnavailable
+	}
+	c.JSON(status, result)
+}
+
+// GetHealthDetails answers 200 whatever the status, the body carries it.
+func (h *HealthHandler) GetHealthDetails(c *gin.Context) {
+	c.JSON(http.StatusOK, h.usecase.GetHealthDetails(c.Request.Context()))
+}
@@ -0,0 +1,100 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"RnD-service/internal/usecase"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type mockHealthUsecase struct {
+	mock.Mock
+}
+
+func (m *mockHealthUsecase) GetReadiness(ctx context.Context) *usecase.ReadinessResponse {
+	args := m.Called(ctx)
+	return args.Get(0).(*usecase.ReadinessResponse)
+}
+
+func (m *mockHealthUsecase) GetHealthDetails(ctx context.Context) *usecase.HealthDetailsResponse {
+	args := m.Called(ctx)
+	return args.Get(0).(*usecase.HealthDetailsResponse)
+}
+
+func setupHealthHandler() (*HealthHandler, *mockHealthUsecase) {
+	mockUsecase := new(mockHealthUsecase)
+	logger, _ := test.NewNullLogger()
+	return NewHealthHandler(mockUsecase, logger), mockUsecase
+}
+
+func serveHealth(route string, handle gin.HandlerFunc) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	_, r := gin.CreateTestContext(w)
+	r.GET(route, handle)
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, route, nil))
+	return w
+}
+
+func TestLivez(t *testing.T) {
+	h, mockUsecase := setupHealthHandler()
+
+	w := serveHealth("/livez", h.Livez)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"status":"ok"}`, w.Body.String())
+	mockUsecase.AssertNotCalled(t, "GetReadiness", mock.Anything)
+}
+
+func TestReadyz(t *testing.T) {
+	h, mockUsecase := setupHealthHandler()
+
+	mockUsecase.On("GetReadiness", mock.Anything).Return(&usecase.ReadinessResponse{
+		Ready:  true,
+		Checks: map[string]string{"database": "ok", "migrations": "ok", "rates": "ok"},
+	})
+
+	w := serveHealth("/readyz", h.Readyz)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"ready":true,"checks":{"database":"ok","migrations":"ok","rates":"ok"}}`, w.Body.String())
+}
+
+func TestReadyz_NotReady(t *testing.T) {
+	h, mockUsecase := setupHealthHandler()
+
+	mockUsecase.On("GetReadiness", mock.Anything).Return(&usecase.ReadinessResponse{
+		Checks: map[string]string{"database": "ok", "migrations": "ok", "rates": "stale"},
+	})
+
+	w := serveHealth("/readyz", h.Readyz)
+
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+	var response usecase.ReadinessResponse
+	json.Unmarshal(w.Body.Bytes(), &response)
+	assert.Equal(t, "stale", response.Checks["rates"])
+}
+
+func TestGetHealthDetails_Down(t *testing.T) {
+	h, mockUsecase := setupHealthHandler()
+
+	mockUsecase.On("GetHealthDetails", mock.Anything).Return(&usecase.HealthDetailsResponse{
+		Status:   usecase.HealthDown,
+		Database: usecase.DatabaseHealthResponse{Status: usecase.HealthDown, Error: "connection refused"},
+	})
+
+	w := serveHealth("/health/details", h.GetHealthDetails)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var response usecase.HealthDetailsResponse
+	json.Unmarshal(w.Body.Bytes(), &response)
+	assert.Equal(t, usecase.HealthDown, response.Status)
+	assert.Equal(t, "connection refused", response.Database.Error)
+}
@@ -0,0 +1,83 @@
+package handler
+
+import (
+	"RnD-service/internal/usecase"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+type IndicatorHandler struct {
+	usecase usecase.IndicatorUsecase
+	logger  *logrus.Logger
+}
+
+func NewIndicatorHandler(usecase usecase.IndicatorUsecase, logger *logrus.Logger) *IndicatorHandler {
+	return &IndicatorHandler{
+		usecase: usecase,
+		logger:  logger,
+	}
+}
+
+func (h *IndicatorHandler) GetKeyRateHistory(c *gin.Context) {
+	from, to, err := parseDateRange(c, h.logger)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	result, err := h.usecase.GetKeyRateHistory(c.Request.Context(), from, to)
+	if err != nil {
+		c.Error(fmt.Errorf("get key rate history for from=%s, to=%s: %w", from.Format("2006-01-02"), to.Format("2006-01-02"), err))
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+func (h *IndicatorHandler) GetRuoniaHistory(c *gin.Context) {
+	from, to, err := parseDateRange(c, h.logger)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	result, err := h.usecase.GetRuoniaHistory(c.Request.Context(), from, to)
+	if err != nil {
+		c.Error(fmt.Errorf("get RUONIA history for from=%s, to=%s: %w", from.Format("2006-01-02"), to.Format("2006-01-02"), err))
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// parseDateRange reads the required 'from' and the optional 'to', which defaults to today.
+func parseDateRange(c *gin.Context, logger *logrus.Logger) (time.Time, time.Time, error) {
+	fromStr := c.Query("from")
+	toStr := c.Query("to")
+
+	if fromStr == "" {
+		return time.Time{}, time.Time{}, fmt.Errorf("%w 'from'", ErrMissingParameter)
+	}
+
+	from, err := parseDate(fromStr)
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("'from': %w", err)
+	}
+
+	if toStr == "" {
+		to := time.Now().Truncate(24 * time.Hour)
+		logger.Debugf("'to' parameter not provided, using default (today): %s", to.Format("2006-01-02"))
+		return from, to, nil
+	}
+
+	to, err := parseDate(toStr)
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("'to': %w", err)
+	}
+
+	return from, to, nil
+}
@@ -0,0 +1,155 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"RnD-service/internal/usecase"
+
+	"github.com/gin-gonic/gin"
+	"github.com/shopspring/decimal"
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type mockIndicatorUsecase struct {
+	mock.Mock
+}
+
+func (m *mockIndicatorUsecase) SyncIndicatorsFromCBR(ctx context.Context) error {
+	args := m.Called(ctx)
+	return args.Error(0)
+}
+
+func (m *mockIndicatorUsecase) GetKeyRateHistory(ctx context.Context, dateFrom, dateTo time.Time) (*usecase.KeyRateHistoryResponse, error) {
+	args := m.Called(ctx, dateFrom, dateTo)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*usecase.KeyRateHistoryResponse), args.Error(1)
+}
+
+func (m *mockIndicatorUsecase) GetRuoniaHistory(ctx context.Context, dateFrom, dateTo time.Time) (*usecase.RuoniaHistoryResponse, error) {
+	args := m.Called(ctx, dateFrom, dateTo)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*usecase.RuoniaHistoryResponse), args.Error(1)
+}
+
+func setupIndicatorHandler() (*IndicatorHandler, *mockIndicatorUsecase) {
+	mockUsecase := new(mockIndicatorUsecase)
+	logger, _ := test.NewNullLogger()
+	return NewIndicatorHandler(mockUsecase, logger), mockUsecase
+}
+
+func serveIndicator(h *IndicatorHandler, route, target string, handle gin.HandlerFunc) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	_, r := gin.CreateTestContext(w)
+	r.Use(ErrorMiddleware(h.logger))
+	r.GET(route, handle)
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, target, nil))
+	return w
+}
+
+func TestGetKeyRateHistory_Success(t *testing.T) {
+	h, mockUsecase := setupIndicatorHandler()
+
+	from := time.Date(2025, 7, 25, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2025, 7, 28, 0, 0, 0, 0, time.UTC)
+	expected := &usecase.KeyRateHistoryResponse{
+		From: "2025-07-25", To: "2025-07-28",
+		Rates: []usecase.KeyRatePoint{
+			{Date: "2025-07-25", Rate: decimal.RequireFromString("20")},
+			{Date: "2025-07-28", Rate: decimal.RequireFromString("18")},
+		},
+	}
+	mockUsecase.On("GetKeyRateHistory", mock.Anything, from, to).Return(expected, nil)
+
+	w := serveIndicator(h, "/indicators/keyrate", "/indicators/keyrate?from=2025-07-25&to=2025-07-28", h.GetKeyRateHistory)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var resp usecase.KeyRateHistoryResponse
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Len(t, resp.Rates, 2)
+	assert.Equal(t, "18", resp.Rates[1].Rate.String())
+	mockUsecase.AssertExpectations(t)
+}
+
+func TestGetKeyRateHistory_BadRequests(t *testing.T) {
+	tests := []struct {
+		name   string
+		target string
+		code   string
+	}{
+		{"missing from", "/indicators/keyrate?to=2025-07-28", CodeMissingParameter},
+		{"invalid from", "/indicators/keyrate?from=25.07.2025", CodeInvalidDate},
+		{"invalid to", "/indicators/keyrate?from=2025-07-25&to=yesterday", CodeInvalidDate},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, mockUsecase := setupIndicatorHandler()
+
+			w := serveIndicator(h, "/indicators/keyrate", tt.target, h.GetKeyRateHistory)
+
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+			var resp ErrorResponse
+			assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+			assert.Equal(t, tt.code, resp.Code)
+			mockUsecase.AssertNotCalled(t, "GetKeyRateHistory", mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestGetRuoniaHistory_DefaultsToToday(t *testing.T) {
+	h, mockUsecase := setupIndicatorHandler()
+
+	from := time.Date(2025, 7, 25, 0, 0, 0, 0, time.UTC)
+	today := time.Now().Truncate(24 * time.Hour)
+	expected := &usecase.RuoniaHistoryResponse{
+		From: "2025-07-25", To: today.Format("2006-01-02"),
+		Rates: []usecase.RuoniaPoint{
+			{Date: "2025-07-25", Rate: decimal.RequireFromString("19.8"), Volume: decimal.RequireFromString("512.34")},
+		},
+	}
+	mockUsecase.On("GetRuoniaHistory", mock.Anything, from, today).Return(expected, nil)
+
+	w := serveIndicator(h, "/indicators/ruonia", "/indicators/ruonia?from=2025-07-25", h.GetRuoniaHistory)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var resp usecase.RuoniaHistoryResponse
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, "512.34", resp.Rates[0].Volume.String())
+	mockUsecase.AssertExpectations(t)
+}
+
+func TestGetRuoniaHistory_Errors(t *testing.T) {
+	tests := []struct {
+		name   string
+		err    error
+		status int
+	}{
+		{"upstream", usecase.ErrUpstreamUnavailable, http.StatusBadGateway},
+		{"invalid range", usecase.ErrInvalidDateRange, http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, mockUsecase := setupIndicatorHandler()
+
+			from := time.Date(2025, 7, 25, 0, 0, 0, 0, time.UTC)
+			to := time.Date(2025, 7, 28, 0, 0, 0, 0, time.UTC)
+			mockUsecase.On("GetRuoniaHistory", mock.Anything, from, to).Return(nil, tt.err)
+
+			w := serveIndicator(h, "/indicators/ruonia", "/indicators/ruonia?from=2025-07-25&to=2025-07-28", h.GetRuoniaHistory)
+
+			assert.Equal(t, tt.status, w.Code)
+		})
+	}
+}
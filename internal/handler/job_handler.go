@@ -0,0 +1,45 @@
+package handler
+
+import (
+	"RnD-service/internal/usecase"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+type JobHandler struct {
+	usecase usecase.JobUsecase
+	logger  *logrus.Logger
+}
+
+func NewJobHandler(usecase usecase.JobUsecase, logger *logrus.Logger) *JobHandler {
+	return &JobHandler{
+		usecase: usecase,
+		logger:  logger,
+	}
+}
+
+func (h *JobHandler) ListJobs(c *gin.Context) {
+	result, err := h.usecase.ListJobs(c.Request.Context())
+	if err != nil {
+		c.Error(fmt.Errorf("list jobs: %w", err))
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// RunJob starts the job on the replica serving the request and answers
+// before it finishes; the run shows up in ListJobs.
+func (h *JobHandler) RunJob(c *gin.Context) {
+	name := c.Param("name")
+	result, err := h.usecase.RunJob(c.Request.Context(), name)
+	if err != nil {
+		c.Error(fmt.Errorf("run job %s: %w", name, err))
+		return
+	}
+
+	c.JSON(http.StatusAccepted, result)
+}
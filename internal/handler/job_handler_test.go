@@ -0,0 +1,110 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"RnD-service/internal/usecase"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type mockJobUsecase struct {
+	mock.Mock
+}
+
+func (m *mockJobUsecase) ListJobs(ctx context.Context) (*usecase.JobListResponse, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*usecase.JobListResponse), args.Error(1)
+}
+
+func (m *mockJobUsecase) RunJob(ctx context.Context, name string) (*usecase.JobRunResponse, error) {
+	args := m.Called(ctx, name)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*usecase.JobRunResponse), args.Error(1)
+}
+
+func serveJobs(h *JobHandler, method, route, target string, handle gin.HandlerFunc) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	_, r := gin.CreateTestContext(w)
+	r.Use(ErrorMiddleware(h.logger))
+	r.Handle(method, route, handle)
+	r.ServeHTTP(w, httptest.NewRequest(method, target, nil))
+	return w
+}
+
+func setupJobHandler() (*JobHandler, *mockJobUsecase) {
+	mockUsecase := new(mockJobUsecase)
+	logger, _ := test.NewNullLogger()
+	return NewJobHandler(mockUsecase, logger), mockUsecase
+}
+
+func TestListJobs(t *testing.T) {
+	h, mockUsecase := setupJobHandler()
+
+	mockUsecase.On("ListJobs", mock.Anything).Return(&usecase.JobListResponse{
+		Instance: "api-1",
+		Leader:   true,
+		Timezone: "Europe/Moscow",
+		Jobs:     []usecase.JobResponse{{Name: "cbr_rates", Schedule: "0 16 * * *"}},
+	}, nil)
+
+	w := serveJobs(h, http.MethodGet, "/admin/jobs", "/admin/jobs", h.ListJobs)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var response usecase.JobListResponse
+	json.Unmarshal(w.Body.Bytes(), &response)
+	assert.True(t, response.Leader)
+	assert.Equal(t, "cbr_rates", response.Jobs[0].Name)
+}
+
+func TestRunJob_Accepted(t *testing.T) {
+	h, mockUsecase := setupJobHandler()
+
+	mockUsecase.On("RunJob", mock.Anything, "cbr_rates").Return(&usecase.JobRunResponse{ID: 8, Job: "cbr_rates", Status: "running"}, nil)
+
+	w := serveJobs(h, http.MethodPost, "/admin/jobs/:name/run", "/admin/jobs/cbr_rates/run", h.RunJob)
+
+	assert.Equal(t, http.StatusAccepted, w.Code)
+	var response usecase.JobRunResponse
+	json.Unmarshal(w.Body.Bytes(), &response)
+	assert.Equal(t, int64(8), response.ID)
+}
+
+func TestRunJob_Errors(t *testing.T) {
+	tests := []struct {
+		name   string
+		err    error
+		status int
+		code   string
+	}{
+		{"unknown job", fmt.Errorf("%w: nightly", usecase.ErrJobNotFound), http.StatusNotFound, CodeNotFound},
+		{"already running", fmt.Errorf("%w: nightly", usecase.ErrJobRunning), http.StatusConflict, CodeConflict},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, mockUsecase := setupJobHandler()
+			mockUsecase.On("RunJob", mock.Anything, "nightly").Return(nil, tt.err)
+
+			w := serveJobs(h, http.MethodPost, "/admin/jobs/:name/run", "/admin/jobs/nightly/run", h.RunJob)
+
+			assert.Equal(t, tt.status, w.Code)
+			var response ErrorResponse
+			json.Unmarshal(w.Body.Bytes(), &response)
+			assert.Equal(t, tt.code, response.Code)
+		})
+	}
+}
@@ -0,0 +1,95 @@
+package handler
+
+import (
+	"RnD-service/internal/usecase"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+type MetalHandler struct {
+	usecase usecase.MetalUsecase
+	logger  *logrus.Logger
+}
+
+func NewMetalHandler(usecase usecase.MetalUsecase, logger *logrus.Logger) *MetalHandler {
+	return &MetalHandler{
+		usecase: usecase,
+		logger:  logger,
+	}
+}
+
+func (h *MetalHandler) GetMetalPrice(c *gin.Context) {
+	code := c.Query("code")
+	dateStr := c.Query("date")
+
+	if code == "" {
+		c.Error(fmt.Errorf("%w 'code'", ErrMissingParameter))
+		return
+	}
+
+	var date time.Time
+	if dateStr == "" {
+		date = time.Now().Truncate(24 * time.Hour)
+		h.logger.Debugf("Date parameter not provided, using default (today): %s", date.Format("2006-01-02"))
+	} else {
+		parsedDate, err := parseDate(dateStr)
+		if err != nil {
+			c.Error(err)
+			return
+		}
+		date = parsedDate
+	}
+
+	result, err := h.usecase.GetMetalPrice(c.Request.Context(), code, date)
+	if err != nil {
+		c.Error(fmt.Errorf("get metal price for code=%s, date=%s: %w", code, date.Format("2006-01-02"), err))
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+func (h *MetalHandler) GetMetalPriceHistory(c *gin.Context) {
+	code := c.Query("code")
+	fromStr := c.Query("from")
+	toStr := c.Query("to")
+
+	if code == "" {
+		c.Error(fmt.Errorf("%w 'code'", ErrMissingParameter))
+		return
+	}
+	if fromStr == "" {
+		c.Error(fmt.Errorf("%w 'from'", ErrMissingParameter))
+		return
+	}
+
+	from, err := parseDate(fromStr)
+	if err != nil {
+		c.Error(fmt.Errorf("'from': %w", err))
+		return
+	}
+
+	var to time.Time
+	if toStr == "" {
+		to = time.Now().Truncate(24 * time.Hour)
+		h.logger.Debugf("'to' parameter not provided, using default (today): %s", to.Format("2006-01-02"))
+	} else {
+		to, err = parseDate(toStr)
+		if err != nil {
+			c.Error(fmt.Errorf("'to': %w", err))
+			return
+		}
+	}
+
+	result, err := h.usecase.GetMetalPriceHistory(c.Request.Context(), code, from, to)
+	if err != nil {
+		c.Error(fmt.Errorf("get metal price history for code=%s, from=%s, to=%s: %w", code, fromStr, toStr, err))
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
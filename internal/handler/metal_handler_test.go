@@ -0,0 +1,161 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"RnD-service/internal/usecase"
+
+	"github.com/gin-gonic/gin"
+	"github.com/shopspring/decimal"
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type mockMetalUsecase struct {
+	mock.Mock
+}
+
+func (m *mockMetalUsecase) FetchAndStoreMetalPricesFromCBR(ctx context.Context) error {
+	args := m.Called(ctx)
+	return args.Error(0)
+}
+
+func (m *mockMetalUsecase) GetMetalPrice(ctx context.Context, code string, date time.Time) (*usecase.MetalPriceResponse, error) {
+	args := m.Called(ctx, code, date)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*usecase.MetalPriceResponse), args.Error(1)
+}
+
+func (m *mockMetalUsecase) GetMetalPriceHistory(ctx context.Context, code string, dateFrom, dateTo time.Time) (*usecase.MetalPriceHistoryResponse, error) {
+	args := m.Called(ctx, code, dateFrom, dateTo)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*usecase.MetalPriceHistoryResponse), args.Error(1)
+}
+
+func setupMetalHandler() (*MetalHandler, *mockMetalUsecase) {
+	mockUsecase := new(mockMetalUsecase)
+	logger, _ := test.NewNullLogger()
+	return NewMetalHandler(mockUsecase, logger), mockUsecase
+}
+
+func serveMetal(h *MetalHandler, route, target string, handle gin.HandlerFunc) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	_, r := gin.CreateTestContext(w)
+	r.Use(ErrorMiddleware(h.logger))
+	r.GET(route, handle)
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, target, nil))
+	return w
+}
+
+func TestGetMetalPrice_Success(t *testing.T) {
+	h, mockUsecase := setupMetalHandler()
+
+	date := time.Date(2025, 7, 31, 0, 0, 0, 0, time.UTC)
+	expected := &usecase.MetalPriceResponse{Code: "AU", Name: "Золото", Date: "2025-07-31", Buy: decimal.RequireFromString("8563.47"), Sell: decimal.RequireFromString("8563.47")}
+	mockUsecase.On("GetMetalPrice", mock.Anything, "AU", date).Return(expected, nil)
+
+	w := serveMetal(h, "/metals/price", "/metals/price?code=AU&date=2025-07-31", h.GetMetalPrice)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var resp usecase.MetalPriceResponse
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, "Золото", resp.Name)
+	assert.Equal(t, "8563.47", resp.Sell.String())
+	mockUsecase.AssertExpectations(t)
+}
+
+func TestGetMetalPrice_BadRequests(t *testing.T) {
+	tests := []struct {
+		name   string
+		target string
+		code   string
+	}{
+		{"missing code", "/metals/price?date=2025-07-31", CodeMissingParameter},
+		{"invalid date", "/metals/price?code=AU&date=31.07.2025", CodeInvalidDate},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, mockUsecase := setupMetalHandler()
+
+			w := serveMetal(h, "/metals/price", tt.target, h.GetMetalPrice)
+
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+			var resp ErrorResponse
+			assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+			assert.Equal(t, tt.code, resp.Code)
+			mockUsecase.AssertNotCalled(t, "GetMetalPrice", mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestGetMetalPrice_UnknownMetal(t *testing.T) {
+	h, mockUsecase := setupMetalHandler()
+
+	date := time.Date(2025, 7, 31, 0, 0, 0, 0, time.UTC)
+	mockUsecase.On("GetMetalPrice", mock.Anything, "CU", date).Return(nil, usecase.ErrUnknownMetal)
+
+	w := serveMetal(h, "/metals/price", "/metals/price?code=CU&date=2025-07-31", h.GetMetalPrice)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	var resp ErrorResponse
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, CodeUnknownMetal, resp.Code)
+}
+
+func TestGetMetalPriceHistory_Success(t *testing.T) {
+	h, mockUsecase := setupMetalHandler()
+
+	from := time.Date(2025, 7, 30, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2025, 7, 31, 0, 0, 0, 0, time.UTC)
+	expected := &usecase.MetalPriceHistoryResponse{
+		Code: "AG", Name: "Серебро", From: "2025-07-30", To: "2025-07-31",
+		Prices: []usecase.MetalPricePoint{
+			{Date: "2025-07-30", Buy: decimal.RequireFromString("95.8"), Sell: decimal.RequireFromString("95.8")},
+			{Date: "2025-07-31", Buy: decimal.RequireFromString("96.12"), Sell: decimal.RequireFromString("96.12")},
+		},
+	}
+	mockUsecase.On("GetMetalPriceHistory", mock.Anything, "AG", from, to).Return(expected, nil)
+
+	w := serveMetal(h, "/metals/price/history", "/metals/price/history?code=AG&from=2025-07-30&to=2025-07-31", h.GetMetalPriceHistory)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var resp usecase.MetalPriceHistoryResponse
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Len(t, resp.Prices, 2)
+	mockUsecase.AssertExpectations(t)
+}
+
+func TestGetMetalPriceHistory_MissingFrom(t *testing.T) {
+	h, mockUsecase := setupMetalHandler()
+
+	w := serveMetal(h, "/metals/price/history", "/metals/price/history?code=AG", h.GetMetalPriceHistory)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	var resp ErrorResponse
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, CodeMissingParameter, resp.Code)
+	mockUsecase.AssertNotCalled(t, "GetMetalPriceHistory", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestGetMetalPriceHistory_Upstream(t *testing.T) {
+	h, mockUsecase := setupMetalHandler()
+
+	from := time.Date(2025, 7, 30, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2025, 7, 31, 0, 0, 0, 0, time.UTC)
+	mockUsecase.On("GetMetalPriceHistory", mock.Anything, "AG", from, to).Return(nil, usecase.ErrUpstreamUnavailable)
+
+	w := serveMetal(h, "/metals/price/history", "/metals/price/history?code=AG&from=2025-07-30&to=2025-07-31", h.GetMetalPriceHistory)
+
+	assert.Equal(t, http.StatusBadGateway, w.Code)
+}
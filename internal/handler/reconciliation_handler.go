@@ -0,0 +1,60 @@
+package handler
+
+import (
+	"RnD-service/internal/usecase"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+type ReconciliationHandler struct {
+	usecase usecase.ReconciliationUsecase
+	logger  *logrus.Logger
+}
+
+func NewReconciliationHandler(usecase usecase.ReconciliationUsecase, logger *logrus.Logger) *ReconciliationHandler {
+	return &ReconciliationHandler{
+		usecase: usecase,
+		logger:  logger,
+	}
+}
+
+// Reconcile compares the configured sources for the optional 'date', today by default.
+func (h *ReconciliationHandler) Reconcile(c *gin.Context) {
+	var date time.Time
+	if dateStr := c.Query("date"); dateStr != "" {
+		parsedDate, err := parseDate(dateStr)
+		if err != nil {
+			c.Error(err)
+			return
+		}
+		date = parsedDate
+	}
+
+	result, err := h.usecase.Reconcile(c.Request.Context(), date)
+	if err != nil {
+		c.Error(fmt.Errorf("reconcile rates for date=%s: %w", c.Query("date"), err))
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+func (h *ReconciliationHandler) GetDiscrepancies(c *gin.Context) {
+	from, to, err := parseDateRange(c, h.logger)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	result, err := h.usecase.GetDiscrepancies(c.Request.Context(), from, to)
+	if err != nil {
+		c.Error(fmt.Errorf("get rate discrepancies for from=%s, to=%s: %w", from.Format("2006-01-02"), to.Format("2006-01-02"), err))
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
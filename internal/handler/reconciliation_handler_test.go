@@ -0,0 +1,110 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"RnD-service/internal/usecase"
+
+	"github.com/gin-gonic/gin"
+	"github.com/shopspring/decimal"
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type mockReconciliationUsecase struct {
+	mock.Mock
+}
+
+func (m *mockReconciliationUsecase) Reconcile(ctx context.Context, date time.Time) (*usecase.ReconciliationResponse, error) {
+	args := m.Called(ctx, date)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*usecase.ReconciliationResponse), args.Error(1)
+}
+
+func (m *mockReconciliationUsecase) GetDiscrepancies(ctx context.Context, dateFrom, dateTo time.Time) (*usecase.DiscrepancyListResponse, error) {
+	args := m.Called(ctx, dateFrom, dateTo)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*usecase.DiscrepancyListResponse), args.Error(1)
+}
+
+func setupReconciliationHandler() (*ReconciliationHandler, *mockReconciliationUsecase) {
+	mockUsecase := new(mockReconciliationUsecase)
+	logger, _ := test.NewNullLogger()
+	return NewReconciliationHandler(mockUsecase, logger), mockUsecase
+}
+
+func serveReconciliation(h *ReconciliationHandler, method, route, target string, handle gin.HandlerFunc) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	_, r := gin.CreateTestContext(w)
+	r.Use(ErrorMiddleware(h.logger))
+	r.Handle(method, route, handle)
+	r.ServeHTTP(w, httptest.NewRequest(method, target, nil))
+	return w
+}
+
+func TestReconcile_WithDate(t *testing.T) {
+	h, mockUsecase := setupReconciliationHandler()
+
+	date := time.Date(2025, 8, 1, 0, 0, 0, 0, time.UTC)
+	expected := &usecase.ReconciliationResponse{
+		Date: "2025-08-01",
+		Discrepancies: []usecase.DiscrepancyItem{
+			{Date: "2025-08-01", CharCode: "USD", PrimarySource: "cbr", SecondarySource: "ecb", Base: "EUR", DiffPercent: decimal.RequireFromString("0.8364")},
+		},
+	}
+	mockUsecase.On("Reconcile", mock.Anything, date).Return(expected, nil)
+
+	w := serveReconciliation(h, http.MethodPost, "/admin/reconcile", "/admin/reconcile?date=2025-08-01", h.Reconcile)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var response usecase.ReconciliationResponse
+	json.Unmarshal(w.Body.Bytes(), &response)
+	assert.Equal(t, "USD", response.Discrepancies[0].CharCode)
+	mockUsecase.AssertExpectations(t)
+}
+
+func TestReconcile_UpstreamUnavailable(t *testing.T) {
+	h, mockUsecase := setupReconciliationHandler()
+
+	mockUsecase.On("Reconcile", mock.Anything, time.Time{}).Return(nil, usecase.ErrUpstreamUnavailable)
+
+	w := serveReconciliation(h, http.MethodPost, "/admin/reconcile", "/admin/reconcile", h.Reconcile)
+
+	assert.Equal(t, http.StatusBadGateway, w.Code)
+}
+
+func TestGetDiscrepancies_MissingFrom(t *testing.T) {
+	h, mockUsecase := setupReconciliationHandler()
+
+	w := serveReconciliation(h, http.MethodGet, "/admin/discrepancies", "/admin/discrepancies", h.GetDiscrepancies)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	var response ErrorResponse
+	json.Unmarshal(w.Body.Bytes(), &response)
+	assert.Equal(t, CodeMissingParameter, response.Code)
+	mockUsecase.AssertNotCalled(t, "GetDiscrepancies", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestGetDiscrepancies_Success(t *testing.T) {
+	h, mockUsecase := setupReconciliationHandler()
+
+	from := time.Date(2025, 8, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2025, 8, 4, 0, 0, 0, 0, time.UTC)
+	mockUsecase.On("GetDiscrepancies", mock.Anything, from, to).Return(&usecase.DiscrepancyListResponse{From: "2025-08-01", To: "2025-08-04", Discrepancies: []usecase.DiscrepancyItem{}}, nil)
+
+	w := serveReconciliation(h, http.MethodGet, "/admin/discrepancies", "/admin/discrepancies?from=2025-08-01&to=2025-08-04", h.GetDiscrepancies)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"from":"2025-08-01","to":"2025-08-04","discrepancies":[]}`, w.Body.String())
+	mockUsecase.AssertExpectations(t)
+}
@@ -0,0 +1,110 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"RnD-service/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func serveWithRequestID(requestID string) (*httptest.ResponseRecorder, string, *test.Hook) {
+	log, hook := test.NewNullLogger()
+	log.AddHook(logger.ContextHook{})
+
+	var seen string
+	w := httptest.NewRecorder()
+	_, r := gin.CreateTestContext(w)
+	r.Use(RequestIDMiddleware(), AccessLogMiddleware(log))
+	r.GET("/currency/rate", func(c *gin.Context) {
+		seen = logger.RequestID(c.Request.Context())
+		c.Status(http.StatusNoContent)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/currency/rate?char_code=USD", nil)
+	if requestID != "" {
+		req.Header.Set(RequestIDHeader, requestID)
+	}
+	r.ServeHTTP(w, req)
+	return w, seen, hook
+}
+
+func TestRequestIDMiddleware_KeepsIncomingID(t *testing.T) {
+	w, seen, hook := serveWithRequestID("req-42")
+
+	assert.Equal(t, "req-42", w.Header().Get(RequestIDHeader))
+	assert.Equal(t, "req-42", seen)
+
+	entry := hook.LastEntry()
+	require.NotNil(t, entry)
+	assert.Equal(t, "req-42", entry.Data["request_id"])
+	assert.Equal(t, "/currency/rate", entry.Data["route"])
+	assert.Equal(t, http.StatusNoContent, entry.Data["status"])
+}
+
+func TestRequestIDMiddleware_GeneratesID(t *testing.T) {
+	for name, incoming := range map[string]string{
+		"missing":   "",
+		"too long":  strings.Repeat("a", maxRequestIDLength+1),
+		"non ascii": "запрос",
+		"spaces":    "req 42",
+	} {
+		t.Run(name, func(t *testing.T) {
+			w, seen, hook := serveWithRequestID(incoming)
+
+			id := w.Header().Get(RequestIDHeader)
+			assert.Len(t, id, 36)
+			assert.Equal(t, id, seen)
+			assert.Equal(t, id, hook.LastEntry().Data["request_id"])
+		})
+	}
+}
+
+func serveAdmin(token, authorization string) *httptest.ResponseRecorder {
+	log, _ := test.NewNullLogger()
+
+	w := httptest.NewRecorder()
+	_, r := gin.CreateTestContext(w)
+	r.Use(ErrorMiddleware(log))
+	admin := r.Group("/admin", AdminAuthMiddleware(token))
+	admin.GET("/jobs", func(c *gin.Context) {
+		c.Status(http.StatusNoContent)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/admin/jobs", nil)
+	if authorization != "" {
+		req.Header.Set("Authorization", authorization)
+	}
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func TestAdminAuthMiddleware_ValidToken(t *testing.T) {
+	w := serveAdmin("s3cret", "Bearer s3cret")
+	assert.Equal(t, http.StatusNoContent, w.Code)
+}
+
+func TestAdminAuthMiddleware_Rejects(t *testing.T) {
+	for name, tt := range map[string]struct {
+		token         string
+		authorization string
+	}{
+		"missing header":     {"s3cret", ""},
+		"wrong token":        {"s3cret", "Bearer guess"},
+		"not bearer":         {"s3cret", "Basic czNjcmV0"},
+		"token not set":      {"", "Bearer "},
+		"token not set, any": {"", "Bearer s3cret"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			w := serveAdmin(tt.token, tt.authorization)
+			assert.Equal(t, http.StatusUnauthorized, w.Code)
+			assert.Contains(t, w.Body.String(), CodeUnauthorized)
+		})
+	}
+}
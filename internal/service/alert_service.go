@@ -0,0 +1,444 @@
+package service
+
+import (
+	"RnD-service/internal/adapter/postgres"
+	"RnD-service/internal/adapter/provider"
+	"RnD-service/internal/adapter/webhook"
+	"RnD-service/internal/entity"
+	"RnD-service/pkg/logger"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/sirupsen/logrus"
+	"go.uber.org/multierr"
+)
+
+// alertScale is the precision of per-unit values compared by alert rules.
+const alertScale = 8
+
+const deadLetterListLimit = 100
+
+const alertEvent = "rate.alert"
+
+// maxPublicationGap bounds the search for the previous publication day.
+const maxPublicationGap = 31
+
+type AlertService struct {
+	repo     postgres.AlertRepository
+	rates    postgres.PostgresRepository
+	notifier webhook.Notifier
+	logger   *logrus.Logger
+	now      func() time.Time
+
+	calendars map[string]provider.Calendar
+	redact    *logger.RedactHook
+
+	// deliveries outlive the store that triggered them and stop on Shutdown
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+func NewAlertService(repo postgres.AlertRepository, rates postgres.PostgresRepository, notifier webhook.Notifier, logger *logrus.Logger) *AlertService {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &AlertService{
+		repo:      repo,
+		rates:     rates,
+		notifier:  notifier,
+		logger:    logger,
+		now:       time.Now,
+		calendars: make(map[string]provider.Calendar),
+		ctx:       ctx,
+		cancel:    cancel,
+	}
+}
+
+// SetCalendar tells which publication a rate of source is compared with.
+// Without a calendar every day is taken as a publication day.
+func (s *AlertService) SetCalendar(source string, calendar provider.Calendar) {
+	s.calendars[source] = calendar
+}
+
+// SetRedactHook registers webhook secrets with hook as rules are saved and
+// delivered, so they are masked if they ever reach a log entry.
+func (s *AlertService) SetRedactHook(hook *logger.RedactHook) {
+	s.redact = hook
+}
+
+func (s *AlertService) registerSecret(secret string) {
+	if s.redact != nil {
+		s.redact.Add(secret)
+	}
+}
+
+// CreateRule generates a webhook secret when the rule has none.
+func (s *AlertService) CreateRule(ctx context.Context, rule *entity.AlertRule) (*entity.AlertRule, error) {
+	if err := validateAlertRule(rule); err != nil {
+		s.logger.WithError(err).Warn("Rejected alert rule")
+		return nil, err
+	}
+	if rule.Secret == "" {
+		secret, err := newWebhookSecret()
+		if err != nil {
+			return nil, fmt.Errorf("generate webhook secret: %w", err)
+		}
+		rule.Secret = secret
+	}
+	s.registerSecret(rule.Secret)
+
+	now := s.now()
+	rule.CreatedAt = now
+	rule.UpdatedAt = now
+
+	id, err := s.repo.CreateAlertRule(ctx, rule)
+	if err != nil {
+		s.logger.Errorf("Failed to create alert rule: %v", err)
+		return nil, fmt.Errorf("create alert rule: %w", err)
+	}
+	rule.ID = id
+
+	s.logger.Infof("Created alert rule %d: %s/%s %s %s %s", rule.ID, rule.Source, rule.CharCode, rule.Direction, rule.Threshold, rule.ThresholdType)
+	return rule, nil
+}
+
+// UpdateRule replaces the rule; an empty secret keeps the stored one.
+func (s *AlertService) UpdateRule(ctx context.Context, rule *entity.AlertRule) (*entity.AlertRule, error) {
+	if err := validateAlertRule(rule); err != nil {
+		s.logger.WithError(err).Warn("Rejected alert rule")
+		return nil, err
+	}
+
+	stored, err := s.GetRule(ctx, rule.ID)
+	if err != nil {
+		return nil, err
+	}
+	if rule.Secret == "" {
+		rule.Secret = stored.Secret
+	}
+	s.registerSecret(rule.Secret)
+	rule.CreatedAt = stored.CreatedAt
+	rule.UpdatedAt = s.now()
+
+	if err := s.repo.UpdateAlertRule(ctx, rule); err != nil {
+		if errors.Is(err, postgres.ErrNotFound) {
+			return nil, fmt.Errorf("%w: %d", ErrAlertRuleNotFound, rule.ID)
+		}
+		s.logger.Errorf("Failed to update alert rule %d: %v", rule.ID, err)
+		return nil, fmt.Errorf("update alert rule: %w", err)
+	}
+
+	s.logger.Infof("Updated alert rule %d", rule.ID)
+	return rule, nil
+}
+
+func (s *AlertService) DeleteRule(ctx context.Context, id int64) error {
+	if err := s.repo.DeleteAlertRule(ctx, id); err != nil {
+		if errors.Is(err, postgres.ErrNotFound) {
+			return fmt.Errorf("%w: %d", ErrAlertRuleNotFound, id)
+		}
+		s.logger.Errorf("Failed to delete alert rule %d: %v", id, err)
+		return fmt.Errorf("delete alert rule: %w", err)
+	}
+	s.logger.Infof("Deleted alert rule %d", id)
+	return nil
+}
+
+func (s *AlertService) GetRule(ctx context.Context, id int64) (*entity.AlertRule, error) {
+	rule, err := s.repo.GetAlertRule(ctx, id)
+	if err != nil {
+		if errors.Is(err, postgres.ErrNotFound) {
+			return nil, fmt.Errorf("%w: %d", ErrAlertRuleNotFound, id)
+		}
+		s.logger.Errorf("Failed to get alert rule %d: %v", id, err)
+		return nil, fmt.Errorf("get alert rule: %w", err)
+	}
+	return rule, nil
+}
+
+func (s *AlertService) ListRules(ctx context.Context) ([]entity.AlertRule, error) {
+	rules, err := s.repo.ListAlertRules(ctx)
+	if err != nil {
+		s.logger.Errorf("Failed to list alert rules: %v", err)
+		return nil, fmt.Errorf("list alert rules: %w", err)
+	}
+	return rules, nil
+}
+
+func (s *AlertService) ListAlerts(ctx context.Context, dateFrom, dateTo time.Time) ([]entity.Alert, error) {
+	alerts, err := s.repo.ListAlerts(ctx, dateFrom.Format("2006-01-02"), dateTo.Format("2006-01-02"))
+	if err != nil {
+		s.logger.WithError(err).Error("Failed to list alerts")
+		return nil, fmt.Errorf("list alerts: %w", err)
+	}
+	return alerts, nil
+}
+
+func (s *AlertService) ListDeadLetters(ctx context.Context) ([]entity.WebhookDeadLetter, error) {
+	letters, err := s.repo.ListDeadLetters(ctx, deadLetterListLimit)
+	if err != nil {
+		s.logger.WithError(err).Error("Failed to list webhook dead letters")
+		return nil, fmt.Errorf("list webhook dead letters: %w", err)
+	}
+	return letters, nil
+}
+
+// Evaluate compares just stored rates with the publication before each of
+// them and records an alert for every enabled rule they trigger. Webhooks are
+// delivered in the background; a rule fires once per date, so storing the
+// same publication again does not notify twice.
+func (s *AlertService) Evaluate(ctx context.Context, rates []entity.Currency) error {
+	bySource := make(map[string][]entity.Currency)
+	for _, rate := range rates {
+		bySource[rate.Source] = append(bySource[rate.Source], rate)
+	}
+
+	var errs error
+	for source, sourceRates := range bySource {
+		rules, err := s.repo.ListEnabledAlertRules(ctx, source)
+		if err != nil {
+			s.logger.WithError(err).Errorf("Failed to list alert rules for %s", source)
+			errs = multierr.Append(errs, fmt.Errorf("list alert rules for %s: %w", source, err))
+			continue
+		}
+		for _, rule := range rules {
+			for _, rate := range sourceRates {
+				if rate.CharCode != rule.CharCode {
+					continue
+				}
+				if err := s.evaluate(ctx, rule, rate); err != nil {
+					s.logger.WithError(err).Errorf("Failed to evaluate alert rule %d for %s on %s", rule.ID, rate.CharCode, rate.Date.Format("2006-01-02"))
+					errs = multierr.Append(errs, err)
+				}
+			}
+		}
+	}
+	return errs
+}
+
+// Shutdown interrupts pending deliveries, which end up in the dead letters.
+func (s *AlertService) Shutdown() {
+	s.cancel()
+	s.wait()
+}
+
+// wait blocks until deliveries started so far are finished.
+func (s *AlertService) wait() {
+	s.wg.Wait()
+}
+
+func (s *AlertService) evaluate(ctx context.Context, rule entity.AlertRule, rate entity.Currency) error {
+	previousDate := s.previousPublication(rate.Source, rate.Date)
+	previous, err := s.rates.GetRateByCharCodeAndDate(ctx, rate.Source, rate.CharCode, previousDate.Format("2006-01-02"))
+	if err != nil {
+		if errors.Is(err, postgres.ErrNotFound) {
+			s.logger.Debugf("No publication before %s for %s/%s, nothing to compare", rate.Date.Format("2006-01-02"), rate.Source, rate.CharCode)
+			return nil
+		}
+		return fmt.Errorf("get previous rate: %w", err)
+	}
+	// an older row means the previous publication is not stored: comparing
+	// with it would report the move of several publications as one
+	if previous.Date.Format("2006-01-02") != previousDate.Format("2006-01-02") {
+		s.logger.Debugf("Publication of %s for %s/%s is not stored, latest before is %s, skipping rule %d",
+			previousDate.Format("2006-01-02"), rate.Source, rate.CharCode, previous.Date.Format("2006-01-02"), rule.ID)
+		return nil
+	}
+	if previous.Nominal <= 0 || rate.Nominal <= 0 {
+		return nil
+	}
+
+	previousValue := previous.Value.DivRound(decimal.NewFromInt(int64(previous.Nominal)), alertScale)
+	value := rate.Value.DivRound(decimal.NewFromInt(int64(rate.Nominal)), alertScale)
+	if previousValue.IsZero() {
+		return nil
+	}
+	change := value.Sub(previousValue)
+	changePercent := change.Div(previousValue).Mul(decimal.NewFromInt(100)).Round(4)
+
+	if !triggers(rule, change, changePercent) {
+		return nil
+	}
+
+	alert := &entity.Alert{
+		RuleID:        rule.ID,
+		Source:        rate.Source,
+		CharCode:      rate.CharCode,
+		Date:          rate.Date,
+		PreviousDate:  previous.Date,
+		PreviousValue: previousValue,
+		Value:         value,
+		Change:        change,
+		ChangePercent: changePercent,
+		Status:        entity.AlertPending,
+		TriggeredAt:   s.now(),
+	}
+	id, created, err := s.repo.CreateAlert(ctx, alert)
+	if err != nil {
+		return fmt.Errorf("record alert: %w", err)
+	}
+	if !created {
+		return nil
+	}
+	alert.ID = id
+
+	s.logger.WithFields(logrus.Fields{
+		"rule_id":   rule.ID,
+		"char_code": rate.CharCode,
+		"date":      rate.Date.Format("2006-01-02"),
+		"previous":  previousValue,
+		"value":     value,
+	}).Warnf("Rate moved by %s (%s%%), alert %d triggered", change, changePercent, alert.ID)
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		s.deliver(rule, alert)
+	}()
+	return nil
+}
+
+// previousPublication is the last publication day of source before date.
+func (s *AlertService) previousPublication(source string, date time.Time) time.Time {
+	calendar, ok := s.calendars[source]
+	if !ok {
+		return date.AddDate(0, 0, -1)
+	}
+	for i := 1; i <= maxPublicationGap; i++ {
+		if d := date.AddDate(0, 0, -i); calendar.IsPublicationDay(d) {
+			return d
+		}
+	}
+	return date.AddDate(0, 0, -1)
+}
+
+func triggers(rule entity.AlertRule, change, changePercent decimal.Decimal) bool {
+	magnitude := change.Abs()
+	if rule.ThresholdType == entity.AlertThresholdPercent {
+		magnitude = changePercent.Abs()
+	}
+	if change.IsZero() || magnitude.LessThan(rule.Threshold) {
+		return false
+	}
+	switch rule.Direction {
+	case entity.AlertDirectionUp:
+		return change.IsPositive()
+	case entity.AlertDirectionDown:
+		return change.IsNegative()
+	default:
+		return true
+	}
+}
+
+type alertPayload struct {
+	ID            int64           `json:"id"`
+	Event         string          `json:"event"`
+	RuleID        int64           `json:"rule_id"`
+	Source        string          `json:"source"`
+	CharCode      string          `json:"char_code"`
+	Date          string          `json:"date"`
+	PreviousDate  string          `json:"previous_date"`
+	PreviousValue decimal.Decimal `json:"previous_value"`
+	Value         decimal.Decimal `json:"value"`
+	Change        decimal.Decimal `json:"change"`
+	ChangePercent decimal.Decimal `json:"change_percent"`
+	ThresholdType string          `json:"threshold_type"`
+	Threshold     decimal.Decimal `json:"threshold"`
+	Direction     string          `json:"direction"`
+	TriggeredAt   time.Time       `json:"triggered_at"`
+}
+
+// deliver records the outcome with a fresh context: a delivery interrupted by
+// Shutdown is still saved to the dead letters.
+func (s *AlertService) deliver(rule entity.AlertRule, alert *entity.Alert) {
+	payload, err := json.Marshal(alertPayload{
+		ID:            alert.ID,
+		Event:         alertEvent,
+		RuleID:        rule.ID,
+		Source:        alert.Source,
+		CharCode:      alert.CharCode,
+		Date:          alert.Date.Format("2006-01-02"),
+		PreviousDate:  alert.PreviousDate.Format("2006-01-02"),
+		PreviousValue: alert.PreviousValue,
+		Value:         alert.Value,
+		Change:        alert.Change,
+		ChangePercent: alert.ChangePercent,
+		ThresholdType: rule.ThresholdType,
+		Threshold:     rule.Threshold,
+		Direction:     rule.Direction,
+		TriggeredAt:   alert.TriggeredAt,
+	})
+	if err != nil {
+		s.logger.WithError(err).Errorf("Failed to encode alert %d", alert.ID)
+		return
+	}
+
+	s.registerSecret(rule.Secret)
+	attempts, deliverErr := s.notifier.Deliver(s.ctx, rule.WebhookURL, rule.Secret, payload)
+	ctx := context.Background()
+
+	alert.Attempts = attempts
+	if deliverErr == nil {
+		deliveredAt := s.now()
+		alert.Status = entity.AlertDelivered
+		alert.DeliveredAt = &deliveredAt
+	} else {
+		alert.Status = entity.AlertFailed
+		alert.LastError = deliverErr.Error()
+		s.logger.WithError(deliverErr).Errorf("Failed to deliver alert %d to %s after %d attempts", alert.ID, logger.RedactURL(rule.WebhookURL), attempts)
+
+		letter := &entity.WebhookDeadLetter{
+			AlertID:   alert.ID,
+			URL:       rule.WebhookURL,
+			Payload:   payload,
+			Attempts:  attempts,
+			LastError: deliverErr.Error(),
+			CreatedAt: s.now(),
+		}
+		if err := s.repo.StoreDeadLetter(ctx, letter); err != nil {
+			s.logger.WithError(err).Errorf("Failed to store dead letter for alert %d", alert.ID)
+		}
+	}
+
+	if err := s.repo.UpdateAlertDelivery(ctx, alert); err != nil {
+		s.logger.WithError(err).Errorf("Failed to record delivery of alert %d", alert.ID)
+	}
+}
+
+func validateAlertRule(rule *entity.AlertRule) error {
+	switch rule.ThresholdType {
+	case entity.AlertThresholdPercent, entity.AlertThresholdAbsolute:
+	default:
+		return fmt.Errorf("%w: threshold_type must be %q or %q", ErrInvalidAlertRule, entity.AlertThresholdPercent, entity.AlertThresholdAbsolute)
+	}
+	if !rule.Threshold.IsPositive() {
+		return fmt.Errorf("%w: threshold must be positive", ErrInvalidAlertRule)
+	}
+	switch rule.Direction {
+	case entity.AlertDirectionUp, entity.AlertDirectionDown, entity.AlertDirectionBoth:
+	default:
+		return fmt.Errorf("%w: direction must be %q, %q or %q", ErrInvalidAlertRule, entity.AlertDirectionUp, entity.AlertDirectionDown, entity.AlertDirectionBoth)
+	}
+	u, err := url.Parse(rule.WebhookURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("%w: webhook_url must be an absolute http(s) URL", ErrInvalidAlertRule)
+	}
+	rule.CharCode = strings.ToUpper(rule.CharCode)
+	return nil
+}
+
+func newWebhookSecret() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
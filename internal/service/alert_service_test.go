@@ -0,0 +1,410 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"RnD-service/internal/adapter/postgres"
+	"RnD-service/internal/adapter/provider"
+	"RnD-service/internal/adapter/webhook"
+	"RnD-service/internal/entity"
+	"RnD-service/pkg/logger"
+	"RnD-service/pkg/netguard"
+
+	"github.com/shopspring/decimal"
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type mockAlertRepo struct {
+	mock.Mock
+}
+
+func (m *mockAlertRepo) CreateAlertRule(ctx context.Context, rule *entity.AlertRule) (int64, error) {
+	args := m.Called(ctx, rule)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *mockAlertRepo) UpdateAlertRule(ctx context.Context, rule *entity.AlertRule) error {
+	args := m.Called(ctx, rule)
+	return args.Error(0)
+}
+
+func (m *mockAlertRepo) DeleteAlertRule(ctx context.Context, id int64) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *mockAlertRepo) GetAlertRule(ctx context.Context, id int64) (*entity.AlertRule, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.AlertRule), args.Error(1)
+}
+
+func (m *mockAlertRepo) ListAlertRules(ctx context.Context) ([]entity.AlertRule, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]entity.AlertRule), args.Error(1)
+}
+
+func (m *mockAlertRepo) ListEnabledAlertRules(ctx context.Context, source string) ([]entity.AlertRule, error) {
+	args := m.Called(ctx, source)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]entity.AlertRule), args.Error(1)
+}
+
+func (m *mockAlertRepo) CreateAlert(ctx context.Context, alert *entity.Alert) (int64, bool, error) {
+	args := m.Called(ctx, alert)
+	return args.Get(0).(int64), args.Bool(1), args.Error(2)
+}
+
+func (m *mockAlertRepo) UpdateAlertDelivery(ctx context.Context, alert *entity.Alert) error {
+	args := m.Called(ctx, alert)
+	return args.Error(0)
+}
+
+func (m *mockAlertRepo) ListAlerts(ctx context.Context, dateFrom, dateTo string) ([]entity.Alert, error) {
+	args := m.Called(ctx, dateFrom, dateTo)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]entity.Alert), args.Error(1)
+}
+
+func (m *mockAlertRepo) StoreDeadLetter(ctx context.Context, letter *entity.WebhookDeadLetter) error {
+	args := m.Called(ctx, letter)
+	return args.Error(0)
+}
+
+func (m *mockAlertRepo) ListDeadLetters(ctx context.Context, limit uint64) ([]entity.WebhookDeadLetter, error) {
+	args := m.Called(ctx, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]entity.WebhookDeadLetter), args.Error(1)
+}
+
+func setupAlertService() (*AlertService, *mockAlertRepo, *mockPostgresRepo) {
+	mockAlerts := new(mockAlertRepo)
+	mockRates := new(mockPostgresRepo)
+	logger, _ := test.NewNullLogger()
+	loopback, _ := netguard.ParseAllowlist([]string{"127.0.0.0/8", "::1"})
+	notifier := webhook.NewClient(logger, webhook.WithRetry(2, time.Millisecond, time.Millisecond), webhook.WithAllowlist(loopback))
+	service := NewAlertService(mockAlerts, mockRates, notifier, logger)
+	service.SetCalendar("cbr", provider.CBRCalendar())
+	now := time.Date(2025, 8, 5, 12, 0, 0, 0, time.UTC)
+	service.now = func() time.Time { return now }
+	return service, mockAlerts, mockRates
+}
+
+func usdRule(url string) entity.AlertRule {
+	return entity.AlertRule{
+		ID:            3,
+		Source:        "cbr",
+		CharCode:      "USD",
+		ThresholdType: entity.AlertThresholdPercent,
+		Threshold:     decimal.RequireFromString("0.3"),
+		Direction:     entity.AlertDirectionBoth,
+		WebhookURL:    url,
+		Secret:        "s3cret",
+		Enabled:       true,
+	}
+}
+
+// usdPair is the USD publication of 2025-08-05 and the one before it, 0.37% lower.
+func usdPair() (previous, current entity.Currency) {
+	previous = entity.Currency{CharCode: "USD", Nominal: 1, Value: decimal.RequireFromString("79.7653"), Date: time.Date(2025, 8, 2, 0, 0, 0, 0, time.UTC), Source: "cbr"}
+	current = entity.Currency{CharCode: "USD", Nominal: 1, Value: decimal.RequireFromString("80.0613"), Date: time.Date(2025, 8, 5, 0, 0, 0, 0, time.UTC), Source: "cbr"}
+	return previous, current
+}
+
+func TestAlertService_CreateRule_GeneratesSecret(t *testing.T) {
+	ctx := context.Background()
+	service, mockAlerts, _ := setupAlertService()
+
+	rule := usdRule("https://hooks.example.com/rates")
+	rule.ID = 0
+	rule.Secret = ""
+	rule.CharCode = "usd"
+	mockAlerts.On("CreateAlertRule", ctx, mock.AnythingOfType("*entity.AlertRule")).Return(int64(7), nil)
+
+	created, err := service.CreateRule(ctx, &rule)
+	require.NoError(t, err)
+	assert.Equal(t, int64(7), created.ID)
+	assert.Equal(t, "USD", created.CharCode)
+	assert.Len(t, created.Secret, 64)
+	assert.Equal(t, service.now(), created.CreatedAt)
+	mockAlerts.AssertExpectations(t)
+}
+
+func TestAlertService_CreateRule_RegistersSecret(t *testing.T) {
+	ctx := context.Background()
+	service, mockAlerts, _ := setupAlertService()
+	redact := logger.NewRedactHook()
+	service.logger.AddHook(redact)
+	recorded := test.NewLocal(service.logger)
+	service.SetRedactHook(redact)
+
+	rule := usdRule("https://hooks.example.com/rates")
+	rule.Secret = ""
+	mockAlerts.On("CreateAlertRule", ctx, mock.AnythingOfType("*entity.AlertRule")).Return(int64(7), nil)
+
+	created, err := service.CreateRule(ctx, &rule)
+	require.NoError(t, err)
+
+	service.logger.Infof("secret %s", created.Secret)
+	assert.Equal(t, "secret xxxxx", recorded.LastEntry().Message)
+}
+
+func TestAlertService_CreateRule_Invalid(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(r *entity.AlertRule)
+	}{
+		{"threshold type", func(r *entity.AlertRule) { r.ThresholdType = "ratio" }},
+		{"zero threshold", func(r *entity.AlertRule) { r.Threshold = decimal.Zero }},
+		{"negative threshold", func(r *entity.AlertRule) { r.Threshold = decimal.NewFromInt(-1) }},
+		{"direction", func(r *entity.AlertRule) { r.Direction = "sideways" }},
+		{"relative url", func(r *entity.AlertRule) { r.WebhookURL = "/hooks" }},
+		{"scheme", func(r *entity.AlertRule) { r.WebhookURL = "ftp://hooks.example.com" }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, mockAlerts, _ := setupAlertService()
+			rule := usdRule("https://hooks.example.com/rates")
+			tt.modify(&rule)
+
+			_, err := service.CreateRule(context.Background(), &rule)
+			assert.ErrorIs(t, err, ErrInvalidAlertRule)
+			mockAlerts.AssertNotCalled(t, "CreateAlertRule", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestAlertService_UpdateRule_KeepsSecret(t *testing.T) {
+	ctx := context.Background()
+	service, mockAlerts, _ := setupAlertService()
+
+	stored := usdRule("https://hooks.example.com/rates")
+	stored.CreatedAt = time.Date(2025, 8, 1, 0, 0, 0, 0, time.UTC)
+	mockAlerts.On("GetAlertRule", ctx, int64(3)).Return(&stored, nil)
+	mockAlerts.On("UpdateAlertRule", ctx, mock.MatchedBy(func(r *entity.AlertRule) bool {
+		return r.Secret == "s3cret" && r.Threshold.Equal(decimal.NewFromInt(1)) && r.CreatedAt.Equal(stored.CreatedAt)
+	})).Return(nil)
+
+	update := usdRule("https://hooks.example.com/rates")
+	update.Secret = ""
+	update.Threshold = decimal.NewFromInt(1)
+	updated, err := service.UpdateRule(ctx, &update)
+	require.NoError(t, err)
+	assert.Equal(t, service.now(), updated.UpdatedAt)
+	mockAlerts.AssertExpectations(t)
+}
+
+func TestAlertService_DeleteRule_NotFound(t *testing.T) {
+	ctx := context.Background()
+	service, mockAlerts, _ := setupAlertService()
+
+	mockAlerts.On("DeleteAlertRule", ctx, int64(9)).Return(postgres.ErrNotFound)
+
+	err := service.DeleteRule(ctx, 9)
+	assert.ErrorIs(t, err, ErrAlertRuleNotFound)
+}
+
+func TestAlertService_Evaluate_DeliversSignedWebhook(t *testing.T) {
+	ctx := context.Background()
+	service, mockAlerts, mockRates := setupAlertService()
+
+	var received alertPayload
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		timestamp, err := strconv.ParseInt(r.Header.Get(webhook.HeaderTimestamp), 10, 64)
+		assert.NoError(t, err)
+		assert.Equal(t, webhook.Sign("s3cret", timestamp, body), r.Header.Get(webhook.HeaderSignature))
+		assert.NoError(t, json.Unmarshal(body, &received))
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	previous, current := usdPair()
+	mockAlerts.On("ListEnabledAlertRules", ctx, "cbr").Return([]entity.AlertRule{usdRule(srv.URL)}, nil)
+	mockRates.On("GetRateByCharCodeAndDate", ctx, "cbr", "USD", "2025-08-02").Return(&previous, nil)
+	mockAlerts.On("CreateAlert", ctx, mock.MatchedBy(func(a *entity.Alert) bool {
+		return a.RuleID == 3 && a.Status == entity.AlertPending &&
+			a.Change.Equal(decimal.RequireFromString("0.296")) &&
+			a.ChangePercent.Equal(decimal.RequireFromString("0.3711")) &&
+			a.PreviousDate.Equal(previous.Date)
+	})).Return(int64(11), true, nil)
+	mockAlerts.On("UpdateAlertDelivery", mock.Anything, mock.MatchedBy(func(a *entity.Alert) bool {
+		return a.ID == 11 && a.Status == entity.AlertDelivered && a.Attempts == 1 && a.DeliveredAt != nil
+	})).Return(nil)
+
+	usd := current
+	eur := entity.Currency{CharCode: "EUR", Nominal: 1, Value: decimal.RequireFromString("92.1"), Date: current.Date, Source: "cbr"}
+	require.NoError(t, service.Evaluate(ctx, []entity.Currency{usd, eur}))
+	service.wait()
+
+	assert.Equal(t, int64(11), received.ID)
+	assert.Equal(t, alertEvent, received.Event)
+	assert.Equal(t, "USD", received.CharCode)
+	assert.Equal(t, "2025-08-05", received.Date)
+	assert.Equal(t, "2025-08-02", received.PreviousDate)
+	assert.True(t, received.Value.Equal(decimal.RequireFromString("80.0613")))
+	mockAlerts.AssertExpectations(t)
+	mockAlerts.AssertNotCalled(t, "StoreDeadLetter", mock.Anything, mock.Anything)
+}
+
+func TestAlertService_Evaluate_FailedDeliveryGoesToDeadLetters(t *testing.T) {
+	ctx := context.Background()
+	service, mockAlerts, mockRates := setupAlertService()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer srv.Close()
+
+	previous, current := usdPair()
+	mockAlerts.On("ListEnabledAlertRules", ctx, "cbr").Return([]entity.AlertRule{usdRule(srv.URL)}, nil)
+	mockRates.On("GetRateByCharCodeAndDate", ctx, "cbr", "USD", "2025-08-02").Return(&previous, nil)
+	mockAlerts.On("CreateAlert", ctx, mock.Anything).Return(int64(11), true, nil)
+	mockAlerts.On("StoreDeadLetter", mock.Anything, mock.MatchedBy(func(l *entity.WebhookDeadLetter) bool {
+		return l.AlertID == 11 && l.URL == srv.URL && l.Attempts == 2 && json.Valid(l.Payload) && l.LastError != ""
+	})).Return(nil)
+	mockAlerts.On("UpdateAlertDelivery", mock.Anything, mock.MatchedBy(func(a *entity.Alert) bool {
+		return a.Status == entity.AlertFailed && a.Attempts == 2 && a.DeliveredAt == nil
+	})).Return(nil)
+
+	require.NoError(t, service.Evaluate(ctx, []entity.Currency{current}))
+	service.wait()
+
+	mockAlerts.AssertExpectations(t)
+}
+
+func TestAlertService_Evaluate_NotTriggered(t *testing.T) {
+	previous, current := usdPair()
+
+	tests := []struct {
+		name   string
+		modify func(r *entity.AlertRule)
+	}{
+		{"below percent threshold", func(r *entity.AlertRule) { r.Threshold = decimal.RequireFromString("0.5") }},
+		{"below absolute threshold", func(r *entity.AlertRule) {
+			r.ThresholdType = entity.AlertThresholdAbsolute
+			r.Threshold = decimal.RequireFromString("0.3")
+		}},
+		{"opposite direction", func(r *entity.AlertRule) { r.Direction = entity.AlertDirectionDown }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			service, mockAlerts, mockRates := setupAlertService()
+
+			rule := usdRule("https://hooks.example.com/rates")
+			tt.modify(&rule)
+			mockAlerts.On("ListEnabledAlertRules", ctx, "cbr").Return([]entity.AlertRule{rule}, nil)
+			mockRates.On("GetRateByCharCodeAndDate", ctx, "cbr", "USD", "2025-08-02").Return(&previous, nil)
+
+			require.NoError(t, service.Evaluate(ctx, []entity.Currency{current}))
+			mockAlerts.AssertNotCalled(t, "CreateAlert", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestAlertService_Evaluate_PerUnitValues(t *testing.T) {
+	ctx := context.Background()
+	service, mockAlerts, mockRates := setupAlertService()
+
+	rule := usdRule("https://hooks.example.com/rates")
+	rule.CharCode = "JPY"
+	rule.ThresholdType = entity.AlertThresholdAbsolute
+	rule.Threshold = decimal.RequireFromString("0.01")
+	rule.Direction = entity.AlertDirectionDown
+
+	// the nominal changed from 100 to 10 yen between the publications
+	previous := entity.Currency{CharCode: "JPY", Nominal: 100, Value: decimal.RequireFromString("54.5"), Date: time.Date(2025, 8, 2, 0, 0, 0, 0, time.UTC), Source: "cbr"}
+	current := entity.Currency{CharCode: "JPY", Nominal: 10, Value: decimal.RequireFromString("5.3"), Date: time.Date(2025, 8, 5, 0, 0, 0, 0, time.UTC), Source: "cbr"}
+
+	mockAlerts.On("ListEnabledAlertRules", ctx, "cbr").Return([]entity.AlertRule{rule}, nil)
+	mockRates.On("GetRateByCharCodeAndDate", ctx, "cbr", "JPY", "2025-08-02").Return(&previous, nil)
+	// the alert was recorded when this publication was stored before
+	mockAlerts.On("CreateAlert", ctx, mock.MatchedBy(func(a *entity.Alert) bool {
+		return a.PreviousValue.Equal(decimal.RequireFromString("0.545")) && a.Value.Equal(decimal.RequireFromString("0.53")) &&
+			a.Change.Equal(decimal.RequireFromString("-0.015"))
+	})).Return(int64(0), false, nil)
+
+	require.NoError(t, service.Evaluate(ctx, []entity.Currency{current}))
+	service.wait()
+
+	mockAlerts.AssertExpectations(t)
+	mockAlerts.AssertNotCalled(t, "UpdateAlertDelivery", mock.Anything, mock.Anything)
+}
+
+func TestAlertService_Evaluate_NoPreviousPublication(t *testing.T) {
+	ctx := context.Background()
+	service, mockAlerts, mockRates := setupAlertService()
+
+	_, current := usdPair()
+	mockAlerts.On("ListEnabledAlertRules", ctx, "cbr").Return([]entity.AlertRule{usdRule("https://hooks.example.com/rates")}, nil)
+	mockRates.On("GetRateByCharCodeAndDate", ctx, "cbr", "USD", "2025-08-02").Return((*entity.Currency)(nil), postgres.ErrNotFound)
+
+	require.NoError(t, service.Evaluate(ctx, []entity.Currency{current}))
+	mockAlerts.AssertNotCalled(t, "CreateAlert", mock.Anything, mock.Anything)
+}
+
+func TestAlertService_Evaluate_PreviousPublicationNotStored(t *testing.T) {
+	ctx := context.Background()
+	service, mockAlerts, mockRates := setupAlertService()
+
+	_, current := usdPair()
+	// the cache skipped 2025-08-02, the row before it is a week older
+	stale := entity.Currency{CharCode: "USD", Nominal: 1, Value: decimal.RequireFromString("78.1"), Date: time.Date(2025, 7, 26, 0, 0, 0, 0, time.UTC), Source: "cbr"}
+	mockAlerts.On("ListEnabledAlertRules", ctx, "cbr").Return([]entity.AlertRule{usdRule("https://hooks.example.com/rates")}, nil)
+	mockRates.On("GetRateByCharCodeAndDate", ctx, "cbr", "USD", "2025-08-02").Return(&stale, nil)
+
+	require.NoError(t, service.Evaluate(ctx, []entity.Currency{current}))
+	mockAlerts.AssertNotCalled(t, "CreateAlert", mock.Anything, mock.Anything)
+}
+
+func TestAlertService_Evaluate_WithoutCalendar(t *testing.T) {
+	ctx := context.Background()
+	service, mockAlerts, mockRates := setupAlertService()
+
+	rule := usdRule("https://hooks.example.com/rates")
+	rule.Source = "nbk"
+	previous := entity.Currency{CharCode: "USD", Nominal: 1, Value: decimal.RequireFromString("540"), Date: time.Date(2025, 8, 4, 0, 0, 0, 0, time.UTC), Source: "nbk"}
+	current := entity.Currency{CharCode: "USD", Nominal: 1, Value: decimal.RequireFromString("541"), Date: time.Date(2025, 8, 5, 0, 0, 0, 0, time.UTC), Source: "nbk"}
+	mockAlerts.On("ListEnabledAlertRules", ctx, "nbk").Return([]entity.AlertRule{rule}, nil)
+	mockRates.On("GetRateByCharCodeAndDate", ctx, "nbk", "USD", "2025-08-04").Return(&previous, nil)
+
+	require.NoError(t, service.Evaluate(ctx, []entity.Currency{current}))
+	mockRates.AssertExpectations(t)
+	mockAlerts.AssertNotCalled(t, "CreateAlert", mock.Anything, mock.Anything)
+}
+
+func TestAlertService_Evaluate_RulesError(t *testing.T) {
+	ctx := context.Background()
+	service, mockAlerts, _ := setupAlertService()
+
+	_, current := usdPair()
+	mockAlerts.On("ListEnabledAlertRules", ctx, "cbr").Return(nil, errors.New("connection reset"))
+
+	err := service.Evaluate(ctx, []entity.Currency{current})
+	assert.ErrorContains(t, err, "connection reset")
+}
@@ -0,0 +1,410 @@
+package service
+
+import (
+	"RnD-service/internal/adapter/postgres"
+	"RnD-service/internal/adapter/provider"
+	"RnD-service/internal/entity"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+type BackfillOptions struct {
+	ChunkDays       int
+	Concurrency     int
+	RequestInterval time.Duration
+}
+
+var DefaultBackfillOptions = BackfillOptions{
+	ChunkDays:       31,
+	Concurrency:     4,
+	RequestInterval: 200 * time.Millisecond,
+}
+
+const backfillListLimit = 50
+
+type BackfillService struct {
+	rates   provider.RateProvider
+	dbRepo  postgres.PostgresRepository
+	jobRepo postgres.BackfillRepository
+	opts    BackfillOptions
+	logger  *logrus.Logger
+	now     func() time.Time
+
+	// background jobs outlive the request that started them and stop on Shutdown
+	ctx     context.Context
+	cancel  context.CancelFunc
+	mu      sync.Mutex
+	running map[int64]postgres.Locker
+	wg      sync.WaitGroup
+
+	newLock func(name string) postgres.Locker
+}
+
+func NewBackfillService(rates provider.RateProvider, dbRepo postgres.PostgresRepository, jobRepo postgres.BackfillRepository, opts BackfillOptions, logger *logrus.Logger) *BackfillService {
+	if opts.ChunkDays <= 0 {
+		opts.ChunkDays = DefaultBackfillOptions.ChunkDays
+	}
+	if opts.Concurrency <= 0 {
+		opts.Concurrency = DefaultBackfillOptions.Concurrency
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	return &BackfillService{
+		rates:   rates,
+		dbRepo:  dbRepo,
+		jobRepo: jobRepo,
+		opts:    opts,
+		logger:  logger,
+		now:     time.Now,
+		ctx:     ctx,
+		cancel:  cancel,
+		running: make(map[int64]postgres.Locker),
+	}
+}
+
+// SetLocker makes a job run on one replica or CLI process at a time: the
+// lock named "backfill:<id>" is held for as long as the job runs. Without
+// it jobs are only kept from running twice within this process.
+func (s *BackfillService) SetLocker(newLock func(name string) postgres.Locker) {
+	s.newLock = newLock
+}
+
+func (s *BackfillService) CreateJob(ctx context.Context, dateFrom, dateTo time.Time, charCodes []string) (*entity.BackfillJob, error) {
+	from := dateFrom.Truncate(24 * time.Hour)
+	to := dateTo.Truncate(24 * time.Hour)
+
+	if from.After(to) {
+		s.logger.Warnf("Invalid backfill range: %s > %s", from.Format("2006-01-02"), to.Format("2006-01-02"))
+		return nil, fmt.Errorf("%w: 'from' is after 'to'", ErrInvalidDateRange)
+	}
+	if to.After(s.now().Truncate(24 * time.Hour)) {
+		s.logger.Warnf("Requested backfill into the future: %s", to.Format("2006-01-02"))
+		return nil, ErrFutureDate
+	}
+
+	codes := make([]string, 0, len(charCodes))
+	for _, code := range charCodes {
+		codes = append(codes, strings.ToUpper(code))
+	}
+
+	now := s.now()
+	job := &entity.BackfillJob{
+		DateFrom:  from,
+		DateTo:    to,
+		CharCodes: codes,
+		Status:    entity.BackfillPending,
+		NextDate:  from,
+		DaysTotal: int(to.Sub(from).Hours()/24) + 1,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+
+	id, err := s.jobRepo.CreateBackfillJob(ctx, job)
+	if err != nil {
+		s.logger.Errorf("Failed to create backfill job: %v", err)
+		return nil, fmt.Errorf("create backfill job: %w", err)
+	}
+	job.ID = id
+
+	s.logger.Infof("Created backfill job %d for %s - %s (%d days)", job.ID, from.Format("2006-01-02"), to.Format("2006-01-02"), job.DaysTotal)
+	return job, nil
+}
+
+func (s *BackfillService) GetJob(ctx context.Context, id int64) (*entity.BackfillJob, error) {
+	job, err := s.jobRepo.GetBackfillJob(ctx, id)
+	if err != nil {
+		if errors.Is(err, postgres.ErrNotFound) {
+			return nil, fmt.Errorf("%w: %d", ErrBackfillJobNotFound, id)
+		}
+		s.logger.Errorf("Failed to get backfill job %d: %v", id, err)
+		return nil, fmt.Errorf("get backfill job: %w", err)
+	}
+	return job, nil
+}
+
+func (s *BackfillService) ListJobs(ctx context.Context) ([]entity.BackfillJob, error) {
+	jobs, err := s.jobRepo.ListBackfillJobs(ctx, backfillListLimit)
+	if err != nil {
+		s.logger.Errorf("Failed to list backfill jobs: %v", err)
+		return nil, fmt.Errorf("list backfill jobs: %w", err)
+	}
+	return jobs, nil
+}
+
+// Start runs the job in the background. The job stops on Shutdown and keeps
+// its progress, so it is picked up again by ResumeUnfinished.
+func (s *BackfillService) Start(id int64) error {
+	claimed, err := s.claim(s.ctx, id)
+	if err != nil {
+		return err
+	}
+	if !claimed {
+		return fmt.Errorf("%w: %d", ErrBackfillJobRunning, id)
+	}
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		defer s.release(id)
+
+		if err := s.run(s.ctx, id); err != nil {
+			s.logger.WithError(err).Errorf("Backfill job %d stopped", id)
+		}
+	}()
+	return nil
+}
+
+// Run executes the job synchronously; used by the CLI subcommand.
+func (s *BackfillService) Run(ctx context.Context, id int64) error {
+	claimed, err := s.claim(ctx, id)
+	if err != nil {
+		return err
+	}
+	if !claimed {
+		return fmt.Errorf("%w: %d", ErrBackfillJobRunning, id)
+	}
+	defer s.release(id)
+
+	return s.run(ctx, id)
+}
+
+func (s *BackfillService) ResumeUnfinished(ctx context.Context) error {
+	jobs, err := s.jobRepo.ListUnfinishedBackfillJobs(ctx)
+	if err != nil {
+		s.logger.Errorf("Failed to list unfinished backfill jobs: %v", err)
+		return fmt.Errorf("list unfinished backfill jobs: %w", err)
+	}
+
+	// every replica resumes on start; the job lock leaves each job to one of them
+	for _, job := range jobs {
+		err := s.Start(job.ID)
+		switch {
+		case errors.Is(err, ErrBackfillJobRunning):
+			s.logger.Infof("Backfill job %d is running elsewhere, not resuming", job.ID)
+		case err != nil:
+			s.logger.WithError(err).Warnf("Failed to resume backfill job %d", job.ID)
+		default:
+			s.logger.Infof("Resuming backfill job %d from %s", job.ID, job.NextDate.Format("2006-01-02"))
+		}
+	}
+	return nil
+}
+
+// Shutdown interrupts background jobs and waits for them to save progress.
+func (s *BackfillService) Shutdown() {
+	s.cancel()
+	s.wg.Wait()
+}
+
+func (s *BackfillService) claim(ctx context.Context, id int64) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.running[id]; ok {
+		return false, nil
+	}
+
+	var lock postgres.Locker
+	if s.newLock != nil {
+		lock = s.newLock(fmt.Sprintf("backfill:%d", id))
+		acquired, err := lock.TryAcquire(ctx)
+		if err != nil {
+			s.logger.WithError(err).Errorf("Failed to lock backfill job %d", id)
+			return false, fmt.Errorf("lock backfill job %d: %w", id, err)
+		}
+		if !acquired {
+			return false, nil
+		}
+	}
+	s.running[id] = lock
+	return true, nil
+}
+
+func (s *BackfillService) release(id int64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if lock := s.running[id]; lock != nil {
+		// the job context may be cancelled by now
+		if err := lock.Release(context.Background()); err != nil {
+			s.logger.WithError(err).Errorf("Failed to unlock backfill job %d", id)
+		}
+	}
+	delete(s.running, id)
+}
+
+func (s *BackfillService) run(ctx context.Context, id int64) error {
+	job, err := s.GetJob(ctx, id)
+	if err != nil {
+		return err
+	}
+	if job.Status == entity.BackfillCompleted {
+		s.logger.Infof("Backfill job %d is already completed", id)
+		return nil
+	}
+
+	job.Status = entity.BackfillRunning
+	job.Error = ""
+	job.FinishedAt = nil
+	if err := s.saveProgress(ctx, job); err != nil {
+		return err
+	}
+
+	limiter := time.NewTicker(max(s.opts.RequestInterval, time.Millisecond))
+	defer limiter.Stop()
+
+	for !job.NextDate.After(job.DateTo) {
+		chunkEnd := job.NextDate.AddDate(0, 0, s.opts.ChunkDays-1)
+		if chunkEnd.After(job.DateTo) {
+			chunkEnd = job.DateTo
+		}
+
+		s.logger.Infof("Backfill job %d: processing %s - %s", job.ID, job.NextDate.Format("2006-01-02"), chunkEnd.Format("2006-01-02"))
+		if err := s.backfillChunk(ctx, job, job.NextDate, chunkEnd, limiter.C); err != nil {
+			if ctx.Err() != nil {
+				s.logger.Warnf("Backfill job %d interrupted at %s, will resume later", job.ID, job.NextDate.Format("2006-01-02"))
+				return ctx.Err()
+			}
+			return s.fail(ctx, job, err)
+		}
+
+		job.DaysDone += int(chunkEnd.Sub(job.NextDate).Hours()/24) + 1
+		job.NextDate = chunkEnd.AddDate(0, 0, 1)
+		if err := s.saveProgress(ctx, job); err != nil {
+			return err
+		}
+	}
+
+	finishedAt := s.now()
+	job.Status = entity.BackfillCompleted
+	job.FinishedAt = &finishedAt
+	if err := s.saveProgress(ctx, job); err != nil {
+		return err
+	}
+
+	s.logger.Infof("Backfill job %d completed: %d days", job.ID, job.DaysDone)
+	return nil
+}
+
+// backfillChunk fetches one range per wanted currency when the job is
+// filtered, since that takes two requests however long the chunk is, and
+// otherwise every publication day of the chunk.
+func (s *BackfillService) backfillChunk(ctx context.Context, job *entity.BackfillJob, from, to time.Time, tick <-chan time.Time) error {
+	var tasks []func() error
+	if len(job.CharCodes) > 0 {
+		for _, code := range job.CharCodes {
+			tasks = append(tasks, func() error { return s.backfillRange(ctx, code, from, to) })
+		}
+	} else {
+		calendar := s.rates.Calendar()
+		for d := from; !d.After(to); d = d.AddDate(0, 0, 1) {
+			// days without a publication would only fetch the one in effect again
+			if !calendar.IsPublicationDay(d) {
+				continue
+			}
+			tasks = append(tasks, func() error { return s.backfillDay(ctx, d) })
+		}
+	}
+
+	sem := make(chan struct{}, s.opts.Concurrency)
+	errCh := make(chan error, len(tasks))
+	var wg sync.WaitGroup
+
+	for _, task := range tasks {
+		select {
+		case <-ctx.Done():
+			wg.Wait()
+			return ctx.Err()
+		case <-tick:
+		}
+		select {
+		case <-ctx.Done():
+			wg.Wait()
+			return ctx.Err()
+		case sem <- struct{}{}:
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+			if err := task(); err != nil {
+				errCh <- err
+			}
+		}()
+	}
+
+	wg.Wait()
+	close(errCh)
+	return <-errCh
+}
+
+func (s *BackfillService) backfillDay(ctx context.Context, date time.Time) error {
+	rates, err := s.rates.FetchDaily(ctx, date)
+	if err != nil {
+		s.logger.Errorf("Backfill: failed to fetch rates from %s for %s: %v", s.rates.Name(), date.Format("2006-01-02"), err)
+		return fmt.Errorf("fetch rates for %s: %w: %w", date.Format("2006-01-02"), ErrUpstreamUnavailable, err)
+	}
+	if len(rates) == 0 {
+		s.logger.Warnf("Backfill: no rates published for %s", date.Format("2006-01-02"))
+		return nil
+	}
+	if rates[0].Date.After(date) {
+		s.logger.Warnf("Backfill: %s returned rates for %s instead of %s, skipping", s.rates.Name(), rates[0].Date.Format("2006-01-02"), date.Format("2006-01-02"))
+		return nil
+	}
+
+	// days without a publication resolve to the one in effect, stored once under its own date;
+	// alert rules are not evaluated for backfilled history
+	effectiveDate := rates[0].Date
+	if err := s.dbRepo.StoreRates(ctx, rates); err != nil {
+		s.logger.Errorf("Backfill: failed to store rates for %s: %v", effectiveDate.Format("2006-01-02"), err)
+		return fmt.Errorf("store rates for %s: %w", effectiveDate.Format("2006-01-02"), err)
+	}
+	return nil
+}
+
+func (s *BackfillService) backfillRange(ctx context.Context, charCode string, from, to time.Time) error {
+	rates, err := s.rates.FetchRange(ctx, charCode, from, to)
+	if errors.Is(err, provider.ErrCurrencyNotQuoted) {
+		s.logger.Warnf("Backfill: %s is not quoted by %s on %s, skipping %s - %s", charCode, s.rates.Name(), to.Format("2006-01-02"), from.Format("2006-01-02"), to.Format("2006-01-02"))
+		return nil
+	}
+	if err != nil {
+		s.logger.Errorf("Backfill: failed to fetch %s rates from %s for %s - %s: %v", charCode, s.rates.Name(), from.Format("2006-01-02"), to.Format("2006-01-02"), err)
+		return fmt.Errorf("fetch %s rates for %s - %s: %w: %w", charCode, from.Format("2006-01-02"), to.Format("2006-01-02"), ErrUpstreamUnavailable, err)
+	}
+	if len(rates) == 0 {
+		s.logger.Warnf("Backfill: no %s rates published for %s - %s", charCode, from.Format("2006-01-02"), to.Format("2006-01-02"))
+		return nil
+	}
+
+	if err := s.dbRepo.StoreRates(ctx, rates); err != nil {
+		s.logger.Errorf("Backfill: failed to store %s rates for %s - %s: %v", charCode, from.Format("2006-01-02"), to.Format("2006-01-02"), err)
+		return fmt.Errorf("store %s rates for %s - %s: %w", charCode, from.Format("2006-01-02"), to.Format("2006-01-02"), err)
+	}
+	return nil
+}
+
+func (s *BackfillService) fail(ctx context.Context, job *entity.BackfillJob, cause error) error {
+	finishedAt := s.now()
+	job.Status = entity.BackfillFailed
+	job.Error = cause.Error()
+	job.FinishedAt = &finishedAt
+	if err := s.saveProgress(ctx, job); err != nil {
+		return errors.Join(cause, err)
+	}
+	return cause
+}
+
+func (s *BackfillService) saveProgress(ctx context.Context, job *entity.BackfillJob) error {
+	job.UpdatedAt = s.now()
+	if err := s.jobRepo.UpdateBackfillJob(ctx, job); err != nil {
+		s.logger.Errorf("Failed to save backfill job %d progress: %v", job.ID, err)
+		return fmt.Errorf("update backfill job: %w", err)
+	}
+	return nil
+}
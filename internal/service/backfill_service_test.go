@@ -0,0 +1,315 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"RnD-service/internal/adapter/cbr"
+	"RnD-service/internal/adapter/postgres"
+	"RnD-service/internal/adapter/provider"
+	"RnD-service/internal/entity"
+
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type mockBackfillRepo struct {
+	mock.Mock
+	mu      sync.Mutex
+	updates []entity.BackfillJob
+}
+
+func (m *mockBackfillRepo) CreateBackfillJob(ctx context.Context, job *entity.BackfillJob) (int64, error) {
+	args := m.Called(ctx, job)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *mockBackfillRepo) UpdateBackfillJob(ctx context.Context, job *entity.BackfillJob) error {
+	m.mu.Lock()
+	m.updates = append(m.updates, *job)
+	m.mu.Unlock()
+	args := m.Called(ctx, job)
+	return args.Error(0)
+}
+
+func (m *mockBackfillRepo) GetBackfillJob(ctx context.Context, id int64) (*entity.BackfillJob, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.BackfillJob), args.Error(1)
+}
+
+func (m *mockBackfillRepo) ListBackfillJobs(ctx context.Context, limit uint64) ([]entity.BackfillJob, error) {
+	args := m.Called(ctx, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]entity.BackfillJob), args.Error(1)
+}
+
+func (m *mockBackfillRepo) ListUnfinishedBackfillJobs(ctx context.Context) ([]entity.BackfillJob, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]entity.BackfillJob), args.Error(1)
+}
+
+func setupBackfillService(opts BackfillOptions) (*BackfillService, *mockCbrClient, *mockPostgresRepo, *mockBackfillRepo) {
+	mockCbr := new(mockCbrClient)
+	mockRepo := new(mockPostgresRepo)
+	mockJobs := new(mockBackfillRepo)
+	logger, _ := test.NewNullLogger()
+	service := NewBackfillService(provider.NewCBRProvider(mockCbr, logger), mockRepo, mockJobs, opts, logger)
+	now := time.Date(2025, 8, 1, 12, 0, 0, 0, time.UTC)
+	service.now = func() time.Time { return now }
+	return service, mockCbr, mockRepo, mockJobs
+}
+
+func dailyResponse(date time.Time) *cbr.ValCurs {
+	return &cbr.ValCurs{
+		Date: date.Format("02.01.2006"),
+		Valutes: []cbr.Valute{
+			{ID: "R01235", CharCode: "USD", Name: "US Dollar", Nominal: 1, Value: "90,5", NumCode: "840"},
+			{ID: "R01239", CharCode: "EUR", Name: "Euro", Nominal: 1, Value: "100,2", NumCode: "978"},
+		},
+	}
+}
+
+func TestBackfillCreateJob(t *testing.T) {
+	ctx := context.Background()
+	service, _, _, mockJobs := setupBackfillService(DefaultBackfillOptions)
+
+	from := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2023, 1, 31, 0, 0, 0, 0, time.UTC)
+	mockJobs.On("CreateBackfillJob", ctx, mock.MatchedBy(func(job *entity.BackfillJob) bool {
+		return job.DaysTotal == 31 && job.NextDate.Equal(from) && job.Status == entity.BackfillPending && assert.Equal(t, []string{"USD"}, job.CharCodes)
+	})).Return(int64(3), nil)
+
+	job, err := service.CreateJob(ctx, from, to, []string{"usd"})
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), job.ID)
+	mockJobs.AssertExpectations(t)
+}
+
+func TestBackfillCreateJob_InvalidRange(t *testing.T) {
+	ctx := context.Background()
+	service, _, _, mockJobs := setupBackfillService(DefaultBackfillOptions)
+
+	_, err := service.CreateJob(ctx, time.Date(2023, 2, 1, 0, 0, 0, 0, time.UTC), time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC), nil)
+	assert.ErrorIs(t, err, ErrInvalidDateRange)
+
+	_, err = service.CreateJob(ctx, time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC), time.Date(2025, 9, 1, 0, 0, 0, 0, time.UTC), nil)
+	assert.ErrorIs(t, err, ErrFutureDate)
+
+	mockJobs.AssertNotCalled(t, "CreateBackfillJob", mock.Anything, mock.Anything)
+}
+
+func TestBackfillRun_ChunksAndFilters(t *testing.T) {
+	ctx := context.Background()
+	service, mockCbr, mockRepo, mockJobs := setupBackfillService(BackfillOptions{ChunkDays: 2, Concurrency: 2, RequestInterval: time.Millisecond})
+
+	from := time.Date(2023, 1, 10, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2023, 1, 12, 0, 0, 0, 0, time.UTC)
+	mockJobs.On("GetBackfillJob", ctx, int64(1)).Return(&entity.BackfillJob{
+		ID: 1, DateFrom: from, DateTo: to, CharCodes: []string{"USD"}, Status: entity.BackfillPending, NextDate: from, DaysTotal: 3,
+	}, nil)
+	mockJobs.On("UpdateBackfillJob", ctx, mock.Anything).Return(nil)
+
+	// one range per chunk: the daily list resolves the valute ID, XML_dynamic the rates
+	for _, chunk := range [][2]time.Time{{from, from.AddDate(0, 0, 1)}, {to, to}} {
+		mockCbr.On("FetchRates", ctx, chunk[1].Format("02/01/2006")).Return(dailyResponse(chunk[1]), nil).Once()
+		var records []cbr.Record
+		for d := chunk[0]; !d.After(chunk[1]); d = d.AddDate(0, 0, 1) {
+			records = append(records, cbr.Record{Date: d.Format("02.01.2006"), ID: "R01235", Nominal: 1, Value: "90,5"})
+		}
+		mockCbr.On("FetchDynamicRates", ctx, "R01235", chunk[0].Format("02/01/2006"), chunk[1].Format("02/01/2006")).
+			Return(&cbr.ValCursDynamic{ID: "R01235", Records: records}, nil).Once()
+		mockRepo.On("StoreRates", ctx, mock.MatchedBy(func(rates []entity.Currency) bool {
+			return len(rates) == len(records) && rates[0].CharCode == "USD" && rates[0].Date.Equal(chunk[0])
+		})).Return(nil).Once()
+	}
+
+	err := service.Run(ctx, 1)
+	require.NoError(t, err)
+
+	mockCbr.AssertExpectations(t)
+	mockRepo.AssertExpectations(t)
+
+	// running, after chunk 1, after chunk 2, completed
+	require.Len(t, mockJobs.updates, 4)
+	assert.Equal(t, entity.BackfillRunning, mockJobs.updates[0].Status)
+	assert.Equal(t, 2, mockJobs.updates[1].DaysDone)
+	assert.Equal(t, from.AddDate(0, 0, 2), mockJobs.updates[1].NextDate)
+	last := mockJobs.updates[3]
+	assert.Equal(t, entity.BackfillCompleted, last.Status)
+	assert.Equal(t, 3, last.DaysDone)
+	assert.NotNil(t, last.FinishedAt)
+}
+
+func TestBackfillRun_SkipsNonPublicationDays(t *testing.T) {
+	ctx := context.Background()
+	service, mockCbr, mockRepo, mockJobs := setupBackfillService(BackfillOptions{ChunkDays: 7, Concurrency: 2, RequestInterval: time.Millisecond})
+
+	// Saturday to Tuesday: CBR has no rates dated Sunday or Monday
+	from := time.Date(2023, 1, 14, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2023, 1, 17, 0, 0, 0, 0, time.UTC)
+	mockJobs.On("GetBackfillJob", ctx, int64(1)).Return(&entity.BackfillJob{
+		ID: 1, DateFrom: from, DateTo: to, Status: entity.BackfillPending, NextDate: from, DaysTotal: 4,
+	}, nil)
+	mockJobs.On("UpdateBackfillJob", ctx, mock.Anything).Return(nil)
+	for _, d := range []time.Time{from, to} {
+		mockCbr.On("FetchRates", ctx, d.Format("02/01/2006")).Return(dailyResponse(d), nil).Once()
+	}
+	mockRepo.On("StoreRates", ctx, mock.Anything).Return(nil).Twice()
+
+	require.NoError(t, service.Run(ctx, 1))
+
+	mockCbr.AssertExpectations(t)
+	mockCbr.AssertNumberOfCalls(t, "FetchRates", 2)
+	last := mockJobs.updates[len(mockJobs.updates)-1]
+	assert.Equal(t, entity.BackfillCompleted, last.Status)
+	assert.Equal(t, 4, last.DaysDone)
+}
+
+func TestBackfillRun_ResumesFromNextDate(t *testing.T) {
+	ctx := context.Background()
+	service, mockCbr, mockRepo, mockJobs := setupBackfillService(BackfillOptions{ChunkDays: 10, Concurrency: 1, RequestInterval: time.Millisecond})
+
+	from := time.Date(2023, 1, 10, 0, 0, 0, 0, time.UTC)
+	next := time.Date(2023, 1, 12, 0, 0, 0, 0, time.UTC)
+	mockJobs.On("GetBackfillJob", ctx, int64(1)).Return(&entity.BackfillJob{
+		ID: 1, DateFrom: from, DateTo: next, Status: entity.BackfillRunning, NextDate: next, DaysTotal: 3, DaysDone: 2,
+	}, nil)
+	mockJobs.On("UpdateBackfillJob", ctx, mock.Anything).Return(nil)
+	mockCbr.On("FetchRates", ctx, "12/01/2023").Return(dailyResponse(next), nil).Once()
+	mockRepo.On("StoreRates", ctx, mock.Anything).Return(nil).Once()
+
+	err := service.Run(ctx, 1)
+	require.NoError(t, err)
+
+	mockCbr.AssertExpectations(t)
+	last := mockJobs.updates[len(mockJobs.updates)-1]
+	assert.Equal(t, entity.BackfillCompleted, last.Status)
+	assert.Equal(t, 3, last.DaysDone)
+}
+
+func TestBackfillRun_FailureKeepsChunkStart(t *testing.T) {
+	ctx := context.Background()
+	service, mockCbr, _, mockJobs := setupBackfillService(BackfillOptions{ChunkDays: 5, Concurrency: 1, RequestInterval: time.Millisecond})
+
+	from := time.Date(2023, 1, 10, 0, 0, 0, 0, time.UTC)
+	mockJobs.On("GetBackfillJob", ctx, int64(1)).Return(&entity.BackfillJob{
+		ID: 1, DateFrom: from, DateTo: from, Status: entity.BackfillPending, NextDate: from, DaysTotal: 1,
+	}, nil)
+	mockJobs.On("UpdateBackfillJob", ctx, mock.Anything).Return(nil)
+	mockCbr.On("FetchRates", ctx, "10/01/2023").Return((*cbr.ValCurs)(nil), errors.New("timeout"))
+
+	err := service.Run(ctx, 1)
+	assert.ErrorIs(t, err, ErrUpstreamUnavailable)
+
+	last := mockJobs.updates[len(mockJobs.updates)-1]
+	assert.Equal(t, entity.BackfillFailed, last.Status)
+	assert.Equal(t, from, last.NextDate)
+	assert.Contains(t, last.Error, "timeout")
+}
+
+func TestBackfillRun_SkipsCompleted(t *testing.T) {
+	ctx := context.Background()
+	service, mockCbr, _, mockJobs := setupBackfillService(DefaultBackfillOptions)
+
+	mockJobs.On("GetBackfillJob", ctx, int64(1)).Return(&entity.BackfillJob{ID: 1, Status: entity.BackfillCompleted}, nil)
+
+	err := service.Run(ctx, 1)
+	require.NoError(t, err)
+	mockCbr.AssertNotCalled(t, "FetchRates", mock.Anything, mock.Anything)
+	mockJobs.AssertNotCalled(t, "UpdateBackfillJob", mock.Anything, mock.Anything)
+}
+
+func TestBackfillGetJob_NotFound(t *testing.T) {
+	ctx := context.Background()
+	service, _, _, mockJobs := setupBackfillService(DefaultBackfillOptions)
+
+	mockJobs.On("GetBackfillJob", ctx, int64(9)).Return(nil, postgres.ErrNotFound)
+
+	_, err := service.GetJob(ctx, 9)
+	assert.ErrorIs(t, err, ErrBackfillJobNotFound)
+}
+
+func TestBackfillStart_RejectsDuplicate(t *testing.T) {
+	service, _, _, _ := setupBackfillService(DefaultBackfillOptions)
+
+	claimed, err := service.claim(context.Background(), 5)
+	require.NoError(t, err)
+	require.True(t, claimed)
+	err = service.Start(5)
+	assert.ErrorIs(t, err, ErrBackfillJobRunning)
+}
+
+func TestBackfillRun_LockedByAnotherProcess(t *testing.T) {
+	ctx := context.Background()
+	locks := newFakeLocks()
+	service, mockCbr, _, mockJobs := setupBackfillService(DefaultBackfillOptions)
+	service.SetLocker(locks.newLock)
+	other, _, _, _ := setupBackfillService(DefaultBackfillOptions)
+	other.SetLocker(locks.newLock)
+
+	claimed, err := other.claim(ctx, 1)
+	require.NoError(t, err)
+	require.True(t, claimed)
+	assert.True(t, locks.isHeld("backfill:1"))
+
+	err = service.Run(ctx, 1)
+	assert.ErrorIs(t, err, ErrBackfillJobRunning)
+	mockJobs.AssertNotCalled(t, "GetBackfillJob", mock.Anything, mock.Anything)
+	mockCbr.AssertNotCalled(t, "FetchRates", mock.Anything, mock.Anything)
+
+	other.release(1)
+	assert.False(t, locks.isHeld("backfill:1"))
+}
+
+func TestBackfillRun_ReleasesLock(t *testing.T) {
+	ctx := context.Background()
+	locks := newFakeLocks()
+	service, _, _, mockJobs := setupBackfillService(DefaultBackfillOptions)
+	service.SetLocker(locks.newLock)
+
+	mockJobs.On("GetBackfillJob", ctx, int64(1)).Return(&entity.BackfillJob{ID: 1, Status: entity.BackfillCompleted}, nil)
+
+	require.NoError(t, service.Run(ctx, 1))
+	assert.False(t, locks.isHeld("backfill:1"))
+}
+
+func TestBackfillResumeUnfinished_SkipsJobsRunningElsewhere(t *testing.T) {
+	ctx := context.Background()
+	locks := newFakeLocks()
+	service, _, _, mockJobs := setupBackfillService(DefaultBackfillOptions)
+	service.SetLocker(locks.newLock)
+	_, err := locks.newLock("backfill:4").TryAcquire(ctx)
+	require.NoError(t, err)
+
+	mockJobs.On("ListUnfinishedBackfillJobs", ctx).Return([]entity.BackfillJob{{ID: 4}}, nil)
+
+	require.NoError(t, service.ResumeUnfinished(ctx))
+	service.Shutdown()
+	mockJobs.AssertNotCalled(t, "GetBackfillJob", mock.Anything, mock.Anything)
+}
+
+func TestBackfillResumeUnfinished(t *testing.T) {
+	ctx := context.Background()
+	service, _, _, mockJobs := setupBackfillService(DefaultBackfillOptions)
+
+	mockJobs.On("ListUnfinishedBackfillJobs", ctx).Return([]entity.BackfillJob{{ID: 4}}, nil)
+	mockJobs.On("GetBackfillJob", mock.Anything, int64(4)).Return(&entity.BackfillJob{ID: 4, Status: entity.BackfillCompleted}, nil)
+
+	err := service.ResumeUnfinished(ctx)
+	require.NoError(t, err)
+	service.Shutdown()
+	mockJobs.AssertExpectations(t)
+}
@@ -0,0 +1,128 @@
+package service
+
+import (
+	"RnD-service/internal/adapter/cbr"
+	"RnD-service/internal/adapter/postgres"
+	"RnD-service/internal/entity"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+func (r *RateService) SyncCurrencyCatalog(ctx context.Context) error {
+	logger := r.logger.WithContext(ctx)
+	logger.Info("Syncing currency catalog from CBR...")
+
+	fetchedAt := r.now()
+	var catalog []entity.CurrencyInfo
+	seen := make(map[string]bool)
+	// daily list first so an ID present in both keeps monthly=false
+	for _, monthly := range []bool{false, true} {
+		resp, err := r.cbr.FetchCurrencyCatalog(ctx, monthly)
+		if err != nil {
+			logger.Errorf("Failed to fetch currency catalog (monthly=%t) from CBR: %v", monthly, err)
+			return fmt.Errorf("fetch currency catalog: %w: %w", ErrUpstreamUnavailable, err)
+		}
+
+		for _, c := range convertCBRCatalog(*resp, monthly, fetchedAt) {
+			if seen[c.ID] {
+				continue
+			}
+			seen[c.ID] = true
+			catalog = append(catalog, c)
+		}
+	}
+
+	if len(catalog) == 0 {
+		logger.Warn("No currencies found in CBR catalog")
+		return errors.New("no currencies to store")
+	}
+
+	if err := r.dbRepo.StoreCurrencies(ctx, catalog); err != nil {
+		logger.Errorf("Failed to store currency catalog in DB: %v", err)
+		return fmt.Errorf("store currency catalog in DB: %w", err)
+	}
+
+	logger.Infof("Currency catalog successfully synced: %d currencies", len(catalog))
+	return nil
+}
+
+func (r *RateService) GetCurrencyCatalog(ctx context.Context) ([]entity.CurrencyInfo, error) {
+	logger := r.logger.WithContext(ctx)
+	catalog, err := r.dbRepo.GetCurrencies(ctx)
+	if err != nil {
+		logger.Errorf("Failed to get currency catalog: %v", err)
+		return nil, fmt.Errorf("get currency catalog: %w", err)
+	}
+	if len(catalog) > 0 {
+		return catalog, nil
+	}
+
+	logger.Info("Currency catalog is empty, loading it from CBR")
+	if err := r.SyncCurrencyCatalog(ctx); err != nil {
+		return nil, err
+	}
+
+	catalog, err = r.dbRepo.GetCurrencies(ctx)
+	if err != nil {
+		logger.Errorf("Failed to get currency catalog after sync: %v", err)
+		return nil, fmt.Errorf("get currency catalog: %w", err)
+	}
+	return catalog, nil
+}
+
+func (r *RateService) GetCurrencyByCharCode(ctx context.Context, charCode string) (*entity.CurrencyInfo, error) {
+	logger := r.logger.WithContext(ctx)
+	charCode = strings.ToUpper(charCode)
+
+	currency, err := r.dbRepo.GetCurrencyByCharCode(ctx, charCode)
+	if errors.Is(err, postgres.ErrNotFound) {
+		// an empty table means the catalog was never synced, not that the code is unknown
+		catalog, catalogErr := r.dbRepo.GetCurrencies(ctx)
+		if catalogErr != nil {
+			logger.Errorf("Failed to check currency catalog: %v", catalogErr)
+			return nil, fmt.Errorf("get currency catalog: %w", catalogErr)
+		}
+		if len(catalog) == 0 {
+			if syncErr := r.SyncCurrencyCatalog(ctx); syncErr != nil {
+				return nil, syncErr
+			}
+			currency, err = r.dbRepo.GetCurrencyByCharCode(ctx, charCode)
+		}
+	}
+	if err != nil {
+		if errors.Is(err, postgres.ErrNotFound) {
+			logger.Warnf("Currency %s is not in the CBR catalog", charCode)
+			return nil, fmt.Errorf("%w: %s", ErrUnknownCurrency, charCode)
+		}
+		logger.Errorf("Failed to get catalog currency %s: %v", charCode, err)
+		return nil, fmt.Errorf("get currency by char code: %w", err)
+	}
+
+	return currency, nil
+}
+
+func convertCBRCatalog(resp cbr.Valuta, monthly bool, fetchedAt time.Time) []entity.CurrencyInfo {
+	currencies := make([]entity.CurrencyInfo, 0, len(resp.Items))
+	for _, item := range resp.Items {
+		charCode := strings.ToUpper(strings.TrimSpace(item.ISOCharCode))
+		// legacy entries without an ISO code cannot be requested by char code
+		if charCode == "" {
+			continue
+		}
+		currencies = append(currencies, entity.CurrencyInfo{
+			ID:         strings.TrimSpace(item.ID),
+			CharCode:   charCode,
+			NumCode:    strings.TrimSpace(item.ISONumCode),
+			Name:       strings.TrimSpace(item.Name),
+			EngName:    strings.TrimSpace(item.EngName),
+			Nominal:    item.Nominal,
+			ParentCode: strings.TrimSpace(item.ParentCode),
+			Monthly:    monthly,
+			UpdatedAt:  fetchedAt,
+		})
+	}
+	return currencies
+}
@@ -6,239 +6,692 @@ package service
 import (
 	"RnD-service/internal/adapter/cbr"
 	"RnD-service/internal/adapter/postgres"
+	"RnD-service/internal/adapter/provider"
 	"RnD-service/internal/entity"
+	"RnD-service/pkg/tracing"
 	"context"
 	"errors"
 	"fmt"
+	"sort"
 	"strings"
 	"time"
 
 	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/multierr"
 )
 
 type RateService struct {
-	cbr    cbr.CbrClient
-	dbRepo postgres.PostgresRepository
-	logger *logrus.Logger
+	cbr       cbr.CbrClient
+	providers map[string]provider.RateProvider
+	fallbacks map[string][]provider.RateProvider
+	dbRepo    postgres.PostgresRepository
+	alerts    RateAlerter
+	observer  RateObserver
+	tracer    trace.Tracer
+	logger    *logrus.Logger
+	now       func() time.Time
 }
 
-func NewRateService(cbr cbr.CbrClient, dbRepo postgres.PostgresRepository, logger *logrus.Logger) *RateService {
+// NewRateService always registers CBR, built from the client that also serves
+// the currency catalog; providers adds further sources such as ECB.
+func NewRateService(cbr cbr.CbrClient, dbRepo postgres.PostgresRepository, logger *logrus.Logger, providers ...provider.RateProvider) *RateService {
+	registry := map[string]provider.RateProvider{
+		provider.SourceCBR: provider.NewCBRProvider(cbr, logger),
+	}
+	for _, p := range providers {
+		registry[p.Name()] = p
+	}
 	return &RateService{
-		cbr:    cbr,
-		dbRepo: dbRepo,
-		logger: logger,
+		cbr:       cbr,
+		providers: registry,
+		fallbacks: map[string][]provider.RateProvider{},
+		dbRepo:    dbRepo,
+		logger:    logger,
+		now:       time.Now,
 	}
 }
 
+// SetFallbacks sets, per source, the sources asked in order when the source
+// itself cannot be fetched. A fallback must quote against the same base currency.
+func (r *RateService) SetFallbacks(fallbacks map[string][]string) error {
+	chains := make(map[string][]provider.RateProvider, len(fallbacks))
+	for source, names := range fallbacks {
+		p, err := r.provider(source)
+		if err != nil {
+			return err
+		}
+		for _, name := range names {
+			fb, err := r.provider(name)
+			if err != nil {
+				return fmt.Errorf("fallback for %s: %w", p.Name(), err)
+			}
+			if fb.Name() == p.Name() {
+				return fmt.Errorf("fallback for %s: source cannot fall back to itself", p.Name())
+			}
+			if fb.BaseCurrency() != p.BaseCurrency() {
+				return fmt.Errorf("fallback for %s: %s quotes against %s, not %s", p.Name(), fb.Name(), fb.BaseCurrency(), p.BaseCurrency())
+			}
+			chains[p.Name()] = append(chains[p.Name()], fb)
+		}
+		r.logger.Infof("Rate source %s falls back to %v", p.Name(), names)
+	}
+	r.fallbacks = chains
+	return nil
+}
+
+// SetAlerts evaluates alert rules against rates stored from now on.
+func (r *RateService) SetAlerts(alerts RateAlerter) {
+	r.alerts = alerts
+}
+
+// SetObserver reports lookups, stores and syncs from now on, e.g. to metrics.
+func (r *RateService) SetObserver(observer RateObserver) {
+	r.observer = observer
+}
+
+// SeedObserver reports what is already stored, so the newest publication and
+// last sync of every source are known before the first sync after a restart.
+// The fetch time of the newest stored rate stands in for the last sync.
+func (r *RateService) SeedObserver(ctx context.Context) error {
+	if r.observer == nil {
+		return nil
+	}
+	logger := r.logger.WithContext(ctx)
+
+	summaries, err := r.dbRepo.GetLatestRateSummaries(ctx)
+	if err != nil {
+		logger.WithError(err).Error("Failed to get latest rate summaries")
+		return fmt.Errorf("get latest rate summaries: %w", err)
+	}
+	for _, s := range summaries {
+		r.observer.ObserveRatesStored(s.Source, s.LatestDate)
+		r.observer.ObserveRateSync(s.Source, s.FetchedAt)
+	}
+	logger.Infof("Seeded rate observer with %d sources", len(summaries))
+	return nil
+}
+
+// SetTracer enables spans for rate lookups, fetches and stores.
+func (r *RateService) SetTracer(tracer trace.Tracer) {
+	r.tracer = tracer
+}
+
+func (r *RateService) BaseCurrency(source string) (string, error) {
+	p, err := r.provider(source)
+	if err != nil {
+		return "", err
+	}
+	return p.BaseCurrency(), nil
+}
+
 func (r *RateService) StoreRatesFromCbr(ctx context.Context) error {
-	date := time.Now().Format("02/01/2006")
-	r.logger.Info("Fetching currency rates from CBR...")
+	return r.StoreRatesFromProvider(ctx, provider.SourceCBR)
+}
 
-	resp, err := r.cbr.FetchRates(ctx, date)
+func (r *RateService) StoreRatesFromProvider(ctx context.Context, source string) error {
+	ctx, span := tracing.Start(ctx, r.tracer, "RateService.StoreRatesFromProvider", attribute.String("rate.source", source))
+	defer span.End()
+	logger := r.logger.WithContext(ctx)
+
+	p, err := r.provider(source)
 	if err != nil {
-		r.logger.Errorf("Failed to fetch rates from CBR: %v", err)
-		return fmt.Errorf("fetch rates: %w", err)
+		return err
 	}
 
-	rates, err := convertCBRResponse(*resp)
+	date := r.now()
+	logger.Infof("Fetching currency rates from %s...", p.Name())
+
+	rates, err := r.fetchDaily(ctx, p, date)
 	if err != nil {
-		r.logger.Errorf("Failed to convert response: %v", err)
-		return fmt.Errorf("convert response: %w", err)
+		logger.Errorf("Failed to fetch rates from %s: %v", p.Name(), err)
+		tracing.RecordError(span, err)
+		return fmt.Errorf("fetch rates: %w: %w", ErrUpstreamUnavailable, err)
 	}
 
 	if len(rates) == 0 {
-		r.logger.Warn("No rates found in response")
+		logger.Warn("No rates found in response")
 		return errors.New("no rates to store")
 	}
+	rates = r.stamp(rates)
 
-	r.logger.Infof("Storing %d rates for date %s", len(rates), date)
+	logger.Infof("Storing %d %s rates for date %s", len(rates), rates[0].Source, date.Format("2006-01-02"))
 
-	if err := r.dbRepo.StoreRates(ctx, rates); err != nil {
-		r.logger.Errorf("Failed to store rates in DB: %v", err)
+	if err := r.storeRates(ctx, p, rates); err != nil {
+		logger.Errorf("Failed to store rates in DB: %v", err)
+		tracing.RecordError(span, err)
 		return fmt.Errorf("store rates in DB: %w", err)
 	}
 
-	r.logger.Info("Currency rates successfully stored.")
+	if r.observer != nil {
+		r.observer.ObserveRateSync(p.Name(), r.now())
+	}
+	logger.Info("Currency rates successfully stored.")
 	return nil
 }
 
-func (r *RateService) GetRateByCharCode(ctx context.Context, charCode string) (*entity.Currency, error) {
-	r.logger.Infof("Fetching currency by CharCode: %s", charCode)
+func (r *RateService) GetRateByCharCode(ctx context.Context, source, charCode string) (*entity.Currency, error) {
+	ctx, span := tracing.Start(ctx, r.tracer, "RateService.GetRateByCharCode", attribute.String("rate.source", source), attribute.String("rate.char_code", charCode))
+	defer span.End()
+	logger := r.logger.WithContext(ctx)
+
+	p, err := r.provider(source)
+	if err != nil {
+		return nil, err
+	}
+	logger.Infof("Fetching %s currency by CharCode: %s", p.Name(), charCode)
 
 	charCode = strings.ToUpper(charCode)
 
-	rate, err := r.dbRepo.GetRateByCharCode(ctx, charCode)
+	rate, err := r.dbRepo.GetRateByCharCode(ctx, p.Name(), charCode)
 	if err != nil {
-		r.logger.Errorf("Failed to get currency rate for %s: %v", charCode, err)
+		logger.Errorf("Failed to get currency rate for %s: %v", charCode, err)
+		tracing.RecordError(span, err)
+		if errors.Is(err, postgres.ErrNotFound) {
+			return nil, fmt.Errorf("%w: valute code %s", ErrRateNotFound, charCode)
+		}
 		return nil, fmt.Errorf("get rate by char code: %w", err)
 	}
 
 	if rate == nil {
-		r.logger.Warnf("No currency found for CharCode: %s", charCode)
-		return nil, fmt.Errorf("valute code %s not found", charCode)
+		logger.Warnf("No currency found for CharCode: %s", charCode)
+		return nil, fmt.Errorf("%w: valute code %s", ErrRateNotFound, charCode)
 	}
 
-	r.logger.Infof("Found rate for %s: %.4f", rate.CharCode, rate.Value)
+	logger.Infof("Found rate for %s: %s", rate.CharCode, rate.Value)
 	return rate, nil
 }
 
-func (r *RateService) GetRateByCharCodeAndDate(ctx context.Context, charCode string, date time.Time) (*entity.Currency, error) {
+// GetRateByCharCodeAndDate returns the rate in effect on date: Date is the
+// requested date and EffectiveDate the publication it comes from.
+func (r *RateService) GetRateByCharCodeAndDate(ctx context.Context, source, charCode string, date time.Time) (*entity.Currency, error) {
+	ctx, span := tracing.Start(ctx, r.tracer, "RateService.GetRateByCharCodeAndDate", attribute.String("rate.source", source), attribute.String("rate.char_code", charCode), attribute.String("rate.date", date.Format("2006-01-02")))
+	defer span.End()
+	logger := r.logger.WithContext(ctx)
+
+	p, err := r.provider(source)
+	if err != nil {
+		return nil, err
+	}
 	charCode = strings.ToUpper(charCode)
 
 	requestedDate := date.Truncate(24 * time.Hour)
+	today := r.now().Truncate(24 * time.Hour)
+	calendar := p.Calendar()
 
-	today := time.Now().Truncate(24 * time.Hour)
-	if requestedDate.After(today) {
-		r.logger.Warnf("Requested future date: %s", requestedDate.Format("2006-01-02"))
-		return nil, fmt.Errorf("cannot fetch rates for future dates")
+	// CBR sets rates on D for D+1, so tomorrow's may already be published
+	if calendar.PublishedOn(requestedDate).After(today) {
+		logger.Warnf("Requested future date: %s", requestedDate.Format("2006-01-02"))
+		return nil, ErrFutureDate
 	}
 
 	dateStr := requestedDate.Format("2006-01-02")
 
-	if requestedDate.Equal(today) {
-		r.logger.Info("Requested rate for today, fetching fresh data from CBR")
-		cbrDateStr := requestedDate.Format("02/01/2006")
-		r.logger.Infof("Fetching today's currency rates from CBR for date: %s", cbrDateStr)
-		resp, err := r.cbr.FetchRates(ctx, cbrDateStr)
-		if err != nil {
-			r.logger.Errorf("Failed to fetch today's rates from CBR: %v", err)
-			return nil, fmt.Errorf("fetch today's rates: %w", err)
-		}
-		rates, err := convertCBRResponse(*resp)
-		if err != nil {
-			r.logger.Errorf("Failed to convert historical response for date %s: %v", cbrDateStr, err)
-			return nil, fmt.Errorf("convert historical response: %w", err)
+	stored, err := r.dbRepo.GetRateByCharCodeAndDate(ctx, p.Name(), charCode, dateStr)
+	if err != nil {
+		if !errors.Is(err, postgres.ErrNotFound) {
+			logger.WithError(err).Warn("DB error querying historical rate, cannot proceed")
+			tracing.RecordError(span, err)
+			return nil, err
 		}
-		if len(rates) == 0 {
-			r.logger.Warnf("No rates found in historical response for date %s", cbrDateStr)
-			return nil, fmt.Errorf("no rates available from CBR for date %s", cbrDateStr)
+		logger.Debugf("Historical rate for %s on %s not found in DB, fetching from %s", charCode, dateStr, p.Name())
+	} else if inEffect(calendar, stored.Date, requestedDate) {
+		rate := asOf(*stored, requestedDate)
+		r.observeLookup(span, "date", true)
+		logger.Infof("Found rate for %s in effect on %s: %s (published for %s)", rate.CharCode, dateStr, rate.Value, rate.EffectiveDate.Format("2006-01-02"))
+		return &rate, nil
+	} else {
+		logger.Debugf("Stored rate for %s on %s may be superseded by %s, fetching from %s", charCode, stored.Date.Format("2006-01-02"), dateStr, p.Name())
+	}
+	r.observeLookup(span, "date", false)
+
+	logger.Infof("Fetching currency rates from %s for date: %s", p.Name(), dateStr)
+	rates, err := r.fetchDaily(ctx, p, requestedDate)
+	if err != nil {
+		logger.Errorf("Failed to fetch rates from %s for date %s: %v", p.Name(), dateStr, err)
+		tracing.RecordError(span, err)
+		return nil, fetchError(p, err)
+	}
+	if len(rates) == 0 {
+		logger.Warnf("No rates found in %s response for date %s", p.Name(), dateStr)
+		return nil, fmt.Errorf("%w: no rates available from %s for date %s", ErrRateNotFound, p.Name(), dateStr)
+	}
+	rates = r.stamp(rates)
+
+	effectiveDate := rates[0].Date
+	if effectiveDate.After(requestedDate) {
+		logger.Errorf("%s returned rates for %s after requested %s", p.Name(), effectiveDate.Format("2006-01-02"), dateStr)
+		return nil, &UpstreamDateError{Requested: requestedDate, Returned: effectiveDate}
+	}
+	if requestedDate.After(today) && !effectiveDate.Equal(requestedDate) {
+		logger.Warnf("%s has not published rates for %s yet", p.Name(), dateStr)
+		return nil, fmt.Errorf("%w: %s has not published rates for %s yet", ErrFutureDate, p.Name(), dateStr)
+	}
+	if !effectiveDate.Equal(requestedDate) {
+		logger.Infof("%s rates in effect on %s were published for %s", p.Name(), dateStr, effectiveDate.Format("2006-01-02"))
+	}
+
+	// only the publication is stored; days without one resolve to it on read
+	if err := r.storeRates(ctx, p, rates); err != nil {
+		logger.Errorf("Failed to store historical rates in DB for date %s: %v", effectiveDate.Format("2006-01-02"), err)
+	}
+
+	for _, rate := range rates {
+		if rate.CharCode == charCode {
+			rate = asOf(rate, requestedDate)
+			logger.Infof("Found rate for %s in effect on %s: %s (published for %s)", rate.CharCode, dateStr, rate.Value, rate.EffectiveDate.Format("2006-01-02"))
+			return &rate, nil
 		}
-		if err := r.dbRepo.StoreHistoricalRates(ctx, requestedDate, rates); err != nil {
-			r.logger.Errorf("Failed to store today's rates in historical DB: %v", err)
+	}
+	logger.Warnf("Currency code %s not found in %s rates for date %s", charCode, p.Name(), dateStr)
+	return nil, fmt.Errorf("%w: currency code %s for date %s", ErrRateNotFound, charCode, dateStr)
+}
+
+// GetRatesByCharCodesAndDate resolves several codes for one date with a single
+// repository query; the source is asked at most once, when a code has no rate
+// in effect in the DB. Codes the source does not quote are left out.
+func (r *RateService) GetRatesByCharCodesAndDate(ctx context.Context, source string, charCodes []string, date time.Time) (map[string]entity.Currency, error) {
+	ctx, span := tracing.Start(ctx, r.tracer, "RateService.GetRatesByCharCodesAndDate", attribute.String("rate.source", source), attribute.StringSlice("rate.char_codes", charCodes), attribute.String("rate.date", date.Format("2006-01-02")))
+	defer span.End()
+	logger := r.logger.WithContext(ctx)
+
+	p, err := r.provider(source)
+	if err != nil {
+		return nil, err
+	}
+
+	requestedDate := date.Truncate(24 * time.Hour)
+	today := r.now().Truncate(24 * time.Hour)
+	calendar := p.Calendar()
+
+	if calendar.PublishedOn(requestedDate).After(today) {
+		logger.Warnf("Requested future date: %s", requestedDate.Format("2006-01-02"))
+		return nil, ErrFutureDate
+	}
+
+	dateStr := requestedDate.Format("2006-01-02")
+	codes := make([]string, 0, len(charCodes))
+	for _, code := range charCodes {
+		codes = append(codes, strings.ToUpper(code))
+	}
+
+	stored, err := r.dbRepo.GetRatesByCharCodesAndDate(ctx, p.Name(), codes, dateStr)
+	if err != nil {
+		logger.WithError(err).Warn("DB error querying historical rates, cannot proceed")
+		tracing.RecordError(span, err)
+		return nil, err
+	}
+
+	result := make(map[string]entity.Currency, len(codes))
+	for _, rate := range stored {
+		if inEffect(calendar, rate.Date, requestedDate) {
+			result[rate.CharCode] = asOf(rate, requestedDate)
 		}
+	}
+	if len(result) == len(codes) {
+		r.observeLookup(span, "batch", true)
+		logger.Infof("Found %d rates in effect on %s in DB", len(result), dateStr)
+		return result, nil
+	}
+	r.observeLookup(span, "batch", false)
 
-		for _, rate := range rates {
-			if rate.CharCode == charCode {
-				r.logger.Infof("Found today's rate for %s: %.4f", rate.CharCode, rate.Value)
-				return &rate, nil
-			}
+	logger.Infof("Fetching currency rates from %s for date: %s, %d of %d codes missing in DB", p.Name(), dateStr, len(codes)-len(result), len(codes))
+	rates, err := r.fetchDaily(ctx, p, requestedDate)
+	if err != nil {
+		logger.Errorf("Failed to fetch rates from %s for date %s: %v", p.Name(), dateStr, err)
+		tracing.RecordError(span, err)
+		return nil, fetchError(p, err)
+	}
+	if len(rates) == 0 {
+		logger.Warnf("No rates found in %s response for date %s", p.Name(), dateStr)
+		return nil, fmt.Errorf("%w: no rates available from %s for date %s", ErrRateNotFound, p.Name(), dateStr)
+	}
+	rates = r.stamp(rates)
+
+	effectiveDate := rates[0].Date
+	if effectiveDate.After(requestedDate) {
+		logger.Errorf("%s returned rates for %s after requested %s", p.Name(), effectiveDate.Format("2006-01-02"), dateStr)
+		return nil, &UpstreamDateError{Requested: requestedDate, Returned: effectiveDate}
+	}
+	if requestedDate.After(today) && !effectiveDate.Equal(requestedDate) {
+		logger.Warnf("%s has not published rates for %s yet", p.Name(), dateStr)
+		return nil, fmt.Errorf("%w: %s has not published rates for %s yet", ErrFutureDate, p.Name(), dateStr)
+	}
+
+	if err := r.storeRates(ctx, p, rates); err != nil {
+		logger.Errorf("Failed to store historical rates in DB for date %s: %v", effectiveDate.Format("2006-01-02"), err)
+	}
+
+	wanted := make(map[string]bool, len(codes))
+	for _, code := range codes {
+		wanted[code] = true
+	}
+	for _, rate := range rates {
+		if wanted[rate.CharCode] {
+			result[rate.CharCode] = asOf(rate, requestedDate)
 		}
-		r.logger.Warnf("Currency code %s not found in today's rates", charCode)
-		return nil, fmt.Errorf("currency code %s not found for today", charCode)
+	}
 
-	} else if requestedDate.Before(today) {
-		r.logger.Infof("Requested rate for past date: %s", dateStr)
+	logger.Infof("Resolved %d of %d rates in effect on %s", len(result), len(codes), dateStr)
+	return result, nil
+}
 
-		rate, err := r.dbRepo.GetRateByCharCodeAndDate(ctx, charCode, dateStr)
-		if err != nil {
-			if errors.Is(err, postgres.ErrNotFound) {
-				r.logger.Debugf("Historical rate for %s on %s not found in DB, fetching from CBR", charCode, dateStr)
-			} else {
-				r.logger.WithError(err).Warn("DB error querying historical rate, cannot proceed")
-				return nil, err
-			}
-		} else {
-			r.logger.Infof("Found historical rate for %s on %s: %.4f", rate.CharCode, rate.Date, rate.Value)
-			return rate, nil
+func (r *RateService) GetRatesByCharCodeAndDateRange(ctx context.Context, source, charCode string, dateFrom, dateTo time.Time) ([]entity.Currency, error) {
+	ctx, span := tracing.Start(ctx, r.tracer, "RateService.GetRatesByCharCodeAndDateRange", attribute.String("rate.source", source), attribute.String("rate.char_code", charCode), attribute.String("rate.from", dateFrom.Format("2006-01-02")), attribute.String("rate.to", dateTo.Format("2006-01-02")))
+	defer span.End()
+	logger := r.logger.WithContext(ctx)
+
+	p, err := r.provider(source)
+	if err != nil {
+		return nil, err
+	}
+	charCode = strings.ToUpper(charCode)
+
+	from := dateFrom.Truncate(24 * time.Hour)
+	to := dateTo.Truncate(24 * time.Hour)
+
+	if from.After(to) {
+		logger.Warnf("Invalid date range: %s > %s", from.Format("2006-01-02"), to.Format("2006-01-02"))
+		return nil, fmt.Errorf("%w: 'from' is after 'to'", ErrInvalidDateRange)
+	}
+
+	today := r.now().Truncate(24 * time.Hour)
+	if to.After(today) {
+		logger.Warnf("Requested future date: %s", to.Format("2006-01-02"))
+		return nil, ErrFutureDate
+	}
+
+	fromStr := from.Format("2006-01-02")
+	toStr := to.Format("2006-01-02")
+
+	cached, err := r.dbRepo.GetRatesByCharCodeAndDateRange(ctx, p.Name(), charCode, fromStr, toStr)
+	if err != nil {
+		logger.WithError(err).Warn("DB error querying historical rates range, cannot proceed")
+		tracing.RecordError(span, err)
+		return nil, err
+	}
+
+	byDate := make(map[string]entity.Currency, len(cached))
+	for _, rate := range cached {
+		byDate[rate.Date.Format("2006-01-02")] = rate
+	}
+
+	// only days the provider publishes on can be missing
+	calendar := p.Calendar()
+	var missingDays []time.Time
+	for d := from; !d.After(to); d = d.AddDate(0, 0, 1) {
+		if !calendar.IsPublicationDay(d) {
+			continue
+		}
+		if _, ok := byDate[d.Format("2006-01-02")]; ok {
+			continue
 		}
+		missingDays = append(missingDays, d)
+	}
 
-		cbrDateStr := requestedDate.Format("02/01/2006")
-		r.logger.Infof("Fetching historical currency rates from CBR for date: %s", cbrDateStr)
-		resp, err := r.cbr.FetchRates(ctx, cbrDateStr)
+	// days the source already answered without a rate, e.g. holidays the
+	// calendar does not know, are not asked again
+	gaps := make(map[string]bool)
+	if len(missingDays) > 0 {
+		known, err := r.dbRepo.GetRateGaps(ctx, p.Name(), charCode, fromStr, toStr)
 		if err != nil {
-			r.logger.Errorf("Failed to fetch historical rates from CBR for date %s: %v", cbrDateStr, err)
-			return nil, fmt.Errorf("fetch historical rates from CBR: %w", err)
+			logger.WithError(err).Warn("Failed to get known rate gaps, fetching every missing day")
 		}
-		rates, err := convertCBRResponse(*resp)
-		if err != nil {
-			r.logger.Errorf("Failed to convert historical response for date %s: %v", cbrDateStr, err)
-			return nil, fmt.Errorf("convert historical response: %w", err)
+		for _, d := range known {
+			gaps[d.Format("2006-01-02")] = true
+		}
+	}
+
+	var firstMissing, lastMissing time.Time
+	for _, d := range missingDays {
+		if gaps[d.Format("2006-01-02")] {
+			continue
 		}
-		if len(rates) == 0 {
-			r.logger.Warnf("No rates found in historical response for date %s", cbrDateStr)
-			return nil, fmt.Errorf("no rates available from CBR for date %s", cbrDateStr)
+		if firstMissing.IsZero() {
+			firstMissing = d
 		}
+		lastMissing = d
+	}
+
+	if firstMissing.IsZero() {
+		r.observeLookup(span, "range", true)
+		logger.Infof("All %d historical rates for %s between %s and %s found in DB", len(cached), charCode, fromStr, toStr)
+		return cached, nil
+	}
+	r.observeLookup(span, "range", false)
 
-		respDate := rates[0].Date
-		if !respDate.Equal(requestedDate) {
-			r.logger.Warnf("CBR вернул курсы за %s вместо запрошенной %s (возможно, не торговый день)", respDate.Format("2006-01-02"), requestedDate.Format("2006-01-02"))
+	logger.Infof("Historical rates for %s missing between %s and %s, fetching from %s", charCode, firstMissing.Format("2006-01-02"), lastMissing.Format("2006-01-02"), p.Name())
+
+	fetched, err := r.fetchRange(ctx, p, charCode, firstMissing, lastMissing)
+	if err != nil {
+		logger.Errorf("Failed to fetch %s rates from %s: %v", charCode, p.Name(), err)
+		tracing.RecordError(span, err)
+		if errors.Is(err, provider.ErrCurrencyNotQuoted) {
+			return nil, fmt.Errorf("%w: %w", ErrRateNotFound, err)
 		}
+		return nil, fetchError(p, err)
+	}
+	fetched = r.stamp(fetched)
 
-		if err := r.dbRepo.StoreHistoricalRates(ctx, requestedDate, rates); err != nil {
-			r.logger.Errorf("Failed to store historical rates in DB for date %s: %v", dateStr, err)
+	missing := make([]entity.Currency, 0, len(fetched))
+	for _, rate := range fetched {
+		key := rate.Date.Format("2006-01-02")
+		if _, ok := byDate[key]; ok {
+			continue
+		}
+		missing = append(missing, rate)
+		byDate[key] = rate
+	}
+	if len(missing) > 0 {
+		if err := r.storeRates(ctx, p, missing); err != nil {
+			logger.Errorf("Failed to store historical rates in DB between %s and %s: %v", firstMissing.Format("2006-01-02"), lastMissing.Format("2006-01-02"), err)
 		}
+	}
 
-		for _, rate := range rates {
-			if rate.CharCode == charCode {
-				r.logger.Infof("Found historical rate for %s on %s: %.4f", rate.CharCode, rate.Date, rate.Value)
-				return &rate, nil
-			}
+	// a day published before today that the answer has no rate for will not get one
+	var newGaps []time.Time
+	for _, d := range missingDays {
+		key := d.Format("2006-01-02")
+		if d.Before(firstMissing) || d.After(lastMissing) || gaps[key] || !calendar.PublishedOn(d).Before(today) {
+			continue
+		}
+		if _, ok := byDate[key]; !ok {
+			newGaps = append(newGaps, d)
+		}
+	}
+	if len(newGaps) > 0 {
+		logger.Infof("%s has no %s rates for %d publication days between %s and %s", p.Name(), charCode, len(newGaps), firstMissing.Format("2006-01-02"), lastMissing.Format("2006-01-02"))
+		if err := r.dbRepo.StoreRateGaps(ctx, p.Name(), charCode, newGaps, r.now()); err != nil {
+			logger.WithError(err).Warn("Failed to store rate gaps")
 		}
-		r.logger.Warnf("Currency code %s not found in historical rates for date %s", charCode, dateStr)
-		return nil, fmt.Errorf("currency code %s not found for date %s", charCode, dateStr)
+	}
 
-	} else {
-		r.logger.Warnf("Requested rate for future date: %s", dateStr)
-		return nil, fmt.Errorf("cannot fetch rates for future dates")
+	result := make([]entity.Currency, 0, len(byDate))
+	for _, rate := range byDate {
+		result = append(result, rate)
 	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Date.Before(result[j].Date)
+	})
+
+	logger.Infof("Returning %d historical rates for %s between %s and %s (%d fetched from %s)", len(result), charCode, fromStr, toStr, len(fetched), p.Name())
+	return result, nil
 }
 
-func convertCBRResponse(resp cbr.ValCurs) ([]entity.Currency, error) {
-	var result []entity.Currency
-	var errs []error
+// GetRateStats first fills the range through GetRatesByCharCodeAndDateRange, so
+// days missing in the DB are fetched and stored, then aggregates it in the DB.
+func (r *RateService) GetRateStats(ctx context.Context, source, charCode string, dateFrom, dateTo time.Time) (*entity.RateStats, error) {
+	ctx, span := tracing.Start(ctx, r.tracer, "RateService.GetRateStats", attribute.String("rate.source", source), attribute.String("rate.char_code", charCode), attribute.String("rate.from", dateFrom.Format("2006-01-02")), attribute.String("rate.to", dateTo.Format("2006-01-02")))
+	defer span.End()
+	logger := r.logger.WithContext(ctx)
 
-	if len(resp.Valutes) == 0 {
-		logrus.Warn("No valutes found in response")
-		return result, nil
+	p, err := r.provider(source)
+	if err != nil {
+		return nil, err
 	}
+	charCode = strings.ToUpper(charCode)
 
-	var respDate time.Time
-	if resp.Date != "" {
-		var err error
-		respDate, err = time.Parse("02.01.2006", resp.Date)
-		if err != nil {
-			return nil, fmt.Errorf("failed to parse CBR response date '%s': %w", resp.Date, err)
+	if _, err := r.GetRatesByCharCodeAndDateRange(ctx, source, charCode, dateFrom, dateTo); err != nil {
+		return nil, err
+	}
+
+	fromStr := dateFrom.Truncate(24 * time.Hour).Format("2006-01-02")
+	toStr := dateTo.Truncate(24 * time.Hour).Format("2006-01-02")
+	stats, err := r.dbRepo.GetRateStats(ctx, p.Name(), charCode, fromStr, toStr)
+	if err != nil {
+		if errors.Is(err, postgres.ErrNotFound) {
+			logger.Warnf("No %s rates for %s between %s and %s to compute statistics", p.Name(), charCode, fromStr, toStr)
+			return nil, fmt.Errorf("%w: no %s rates for %s between %s and %s", ErrRateNotFound, p.Name(), charCode, fromStr, toStr)
 		}
-	} else {
-		respDate = time.Now().Truncate(24 * time.Hour)
-		logrus.Warn("No date in CBR response, using current date")
+		logger.WithError(err).Errorf("Failed to compute rate statistics for %s between %s and %s", charCode, fromStr, toStr)
+		tracing.RecordError(span, err)
+		return nil, err
 	}
 
-	skipped := 0
-	for _, valute := range resp.Valutes {
-		value, err := valute.GetValue()
-		if err != nil {
-			logrus.Debugf("Skipped %s due to parse error: %v", valute.CharCode, err)
-			skipped++
-			continue
+	logger.Infof("Computed %s rate statistics for %s over %d publications between %s and %s", p.Name(), charCode, stats.Count, fromStr, toStr)
+	return stats, nil
+}
+
+// fetchDaily asks p and then its fallbacks in order; the rates keep the Source
+// of the provider that answered.
+func (r *RateService) fetchDaily(ctx context.Context, p provider.RateProvider, date time.Time) ([]entity.Currency, error) {
+	ctx, span := tracing.Start(ctx, r.tracer, "RateService.fetchDaily", attribute.String("rate.source", p.Name()), attribute.String("rate.date", date.Format("2006-01-02")))
+	defer span.End()
+	logger := r.logger.WithContext(ctx)
+
+	rates, err := p.FetchDaily(ctx, date)
+	if err == nil {
+		return rates, nil
+	}
+
+	for _, fb := range r.fallbacks[p.Name()] {
+		logger.Warnf("Failed to fetch rates from %s, falling back to %s: %v", p.Name(), fb.Name(), err)
+		fbRates, fbErr := fb.FetchDaily(ctx, date)
+		if fbErr == nil {
+			span.SetAttributes(attribute.String("rate.fallback", fb.Name()))
+			return fbRates, nil
 		}
-		if value == 0 {
-			logrus.Debugf("Skipped %s due to zero value", valute.CharCode)
-			skipped++
-			continue
+		err = multierr.Append(err, fbErr)
+	}
+	tracing.RecordError(span, err)
+	return nil, err
+}
+
+// fetchRange falls back like fetchDaily, except when p answered that it does
+// not quote the currency.
+func (r *RateService) fetchRange(ctx context.Context, p provider.RateProvider, charCode string, from, to time.Time) ([]entity.Currency, error) {
+	ctx, span := tracing.Start(ctx, r.tracer, "RateService.fetchRange", attribute.String("rate.source", p.Name()), attribute.String("rate.char_code", charCode), attribute.String("rate.from", from.Format("2006-01-02")), attribute.String("rate.to", to.Format("2006-01-02")))
+	defer span.End()
+	logger := r.logger.WithContext(ctx)
+
+	rates, err := p.FetchRange(ctx, charCode, from, to)
+	if err == nil || errors.Is(err, provider.ErrCurrencyNotQuoted) {
+		return rates, err
+	}
+
+	for _, fb := range r.fallbacks[p.Name()] {
+		logger.Warnf("Failed to fetch %s rates from %s, falling back to %s: %v", charCode, p.Name(), fb.Name(), err)
+		fbRates, fbErr := fb.FetchRange(ctx, charCode, from, to)
+		if fbErr == nil {
+			span.SetAttributes(attribute.String("rate.fallback", fb.Name()))
+			return fbRates, nil
 		}
+		err = multierr.Append(err, fbErr)
+	}
+	tracing.RecordError(span, err)
+	return nil, err
+}
+
+// fetchError wraps a failed lookup fetch from p: CBR answering without data
+// means there is no rate to find, any other failure that p is unavailable.
+func fetchError(p provider.RateProvider, err error) error {
+	if errors.Is(err, cbr.ErrNoData) {
+		return fmt.Errorf("%w: %s has no data: %w", ErrRateNotFound, p.Name(), err)
+	}
+	return fmt.Errorf("fetch rates from %s: %w: %w", p.Name(), ErrUpstreamUnavailable, err)
+}
 
-		rate := entity.Currency{
-			CharCode:  valute.CharCode,
-			Name:      valute.Name,
-			Nominal:   valute.Nominal,
-			Value:     value,
-			NumCode:   valute.NumCode,
-			UpdatedAt: time.Now(),
-			Date:      respDate,
+// provider resolves a source name; an empty source means CBR.
+func (r *RateService) provider(source string) (provider.RateProvider, error) {
+	if source == "" {
+		source = provider.SourceCBR
+	}
+	p, ok := r.providers[strings.ToLower(source)]
+	if !ok {
+		r.logger.Warnf("Unknown rate source: %s", source)
+		return nil, fmt.Errorf("%w: %s", ErrUnknownSource, source)
+	}
+	return p, nil
+}
+
+// inEffect reports whether a rate stored for storedDate still holds on date,
+// that is no publication is due after storedDate up to date.
+func inEffect(calendar provider.Calendar, storedDate, date time.Time) bool {
+	for d := storedDate.AddDate(0, 0, 1); !d.After(date); d = d.AddDate(0, 0, 1) {
+		if calendar.IsPublicationDay(d) {
+			return false
 		}
-		result = append(result, rate)
 	}
+	return true
+}
+
+// asOf dates a stored or fetched rate with the date it is requested for.
+func asOf(rate entity.Currency, date time.Time) entity.Currency {
+	if rate.EffectiveDate.IsZero() {
+		rate.EffectiveDate = rate.Date
+	}
+	rate.Date = date
+	return rate
+}
 
-	logrus.Infof("Converted %d valid rates out of %d (skipped %d)", len(result), len(resp.Valutes), skipped)
+// storeRates reports the newest stored publication per source and evaluates
+// alert rules once the rates are stored. Rules are those of the requested
+// source p, also when a fallback answered for it. An alert that cannot be
+// evaluated is logged and does not fail the store.
+func (r *RateService) storeRates(ctx context.Context, p provider.RateProvider, rates []entity.Currency) error {
+	ctx, span := tracing.Start(ctx, r.tracer, "RateService.storeRates", attribute.Int("rate.count", len(rates)))
+	defer span.End()
+
+	if err := r.dbRepo.StoreRates(ctx, rates); err != nil {
+		tracing.RecordError(span, err)
+		return err
+	}
+	if r.observer != nil {
+		latest := map[string]time.Time{}
+		for _, rate := range rates {
+			if rate.Date.After(latest[rate.Source]) {
+				latest[rate.Source] = rate.Date
+			}
+		}
+		for source, date := range latest {
+			r.observer.ObserveRatesStored(source, date)
+		}
+	}
+	if r.alerts != nil && len(rates) > 0 {
+		evaluated := make([]entity.Currency, len(rates))
+		for i, rate := range rates {
+			rate.Source = p.Name()
+			evaluated[i] = rate
+		}
+		if err := r.alerts.Evaluate(ctx, evaluated); err != nil {
+			r.logger.WithContext(ctx).WithError(err).Error("Failed to evaluate rate alerts")
+		}
+	}
+	return nil
+}
 
-	if len(result) == 0 && len(resp.Valutes) > 0 {
-		errs = append(errs, fmt.Errorf("all %d valutes were skipped", len(resp.Valutes)))
+func (r *RateService) observeLookup(span trace.Span, lookup string, hit bool) {
+	span.SetAttributes(attribute.Bool("rate.cache_hit", hit))
+	if r.observer != nil {
+		r.observer.ObserveRateLookup(lookup, hit)
 	}
+}
 
-	if len(errs) > 0 {
-		return result, multierr.Combine(errs...)
+func (r *RateService) stamp(rates []entity.Currency) []entity.Currency {
+	fetchedAt := r.now()
+	for i := range rates {
+		rates[i].UpdatedAt = fetchedAt
 	}
-	return result, nil
+	return rates
 }
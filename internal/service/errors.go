@@ -0,0 +1,40 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+var (
+	ErrFutureDate           = errors.New("cannot fetch rates for future dates")
+	ErrInvalidDateRange     = errors.New("invalid date range")
+	ErrRateNotFound         = errors.New("rate not found")
+	ErrUnknownCurrency      = errors.New("unknown currency")
+	ErrUnknownMetal         = errors.New("unknown metal")
+	ErrUnknownSource        = errors.New("unknown rate source")
+	ErrUpstreamUnavailable  = errors.New("upstream is unavailable")
+	ErrUpstreamDateMismatch = errors.New("upstream returned rates for a different date")
+	ErrNoCommonCurrency     = errors.New("rate sources share no currency to compare in")
+	ErrBackfillJobNotFound  = errors.New("backfill job not found")
+	ErrBackfillJobRunning   = errors.New("backfill job is already running")
+	ErrInvalidAlertRule     = errors.New("invalid alert rule")
+	ErrAlertRuleNotFound    = errors.New("alert rule not found")
+	ErrJobNotFound          = errors.New("job not found")
+	ErrJobRunning           = errors.New("job is already running")
+)
+
+// UpstreamDateError is returned when a provider answers with a publication that
+// cannot belong to the requested date.
+type UpstreamDateError struct {
+	Requested time.Time
+	Returned  time.Time
+}
+
+func (e *UpstreamDateError) Error() string {
+	return fmt.Sprintf("%s: requested %s, got %s", ErrUpstreamDateMismatch, e.Requested.Format("2006-01-02"), e.Returned.Format("2006-01-02"))
+}
+
+func (e *UpstreamDateError) Unwrap() error {
+	return ErrUpstreamDateMismatch
+}
@@ -0,0 +1,179 @@
+package service
+
+import (
+	"RnD-service/internal/adapter/postgres"
+	"RnD-service/internal/adapter/provider"
+	"RnD-service/internal/entity"
+	"context"
+	"errors"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// healthCheckTimeout bounds every check so a hung database fails the probe
+// instead of stalling it.
+const healthCheckTimeout = 3 * time.Second
+
+type HealthService struct {
+	repo     postgres.HealthRepository
+	schema   SchemaChecker
+	source   string
+	calendar provider.Calendar
+	maxLag   time.Duration
+	logger   *logrus.Logger
+	now      func() time.Time
+
+	syncJobs JobReporter
+	syncJob  string
+}
+
+// NewHealthService checks the freshness of the rates of source, published on
+// calendar; they are stale once they lag the expected publication by more than maxLag.
+func NewHealthService(repo postgres.HealthRepository, schema SchemaChecker, source string, calendar provider.Calendar, maxLag time.Duration, logger *logrus.Logger) *HealthService {
+	return &HealthService{
+		repo:     repo,
+		schema:   schema,
+		source:   source,
+		calendar: calendar,
+		maxLag:   maxLag,
+		logger:   logger,
+		now:      time.Now,
+	}
+}
+
+// SetSyncJob reports the runs of the scheduled job name as the rate sync.
+func (s *HealthService) SetSyncJob(jobs JobReporter, name string) {
+	s.syncJobs = jobs
+	s.syncJob = name
+}
+
+func (s *HealthService) Check(ctx context.Context) *entity.HealthReport {
+	ctx, cancel := context.WithTimeout(ctx, healthCheckTimeout)
+	defer cancel()
+
+	report := &entity.HealthReport{CheckedAt: s.now()}
+	report.Database = s.checkDatabase(ctx)
+	if report.Database.OK {
+		report.Schema = s.checkSchema(ctx)
+		report.Rates = s.checkRates(ctx, report.CheckedAt)
+	} else {
+		report.Schema.Error = "database unavailable"
+		report.Rates = entity.RateFreshness{Source: s.source, MaxLag: s.maxLag, Error: "database unavailable"}
+	}
+	report.Sync = s.syncStatus(ctx)
+	if report.Rates.Stale && report.Rates.Error == "" && s.syncConfirmed(report.Sync, report.Rates.ExpectedDate) {
+		// the source answered after the expected publication without a newer
+		// rate, so the gap is a day off the calendar does not know about
+		report.Rates.Stale = false
+	}
+	report.Ready = report.Database.OK && report.Schema.OK && report.Rates.Error == "" && !report.Rates.Stale
+
+	if !report.Ready {
+		s.logger.WithFields(logrus.Fields{
+			"database": report.Database.Error,
+			"schema":   report.Schema.Error,
+			"rates":    report.Rates.Error,
+			"stale":    report.Rates.Stale,
+		}).Warn("Service is not ready")
+	}
+	return report
+}
+
+func (s *HealthService) checkDatabase(ctx context.Context) entity.DatabaseHealth {
+	start := time.Now()
+	err := s.repo.Ping(ctx)
+	health := entity.DatabaseHealth{OK: err == nil, Latency: time.Since(start)}
+	if err != nil {
+		health.Error = err.Error()
+	}
+	return health
+}
+
+func (s *HealthService) checkSchema(ctx context.Context) entity.SchemaHealth {
+	status, err := s.schema.Status(ctx)
+	if err != nil {
+		s.logger.WithError(err).Error("Failed to get schema migration status")
+		return entity.SchemaHealth{Error: err.Error()}
+	}
+
+	health := entity.SchemaHealth{Version: status.Version, Latest: status.Latest, Dirty: status.Dirty}
+	switch {
+	case status.Dirty:
+		health.Error = "schema is dirty"
+	case status.Version != status.Latest:
+		health.Error = "schema is not at the latest migration"
+	default:
+		health.OK = true
+	}
+	return health
+}
+
+func (s *HealthService) checkRates(ctx context.Context, now time.Time) entity.RateFreshness {
+	freshness := entity.RateFreshness{
+		Source:       s.source,
+		ExpectedDate: expectedRateDate(s.calendar, now),
+		MaxLag:       s.maxLag,
+	}
+
+	latest, err := s.repo.GetLatestRateDate(ctx, s.source)
+	if err != nil {
+		if errors.Is(err, postgres.ErrNotFound) {
+			freshness.Error = "no rates stored"
+		} else {
+			s.logger.WithError(err).Errorf("Failed to get latest %s rate date", s.source)
+			freshness.Error = err.Error()
+		}
+		freshness.Stale = true
+		return freshness
+	}
+
+	freshness.LatestDate = latest
+	if lag := freshness.ExpectedDate.Sub(latest); lag > 0 {
+		freshness.Lag = lag
+	}
+	freshness.Stale = freshness.Lag > s.maxLag
+	return freshness
+}
+
+// syncStatus reads job_runs rather than this replica's memory, so every
+// replica reports the sync run by the scheduler leader.
+func (s *HealthService) syncStatus(ctx context.Context) entity.SyncStatus {
+	var status entity.SyncStatus
+	if s.syncJobs == nil {
+		return status
+	}
+
+	status.NextAt = s.syncJobs.NextRun(s.syncJob)
+	run, err := s.syncJobs.LastFinishedRun(ctx, s.syncJob)
+	if err != nil {
+		s.logger.WithContext(ctx).WithError(err).Warnf("Failed to read last run of %s", s.syncJob)
+		return status
+	}
+	if run != nil && run.FinishedAt != nil {
+		status.LastAt = *run.FinishedAt
+		status.LastError = run.Error
+	}
+	return status
+}
+
+// syncConfirmed reports whether the last sync succeeded once the rates for
+// expected were due.
+func (s *HealthService) syncConfirmed(sync entity.SyncStatus, expected time.Time) bool {
+	if sync.LastAt.IsZero() || sync.LastError != "" {
+		return false
+	}
+	return !sync.LastAt.Before(s.calendar.PublishedOn(expected))
+}
+
+// expectedRateDate returns the newest rate date the calendar has published by
+// now; CBR publishes the next working day's rates, so it may be tomorrow.
+func expectedRateDate(calendar provider.Calendar, now time.Time) time.Time {
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	for date := today.AddDate(0, 0, 7); date.After(today.AddDate(0, 0, -14)); date = date.AddDate(0, 0, -1) {
+		if calendar.IsPublicationDay(date) && !calendar.PublishedOn(date).After(today) {
+			return date
+		}
+	}
+	return today
+}
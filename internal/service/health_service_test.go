@@ -0,0 +1,215 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"RnD-service/internal/adapter/postgres"
+	"RnD-service/internal/adapter/provider"
+	"RnD-service/internal/entity"
+
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type mockHealthRepo struct {
+	mock.Mock
+}
+
+func (m *mockHealthRepo) Ping(ctx context.Context) error {
+	args := m.Called(ctx)
+	return args.Error(0)
+}
+
+func (m *mockHealthRepo) GetLatestRateDate(ctx context.Context, source string) (time.Time, error) {
+	args := m.Called(ctx, source)
+	return args.Get(0).(time.Time), args.Error(1)
+}
+
+type mockSchemaChecker struct {
+	mock.Mock
+}
+
+func (m *mockSchemaChecker) Status(ctx context.Context) (*postgres.MigrationStatus, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*postgres.MigrationStatus), args.Error(1)
+}
+
+type mockJobReporter struct {
+	mock.Mock
+}
+
+func (m *mockJobReporter) NextRun(name string) time.Time {
+	args := m.Called(name)
+	return args.Get(0).(time.Time)
+}
+
+func (m *mockJobReporter) LastFinishedRun(ctx context.Context, name string) (*entity.JobRun, error) {
+	args := m.Called(ctx, name)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.JobRun), args.Error(1)
+}
+
+// Monday: CBR has published Tuesday's rates by noon
+var healthNow = time.Date(2025, 8, 4, 12, 0, 0, 0, time.UTC)
+
+func setupHealthService() (*HealthService, *mockHealthRepo, *mockSchemaChecker) {
+	mockRepo := new(mockHealthRepo)
+	mockSchema := new(mockSchemaChecker)
+	logger, _ := test.NewNullLogger()
+	service := NewHealthService(mockRepo, mockSchema, provider.SourceCBR, provider.CBRCalendar(), 72*time.Hour, logger)
+	service.now = func() time.Time { return healthNow }
+	return service, mockRepo, mockSchema
+}
+
+func TestHealthCheck_Ready(t *testing.T) {
+	service, mockRepo, mockSchema := setupHealthService()
+
+	mockRepo.On("Ping", mock.Anything).Return(nil)
+	mockSchema.On("Status", mock.Anything).Return(&postgres.MigrationStatus{Version: 12, Latest: 12}, nil)
+	mockRepo.On("GetLatestRateDate", mock.Anything, "cbr").Return(time.Date(2025, 8, 2, 0, 0, 0, 0, time.UTC), nil)
+
+	lastSync := healthNow.Add(-time.Hour)
+	nextSync := healthNow.Add(time.Hour)
+	jobs := new(mockJobReporter)
+	jobs.On("NextRun", "cbr_rates").Return(nextSync)
+	jobs.On("LastFinishedRun", mock.Anything, "cbr_rates").Return(&entity.JobRun{Status: entity.JobRunSucceeded, FinishedAt: &lastSync}, nil)
+	service.SetSyncJob(jobs, "cbr_rates")
+
+	report := service.Check(context.Background())
+	require.True(t, report.Ready)
+	assert.Equal(t, healthNow, report.CheckedAt)
+	assert.True(t, report.Database.OK)
+	assert.True(t, report.Schema.OK)
+	assert.Equal(t, int64(12), report.Schema.Version)
+	assert.Equal(t, time.Date(2025, 8, 5, 0, 0, 0, 0, time.UTC), report.Rates.ExpectedDate)
+	assert.Equal(t, 72*time.Hour, report.Rates.Lag)
+	assert.False(t, report.Rates.Stale)
+	assert.Equal(t, lastSync, report.Sync.LastAt)
+	assert.Empty(t, report.Sync.LastError)
+	assert.Equal(t, nextSync, report.Sync.NextAt)
+}
+
+func TestHealthCheck_StaleRates(t *testing.T) {
+	service, mockRepo, mockSchema := setupHealthService()
+
+	mockRepo.On("Ping", mock.Anything).Return(nil)
+	mockSchema.On("Status", mock.Anything).Return(&postgres.MigrationStatus{Version: 12, Latest: 12}, nil)
+	mockRepo.On("GetLatestRateDate", mock.Anything, "cbr").Return(time.Date(2025, 8, 1, 0, 0, 0, 0, time.UTC), nil)
+	lastSync := healthNow.Add(-time.Hour)
+	jobs := new(mockJobReporter)
+	jobs.On("NextRun", "cbr_rates").Return(time.Time{})
+	jobs.On("LastFinishedRun", mock.Anything, "cbr_rates").Return(&entity.JobRun{Status: entity.JobRunFailed, Error: "CBR answered 503", FinishedAt: &lastSync}, nil)
+	service.SetSyncJob(jobs, "cbr_rates")
+
+	report := service.Check(context.Background())
+	assert.False(t, report.Ready)
+	assert.True(t, report.Rates.Stale)
+	assert.Equal(t, 96*time.Hour, report.Rates.Lag)
+	assert.Equal(t, "CBR answered 503", report.Sync.LastError)
+}
+
+func TestHealthCheck_NewYearBreak(t *testing.T) {
+	service, mockRepo, mockSchema := setupHealthService()
+	// Tuesday 6 January: CBR set no rates since the 31 December publication
+	service.now = func() time.Time { return time.Date(2026, 1, 6, 12, 0, 0, 0, time.UTC) }
+
+	mockRepo.On("Ping", mock.Anything).Return(nil)
+	mockSchema.On("Status", mock.Anything).Return(&postgres.MigrationStatus{Version: 12, Latest: 12}, nil)
+	mockRepo.On("GetLatestRateDate", mock.Anything, "cbr").Return(time.Date(2025, 12, 31, 0, 0, 0, 0, time.UTC), nil)
+
+	report := service.Check(context.Background())
+	assert.True(t, report.Ready)
+	assert.False(t, report.Rates.Stale)
+	assert.Equal(t, time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC), report.Rates.ExpectedDate)
+}
+
+func TestHealthCheck_SyncFoundNoNewRates(t *testing.T) {
+	service, mockRepo, mockSchema := setupHealthService()
+
+	mockRepo.On("Ping", mock.Anything).Return(nil)
+	mockSchema.On("Status", mock.Anything).Return(&postgres.MigrationStatus{Version: 12, Latest: 12}, nil)
+	mockRepo.On("GetLatestRateDate", mock.Anything, "cbr").Return(time.Date(2025, 8, 1, 0, 0, 0, 0, time.UTC), nil)
+	lastSync := healthNow.Add(-time.Hour)
+	jobs := new(mockJobReporter)
+	jobs.On("NextRun", "cbr_rates").Return(time.Time{})
+	jobs.On("LastFinishedRun", mock.Anything, "cbr_rates").Return(&entity.JobRun{Status: entity.JobRunSucceeded, FinishedAt: &lastSync}, nil)
+	service.SetSyncJob(jobs, "cbr_rates")
+
+	report := service.Check(context.Background())
+	assert.True(t, report.Ready)
+	assert.False(t, report.Rates.Stale)
+	assert.Equal(t, 96*time.Hour, report.Rates.Lag)
+}
+
+func TestHealthCheck_NoRates(t *testing.T) {
+	service, mockRepo, mockSchema := setupHealthService()
+
+	mockRepo.On("Ping", mock.Anything).Return(nil)
+	mockSchema.On("Status", mock.Anything).Return(&postgres.MigrationStatus{Version: 12, Latest: 12}, nil)
+	mockRepo.On("GetLatestRateDate", mock.Anything, "cbr").Return(time.Time{}, postgres.ErrNotFound)
+
+	report := service.Check(context.Background())
+	assert.False(t, report.Ready)
+	assert.True(t, report.Rates.Stale)
+	assert.Equal(t, "no rates stored", report.Rates.Error)
+}
+
+func TestHealthCheck_PendingMigrations(t *testing.T) {
+	service, mockRepo, mockSchema := setupHealthService()
+
+	mockRepo.On("Ping", mock.Anything).Return(nil)
+	mockSchema.On("Status", mock.Anything).Return(&postgres.MigrationStatus{Version: 11, Latest: 12}, nil)
+	mockRepo.On("GetLatestRateDate", mock.Anything, "cbr").Return(time.Date(2025, 8, 5, 0, 0, 0, 0, time.UTC), nil)
+
+	report := service.Check(context.Background())
+	assert.False(t, report.Ready)
+	assert.False(t, report.Schema.OK)
+	assert.Equal(t, "schema is not at the latest migration", report.Schema.Error)
+	assert.False(t, report.Rates.Stale)
+}
+
+func TestHealthCheck_DatabaseDown(t *testing.T) {
+	service, mockRepo, mockSchema := setupHealthService()
+
+	mockRepo.On("Ping", mock.Anything).Return(errors.New("ping database: connection refused"))
+
+	report := service.Check(context.Background())
+	assert.False(t, report.Ready)
+	assert.False(t, report.Database.OK)
+	assert.Equal(t, "ping database: connection refused", report.Database.Error)
+	mockSchema.AssertNotCalled(t, "Status", mock.Anything)
+	mockRepo.AssertNotCalled(t, "GetLatestRateDate", mock.Anything, mock.Anything)
+}
+
+func TestExpectedRateDate(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2025, 8, d, 0, 0, 0, 0, time.UTC) }
+
+	tests := []struct {
+		name     string
+		calendar provider.Calendar
+		now      time.Time
+		expected time.Time
+	}{
+		{"cbr friday publishes saturday", provider.CBRCalendar(), day(1), day(2)},
+		{"cbr sunday keeps saturday", provider.CBRCalendar(), day(3), day(2)},
+		{"cbr monday publishes tuesday", provider.CBRCalendar(), day(4), day(5)},
+		{"cbr new year break keeps 1 january", provider.CBRCalendar(), time.Date(2026, 1, 8, 12, 0, 0, 0, time.UTC), time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{"ecb sunday keeps friday", provider.TARGETCalendar(), day(3), day(1)},
+		{"ecb monday", provider.TARGETCalendar(), day(4).Add(18 * time.Hour), day(4)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, expectedRateDate(tt.calendar, tt.now))
+		})
+	}
+}
@@ -0,0 +1,299 @@
+package service
+
+import (
+	"RnD-service/internal/adapter/cbr"
+	"RnD-service/internal/adapter/postgres"
+	"RnD-service/internal/entity"
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"go.uber.org/multierr"
+)
+
+// indicatorSyncDays is how far back SyncIndicators re-reads both series, so
+// RUONIA revisions and days missed while the service was down are picked up.
+const indicatorSyncDays = 14
+
+type IndicatorService struct {
+	cbr    cbr.DailyInfoClient
+	repo   postgres.IndicatorRepository
+	logger *logrus.Logger
+	now    func() time.Time
+}
+
+func NewIndicatorService(cbr cbr.DailyInfoClient, repo postgres.IndicatorRepository, logger *logrus.Logger) *IndicatorService {
+	return &IndicatorService{
+		cbr:    cbr,
+		repo:   repo,
+		logger: logger,
+		now:    time.Now,
+	}
+}
+
+func (s *IndicatorService) SyncIndicators(ctx context.Context) error {
+	to := s.now().Truncate(24 * time.Hour)
+	from := to.AddDate(0, 0, -indicatorSyncDays)
+	s.logger.Infof("Syncing key rate and RUONIA from CBR for %s - %s", from.Format("2006-01-02"), to.Format("2006-01-02"))
+
+	var errs error
+	if _, err := s.fetchKeyRates(ctx, from, to); err != nil {
+		errs = multierr.Append(errs, err)
+	}
+	if _, err := s.fetchRuoniaRates(ctx, from, to); err != nil {
+		errs = multierr.Append(errs, err)
+	}
+	if errs != nil {
+		return errs
+	}
+
+	s.logger.Info("Key rate and RUONIA successfully synced.")
+	return nil
+}
+
+func (s *IndicatorService) GetKeyRates(ctx context.Context, dateFrom, dateTo time.Time) ([]entity.KeyRate, error) {
+	from, to, err := s.indicatorRange(dateFrom, dateTo)
+	if err != nil {
+		return nil, err
+	}
+	fromStr := from.Format("2006-01-02")
+	toStr := to.Format("2006-01-02")
+
+	cached, err := s.repo.GetKeyRates(ctx, fromStr, toStr)
+	if err != nil {
+		s.logger.WithError(err).Warn("DB error querying key rates, cannot proceed")
+		return nil, err
+	}
+
+	byDate := make(map[string]entity.KeyRate, len(cached))
+	for _, rate := range cached {
+		byDate[rate.Date.Format("2006-01-02")] = rate
+	}
+
+	firstMissing, lastMissing := missingBusinessDays(from, to, func(key string) bool {
+		_, ok := byDate[key]
+		return ok
+	})
+	if firstMissing.IsZero() {
+		s.logger.Infof("All %d key rates between %s and %s found in DB", len(cached), fromStr, toStr)
+		return cached, nil
+	}
+
+	s.logger.Infof("Key rates missing between %s and %s, fetching from CBR", firstMissing.Format("2006-01-02"), lastMissing.Format("2006-01-02"))
+	fetched, err := s.fetchKeyRates(ctx, firstMissing, lastMissing)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, rate := range fetched {
+		key := rate.Date.Format("2006-01-02")
+		if _, ok := byDate[key]; !ok {
+			byDate[key] = rate
+		}
+	}
+
+	result := make([]entity.KeyRate, 0, len(byDate))
+	for _, rate := range byDate {
+		result = append(result, rate)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Date.Before(result[j].Date)
+	})
+
+	s.logger.Infof("Returning %d key rates between %s and %s", len(result), fromStr, toStr)
+	return result, nil
+}
+
+func (s *IndicatorService) GetRuoniaRates(ctx context.Context, dateFrom, dateTo time.Time) ([]entity.RuoniaRate, error) {
+	from, to, err := s.indicatorRange(dateFrom, dateTo)
+	if err != nil {
+		return nil, err
+	}
+	fromStr := from.Format("2006-01-02")
+	toStr := to.Format("2006-01-02")
+
+	cached, err := s.repo.GetRuoniaRates(ctx, fromStr, toStr)
+	if err != nil {
+		s.logger.WithError(err).Warn("DB error querying RUONIA rates, cannot proceed")
+		return nil, err
+	}
+
+	byDate := make(map[string]entity.RuoniaRate, len(cached))
+	for _, rate := range cached {
+		byDate[rate.Date.Format("2006-01-02")] = rate
+	}
+
+	// RUONIA for a day is published on the next business day, so today is never a gap
+	gapsTo := to
+	if yesterday := s.now().Truncate(24*time.Hour).AddDate(0, 0, -1); gapsTo.After(yesterday) {
+		gapsTo = yesterday
+	}
+	firstMissing, lastMissing := missingBusinessDays(from, gapsTo, func(key string) bool {
+		_, ok := byDate[key]
+		return ok
+	})
+	if firstMissing.IsZero() {
+		s.logger.Infof("All %d RUONIA rates between %s and %s found in DB", len(cached), fromStr, toStr)
+		return cached, nil
+	}
+
+	s.logger.Infof("RUONIA rates missing between %s and %s, fetching from CBR", firstMissing.Format("2006-01-02"), lastMissing.Format("2006-01-02"))
+	fetched, err := s.fetchRuoniaRates(ctx, firstMissing, lastMissing)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, rate := range fetched {
+		key := rate.Date.Format("2006-01-02")
+		if _, ok := byDate[key]; !ok {
+			byDate[key] = rate
+		}
+	}
+
+	result := make([]entity.RuoniaRate, 0, len(byDate))
+	for _, rate := range byDate {
+		result = append(result, rate)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Date.Before(result[j].Date)
+	})
+
+	s.logger.Infof("Returning %d RUONIA rates between %s and %s", len(result), fromStr, toStr)
+	return result, nil
+}
+
+func (s *IndicatorService) indicatorRange(dateFrom, dateTo time.Time) (time.Time, time.Time, error) {
+	from := dateFrom.Truncate(24 * time.Hour)
+	to := dateTo.Truncate(24 * time.Hour)
+
+	if from.After(to) {
+		s.logger.Warnf("Invalid date range: %s > %s", from.Format("2006-01-02"), to.Format("2006-01-02"))
+		return time.Time{}, time.Time{}, fmt.Errorf("%w: 'from' is after 'to'", ErrInvalidDateRange)
+	}
+
+	today := s.now().Truncate(24 * time.Hour)
+	if to.After(today) {
+		s.logger.Warnf("Requested future date: %s", to.Format("2006-01-02"))
+		return time.Time{}, time.Time{}, ErrFutureDate
+	}
+
+	return from, to, nil
+}
+
+// fetchKeyRates loads the key rate from CBR and stores it. A failed store is
+// only logged: the caller still gets the fetched rates.
+func (s *IndicatorService) fetchKeyRates(ctx context.Context, from, to time.Time) ([]entity.KeyRate, error) {
+	resp, err := s.cbr.FetchKeyRate(ctx, from, to)
+	if err != nil {
+		s.logger.Errorf("Failed to fetch key rate from CBR: %v", err)
+		return nil, fmt.Errorf("fetch key rate from CBR: %w: %w", ErrUpstreamUnavailable, err)
+	}
+
+	rates, err := convertCBRKeyRates(resp, s.now())
+	if err != nil {
+		s.logger.Errorf("Failed to convert key rates: %v", err)
+		return nil, fmt.Errorf("convert key rates: %w", err)
+	}
+
+	if len(rates) > 0 {
+		if err := s.repo.StoreKeyRates(ctx, rates); err != nil {
+			s.logger.Errorf("Failed to store key rates in DB: %v", err)
+		}
+	}
+	return rates, nil
+}
+
+// fetchRuoniaRates loads RUONIA from CBR and upserts it, as CBR may revise
+// recent values.
+func (s *IndicatorService) fetchRuoniaRates(ctx context.Context, from, to time.Time) ([]entity.RuoniaRate, error) {
+	resp, err := s.cbr.FetchRuonia(ctx, from, to)
+	if err != nil {
+		s.logger.Errorf("Failed to fetch RUONIA from CBR: %v", err)
+		return nil, fmt.Errorf("fetch RUONIA from CBR: %w: %w", ErrUpstreamUnavailable, err)
+	}
+
+	rates, err := convertCBRRuonia(resp, s.now())
+	if err != nil {
+		s.logger.Errorf("Failed to convert RUONIA rates: %v", err)
+		return nil, fmt.Errorf("convert RUONIA rates: %w", err)
+	}
+
+	if len(rates) > 0 {
+		if err := s.repo.StoreRuoniaRates(ctx, rates); err != nil {
+			s.logger.Errorf("Failed to store RUONIA rates in DB: %v", err)
+		}
+	}
+	return rates, nil
+}
+
+// missingBusinessDays returns the first and last weekday between from and to
+// for which has reports no data, or zero times when nothing is missing.
+func missingBusinessDays(from, to time.Time, has func(date string) bool) (time.Time, time.Time) {
+	var firstMissing, lastMissing time.Time
+	for d := from; !d.After(to); d = d.AddDate(0, 0, 1) {
+		if d.Weekday() == time.Saturday || d.Weekday() == time.Sunday {
+			continue
+		}
+		if has(d.Format("2006-01-02")) {
+			continue
+		}
+		if firstMissing.IsZero() {
+			firstMissing = d
+		}
+		lastMissing = d
+	}
+	return firstMissing, lastMissing
+}
+
+func convertCBRKeyRates(resp *cbr.KeyRateResponse, fetchedAt time.Time) ([]entity.KeyRate, error) {
+	var result []entity.KeyRate
+
+	for _, record := range resp.Records() {
+		date, err := record.GetDate()
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse CBR key rate date '%s': %w", record.Date, err)
+		}
+		rate, err := record.GetRate()
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse key rate on %s: %w", record.Date, err)
+		}
+
+		result = append(result, entity.KeyRate{
+			Date:      date,
+			Rate:      rate,
+			UpdatedAt: fetchedAt,
+		})
+	}
+
+	return result, nil
+}
+
+func convertCBRRuonia(resp *cbr.RuoniaResponse, fetchedAt time.Time) ([]entity.RuoniaRate, error) {
+	var result []entity.RuoniaRate
+
+	for _, record := range resp.Records() {
+		date, err := record.GetDate()
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse CBR RUONIA date '%s': %w", record.Date, err)
+		}
+		rate, err := record.GetRate()
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse RUONIA on %s: %w", record.Date, err)
+		}
+		volume, err := record.GetVolume()
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse RUONIA volume on %s: %w", record.Date, err)
+		}
+
+		result = append(result, entity.RuoniaRate{
+			Date:      date,
+			Rate:      rate,
+			Volume:    volume,
+			UpdatedAt: fetchedAt,
+		})
+	}
+
+	return result, nil
+}
@@ -0,0 +1,225 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"RnD-service/internal/adapter/cbr"
+	"RnD-service/internal/entity"
+
+	"github.com/shopspring/decimal"
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type mockDailyInfoClient struct {
+	mock.Mock
+}
+
+func (m *mockDailyInfoClient) FetchKeyRate(ctx context.Context, dateFrom, dateTo time.Time) (*cbr.KeyRateResponse, error) {
+	args := m.Called(ctx, dateFrom, dateTo)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*cbr.KeyRateResponse), args.Error(1)
+}
+
+func (m *mockDailyInfoClient) FetchRuonia(ctx context.Context, dateFrom, dateTo time.Time) (*cbr.RuoniaResponse, error) {
+	args := m.Called(ctx, dateFrom, dateTo)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*cbr.RuoniaResponse), args.Error(1)
+}
+
+type mockIndicatorRepo struct {
+	mock.Mock
+}
+
+func (m *mockIndicatorRepo) StoreKeyRates(ctx context.Context, rates []entity.KeyRate) error {
+	args := m.Called(ctx, rates)
+	return args.Error(0)
+}
+
+func (m *mockIndicatorRepo) GetKeyRates(ctx context.Context, dateFrom, dateTo string) ([]entity.KeyRate, error) {
+	args := m.Called(ctx, dateFrom, dateTo)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]entity.KeyRate), args.Error(1)
+}
+
+func (m *mockIndicatorRepo) StoreRuoniaRates(ctx context.Context, rates []entity.RuoniaRate) error {
+	args := m.Called(ctx, rates)
+	return args.Error(0)
+}
+
+func (m *mockIndicatorRepo) GetRuoniaRates(ctx context.Context, dateFrom, dateTo string) ([]entity.RuoniaRate, error) {
+	args := m.Called(ctx, dateFrom, dateTo)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]entity.RuoniaRate), args.Error(1)
+}
+
+func setupIndicatorService() (*IndicatorService, *mockDailyInfoClient, *mockIndicatorRepo) {
+	mockCbr := new(mockDailyInfoClient)
+	mockRepo := new(mockIndicatorRepo)
+	logger, _ := test.NewNullLogger()
+	service := NewIndicatorService(mockCbr, mockRepo, logger)
+	service.now = func() time.Time { return metalTestNow }
+	return service, mockCbr, mockRepo
+}
+
+func day(year int, month time.Month, d int) time.Time {
+	return time.Date(year, month, d, 0, 0, 0, 0, time.UTC)
+}
+
+func keyRateResponse(rate string, dates ...string) *cbr.KeyRateResponse {
+	resp := &cbr.KeyRateResponse{Result: &cbr.KeyRateResult{}}
+	for _, date := range dates {
+		resp.Result.Rates = append(resp.Result.Rates, cbr.KeyRateRecord{Date: date + "T00:00:00+03:00", Rate: rate})
+	}
+	return resp
+}
+
+func ruoniaResponse(rate string, dates ...string) *cbr.RuoniaResponse {
+	resp := &cbr.RuoniaResponse{Result: &cbr.RuoniaResult{}}
+	for _, date := range dates {
+		resp.Result.Rates = append(resp.Result.Rates, cbr.RuoniaRecord{Date: date + "T00:00:00+03:00", Rate: rate, Volume: "512.34"})
+	}
+	return resp
+}
+
+func TestSyncIndicators(t *testing.T) {
+	ctx := context.Background()
+	service, mockCbr, mockRepo := setupIndicatorService()
+
+	mockCbr.On("FetchKeyRate", ctx, day(2025, 7, 18), day(2025, 8, 1)).Return(keyRateResponse("18.00", "2025-08-01", "2025-07-31"), nil)
+	mockCbr.On("FetchRuonia", ctx, day(2025, 7, 18), day(2025, 8, 1)).Return(ruoniaResponse("17.85", "2025-07-31"), nil)
+	mockRepo.On("StoreKeyRates", ctx, mock.MatchedBy(func(rates []entity.KeyRate) bool {
+		return len(rates) == 2 && rates[0].Date.Equal(day(2025, 8, 1))
+	})).Return(nil)
+	mockRepo.On("StoreRuoniaRates", ctx, mock.MatchedBy(func(rates []entity.RuoniaRate) bool {
+		return len(rates) == 1 && rates[0].Volume.Equal(decimal.RequireFromString("512.34"))
+	})).Return(nil)
+
+	require.NoError(t, service.SyncIndicators(ctx))
+	mockCbr.AssertExpectations(t)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestSyncIndicators_OneSeriesFails(t *testing.T) {
+	ctx := context.Background()
+	service, mockCbr, mockRepo := setupIndicatorService()
+
+	mockCbr.On("FetchKeyRate", ctx, mock.Anything, mock.Anything).Return(nil, errors.New("timeout"))
+	mockCbr.On("FetchRuonia", ctx, mock.Anything, mock.Anything).Return(ruoniaResponse("17.85", "2025-07-31"), nil)
+	mockRepo.On("StoreRuoniaRates", ctx, mock.Anything).Return(nil)
+
+	err := service.SyncIndicators(ctx)
+	assert.ErrorIs(t, err, ErrUpstreamUnavailable)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestGetKeyRates_AllCached(t *testing.T) {
+	ctx := context.Background()
+	service, mockCbr, mockRepo := setupIndicatorService()
+
+	cached := []entity.KeyRate{
+		{Date: day(2025, 7, 25), Rate: decimal.RequireFromString("20")},
+		{Date: day(2025, 7, 28), Rate: decimal.RequireFromString("18")},
+	}
+	mockRepo.On("GetKeyRates", ctx, "2025-07-25", "2025-07-28").Return(cached, nil)
+
+	rates, err := service.GetKeyRates(ctx, day(2025, 7, 25), day(2025, 7, 28))
+	require.NoError(t, err)
+	assert.Equal(t, cached, rates)
+	mockCbr.AssertNotCalled(t, "FetchKeyRate", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestGetKeyRates_FetchesGapOnce(t *testing.T) {
+	ctx := context.Background()
+	service, mockCbr, mockRepo := setupIndicatorService()
+
+	mockRepo.On("GetKeyRates", ctx, "2025-07-24", "2025-07-29").Return([]entity.KeyRate{
+		{Date: day(2025, 7, 24), Rate: decimal.RequireFromString("20")},
+		{Date: day(2025, 7, 29), Rate: decimal.RequireFromString("18")},
+	}, nil)
+	mockCbr.On("FetchKeyRate", ctx, day(2025, 7, 25), day(2025, 7, 28)).Return(keyRateResponse("18.00", "2025-07-28", "2025-07-25"), nil)
+	mockRepo.On("StoreKeyRates", ctx, mock.Anything).Return(nil)
+
+	rates, err := service.GetKeyRates(ctx, day(2025, 7, 24), day(2025, 7, 29))
+	require.NoError(t, err)
+	require.Len(t, rates, 4)
+	assert.Equal(t, day(2025, 7, 24), rates[0].Date)
+	assert.Equal(t, day(2025, 7, 25), rates[1].Date)
+	assert.Equal(t, day(2025, 7, 29), rates[3].Date)
+	mockCbr.AssertNumberOfCalls(t, "FetchKeyRate", 1)
+}
+
+func TestGetKeyRates_Validation(t *testing.T) {
+	service, _, _ := setupIndicatorService()
+
+	_, err := service.GetKeyRates(context.Background(), day(2025, 7, 29), day(2025, 7, 24))
+	assert.ErrorIs(t, err, ErrInvalidDateRange)
+
+	_, err = service.GetKeyRates(context.Background(), day(2025, 7, 29), day(2025, 8, 2))
+	assert.ErrorIs(t, err, ErrFutureDate)
+}
+
+func TestGetKeyRates_UpstreamError(t *testing.T) {
+	ctx := context.Background()
+	service, mockCbr, mockRepo := setupIndicatorService()
+
+	mockRepo.On("GetKeyRates", ctx, "2025-07-28", "2025-07-28").Return(nil, nil)
+	mockCbr.On("FetchKeyRate", ctx, day(2025, 7, 28), day(2025, 7, 28)).Return(nil, errors.New("connection refused"))
+
+	_, err := service.GetKeyRates(ctx, day(2025, 7, 28), day(2025, 7, 28))
+	assert.ErrorIs(t, err, ErrUpstreamUnavailable)
+}
+
+func TestGetRuoniaRates_TodayIsNotAGap(t *testing.T) {
+	ctx := context.Background()
+	service, mockCbr, mockRepo := setupIndicatorService()
+
+	cached := []entity.RuoniaRate{
+		{Date: day(2025, 7, 31), Rate: decimal.RequireFromString("17.85")},
+	}
+	mockRepo.On("GetRuoniaRates", ctx, "2025-07-31", "2025-08-01").Return(cached, nil)
+
+	rates, err := service.GetRuoniaRates(ctx, day(2025, 7, 31), day(2025, 8, 1))
+	require.NoError(t, err)
+	assert.Equal(t, cached, rates)
+	mockCbr.AssertNotCalled(t, "FetchRuonia", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestGetRuoniaRates_FetchesGap(t *testing.T) {
+	ctx := context.Background()
+	service, mockCbr, mockRepo := setupIndicatorService()
+
+	mockRepo.On("GetRuoniaRates", ctx, "2025-07-28", "2025-08-01").Return(nil, nil)
+	mockCbr.On("FetchRuonia", ctx, day(2025, 7, 28), day(2025, 7, 31)).Return(ruoniaResponse("17.85", "2025-07-31", "2025-07-30", "2025-07-29", "2025-07-28"), nil)
+	mockRepo.On("StoreRuoniaRates", ctx, mock.Anything).Return(errors.New("db down"))
+
+	rates, err := service.GetRuoniaRates(ctx, day(2025, 7, 28), day(2025, 8, 1))
+	require.NoError(t, err, "a failed store does not fail the read")
+	require.Len(t, rates, 4)
+	assert.Equal(t, day(2025, 7, 28), rates[0].Date)
+	assert.Equal(t, "512.34", rates[0].Volume.String())
+}
+
+func TestGetRuoniaRates_DBError(t *testing.T) {
+	ctx := context.Background()
+	service, _, mockRepo := setupIndicatorService()
+
+	dbErr := errors.New("db down")
+	mockRepo.On("GetRuoniaRates", ctx, "2025-07-28", "2025-07-29").Return(nil, dbErr)
+
+	_, err := service.GetRuoniaRates(ctx, day(2025, 7, 28), day(2025, 7, 29))
+	assert.ErrorIs(t, err, dbErr)
+}
@@ -0,0 +1,265 @@
+package service
+
+import (
+	"RnD-service/internal/adapter/cbr"
+	"RnD-service/internal/adapter/postgres"
+	"RnD-service/internal/entity"
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// metalLookbackDays is how far back GetMetalPrice looks for the last trading
+// day when the requested date is a weekend or holiday.
+const metalLookbackDays = 7
+
+type MetalService struct {
+	cbr    cbr.CbrClient
+	repo   postgres.MetalRepository
+	logger *logrus.Logger
+	now    func() time.Time
+}
+
+func NewMetalService(cbr cbr.CbrClient, repo postgres.MetalRepository, logger *logrus.Logger) *MetalService {
+	return &MetalService{
+		cbr:    cbr,
+		repo:   repo,
+		logger: logger,
+		now:    time.Now,
+	}
+}
+
+func (s *MetalService) StoreMetalPricesFromCbr(ctx context.Context) error {
+	date := s.now().Format("02/01/2006")
+	s.logger.Info("Fetching metal prices from CBR...")
+
+	resp, err := s.cbr.FetchMetalPrices(ctx, date, date)
+	if err != nil {
+		s.logger.Errorf("Failed to fetch metal prices from CBR: %v", err)
+		return fmt.Errorf("fetch metal prices: %w: %w", ErrUpstreamUnavailable, err)
+	}
+
+	prices, err := convertCBRMetals(*resp, s.now())
+	if err != nil {
+		s.logger.Errorf("Failed to convert metal prices: %v", err)
+		return fmt.Errorf("convert metal prices: %w", err)
+	}
+
+	if len(prices) == 0 {
+		s.logger.Infof("No metal prices published for %s", date)
+		return nil
+	}
+
+	if err := s.repo.StoreMetalPrices(ctx, prices); err != nil {
+		s.logger.Errorf("Failed to store metal prices in DB: %v", err)
+		return fmt.Errorf("store metal prices in DB: %w", err)
+	}
+
+	s.logger.Infof("Metal prices for %s successfully stored.", date)
+	return nil
+}
+
+// GetMetalPrice returns the price for the given date, or for the last trading
+// day before it when CBR did not set prices on that date.
+func (s *MetalService) GetMetalPrice(ctx context.Context, code string, date time.Time) (*entity.MetalPrice, error) {
+	code = strings.ToUpper(code)
+	if _, ok := entity.MetalNames[code]; !ok {
+		s.logger.Warnf("Unknown metal code: %s", code)
+		return nil, fmt.Errorf("%w: %s", ErrUnknownMetal, code)
+	}
+
+	requestedDate := date.Truncate(24 * time.Hour)
+	today := s.now().Truncate(24 * time.Hour)
+	if requestedDate.After(today) {
+		s.logger.Warnf("Requested future date: %s", requestedDate.Format("2006-01-02"))
+		return nil, ErrFutureDate
+	}
+
+	dateStr := requestedDate.Format("2006-01-02")
+
+	price, err := s.repo.GetMetalPrice(ctx, code, dateStr)
+	if err == nil {
+		s.logger.Infof("Found metal price for %s on %s: %s", code, dateStr, price.Sell)
+		return price, nil
+	}
+	if !errors.Is(err, postgres.ErrNotFound) {
+		s.logger.WithError(err).Warn("DB error querying metal price, cannot proceed")
+		return nil, err
+	}
+
+	from := requestedDate.AddDate(0, 0, -metalLookbackDays)
+	s.logger.Infof("Metal price for %s on %s not found in DB, fetching from CBR since %s", code, dateStr, from.Format("2006-01-02"))
+
+	resp, err := s.cbr.FetchMetalPrices(ctx, from.Format("02/01/2006"), requestedDate.Format("02/01/2006"))
+	if err != nil {
+		s.logger.Errorf("Failed to fetch metal prices from CBR for date %s: %v", dateStr, err)
+		return nil, fmt.Errorf("fetch metal prices from CBR: %w: %w", ErrUpstreamUnavailable, err)
+	}
+
+	prices, err := convertCBRMetals(*resp, s.now())
+	if err != nil {
+		s.logger.Errorf("Failed to convert metal prices for date %s: %v", dateStr, err)
+		return nil, fmt.Errorf("convert metal prices: %w", err)
+	}
+
+	if len(prices) > 0 {
+		if err := s.repo.StoreMetalPrices(ctx, prices); err != nil {
+			s.logger.Errorf("Failed to store metal prices in DB for date %s: %v", dateStr, err)
+		}
+	}
+
+	var latest *entity.MetalPrice
+	for i := range prices {
+		if prices[i].Code != code || prices[i].Date.After(requestedDate) {
+			continue
+		}
+		if latest == nil || prices[i].Date.After(latest.Date) {
+			latest = &prices[i]
+		}
+	}
+	if latest == nil {
+		s.logger.Warnf("Metal %s not found in CBR prices for %s", code, dateStr)
+		return nil, fmt.Errorf("%w: metal %s for date %s", ErrRateNotFound, code, dateStr)
+	}
+
+	if !latest.Date.Equal(requestedDate) {
+		s.logger.Warnf("CBR не установил цену %s на %s, используется цена за %s", code, dateStr, latest.Date.Format("2006-01-02"))
+	}
+	return latest, nil
+}
+
+func (s *MetalService) GetMetalPricesByDateRange(ctx context.Context, code string, dateFrom, dateTo time.Time) ([]entity.MetalPrice, error) {
+	code = strings.ToUpper(code)
+	if _, ok := entity.MetalNames[code]; !ok {
+		s.logger.Warnf("Unknown metal code: %s", code)
+		return nil, fmt.Errorf("%w: %s", ErrUnknownMetal, code)
+	}
+
+	from := dateFrom.Truncate(24 * time.Hour)
+	to := dateTo.Truncate(24 * time.Hour)
+
+	if from.After(to) {
+		s.logger.Warnf("Invalid date range: %s > %s", from.Format("2006-01-02"), to.Format("2006-01-02"))
+		return nil, fmt.Errorf("%w: 'from' is after 'to'", ErrInvalidDateRange)
+	}
+
+	today := s.now().Truncate(24 * time.Hour)
+	if to.After(today) {
+		s.logger.Warnf("Requested future date: %s", to.Format("2006-01-02"))
+		return nil, ErrFutureDate
+	}
+
+	fromStr := from.Format("2006-01-02")
+	toStr := to.Format("2006-01-02")
+
+	cached, err := s.repo.GetMetalPricesByDateRange(ctx, code, fromStr, toStr)
+	if err != nil {
+		s.logger.WithError(err).Warn("DB error querying metal prices range, cannot proceed")
+		return nil, err
+	}
+
+	byDate := make(map[string]entity.MetalPrice, len(cached))
+	for _, price := range cached {
+		byDate[price.Date.Format("2006-01-02")] = price
+	}
+
+	// CBR does not set metal prices on weekends, so only weekdays count as gaps
+	var firstMissing, lastMissing time.Time
+	for d := from; !d.After(to); d = d.AddDate(0, 0, 1) {
+		if d.Weekday() == time.Saturday || d.Weekday() == time.Sunday {
+			continue
+		}
+		if _, ok := byDate[d.Format("2006-01-02")]; ok {
+			continue
+		}
+		if firstMissing.IsZero() {
+			firstMissing = d
+		}
+		lastMissing = d
+	}
+
+	if firstMissing.IsZero() {
+		s.logger.Infof("All %d metal prices for %s between %s and %s found in DB", len(cached), code, fromStr, toStr)
+		return cached, nil
+	}
+
+	s.logger.Infof("Metal prices for %s missing between %s and %s, fetching from CBR", code, firstMissing.Format("2006-01-02"), lastMissing.Format("2006-01-02"))
+
+	resp, err := s.cbr.FetchMetalPrices(ctx, firstMissing.Format("02/01/2006"), lastMissing.Format("02/01/2006"))
+	if err != nil {
+		s.logger.Errorf("Failed to fetch metal prices from CBR: %v", err)
+		return nil, fmt.Errorf("fetch metal prices from CBR: %w: %w", ErrUpstreamUnavailable, err)
+	}
+
+	fetched, err := convertCBRMetals(*resp, s.now())
+	if err != nil {
+		s.logger.Errorf("Failed to convert metal prices: %v", err)
+		return nil, fmt.Errorf("convert metal prices: %w", err)
+	}
+
+	if len(fetched) > 0 {
+		if err := s.repo.StoreMetalPrices(ctx, fetched); err != nil {
+			s.logger.Errorf("Failed to store metal prices in DB: %v", err)
+		}
+	}
+
+	for _, price := range fetched {
+		if price.Code != code {
+			continue
+		}
+		key := price.Date.Format("2006-01-02")
+		if _, ok := byDate[key]; !ok {
+			byDate[key] = price
+		}
+	}
+
+	result := make([]entity.MetalPrice, 0, len(byDate))
+	for _, price := range byDate {
+		result = append(result, price)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Date.Before(result[j].Date)
+	})
+
+	s.logger.Infof("Returning %d metal prices for %s between %s and %s", len(result), code, fromStr, toStr)
+	return result, nil
+}
+
+// convertCBRMetals skips metals CBR may add that have no code in our API.
+func convertCBRMetals(resp cbr.Metall, fetchedAt time.Time) ([]entity.MetalPrice, error) {
+	var result []entity.MetalPrice
+
+	for _, record := range resp.Records {
+		code := record.MetalCode()
+		if code == "" {
+			continue
+		}
+		date, err := time.Parse("02.01.2006", record.Date)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse CBR metal record date '%s': %w", record.Date, err)
+		}
+		buy, err := record.GetBuy()
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse buy price of metal %s on %s: %w", code, record.Date, err)
+		}
+		sell, err := record.GetSell()
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse sell price of metal %s on %s: %w", code, record.Date, err)
+		}
+
+		result = append(result, entity.MetalPrice{
+			Code:      code,
+			Date:      date,
+			Buy:       buy,
+			Sell:      sell,
+			UpdatedAt: fetchedAt,
+		})
+	}
+
+	return result, nil
+}
@@ -0,0 +1,239 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"RnD-service/internal/adapter/cbr"
+	"RnD-service/internal/adapter/postgres"
+	"RnD-service/internal/entity"
+
+	"github.com/shopspring/decimal"
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type mockMetalRepo struct {
+	mock.Mock
+}
+
+func (m *mockMetalRepo) StoreMetalPrices(ctx context.Context, prices []entity.MetalPrice) error {
+	args := m.Called(ctx, prices)
+	return args.Error(0)
+}
+
+func (m *mockMetalRepo) GetMetalPrice(ctx context.Context, code, date string) (*entity.MetalPrice, error) {
+	args := m.Called(ctx, code, date)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.MetalPrice), args.Error(1)
+}
+
+func (m *mockMetalRepo) GetMetalPricesByDateRange(ctx context.Context, code, dateFrom, dateTo string) ([]entity.MetalPrice, error) {
+	args := m.Called(ctx, code, dateFrom, dateTo)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]entity.MetalPrice), args.Error(1)
+}
+
+// Friday, 1 August 2025.
+var metalTestNow = time.Date(2025, 8, 1, 12, 0, 0, 0, time.UTC)
+
+func setupMetalService() (*MetalService, *mockCbrClient, *mockMetalRepo) {
+	mockCbr := new(mockCbrClient)
+	mockRepo := new(mockMetalRepo)
+	logger, _ := test.NewNullLogger()
+	service := NewMetalService(mockCbr, mockRepo, logger)
+	service.now = func() time.Time { return metalTestNow }
+	return service, mockCbr, mockRepo
+}
+
+func metallResponse(dates ...string) *cbr.Metall {
+	resp := &cbr.Metall{}
+	for _, date := range dates {
+		resp.Records = append(resp.Records,
+			cbr.MetallRecord{Date: date, Code: 1, Buy: "8563,47", Sell: "8563,47"},
+			cbr.MetallRecord{Date: date, Code: 2, Buy: "96,12", Sell: "96,12"},
+			cbr.MetallRecord{Date: date, Code: 9, Buy: "1,00", Sell: "1,00"},
+		)
+	}
+	return resp
+}
+
+func TestStoreMetalPricesFromCbr(t *testing.T) {
+	ctx := context.Background()
+	service, mockCbr, mockRepo := setupMetalService()
+
+	mockCbr.On("FetchMetalPrices", ctx, "01/08/2025", "01/08/2025").Return(metallResponse("01.08.2025"), nil)
+	mockRepo.On("StoreMetalPrices", ctx, mock.MatchedBy(func(prices []entity.MetalPrice) bool {
+		return len(prices) == 2 && prices[0].Code == "AU" && prices[1].Code == "AG"
+	})).Return(nil)
+
+	require.NoError(t, service.StoreMetalPricesFromCbr(ctx))
+	mockCbr.AssertExpectations(t)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestStoreMetalPricesFromCbr_NoPrices(t *testing.T) {
+	ctx := context.Background()
+	service, mockCbr, mockRepo := setupMetalService()
+
+	mockCbr.On("FetchMetalPrices", ctx, "01/08/2025", "01/08/2025").Return(&cbr.Metall{}, nil)
+
+	require.NoError(t, service.StoreMetalPricesFromCbr(ctx))
+	mockRepo.AssertNotCalled(t, "StoreMetalPrices", mock.Anything, mock.Anything)
+}
+
+func TestStoreMetalPricesFromCbr_UpstreamError(t *testing.T) {
+	ctx := context.Background()
+	service, mockCbr, _ := setupMetalService()
+
+	mockCbr.On("FetchMetalPrices", ctx, "01/08/2025", "01/08/2025").Return((*cbr.Metall)(nil), errors.New("timeout"))
+
+	err := service.StoreMetalPricesFromCbr(ctx)
+	assert.ErrorIs(t, err, ErrUpstreamUnavailable)
+}
+
+func TestGetMetalPrice_FromDB(t *testing.T) {
+	ctx := context.Background()
+	service, mockCbr, mockRepo := setupMetalService()
+
+	cached := &entity.MetalPrice{Code: "AU", Date: time.Date(2025, 7, 30, 0, 0, 0, 0, time.UTC), Sell: decimal.RequireFromString("8500")}
+	mockRepo.On("GetMetalPrice", ctx, "AU", "2025-07-30").Return(cached, nil)
+
+	price, err := service.GetMetalPrice(ctx, "au", time.Date(2025, 7, 30, 0, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+	assert.Equal(t, cached, price)
+	mockCbr.AssertNotCalled(t, "FetchMetalPrices", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestGetMetalPrice_FetchesAndStores(t *testing.T) {
+	ctx := context.Background()
+	service, mockCbr, mockRepo := setupMetalService()
+
+	mockRepo.On("GetMetalPrice", ctx, "AG", "2025-07-31").Return(nil, postgres.ErrNotFound)
+	mockCbr.On("FetchMetalPrices", ctx, "24/07/2025", "31/07/2025").Return(metallResponse("30.07.2025", "31.07.2025"), nil)
+	mockRepo.On("StoreMetalPrices", ctx, mock.MatchedBy(func(prices []entity.MetalPrice) bool {
+		return len(prices) == 4
+	})).Return(nil)
+
+	price, err := service.GetMetalPrice(ctx, "AG", time.Date(2025, 7, 31, 0, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+	assert.Equal(t, "AG", price.Code)
+	assert.Equal(t, time.Date(2025, 7, 31, 0, 0, 0, 0, time.UTC), price.Date)
+	assert.Equal(t, "96.12", price.Sell.String())
+	mockRepo.AssertExpectations(t)
+}
+
+func TestGetMetalPrice_Weekend(t *testing.T) {
+	ctx := context.Background()
+	service, mockCbr, mockRepo := setupMetalService()
+
+	// Sunday 27 July: the last prices were set on Saturday 26 July.
+	mockRepo.On("GetMetalPrice", ctx, "AU", "2025-07-27").Return(nil, postgres.ErrNotFound)
+	mockCbr.On("FetchMetalPrices", ctx, "20/07/2025", "27/07/2025").Return(metallResponse("25.07.2025", "26.07.2025"), nil)
+	mockRepo.On("StoreMetalPrices", ctx, mock.Anything).Return(nil)
+
+	price, err := service.GetMetalPrice(ctx, "AU", time.Date(2025, 7, 27, 0, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2025, 7, 26, 0, 0, 0, 0, time.UTC), price.Date)
+}
+
+func TestGetMetalPrice_NotPublished(t *testing.T) {
+	ctx := context.Background()
+	service, mockCbr, mockRepo := setupMetalService()
+
+	mockRepo.On("GetMetalPrice", ctx, "PT", "2025-07-31").Return(nil, postgres.ErrNotFound)
+	mockCbr.On("FetchMetalPrices", ctx, "24/07/2025", "31/07/2025").Return(metallResponse("31.07.2025"), nil)
+	mockRepo.On("StoreMetalPrices", ctx, mock.Anything).Return(nil)
+
+	_, err := service.GetMetalPrice(ctx, "PT", time.Date(2025, 7, 31, 0, 0, 0, 0, time.UTC))
+	assert.ErrorIs(t, err, ErrRateNotFound)
+}
+
+func TestGetMetalPrice_Validation(t *testing.T) {
+	ctx := context.Background()
+	service, _, mockRepo := setupMetalService()
+
+	_, err := service.GetMetalPrice(ctx, "XX", metalTestNow)
+	assert.ErrorIs(t, err, ErrUnknownMetal)
+
+	_, err = service.GetMetalPrice(ctx, "AU", metalTestNow.AddDate(0, 0, 1))
+	assert.ErrorIs(t, err, ErrFutureDate)
+
+	mockRepo.AssertNotCalled(t, "GetMetalPrice", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestGetMetalPrice_DBError(t *testing.T) {
+	ctx := context.Background()
+	service, mockCbr, mockRepo := setupMetalService()
+
+	dbErr := errors.New("connection refused")
+	mockRepo.On("GetMetalPrice", ctx, "AU", "2025-07-31").Return(nil, dbErr)
+
+	_, err := service.GetMetalPrice(ctx, "AU", time.Date(2025, 7, 31, 0, 0, 0, 0, time.UTC))
+	assert.ErrorIs(t, err, dbErr)
+	mockCbr.AssertNotCalled(t, "FetchMetalPrices", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestGetMetalPricesByDateRange_AllCached(t *testing.T) {
+	ctx := context.Background()
+	service, mockCbr, mockRepo := setupMetalService()
+
+	// Friday to Monday: the weekend is not a gap.
+	cached := []entity.MetalPrice{
+		{Code: "AU", Date: time.Date(2025, 7, 25, 0, 0, 0, 0, time.UTC)},
+		{Code: "AU", Date: time.Date(2025, 7, 28, 0, 0, 0, 0, time.UTC)},
+	}
+	mockRepo.On("GetMetalPricesByDateRange", ctx, "AU", "2025-07-25", "2025-07-28").Return(cached, nil)
+
+	prices, err := service.GetMetalPricesByDateRange(ctx, "AU", time.Date(2025, 7, 25, 0, 0, 0, 0, time.UTC), time.Date(2025, 7, 28, 0, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+	assert.Equal(t, cached, prices)
+	mockCbr.AssertNotCalled(t, "FetchMetalPrices", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestGetMetalPricesByDateRange_FetchesMissing(t *testing.T) {
+	ctx := context.Background()
+	service, mockCbr, mockRepo := setupMetalService()
+
+	cached := []entity.MetalPrice{
+		{Code: "AG", Date: time.Date(2025, 7, 29, 0, 0, 0, 0, time.UTC), Sell: decimal.RequireFromString("95")},
+	}
+	mockRepo.On("GetMetalPricesByDateRange", ctx, "AG", "2025-07-29", "2025-07-31").Return(cached, nil)
+	mockCbr.On("FetchMetalPrices", ctx, "30/07/2025", "31/07/2025").Return(metallResponse("30.07.2025", "31.07.2025"), nil)
+	mockRepo.On("StoreMetalPrices", ctx, mock.Anything).Return(nil)
+
+	prices, err := service.GetMetalPricesByDateRange(ctx, "AG", time.Date(2025, 7, 29, 0, 0, 0, 0, time.UTC), time.Date(2025, 7, 31, 0, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+	require.Len(t, prices, 3)
+	assert.Equal(t, "95", prices[0].Sell.String())
+	assert.Equal(t, time.Date(2025, 7, 30, 0, 0, 0, 0, time.UTC), prices[1].Date)
+	assert.Equal(t, "AG", prices[2].Code)
+	mockCbr.AssertExpectations(t)
+}
+
+func TestGetMetalPricesByDateRange_Validation(t *testing.T) {
+	ctx := context.Background()
+	service, _, _ := setupMetalService()
+
+	_, err := service.GetMetalPricesByDateRange(ctx, "AU", time.Date(2025, 7, 31, 0, 0, 0, 0, time.UTC), time.Date(2025, 7, 30, 0, 0, 0, 0, time.UTC))
+	assert.ErrorIs(t, err, ErrInvalidDateRange)
+
+	_, err = service.GetMetalPricesByDateRange(ctx, "AU", time.Date(2025, 7, 30, 0, 0, 0, 0, time.UTC), time.Date(2025, 8, 2, 0, 0, 0, 0, time.UTC))
+	assert.ErrorIs(t, err, ErrFutureDate)
+
+	_, err = service.GetMetalPricesByDateRange(ctx, "CU", time.Date(2025, 7, 30, 0, 0, 0, 0, time.UTC), time.Date(2025, 7, 31, 0, 0, 0, 0, time.UTC))
+	assert.ErrorIs(t, err, ErrUnknownMetal)
+}
+
+func TestConvertCBRMetals_InvalidDate(t *testing.T) {
+	_, err := convertCBRMetals(cbr.Metall{Records: []cbr.MetallRecord{{Date: "bad", Code: 1, Buy: "1", Sell: "1"}}}, metalTestNow)
+	assert.Error(t, err)
+}
@@ -3,18 +3,29 @@ package service
 import (
 	"context"
 	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 	"time"
 
 	"RnD-service/internal/adapter/cbr"
 	"RnD-service/internal/adapter/postgres"
+	"RnD-service/internal/adapter/provider"
 	"RnD-service/internal/entity"
+	"RnD-service/pkg/tracing"
 
+	"github.com/shopspring/decimal"
 	"github.com/sirupsen/logrus"
 	"github.com/sirupsen/logrus/hooks/test"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace/noop"
 )
 
 type mockCbrClient struct {
@@ -26,6 +37,21 @@ func (m *mockCbrClient) FetchRates(ctx context.Context, date string) (*cbr.ValCu
 	return args.Get(0).(*cbr.ValCurs), args.Error(1)
 }
 
+func (m *mockCbrClient) FetchDynamicRates(ctx context.Context, valuteID, dateFrom, dateTo string) (*cbr.ValCursDynamic, error) {
+	args := m.Called(ctx, valuteID, dateFrom, dateTo)
+	return args.Get(0).(*cbr.ValCursDynamic), args.Error(1)
+}
+
+func (m *mockCbrClient) FetchCurrencyCatalog(ctx context.Context, monthly bool) (*cbr.Valuta, error) {
+	args := m.Called(ctx, monthly)
+	return args.Get(0).(*cbr.Valuta), args.Error(1)
+}
+
+func (m *mockCbrClient) FetchMetalPrices(ctx context.Context, dateFrom, dateTo string) (*cbr.Metall, error) {
+	args := m.Called(ctx, dateFrom, dateTo)
+	return args.Get(0).(*cbr.Metall), args.Error(1)
+}
+
 type mockPostgresRepo struct {
 	mock.Mock
 }
@@ -35,25 +61,86 @@ func (m *mockPostgresRepo) StoreRates(ctx context.Context, rates []entity.Curren
 	return args.Error(0)
 }
 
-func (m *mockPostgresRepo) GetRateByCharCode(ctx context.Context, charCode string) (*entity.Currency, error) {
-	args := m.Called(ctx, charCode)
+func (m *mockPostgresRepo) GetRateByCharCode(ctx context.Context, source, charCode string) (*entity.Currency, error) {
+	args := m.Called(ctx, source, charCode)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.Currency), args.Error(1)
+}
+
+func (m *mockPostgresRepo) GetRateByCharCodeAndDate(ctx context.Context, source, charCode, date string) (*entity.Currency, error) {
+	args := m.Called(ctx, source, charCode, date)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
 	return args.Get(0).(*entity.Currency), args.Error(1)
 }
 
-func (m *mockPostgresRepo) StoreHistoricalRates(ctx context.Context, date time.Time, rates []entity.Currency) error {
-	args := m.Called(ctx, date, rates)
+func (m *mockPostgresRepo) GetRatesByCharCodesAndDate(ctx context.Context, source string, charCodes []string, date string) ([]entity.Currency, error) {
+	args := m.Called(ctx, source, charCodes, date)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]entity.Currency), args.Error(1)
+}
+
+func (m *mockPostgresRepo) GetRatesByCharCodeAndDateRange(ctx context.Context, source, charCode, dateFrom, dateTo string) ([]entity.Currency, error) {
+	args := m.Called(ctx, source, charCode, dateFrom, dateTo)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]entity.Currency), args.Error(1)
+}
+
+func (m *mockPostgresRepo) GetLatestRateSummaries(ctx context.Context) ([]entity.LatestRateSummary, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]entity.LatestRateSummary), args.Error(1)
+}
+
+func (m *mockPostgresRepo) GetRateStats(ctx context.Context, source, charCode, dateFrom, dateTo string) (*entity.RateStats, error) {
+	args := m.Called(ctx, source, charCode, dateFrom, dateTo)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.RateStats), args.Error(1)
+}
+
+func (m *mockPostgresRepo) StoreRateGaps(ctx context.Context, source, charCode string, dates []time.Time, checkedAt time.Time) error {
+	args := m.Called(ctx, source, charCode, dates, checkedAt)
 	return args.Error(0)
 }
 
-func (m *mockPostgresRepo) GetRateByCharCodeAndDate(ctx context.Context, charCode, date string) (*entity.Currency, error) {
-	args := m.Called(ctx, charCode, date)
+func (m *mockPostgresRepo) GetRateGaps(ctx context.Context, source, charCode, dateFrom, dateTo string) ([]time.Time, error) {
+	args := m.Called(ctx, source, charCode, dateFrom, dateTo)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
-	return args.Get(0).(*entity.Currency), args.Error(1)
+	return args.Get(0).([]time.Time), args.Error(1)
+}
+
+func (m *mockPostgresRepo) StoreCurrencies(ctx context.Context, currencies []entity.CurrencyInfo) error {
+	args := m.Called(ctx, currencies)
+	return args.Error(0)
+}
+
+func (m *mockPostgresRepo) GetCurrencies(ctx context.Context) ([]entity.CurrencyInfo, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]entity.CurrencyInfo), args.Error(1)
+}
+
+func (m *mockPostgresRepo) GetCurrencyByCharCode(ctx context.Context, charCode string) (*entity.CurrencyInfo, error) {
+	args := m.Called(ctx, charCode)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.CurrencyInfo), args.Error(1)
 }
 
 func setupTestService() (*RateService, *mockCbrClient, *mockPostgresRepo, *logrus.Logger, *test.Hook) {
@@ -61,9 +148,81 @@ func setupTestService() (*RateService, *mockCbrClient, *mockPostgresRepo, *logru
 	mockRepo := new(mockPostgresRepo)
 	logger, hook := test.NewNullLogger()
 	service := NewRateService(mockCbr, mockRepo, logger)
+	now := time.Now()
+	service.now = func() time.Time { return now }
 	return service, mockCbr, mockRepo, logger, hook
 }
 
+// cbrRates is what the CBR provider yields for resp, stamped by the service.
+func cbrRates(t *testing.T, resp *cbr.ValCurs, fetchedAt time.Time) []entity.Currency {
+	date, err := time.Parse("02.01.2006", resp.Date)
+	require.NoError(t, err)
+
+	var rates []entity.Currency
+	for _, v := range resp.Valutes {
+		value, err := v.GetValue()
+		require.NoError(t, err)
+		rates = append(rates, entity.Currency{
+			CharCode:  v.CharCode,
+			Name:      v.Name,
+			Nominal:   v.Nominal,
+			Value:     value,
+			NumCode:   v.NumCode,
+			UpdatedAt: fetchedAt,
+			Date:      date,
+			Source:    provider.SourceCBR,
+		})
+	}
+	return rates
+}
+
+type mockRateProvider struct {
+	mock.Mock
+	name string
+	base string
+}
+
+func (m *mockRateProvider) Name() string {
+	return m.name
+}
+
+func (m *mockRateProvider) BaseCurrency() string {
+	if m.base == "" {
+		return "EUR"
+	}
+	return m.base
+}
+
+func (m *mockRateProvider) Calendar() provider.Calendar {
+	return provider.TARGETCalendar()
+}
+
+func (m *mockRateProvider) FetchDaily(ctx context.Context, date time.Time) ([]entity.Currency, error) {
+	args := m.Called(ctx, date)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]entity.Currency), args.Error(1)
+}
+
+func (m *mockRateProvider) FetchRange(ctx context.Context, charCode string, from, to time.Time) ([]entity.Currency, error) {
+	args := m.Called(ctx, charCode, from, to)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]entity.Currency), args.Error(1)
+}
+
+func setupECBService() (*RateService, *mockRateProvider, *mockPostgresRepo) {
+	mockECB := &mockRateProvider{name: provider.SourceECB}
+	mockRepo := new(mockPostgresRepo)
+	logger, _ := test.NewNullLogger()
+	service := NewRateService(new(mockCbrClient), mockRepo, logger, mockECB)
+	now := time.Date(2025, 8, 4, 17, 0, 0, 0, time.UTC)
+	service.now = func() time.Time { return now }
+	return service, mockECB, mockRepo
+}
+
 func TestStoreRatesFromCbr(t *testing.T) {
 	ctx := context.Background()
 	service, mockCbr, mockRepo, _, _ := setupTestService()
@@ -79,14 +238,13 @@ func TestStoreRatesFromCbr(t *testing.T) {
 
 	mockCbr.On("FetchRates", ctx, dateStr).Return(sampleResp, nil)
 
-	rates, err := convertCBRResponse(*sampleResp)
-	require.NoError(t, err)
+	rates := cbrRates(t, sampleResp, service.now())
 
 	mockRepo.On("StoreRates", ctx, mock.MatchedBy(func(r []entity.Currency) bool {
 		return assert.ElementsMatch(t, rates, r)
 	})).Return(nil)
 
-	err = service.StoreRatesFromCbr(ctx)
+	err := service.StoreRatesFromCbr(ctx)
 	assert.NoError(t, err)
 
 	mockCbr.AssertExpectations(t)
@@ -103,6 +261,8 @@ func TestStoreRatesFromCbr_FetchError(t *testing.T) {
 
 	err := service.StoreRatesFromCbr(ctx)
 	assert.ErrorContains(t, err, expectedErr.Error())
+	assert.ErrorIs(t, err, expectedErr)
+	assert.ErrorIs(t, err, ErrUpstreamUnavailable)
 
 	mockCbr.AssertExpectations(t)
 }
@@ -121,15 +281,14 @@ func TestStoreRatesFromCbr_StoreError(t *testing.T) {
 
 	mockCbr.On("FetchRates", ctx, dateStr).Return(sampleResp, nil)
 
-	rates, err := convertCBRResponse(*sampleResp)
-	require.NoError(t, err)
+	rates := cbrRates(t, sampleResp, service.now())
 
 	expectedErr := errors.New("store error")
 	mockRepo.On("StoreRates", ctx, mock.MatchedBy(func(r []entity.Currency) bool {
 		return assert.ElementsMatch(t, rates, r)
 	})).Return(expectedErr)
 
-	err = service.StoreRatesFromCbr(ctx)
+	err := service.StoreRatesFromCbr(ctx)
 	assert.ErrorContains(t, err, expectedErr.Error())
 
 	mockCbr.AssertExpectations(t)
@@ -141,11 +300,11 @@ func TestGetRateByCharCode(t *testing.T) {
 	service, _, mockRepo, _, _ := setupTestService()
 
 	charCode := "usd"
-	expected := &entity.Currency{CharCode: "USD", Value: 90.5}
+	expected := &entity.Currency{CharCode: "USD", Value: decimal.RequireFromString("90.5")}
 
-	mockRepo.On("GetRateByCharCode", ctx, "USD").Return(expected, nil)
+	mockRepo.On("GetRateByCharCode", ctx, "cbr", "USD").Return(expected, nil)
 
-	result, err := service.GetRateByCharCode(ctx, charCode)
+	result, err := service.GetRateByCharCode(ctx, "cbr", charCode)
 	assert.NoError(t, err)
 	assert.Equal(t, expected, result)
 
@@ -158,10 +317,10 @@ func TestGetRateByCharCode_NotFound(t *testing.T) {
 
 	charCode := "USD"
 
-	mockRepo.On("GetRateByCharCode", ctx, charCode).Return((*entity.Currency)(nil), postgres.ErrNotFound)
+	mockRepo.On("GetRateByCharCode", ctx, "cbr", charCode).Return((*entity.Currency)(nil), postgres.ErrNotFound)
 
-	_, err := service.GetRateByCharCode(ctx, charCode)
-	assert.ErrorContains(t, err, "not found")
+	_, err := service.GetRateByCharCode(ctx, "cbr", charCode)
+	assert.ErrorIs(t, err, ErrRateNotFound)
 
 	mockRepo.AssertExpectations(t)
 }
@@ -170,9 +329,9 @@ func TestGetRateByCharCodeAndDate_FutureDate(t *testing.T) {
 	ctx := context.Background()
 	service, _, _, _, _ := setupTestService()
 
-	futureDate := time.Now().Add(24 * time.Hour)
-	_, err := service.GetRateByCharCodeAndDate(ctx, "USD", futureDate)
-	assert.ErrorContains(t, err, "cannot fetch rates for future dates")
+	futureDate := time.Now().Add(48 * time.Hour)
+	_, err := service.GetRateByCharCodeAndDate(ctx, "cbr", "USD", futureDate)
+	assert.ErrorIs(t, err, ErrFutureDate)
 }
 
 func TestGetRateByCharCodeAndDate_Today(t *testing.T) {
@@ -190,16 +349,16 @@ func TestGetRateByCharCodeAndDate_Today(t *testing.T) {
 		Date: today.Format("02.01.2006"),
 	}
 
+	mockRepo.On("GetRateByCharCodeAndDate", ctx, "cbr", "USD", today.Format("2006-01-02")).Return((*entity.Currency)(nil), postgres.ErrNotFound)
 	mockCbr.On("FetchRates", ctx, cbrDateStr).Return(sampleResp, nil)
 
-	rates, err := convertCBRResponse(*sampleResp)
-	require.NoError(t, err)
+	rates := cbrRates(t, sampleResp, service.now())
 
-	mockRepo.On("StoreHistoricalRates", ctx, today, mock.MatchedBy(func(r []entity.Currency) bool {
+	mockRepo.On("StoreRates", ctx, mock.MatchedBy(func(r []entity.Currency) bool {
 		return assert.ElementsMatch(t, rates, r)
 	})).Return(nil)
 
-	result, err := service.GetRateByCharCodeAndDate(ctx, charCode, today)
+	result, err := service.GetRateByCharCodeAndDate(ctx, "cbr", charCode, today)
 	assert.NoError(t, err)
 	assert.Equal(t, rates[0].Value, result.Value)
 
@@ -214,13 +373,15 @@ func TestGetRateByCharCodeAndDate_PastDate_FromDB(t *testing.T) {
 	charCode := "USD"
 	pastDate := time.Date(2025, 8, 1, 0, 0, 0, 0, time.UTC)
 	dateStr := pastDate.Format("2006-01-02")
-	expected := &entity.Currency{CharCode: "USD", Value: 90.5, Date: pastDate}
+	expected := &entity.Currency{CharCode: "USD", Value: decimal.RequireFromString("90.5"), Date: pastDate}
 
-	mockRepo.On("GetRateByCharCodeAndDate", ctx, "USD", dateStr).Return(expected, nil)
+	mockRepo.On("GetRateByCharCodeAndDate", ctx, "cbr", "USD", dateStr).Return(expected, nil)
 
-	result, err := service.GetRateByCharCodeAndDate(ctx, charCode, pastDate)
+	result, err := service.GetRateByCharCodeAndDate(ctx, "cbr", charCode, pastDate)
 	assert.NoError(t, err)
-	assert.Equal(t, expected, result)
+	assert.Equal(t, expected.Value, result.Value)
+	assert.Equal(t, pastDate, result.Date)
+	assert.Equal(t, pastDate, result.EffectiveDate)
 
 	mockRepo.AssertExpectations(t)
 }
@@ -234,7 +395,7 @@ func TestGetRateByCharCodeAndDate_PastDate_FetchFromCBR(t *testing.T) {
 	dateStr := pastDate.Format("2006-01-02")
 	cbrDateStr := pastDate.Format("02/01/2006")
 
-	mockRepo.On("GetRateByCharCodeAndDate", ctx, "USD", dateStr).Return((*entity.Currency)(nil), postgres.ErrNotFound)
+	mockRepo.On("GetRateByCharCodeAndDate", ctx, "cbr", "USD", dateStr).Return((*entity.Currency)(nil), postgres.ErrNotFound)
 
 	sampleResp := &cbr.ValCurs{
 		Valutes: []cbr.Valute{
@@ -245,14 +406,13 @@ func TestGetRateByCharCodeAndDate_PastDate_FetchFromCBR(t *testing.T) {
 
 	mockCbr.On("FetchRates", ctx, cbrDateStr).Return(sampleResp, nil)
 
-	rates, err := convertCBRResponse(*sampleResp)
-	require.NoError(t, err)
+	rates := cbrRates(t, sampleResp, service.now())
 
-	mockRepo.On("StoreHistoricalRates", ctx, pastDate, mock.MatchedBy(func(r []entity.Currency) bool {
+	mockRepo.On("StoreRates", ctx, mock.MatchedBy(func(r []entity.Currency) bool {
 		return assert.ElementsMatch(t, rates, r)
 	})).Return(nil)
 
-	result, err := service.GetRateByCharCodeAndDate(ctx, charCode, pastDate)
+	result, err := service.GetRateByCharCodeAndDate(ctx, "cbr", charCode, pastDate)
 	assert.NoError(t, err)
 	assert.Equal(t, rates[0].Value, result.Value)
 
@@ -269,7 +429,7 @@ func TestGetRateByCharCodeAndDate_PastDate_NotFoundInCBR(t *testing.T) {
 	dateStr := pastDate.Format("2006-01-02")
 	cbrDateStr := pastDate.Format("02/01/2006")
 
-	mockRepo.On("GetRateByCharCodeAndDate", ctx, "USD", dateStr).Return((*entity.Currency)(nil), postgres.ErrNotFound)
+	mockRepo.On("GetRateByCharCodeAndDate", ctx, "cbr", "USD", dateStr).Return((*entity.Currency)(nil), postgres.ErrNotFound)
 
 	sampleResp := &cbr.ValCurs{
 		Valutes: []cbr.Valute{
@@ -280,57 +440,999 @@ func TestGetRateByCharCodeAndDate_PastDate_NotFoundInCBR(t *testing.T) {
 
 	mockCbr.On("FetchRates", ctx, cbrDateStr).Return(sampleResp, nil)
 
-	rates, err := convertCBRResponse(*sampleResp)
-	require.NoError(t, err)
+	rates := cbrRates(t, sampleResp, service.now())
 
-	mockRepo.On("StoreHistoricalRates", ctx, pastDate, mock.MatchedBy(func(r []entity.Currency) bool {
+	mockRepo.On("StoreRates", ctx, mock.MatchedBy(func(r []entity.Currency) bool {
 		return assert.ElementsMatch(t, rates, r)
 	})).Return(nil)
 
-	_, err = service.GetRateByCharCodeAndDate(ctx, charCode, pastDate)
-	assert.ErrorContains(t, err, "not found")
+	_, err := service.GetRateByCharCodeAndDate(ctx, "cbr", charCode, pastDate)
+	assert.ErrorIs(t, err, ErrRateNotFound)
 
 	mockCbr.AssertExpectations(t)
 	mockRepo.AssertExpectations(t)
 }
 
-func TestConvertCBRResponse(t *testing.T) {
-	now := time.Now()
-	sampleResp := cbr.ValCurs{
-		Valutes: []cbr.Valute{
-			{CharCode: "USD", Name: "US Dollar", Nominal: 1, Value: "90,5", NumCode: "840"},
-			{CharCode: "EUR", Name: "Euro", Nominal: 1, Value: "100.2", NumCode: "978"},
-			{CharCode: "INVALID", Name: "Invalid", Nominal: 1, Value: "abc", NumCode: "000"},
-			{CharCode: "ZERO", Name: "Zero", Nominal: 1, Value: "0", NumCode: "000"},
-		},
-		Date: now.Format("02.01.2006"),
+func TestGetRatesByCharCodeAndDateRange_AllCached(t *testing.T) {
+	ctx := context.Background()
+	service, _, mockRepo, _, _ := setupTestService()
+
+	from := time.Date(2025, 8, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2025, 8, 2, 0, 0, 0, 0, time.UTC)
+	cached := []entity.Currency{
+		{CharCode: "USD", Nominal: 1, Value: decimal.RequireFromString("90.5"), Date: from},
+		{CharCode: "USD", Nominal: 1, Value: decimal.RequireFromString("91.0"), Date: to},
 	}
 
-	rates, err := convertCBRResponse(sampleResp)
+	mockRepo.On("GetRatesByCharCodeAndDateRange", ctx, "cbr", "USD", "2025-08-01", "2025-08-02").Return(cached, nil)
+
+	result, err := service.GetRatesByCharCodeAndDateRange(ctx, "cbr", "usd", from, to)
 	assert.NoError(t, err)
-	assert.Len(t, rates, 2)
+	assert.Equal(t, cached, result)
 
-	assert.Equal(t, "USD", rates[0].CharCode)
-	assert.Equal(t, 90.5, rates[0].Value)
-	assert.Equal(t, "EUR", rates[1].CharCode)
-	assert.Equal(t, 100.2, rates[1].Value)
+	mockRepo.AssertExpectations(t)
 }
 
-func TestConvertCBRResponse_NoValutes(t *testing.T) {
-	sampleResp := cbr.ValCurs{Valutes: []cbr.Valute{}}
-	rates, err := convertCBRResponse(sampleResp)
+func TestGetRatesByCharCodeAndDateRange_FillsGapsFromCBR(t *testing.T) {
+	ctx := context.Background()
+	service, mockCbr, mockRepo, _, _ := setupTestService()
+
+	day1 := time.Date(2025, 8, 1, 0, 0, 0, 0, time.UTC)
+	day2 := time.Date(2025, 8, 2, 0, 0, 0, 0, time.UTC)
+	day3 := time.Date(2025, 8, 3, 0, 0, 0, 0, time.UTC)
+	cached := []entity.Currency{
+		{CharCode: "USD", Name: "US Dollar", Nominal: 1, Value: decimal.RequireFromString("90.5"), NumCode: "840", Date: day1},
+	}
+
+	mockRepo.On("GetRatesByCharCodeAndDateRange", ctx, "cbr", "USD", "2025-08-01", "2025-08-03").Return(cached, nil)
+	mockRepo.On("GetRateGaps", ctx, "cbr", "USD", "2025-08-01", "2025-08-03").Return([]time.Time(nil), nil)
+
+	// Sunday is not a CBR publication day, so only Saturday is fetched
+	mockCbr.On("FetchRates", ctx, "02/08/2025").Return(&cbr.ValCurs{
+		Date: "02.08.2025",
+		Valutes: []cbr.Valute{
+			{ID: "R01239", CharCode: "EUR", Name: "Euro", Nominal: 1, Value: "100,2", NumCode: "978"},
+			{ID: "R01235", CharCode: "USD", Name: "US Dollar", Nominal: 1, Value: "91,0", NumCode: "840"},
+		},
+	}, nil)
+
+	mockCbr.On("FetchDynamicRates", ctx, "R01235", "02/08/2025", "02/08/2025").Return(&cbr.ValCursDynamic{
+		ID: "R01235",
+		Records: []cbr.Record{
+			{Date: "02.08.2025", ID: "R01235", Nominal: 1, Value: "91,0"},
+		},
+	}, nil)
+
+	expectedDay2 := entity.Currency{CharCode: "USD", Name: "US Dollar", Nominal: 1, Value: decimal.RequireFromString("91.0"), NumCode: "840", UpdatedAt: service.now(), Date: day2, Source: "cbr"}
+	mockRepo.On("StoreRates", ctx, []entity.Currency{expectedDay2}).Return(nil)
+
+	result, err := service.GetRatesByCharCodeAndDateRange(ctx, "cbr", "USD", day1, day3)
 	assert.NoError(t, err)
-	assert.Empty(t, rates)
+	assert.Equal(t, []entity.Currency{cached[0], expectedDay2}, result)
+
+	mockCbr.AssertExpectations(t)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestGetRatesByCharCodeAndDateRange_RemembersDaysWithoutRates(t *testing.T) {
+	ctx := context.Background()
+	service, mockCbr, mockRepo, _, _ := setupTestService()
+
+	// CBR publishes no rate for Wednesday, a day off its calendar does not know
+	tue := time.Date(2025, 8, 5, 0, 0, 0, 0, time.UTC)
+	wed := time.Date(2025, 8, 6, 0, 0, 0, 0, time.UTC)
+	thu := time.Date(2025, 8, 7, 0, 0, 0, 0, time.UTC)
+	cached := []entity.Currency{
+		{CharCode: "USD", Nominal: 1, Value: decimal.RequireFromString("79.9"), Date: tue, Source: "cbr"},
+		{CharCode: "USD", Nominal: 1, Value: decimal.RequireFromString("79.7"), Date: thu, Source: "cbr"},
+	}
+
+	mockRepo.On("GetRatesByCharCodeAndDateRange", ctx, "cbr", "USD", "2025-08-05", "2025-08-07").Return(cached, nil)
+	mockRepo.On("GetRateGaps", ctx, "cbr", "USD", "2025-08-05", "2025-08-07").Return([]time.Time(nil), nil).Once()
+	mockCbr.On("FetchRates", ctx, "06/08/2025").Return(&cbr.ValCurs{
+		Date:    "06.08.2025",
+		Valutes: []cbr.Valute{{ID: "R01235", CharCode: "USD", Name: "US Dollar", Nominal: 1, Value: "79,9", NumCode: "840"}},
+	}, nil).Once()
+	mockCbr.On("FetchDynamicRates", ctx, "R01235", "06/08/2025", "06/08/2025").Return(&cbr.ValCursDynamic{ID: "R01235"}, nil).Once()
+	mockRepo.On("StoreRateGaps", ctx, "cbr", "USD", []time.Time{wed}, service.now()).Return(nil).Once()
+
+	result, err := service.GetRatesByCharCodeAndDateRange(ctx, "cbr", "USD", tue, thu)
+	require.NoError(t, err)
+	assert.Equal(t, cached, result)
+
+	// the same range again is answered from the DB alone
+	mockRepo.On("GetRateGaps", ctx, "cbr", "USD", "2025-08-05", "2025-08-07").Return([]time.Time{wed}, nil).Once()
+
+	result, err = service.GetRatesByCharCodeAndDateRange(ctx, "cbr", "USD", tue, thu)
+	require.NoError(t, err)
+	assert.Equal(t, cached, result)
+
+	mockCbr.AssertExpectations(t)
+	mockCbr.AssertNumberOfCalls(t, "FetchRates", 1)
+	mockCbr.AssertNumberOfCalls(t, "FetchDynamicRates", 1)
+	mockRepo.AssertExpectations(t)
+	mockRepo.AssertNotCalled(t, "StoreRates", mock.Anything, mock.Anything)
+}
+
+func TestGetRatesByCharCodeAndDateRange_EvaluatesAlerts(t *testing.T) {
+	ctx := context.Background()
+	service, mockCbr, mockRepo, _, _ := setupTestService()
+	mockAlerts := new(mockRateAlerter)
+	service.SetAlerts(mockAlerts)
+
+	day := time.Date(2014, 3, 4, 0, 0, 0, 0, time.UTC)
+	mockRepo.On("GetRatesByCharCodeAndDateRange", ctx, "cbr", "USD", "2014-03-04", "2014-03-04").Return([]entity.Currency(nil), nil)
+	mockRepo.On("GetRateGaps", ctx, "cbr", "USD", "2014-03-04", "2014-03-04").Return([]time.Time(nil), nil)
+	mockCbr.On("FetchRates", ctx, "04/03/2014").Return(&cbr.ValCurs{
+		Date:    "04.03.2014",
+		Valutes: []cbr.Valute{{ID: "R01235", CharCode: "USD", Name: "US Dollar", Nominal: 1, Value: "36,1", NumCode: "840"}},
+	}, nil)
+	mockCbr.On("FetchDynamicRates", ctx, "R01235", "04/03/2014", "04/03/2014").Return(&cbr.ValCursDynamic{
+		ID:      "R01235",
+		Records: []cbr.Record{{Date: "04.03.2014", ID: "R01235", Nominal: 1, Value: "36,1"}},
+	}, nil)
+	mockRepo.On("StoreRates", ctx, mock.Anything).Return(nil)
+	mockAlerts.On("Evaluate", ctx, mock.MatchedBy(func(rates []entity.Currency) bool {
+		return len(rates) == 1 && rates[0].CharCode == "USD" && rates[0].Date.Equal(day)
+	})).Return(nil)
+
+	_, err := service.GetRatesByCharCodeAndDateRange(ctx, "cbr", "USD", day, day)
+	require.NoError(t, err)
+	mockRepo.AssertCalled(t, "StoreRates", ctx, mock.Anything)
+	mockAlerts.AssertExpectations(t)
 }
 
-func TestConvertCBRResponse_AllSkipped(t *testing.T) {
-	sampleResp := cbr.ValCurs{
+func TestGetRatesByCharCodeAndDateRange_UnknownCurrency(t *testing.T) {
+	ctx := context.Background()
+	service, mockCbr, mockRepo, _, _ := setupTestService()
+
+	day := time.Date(2025, 8, 1, 0, 0, 0, 0, time.UTC)
+
+	mockRepo.On("GetRatesByCharCodeAndDateRange", ctx, "cbr", "XXX", "2025-08-01", "2025-08-01").Return([]entity.Currency(nil), nil)
+	mockRepo.On("GetRateGaps", ctx, "cbr", "XXX", "2025-08-01", "2025-08-01").Return([]time.Time(nil), nil)
+	mockCbr.On("FetchRates", ctx, "01/08/2025").Return(&cbr.ValCurs{
+		Date: "01.08.2025",
 		Valutes: []cbr.Valute{
-			{CharCode: "INVALID", Name: "Invalid", Nominal: 1, Value: "abc", NumCode: "000"},
+			{ID: "R01235", CharCode: "USD", Name: "US Dollar", Nominal: 1, Value: "91,0", NumCode: "840"},
 		},
+	}, nil)
+
+	_, err := service.GetRatesByCharCodeAndDateRange(ctx, "cbr", "XXX", day, day)
+	assert.ErrorIs(t, err, ErrRateNotFound)
+
+	mockCbr.AssertExpectations(t)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestGetRatesByCharCodeAndDateRange_InvalidRange(t *testing.T) {
+	ctx := context.Background()
+	service, _, _, _, _ := setupTestService()
+
+	from := time.Date(2025, 8, 2, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2025, 8, 1, 0, 0, 0, 0, time.UTC)
+
+	_, err := service.GetRatesByCharCodeAndDateRange(ctx, "cbr", "USD", from, to)
+	assert.ErrorIs(t, err, ErrInvalidDateRange)
+}
+
+func TestGetRatesByCharCodeAndDateRange_FutureDate(t *testing.T) {
+	ctx := context.Background()
+	service, _, _, _, _ := setupTestService()
+
+	from := time.Now().Add(-24 * time.Hour)
+	to := time.Now().Add(48 * time.Hour)
+
+	_, err := service.GetRatesByCharCodeAndDateRange(ctx, "cbr", "USD", from, to)
+	assert.ErrorIs(t, err, ErrFutureDate)
+}
+
+func TestGetRateByCharCodeAndDate_PastDate_UpstreamDateMismatch(t *testing.T) {
+	ctx := context.Background()
+	service, mockCbr, mockRepo, _, _ := setupTestService()
+
+	pastDate := time.Date(2025, 8, 1, 0, 0, 0, 0, time.UTC)
+	dateStr := pastDate.Format("2006-01-02")
+	cbrDateStr := pastDate.Format("02/01/2006")
+
+	mockRepo.On("GetRateByCharCodeAndDate", ctx, "cbr", "USD", dateStr).Return((*entity.Currency)(nil), postgres.ErrNotFound)
+	mockCbr.On("FetchRates", ctx, cbrDateStr).Return(&cbr.ValCurs{
+		Valutes: []cbr.Valute{
+			{CharCode: "USD", Name: "US Dollar", Nominal: 1, Value: "90.5", NumCode: "840"},
+		},
+		Date: "05.08.2025",
+	}, nil)
+
+	_, err := service.GetRateByCharCodeAndDate(ctx, "cbr", "USD", pastDate)
+	assert.ErrorIs(t, err, ErrUpstreamDateMismatch)
+
+	var dateErr *UpstreamDateError
+	require.ErrorAs(t, err, &dateErr)
+	assert.Equal(t, pastDate, dateErr.Requested)
+	assert.Equal(t, time.Date(2025, 8, 5, 0, 0, 0, 0, time.UTC), dateErr.Returned)
+
+	mockCbr.AssertExpectations(t)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestGetRateByCharCodeAndDate_WeekendFromDB(t *testing.T) {
+	ctx := context.Background()
+	service, mockCbr, mockRepo, _, _ := setupTestService()
+	service.now = func() time.Time { return time.Date(2025, 8, 6, 12, 0, 0, 0, time.UTC) }
+
+	saturday := time.Date(2025, 8, 2, 0, 0, 0, 0, time.UTC)
+	monday := time.Date(2025, 8, 4, 0, 0, 0, 0, time.UTC)
+	stored := &entity.Currency{CharCode: "USD", Nominal: 1, Value: decimal.RequireFromString("79.7653"), Date: saturday, EffectiveDate: saturday, Source: "cbr"}
+
+	mockRepo.On("GetRateByCharCodeAndDate", ctx, "cbr", "USD", "2025-08-04").Return(stored, nil)
+
+	result, err := service.GetRateByCharCodeAndDate(ctx, "cbr", "USD", monday)
+	require.NoError(t, err)
+	assert.Equal(t, monday, result.Date)
+	assert.Equal(t, saturday, result.EffectiveDate)
+	assert.Equal(t, "79.7653", result.Value.String())
+
+	mockCbr.AssertNotCalled(t, "FetchRates", mock.Anything, mock.Anything)
+}
+
+func TestGetRateByCharCodeAndDate_StoredRateSuperseded(t *testing.T) {
+	ctx := context.Background()
+	service, mockCbr, mockRepo, _, _ := setupTestService()
+	service.now = func() time.Time { return time.Date(2025, 8, 6, 12, 0, 0, 0, time.UTC) }
+
+	saturday := time.Date(2025, 8, 2, 0, 0, 0, 0, time.UTC)
+	tuesday := time.Date(2025, 8, 5, 0, 0, 0, 0, time.UTC)
+	stored := &entity.Currency{CharCode: "USD", Nominal: 1, Value: decimal.RequireFromString("79.7653"), Date: saturday, EffectiveDate: saturday, Source: "cbr"}
+	sampleResp := &cbr.ValCurs{
+		Valutes: []cbr.Valute{{CharCode: "USD", Name: "US Dollar", Nominal: 1, Value: "80.0613", NumCode: "840"}},
+		Date:    "05.08.2025",
+	}
+
+	mockRepo.On("GetRateByCharCodeAndDate", ctx, "cbr", "USD", "2025-08-05").Return(stored, nil)
+	mockCbr.On("FetchRates", ctx, "05/08/2025").Return(sampleResp, nil)
+	mockRepo.On("StoreRates", ctx, cbrRates(t, sampleResp, service.now())).Return(nil)
+
+	result, err := service.GetRateByCharCodeAndDate(ctx, "cbr", "USD", tuesday)
+	require.NoError(t, err)
+	assert.Equal(t, tuesday, result.EffectiveDate)
+	assert.Equal(t, "80.0613", result.Value.String())
+
+	mockCbr.AssertExpectations(t)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestGetRateByCharCodeAndDate_HolidayKeepsEarlierPublication(t *testing.T) {
+	ctx := context.Background()
+	service, mockCbr, mockRepo, _, _ := setupTestService()
+	service.now = func() time.Time { return time.Date(2025, 1, 10, 12, 0, 0, 0, time.UTC) }
+
+	// no rates are set over the New Year holidays, so 8 January carries 1 January's;
+	// only that publication is stored
+	holiday := time.Date(2025, 1, 8, 0, 0, 0, 0, time.UTC)
+	published := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	sampleResp := &cbr.ValCurs{
+		Valutes: []cbr.Valute{{CharCode: "USD", Name: "US Dollar", Nominal: 1, Value: "101.6797", NumCode: "840"}},
+		Date:    "01.01.2025",
+	}
+	rates := cbrRates(t, sampleResp, service.now())
+
+	mockRepo.On("GetRateByCharCodeAndDate", ctx, "cbr", "USD", "2025-01-08").Return((*entity.Currency)(nil), postgres.ErrNotFound)
+	mockCbr.On("FetchRates", ctx, "08/01/2025").Return(sampleResp, nil)
+	mockRepo.On("StoreRates", ctx, rates).Return(nil)
+
+	result, err := service.GetRateByCharCodeAndDate(ctx, "cbr", "USD", holiday)
+	require.NoError(t, err)
+	assert.Equal(t, holiday, result.Date)
+	assert.Equal(t, published, result.EffectiveDate)
+
+	mockCbr.AssertExpectations(t)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestGetRateByCharCodeAndDate_TomorrowPublished(t *testing.T) {
+	ctx := context.Background()
+	service, mockCbr, mockRepo, _, _ := setupTestService()
+	service.now = func() time.Time { return time.Date(2025, 8, 5, 15, 0, 0, 0, time.UTC) }
+
+	tomorrow := time.Date(2025, 8, 6, 0, 0, 0, 0, time.UTC)
+	sampleResp := &cbr.ValCurs{
+		Valutes: []cbr.Valute{{CharCode: "USD", Name: "US Dollar", Nominal: 1, Value: "79.9282", NumCode: "840"}},
+		Date:    "06.08.2025",
 	}
 
-	rates, err := convertCBRResponse(sampleResp)
+	mockRepo.On("GetRateByCharCodeAndDate", ctx, "cbr", "USD", "2025-08-06").Return((*entity.Currency)(nil), postgres.ErrNotFound)
+	mockCbr.On("FetchRates", ctx, "06/08/2025").Return(sampleResp, nil)
+	mockRepo.On("StoreRates", ctx, cbrRates(t, sampleResp, service.now())).Return(nil)
+
+	result, err := service.GetRateByCharCodeAndDate(ctx, "cbr", "USD", tomorrow)
+	require.NoError(t, err)
+	assert.Equal(t, tomorrow, result.EffectiveDate)
+	assert.Equal(t, "79.9282", result.Value.String())
+
+	mockRepo.AssertExpectations(t)
+}
+
+func TestGetRateByCharCodeAndDate_TomorrowNotPublishedYet(t *testing.T) {
+	ctx := context.Background()
+	service, mockCbr, mockRepo, _, _ := setupTestService()
+	service.now = func() time.Time { return time.Date(2025, 8, 5, 9, 0, 0, 0, time.UTC) }
+
+	sampleResp := &cbr.ValCurs{
+		Valutes: []cbr.Valute{{CharCode: "USD", Name: "US Dollar", Nominal: 1, Value: "80.0613", NumCode: "840"}},
+		Date:    "05.08.2025",
+	}
+
+	mockRepo.On("GetRateByCharCodeAndDate", ctx, "cbr", "USD", "2025-08-06").Return((*entity.Currency)(nil), postgres.ErrNotFound)
+	mockCbr.On("FetchRates", ctx, "06/08/2025").Return(sampleResp, nil)
+
+	_, err := service.GetRateByCharCodeAndDate(ctx, "cbr", "USD", time.Date(2025, 8, 6, 0, 0, 0, 0, time.UTC))
+	assert.ErrorIs(t, err, ErrFutureDate)
+	mockRepo.AssertNotCalled(t, "StoreRates", mock.Anything, mock.Anything)
+}
+
+func TestGetRateByCharCodeAndDate_ECBTomorrowIsFuture(t *testing.T) {
+	service, _, _ := setupECBService()
+
+	_, err := service.GetRateByCharCodeAndDate(context.Background(), "ecb", "USD", time.Date(2025, 8, 5, 0, 0, 0, 0, time.UTC))
+	assert.ErrorIs(t, err, ErrFutureDate)
+}
+
+func setupFallbackService(t *testing.T) (*RateService, *mockCbrClient, *mockRateProvider, *mockPostgresRepo) {
+	mockCbr := new(mockCbrClient)
+	mockMirror := &mockRateProvider{name: provider.SourceCBRMirror, base: "RUB"}
+	mockRepo := new(mockPostgresRepo)
+	logger, _ := test.NewNullLogger()
+	service := NewRateService(mockCbr, mockRepo, logger, mockMirror)
+	require.NoError(t, service.SetFallbacks(map[string][]string{"cbr": {"cbr_mirror"}}))
+	now := time.Date(2025, 8, 4, 12, 0, 0, 0, time.UTC)
+	service.now = func() time.Time { return now }
+	return service, mockCbr, mockMirror, mockRepo
+}
+
+func TestSetFallbacks_Validation(t *testing.T) {
+	service, _, _ := setupECBService()
+
+	err := service.SetFallbacks(map[string][]string{"cbr": {"ecb"}})
+	assert.ErrorContains(t, err, "quotes against EUR")
+
+	err = service.SetFallbacks(map[string][]string{"cbr": {"nbk"}})
+	assert.ErrorIs(t, err, ErrUnknownSource)
+
+	err = service.SetFallbacks(map[string][]string{"cbr": {"cbr"}})
 	assert.Error(t, err)
-	assert.Empty(t, rates)
+}
+
+func TestGetRateByCharCodeAndDate_FallsBackToMirror(t *testing.T) {
+	ctx := context.Background()
+	service, mockCbr, mockMirror, mockRepo := setupFallbackService(t)
+
+	day := time.Date(2025, 8, 1, 0, 0, 0, 0, time.UTC)
+	mirrored := entity.Currency{CharCode: "USD", Name: "Доллар США", Nominal: 1, Value: decimal.RequireFromString("79.7653"), Date: day, Source: provider.SourceCBRMirror}
+
+	mockRepo.On("GetRateByCharCodeAndDate", ctx, "cbr", "USD", "2025-08-01").Return((*entity.Currency)(nil), postgres.ErrNotFound)
+	mockCbr.On("FetchRates", ctx, "01/08/2025").Return((*cbr.ValCurs)(nil), errors.New("connection refused"))
+	mockMirror.On("FetchDaily", ctx, day).Return([]entity.Currency{mirrored}, nil)
+	mirrored.UpdatedAt = service.now()
+	mockRepo.On("StoreRates", ctx, []entity.Currency{mirrored}).Return(nil)
+
+	rate, err := service.GetRateByCharCodeAndDate(ctx, "cbr", "USD", day)
+	require.NoError(t, err)
+	assert.Equal(t, provider.SourceCBRMirror, rate.Source)
+	assert.Equal(t, "79.7653", rate.Value.String())
+
+	mockCbr.AssertExpectations(t)
+	mockMirror.AssertExpectations(t)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestStoreRatesFromProvider_MirrorEvaluatesRequestedSourceAlerts(t *testing.T) {
+	ctx := context.Background()
+	service, mockCbr, mockMirror, mockRepo := setupFallbackService(t)
+	mockAlerts := new(mockRateAlerter)
+	service.SetAlerts(mockAlerts)
+
+	day := time.Date(2025, 8, 5, 0, 0, 0, 0, time.UTC)
+	mirrored := entity.Currency{CharCode: "USD", Name: "Доллар США", Nominal: 1, Value: decimal.RequireFromString("79.7653"), Date: day, Source: provider.SourceCBRMirror}
+
+	mockCbr.On("FetchRates", ctx, "04/08/2025").Return((*cbr.ValCurs)(nil), errors.New("connection refused"))
+	mockMirror.On("FetchDaily", ctx, service.now()).Return([]entity.Currency{mirrored}, nil)
+	mirrored.UpdatedAt = service.now()
+	mockRepo.On("StoreRates", ctx, []entity.Currency{mirrored}).Return(nil)
+	// stored as the mirror's, compared against the rules for cbr
+	evaluated := mirrored
+	evaluated.Source = provider.SourceCBR
+	mockAlerts.On("Evaluate", ctx, []entity.Currency{evaluated}).Return(nil)
+
+	require.NoError(t, service.StoreRatesFromProvider(ctx, "cbr"))
+	mockRepo.AssertExpectations(t)
+	mockAlerts.AssertExpectations(t)
+}
+
+func TestStoreRatesFromProvider_AllSourcesFail(t *testing.T) {
+	ctx := context.Background()
+	service, mockCbr, mockMirror, mockRepo := setupFallbackService(t)
+
+	mockCbr.On("FetchRates", ctx, "04/08/2025").Return((*cbr.ValCurs)(nil), errors.New("connection refused"))
+	mockMirror.On("FetchDaily", ctx, service.now()).Return(nil, errors.New("mirror is down"))
+
+	err := service.StoreRatesFromProvider(ctx, "cbr")
+	assert.ErrorIs(t, err, ErrUpstreamUnavailable)
+	assert.ErrorContains(t, err, "mirror is down")
+	mockRepo.AssertNotCalled(t, "StoreRates", mock.Anything, mock.Anything)
+}
+
+func TestGetRatesByCharCodeAndDateRange_NotQuotedDoesNotFallBack(t *testing.T) {
+	ctx := context.Background()
+	service, mockCbr, mockMirror, mockRepo := setupFallbackService(t)
+
+	day := time.Date(2025, 8, 1, 0, 0, 0, 0, time.UTC)
+	mockRepo.On("GetRatesByCharCodeAndDateRange", ctx, "cbr", "XAU", "2025-08-01", "2025-08-01").Return([]entity.Currency(nil), nil)
+	mockRepo.On("GetRateGaps", ctx, "cbr", "XAU", "2025-08-01", "2025-08-01").Return([]time.Time(nil), nil)
+	mockCbr.On("FetchRates", ctx, "01/08/2025").Return(&cbr.ValCurs{Date: "01.08.2025", Valutes: []cbr.Valute{{ID: "R01235", CharCode: "USD", Nominal: 1, Value: "79,7653"}}}, nil)
+
+	_, err := service.GetRatesByCharCodeAndDateRange(ctx, "cbr", "XAU", day, day)
+	assert.ErrorIs(t, err, ErrRateNotFound)
+	mockMirror.AssertNotCalled(t, "FetchRange", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestGetRateByCharCodeAndDate_PastDate_UpstreamUnavailable(t *testing.T) {
+	ctx := context.Background()
+	service, mockCbr, mockRepo, _, _ := setupTestService()
+
+	pastDate := time.Date(2025, 8, 1, 0, 0, 0, 0, time.UTC)
+
+	mockRepo.On("GetRateByCharCodeAndDate", ctx, "cbr", "USD", "2025-08-01").Return((*entity.Currency)(nil), postgres.ErrNotFound)
+	mockCbr.On("FetchRates", ctx, "01/08/2025").Return((*cbr.ValCurs)(nil), errors.New("connection refused"))
+
+	_, err := service.GetRateByCharCodeAndDate(ctx, "cbr", "USD", pastDate)
+	assert.ErrorIs(t, err, ErrUpstreamUnavailable)
+
+	mockCbr.AssertExpectations(t)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestGetRateByCharCodeAndDate_NoDataIsNotFound(t *testing.T) {
+	ctx := context.Background()
+	service, mockCbr, mockRepo, _, _ := setupTestService()
+
+	pastDate := time.Date(1991, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	mockRepo.On("GetRateByCharCodeAndDate", ctx, "cbr", "USD", "1991-01-01").Return((*entity.Currency)(nil), postgres.ErrNotFound)
+	mockCbr.On("FetchRates", ctx, "01/01/1991").Return((*cbr.ValCurs)(nil), fmt.Errorf("%w: no Valute elements for 01.01.1991", cbr.ErrNoData))
+
+	_, err := service.GetRateByCharCodeAndDate(ctx, "cbr", "USD", pastDate)
+	assert.ErrorIs(t, err, ErrRateNotFound)
+	assert.NotErrorIs(t, err, ErrUpstreamUnavailable)
+}
+
+func TestGetRatesByCharCodeAndDateRange_NoDataIsNotFound(t *testing.T) {
+	ctx := context.Background()
+	service, mockCbr, mockRepo, _, _ := setupTestService()
+
+	day := time.Date(1991, 2, 6, 0, 0, 0, 0, time.UTC)
+	mockRepo.On("GetRatesByCharCodeAndDateRange", ctx, "cbr", "USD", "1991-02-06", "1991-02-06").Return([]entity.Currency(nil), nil)
+	mockRepo.On("GetRateGaps", ctx, "cbr", "USD", "1991-02-06", "1991-02-06").Return([]time.Time(nil), nil)
+	mockCbr.On("FetchRates", ctx, "06/02/1991").Return((*cbr.ValCurs)(nil), fmt.Errorf("%w: no Valute elements for 06.02.1991", cbr.ErrNoData))
+
+	_, err := service.GetRatesByCharCodeAndDateRange(ctx, "cbr", "USD", day, day)
+	assert.ErrorIs(t, err, ErrRateNotFound)
+	assert.NotErrorIs(t, err, ErrUpstreamUnavailable)
+}
+
+func TestSyncCurrencyCatalog(t *testing.T) {
+	ctx := context.Background()
+	service, mockCbr, mockRepo, _, _ := setupTestService()
+
+	daily := &cbr.Valuta{Items: []cbr.ValutaItem{
+		{ID: "R01235", Name: "Доллар США", EngName: "US Dollar", Nominal: 1, ParentCode: "R01235    ", ISONumCode: "840", ISOCharCode: "USD"},
+		{ID: "R01436", Name: "Литовский лит", EngName: "Lithuanian Lita", Nominal: 1, ParentCode: "R01435    "},
+	}}
+	monthly := &cbr.Valuta{Items: []cbr.ValutaItem{
+		{ID: "R01235", Name: "Доллар США", EngName: "US Dollar", Nominal: 1, ParentCode: "R01235    ", ISONumCode: "840", ISOCharCode: "USD"},
+		{ID: "R01010", Name: "Австралийский доллар", EngName: "Australian Dollar", Nominal: 1, ParentCode: "R01010    ", ISONumCode: "36", ISOCharCode: "AUD"},
+	}}
+	mockCbr.On("FetchCurrencyCatalog", ctx, false).Return(daily, nil)
+	mockCbr.On("FetchCurrencyCatalog", ctx, true).Return(monthly, nil)
+
+	expected := []entity.CurrencyInfo{
+		{ID: "R01235", CharCode: "USD", NumCode: "840", Name: "Доллар США", EngName: "US Dollar", Nominal: 1, ParentCode: "R01235", UpdatedAt: service.now()},
+		{ID: "R01010", CharCode: "AUD", NumCode: "36", Name: "Австралийский доллар", EngName: "Australian Dollar", Nominal: 1, ParentCode: "R01010", Monthly: true, UpdatedAt: service.now()},
+	}
+	mockRepo.On("StoreCurrencies", ctx, expected).Return(nil)
+
+	err := service.SyncCurrencyCatalog(ctx)
+	assert.NoError(t, err)
+
+	mockCbr.AssertExpectations(t)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestSyncCurrencyCatalog_FetchError(t *testing.T) {
+	ctx := context.Background()
+	service, mockCbr, mockRepo, _, _ := setupTestService()
+
+	mockCbr.On("FetchCurrencyCatalog", ctx, false).Return((*cbr.Valuta)(nil), errors.New("timeout"))
+
+	err := service.SyncCurrencyCatalog(ctx)
+	assert.ErrorIs(t, err, ErrUpstreamUnavailable)
+	mockRepo.AssertNotCalled(t, "StoreCurrencies", mock.Anything, mock.Anything)
+}
+
+func TestGetCurrencyCatalog_LoadsWhenEmpty(t *testing.T) {
+	ctx := context.Background()
+	service, mockCbr, mockRepo, _, _ := setupTestService()
+
+	catalog := []entity.CurrencyInfo{{ID: "R01235", CharCode: "USD"}}
+	mockRepo.On("GetCurrencies", ctx).Return([]entity.CurrencyInfo(nil), nil).Once()
+	mockCbr.On("FetchCurrencyCatalog", ctx, false).Return(&cbr.Valuta{Items: []cbr.ValutaItem{{ID: "R01235", ISOCharCode: "USD", Nominal: 1}}}, nil)
+	mockCbr.On("FetchCurrencyCatalog", ctx, true).Return(&cbr.Valuta{}, nil)
+	mockRepo.On("StoreCurrencies", ctx, mock.Anything).Return(nil)
+	mockRepo.On("GetCurrencies", ctx).Return(catalog, nil).Once()
+
+	result, err := service.GetCurrencyCatalog(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, catalog, result)
+
+	mockCbr.AssertExpectations(t)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestGetCurrencyByCharCode(t *testing.T) {
+	ctx := context.Background()
+	service, _, mockRepo, _, _ := setupTestService()
+
+	expected := &entity.CurrencyInfo{ID: "R01235", CharCode: "USD"}
+	mockRepo.On("GetCurrencyByCharCode", ctx, "USD").Return(expected, nil)
+
+	result, err := service.GetCurrencyByCharCode(ctx, "usd")
+	require.NoError(t, err)
+	assert.Equal(t, expected, result)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestGetCurrencyByCharCode_Unknown(t *testing.T) {
+	ctx := context.Background()
+	service, mockCbr, mockRepo, _, _ := setupTestService()
+
+	mockRepo.On("GetCurrencyByCharCode", ctx, "XYZ").Return(nil, postgres.ErrNotFound)
+	mockRepo.On("GetCurrencies", ctx).Return([]entity.CurrencyInfo{{ID: "R01235", CharCode: "USD"}}, nil)
+
+	result, err := service.GetCurrencyByCharCode(ctx, "XYZ")
+	assert.Nil(t, result)
+	assert.ErrorIs(t, err, ErrUnknownCurrency)
+	mockCbr.AssertNotCalled(t, "FetchCurrencyCatalog", mock.Anything, mock.Anything)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestGetCurrencyByCharCode_SyncsEmptyCatalog(t *testing.T) {
+	ctx := context.Background()
+	service, mockCbr, mockRepo, _, _ := setupTestService()
+
+	expected := &entity.CurrencyInfo{ID: "R01235", CharCode: "USD"}
+	mockRepo.On("GetCurrencyByCharCode", ctx, "USD").Return(nil, postgres.ErrNotFound).Once()
+	mockRepo.On("GetCurrencies", ctx).Return([]entity.CurrencyInfo(nil), nil)
+	mockCbr.On("FetchCurrencyCatalog", ctx, false).Return(&cbr.Valuta{Items: []cbr.ValutaItem{{ID: "R01235", ISOCharCode: "USD", Nominal: 1}}}, nil)
+	mockCbr.On("FetchCurrencyCatalog", ctx, true).Return(&cbr.Valuta{}, nil)
+	mockRepo.On("StoreCurrencies", ctx, mock.Anything).Return(nil)
+	mockRepo.On("GetCurrencyByCharCode", ctx, "USD").Return(expected, nil).Once()
+
+	result, err := service.GetCurrencyByCharCode(ctx, "USD")
+	require.NoError(t, err)
+	assert.Equal(t, expected, result)
+	mockCbr.AssertExpectations(t)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestBaseCurrency(t *testing.T) {
+	service, _, _ := setupECBService()
+
+	base, err := service.BaseCurrency("")
+	require.NoError(t, err)
+	assert.Equal(t, "RUB", base)
+
+	base, err = service.BaseCurrency("ECB")
+	require.NoError(t, err)
+	assert.Equal(t, "EUR", base)
+
+	_, err = service.BaseCurrency("nbk")
+	assert.ErrorIs(t, err, ErrUnknownSource)
+}
+
+func TestStoreRatesFromProvider_ECB(t *testing.T) {
+	ctx := context.Background()
+	service, mockECB, mockRepo := setupECBService()
+
+	day := time.Date(2025, 8, 4, 0, 0, 0, 0, time.UTC)
+	mockECB.On("FetchDaily", ctx, service.now()).Return([]entity.Currency{
+		{CharCode: "USD", Name: "USD", Nominal: 1, Value: decimal.RequireFromString("0.8769"), Date: day, Source: provider.SourceECB},
+	}, nil)
+	mockRepo.On("StoreRates", ctx, []entity.Currency{
+		{CharCode: "USD", Name: "USD", Nominal: 1, Value: decimal.RequireFromString("0.8769"), Date: day, Source: provider.SourceECB, UpdatedAt: service.now()},
+	}).Return(nil)
+
+	err := service.StoreRatesFromProvider(ctx, "ecb")
+	require.NoError(t, err)
+
+	mockECB.AssertExpectations(t)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestStoreRatesFromProvider_UnknownSource(t *testing.T) {
+	service, _, mockRepo := setupECBService()
+
+	err := service.StoreRatesFromProvider(context.Background(), "nbk")
+	assert.ErrorIs(t, err, ErrUnknownSource)
+	mockRepo.AssertNotCalled(t, "StoreRates", mock.Anything, mock.Anything)
+}
+
+func TestGetRatesByCharCodeAndDateRange_ECBUsesTARGETCalendar(t *testing.T) {
+	ctx := context.Background()
+	service, mockECB, mockRepo := setupECBService()
+
+	fri := time.Date(2025, 8, 1, 0, 0, 0, 0, time.UTC)
+	mon := time.Date(2025, 8, 4, 0, 0, 0, 0, time.UTC)
+	cached := []entity.Currency{
+		{CharCode: "USD", Nominal: 1, Value: decimal.RequireFromString("0.8769"), Date: fri, Source: provider.SourceECB},
+	}
+	fetched := entity.Currency{CharCode: "USD", Nominal: 1, Value: decimal.RequireFromString("0.8650"), Date: mon, Source: provider.SourceECB}
+
+	mockRepo.On("GetRatesByCharCodeAndDateRange", ctx, "ecb", "USD", "2025-08-01", "2025-08-04").Return(cached, nil)
+	mockRepo.On("GetRateGaps", ctx, "ecb", "USD", "2025-08-01", "2025-08-04").Return([]time.Time(nil), nil)
+	mockECB.On("FetchRange", ctx, "USD", mon, mon).Return([]entity.Currency{fetched}, nil)
+	fetched.UpdatedAt = service.now()
+	mockRepo.On("StoreRates", ctx, []entity.Currency{fetched}).Return(nil)
+
+	result, err := service.GetRatesByCharCodeAndDateRange(ctx, "ecb", "USD", fri, mon)
+	require.NoError(t, err)
+	assert.Equal(t, []entity.Currency{cached[0], fetched}, result)
+
+	mockECB.AssertExpectations(t)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestGetRatesByCharCodeAndDateRange_NotQuoted(t *testing.T) {
+	ctx := context.Background()
+	service, mockECB, mockRepo := setupECBService()
+
+	day := time.Date(2025, 8, 1, 0, 0, 0, 0, time.UTC)
+	mockRepo.On("GetRatesByCharCodeAndDateRange", ctx, "ecb", "RUB", "2025-08-01", "2025-08-01").Return([]entity.Currency(nil), nil)
+	mockRepo.On("GetRateGaps", ctx, "ecb", "RUB", "2025-08-01", "2025-08-01").Return([]time.Time(nil), nil)
+	mockECB.On("FetchRange", ctx, "RUB", day, day).Return(nil, provider.ErrCurrencyNotQuoted)
+
+	_, err := service.GetRatesByCharCodeAndDateRange(ctx, "ecb", "RUB", day, day)
+	assert.ErrorIs(t, err, ErrRateNotFound)
+}
+
+type mockRateAlerter struct {
+	mock.Mock
+}
+
+func (m *mockRateAlerter) Evaluate(ctx context.Context, rates []entity.Currency) error {
+	args := m.Called(ctx, rates)
+	return args.Error(0)
+}
+
+func TestStoreRatesFromCbr_EvaluatesAlerts(t *testing.T) {
+	ctx := context.Background()
+	service, mockCbr, mockRepo, _, _ := setupTestService()
+	mockAlerts := new(mockRateAlerter)
+	service.SetAlerts(mockAlerts)
+
+	sampleResp := &cbr.ValCurs{
+		Valutes: []cbr.Valute{{CharCode: "USD", Name: "US Dollar", Nominal: 1, Value: "90.5", NumCode: "840"}},
+		Date:    time.Now().Format("02.01.2006"),
+	}
+	mockCbr.On("FetchRates", ctx, time.Now().Format("02/01/2006")).Return(sampleResp, nil)
+	rates := cbrRates(t, sampleResp, service.now())
+	mockRepo.On("StoreRates", ctx, rates).Return(nil)
+	// a failed evaluation does not fail the store
+	mockAlerts.On("Evaluate", ctx, rates).Return(errors.New("rules unavailable"))
+
+	require.NoError(t, service.StoreRatesFromCbr(ctx))
+	mockAlerts.AssertExpectations(t)
+}
+
+func TestStoreRatesFromCbr_StoreErrorSkipsAlerts(t *testing.T) {
+	ctx := context.Background()
+	service, mockCbr, mockRepo, _, _ := setupTestService()
+	mockAlerts := new(mockRateAlerter)
+	service.SetAlerts(mockAlerts)
+
+	sampleResp := &cbr.ValCurs{
+		Valutes: []cbr.Valute{{CharCode: "USD", Name: "US Dollar", Nominal: 1, Value: "90.5", NumCode: "840"}},
+		Date:    time.Now().Format("02.01.2006"),
+	}
+	mockCbr.On("FetchRates", ctx, time.Now().Format("02/01/2006")).Return(sampleResp, nil)
+	mockRepo.On("StoreRates", ctx, mock.Anything).Return(errors.New("store error"))
+
+	assert.Error(t, service.StoreRatesFromCbr(ctx))
+	mockAlerts.AssertNotCalled(t, "Evaluate", mock.Anything, mock.Anything)
+}
+
+func TestGetRatesByCharCodesAndDate_AllFromDB(t *testing.T) {
+	ctx := context.Background()
+	service, mockCbr, mockRepo, _, _ := setupTestService()
+
+	// Sunday resolves to Saturday's publication
+	sunday := time.Date(2025, 8, 3, 0, 0, 0, 0, time.UTC)
+	saturday := time.Date(2025, 8, 2, 0, 0, 0, 0, time.UTC)
+	mockRepo.On("GetRatesByCharCodesAndDate", ctx, "cbr", []string{"EUR", "USD"}, "2025-08-03").Return([]entity.Currency{
+		{CharCode: "EUR", Nominal: 1, Value: decimal.RequireFromString("92.6"), Date: saturday},
+		{CharCode: "USD", Nominal: 1, Value: decimal.RequireFromString("79.7653"), Date: saturday},
+	}, nil)
+
+	result, err := service.GetRatesByCharCodesAndDate(ctx, "cbr", []string{"eur", "USD"}, sunday)
+	require.NoError(t, err)
+	require.Len(t, result, 2)
+	assert.Equal(t, sunday, result["USD"].Date)
+	assert.Equal(t, saturday, result["USD"].EffectiveDate)
+
+	mockCbr.AssertNotCalled(t, "FetchRates", mock.Anything, mock.Anything)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestGetRatesByCharCodesAndDate_FetchesMissingOnce(t *testing.T) {
+	ctx := context.Background()
+	service, mockCbr, mockRepo, _, _ := setupTestService()
+
+	date := time.Date(2025, 8, 1, 0, 0, 0, 0, time.UTC)
+	mockRepo.On("GetRatesByCharCodesAndDate", ctx, "cbr", []string{"EUR", "USD", "XYZ"}, "2025-08-01").Return([]entity.Currency{
+		{CharCode: "EUR", Nominal: 1, Value: decimal.RequireFromString("92.6"), Date: date},
+	}, nil)
+
+	sampleResp := &cbr.ValCurs{
+		Valutes: []cbr.Valute{
+			{CharCode: "USD", Name: "US Dollar", Nominal: 1, Value: "90.5", NumCode: "840"},
+			{CharCode: "EUR", Name: "Euro", Nominal: 1, Value: "100.2", NumCode: "978"},
+			{CharCode: "JPY", Name: "Yen", Nominal: 100, Value: "55.1", NumCode: "392"},
+		},
+		Date: date.Format("02.01.2006"),
+	}
+	mockCbr.On("FetchRates", ctx, date.Format("02/01/2006")).Return(sampleResp, nil).Once()
+	mockRepo.On("StoreRates", ctx, mock.MatchedBy(func(r []entity.Currency) bool { return len(r) == 3 })).Return(nil).Once()
+
+	result, err := service.GetRatesByCharCodesAndDate(ctx, "cbr", []string{"EUR", "USD", "XYZ"}, date)
+	require.NoError(t, err)
+	assert.Len(t, result, 2)
+	assert.Equal(t, "90.5", result["USD"].Value.String())
+	assert.Equal(t, "100.2", result["EUR"].Value.String())
+	assert.NotContains(t, result, "JPY")
+	assert.NotContains(t, result, "XYZ")
+
+	mockCbr.AssertExpectations(t)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestGetRatesByCharCodesAndDate_FutureDate(t *testing.T) {
+	ctx := context.Background()
+	service, _, mockRepo, _, _ := setupTestService()
+
+	_, err := service.GetRatesByCharCodesAndDate(ctx, "cbr", []string{"USD"}, time.Now().Add(48*time.Hour))
+	assert.ErrorIs(t, err, ErrFutureDate)
+	mockRepo.AssertNotCalled(t, "GetRatesByCharCodesAndDate", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestGetRatesByCharCodesAndDate_UpstreamUnavailable(t *testing.T) {
+	ctx := context.Background()
+	service, mockCbr, mockRepo, _, _ := setupTestService()
+
+	date := time.Date(2025, 8, 1, 0, 0, 0, 0, time.UTC)
+	mockRepo.On("GetRatesByCharCodesAndDate", ctx, "cbr", []string{"USD"}, "2025-08-01").Return([]entity.Currency{}, nil)
+	mockCbr.On("FetchRates", ctx, date.Format("02/01/2006")).Return((*cbr.ValCurs)(nil), errors.New("connection refused"))
+
+	_, err := service.GetRatesByCharCodesAndDate(ctx, "cbr", []string{"USD"}, date)
+	assert.ErrorIs(t, err, ErrUpstreamUnavailable)
+	mockRepo.AssertNotCalled(t, "StoreRates", mock.Anything, mock.Anything)
+}
+
+func TestGetRateStats_BackfillsBeforeAggregating(t *testing.T) {
+	ctx := context.Background()
+	service, mockCbr, mockRepo, _, _ := setupTestService()
+
+	// Friday to Monday: CBR publishes on Friday and Saturday only
+	from := time.Date(2025, 8, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2025, 8, 4, 0, 0, 0, 0, time.UTC)
+	saturday := time.Date(2025, 8, 2, 0, 0, 0, 0, time.UTC)
+	mockRepo.On("GetRatesByCharCodeAndDateRange", ctx, "cbr", "USD", "2025-08-01", "2025-08-04").Return([]entity.Currency{
+		{CharCode: "USD", Nominal: 1, Value: decimal.RequireFromString("79.5"), Date: from},
+	}, nil).Once()
+	mockRepo.On("GetRateGaps", ctx, "cbr", "USD", "2025-08-01", "2025-08-04").Return([]time.Time(nil), nil)
+
+	mockCbr.On("FetchRates", ctx, "02/08/2025").Return(&cbr.ValCurs{
+		Date: "02.08.2025",
+		Valutes: []cbr.Valute{
+			{ID: "R01235", CharCode: "USD", Name: "US Dollar", Nominal: 1, Value: "79,7653", NumCode: "840"},
+		},
+	}, nil)
+	mockCbr.On("FetchDynamicRates", ctx, "R01235", "02/08/2025", "02/08/2025").Return(&cbr.ValCursDynamic{
+		ID: "R01235",
+		Records: []cbr.Record{
+			{Date: "02.08.2025", ID: "R01235", Nominal: 1, Value: "79,7653"},
+		},
+	}, nil).Once()
+	mockRepo.On("StoreRates", ctx, mock.MatchedBy(func(r []entity.Currency) bool {
+		return len(r) == 1 && r[0].Date.Equal(saturday)
+	})).Return(nil).Once()
+
+	expected := &entity.RateStats{Source: "cbr", CharCode: "USD", Count: 2}
+	mockRepo.On("GetRateStats", ctx, "cbr", "USD", "2025-08-01", "2025-08-04").Return(expected, nil).Once()
+
+	stats, err := service.GetRateStats(ctx, "cbr", "usd", from, to)
+	require.NoError(t, err)
+	assert.Equal(t, expected, stats)
+
+	mockCbr.AssertExpectations(t)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestGetRateStats_NoRates(t *testing.T) {
+	ctx := context.Background()
+	service, _, mockRepo, _, _ := setupTestService()
+
+	from := time.Date(2025, 8, 2, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2025, 8, 2, 0, 0, 0, 0, time.UTC)
+	mockRepo.On("GetRatesByCharCodeAndDateRange", ctx, "cbr", "USD", "2025-08-02", "2025-08-02").Return([]entity.Currency{
+		{CharCode: "USD", Nominal: 1, Value: decimal.RequireFromString("79.7653"), Date: from},
+	}, nil)
+	mockRepo.On("GetRateStats", ctx, "cbr", "USD", "2025-08-02", "2025-08-02").Return(nil, postgres.ErrNotFound)
+
+	_, err := service.GetRateStats(ctx, "cbr", "USD", from, to)
+	assert.ErrorIs(t, err, ErrRateNotFound)
+}
+
+func TestGetRateStats_FutureRange(t *testing.T) {
+	ctx := context.Background()
+	service, _, mockRepo, _, _ := setupTestService()
+
+	_, err := service.GetRateStats(ctx, "cbr", "USD", time.Now(), time.Now().Add(72*time.Hour))
+	assert.ErrorIs(t, err, ErrFutureDate)
+	mockRepo.AssertNotCalled(t, "GetRateStats", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+type recordingObserver struct {
+	lookups []string
+	stored  map[string]time.Time
+	synced  map[string]time.Time
+}
+
+func newRecordingObserver() *recordingObserver {
+	return &recordingObserver{stored: map[string]time.Time{}, synced: map[string]time.Time{}}
+}
+
+func (o *recordingObserver) ObserveRateLookup(lookup string, hit bool) {
+	result := "miss"
+	if hit {
+		result = "hit"
+	}
+	o.lookups = append(o.lookups, lookup+"/"+result)
+}
+
+func (o *recordingObserver) ObserveRatesStored(source string, latest time.Time) {
+	o.stored[source] = latest
+}
+
+func (o *recordingObserver) ObserveRateSync(source string, at time.Time) {
+	o.synced[source] = at
+}
+
+func TestRateService_ObservesLookupsAndStores(t *testing.T) {
+	ctx := context.Background()
+	service, mockCbr, mockRepo, _, _ := setupTestService()
+	observer := newRecordingObserver()
+	service.SetObserver(observer)
+
+	stored := time.Date(2025, 8, 1, 0, 0, 0, 0, time.UTC)
+	missing := time.Date(2025, 7, 31, 0, 0, 0, 0, time.UTC)
+	mockRepo.On("GetRateByCharCodeAndDate", ctx, "cbr", "USD", "2025-08-01").Return(&entity.Currency{CharCode: "USD", Value: decimal.RequireFromString("90.5"), Date: stored}, nil)
+	mockRepo.On("GetRateByCharCodeAndDate", ctx, "cbr", "USD", "2025-07-31").Return(nil, postgres.ErrNotFound)
+	mockCbr.On("FetchRates", ctx, "31/07/2025").Return(&cbr.ValCurs{
+		Date:    "31.07.2025",
+		Valutes: []cbr.Valute{{ID: "R01235", CharCode: "USD", Name: "US Dollar", Nominal: 1, Value: "80,1", NumCode: "840"}},
+	}, nil)
+	mockRepo.On("StoreRates", ctx, mock.Anything).Return(nil)
+
+	_, err := service.GetRateByCharCodeAndDate(ctx, "cbr", "USD", stored)
+	require.NoError(t, err)
+	_, err = service.GetRateByCharCodeAndDate(ctx, "cbr", "USD", missing)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"date/hit", "date/miss"}, observer.lookups)
+	assert.Equal(t, map[string]time.Time{"cbr": missing}, observer.stored)
+	assert.Empty(t, observer.synced)
+}
+
+func TestRateService_ObservesDailySync(t *testing.T) {
+	ctx := context.Background()
+	service, mockCbr, mockRepo, _, _ := setupTestService()
+	observer := newRecordingObserver()
+	service.SetObserver(observer)
+
+	today := service.now()
+	mockCbr.On("FetchRates", ctx, today.Format("02/01/2006")).Return(&cbr.ValCurs{
+		Date:    today.Format("02.01.2006"),
+		Valutes: []cbr.Valute{{ID: "R01235", CharCode: "USD", Name: "US Dollar", Nominal: 1, Value: "80,1", NumCode: "840"}},
+	}, nil)
+	mockRepo.On("StoreRates", ctx, mock.Anything).Return(nil)
+
+	require.NoError(t, service.StoreRatesFromProvider(ctx, "cbr"))
+	assert.Equal(t, today, observer.synced["cbr"])
+	assert.Contains(t, observer.stored, "cbr")
+}
+
+func TestRateService_SeedObserver(t *testing.T) {
+	ctx := context.Background()
+	service, _, mockRepo, _, _ := setupTestService()
+	observer := newRecordingObserver()
+	service.SetObserver(observer)
+
+	cbrDate := time.Date(2025, 8, 5, 0, 0, 0, 0, time.UTC)
+	cbrFetched := time.Date(2025, 8, 4, 12, 1, 0, 0, time.UTC)
+	ecbDate := time.Date(2025, 8, 4, 0, 0, 0, 0, time.UTC)
+	ecbFetched := time.Date(2025, 8, 4, 16, 5, 0, 0, time.UTC)
+	mockRepo.On("GetLatestRateSummaries", ctx).Return([]entity.LatestRateSummary{
+		{Source: "cbr", LatestDate: cbrDate, FetchedAt: cbrFetched},
+		{Source: "ecb", LatestDate: ecbDate, FetchedAt: ecbFetched},
+	}, nil)
+
+	require.NoError(t, service.SeedObserver(ctx))
+	assert.Equal(t, map[string]time.Time{"cbr": cbrDate, "ecb": ecbDate}, observer.stored)
+	assert.Equal(t, map[string]time.Time{"cbr": cbrFetched, "ecb": ecbFetched}, observer.synced)
+	assert.Empty(t, observer.lookups)
+}
+
+func TestRateService_SeedObserver_Error(t *testing.T) {
+	ctx := context.Background()
+	service, _, mockRepo, _, _ := setupTestService()
+	observer := newRecordingObserver()
+	service.SetObserver(observer)
+
+	mockRepo.On("GetLatestRateSummaries", ctx).Return(nil, errors.New("db down"))
+
+	assert.ErrorContains(t, service.SeedObserver(ctx), "db down")
+	assert.Empty(t, observer.stored)
+	assert.Empty(t, observer.synced)
+}
+
+func TestRateService_SeedObserver_WithoutObserver(t *testing.T) {
+	service, _, mockRepo, _, _ := setupTestService()
+
+	require.NoError(t, service.SeedObserver(context.Background()))
+	mockRepo.AssertNotCalled(t, "GetLatestRateSummaries", mock.Anything)
+}
+
+func TestRateService_TracesHistoricalFetch(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	tracerProvider := tracing.NewProvider(recorder, "RnD-service", 1)
+	otel.SetTracerProvider(tracerProvider)
+	t.Cleanup(func() { otel.SetTracerProvider(noop.NewTracerProvider()) })
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `<?xml version="1.0" encoding="windows-1251"?><ValCurs Date="31.07.2025" name="Foreign Currency Market"><Valute ID="R01235"><NumCode>840</NumCode><CharCode>USD</CharCode><Nominal>1</Nominal><Name>US Dollar</Name><Value>80,1000</Value></Valute></ValCurs>`)
+	}))
+	defer srv.Close()
+
+	logger, _ := test.NewNullLogger()
+	client, err := cbr.NewClient(logger, cbr.WithBaseURL(srv.URL))
+	require.NoError(t, err)
+	mockRepo := new(mockPostgresRepo)
+	service := NewRateService(client, mockRepo, logger)
+	service.SetTracer(tracerProvider.Tracer("test"))
+
+	mockRepo.On("GetRateByCharCodeAndDate", mock.Anything, "cbr", "USD", "2025-07-31").Return(nil, postgres.ErrNotFound)
+	mockRepo.On("StoreRates", mock.Anything, mock.Anything).Return(nil)
+
+	_, err = service.GetRateByCharCodeAndDate(context.Background(), "cbr", "USD", time.Date(2025, 7, 31, 0, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+
+	spans := map[string]sdktrace.ReadOnlySpan{}
+	for _, span := range recorder.Ended() {
+		spans[span.Name()] = span
+	}
+	require.Len(t, spans, 4)
+
+	lookup := spans["RateService.GetRateByCharCodeAndDate"]
+	assert.False(t, lookup.Parent().IsValid())
+	assert.Contains(t, lookup.Attributes(), attribute.Bool("rate.cache_hit", false))
+	assert.Equal(t, lookup.SpanContext().SpanID(), spans["RateService.fetchDaily"].Parent().SpanID())
+	assert.Equal(t, spans["RateService.fetchDaily"].SpanContext().SpanID(), spans["CBR GET XML_daily.asp"].Parent().SpanID())
+	assert.Equal(t, lookup.SpanContext().SpanID(), spans["RateService.storeRates"].Parent().SpanID())
 }
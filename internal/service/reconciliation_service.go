@@ -0,0 +1,175 @@
+package service
+
+import (
+	"RnD-service/internal/adapter/postgres"
+	"RnD-service/internal/adapter/provider"
+	"RnD-service/internal/entity"
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/sirupsen/logrus"
+)
+
+// reconciliationScale is the precision of per-unit values compared across sources.
+const reconciliationScale = 8
+
+type ReconciliationService struct {
+	primary   provider.RateProvider
+	secondary provider.RateProvider
+	repo      postgres.DiscrepancyRepository
+	threshold decimal.Decimal
+	logger    *logrus.Logger
+	now       func() time.Time
+}
+
+// NewReconciliationService flags currencies whose rates differ by more than
+// thresholdPercent between primary and secondary.
+func NewReconciliationService(primary, secondary provider.RateProvider, repo postgres.DiscrepancyRepository, thresholdPercent decimal.Decimal, logger *logrus.Logger) *ReconciliationService {
+	return &ReconciliationService{
+		primary:   primary,
+		secondary: secondary,
+		repo:      repo,
+		threshold: thresholdPercent,
+		logger:    logger,
+		now:       time.Now,
+	}
+}
+
+// Reconcile fetches both publications for date, records the discrepancies and
+// returns them. Sources with different bases are compared in the base of the
+// one whose currency the other quotes, e.g. CBR rates are turned into EUR via
+// the CBR euro rate to compare them with ECB.
+func (s *ReconciliationService) Reconcile(ctx context.Context, date time.Time) ([]entity.RateDiscrepancy, error) {
+	date = date.Truncate(24 * time.Hour)
+	if date.After(s.now().Truncate(24 * time.Hour)) {
+		s.logger.Warnf("Requested reconciliation for future date: %s", date.Format("2006-01-02"))
+		return nil, ErrFutureDate
+	}
+	dateStr := date.Format("2006-01-02")
+
+	s.logger.Infof("Reconciling %s against %s rates for %s", s.primary.Name(), s.secondary.Name(), dateStr)
+
+	primaryRates, err := s.primary.FetchDaily(ctx, date)
+	if err != nil {
+		s.logger.Errorf("Failed to fetch %s rates for reconciliation: %v", s.primary.Name(), err)
+		return nil, fmt.Errorf("fetch %s rates: %w: %w", s.primary.Name(), ErrUpstreamUnavailable, err)
+	}
+	secondaryRates, err := s.secondary.FetchDaily(ctx, date)
+	if err != nil {
+		s.logger.Errorf("Failed to fetch %s rates for reconciliation: %v", s.secondary.Name(), err)
+		return nil, fmt.Errorf("fetch %s rates: %w: %w", s.secondary.Name(), ErrUpstreamUnavailable, err)
+	}
+
+	base, primaryValues, secondaryValues, err := s.commonBase(perUnit(primaryRates), perUnit(secondaryRates))
+	if err != nil {
+		s.logger.WithError(err).Error("Cannot reconcile rate sources")
+		return nil, err
+	}
+
+	codes := make([]string, 0, len(secondaryValues))
+	for code := range secondaryValues {
+		if _, ok := primaryValues[code]; ok {
+			codes = append(codes, code)
+		}
+	}
+	sort.Strings(codes)
+
+	detectedAt := s.now()
+	var discrepancies []entity.RateDiscrepancy
+	for _, code := range codes {
+		primaryValue, secondaryValue := primaryValues[code], secondaryValues[code]
+		if secondaryValue.IsZero() {
+			continue
+		}
+		diff := primaryValue.Sub(secondaryValue).Abs().Div(secondaryValue).Mul(decimal.NewFromInt(100)).Round(4)
+		if !diff.GreaterThan(s.threshold) {
+			continue
+		}
+
+		discrepancy := entity.RateDiscrepancy{
+			Date:            date,
+			CharCode:        code,
+			PrimarySource:   s.primary.Name(),
+			SecondarySource: s.secondary.Name(),
+			Base:            base,
+			PrimaryValue:    primaryValue,
+			SecondaryValue:  secondaryValue,
+			DiffPercent:     diff,
+			DetectedAt:      detectedAt,
+		}
+		s.logger.WithFields(logrus.Fields{
+			"date":      dateStr,
+			"char_code": code,
+			"primary":   fmt.Sprintf("%s=%s", discrepancy.PrimarySource, primaryValue),
+			"secondary": fmt.Sprintf("%s=%s", discrepancy.SecondarySource, secondaryValue),
+			"base":      base,
+		}).Errorf("Rate discrepancy of %s%% exceeds threshold of %s%%", diff, s.threshold)
+		discrepancies = append(discrepancies, discrepancy)
+	}
+
+	s.logger.Infof("Reconciled %d currencies for %s, %d discrepancies above %s%%", len(codes), dateStr, len(discrepancies), s.threshold)
+
+	if err := s.repo.StoreDiscrepancies(ctx, discrepancies); err != nil {
+		s.logger.Errorf("Failed to store rate discrepancies in DB: %v", err)
+		return nil, fmt.Errorf("store rate discrepancies in DB: %w", err)
+	}
+
+	return discrepancies, nil
+}
+
+func (s *ReconciliationService) ListDiscrepancies(ctx context.Context, dateFrom, dateTo time.Time) ([]entity.RateDiscrepancy, error) {
+	discrepancies, err := s.repo.ListDiscrepancies(ctx, dateFrom.Format("2006-01-02"), dateTo.Format("2006-01-02"))
+	if err != nil {
+		s.logger.WithError(err).Error("Failed to list rate discrepancies")
+		return nil, fmt.Errorf("list rate discrepancies: %w", err)
+	}
+	return discrepancies, nil
+}
+
+// commonBase returns both value sets expressed in one currency.
+func (s *ReconciliationService) commonBase(primary, secondary map[string]decimal.Decimal) (string, map[string]decimal.Decimal, map[string]decimal.Decimal, error) {
+	primaryBase, secondaryBase := s.primary.BaseCurrency(), s.secondary.BaseCurrency()
+	if primaryBase == secondaryBase {
+		return primaryBase, primary, secondary, nil
+	}
+	if rebased, ok := rebase(primary, primaryBase, secondaryBase); ok {
+		return secondaryBase, rebased, secondary, nil
+	}
+	if rebased, ok := rebase(secondary, secondaryBase, primaryBase); ok {
+		return primaryBase, primary, rebased, nil
+	}
+	return "", nil, nil, fmt.Errorf("%w: %s quotes against %s, %s against %s", ErrNoCommonCurrency, s.primary.Name(), primaryBase, s.secondary.Name(), secondaryBase)
+}
+
+func perUnit(rates []entity.Currency) map[string]decimal.Decimal {
+	values := make(map[string]decimal.Decimal, len(rates))
+	for _, rate := range rates {
+		if rate.Nominal <= 0 {
+			continue
+		}
+		values[rate.CharCode] = rate.Value.DivRound(decimal.NewFromInt(int64(rate.Nominal)), reconciliationScale)
+	}
+	return values
+}
+
+// rebase turns per-unit values in from into values in to, using the rate of to
+// from the same set; from itself becomes 1/rate.
+func rebase(values map[string]decimal.Decimal, from, to string) (map[string]decimal.Decimal, bool) {
+	pivot, ok := values[to]
+	if !ok || pivot.IsZero() {
+		return nil, false
+	}
+
+	rebased := make(map[string]decimal.Decimal, len(values))
+	for code, value := range values {
+		if code == to {
+			continue
+		}
+		rebased[code] = value.DivRound(pivot, reconciliationScale)
+	}
+	rebased[from] = decimal.NewFromInt(1).DivRound(pivot, reconciliationScale)
+	return rebased, true
+}
@@ -0,0 +1,145 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"RnD-service/internal/adapter/provider"
+	"RnD-service/internal/entity"
+
+	"github.com/shopspring/decimal"
+	"github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type mockDiscrepancyRepo struct {
+	mock.Mock
+}
+
+func (m *mockDiscrepancyRepo) StoreDiscrepancies(ctx context.Context, discrepancies []entity.RateDiscrepancy) error {
+	args := m.Called(ctx, discrepancies)
+	return args.Error(0)
+}
+
+func (m *mockDiscrepancyRepo) ListDiscrepancies(ctx context.Context, dateFrom, dateTo string) ([]entity.RateDiscrepancy, error) {
+	args := m.Called(ctx, dateFrom, dateTo)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]entity.RateDiscrepancy), args.Error(1)
+}
+
+func setupReconciliationService() (*ReconciliationService, *mockRateProvider, *mockRateProvider, *mockDiscrepancyRepo, *test.Hook) {
+	primary := &mockRateProvider{name: provider.SourceCBR, base: "RUB"}
+	secondary := &mockRateProvider{name: provider.SourceECB}
+	repo := new(mockDiscrepancyRepo)
+	logger, hook := test.NewNullLogger()
+	service := NewReconciliationService(primary, secondary, repo, decimal.RequireFromString("0.5"), logger)
+	now := time.Date(2025, 8, 1, 17, 0, 0, 0, time.UTC)
+	service.now = func() time.Time { return now }
+	return service, primary, secondary, repo, hook
+}
+
+func TestReconcile_FlagsDivergentRatesInCommonBase(t *testing.T) {
+	ctx := context.Background()
+	service, primary, secondary, repo, hook := setupReconciliationService()
+
+	day := time.Date(2025, 8, 1, 0, 0, 0, 0, time.UTC)
+	primary.On("FetchDaily", ctx, day).Return([]entity.Currency{
+		{CharCode: "USD", Nominal: 1, Value: decimal.RequireFromString("80")},
+		{CharCode: "EUR", Nominal: 1, Value: decimal.RequireFromString("92")},
+		{CharCode: "JPY", Nominal: 100, Value: decimal.RequireFromString("54")},
+	}, nil)
+	secondary.On("FetchDaily", ctx, day).Return([]entity.Currency{
+		{CharCode: "USD", Nominal: 1, Value: decimal.RequireFromString("0.8769")},
+		{CharCode: "JPY", Nominal: 100, Value: decimal.RequireFromString("0.5886")},
+		{CharCode: "GBP", Nominal: 1, Value: decimal.RequireFromString("1.1616")},
+	}, nil)
+
+	repo.On("StoreDiscrepancies", ctx, mock.Anything).Return(nil)
+
+	// CBR USD in EUR is 80 / 92; JPY differs by only 0.28%
+	discrepancies, err := service.Reconcile(ctx, day)
+	require.NoError(t, err)
+	require.Len(t, discrepancies, 1)
+	d := discrepancies[0]
+	assert.Equal(t, "USD", d.CharCode)
+	assert.Equal(t, "cbr", d.PrimarySource)
+	assert.Equal(t, "ecb", d.SecondarySource)
+	assert.Equal(t, "EUR", d.Base)
+	assert.Equal(t, "0.86956522", d.PrimaryValue.String())
+	assert.Equal(t, "0.8769", d.SecondaryValue.String())
+	assert.Equal(t, "0.8364", d.DiffPercent.String())
+	assert.Equal(t, service.now(), d.DetectedAt)
+	repo.AssertCalled(t, "StoreDiscrepancies", ctx, discrepancies)
+
+	var alerts int
+	for _, entry := range hook.AllEntries() {
+		if entry.Level == logrus.ErrorLevel {
+			alerts++
+			assert.Equal(t, "USD", entry.Data["char_code"])
+		}
+	}
+	assert.Equal(t, 1, alerts)
+}
+
+func TestReconcile_SameBaseComparesDirectly(t *testing.T) {
+	ctx := context.Background()
+	service, primary, _, repo, _ := setupReconciliationService()
+	mirror := &mockRateProvider{name: provider.SourceCBRMirror, base: "RUB"}
+	service.secondary = mirror
+
+	day := time.Date(2025, 8, 1, 0, 0, 0, 0, time.UTC)
+	primary.On("FetchDaily", ctx, day).Return([]entity.Currency{
+		{CharCode: "USD", Nominal: 1, Value: decimal.RequireFromString("80")},
+	}, nil)
+	mirror.On("FetchDaily", ctx, day).Return([]entity.Currency{
+		{CharCode: "USD", Nominal: 1, Value: decimal.RequireFromString("80.2")},
+	}, nil)
+	repo.On("StoreDiscrepancies", ctx, []entity.RateDiscrepancy(nil)).Return(nil)
+
+	discrepancies, err := service.Reconcile(ctx, day)
+	require.NoError(t, err)
+	assert.Empty(t, discrepancies)
+	repo.AssertExpectations(t)
+}
+
+func TestReconcile_NoCommonCurrency(t *testing.T) {
+	ctx := context.Background()
+	service, primary, secondary, repo, _ := setupReconciliationService()
+
+	day := time.Date(2025, 8, 1, 0, 0, 0, 0, time.UTC)
+	primary.On("FetchDaily", ctx, day).Return([]entity.Currency{
+		{CharCode: "USD", Nominal: 1, Value: decimal.RequireFromString("80")},
+	}, nil)
+	secondary.On("FetchDaily", ctx, day).Return([]entity.Currency{
+		{CharCode: "USD", Nominal: 1, Value: decimal.RequireFromString("0.8769")},
+	}, nil)
+
+	_, err := service.Reconcile(ctx, day)
+	assert.ErrorIs(t, err, ErrNoCommonCurrency)
+	repo.AssertNotCalled(t, "StoreDiscrepancies", mock.Anything, mock.Anything)
+}
+
+func TestReconcile_UpstreamError(t *testing.T) {
+	ctx := context.Background()
+	service, primary, _, _, _ := setupReconciliationService()
+
+	day := time.Date(2025, 8, 1, 0, 0, 0, 0, time.UTC)
+	primary.On("FetchDaily", ctx, day).Return(nil, errors.New("connection refused"))
+
+	_, err := service.Reconcile(ctx, day)
+	assert.ErrorIs(t, err, ErrUpstreamUnavailable)
+}
+
+func TestReconcile_FutureDate(t *testing.T) {
+	service, _, _, _, _ := setupReconciliationService()
+
+	_, err := service.Reconcile(context.Background(), time.Date(2025, 8, 2, 0, 0, 0, 0, time.UTC))
+	assert.ErrorIs(t, err, ErrFutureDate)
+}
@@ -0,0 +1,421 @@
+package service
+
+import (
+	"RnD-service/internal/adapter/postgres"
+	"RnD-service/internal/entity"
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/robfig/cron/v3"
+	"github.com/sirupsen/logrus"
+)
+
+// Job is a task the scheduler runs on its cron Schedule, in the scheduler's
+// time zone. A job without a Schedule runs only on start or when triggered.
+type Job struct {
+	Name     string
+	Schedule string
+	// RunOnStart runs the job when a replica becomes the leader, catching up
+	// on runs missed while no replica was up.
+	RunOnStart bool
+	Run        func(ctx context.Context) error
+}
+
+type SchedulerOptions struct {
+	Location *time.Location
+	// LeaderCheckInterval is how often the leader confirms its lock and the
+	// other replicas try to take it over.
+	LeaderCheckInterval time.Duration
+	MaxAttempts         int
+	BaseDelay           time.Duration
+	MaxDelay            time.Duration
+	// Instance names this replica in job_runs.
+	Instance string
+}
+
+var DefaultSchedulerOptions = SchedulerOptions{
+	Location:            time.UTC,
+	LeaderCheckInterval: 15 * time.Second,
+	MaxAttempts:         3,
+	BaseDelay:           time.Minute,
+	MaxDelay:            10 * time.Minute,
+}
+
+const schedulerLockName = "scheduler"
+
+// SchedulerService runs jobs on the replica holding the scheduler lock. Every
+// run also holds a lock of its job, so a job triggered by hand never overlaps
+// a run of the same job on another replica.
+type SchedulerService struct {
+	repo    postgres.JobRepository
+	leader  postgres.Locker
+	newLock func(name string) postgres.Locker
+	opts    SchedulerOptions
+	logger  *logrus.Logger
+	now     func() time.Time
+	cron    *cron.Cron
+
+	jobs    map[string]*scheduledJob
+	names   []string
+	mu      sync.Mutex
+	running map[string]bool
+	isLead  bool
+
+	// runs outlive the request that triggered them and stop on Shutdown
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+type scheduledJob struct {
+	Job
+	entry cron.EntryID
+}
+
+// NewSchedulerService takes newLock to create the per-job locks; the
+// scheduler lock is newLock("scheduler").
+func NewSchedulerService(repo postgres.JobRepository, newLock func(name string) postgres.Locker, opts SchedulerOptions, logger *logrus.Logger) *SchedulerService {
+	if opts.Location == nil {
+		opts.Location = DefaultSchedulerOptions.Location
+	}
+	if opts.LeaderCheckInterval <= 0 {
+		opts.LeaderCheckInterval = DefaultSchedulerOptions.LeaderCheckInterval
+	}
+	if opts.MaxAttempts <= 0 {
+		opts.MaxAttempts = DefaultSchedulerOptions.MaxAttempts
+	}
+	if opts.BaseDelay <= 0 {
+		opts.BaseDelay = DefaultSchedulerOptions.BaseDelay
+	}
+	if opts.MaxDelay < opts.BaseDelay {
+		opts.MaxDelay = max(opts.BaseDelay, DefaultSchedulerOptions.MaxDelay)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	return &SchedulerService{
+		repo:    repo,
+		leader:  newLock(schedulerLockName),
+		newLock: newLock,
+		opts:    opts,
+		logger:  logger,
+		now:     time.Now,
+		cron:    cron.New(cron.WithLocation(opts.Location)),
+		jobs:    make(map[string]*scheduledJob),
+		running: make(map[string]bool),
+		ctx:     ctx,
+		cancel:  cancel,
+	}
+}
+
+// AddJob registers job before Start; it fails on a duplicate name or an
+// invalid cron expression.
+func (s *SchedulerService) AddJob(job Job) error {
+	if _, ok := s.jobs[job.Name]; ok {
+		return fmt.Errorf("job %s is already registered", job.Name)
+	}
+
+	scheduled := &scheduledJob{Job: job}
+	if job.Schedule != "" {
+		id, err := s.cron.AddFunc(job.Schedule, func() { s.tick(job.Name) })
+		if err != nil {
+			return fmt.Errorf("job %s: invalid schedule %q: %w", job.Name, job.Schedule, err)
+		}
+		scheduled.entry = id
+	}
+	s.jobs[job.Name] = scheduled
+	s.names = append(s.names, job.Name)
+
+	s.logger.WithField("job", job.Name).Infof("Registered job %s with schedule %q in %s", job.Name, job.Schedule, s.opts.Location)
+	return nil
+}
+
+// Start starts the cron and the leader election; ticks are skipped on every
+// replica but the leader.
+func (s *SchedulerService) Start() {
+	s.cron.Start()
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		s.lead()
+	}()
+}
+
+// Shutdown stops the cron, interrupts running jobs, waits for them to record
+// their runs and gives up leadership.
+func (s *SchedulerService) Shutdown() {
+	<-s.cron.Stop().Done()
+	s.cancel()
+	s.wg.Wait()
+
+	if err := s.leader.Release(context.Background()); err != nil {
+		s.logger.WithError(err).Error("Failed to release scheduler lock")
+	}
+}
+
+func (s *SchedulerService) IsLeader() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.isLead
+}
+
+func (s *SchedulerService) Status(ctx context.Context) (*entity.SchedulerStatus, error) {
+	runs, err := s.repo.ListLatestJobRuns(ctx)
+	if err != nil {
+		s.logger.WithContext(ctx).Errorf("Failed to list job runs: %v", err)
+		return nil, fmt.Errorf("list job runs: %w", err)
+	}
+	lastRuns := make(map[string]entity.JobRun, len(runs))
+	for _, run := range runs {
+		lastRuns[run.Job] = run
+	}
+
+	status := &entity.SchedulerStatus{
+		Instance: s.opts.Instance,
+		Leader:   s.IsLeader(),
+		Timezone: s.opts.Location.String(),
+		Jobs:     make([]entity.JobInfo, 0, len(s.names)),
+	}
+	for _, name := range s.names {
+		job := s.jobs[name]
+		info := entity.JobInfo{
+			Name:       name,
+			Schedule:   job.Schedule,
+			RunOnStart: job.RunOnStart,
+			NextRun:    s.NextRun(name),
+			Running:    s.isRunning(name),
+		}
+		if run, ok := lastRuns[name]; ok {
+			info.LastRun = &run
+			// a run left by another replica counts as well
+			info.Running = info.Running || run.Status == entity.JobRunRunning
+		}
+		status.Jobs = append(status.Jobs, info)
+	}
+	return status, nil
+}
+
+// NextRun is zero for an unknown job, a job without a schedule or before Start.
+func (s *SchedulerService) NextRun(name string) time.Time {
+	job, ok := s.jobs[name]
+	if !ok || job.Schedule == "" {
+		return time.Time{}
+	}
+	return s.cron.Entry(job.entry).Next
+}
+
+// LastFinishedRun returns nil while the job has never finished.
+func (s *SchedulerService) LastFinishedRun(ctx context.Context, name string) (*entity.JobRun, error) {
+	run, err := s.repo.GetLastFinishedJobRun(ctx, name)
+	if err != nil {
+		if errors.Is(err, postgres.ErrNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("get last run of %s: %w", name, err)
+	}
+	return run, nil
+}
+
+// RunJob starts the job in the background on this replica, whether or not it
+// is the leader, and returns the created run.
+func (s *SchedulerService) RunJob(ctx context.Context, name string) (*entity.JobRun, error) {
+	job, ok := s.jobs[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrJobNotFound, name)
+	}
+	return s.start(ctx, job, entity.JobTriggerManual)
+}
+
+func (s *SchedulerService) lead() {
+	ticker := time.NewTicker(s.opts.LeaderCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		s.checkLeadership()
+		select {
+		case <-s.ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// checkLeadership takes the scheduler lock when it is free and, on becoming
+// the leader, starts the jobs that run on start.
+func (s *SchedulerService) checkLeadership() {
+	acquired, err := s.leader.TryAcquire(s.ctx)
+	if err != nil {
+		s.logger.WithError(err).Warn("Failed to check scheduler lock")
+		acquired = false
+	}
+
+	s.mu.Lock()
+	wasLeader := s.isLead
+	s.isLead = acquired
+	s.mu.Unlock()
+
+	switch {
+	case acquired && !wasLeader:
+		s.logger.WithField("instance", s.opts.Instance).Info("Became scheduler leader")
+		for _, name := range s.names {
+			if job := s.jobs[name]; job.RunOnStart {
+				if _, err := s.start(s.ctx, job, entity.JobTriggerStartup); err != nil {
+					s.logger.WithError(err).Warnf("Failed to start job %s", name)
+				}
+			}
+		}
+	case !acquired && wasLeader:
+		s.logger.WithField("instance", s.opts.Instance).Warn("Lost scheduler leadership")
+	}
+}
+
+func (s *SchedulerService) tick(name string) {
+	if !s.IsLeader() {
+		s.logger.WithField("job", name).Debugf("Skipping job %s, not the scheduler leader", name)
+		return
+	}
+	if _, err := s.start(s.ctx, s.jobs[name], entity.JobTriggerSchedule); err != nil {
+		s.logger.WithError(err).Warnf("Skipping scheduled run of job %s", name)
+	}
+}
+
+func (s *SchedulerService) start(ctx context.Context, job *scheduledJob, trigger string) (*entity.JobRun, error) {
+	logger := s.logger.WithContext(ctx).WithField("job", job.Name)
+	if !s.claim(job.Name) {
+		return nil, fmt.Errorf("%w: %s", ErrJobRunning, job.Name)
+	}
+
+	lock := s.newLock("job:" + job.Name)
+	acquired, err := lock.TryAcquire(ctx)
+	if err != nil {
+		s.release(job.Name)
+		logger.Errorf("Failed to lock job %s: %v", job.Name, err)
+		return nil, fmt.Errorf("lock job %s: %w", job.Name, err)
+	}
+	if !acquired {
+		s.release(job.Name)
+		return nil, fmt.Errorf("%w: %s runs on another replica", ErrJobRunning, job.Name)
+	}
+
+	// nothing else runs the job now, so a running row was left by a replica that stopped mid-run
+	now := s.now()
+	if err := s.repo.FailInterruptedJobRuns(ctx, job.Name, now); err != nil {
+		logger.WithError(err).Warnf("Failed to close interrupted runs of job %s", job.Name)
+	}
+
+	run := &entity.JobRun{
+		Job:       job.Name,
+		Trigger:   trigger,
+		Instance:  s.opts.Instance,
+		Status:    entity.JobRunRunning,
+		StartedAt: now,
+	}
+	id, err := s.repo.CreateJobRun(ctx, run)
+	if err != nil {
+		s.unlock(lock)
+		s.release(job.Name)
+		logger.Errorf("Failed to record run of job %s: %v", job.Name, err)
+		return nil, fmt.Errorf("create job run: %w", err)
+	}
+	run.ID = id
+	started := *run
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		defer s.release(job.Name)
+		defer s.unlock(lock)
+
+		s.execute(job, run)
+	}()
+	return &started, nil
+}
+
+func (s *SchedulerService) execute(job *scheduledJob, run *entity.JobRun) {
+	logger := s.logger.WithFields(logrus.Fields{"job": job.Name, "run_id": run.ID})
+	logger.Infof("Running job %s (%s)", job.Name, run.Trigger)
+
+	err := s.runWithRetries(job, run)
+
+	finishedAt := s.now()
+	run.FinishedAt = &finishedAt
+	if err != nil {
+		run.Status = entity.JobRunFailed
+		run.Error = err.Error()
+		logger.WithError(err).Errorf("Job %s failed after %d attempts", job.Name, run.Attempts)
+	} else {
+		run.Status = entity.JobRunSucceeded
+		run.Error = ""
+		logger.Infof("Job %s succeeded in %s", job.Name, finishedAt.Sub(run.StartedAt).Round(time.Millisecond))
+	}
+	s.saveRun(run)
+}
+
+func (s *SchedulerService) runWithRetries(job *scheduledJob, run *entity.JobRun) error {
+	for attempt := 1; ; attempt++ {
+		run.Attempts = attempt
+		err := job.Run(s.ctx)
+		if err == nil {
+			return nil
+		}
+		if attempt >= s.opts.MaxAttempts || s.ctx.Err() != nil {
+			return err
+		}
+
+		delay := s.backoff(attempt)
+		s.logger.WithError(err).WithField("job", job.Name).Warnf("Job %s failed on attempt %d, retrying in %s", job.Name, attempt, delay)
+		run.Error = err.Error()
+		s.saveRun(run)
+
+		select {
+		case <-s.ctx.Done():
+			return fmt.Errorf("%w (last error: %w)", s.ctx.Err(), err)
+		case <-time.After(delay):
+		}
+	}
+}
+
+func (s *SchedulerService) backoff(attempt int) time.Duration {
+	d := s.opts.BaseDelay
+	for i := 1; i < attempt && d < s.opts.MaxDelay; i++ {
+		d *= 2
+	}
+	return min(d, s.opts.MaxDelay)
+}
+
+// saveRun outlives Shutdown, so an interrupted run is still recorded.
+func (s *SchedulerService) saveRun(run *entity.JobRun) {
+	if err := s.repo.UpdateJobRun(context.Background(), run); err != nil {
+		s.logger.WithError(err).Errorf("Failed to save run %d of job %s", run.ID, run.Job)
+	}
+}
+
+func (s *SchedulerService) unlock(lock postgres.Locker) {
+	if err := lock.Release(context.Background()); err != nil {
+		s.logger.WithError(err).Error("Failed to release job lock")
+	}
+}
+
+func (s *SchedulerService) claim(name string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.running[name] {
+		return false
+	}
+	s.running[name] = true
+	return true
+}
+
+func (s *SchedulerService) release(name string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.running, name)
+}
+
+func (s *SchedulerService) isRunning(name string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.running[name]
+}
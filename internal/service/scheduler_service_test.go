@@ -0,0 +1,327 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"RnD-service/internal/adapter/postgres"
+	"RnD-service/internal/entity"
+
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type mockJobRepo struct {
+	mock.Mock
+	mu      sync.Mutex
+	updates []entity.JobRun
+}
+
+func (m *mockJobRepo) CreateJobRun(ctx context.Context, run *entity.JobRun) (int64, error) {
+	args := m.Called(ctx, run)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *mockJobRepo) UpdateJobRun(ctx context.Context, run *entity.JobRun) error {
+	m.mu.Lock()
+	m.updates = append(m.updates, *run)
+	m.mu.Unlock()
+	args := m.Called(ctx, run)
+	return args.Error(0)
+}
+
+func (m *mockJobRepo) FailInterruptedJobRuns(ctx context.Context, job string, at time.Time) error {
+	args := m.Called(ctx, job, at)
+	return args.Error(0)
+}
+
+func (m *mockJobRepo) ListLatestJobRuns(ctx context.Context) ([]entity.JobRun, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]entity.JobRun), args.Error(1)
+}
+
+func (m *mockJobRepo) GetLastFinishedJobRun(ctx context.Context, job string) (*entity.JobRun, error) {
+	args := m.Called(ctx, job)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.JobRun), args.Error(1)
+}
+
+func (m *mockJobRepo) lastUpdate() entity.JobRun {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.updates[len(m.updates)-1]
+}
+
+// fakeLocks is the lock table shared by the replicas of a test.
+type fakeLocks struct {
+	mu   sync.Mutex
+	held map[string]bool
+}
+
+func newFakeLocks() *fakeLocks {
+	return &fakeLocks{held: make(map[string]bool)}
+}
+
+func (f *fakeLocks) newLock(name string) postgres.Locker {
+	return &fakeLocker{locks: f, name: name}
+}
+
+func (f *fakeLocks) isHeld(name string) bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.held[name]
+}
+
+type fakeLocker struct {
+	locks   *fakeLocks
+	name    string
+	holding bool
+}
+
+func (l *fakeLocker) TryAcquire(ctx context.Context) (bool, error) {
+	l.locks.mu.Lock()
+	defer l.locks.mu.Unlock()
+	if l.holding {
+		return true, nil
+	}
+	if l.locks.held[l.name] {
+		return false, nil
+	}
+	l.locks.held[l.name] = true
+	l.holding = true
+	return true, nil
+}
+
+func (l *fakeLocker) Release(ctx context.Context) error {
+	l.locks.mu.Lock()
+	defer l.locks.mu.Unlock()
+	if l.holding {
+		delete(l.locks.held, l.name)
+		l.holding = false
+	}
+	return nil
+}
+
+var schedulerNow = time.Date(2025, 8, 5, 13, 0, 0, 0, time.UTC)
+
+func setupScheduler(locks *fakeLocks, instance string) (*SchedulerService, *mockJobRepo) {
+	mockRepo := new(mockJobRepo)
+	logger, _ := test.NewNullLogger()
+	opts := SchedulerOptions{
+		MaxAttempts: 3,
+		BaseDelay:   time.Millisecond,
+		MaxDelay:    2 * time.Millisecond,
+		Instance:    instance,
+	}
+	scheduler := NewSchedulerService(mockRepo, locks.newLock, opts, logger)
+	scheduler.now = func() time.Time { return schedulerNow }
+	return scheduler, mockRepo
+}
+
+func expectJobRun(mockRepo *mockJobRepo, job, trigger string, id int64) {
+	mockRepo.On("FailInterruptedJobRuns", mock.Anything, job, schedulerNow).Return(nil)
+	mockRepo.On("CreateJobRun", mock.Anything, mock.MatchedBy(func(r *entity.JobRun) bool {
+		return r.Job == job && r.Trigger == trigger && r.Status == entity.JobRunRunning
+	})).Return(id, nil)
+	mockRepo.On("UpdateJobRun", mock.Anything, mock.Anything).Return(nil)
+}
+
+func TestSchedulerRunJob_RetriesUntilSuccess(t *testing.T) {
+	ctx := context.Background()
+	locks := newFakeLocks()
+	scheduler, mockRepo := setupScheduler(locks, "api-1")
+
+	calls := 0
+	require.NoError(t, scheduler.AddJob(Job{Name: "cbr_rates", Run: func(ctx context.Context) error {
+		calls++
+		if calls == 1 {
+			return errors.New("CBR answered 503")
+		}
+		return nil
+	}}))
+	expectJobRun(mockRepo, "cbr_rates", entity.JobTriggerManual, 7)
+
+	run, err := scheduler.RunJob(ctx, "cbr_rates")
+	require.NoError(t, err)
+	assert.Equal(t, int64(7), run.ID)
+	assert.Equal(t, entity.JobRunRunning, run.Status)
+	assert.Equal(t, "api-1", run.Instance)
+
+	scheduler.wg.Wait()
+	last := mockRepo.lastUpdate()
+	assert.Equal(t, entity.JobRunSucceeded, last.Status)
+	assert.Equal(t, 2, last.Attempts)
+	assert.Empty(t, last.Error)
+	require.NotNil(t, last.FinishedAt)
+	assert.False(t, locks.isHeld("job:cbr_rates"))
+	assert.False(t, scheduler.isRunning("cbr_rates"))
+}
+
+func TestSchedulerRunJob_FailsAfterMaxAttempts(t *testing.T) {
+	ctx := context.Background()
+	scheduler, mockRepo := setupScheduler(newFakeLocks(), "api-1")
+
+	require.NoError(t, scheduler.AddJob(Job{Name: "cbr_rates", Run: func(ctx context.Context) error {
+		return errors.New("CBR answered 503")
+	}}))
+	expectJobRun(mockRepo, "cbr_rates", entity.JobTriggerManual, 7)
+
+	_, err := scheduler.RunJob(ctx, "cbr_rates")
+	require.NoError(t, err)
+
+	scheduler.wg.Wait()
+	last := mockRepo.lastUpdate()
+	assert.Equal(t, entity.JobRunFailed, last.Status)
+	assert.Equal(t, 3, last.Attempts)
+	assert.Equal(t, "CBR answered 503", last.Error)
+}
+
+func TestSchedulerRunJob_NotFound(t *testing.T) {
+	scheduler, _ := setupScheduler(newFakeLocks(), "api-1")
+
+	_, err := scheduler.RunJob(context.Background(), "cbr_rates")
+	assert.ErrorIs(t, err, ErrJobNotFound)
+}
+
+func TestSchedulerRunJob_AlreadyRunning(t *testing.T) {
+	ctx := context.Background()
+	locks := newFakeLocks()
+	scheduler, mockRepo := setupScheduler(locks, "api-1")
+	require.NoError(t, scheduler.AddJob(Job{Name: "cbr_rates", Run: func(ctx context.Context) error { return nil }}))
+
+	t.Run("on this replica", func(t *testing.T) {
+		require.True(t, scheduler.claim("cbr_rates"))
+		defer scheduler.release("cbr_rates")
+
+		_, err := scheduler.RunJob(ctx, "cbr_rates")
+		assert.ErrorIs(t, err, ErrJobRunning)
+	})
+
+	t.Run("on another replica", func(t *testing.T) {
+		other, _ := locks.newLock("job:cbr_rates").TryAcquire(ctx)
+		require.True(t, other)
+
+		_, err := scheduler.RunJob(ctx, "cbr_rates")
+		assert.ErrorIs(t, err, ErrJobRunning)
+		assert.False(t, scheduler.isRunning("cbr_rates"))
+	})
+
+	mockRepo.AssertNotCalled(t, "CreateJobRun", mock.Anything, mock.Anything)
+}
+
+func TestSchedulerTick_OnlyLeaderRuns(t *testing.T) {
+	locks := newFakeLocks()
+	leader, leaderRepo := setupScheduler(locks, "api-1")
+	follower, followerRepo := setupScheduler(locks, "api-2")
+
+	var mu sync.Mutex
+	var ranOn []string
+	for _, s := range []*SchedulerService{leader, follower} {
+		instance := s.opts.Instance
+		require.NoError(t, s.AddJob(Job{Name: "cbr_rates", Schedule: "0 10 * * *", RunOnStart: true, Run: func(ctx context.Context) error {
+			mu.Lock()
+			defer mu.Unlock()
+			ranOn = append(ranOn, instance)
+			return nil
+		}}))
+	}
+	expectJobRun(leaderRepo, "cbr_rates", entity.JobTriggerStartup, 7)
+
+	leader.checkLeadership()
+	follower.checkLeadership()
+	leader.wg.Wait()
+	assert.True(t, leader.IsLeader())
+	assert.False(t, follower.IsLeader())
+
+	expectJobRun(leaderRepo, "cbr_rates", entity.JobTriggerSchedule, 8)
+	leader.tick("cbr_rates")
+	follower.tick("cbr_rates")
+	leader.wg.Wait()
+	follower.wg.Wait()
+
+	assert.Equal(t, []string{"api-1", "api-1"}, ranOn)
+	followerRepo.AssertNotCalled(t, "CreateJobRun", mock.Anything, mock.Anything)
+
+	// the follower takes over once the leader is gone
+	leader.Shutdown()
+	expectJobRun(followerRepo, "cbr_rates", entity.JobTriggerStartup, 9)
+	follower.checkLeadership()
+	follower.wg.Wait()
+	assert.True(t, follower.IsLeader())
+	assert.Equal(t, []string{"api-1", "api-1", "api-2"}, ranOn)
+}
+
+func TestSchedulerAddJob_InvalidSchedule(t *testing.T) {
+	scheduler, _ := setupScheduler(newFakeLocks(), "api-1")
+
+	err := scheduler.AddJob(Job{Name: "cbr_rates", Schedule: "every day"})
+	assert.ErrorContains(t, err, "invalid schedule")
+	assert.NoError(t, scheduler.AddJob(Job{Name: "ecb_rates"}))
+	assert.ErrorContains(t, scheduler.AddJob(Job{Name: "ecb_rates"}), "already registered")
+}
+
+func TestSchedulerNextRun_InLocation(t *testing.T) {
+	moscow, err := time.LoadLocation("Europe/Moscow")
+	require.NoError(t, err)
+
+	mockRepo := new(mockJobRepo)
+	logger, _ := test.NewNullLogger()
+	scheduler := NewSchedulerService(mockRepo, newFakeLocks().newLock, SchedulerOptions{Location: moscow, LeaderCheckInterval: time.Hour}, logger)
+	require.NoError(t, scheduler.AddJob(Job{Name: "cbr_rates", Schedule: "0 16 * * *", Run: func(ctx context.Context) error { return nil }}))
+	require.NoError(t, scheduler.AddJob(Job{Name: "reconcile", Run: func(ctx context.Context) error { return nil }}))
+
+	scheduler.Start()
+	defer scheduler.Shutdown()
+
+	next := scheduler.NextRun("cbr_rates")
+	require.False(t, next.IsZero())
+	assert.Equal(t, 16, next.In(moscow).Hour())
+	assert.Equal(t, 13, next.UTC().Hour())
+	assert.True(t, scheduler.NextRun("reconcile").IsZero())
+	assert.True(t, scheduler.NextRun("unknown").IsZero())
+}
+
+func TestSchedulerStatus(t *testing.T) {
+	ctx := context.Background()
+	scheduler, mockRepo := setupScheduler(newFakeLocks(), "api-2")
+	require.NoError(t, scheduler.AddJob(Job{Name: "cbr_rates", Schedule: "0 16 * * *", RunOnStart: true}))
+	require.NoError(t, scheduler.AddJob(Job{Name: "reconcile", Schedule: "0 19 * * 1-5"}))
+
+	mockRepo.On("ListLatestJobRuns", ctx).Return([]entity.JobRun{
+		{ID: 7, Job: "cbr_rates", Instance: "api-1", Status: entity.JobRunRunning, Attempts: 1, StartedAt: schedulerNow},
+	}, nil)
+
+	status, err := scheduler.Status(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "api-2", status.Instance)
+	assert.False(t, status.Leader)
+	assert.Equal(t, "UTC", status.Timezone)
+	require.Len(t, status.Jobs, 2)
+	assert.Equal(t, "cbr_rates", status.Jobs[0].Name)
+	assert.True(t, status.Jobs[0].Running)
+	require.NotNil(t, status.Jobs[0].LastRun)
+	assert.Equal(t, "api-1", status.Jobs[0].LastRun.Instance)
+	assert.Equal(t, "reconcile", status.Jobs[1].Name)
+	assert.False(t, status.Jobs[1].Running)
+	assert.Nil(t, status.Jobs[1].LastRun)
+}
+
+func TestSchedulerLastFinishedRun_Never(t *testing.T) {
+	ctx := context.Background()
+	scheduler, mockRepo := setupScheduler(newFakeLocks(), "api-1")
+	mockRepo.On("GetLastFinishedJobRun", ctx, "cbr_rates").Return(nil, postgres.ErrNotFound)
+
+	run, err := scheduler.LastFinishedRun(ctx, "cbr_rates")
+	require.NoError(t, err)
+	assert.Nil(t, run)
+}
@@ -1,6 +1,7 @@
 package service
 
 import (
+	"RnD-service/internal/adapter/postgres"
 	"RnD-service/internal/entity"
 	"context"
 	"time"
@@ -8,6 +9,84 @@ import (
 
 type CurrencyService interface {
 	StoreRatesFromCbr(ctx context.Context) error
-	GetRateByCharCode(ctx context.Context, charCode string) (*entity.Currency, error)
-	GetRateByCharCodeAndDate(ctx context.Context, charCode string, date time.Time) (*entity.Currency, error)
+	StoreRatesFromProvider(ctx context.Context, source string) error
+	BaseCurrency(source string) (string, error)
+	GetRateByCharCode(ctx context.Context, source, charCode string) (*entity.Currency, error)
+	GetRateByCharCodeAndDate(ctx context.Context, source, charCode string, date time.Time) (*entity.Currency, error)
+	GetRatesByCharCodesAndDate(ctx context.Context, source string, charCodes []string, date time.Time) (map[string]entity.Currency, error)
+	GetRatesByCharCodeAndDateRange(ctx context.Context, source, charCode string, dateFrom, dateTo time.Time) ([]entity.Currency, error)
+	GetRateStats(ctx context.Context, source, charCode string, dateFrom, dateTo time.Time) (*entity.RateStats, error)
+
+	SyncCurrencyCatalog(ctx context.Context) error
+	GetCurrencyCatalog(ctx context.Context) ([]entity.CurrencyInfo, error)
+	GetCurrencyByCharCode(ctx context.Context, charCode string) (*entity.CurrencyInfo, error)
+}
+
+type MetalPriceService interface {
+	StoreMetalPricesFromCbr(ctx context.Context) error
+	GetMetalPrice(ctx context.Context, code string, date time.Time) (*entity.MetalPrice, error)
+	GetMetalPricesByDateRange(ctx context.Context, code string, dateFrom, dateTo time.Time) ([]entity.MetalPrice, error)
+}
+
+type IndicatorRateService interface {
+	SyncIndicators(ctx context.Context) error
+	GetKeyRates(ctx context.Context, dateFrom, dateTo time.Time) ([]entity.KeyRate, error)
+	GetRuoniaRates(ctx context.Context, dateFrom, dateTo time.Time) ([]entity.RuoniaRate, error)
+}
+
+type RateReconciliationService interface {
+	Reconcile(ctx context.Context, date time.Time) ([]entity.RateDiscrepancy, error)
+	ListDiscrepancies(ctx context.Context, dateFrom, dateTo time.Time) ([]entity.RateDiscrepancy, error)
+}
+
+type BackfillJobService interface {
+	CreateJob(ctx context.Context, dateFrom, dateTo time.Time, charCodes []string) (*entity.BackfillJob, error)
+	GetJob(ctx context.Context, id int64) (*entity.BackfillJob, error)
+	ListJobs(ctx context.Context) ([]entity.BackfillJob, error)
+	Start(id int64) error
+	Run(ctx context.Context, id int64) error
+}
+
+type RateAlertService interface {
+	CreateRule(ctx context.Context, rule *entity.AlertRule) (*entity.AlertRule, error)
+	UpdateRule(ctx context.Context, rule *entity.AlertRule) (*entity.AlertRule, error)
+	DeleteRule(ctx context.Context, id int64) error
+	GetRule(ctx context.Context, id int64) (*entity.AlertRule, error)
+	ListRules(ctx context.Context) ([]entity.AlertRule, error)
+	ListAlerts(ctx context.Context, dateFrom, dateTo time.Time) ([]entity.Alert, error)
+	ListDeadLetters(ctx context.Context) ([]entity.WebhookDeadLetter, error)
+}
+
+type HealthCheckService interface {
+	Check(ctx context.Context) *entity.HealthReport
+}
+
+// JobReporter is what the health check reads about the rate sync job.
+type JobReporter interface {
+	NextRun(name string) time.Time
+	LastFinishedRun(ctx context.Context, name string) (*entity.JobRun, error)
+}
+
+// JobScheduler lists the scheduled jobs and triggers them by hand.
+type JobScheduler interface {
+	Status(ctx context.Context) (*entity.SchedulerStatus, error)
+	RunJob(ctx context.Context, name string) (*entity.JobRun, error)
+}
+
+// SchemaChecker reports the applied and known schema migrations.
+type SchemaChecker interface {
+	Status(ctx context.Context) (*postgres.MigrationStatus, error)
+}
+
+// RateAlerter is told about every batch of rates stored from a provider.
+type RateAlerter interface {
+	Evaluate(ctx context.Context, rates []entity.Currency) error
+}
+
+// RateObserver is told how historical lookups were served (hit means from the
+// DB alone), the newest publication stored per source and successful daily syncs.
+type RateObserver interface {
+	ObserveRateLookup(lookup string, hit bool)
+	ObserveRatesStored(source string, latest time.Time)
+	ObserveRateSync(source string, at time.Time)
 }
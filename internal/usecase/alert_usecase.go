@@ -0,0 +1,248 @@
+package usecase
+
+import (
+	"RnD-service/internal/entity"
+	"RnD-service/internal/service"
+	"RnD-service/pkg/netguard"
+	"context"
+	"fmt"
+	"net"
+	"net/netip"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+type RateAlertUsecase struct {
+	service service.RateAlertService
+	rates   service.CurrencyService
+	logger  *logrus.Logger
+
+	webhookAllowlist netguard.Allowlist
+	lookupIP         func(ctx context.Context, host string) ([]netip.Addr, error)
+}
+
+func NewRateAlertUsecase(service service.RateAlertService, rates service.CurrencyService, logger *logrus.Logger) *RateAlertUsecase {
+	return &RateAlertUsecase{
+		service: service,
+		rates:   rates,
+		logger:  logger,
+		lookupIP: func(ctx context.Context, host string) ([]netip.Addr, error) {
+			return net.DefaultResolver.LookupNetIP(ctx, "ip", host)
+		},
+	}
+}
+
+// SetWebhookAllowlist lets webhook URLs point to the listed internal networks.
+func (uc *RateAlertUsecase) SetWebhookAllowlist(allowlist netguard.Allowlist) {
+	uc.webhookAllowlist = allowlist
+}
+
+func (uc *RateAlertUsecase) CreateAlertRule(ctx context.Context, input AlertRuleInput) (*AlertRuleResponse, error) {
+	rule, err := uc.toAlertRule(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+
+	created, err := uc.service.CreateRule(ctx, rule)
+	if err != nil {
+		uc.logger.WithError(err).Errorf("Failed to create alert rule for %s/%s", rule.Source, rule.CharCode)
+		return nil, err
+	}
+
+	// the generated secret is shown once, receivers need it to verify signatures
+	result := toAlertRuleResponse(created)
+	result.Secret = created.Secret
+	return result, nil
+}
+
+func (uc *RateAlertUsecase) UpdateAlertRule(ctx context.Context, id int64, input AlertRuleInput) (*AlertRuleResponse, error) {
+	rule, err := uc.toAlertRule(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+	rule.ID = id
+
+	updated, err := uc.service.UpdateRule(ctx, rule)
+	if err != nil {
+		uc.logger.WithError(err).Errorf("Failed to update alert rule %d", id)
+		return nil, err
+	}
+	return toAlertRuleResponse(updated), nil
+}
+
+func (uc *RateAlertUsecase) DeleteAlertRule(ctx context.Context, id int64) error {
+	if err := uc.service.DeleteRule(ctx, id); err != nil {
+		uc.logger.WithError(err).Errorf("Failed to delete alert rule %d", id)
+		return err
+	}
+	return nil
+}
+
+func (uc *RateAlertUsecase) GetAlertRule(ctx context.Context, id int64) (*AlertRuleResponse, error) {
+	rule, err := uc.service.GetRule(ctx, id)
+	if err != nil {
+		uc.logger.WithError(err).Errorf("Failed to get alert rule %d", id)
+		return nil, err
+	}
+	return toAlertRuleResponse(rule), nil
+}
+
+func (uc *RateAlertUsecase) ListAlertRules(ctx context.Context) ([]AlertRuleResponse, error) {
+	rules, err := uc.service.ListRules(ctx)
+	if err != nil {
+		uc.logger.WithError(err).Error("Failed to list alert rules")
+		return nil, err
+	}
+
+	result := make([]AlertRuleResponse, 0, len(rules))
+	for i := range rules {
+		result = append(result, *toAlertRuleResponse(&rules[i]))
+	}
+	return result, nil
+}
+
+func (uc *RateAlertUsecase) GetAlerts(ctx context.Context, dateFrom, dateTo time.Time) (*AlertListResponse, error) {
+	dateTo, err := validateHistoryRange(uc.logger.WithContext(ctx), dateFrom, dateTo)
+	if err != nil {
+		return nil, err
+	}
+
+	alerts, err := uc.service.ListAlerts(ctx, dateFrom, dateTo)
+	if err != nil {
+		uc.logger.WithError(err).Errorf("Failed to get alerts for %s - %s", dateFrom.Format("2006-01-02"), dateTo.Format("2006-01-02"))
+		return nil, err
+	}
+
+	result := &AlertListResponse{
+		From:   dateFrom.Format("2006-01-02"),
+		To:     dateTo.Format("2006-01-02"),
+		Alerts: make([]AlertResponse, 0, len(alerts)),
+	}
+	for _, a := range alerts {
+		result.Alerts = append(result.Alerts, AlertResponse{
+			ID:            a.ID,
+			RuleID:        a.RuleID,
+			Source:        a.Source,
+			CharCode:      a.CharCode,
+			Date:          a.Date.Format("2006-01-02"),
+			PreviousDate:  a.PreviousDate.Format("2006-01-02"),
+			PreviousValue: a.PreviousValue,
+			Value:         a.Value,
+			Change:        a.Change,
+			ChangePercent: a.ChangePercent,
+			Status:        a.Status,
+			Attempts:      a.Attempts,
+			LastError:     a.LastError,
+			TriggeredAt:   a.TriggeredAt,
+			DeliveredAt:   a.DeliveredAt,
+		})
+	}
+
+	uc.logger.Infof("Successfully fetched %d alerts between %s and %s", len(result.Alerts), result.From, result.To)
+	return result, nil
+}
+
+func (uc *RateAlertUsecase) GetDeadLetters(ctx context.Context) ([]DeadLetterResponse, error) {
+	letters, err := uc.service.ListDeadLetters(ctx)
+	if err != nil {
+		uc.logger.WithError(err).Error("Failed to get webhook dead letters")
+		return nil, err
+	}
+
+	result := make([]DeadLetterResponse, 0, len(letters))
+	for _, l := range letters {
+		result = append(result, DeadLetterResponse{
+			ID:        l.ID,
+			AlertID:   l.AlertID,
+			URL:       l.URL,
+			Payload:   l.Payload,
+			Attempts:  l.Attempts,
+			LastError: l.LastError,
+			CreatedAt: l.CreatedAt,
+		})
+	}
+	return result, nil
+}
+
+func (uc *RateAlertUsecase) toAlertRule(ctx context.Context, input AlertRuleInput) (*entity.AlertRule, error) {
+	code := strings.ToUpper(strings.TrimSpace(input.CharCode))
+	if !charCodeRegexp.MatchString(code) {
+		uc.logger.Errorf("Invalid currency code format in alert rule: %s", code)
+		return nil, fmt.Errorf("%w: %s, expected 3 uppercase letters", ErrInvalidCharCode, code)
+	}
+
+	source := normalizeSource(input.Source)
+	base, err := uc.rates.BaseCurrency(source)
+	if err != nil {
+		return nil, err
+	}
+	if code == base {
+		return nil, fmt.Errorf("%w: %s is the base currency of %s", ErrInvalidAlertRule, code, source)
+	}
+
+	webhookURL := strings.TrimSpace(input.WebhookURL)
+	if err := uc.validateWebhookURL(ctx, webhookURL); err != nil {
+		uc.logger.WithContext(ctx).WithError(err).Warn("Rejected alert rule webhook URL")
+		return nil, err
+	}
+
+	enabled := true
+	if input.Enabled != nil {
+		enabled = *input.Enabled
+	}
+
+	return &entity.AlertRule{
+		Source:        source,
+		CharCode:      code,
+		ThresholdType: strings.ToLower(input.ThresholdType),
+		Threshold:     input.Threshold,
+		Direction:     strings.ToLower(input.Direction),
+		WebhookURL:    webhookURL,
+		Secret:        input.Secret,
+		Enabled:       enabled,
+	}, nil
+}
+
+// validateWebhookURL accepts http(s) URLs whose host does not resolve to a
+// loopback, private or link-local address outside the allowlist.
+func (uc *RateAlertUsecase) validateWebhookURL(ctx context.Context, raw string) error {
+	u, err := url.Parse(raw)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Hostname() == "" {
+		return fmt.Errorf("%w: webhook_url must be an absolute http(s) URL", ErrInvalidAlertRule)
+	}
+
+	host := u.Hostname()
+	addrs := make([]netip.Addr, 0, 1)
+	if addr, err := netip.ParseAddr(host); err == nil {
+		addrs = append(addrs, addr)
+	} else {
+		addrs, err = uc.lookupIP(ctx, host)
+		if err != nil {
+			return fmt.Errorf("%w: webhook_url host %s cannot be resolved: %v", ErrInvalidAlertRule, host, err)
+		}
+	}
+	for _, addr := range addrs {
+		if err := uc.webhookAllowlist.Check(addr); err != nil {
+			return fmt.Errorf("%w: webhook_url host %s: %v", ErrInvalidAlertRule, host, err)
+		}
+	}
+	return nil
+}
+
+func toAlertRuleResponse(rule *entity.AlertRule) *AlertRuleResponse {
+	return &AlertRuleResponse{
+		ID:            rule.ID,
+		Source:        rule.Source,
+		CharCode:      rule.CharCode,
+		ThresholdType: rule.ThresholdType,
+		Threshold:     rule.Threshold,
+		Direction:     rule.Direction,
+		WebhookURL:    rule.WebhookURL,
+		Enabled:       rule.Enabled,
+		CreatedAt:     rule.CreatedAt,
+		UpdatedAt:     rule.UpdatedAt,
+	}
+}
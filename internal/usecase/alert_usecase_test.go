@@ -0,0 +1,257 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"net/netip"
+	"testing"
+	"time"
+
+	"RnD-service/internal/entity"
+	"RnD-service/pkg/netguard"
+
+	"github.com/shopspring/decimal"
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type mockAlertService struct {
+	mock.Mock
+}
+
+func (m *mockAlertService) CreateRule(ctx context.Context, rule *entity.AlertRule) (*entity.AlertRule, error) {
+	args := m.Called(ctx, rule)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.AlertRule), args.Error(1)
+}
+
+func (m *mockAlertService) UpdateRule(ctx context.Context, rule *entity.AlertRule) (*entity.AlertRule, error) {
+	args := m.Called(ctx, rule)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.AlertRule), args.Error(1)
+}
+
+func (m *mockAlertService) DeleteRule(ctx context.Context, id int64) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *mockAlertService) GetRule(ctx context.Context, id int64) (*entity.AlertRule, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.AlertRule), args.Error(1)
+}
+
+func (m *mockAlertService) ListRules(ctx context.Context) ([]entity.AlertRule, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]entity.AlertRule), args.Error(1)
+}
+
+func (m *mockAlertService) ListAlerts(ctx context.Context, dateFrom, dateTo time.Time) ([]entity.Alert, error) {
+	args := m.Called(ctx, dateFrom, dateTo)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]entity.Alert), args.Error(1)
+}
+
+func (m *mockAlertService) ListDeadLetters(ctx context.Context) ([]entity.WebhookDeadLetter, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]entity.WebhookDeadLetter), args.Error(1)
+}
+
+func setupAlertUsecase() (*RateAlertUsecase, *mockAlertService, *mockCurrencyService) {
+	mockAlerts := new(mockAlertService)
+	mockRates := new(mockCurrencyService)
+	logger, _ := test.NewNullLogger()
+	uc := NewRateAlertUsecase(mockAlerts, mockRates, logger)
+	uc.lookupIP = func(ctx context.Context, host string) ([]netip.Addr, error) {
+		switch host {
+		case "hooks.example.com":
+			return []netip.Addr{netip.MustParseAddr("93.184.216.34")}, nil
+		case "hooks.internal":
+			return []netip.Addr{netip.MustParseAddr("93.184.216.34"), netip.MustParseAddr("10.0.0.5")}, nil
+		}
+		return nil, fmt.Errorf("lookup %s: no such host", host)
+	}
+	return uc, mockAlerts, mockRates
+}
+
+func alertRuleInput() AlertRuleInput {
+	return AlertRuleInput{
+		CharCode:      "usd",
+		ThresholdType: "Percent",
+		Threshold:     decimal.RequireFromString("0.5"),
+		Direction:     "up",
+		WebhookURL:    " https://hooks.example.com/rates ",
+	}
+}
+
+func TestCreateAlertRule_ReturnsSecretOnce(t *testing.T) {
+	ctx := context.Background()
+	uc, mockAlerts, mockRates := setupAlertUsecase()
+
+	mockRates.On("BaseCurrency", "cbr").Return("RUB", nil)
+	mockAlerts.On("CreateRule", ctx, mock.MatchedBy(func(r *entity.AlertRule) bool {
+		return r.Source == "cbr" && r.CharCode == "USD" && r.ThresholdType == entity.AlertThresholdPercent &&
+			r.WebhookURL == "https://hooks.example.com/rates" && r.Enabled
+	})).Return(&entity.AlertRule{ID: 5, Source: "cbr", CharCode: "USD", Secret: "generated", Enabled: true}, nil)
+	mockAlerts.On("GetRule", ctx, int64(5)).Return(&entity.AlertRule{ID: 5, Source: "cbr", CharCode: "USD", Secret: "generated", Enabled: true}, nil)
+
+	created, err := uc.CreateAlertRule(ctx, alertRuleInput())
+	require.NoError(t, err)
+	assert.Equal(t, int64(5), created.ID)
+	assert.Equal(t, "generated", created.Secret)
+
+	stored, err := uc.GetAlertRule(ctx, 5)
+	require.NoError(t, err)
+	assert.Empty(t, stored.Secret)
+	mockAlerts.AssertExpectations(t)
+}
+
+func TestCreateAlertRule_Disabled(t *testing.T) {
+	ctx := context.Background()
+	uc, mockAlerts, mockRates := setupAlertUsecase()
+
+	enabled := false
+	input := alertRuleInput()
+	input.Source = "ECB"
+	input.Enabled = &enabled
+	mockRates.On("BaseCurrency", "ecb").Return("EUR", nil)
+	mockAlerts.On("CreateRule", ctx, mock.MatchedBy(func(r *entity.AlertRule) bool {
+		return r.Source == "ecb" && !r.Enabled
+	})).Return(&entity.AlertRule{ID: 6, Source: "ecb"}, nil)
+
+	_, err := uc.CreateAlertRule(ctx, input)
+	require.NoError(t, err)
+	mockAlerts.AssertExpectations(t)
+}
+
+func TestCreateAlertRule_Validation(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("invalid char code", func(t *testing.T) {
+		uc, mockAlerts, _ := setupAlertUsecase()
+		input := alertRuleInput()
+		input.CharCode = "US1"
+
+		_, err := uc.CreateAlertRule(ctx, input)
+		assert.ErrorIs(t, err, ErrInvalidCharCode)
+		mockAlerts.AssertNotCalled(t, "CreateRule", mock.Anything, mock.Anything)
+	})
+
+	t.Run("unknown source", func(t *testing.T) {
+		uc, mockAlerts, mockRates := setupAlertUsecase()
+		input := alertRuleInput()
+		input.Source = "nbk"
+		mockRates.On("BaseCurrency", "nbk").Return("", fmt.Errorf("%w: nbk", ErrUnknownSource))
+
+		_, err := uc.CreateAlertRule(ctx, input)
+		assert.ErrorIs(t, err, ErrUnknownSource)
+		mockAlerts.AssertNotCalled(t, "CreateRule", mock.Anything, mock.Anything)
+	})
+
+	t.Run("base currency", func(t *testing.T) {
+		uc, mockAlerts, mockRates := setupAlertUsecase()
+		input := alertRuleInput()
+		input.CharCode = "RUB"
+		mockRates.On("BaseCurrency", "cbr").Return("RUB", nil)
+
+		_, err := uc.CreateAlertRule(ctx, input)
+		assert.ErrorIs(t, err, ErrInvalidAlertRule)
+		mockAlerts.AssertNotCalled(t, "CreateRule", mock.Anything, mock.Anything)
+	})
+}
+
+func TestCreateAlertRule_WebhookURL(t *testing.T) {
+	ctx := context.Background()
+
+	for name, webhookURL := range map[string]string{
+		"not http":           "ftp://hooks.example.com/rates",
+		"relative":           "/rates",
+		"loopback":           "http://127.0.0.1:8080/admin/backfill",
+		"localhost":          "http://localhost/hook",
+		"ipv6 loopback":      "http://[::1]/hook",
+		"metadata":           "http://169.254.169.254/latest/meta-data",
+		"private":            "https://192.168.0.10/hook",
+		"resolves privately": "https://hooks.internal/hook",
+		"unresolvable":       "https://nowhere.example.com/hook",
+	} {
+		t.Run(name, func(t *testing.T) {
+			uc, mockAlerts, mockRates := setupAlertUsecase()
+			mockRates.On("BaseCurrency", "cbr").Return("RUB", nil)
+			input := alertRuleInput()
+			input.WebhookURL = webhookURL
+
+			_, err := uc.CreateAlertRule(ctx, input)
+			assert.ErrorIs(t, err, ErrInvalidAlertRule)
+			mockAlerts.AssertNotCalled(t, "CreateRule", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestCreateAlertRule_WebhookURLAllowlisted(t *testing.T) {
+	ctx := context.Background()
+	uc, mockAlerts, mockRates := setupAlertUsecase()
+	allowlist, err := netguard.ParseAllowlist([]string{"10.0.0.0/24"})
+	require.NoError(t, err)
+	uc.SetWebhookAllowlist(allowlist)
+
+	input := alertRuleInput()
+	input.WebhookURL = "https://hooks.internal/hook"
+	mockRates.On("BaseCurrency", "cbr").Return("RUB", nil)
+	mockAlerts.On("CreateRule", ctx, mock.Anything).Return(&entity.AlertRule{ID: 7, Source: "cbr"}, nil)
+
+	_, err = uc.CreateAlertRule(ctx, input)
+	require.NoError(t, err)
+	mockAlerts.AssertExpectations(t)
+}
+
+func TestGetAlerts(t *testing.T) {
+	ctx := context.Background()
+	uc, mockAlerts, _ := setupAlertUsecase()
+
+	from := time.Date(2025, 8, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2025, 8, 5, 0, 0, 0, 0, time.UTC)
+	mockAlerts.On("ListAlerts", ctx, from, to).Return([]entity.Alert{{
+		ID:            11,
+		RuleID:        5,
+		CharCode:      "USD",
+		Date:          to,
+		PreviousDate:  time.Date(2025, 8, 2, 0, 0, 0, 0, time.UTC),
+		ChangePercent: decimal.RequireFromString("0.3711"),
+		Status:        entity.AlertDelivered,
+	}}, nil)
+
+	result, err := uc.GetAlerts(ctx, from, to)
+	require.NoError(t, err)
+	require.Len(t, result.Alerts, 1)
+	assert.Equal(t, "2025-08-05", result.Alerts[0].Date)
+	assert.Equal(t, "2025-08-02", result.Alerts[0].PreviousDate)
+	assert.Equal(t, entity.AlertDelivered, result.Alerts[0].Status)
+}
+
+func TestGetAlerts_InvalidRange(t *testing.T) {
+	uc, mockAlerts, _ := setupAlertUsecase()
+
+	from := time.Date(2025, 8, 5, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2025, 8, 1, 0, 0, 0, 0, time.UTC)
+
+	_, err := uc.GetAlerts(context.Background(), from, to)
+	assert.ErrorIs(t, err, ErrInvalidDateRange)
+	mockAlerts.AssertNotCalled(t, "ListAlerts", mock.Anything, mock.Anything, mock.Anything)
+}
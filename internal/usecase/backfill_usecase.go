@@ -0,0 +1,150 @@
+package usecase
+
+import (
+	"RnD-service/internal/entity"
+	"RnD-service/internal/service"
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+type BackfillUsecase struct {
+	service service.BackfillJobService
+	logger  *logrus.Logger
+}
+
+func NewBackfillUsecase(service service.BackfillJobService, logger *logrus.Logger) *BackfillUsecase {
+	return &BackfillUsecase{
+		service: service,
+		logger:  logger,
+	}
+}
+
+func (uc *BackfillUsecase) StartBackfill(ctx context.Context, dateFrom, dateTo time.Time, charCodes []string) (*BackfillJobResponse, error) {
+	job, err := uc.createJob(ctx, dateFrom, dateTo, charCodes)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := uc.service.Start(job.ID); err != nil {
+		uc.logger.WithError(err).Errorf("Failed to start backfill job %d", job.ID)
+		return nil, err
+	}
+
+	uc.logger.Infof("Started backfill job %d", job.ID)
+	return toBackfillJobResponse(job), nil
+}
+
+// RunBackfill creates a job and blocks until it finishes.
+func (uc *BackfillUsecase) RunBackfill(ctx context.Context, dateFrom, dateTo time.Time, charCodes []string) (*BackfillJobResponse, error) {
+	job, err := uc.createJob(ctx, dateFrom, dateTo, charCodes)
+	if err != nil {
+		return nil, err
+	}
+	return uc.runJob(ctx, job.ID)
+}
+
+func (uc *BackfillUsecase) ResumeBackfill(ctx context.Context, id int64) (*BackfillJobResponse, error) {
+	job, err := uc.service.GetJob(ctx, id)
+	if err != nil {
+		uc.logger.WithError(err).Errorf("Failed to get backfill job %d", id)
+		return nil, err
+	}
+
+	if err := uc.service.Start(job.ID); err != nil {
+		uc.logger.WithError(err).Errorf("Failed to resume backfill job %d", job.ID)
+		return nil, err
+	}
+
+	uc.logger.Infof("Resumed backfill job %d from %s", job.ID, job.NextDate.Format("2006-01-02"))
+	return toBackfillJobResponse(job), nil
+}
+
+// RunExistingBackfill resumes a job in the foreground.
+func (uc *BackfillUsecase) RunExistingBackfill(ctx context.Context, id int64) (*BackfillJobResponse, error) {
+	return uc.runJob(ctx, id)
+}
+
+func (uc *BackfillUsecase) GetBackfillJob(ctx context.Context, id int64) (*BackfillJobResponse, error) {
+	job, err := uc.service.GetJob(ctx, id)
+	if err != nil {
+		uc.logger.WithError(err).Errorf("Failed to get backfill job %d", id)
+		return nil, err
+	}
+	return toBackfillJobResponse(job), nil
+}
+
+func (uc *BackfillUsecase) ListBackfillJobs(ctx context.Context) ([]BackfillJobResponse, error) {
+	jobs, err := uc.service.ListJobs(ctx)
+	if err != nil {
+		uc.logger.WithError(err).Error("Failed to list backfill jobs")
+		return nil, err
+	}
+
+	result := make([]BackfillJobResponse, 0, len(jobs))
+	for i := range jobs {
+		result = append(result, *toBackfillJobResponse(&jobs[i]))
+	}
+	return result, nil
+}
+
+func (uc *BackfillUsecase) createJob(ctx context.Context, dateFrom, dateTo time.Time, charCodes []string) (*entity.BackfillJob, error) {
+	codes := make([]string, 0, len(charCodes))
+	for _, charCode := range charCodes {
+		code := strings.ToUpper(strings.TrimSpace(charCode))
+		if code == "" {
+			continue
+		}
+		if !charCodeRegexp.MatchString(code) {
+			uc.logger.Errorf("Invalid currency code format in backfill filter: %s", code)
+			return nil, fmt.Errorf("%w: %s, expected 3 uppercase letters", ErrInvalidCharCode, code)
+		}
+		codes = append(codes, code)
+	}
+
+	job, err := uc.service.CreateJob(ctx, dateFrom, dateTo, codes)
+	if err != nil {
+		uc.logger.WithError(err).Errorf("Failed to create backfill job for %s - %s", dateFrom.Format("2006-01-02"), dateTo.Format("2006-01-02"))
+		return nil, err
+	}
+	return job, nil
+}
+
+func (uc *BackfillUsecase) runJob(ctx context.Context, id int64) (*BackfillJobResponse, error) {
+	runErr := uc.service.Run(ctx, id)
+
+	job, err := uc.service.GetJob(context.WithoutCancel(ctx), id)
+	if err != nil {
+		uc.logger.WithError(err).Errorf("Failed to get backfill job %d", id)
+		return nil, err
+	}
+	if runErr != nil {
+		uc.logger.WithError(runErr).Errorf("Backfill job %d did not complete", id)
+		return toBackfillJobResponse(job), runErr
+	}
+	return toBackfillJobResponse(job), nil
+}
+
+func toBackfillJobResponse(job *entity.BackfillJob) *BackfillJobResponse {
+	resp := &BackfillJobResponse{
+		ID:         job.ID,
+		From:       job.DateFrom.Format("2006-01-02"),
+		To:         job.DateTo.Format("2006-01-02"),
+		CharCodes:  job.CharCodes,
+		Status:     job.Status,
+		NextDate:   job.NextDate.Format("2006-01-02"),
+		DaysTotal:  job.DaysTotal,
+		DaysDone:   job.DaysDone,
+		Error:      job.Error,
+		CreatedAt:  job.CreatedAt,
+		UpdatedAt:  job.UpdatedAt,
+		FinishedAt: job.FinishedAt,
+	}
+	if job.DaysTotal > 0 {
+		resp.Progress = float64(job.DaysDone) * 100 / float64(job.DaysTotal)
+	}
+	return resp
+}
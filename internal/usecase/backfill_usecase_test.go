@@ -0,0 +1,125 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"RnD-service/internal/entity"
+
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type mockBackfillService struct {
+	mock.Mock
+}
+
+func (m *mockBackfillService) CreateJob(ctx context.Context, dateFrom, dateTo time.Time, charCodes []string) (*entity.BackfillJob, error) {
+	args := m.Called(ctx, dateFrom, dateTo, charCodes)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.BackfillJob), args.Error(1)
+}
+
+func (m *mockBackfillService) GetJob(ctx context.Context, id int64) (*entity.BackfillJob, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.BackfillJob), args.Error(1)
+}
+
+func (m *mockBackfillService) ListJobs(ctx context.Context) ([]entity.BackfillJob, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]entity.BackfillJob), args.Error(1)
+}
+
+func (m *mockBackfillService) Start(id int64) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func (m *mockBackfillService) Run(ctx context.Context, id int64) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func setupBackfillUsecase() (*BackfillUsecase, *mockBackfillService) {
+	mockService := new(mockBackfillService)
+	logger, _ := test.NewNullLogger()
+	return NewBackfillUsecase(mockService, logger), mockService
+}
+
+func TestStartBackfill(t *testing.T) {
+	ctx := context.Background()
+	uc, mockService := setupBackfillUsecase()
+
+	from := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2023, 1, 31, 0, 0, 0, 0, time.UTC)
+	job := &entity.BackfillJob{ID: 1, DateFrom: from, DateTo: to, CharCodes: []string{"USD", "EUR"}, Status: entity.BackfillPending, NextDate: from, DaysTotal: 31}
+	mockService.On("CreateJob", ctx, from, to, []string{"USD", "EUR"}).Return(job, nil)
+	mockService.On("Start", int64(1)).Return(nil)
+
+	result, err := uc.StartBackfill(ctx, from, to, []string{"usd", " EUR ", ""})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), result.ID)
+	assert.Equal(t, "2023-01-01", result.From)
+	assert.Equal(t, "2023-01-01", result.NextDate)
+	assert.Equal(t, entity.BackfillPending, result.Status)
+	mockService.AssertExpectations(t)
+}
+
+func TestStartBackfill_InvalidCode(t *testing.T) {
+	ctx := context.Background()
+	uc, mockService := setupBackfillUsecase()
+
+	_, err := uc.StartBackfill(ctx, time.Now(), time.Now(), []string{"US1"})
+	assert.ErrorIs(t, err, ErrInvalidCharCode)
+	mockService.AssertNotCalled(t, "CreateJob", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestRunBackfill_ReturnsFinalState(t *testing.T) {
+	ctx := context.Background()
+	uc, mockService := setupBackfillUsecase()
+
+	from := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	mockService.On("CreateJob", ctx, from, from, []string{}).Return(&entity.BackfillJob{ID: 2, DateFrom: from, DateTo: from, DaysTotal: 1}, nil)
+	mockService.On("Run", ctx, int64(2)).Return(errors.New("CBR is unavailable"))
+	mockService.On("GetJob", mock.Anything, int64(2)).Return(&entity.BackfillJob{ID: 2, DateFrom: from, DateTo: from, Status: entity.BackfillFailed, DaysTotal: 1, Error: "CBR is unavailable"}, nil)
+
+	result, err := uc.RunBackfill(ctx, from, from, nil)
+	assert.Error(t, err)
+	require.NotNil(t, result)
+	assert.Equal(t, entity.BackfillFailed, result.Status)
+	assert.Equal(t, "CBR is unavailable", result.Error)
+}
+
+func TestGetBackfillJob_Progress(t *testing.T) {
+	ctx := context.Background()
+	uc, mockService := setupBackfillUsecase()
+
+	mockService.On("GetJob", ctx, int64(3)).Return(&entity.BackfillJob{ID: 3, Status: entity.BackfillRunning, DaysTotal: 40, DaysDone: 10}, nil)
+
+	result, err := uc.GetBackfillJob(ctx, 3)
+	require.NoError(t, err)
+	assert.Equal(t, 25.0, result.Progress)
+}
+
+func TestResumeBackfill_AlreadyRunning(t *testing.T) {
+	ctx := context.Background()
+	uc, mockService := setupBackfillUsecase()
+
+	mockService.On("GetJob", ctx, int64(3)).Return(&entity.BackfillJob{ID: 3, Status: entity.BackfillRunning}, nil)
+	mockService.On("Start", int64(3)).Return(ErrBackfillJobRunning)
+
+	_, err := uc.ResumeBackfill(ctx, 3)
+	assert.ErrorIs(t, err, ErrBackfillJobRunning)
+}
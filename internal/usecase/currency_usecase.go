@@ -1,90 +1,617 @@
 package usecase
 
 import (
+	"RnD-service/internal/entity"
 	"RnD-service/internal/service"
+	"RnD-service/pkg/tracing"
 	"context"
-	"errors"
+	"fmt"
 	"regexp"
+	"sort"
 	"strings"
 	"time"
 
+	"github.com/shopspring/decimal"
 	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
 type CurrencyUsecase struct {
-	service service.CurrencyService
-	logger  *logrus.Logger
+	service  service.CurrencyService
+	rounding Rounding
+	tracer   trace.Tracer
+	logger   *logrus.Logger
 }
 
-func NewCurrencyUsecase(service service.CurrencyService, logger *logrus.Logger) *CurrencyUsecase {
+func NewCurrencyUsecase(service service.CurrencyService, rounding Rounding, logger *logrus.Logger) *CurrencyUsecase {
 	return &CurrencyUsecase{
-		service: service,
-		logger:  logger,
+		service:  service,
+		rounding: rounding,
+		logger:   logger,
 	}
 }
 
+// SetTracer enables spans for every use case call.
+func (uc *CurrencyUsecase) SetTracer(tracer trace.Tracer) {
+	uc.tracer = tracer
+}
+
 var charCodeRegexp = regexp.MustCompile(`^[A-Z]{3}$`)
 
+const defaultSource = "cbr"
+
 func (uc *CurrencyUsecase) FetchAndStoreRatesFromCBR(ctx context.Context) error {
-	uc.logger.Info("Fetching rates from API...")
-	return uc.service.StoreRatesFromCbr(ctx)
+	return uc.FetchAndStoreRates(ctx, defaultSource)
+}
+
+func (uc *CurrencyUsecase) FetchAndStoreRates(ctx context.Context, source string) error {
+	ctx, span := tracing.Start(ctx, uc.tracer, "CurrencyUsecase.FetchAndStoreRates", attribute.String("rate.source", source))
+	defer span.End()
+	logger := uc.logger.WithContext(ctx)
+
+	source = normalizeSource(source)
+	logger.Infof("Fetching rates from %s API...", source)
+	return uc.service.StoreRatesFromProvider(ctx, source)
 }
 
-func (uc *CurrencyUsecase) GetRateByCharCode(ctx context.Context, charCode string, amount float64) (*CurrencyResponse, error) {
+func (uc *CurrencyUsecase) GetRateByCharCode(ctx context.Context, source, charCode string, amount decimal.Decimal) (*CurrencyResponse, error) {
+	ctx, span := tracing.Start(ctx, uc.tracer, "CurrencyUsecase.GetRateByCharCode", attribute.String("rate.source", source), attribute.String("rate.char_code", charCode))
+	defer span.End()
+	logger := uc.logger.WithContext(ctx)
+
 	code := strings.ToUpper(charCode)
 
 	if !charCodeRegexp.MatchString(code) {
-		uc.logger.Errorf("Bad Valute format %s", code)
-		return nil, errors.New("invalid char code format")
+		logger.Errorf("Bad Valute format %s", code)
+		return nil, fmt.Errorf("%w: %s", ErrInvalidCharCode, code)
+	}
+
+	source = normalizeSource(source)
+	base, err := uc.service.BaseCurrency(source)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := uc.checkCatalog(ctx, base, code); err != nil {
+		return nil, err
 	}
 
-	currency, err := uc.service.GetRateByCharCode(ctx, code)
+	currency, err := uc.service.GetRateByCharCode(ctx, source, code)
 	if err != nil {
-		uc.logger.Errorf("Failed to get rate by char code")
+		logger.Errorf("Failed to get rate by char code")
 		return nil, err
 	}
 
-	convertedValue := (currency.Value / float64(currency.Nominal)) * amount
+	convertedValue := uc.rounding.Amount(currency.Value.Mul(amount), decimal.NewFromInt(int64(currency.Nominal)))
+	servedBy, fallback := servingSource(source, currency)
 
 	result := &CurrencyResponse{
 		CharCode: currency.CharCode,
 		ValueRUB: convertedValue,
+		Source:   servedBy,
+		Base:     base,
+		Fallback: fallback,
 	}
 
-	uc.logger.Infof("Successfuly fetched rate by char code!")
+	logger.Infof("Successfuly fetched rate by char code!")
 
 	return result, nil
 }
 
-func (uc *CurrencyUsecase) GetHistoricalRateByCharCode(ctx context.Context, charCode string, date time.Time, amount float64) (*CurrencyResponse, error) {
+func (uc *CurrencyUsecase) GetHistoricalRateByCharCode(ctx context.Context, source, charCode string, date time.Time, amount decimal.Decimal) (*CurrencyResponse, error) {
+	ctx, span := tracing.Start(ctx, uc.tracer, "CurrencyUsecase.GetHistoricalRateByCharCode", attribute.String("rate.source", source), attribute.String("rate.char_code", charCode))
+	defer span.End()
+	logger := uc.logger.WithContext(ctx)
+
 	code := strings.ToUpper(charCode)
 	if !charCodeRegexp.MatchString(code) {
-		uc.logger.Errorf("Invalid currency code format: %s", code)
-		return nil, errors.New("invalid char code format, expected 3 uppercase letters")
+		logger.Errorf("Invalid currency code format: %s", code)
+		return nil, fmt.Errorf("%w: %s, expected 3 uppercase letters", ErrInvalidCharCode, code)
 	}
 
 	if date.IsZero() {
 		date = time.Now().Truncate(24 * time.Hour)
-		uc.logger.Debugf("No date provided, using today: %s", date.Format("2006-01-02"))
+		logger.Debugf("No date provided, using today: %s", date.Format("2006-01-02"))
 	}
 
+	// tomorrow's rates may already be published; the service checks the source's calendar
 	today := time.Now().Truncate(24 * time.Hour)
-	if date.After(today) {
-		uc.logger.Warnf("Requested future date: %s", date.Format("2006-01-02"))
-		return nil, errors.New("cannot fetch rates for future dates")
+	if date.After(today.AddDate(0, 0, 1)) {
+		logger.Warnf("Requested future date: %s", date.Format("2006-01-02"))
+		return nil, ErrFutureDate
 	}
 
-	currency, err := uc.service.GetRateByCharCodeAndDate(ctx, code, date)
+	source = normalizeSource(source)
+	base, err := uc.service.BaseCurrency(source)
 	if err != nil {
-		uc.logger.WithError(err).Errorf("Failed to get historical rate by char code %s for date %s", code, date.Format("2006-01-02"))
 		return nil, err
 	}
 
-	convertedValue := (currency.Value / float64(currency.Nominal)) * amount
+	if err := uc.checkCatalog(ctx, base, code); err != nil {
+		return nil, err
+	}
+
+	currency, err := uc.service.GetRateByCharCodeAndDate(ctx, source, code, date)
+	if err != nil {
+		logger.WithError(err).Errorf("Failed to get historical rate by char code %s for date %s", code, date.Format("2006-01-02"))
+		return nil, err
+	}
+
+	convertedValue := uc.rounding.Amount(currency.Value.Mul(amount), decimal.NewFromInt(int64(currency.Nominal)))
+	servedBy, fallback := servingSource(source, currency)
+	if fallback {
+		logger.Warnf("Rate for %s on %s served by fallback source %s instead of %s", currency.CharCode, date.Format("2006-01-02"), servedBy, source)
+	}
 	result := &CurrencyResponse{
-		CharCode: currency.CharCode,
-		ValueRUB: convertedValue,
+		CharCode:      currency.CharCode,
+		ValueRUB:      convertedValue,
+		Source:        servedBy,
+		Base:          base,
+		Fallback:      fallback,
+		RequestedDate: date.Format("2006-01-02"),
+		EffectiveDate: effectiveDate(currency).Format("2006-01-02"),
+	}
+	logger.Infof("Successfully fetched historical rate for %s on %s (effective %s): %s %s for %s unit(s)", currency.CharCode, result.RequestedDate, result.EffectiveDate, convertedValue, base, amount)
+	return result, nil
+}
+
+func (uc *CurrencyUsecase) GetRateHistoryByCharCode(ctx context.Context, source, charCode string, dateFrom, dateTo time.Time) (*RateHistoryResponse, error) {
+	ctx, span := tracing.Start(ctx, uc.tracer, "CurrencyUsecase.GetRateHistoryByCharCode", attribute.String("rate.source", source), attribute.String("rate.char_code", charCode))
+	defer span.End()
+	logger := uc.logger.WithContext(ctx)
+
+	code := strings.ToUpper(charCode)
+	if !charCodeRegexp.MatchString(code) {
+		logger.Errorf("Invalid currency code format: %s", code)
+		return nil, fmt.Errorf("%w: %s, expected 3 uppercase letters", ErrInvalidCharCode, code)
+	}
+
+	dateTo, err := validateHistoryRange(logger, dateFrom, dateTo)
+	if err != nil {
+		return nil, err
+	}
+
+	source = normalizeSource(source)
+	base, err := uc.service.BaseCurrency(source)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := uc.checkCatalog(ctx, base, code); err != nil {
+		return nil, err
+	}
+
+	rates, err := uc.service.GetRatesByCharCodeAndDateRange(ctx, source, code, dateFrom, dateTo)
+	if err != nil {
+		logger.WithError(err).Errorf("Failed to get rate history by char code %s for %s - %s", code, dateFrom.Format("2006-01-02"), dateTo.Format("2006-01-02"))
+		return nil, err
 	}
-	uc.logger.Infof("Successfully fetched historical rate for %s on %s: %.4f RUB for %.2f unit(s)", currency.CharCode, currency.Date.Format("2006-01-02"), convertedValue, amount)
+
+	result := &RateHistoryResponse{
+		CharCode: code,
+		Source:   source,
+		Base:     base,
+		From:     dateFrom.Format("2006-01-02"),
+		To:       dateTo.Format("2006-01-02"),
+		Rates:    make([]RatePoint, 0, len(rates)),
+	}
+	for _, rate := range rates {
+		point := RatePoint{
+			Date:     rate.Date.Format("2006-01-02"),
+			Nominal:  rate.Nominal,
+			Value:    rate.Value,
+			ValueRUB: uc.rounding.Rate(rate.Value, decimal.NewFromInt(int64(rate.Nominal))),
+		}
+		if servedBy, fallback := servingSource(source, &rate); fallback {
+			point.Source = servedBy
+			result.Fallback = true
+		}
+		result.Rates = append(result.Rates, point)
+	}
+
+	logger.Infof("Successfully fetched %d historical rates for %s between %s and %s", len(result.Rates), code, result.From, result.To)
+	return result, nil
+}
+
+func (uc *CurrencyUsecase) GetRateStats(ctx context.Context, source, charCode string, dateFrom, dateTo time.Time) (*RateStatsResponse, error) {
+	ctx, span := tracing.Start(ctx, uc.tracer, "CurrencyUsecase.GetRateStats", attribute.String("rate.source", source), attribute.String("rate.char_code", charCode))
+	defer span.End()
+	logger := uc.logger.WithContext(ctx)
+
+	code := strings.ToUpper(charCode)
+	if !charCodeRegexp.MatchString(code) {
+		logger.Errorf("Invalid currency code format: %s", code)
+		return nil, fmt.Errorf("%w: %s, expected 3 uppercase letters", ErrInvalidCharCode, code)
+	}
+
+	dateTo, err := validateHistoryRange(logger, dateFrom, dateTo)
+	if err != nil {
+		return nil, err
+	}
+
+	source = normalizeSource(source)
+	base, err := uc.service.BaseCurrency(source)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := uc.checkCatalog(ctx, base, code); err != nil {
+		return nil, err
+	}
+
+	stats, err := uc.service.GetRateStats(ctx, source, code, dateFrom, dateTo)
+	if err != nil {
+		logger.WithError(err).Errorf("Failed to get rate statistics for %s between %s and %s", code, dateFrom.Format("2006-01-02"), dateTo.Format("2006-01-02"))
+		return nil, err
+	}
+
+	one := decimal.NewFromInt(1)
+	change := stats.Last.Sub(stats.First)
+	result := &RateStatsResponse{
+		CharCode:   code,
+		Source:     source,
+		Base:       base,
+		From:       dateFrom.Format("2006-01-02"),
+		To:         dateTo.Format("2006-01-02"),
+		Count:      stats.Count,
+		FirstDate:  stats.FirstDate.Format("2006-01-02"),
+		LastDate:   stats.LastDate.Format("2006-01-02"),
+		Min:        uc.rounding.Rate(stats.Min, one),
+		Max:        uc.rounding.Rate(stats.Max, one),
+		Mean:       uc.rounding.Rate(stats.Mean, one),
+		Median:     uc.rounding.Rate(stats.Median, one),
+		First:      uc.rounding.Rate(stats.First, one),
+		Last:       uc.rounding.Rate(stats.Last, one),
+		Change:     uc.rounding.Rate(change, one),
+		Volatility: uc.rounding.Rate(stats.Volatility, one),
+		Monthly:    make([]PeriodAverageResponse, 0, len(stats.Monthly)),
+		Quarterly:  make([]PeriodAverageResponse, 0, len(stats.Quarterly)),
+	}
+	if !stats.First.IsZero() {
+		result.ChangePercent = uc.rounding.Rate(change.Mul(decimal.NewFromInt(100)), stats.First)
+	}
+	for _, m := range stats.Monthly {
+		result.Monthly = append(result.Monthly, PeriodAverageResponse{
+			Period:  m.Start.Format("2006-01"),
+			Average: uc.rounding.Rate(m.Average, one),
+			Count:   m.Count,
+		})
+	}
+	for _, q := range stats.Quarterly {
+		result.Quarterly = append(result.Quarterly, PeriodAverageResponse{
+			Period:  fmt.Sprintf("%d-Q%d", q.Start.Year(), (int(q.Start.Month())+2)/3),
+			Average: uc.rounding.Rate(q.Average, one),
+			Count:   q.Count,
+		})
+	}
+
+	logger.Infof("Successfully computed statistics for %s over %d publications between %s and %s", code, result.Count, result.From, result.To)
 	return result, nil
 }
+
+func (uc *CurrencyUsecase) ConvertCurrency(ctx context.Context, source, from, to string, amount decimal.Decimal, date time.Time) (*ConversionResponse, error) {
+	ctx, span := tracing.Start(ctx, uc.tracer, "CurrencyUsecase.ConvertCurrency", attribute.String("rate.source", source), attribute.String("conversion.from", from), attribute.String("conversion.to", to))
+	defer span.End()
+	logger := uc.logger.WithContext(ctx)
+
+	fromCode := strings.ToUpper(from)
+	toCode := strings.ToUpper(to)
+	if !charCodeRegexp.MatchString(fromCode) || !charCodeRegexp.MatchString(toCode) {
+		logger.Errorf("Invalid currency code format: %s -> %s", fromCode, toCode)
+		return nil, fmt.Errorf("%w: %s -> %s, expected 3 uppercase letters", ErrInvalidCharCode, fromCode, toCode)
+	}
+
+	if !amount.IsPositive() {
+		logger.Errorf("Invalid amount: %s", amount)
+		return nil, ErrInvalidAmount
+	}
+
+	today := time.Now().Truncate(24 * time.Hour)
+	if date.IsZero() {
+		date = today
+		logger.Debugf("No date provided, using today: %s", date.Format("2006-01-02"))
+	}
+
+	// tomorrow's rates may already be published; the service checks the source's calendar
+	if date.After(today.AddDate(0, 0, 1)) {
+		logger.Warnf("Requested future date: %s", date.Format("2006-01-02"))
+		return nil, ErrFutureDate
+	}
+
+	source = normalizeSource(source)
+	base, err := uc.service.BaseCurrency(source)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := uc.checkCatalog(ctx, base, fromCode); err != nil {
+		return nil, err
+	}
+	if err := uc.checkCatalog(ctx, base, toCode); err != nil {
+		return nil, err
+	}
+
+	// both legs are requested for the same date, so they come from one publication of the source
+	fromRate, err := uc.rateInBase(ctx, source, base, fromCode, date)
+	if err != nil {
+		return nil, err
+	}
+	toRate, err := uc.rateInBase(ctx, source, base, toCode, date)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := uc.convert(source, fromCode, toCode, amount, date, fromRate, toRate)
+	if err != nil {
+		logger.WithError(err).Errorf("Failed to convert %s to %s on %s", fromCode, toCode, date.Format("2006-01-02"))
+		return nil, err
+	}
+	logger.Infof("Successfully converted %s %s to %s %s on %s (rate %s)", amount, fromCode, result.Result, toCode, result.Date, result.Rate)
+	return result, nil
+}
+
+// ConvertBatch converts every item on its own: an invalid item or a missing
+// rate fails only that item, and only an unknown source fails the batch. Rates
+// are resolved with one service call per distinct date.
+func (uc *CurrencyUsecase) ConvertBatch(ctx context.Context, source string, items []ConversionItem) ([]ConversionResult, error) {
+	ctx, span := tracing.Start(ctx, uc.tracer, "CurrencyUsecase.ConvertBatch", attribute.String("rate.source", source), attribute.Int("conversion.items", len(items)))
+	defer span.End()
+	logger := uc.logger.WithContext(ctx)
+
+	source = normalizeSource(source)
+	base, err := uc.service.BaseCurrency(source)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]ConversionResult, len(items))
+	prepared := make([]ConversionItem, len(items))
+	checked := make(map[string]error)
+	byDate := make(map[string][]int)
+	dates := make(map[string]time.Time)
+	today := time.Now().Truncate(24 * time.Hour)
+
+	for i, item := range items {
+		item, err := uc.prepareConversion(ctx, base, item, today, checked)
+		if err != nil {
+			results[i].Err = err
+			continue
+		}
+		prepared[i] = item
+		key := item.Date.Format("2006-01-02")
+		byDate[key] = append(byDate[key], i)
+		dates[key] = item.Date
+	}
+
+	keys := make([]string, 0, len(byDate))
+	for key := range byDate {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		indexes := byDate[key]
+		date := dates[key]
+
+		codeSet := make(map[string]bool)
+		for _, i := range indexes {
+			codeSet[prepared[i].From] = true
+			codeSet[prepared[i].To] = true
+		}
+		delete(codeSet, base)
+		codes := make([]string, 0, len(codeSet))
+		for code := range codeSet {
+			codes = append(codes, code)
+		}
+		sort.Strings(codes)
+
+		rates := map[string]entity.Currency{}
+		if len(codes) > 0 {
+			rates, err = uc.service.GetRatesByCharCodesAndDate(ctx, source, codes, date)
+			if err != nil {
+				logger.WithError(err).Errorf("Failed to get %d rates for date %s", len(codes), key)
+				for _, i := range indexes {
+					results[i].Err = err
+				}
+				continue
+			}
+		}
+
+		for _, i := range indexes {
+			item := prepared[i]
+			fromRate, err := batchLeg(source, base, item.From, rates, date)
+			if err != nil {
+				results[i].Err = err
+				continue
+			}
+			toRate, err := batchLeg(source, base, item.To, rates, date)
+			if err != nil {
+				results[i].Err = err
+				continue
+			}
+			results[i].Result, results[i].Err = uc.convert(source, item.From, item.To, item.Amount, date, fromRate, toRate)
+		}
+	}
+
+	failed := 0
+	for _, r := range results {
+		if r.Err != nil {
+			failed++
+		}
+	}
+	logger.Infof("Converted batch of %d items over %d dates, %d failed", len(items), len(keys), failed)
+	return results, nil
+}
+
+// prepareConversion validates an item like ConvertCurrency does; checked
+// caches catalog lookups across the batch.
+func (uc *CurrencyUsecase) prepareConversion(ctx context.Context, base string, item ConversionItem, today time.Time, checked map[string]error) (ConversionItem, error) {
+	item.From = strings.ToUpper(item.From)
+	item.To = strings.ToUpper(item.To)
+	if !charCodeRegexp.MatchString(item.From) || !charCodeRegexp.MatchString(item.To) {
+		return item, fmt.Errorf("%w: %s -> %s, expected 3 uppercase letters", ErrInvalidCharCode, item.From, item.To)
+	}
+	if !item.Amount.IsPositive() {
+		return item, ErrInvalidAmount
+	}
+
+	if item.Date.IsZero() {
+		item.Date = today
+	}
+	if item.Date.After(today.AddDate(0, 0, 1)) {
+		return item, ErrFutureDate
+	}
+
+	for _, code := range []string{item.From, item.To} {
+		err, ok := checked[code]
+		if !ok {
+			err = uc.checkCatalog(ctx, base, code)
+			checked[code] = err
+		}
+		if err != nil {
+			return item, err
+		}
+	}
+	return item, nil
+}
+
+// convert prices amount of from in to; both rates are in the source's base currency.
+// A non-positive stored rate fails with ErrRateNotFound rather than dividing by zero.
+func (uc *CurrencyUsecase) convert(source, from, to string, amount decimal.Decimal, date time.Time, fromRate, toRate *entity.Currency) (*ConversionResponse, error) {
+	for _, leg := range []struct {
+		code string
+		rate *entity.Currency
+	}{{from, fromRate}, {to, toRate}} {
+		if !leg.rate.Value.IsPositive() || leg.rate.Nominal <= 0 {
+			return nil, fmt.Errorf("%w: non-positive %s rate %s per %d on %s", ErrRateNotFound, leg.code, leg.rate.Value, leg.rate.Nominal, date.Format("2006-01-02"))
+		}
+	}
+
+	// (fromValue / fromNominal) / (toValue / toNominal), divided once to avoid intermediate rounding
+	numerator := fromRate.Value.Mul(decimal.NewFromInt(int64(toRate.Nominal)))
+	denominator := decimal.NewFromInt(int64(fromRate.Nominal)).Mul(toRate.Value)
+
+	servedBy, fallback := servingSource(source, fromRate)
+	if toServedBy, toFallback := servingSource(source, toRate); toFallback {
+		servedBy, fallback = toServedBy, true
+	}
+
+	result := &ConversionResponse{
+		From:     from,
+		To:       to,
+		Amount:   amount,
+		Rate:     uc.rounding.Rate(numerator, denominator),
+		Result:   uc.rounding.Amount(numerator.Mul(amount), denominator),
+		Date:     date.Format("2006-01-02"),
+		Source:   servedBy,
+		Fallback: fallback,
+	}
+	// the base currency leg has no publication of its own
+	for _, leg := range []*entity.Currency{fromRate, toRate} {
+		if effective := effectiveDate(leg); !effective.IsZero() {
+			result.EffectiveDate = effective.Format("2006-01-02")
+		}
+	}
+	return result, nil
+}
+
+func (uc *CurrencyUsecase) GetCurrencyList(ctx context.Context) (*CurrencyListResponse, error) {
+	ctx, span := tracing.Start(ctx, uc.tracer, "CurrencyUsecase.GetCurrencyList")
+	defer span.End()
+	logger := uc.logger.WithContext(ctx)
+
+	catalog, err := uc.service.GetCurrencyCatalog(ctx)
+	if err != nil {
+		logger.WithError(err).Error("Failed to get currency catalog")
+		return nil, err
+	}
+
+	result := &CurrencyListResponse{
+		Currencies: make([]CurrencyListItem, 0, len(catalog)),
+	}
+	for _, c := range catalog {
+		result.Currencies = append(result.Currencies, CurrencyListItem{
+			CharCode:   c.CharCode,
+			NumCode:    c.NumCode,
+			CbrID:      c.ID,
+			Name:       c.Name,
+			EngName:    c.EngName,
+			Nominal:    c.Nominal,
+			ParentCode: c.ParentCode,
+			Monthly:    c.Monthly,
+		})
+	}
+	result.Count = len(result.Currencies)
+
+	logger.Infof("Successfully fetched currency catalog: %d currencies", result.Count)
+	return result, nil
+}
+
+// checkCatalog rejects well-formed codes that CBR does not know about. The
+// CBR catalog is used for every source as it lists all the ISO codes we quote.
+func (uc *CurrencyUsecase) checkCatalog(ctx context.Context, base, code string) error {
+	if code == base {
+		return nil
+	}
+
+	if _, err := uc.service.GetCurrencyByCharCode(ctx, code); err != nil {
+		uc.logger.WithError(err).Warnf("Currency %s failed catalog check", code)
+		return err
+	}
+	return nil
+}
+
+func batchLeg(source, base, code string, rates map[string]entity.Currency, date time.Time) (*entity.Currency, error) {
+	if code == base {
+		return baseRate(source, base), nil
+	}
+	rate, ok := rates[code]
+	if !ok {
+		return nil, fmt.Errorf("%w: currency code %s for date %s", ErrRateNotFound, code, date.Format("2006-01-02"))
+	}
+	return &rate, nil
+}
+
+func (uc *CurrencyUsecase) rateInBase(ctx context.Context, source, base, code string, date time.Time) (*entity.Currency, error) {
+	if code == base {
+		return baseRate(source, base), nil
+	}
+
+	currency, err := uc.service.GetRateByCharCodeAndDate(ctx, source, code, date)
+	if err != nil {
+		uc.logger.WithError(err).Errorf("Failed to get historical rate by char code %s for date %s", code, date.Format("2006-01-02"))
+		return nil, err
+	}
+
+	return currency, nil
+}
+
+func baseRate(source, base string) *entity.Currency {
+	return &entity.Currency{CharCode: base, Nominal: 1, Value: decimal.NewFromInt(1), Source: source}
+}
+
+// servingSource names the source the rate actually came from; it differs from the
+// requested one when the service fell back to another provider.
+func servingSource(source string, rate *entity.Currency) (string, bool) {
+	if rate.Source == "" || rate.Source == source {
+		return source, false
+	}
+	return rate.Source, true
+}
+
+// effectiveDate is the publication a rate comes from; rates without an
+// EffectiveDate are publications themselves.
+func effectiveDate(rate *entity.Currency) time.Time {
+	if rate.EffectiveDate.IsZero() {
+		return rate.Date
+	}
+	return rate.EffectiveDate
+}
+
+func normalizeSource(source string) string {
+	if source == "" {
+		return defaultSource
+	}
+	return strings.ToLower(source)
+}
@@ -3,15 +3,22 @@ package usecase
 import (
 	"context"
 	"errors"
+	"fmt"
 	"testing"
 	"time"
 
 	"RnD-service/internal/entity"
+	"RnD-service/pkg/tracing"
 
+	"github.com/shopspring/decimal"
 	"github.com/sirupsen/logrus"
 	"github.com/sirupsen/logrus/hooks/test"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
 )
 
 type mockCurrencyService struct {
@@ -23,26 +30,91 @@ func (m *mockCurrencyService) StoreRatesFromCbr(ctx context.Context) error {
 	return args.Error(0)
 }
 
-func (m *mockCurrencyService) GetRateByCharCode(ctx context.Context, charCode string) (*entity.Currency, error) {
-	args := m.Called(ctx, charCode)
+func (m *mockCurrencyService) StoreRatesFromProvider(ctx context.Context, source string) error {
+	args := m.Called(ctx, source)
+	return args.Error(0)
+}
+
+func (m *mockCurrencyService) BaseCurrency(source string) (string, error) {
+	args := m.Called(source)
+	return args.String(0), args.Error(1)
+}
+
+func (m *mockCurrencyService) GetRateByCharCode(ctx context.Context, source, charCode string) (*entity.Currency, error) {
+	args := m.Called(ctx, source, charCode)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
 	return args.Get(0).(*entity.Currency), args.Error(1)
 }
 
-func (m *mockCurrencyService) GetRateByCharCodeAndDate(ctx context.Context, charCode string, date time.Time) (*entity.Currency, error) {
-	args := m.Called(ctx, charCode, date)
+func (m *mockCurrencyService) GetRateByCharCodeAndDate(ctx context.Context, source, charCode string, date time.Time) (*entity.Currency, error) {
+	args := m.Called(ctx, source, charCode, date)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
 	return args.Get(0).(*entity.Currency), args.Error(1)
 }
 
+func (m *mockCurrencyService) GetRatesByCharCodesAndDate(ctx context.Context, source string, charCodes []string, date time.Time) (map[string]entity.Currency, error) {
+	args := m.Called(ctx, source, charCodes, date)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(map[string]entity.Currency), args.Error(1)
+}
+
+func (m *mockCurrencyService) GetRatesByCharCodeAndDateRange(ctx context.Context, source, charCode string, dateFrom, dateTo time.Time) ([]entity.Currency, error) {
+	args := m.Called(ctx, source, charCode, dateFrom, dateTo)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]entity.Currency), args.Error(1)
+}
+
+func (m *mockCurrencyService) GetRateStats(ctx context.Context, source, charCode string, dateFrom, dateTo time.Time) (*entity.RateStats, error) {
+	args := m.Called(ctx, source, charCode, dateFrom, dateTo)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.RateStats), args.Error(1)
+}
+
+func (m *mockCurrencyService) SyncCurrencyCatalog(ctx context.Context) error {
+	args := m.Called(ctx)
+	return args.Error(0)
+}
+
+func (m *mockCurrencyService) GetCurrencyCatalog(ctx context.Context) ([]entity.CurrencyInfo, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]entity.CurrencyInfo), args.Error(1)
+}
+
+func (m *mockCurrencyService) GetCurrencyByCharCode(ctx context.Context, charCode string) (*entity.CurrencyInfo, error) {
+	args := m.Called(ctx, charCode)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.CurrencyInfo), args.Error(1)
+}
+
+// knownCurrencies makes every char code pass the catalog check.
+func knownCurrencies(m *mockCurrencyService) {
+	m.On("GetCurrencyByCharCode", mock.Anything, mock.Anything).Return(&entity.CurrencyInfo{}, nil)
+}
+
+func cbrBase(m *mockCurrencyService) {
+	m.On("BaseCurrency", "cbr").Return("RUB", nil).Maybe()
+}
+
 func setupTestUsecase() (*CurrencyUsecase, *mockCurrencyService, *logrus.Logger, *test.Hook) {
 	mockService := new(mockCurrencyService)
+	cbrBase(mockService)
 	logger, hook := test.NewNullLogger()
-	usecase := NewCurrencyUsecase(mockService, logger)
+	usecase := NewCurrencyUsecase(mockService, DefaultRounding, logger)
 	return usecase, mockService, logger, hook
 }
 
@@ -50,7 +122,7 @@ func TestFetchAndStoreRatesFromCBR(t *testing.T) {
 	ctx := context.Background()
 	usecase, mockService, _, _ := setupTestUsecase()
 
-	mockService.On("StoreRatesFromCbr", ctx).Return(nil)
+	mockService.On("StoreRatesFromProvider", ctx, "cbr").Return(nil)
 
 	err := usecase.FetchAndStoreRatesFromCBR(ctx)
 	assert.NoError(t, err)
@@ -63,7 +135,7 @@ func TestFetchAndStoreRatesFromCBR_Error(t *testing.T) {
 	usecase, mockService, _, _ := setupTestUsecase()
 
 	expectedErr := errors.New("service error")
-	mockService.On("StoreRatesFromCbr", ctx).Return(expectedErr)
+	mockService.On("StoreRatesFromProvider", ctx, "cbr").Return(expectedErr)
 
 	err := usecase.FetchAndStoreRatesFromCBR(ctx)
 	assert.Equal(t, expectedErr, err)
@@ -76,23 +148,24 @@ func TestGetRateByCharCode_InvalidCode(t *testing.T) {
 	usecase, _, _, _ := setupTestUsecase()
 
 	charCode := "us"
-	amount := 1.0
+	amount := decimal.NewFromInt(1)
 
-	_, err := usecase.GetRateByCharCode(ctx, charCode, amount)
-	assert.ErrorContains(t, err, "invalid char code format")
+	_, err := usecase.GetRateByCharCode(ctx, "", charCode, amount)
+	assert.ErrorIs(t, err, ErrInvalidCharCode)
 }
 
 func TestGetRateByCharCode_ServiceError(t *testing.T) {
 	ctx := context.Background()
 	usecase, mockService, _, _ := setupTestUsecase()
+	knownCurrencies(mockService)
 
 	charCode := "USD"
-	amount := 1.0
+	amount := decimal.NewFromInt(1)
 
 	expectedErr := errors.New("service error")
-	mockService.On("GetRateByCharCode", ctx, charCode).Return((*entity.Currency)(nil), expectedErr)
+	mockService.On("GetRateByCharCode", ctx, "cbr", charCode).Return((*entity.Currency)(nil), expectedErr)
 
-	_, err := usecase.GetRateByCharCode(ctx, charCode, amount)
+	_, err := usecase.GetRateByCharCode(ctx, "", charCode, amount)
 	assert.Equal(t, expectedErr, err)
 
 	mockService.AssertExpectations(t)
@@ -101,21 +174,22 @@ func TestGetRateByCharCode_ServiceError(t *testing.T) {
 func TestGetRateByCharCode_Success(t *testing.T) {
 	ctx := context.Background()
 	usecase, mockService, _, _ := setupTestUsecase()
+	knownCurrencies(mockService)
 
 	charCode := "usd"
-	amount := 2.0
+	amount := decimal.NewFromInt(2)
 	currency := &entity.Currency{
 		CharCode: "USD",
 		Nominal:  1,
-		Value:    90.5,
+		Value:    decimal.RequireFromString("90.5"),
 	}
 
-	mockService.On("GetRateByCharCode", ctx, "USD").Return(currency, nil)
+	mockService.On("GetRateByCharCode", ctx, "cbr", "USD").Return(currency, nil)
 
-	result, err := usecase.GetRateByCharCode(ctx, charCode, amount)
+	result, err := usecase.GetRateByCharCode(ctx, "", charCode, amount)
 	assert.NoError(t, err)
 	assert.Equal(t, "USD", result.CharCode)
-	assert.Equal(t, 181.0, result.ValueRUB)
+	assert.Equal(t, "181", result.ValueRUB.String())
 
 	mockService.AssertExpectations(t)
 }
@@ -126,10 +200,10 @@ func TestGetHistoricalRateByCharCode_InvalidCode(t *testing.T) {
 
 	charCode := "us"
 	date := time.Date(2025, 8, 1, 0, 0, 0, 0, time.UTC)
-	amount := 1.0
+	amount := decimal.NewFromInt(1)
 
-	_, err := usecase.GetHistoricalRateByCharCode(ctx, charCode, date, amount)
-	assert.ErrorContains(t, err, "invalid char code format")
+	_, err := usecase.GetHistoricalRateByCharCode(ctx, "", charCode, date, amount)
+	assert.ErrorIs(t, err, ErrInvalidCharCode)
 }
 
 func TestGetHistoricalRateByCharCode_FutureDate(t *testing.T) {
@@ -137,34 +211,35 @@ func TestGetHistoricalRateByCharCode_FutureDate(t *testing.T) {
 	usecase, _, _, _ := setupTestUsecase()
 
 	charCode := "USD"
-	date := time.Now().Add(24 * time.Hour)
-	amount := 1.0
+	date := time.Now().Add(48 * time.Hour)
+	amount := decimal.NewFromInt(1)
 
-	_, err := usecase.GetHistoricalRateByCharCode(ctx, charCode, date, amount)
-	assert.ErrorContains(t, err, "cannot fetch rates for future dates")
+	_, err := usecase.GetHistoricalRateByCharCode(ctx, "", charCode, date, amount)
+	assert.ErrorIs(t, err, ErrFutureDate)
 }
 
 func TestGetHistoricalRateByCharCode_ZeroDate(t *testing.T) {
 	ctx := context.Background()
 	usecase, mockService, _, _ := setupTestUsecase()
+	knownCurrencies(mockService)
 
 	charCode := "USD"
 	var date time.Time
-	amount := 1.0
+	amount := decimal.NewFromInt(1)
 	today := time.Now().Truncate(24 * time.Hour)
 	currency := &entity.Currency{
 		CharCode: "USD",
 		Nominal:  1,
-		Value:    90.5,
+		Value:    decimal.RequireFromString("90.5"),
 		Date:     time.Now(),
 	}
 
-	mockService.On("GetRateByCharCodeAndDate", ctx, "USD", today).Return(currency, nil)
+	mockService.On("GetRateByCharCodeAndDate", ctx, "cbr", "USD", today).Return(currency, nil)
 
-	result, err := usecase.GetHistoricalRateByCharCode(ctx, charCode, date, amount)
+	result, err := usecase.GetHistoricalRateByCharCode(ctx, "", charCode, date, amount)
 	assert.NoError(t, err)
 	assert.Equal(t, "USD", result.CharCode)
-	assert.Equal(t, 90.5, result.ValueRUB)
+	assert.Equal(t, "90.5", result.ValueRUB.String())
 
 	mockService.AssertExpectations(t)
 }
@@ -172,40 +247,637 @@ func TestGetHistoricalRateByCharCode_ZeroDate(t *testing.T) {
 func TestGetHistoricalRateByCharCode_ServiceError(t *testing.T) {
 	ctx := context.Background()
 	usecase, mockService, _, _ := setupTestUsecase()
+	knownCurrencies(mockService)
 
 	charCode := "USD"
 	date := time.Date(2025, 8, 1, 0, 0, 0, 0, time.UTC)
-	amount := 1.0
+	amount := decimal.NewFromInt(1)
 
 	expectedErr := errors.New("service error")
-	mockService.On("GetRateByCharCodeAndDate", ctx, "USD", date).Return((*entity.Currency)(nil), expectedErr)
+	mockService.On("GetRateByCharCodeAndDate", ctx, "cbr", "USD", date).Return((*entity.Currency)(nil), expectedErr)
 
-	_, err := usecase.GetHistoricalRateByCharCode(ctx, charCode, date, amount)
+	_, err := usecase.GetHistoricalRateByCharCode(ctx, "", charCode, date, amount)
 	assert.Equal(t, expectedErr, err)
 
 	mockService.AssertExpectations(t)
 }
 
+func TestGetHistoricalRateByCharCode_Traced(t *testing.T) {
+	usecase, mockService, logger, hook := setupTestUsecase()
+	knownCurrencies(mockService)
+	logger.AddHook(tracing.LogHook{})
+
+	recorder := tracetest.NewSpanRecorder()
+	tracer := tracing.NewProvider(recorder, "RnD-service", 1).Tracer("test")
+	usecase.SetTracer(tracer)
+
+	date := time.Date(2025, 8, 1, 0, 0, 0, 0, time.UTC)
+	var serviceSpan trace.SpanContext
+	mockService.On("GetRateByCharCodeAndDate", mock.MatchedBy(func(ctx context.Context) bool {
+		serviceSpan = trace.SpanContextFromContext(ctx)
+		return true
+	}), "cbr", "USD", date).Return(&entity.Currency{CharCode: "USD", Nominal: 1, Value: decimal.RequireFromString("90.5"), Date: date}, nil)
+
+	ctx, request := tracer.Start(context.Background(), "GET /currency/rate")
+	_, err := usecase.GetHistoricalRateByCharCode(ctx, "", "USD", date, decimal.NewFromInt(1))
+	request.End()
+	require.NoError(t, err)
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 2)
+	span := spans[0]
+	assert.Equal(t, "CurrencyUsecase.GetHistoricalRateByCharCode", span.Name())
+	assert.Equal(t, request.SpanContext().SpanID(), span.Parent().SpanID())
+	assert.Contains(t, span.Attributes(), attribute.String("rate.char_code", "USD"))
+	assert.Equal(t, span.SpanContext().SpanID(), serviceSpan.SpanID())
+	assert.Equal(t, span.SpanContext().TraceID().String(), hook.LastEntry().Data["trace_id"])
+}
+
 func TestGetHistoricalRateByCharCode_Success(t *testing.T) {
 	ctx := context.Background()
 	usecase, mockService, _, _ := setupTestUsecase()
+	knownCurrencies(mockService)
 
 	charCode := "USD"
 	date := time.Date(2025, 8, 1, 0, 0, 0, 0, time.UTC)
-	amount := 2.0
+	amount := decimal.NewFromInt(2)
 	currency := &entity.Currency{
 		CharCode: "USD",
 		Nominal:  1,
-		Value:    90.5,
+		Value:    decimal.RequireFromString("90.5"),
 		Date:     date,
 	}
 
-	mockService.On("GetRateByCharCodeAndDate", ctx, "USD", date).Return(currency, nil)
+	mockService.On("GetRateByCharCodeAndDate", ctx, "cbr", "USD", date).Return(currency, nil)
 
-	result, err := usecase.GetHistoricalRateByCharCode(ctx, charCode, date, amount)
+	result, err := usecase.GetHistoricalRateByCharCode(ctx, "", charCode, date, amount)
 	assert.NoError(t, err)
 	assert.Equal(t, "USD", result.CharCode)
-	assert.Equal(t, 181.0, result.ValueRUB)
+	assert.Equal(t, "181", result.ValueRUB.String())
+
+	mockService.AssertExpectations(t)
+}
+
+func TestGetRateHistoryByCharCode_InvalidCode(t *testing.T) {
+	ctx := context.Background()
+	usecase, _, _, _ := setupTestUsecase()
+
+	from := time.Date(2025, 8, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2025, 8, 2, 0, 0, 0, 0, time.UTC)
+
+	_, err := usecase.GetRateHistoryByCharCode(ctx, "", "us", from, to)
+	assert.ErrorIs(t, err, ErrInvalidCharCode)
+}
+
+func TestGetRateHistoryByCharCode_InvalidRange(t *testing.T) {
+	ctx := context.Background()
+	usecase, _, _, _ := setupTestUsecase()
+
+	from := time.Date(2025, 8, 2, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2025, 8, 1, 0, 0, 0, 0, time.UTC)
+
+	_, err := usecase.GetRateHistoryByCharCode(ctx, "", "USD", from, to)
+	assert.ErrorIs(t, err, ErrInvalidDateRange)
+}
+
+func TestGetRateHistoryByCharCode_RangeTooLong(t *testing.T) {
+	ctx := context.Background()
+	usecase, _, _, _ := setupTestUsecase()
+
+	from := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	_, err := usecase.GetRateHistoryByCharCode(ctx, "", "USD", from, to)
+	assert.ErrorIs(t, err, ErrInvalidDateRange)
+	assert.ErrorContains(t, err, "must not exceed")
+}
+
+func TestGetRateHistoryByCharCode_Success(t *testing.T) {
+	ctx := context.Background()
+	usecase, mockService, _, _ := setupTestUsecase()
+	knownCurrencies(mockService)
+
+	from := time.Date(2025, 8, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2025, 8, 2, 0, 0, 0, 0, time.UTC)
+	rates := []entity.Currency{
+		{CharCode: "JPY", Nominal: 100, Value: decimal.RequireFromString("54.5"), Date: from},
+		{CharCode: "JPY", Nominal: 100, Value: decimal.RequireFromString("55.0"), Date: to},
+	}
+
+	mockService.On("GetRatesByCharCodeAndDateRange", ctx, "cbr", "JPY", from, to).Return(rates, nil)
+
+	result, err := usecase.GetRateHistoryByCharCode(ctx, "", "jpy", from, to)
+	assert.NoError(t, err)
+	assert.Equal(t, "JPY", result.CharCode)
+	assert.Equal(t, "2025-08-01", result.From)
+	assert.Equal(t, "2025-08-02", result.To)
+	require.Len(t, result.Rates, 2)
+	assert.Equal(t, "2025-08-01", result.Rates[0].Date)
+	assert.Equal(t, 100, result.Rates[0].Nominal)
+	assert.Equal(t, "54.5", result.Rates[0].Value.String())
+	assert.Equal(t, "0.545", result.Rates[0].ValueRUB.String())
+	assert.Equal(t, "2025-08-02", result.Rates[1].Date)
+	assert.Equal(t, "0.55", result.Rates[1].ValueRUB.String())
+
+	mockService.AssertExpectations(t)
+}
+
+func TestConvertCurrency_InvalidCode(t *testing.T) {
+	ctx := context.Background()
+	usecase, _, _, _ := setupTestUsecase()
+
+	_, err := usecase.ConvertCurrency(ctx, "", "USD", "eu", decimal.NewFromInt(100), time.Time{})
+	assert.ErrorIs(t, err, ErrInvalidCharCode)
+}
+
+func TestConvertCurrency_InvalidAmount(t *testing.T) {
+	ctx := context.Background()
+	usecase, _, _, _ := setupTestUsecase()
+
+	_, err := usecase.ConvertCurrency(ctx, "", "USD", "EUR", decimal.NewFromInt(-1), time.Time{})
+	assert.ErrorIs(t, err, ErrInvalidAmount)
+}
+
+func TestConvertCurrency_FutureDate(t *testing.T) {
+	ctx := context.Background()
+	usecase, _, _, _ := setupTestUsecase()
+
+	_, err := usecase.ConvertCurrency(ctx, "", "USD", "EUR", decimal.NewFromInt(100), time.Now().Add(48*time.Hour))
+	assert.ErrorIs(t, err, ErrFutureDate)
+}
+
+func TestConvertCurrency_CrossRate(t *testing.T) {
+	ctx := context.Background()
+	usecase, mockService, _, _ := setupTestUsecase()
+	knownCurrencies(mockService)
+
+	date := time.Date(2025, 8, 1, 0, 0, 0, 0, time.UTC)
+	mockService.On("GetRateByCharCodeAndDate", ctx, "cbr", "USD", date).Return(&entity.Currency{CharCode: "USD", Nominal: 1, Value: decimal.NewFromInt(80), Date: date}, nil)
+	mockService.On("GetRateByCharCodeAndDate", ctx, "cbr", "EUR", date).Return(&entity.Currency{CharCode: "EUR", Nominal: 1, Value: decimal.NewFromInt(100), Date: date}, nil)
+
+	result, err := usecase.ConvertCurrency(ctx, "", "usd", "eur", decimal.NewFromInt(100), date)
+	assert.NoError(t, err)
+	assert.Equal(t, "USD", result.From)
+	assert.Equal(t, "EUR", result.To)
+	assert.Equal(t, "0.8", result.Rate.String())
+	assert.Equal(t, "80", result.Result.String())
+	assert.Equal(t, "2025-08-01", result.Date)
+
+	mockService.AssertExpectations(t)
+}
+
+func TestConvertCurrency_FromRUB(t *testing.T) {
+	ctx := context.Background()
+	usecase, mockService, _, _ := setupTestUsecase()
+	knownCurrencies(mockService)
+
+	date := time.Date(2025, 8, 1, 0, 0, 0, 0, time.UTC)
+	mockService.On("GetRateByCharCodeAndDate", ctx, "cbr", "JPY", date).Return(&entity.Currency{CharCode: "JPY", Nominal: 100, Value: decimal.NewFromInt(50), Date: date}, nil)
+
+	result, err := usecase.ConvertCurrency(ctx, "", "RUB", "JPY", decimal.NewFromInt(100), date)
+	assert.NoError(t, err)
+	assert.Equal(t, "2", result.Rate.String())
+	assert.Equal(t, "200", result.Result.String())
+
+	mockService.AssertExpectations(t)
+}
+
+func TestConvertCurrency_ToRUB(t *testing.T) {
+	ctx := context.Background()
+	usecase, mockService, _, _ := setupTestUsecase()
+	knownCurrencies(mockService)
+
+	date := time.Date(2025, 8, 1, 0, 0, 0, 0, time.UTC)
+	mockService.On("GetRateByCharCodeAndDate", ctx, "cbr", "USD", date).Return(&entity.Currency{CharCode: "USD", Nominal: 1, Value: decimal.RequireFromString("90.5"), Date: date}, nil)
+
+	result, err := usecase.ConvertCurrency(ctx, "", "USD", "RUB", decimal.NewFromInt(2), date)
+	assert.NoError(t, err)
+	assert.Equal(t, "90.5", result.Rate.String())
+	assert.Equal(t, "181", result.Result.String())
+
+	mockService.AssertExpectations(t)
+}
+
+func TestConvertCurrency_ZeroRate(t *testing.T) {
+	ctx := context.Background()
+	usecase, mockService, _, _ := setupTestUsecase()
+	knownCurrencies(mockService)
+
+	date := time.Date(2025, 8, 1, 0, 0, 0, 0, time.UTC)
+	mockService.On("GetRateByCharCodeAndDate", ctx, "cbr", "USD", date).Return(&entity.Currency{CharCode: "USD", Nominal: 1, Value: decimal.NewFromInt(80), Date: date}, nil)
+	mockService.On("GetRateByCharCodeAndDate", ctx, "cbr", "EUR", date).Return(&entity.Currency{CharCode: "EUR", Nominal: 1, Value: decimal.Zero, Date: date}, nil)
+
+	result, err := usecase.ConvertCurrency(ctx, "", "USD", "EUR", decimal.NewFromInt(100), date)
+	assert.Nil(t, result)
+	assert.ErrorIs(t, err, ErrRateNotFound)
+	assert.ErrorContains(t, err, "non-positive EUR rate")
+
+	mockService.AssertExpectations(t)
+}
+
+func TestConvertCurrency_ServiceError(t *testing.T) {
+	ctx := context.Background()
+	usecase, mockService, _, _ := setupTestUsecase()
+	knownCurrencies(mockService)
+
+	date := time.Date(2025, 8, 1, 0, 0, 0, 0, time.UTC)
+	expectedErr := errors.New("service error")
+	mockService.On("GetRateByCharCodeAndDate", ctx, "cbr", "USD", date).Return(&entity.Currency{CharCode: "USD", Nominal: 1, Value: decimal.NewFromInt(80), Date: date}, nil)
+	mockService.On("GetRateByCharCodeAndDate", ctx, "cbr", "EUR", date).Return((*entity.Currency)(nil), expectedErr)
+
+	_, err := usecase.ConvertCurrency(ctx, "", "USD", "EUR", decimal.NewFromInt(100), date)
+	assert.Equal(t, expectedErr, err)
+
+	mockService.AssertExpectations(t)
+}
+
+func TestGetHistoricalRateByCharCode_UnknownCurrency(t *testing.T) {
+	ctx := context.Background()
+	usecase, mockService, _, _ := setupTestUsecase()
+
+	date := time.Date(2025, 8, 1, 0, 0, 0, 0, time.UTC)
+	mockService.On("GetCurrencyByCharCode", ctx, "XYZ").Return(nil, fmt.Errorf("%w: XYZ", ErrUnknownCurrency))
+
+	result, err := usecase.GetHistoricalRateByCharCode(ctx, "", "xyz", date, decimal.NewFromInt(1))
+	assert.Nil(t, result)
+	assert.ErrorIs(t, err, ErrUnknownCurrency)
+	mockService.AssertNotCalled(t, "GetRateByCharCodeAndDate", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestConvertCurrency_UnknownCurrency(t *testing.T) {
+	ctx := context.Background()
+	usecase, mockService, _, _ := setupTestUsecase()
+
+	date := time.Date(2025, 8, 1, 0, 0, 0, 0, time.UTC)
+	mockService.On("GetCurrencyByCharCode", ctx, "USD").Return(&entity.CurrencyInfo{ID: "R01235", CharCode: "USD"}, nil)
+	mockService.On("GetCurrencyByCharCode", ctx, "ABC").Return(nil, fmt.Errorf("%w: ABC", ErrUnknownCurrency))
+
+	result, err := usecase.ConvertCurrency(ctx, "", "USD", "ABC", decimal.NewFromInt(1), date)
+	assert.Nil(t, result)
+	assert.ErrorIs(t, err, ErrUnknownCurrency)
+	mockService.AssertNotCalled(t, "GetRateByCharCodeAndDate", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestConvertCurrency_RUBSkipsCatalog(t *testing.T) {
+	ctx := context.Background()
+	usecase, mockService, _, _ := setupTestUsecase()
+
+	date := time.Date(2025, 8, 1, 0, 0, 0, 0, time.UTC)
+	mockService.On("GetCurrencyByCharCode", ctx, "USD").Return(&entity.CurrencyInfo{ID: "R01235", CharCode: "USD"}, nil)
+	mockService.On("GetRateByCharCodeAndDate", ctx, "cbr", "USD", date).Return(&entity.Currency{CharCode: "USD", Nominal: 1, Value: decimal.NewFromInt(80), Date: date}, nil)
+
+	result, err := usecase.ConvertCurrency(ctx, "", "RUB", "USD", decimal.NewFromInt(80), date)
+	require.NoError(t, err)
+	assert.Equal(t, "1", result.Result.String())
+	mockService.AssertNotCalled(t, "GetCurrencyByCharCode", mock.Anything, "RUB")
+}
+
+func TestGetCurrencyList(t *testing.T) {
+	ctx := context.Background()
+	usecase, mockService, _, _ := setupTestUsecase()
+
+	mockService.On("GetCurrencyCatalog", ctx).Return([]entity.CurrencyInfo{
+		{ID: "R01010", CharCode: "AUD", NumCode: "36", Name: "Австралийский доллар", EngName: "Australian Dollar", Nominal: 1, ParentCode: "R01010"},
+		{ID: "R01235", CharCode: "USD", NumCode: "840", Name: "Доллар США", EngName: "US Dollar", Nominal: 1, ParentCode: "R01235"},
+	}, nil)
+
+	result, err := usecase.GetCurrencyList(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 2, result.Count)
+	assert.Equal(t, CurrencyListItem{CharCode: "USD", NumCode: "840", CbrID: "R01235", Name: "Доллар США", EngName: "US Dollar", Nominal: 1, ParentCode: "R01235"}, result.Currencies[1])
+	mockService.AssertExpectations(t)
+}
+
+func TestGetCurrencyList_Error(t *testing.T) {
+	ctx := context.Background()
+	usecase, mockService, _, _ := setupTestUsecase()
+
+	expectedErr := errors.New("db error")
+	mockService.On("GetCurrencyCatalog", ctx).Return(nil, expectedErr)
+
+	result, err := usecase.GetCurrencyList(ctx)
+	assert.Nil(t, result)
+	assert.ErrorIs(t, err, expectedErr)
+}
+
+func TestGetRateByCharCode_ECBSource(t *testing.T) {
+	ctx := context.Background()
+	usecase, mockService, _, _ := setupTestUsecase()
+	knownCurrencies(mockService)
+
+	mockService.On("BaseCurrency", "ecb").Return("EUR", nil)
+	mockService.On("GetRateByCharCode", ctx, "ecb", "USD").Return(&entity.Currency{CharCode: "USD", Nominal: 1, Value: decimal.RequireFromString("0.8769"), Source: "ecb"}, nil)
+
+	result, err := usecase.GetRateByCharCode(ctx, "ECB", "USD", decimal.NewFromInt(10))
+	require.NoError(t, err)
+	assert.Equal(t, "ecb", result.Source)
+	assert.Equal(t, "EUR", result.Base)
+	assert.Equal(t, "8.769", result.ValueRUB.String())
+
+	mockService.AssertExpectations(t)
+}
+
+func TestConvertCurrency_ECBBase(t *testing.T) {
+	ctx := context.Background()
+	usecase, mockService, _, _ := setupTestUsecase()
+	knownCurrencies(mockService)
+
+	date := time.Date(2025, 8, 1, 0, 0, 0, 0, time.UTC)
+	mockService.On("BaseCurrency", "ecb").Return("EUR", nil)
+	mockService.On("GetRateByCharCodeAndDate", ctx, "ecb", "USD", date).Return(&entity.Currency{CharCode: "USD", Nominal: 1, Value: decimal.RequireFromString("0.8769"), Date: date}, nil)
+
+	result, err := usecase.ConvertCurrency(ctx, "ecb", "EUR", "USD", decimal.NewFromInt(100), date)
+	require.NoError(t, err)
+	assert.Equal(t, "ecb", result.Source)
+	assert.Equal(t, "1.140381", result.Rate.String())
+
+	mockService.AssertExpectations(t)
+	mockService.AssertNotCalled(t, "GetRateByCharCodeAndDate", ctx, "ecb", "EUR", date)
+}
+
+func TestGetRateByCharCode_UnknownSource(t *testing.T) {
+	ctx := context.Background()
+	usecase, mockService, _, _ := setupTestUsecase()
+
+	mockService.On("BaseCurrency", "nbk").Return("", fmt.Errorf("%w: nbk", ErrUnknownSource))
+
+	_, err := usecase.GetRateByCharCode(ctx, "nbk", "USD", decimal.NewFromInt(1))
+	assert.ErrorIs(t, err, ErrUnknownSource)
+	mockService.AssertNotCalled(t, "GetRateByCharCode", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestGetHistoricalRateByCharCode_FlagsFallback(t *testing.T) {
+	ctx := context.Background()
+	usecase, mockService, _, _ := setupTestUsecase()
+	knownCurrencies(mockService)
+
+	date := time.Date(2025, 8, 1, 0, 0, 0, 0, time.UTC)
+	mockService.On("GetRateByCharCodeAndDate", ctx, "cbr", "USD", date).Return(&entity.Currency{CharCode: "USD", Nominal: 1, Value: decimal.RequireFromString("79.7653"), Date: date, Source: "cbr_mirror"}, nil)
+
+	result, err := usecase.GetHistoricalRateByCharCode(ctx, "", "USD", date, decimal.NewFromInt(1))
+	require.NoError(t, err)
+	assert.Equal(t, "cbr_mirror", result.Source)
+	assert.True(t, result.Fallback)
+}
+
+func TestGetRateHistoryByCharCode_FlagsFallbackPoints(t *testing.T) {
+	ctx := context.Background()
+	usecase, mockService, _, _ := setupTestUsecase()
+	knownCurrencies(mockService)
+
+	from := time.Date(2025, 7, 31, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2025, 8, 1, 0, 0, 0, 0, time.UTC)
+	mockService.On("GetRatesByCharCodeAndDateRange", ctx, "cbr", "USD", from, to).Return([]entity.Currency{
+		{CharCode: "USD", Nominal: 1, Value: decimal.RequireFromString("80.3682"), Date: from, Source: "cbr"},
+		{CharCode: "USD", Nominal: 1, Value: decimal.RequireFromString("79.7653"), Date: to, Source: "cbr_mirror"},
+	}, nil)
+
+	result, err := usecase.GetRateHistoryByCharCode(ctx, "", "USD", from, to)
+	require.NoError(t, err)
+	assert.Equal(t, "cbr", result.Source)
+	assert.True(t, result.Fallback)
+	require.Len(t, result.Rates, 2)
+	assert.Empty(t, result.Rates[0].Source)
+	assert.Equal(t, "cbr_mirror", result.Rates[1].Source)
+}
+
+func TestConvertCurrency_FlagsFallbackLeg(t *testing.T) {
+	ctx := context.Background()
+	usecase, mockService, _, _ := setupTestUsecase()
+	knownCurrencies(mockService)
+
+	date := time.Date(2025, 8, 1, 0, 0, 0, 0, time.UTC)
+	mockService.On("GetRateByCharCodeAndDate", ctx, "cbr", "USD", date).Return(&entity.Currency{CharCode: "USD", Nominal: 1, Value: decimal.NewFromInt(80), Date: date, Source: "cbr"}, nil)
+	mockService.On("GetRateByCharCodeAndDate", ctx, "cbr", "EUR", date).Return(&entity.Currency{CharCode: "EUR", Nominal: 1, Value: decimal.NewFromInt(100), Date: date, Source: "cbr_mirror"}, nil)
+
+	result, err := usecase.ConvertCurrency(ctx, "", "USD", "EUR", decimal.NewFromInt(100), date)
+	require.NoError(t, err)
+	assert.Equal(t, "cbr_mirror", result.Source)
+	assert.True(t, result.Fallback)
+	assert.Equal(t, "80", result.Result.String())
+}
+
+func TestGetHistoricalRateByCharCode_ReportsEffectiveDate(t *testing.T) {
+	ctx := context.Background()
+	usecase, mockService, _, _ := setupTestUsecase()
+	knownCurrencies(mockService)
+
+	monday := time.Date(2025, 8, 4, 0, 0, 0, 0, time.UTC)
+	saturday := time.Date(2025, 8, 2, 0, 0, 0, 0, time.UTC)
+	mockService.On("GetRateByCharCodeAndDate", ctx, "cbr", "USD", monday).Return(&entity.Currency{CharCode: "USD", Nominal: 1, Value: decimal.RequireFromString("79.7653"), Date: monday, EffectiveDate: saturday}, nil)
+
+	result, err := usecase.GetHistoricalRateByCharCode(ctx, "", "USD", monday, decimal.NewFromInt(1))
+	require.NoError(t, err)
+	assert.Equal(t, "2025-08-04", result.RequestedDate)
+	assert.Equal(t, "2025-08-02", result.EffectiveDate)
+}
+
+func TestConvertCurrency_ReportsEffectiveDate(t *testing.T) {
+	ctx := context.Background()
+	usecase, mockService, _, _ := setupTestUsecase()
+	knownCurrencies(mockService)
+
+	monday := time.Date(2025, 8, 4, 0, 0, 0, 0, time.UTC)
+	saturday := time.Date(2025, 8, 2, 0, 0, 0, 0, time.UTC)
+	mockService.On("GetRateByCharCodeAndDate", ctx, "cbr", "USD", monday).Return(&entity.Currency{CharCode: "USD", Nominal: 1, Value: decimal.RequireFromString("79.7653"), Date: monday, EffectiveDate: saturday}, nil)
+
+	result, err := usecase.ConvertCurrency(ctx, "", "USD", "RUB", decimal.NewFromInt(1), monday)
+	require.NoError(t, err)
+	assert.Equal(t, "2025-08-04", result.Date)
+	assert.Equal(t, "2025-08-02", result.EffectiveDate)
+}
+
+func TestConvertBatch_OneLookupPerDate(t *testing.T) {
+	ctx := context.Background()
+	usecase, mockService, _, _ := setupTestUsecase()
+	knownCurrencies(mockService)
+
+	first := time.Date(2025, 8, 1, 0, 0, 0, 0, time.UTC)
+	second := time.Date(2025, 8, 2, 0, 0, 0, 0, time.UTC)
+	mockService.On("GetRatesByCharCodesAndDate", ctx, "cbr", []string{"EUR", "USD"}, first).Return(map[string]entity.Currency{
+		"USD": {CharCode: "USD", Nominal: 1, Value: decimal.NewFromInt(80), Date: first},
+		"EUR": {CharCode: "EUR", Nominal: 1, Value: decimal.NewFromInt(100), Date: first},
+	}, nil).Once()
+	mockService.On("GetRatesByCharCodesAndDate", ctx, "cbr", []string{"JPY"}, second).Return(map[string]entity.Currency{
+		"JPY": {CharCode: "JPY", Nominal: 100, Value: decimal.NewFromInt(50), Date: second},
+	}, nil).Once()
+
+	results, err := usecase.ConvertBatch(ctx, "", []ConversionItem{
+		{From: "usd", To: "eur", Amount: decimal.NewFromInt(100), Date: first},
+		{From: "EUR", To: "RUB", Amount: decimal.NewFromInt(2), Date: first},
+		{From: "RUB", To: "JPY", Amount: decimal.NewFromInt(100), Date: second},
+	})
+	require.NoError(t, err)
+	require.Len(t, results, 3)
+	for _, r := range results {
+		require.NoError(t, r.Err)
+	}
+	assert.Equal(t, "80", results[0].Result.Result.String())
+	assert.Equal(t, "USD", results[0].Result.From)
+	assert.Equal(t, "200", results[1].Result.Result.String())
+	assert.Equal(t, "200", results[2].Result.Result.String())
+	assert.Equal(t, "2025-08-02", results[2].Result.Date)
+
+	mockService.AssertExpectations(t)
+}
+
+func TestConvertBatch_PerItemErrors(t *testing.T) {
+	ctx := context.Background()
+	usecase, mockService, _, _ := setupTestUsecase()
+
+	date := time.Date(2025, 8, 1, 0, 0, 0, 0, time.UTC)
+	mockService.On("GetCurrencyByCharCode", ctx, "USD").Return(&entity.CurrencyInfo{CharCode: "USD"}, nil).Once()
+	mockService.On("GetCurrencyByCharCode", ctx, "XDR").Return(&entity.CurrencyInfo{CharCode: "XDR"}, nil).Once()
+	mockService.On("GetCurrencyByCharCode", ctx, "ABC").Return(nil, fmt.Errorf("%w: ABC", ErrUnknownCurrency)).Once()
+	mockService.On("GetRatesByCharCodesAndDate", ctx, "cbr", []string{"USD", "XDR"}, date).Return(map[string]entity.Currency{
+		"USD": {CharCode: "USD", Nominal: 1, Value: decimal.NewFromInt(80), Date: date},
+	}, nil).Once()
+
+	results, err := usecase.ConvertBatch(ctx, "", []ConversionItem{
+		{From: "USD", To: "RUB", Amount: decimal.NewFromInt(1), Date: date},
+		{From: "US1", To: "RUB", Amount: decimal.NewFromInt(1), Date: date},
+		{From: "USD", To: "RUB", Amount: decimal.Zero, Date: date},
+		{From: "USD", To: "RUB", Amount: decimal.NewFromInt(1), Date: time.Now().AddDate(0, 0, 5)},
+		{From: "ABC", To: "USD", Amount: decimal.NewFromInt(1), Date: date},
+		{From: "XDR", To: "USD", Amount: decimal.NewFromInt(1), Date: date},
+	})
+	require.NoError(t, err)
+	require.Len(t, results, 6)
+	assert.NoError(t, results[0].Err)
+	assert.Equal(t, "80", results[0].Result.Result.String())
+	assert.ErrorIs(t, results[1].Err, ErrInvalidCharCode)
+	assert.ErrorIs(t, results[2].Err, ErrInvalidAmount)
+	assert.ErrorIs(t, results[3].Err, ErrFutureDate)
+	assert.ErrorIs(t, results[4].Err, ErrUnknownCurrency)
+	assert.ErrorIs(t, results[5].Err, ErrRateNotFound)
+	for _, r := range results[1:] {
+		assert.Nil(t, r.Result)
+	}
+
+	mockService.AssertExpectations(t)
+}
+
+func TestConvertBatch_ServiceErrorOnlyFailsItsDate(t *testing.T) {
+	ctx := context.Background()
+	usecase, mockService, _, _ := setupTestUsecase()
+	knownCurrencies(mockService)
+
+	first := time.Date(2025, 8, 1, 0, 0, 0, 0, time.UTC)
+	second := time.Date(2025, 8, 4, 0, 0, 0, 0, time.UTC)
+	upstreamErr := fmt.Errorf("%w: timeout", ErrUpstreamUnavailable)
+	mockService.On("GetRatesByCharCodesAndDate", ctx, "cbr", []string{"USD"}, first).Return(nil, upstreamErr)
+	mockService.On("GetRatesByCharCodesAndDate", ctx, "cbr", []string{"USD"}, second).Return(map[string]entity.Currency{
+		"USD": {CharCode: "USD", Nominal: 1, Value: decimal.NewFromInt(80), Date: second},
+	}, nil)
+
+	results, err := usecase.ConvertBatch(ctx, "", []ConversionItem{
+		{From: "USD", To: "RUB", Amount: decimal.NewFromInt(1), Date: first},
+		{From: "RUB", To: "USD", Amount: decimal.NewFromInt(80), Date: first},
+		{From: "USD", To: "RUB", Amount: decimal.NewFromInt(1), Date: second},
+	})
+	require.NoError(t, err)
+	assert.ErrorIs(t, results[0].Err, ErrUpstreamUnavailable)
+	assert.ErrorIs(t, results[1].Err, ErrUpstreamUnavailable)
+	assert.NoError(t, results[2].Err)
+
+	mockService.AssertExpectations(t)
+}
+
+func TestConvertBatch_BaseOnlySkipsLookup(t *testing.T) {
+	ctx := context.Background()
+	usecase, mockService, _, _ := setupTestUsecase()
+
+	date := time.Date(2025, 8, 1, 0, 0, 0, 0, time.UTC)
+	results, err := usecase.ConvertBatch(ctx, "", []ConversionItem{{From: "RUB", To: "RUB", Amount: decimal.NewFromInt(5), Date: date}})
+	require.NoError(t, err)
+	require.NoError(t, results[0].Err)
+	assert.Equal(t, "5", results[0].Result.Result.String())
+	mockService.AssertNotCalled(t, "GetRatesByCharCodesAndDate", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestConvertBatch_UnknownSource(t *testing.T) {
+	ctx := context.Background()
+	usecase, mockService, _, _ := setupTestUsecase()
+
+	mockService.On("BaseCurrency", "nbk").Return("", fmt.Errorf("%w: nbk", ErrUnknownSource))
+
+	results, err := usecase.ConvertBatch(ctx, "nbk", []ConversionItem{{From: "USD", To: "EUR", Amount: decimal.NewFromInt(1)}})
+	assert.Nil(t, results)
+	assert.ErrorIs(t, err, ErrUnknownSource)
+}
+
+func TestGetRateStats(t *testing.T) {
+	ctx := context.Background()
+	usecase, mockService, _, _ := setupTestUsecase()
+	knownCurrencies(mockService)
+
+	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2025, 6, 30, 0, 0, 0, 0, time.UTC)
+	mockService.On("GetRateStats", ctx, "cbr", "USD", from, to).Return(&entity.RateStats{
+		Source:     "cbr",
+		CharCode:   "USD",
+		Count:      115,
+		FirstDate:  time.Date(2025, 1, 11, 0, 0, 0, 0, time.UTC),
+		LastDate:   time.Date(2025, 6, 28, 0, 0, 0, 0, time.UTC),
+		Min:        decimal.RequireFromString("78.1"),
+		Max:        decimal.RequireFromString("101.6797"),
+		Mean:       decimal.RequireFromString("89.35123456789"),
+		Median:     decimal.RequireFromString("88.7"),
+		First:      decimal.RequireFromString("100"),
+		Last:       decimal.RequireFromString("78.5"),
+		Volatility: decimal.RequireFromString("0.6123456789"),
+		Monthly: []entity.PeriodAverage{
+			{Start: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), Average: decimal.RequireFromString("100.5"), Count: 15},
+		},
+		Quarterly: []entity.PeriodAverage{
+			{Start: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), Average: decimal.RequireFromString("95.1"), Count: 55},
+			{Start: time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC), Average: decimal.RequireFromString("82.4"), Count: 60},
+		},
+	}, nil)
+
+	result, err := usecase.GetRateStats(ctx, "", "usd", from, to)
+	require.NoError(t, err)
+	assert.Equal(t, "USD", result.CharCode)
+	assert.Equal(t, "RUB", result.Base)
+	assert.Equal(t, "2025-01-11", result.FirstDate)
+	assert.Equal(t, "89.351235", result.Mean.String())
+	assert.Equal(t, "-21.5", result.Change.String())
+	assert.Equal(t, "-21.5", result.ChangePercent.String())
+	assert.Equal(t, "0.612346", result.Volatility.String())
+	require.Len(t, result.Monthly, 1)
+	assert.Equal(t, "2025-01", result.Monthly[0].Period)
+	require.Len(t, result.Quarterly, 2)
+	assert.Equal(t, "2025-Q1", result.Quarterly[0].Period)
+	assert.Equal(t, "2025-Q2", result.Quarterly[1].Period)
+	assert.Equal(t, 60, result.Quarterly[1].Count)
 
 	mockService.AssertExpectations(t)
 }
+
+func TestGetRateStats_Validation(t *testing.T) {
+	ctx := context.Background()
+	from := time.Date(2025, 8, 5, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2025, 8, 1, 0, 0, 0, 0, time.UTC)
+
+	t.Run("invalid char code", func(t *testing.T) {
+		usecase, mockService, _, _ := setupTestUsecase()
+		_, err := usecase.GetRateStats(ctx, "", "US", to, from)
+		assert.ErrorIs(t, err, ErrInvalidCharCode)
+		mockService.AssertNotCalled(t, "GetRateStats", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("inverted range", func(t *testing.T) {
+		usecase, mockService, _, _ := setupTestUsecase()
+		_, err := usecase.GetRateStats(ctx, "", "USD", from, to)
+		assert.ErrorIs(t, err, ErrInvalidDateRange)
+		mockService.AssertNotCalled(t, "GetRateStats", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("unknown currency", func(t *testing.T) {
+		usecase, mockService, _, _ := setupTestUsecase()
+		mockService.On("GetCurrencyByCharCode", ctx, "XYZ").Return(nil, fmt.Errorf("%w: XYZ", ErrUnknownCurrency))
+		_, err := usecase.GetRateStats(ctx, "", "XYZ", to, from)
+		assert.ErrorIs(t, err, ErrUnknownCurrency)
+		mockService.AssertNotCalled(t, "GetRateStats", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+}
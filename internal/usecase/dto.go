@@ -1,6 +1,353 @@
 package usecase
 
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// CurrencyResponse keeps the value_rub name for compatibility; the value is in
+// Base, which is RUB only for the cbr source. EffectiveDate is the publication
+// in effect on RequestedDate, an earlier one on days the source does not publish.
 type CurrencyResponse struct {
-	CharCode string  `json:"char_name"`
-	ValueRUB float64 `json:"value_rub"`
+	CharCode      string          `json:"char_name"`
+	ValueRUB      decimal.Decimal `json:"value_rub"`
+	Source        string          `json:"source"`
+	Base          string          `json:"base"`
+	Fallback      bool            `json:"fallback"`
+	RequestedDate string          `json:"requested_date,omitempty"`
+	EffectiveDate string          `json:"effective_date,omitempty"`
+}
+
+type RateHistoryResponse struct {
+	CharCode string      `json:"char_name"`
+	Source   string      `json:"source"`
+	Base     string      `json:"base"`
+	Fallback bool        `json:"fallback"`
+	From     string      `json:"from"`
+	To       string      `json:"to"`
+	Rates    []RatePoint `json:"rates"`
+}
+
+// RatePoint.Source is set only for points served by a fallback source.
+type RatePoint struct {
+	Date     string          `json:"date"`
+	Nominal  int             `json:"nominal"`
+	Value    decimal.Decimal `json:"value"`
+	ValueRUB decimal.Decimal `json:"value_rub"`
+	Source   string          `json:"source,omitempty"`
+}
+
+// RateStatsResponse values are per unit of CharCode in Base, over the
+// publications between From and To. ChangePercent is relative to First and
+// Volatility is the standard deviation of publication-to-publication changes, in percent.
+type RateStatsResponse struct {
+	CharCode      string                  `json:"char_name"`
+	Source        string                  `json:"source"`
+	Base          string                  `json:"base"`
+	From          string                  `json:"from"`
+	To            string                  `json:"to"`
+	Count         int                     `json:"count"`
+	FirstDate     string                  `json:"first_date"`
+	LastDate      string                  `json:"last_date"`
+	Min           decimal.Decimal         `json:"min"`
+	Max           decimal.Decimal         `json:"max"`
+	Mean          decimal.Decimal         `json:"mean"`
+	Median        decimal.Decimal         `json:"median"`
+	First         decimal.Decimal         `json:"first"`
+	Last          decimal.Decimal         `json:"last"`
+	Change        decimal.Decimal         `json:"change"`
+	ChangePercent decimal.Decimal         `json:"change_percent"`
+	Volatility    decimal.Decimal         `json:"volatility"`
+	Monthly       []PeriodAverageResponse `json:"monthly"`
+	Quarterly     []PeriodAverageResponse `json:"quarterly"`
+}
+
+// PeriodAverageResponse.Period is "2025-01" for a month or "2025-Q1" for a
+// quarter; a period cut by the range averages only its publications inside it.
+type PeriodAverageResponse struct {
+	Period  string          `json:"period"`
+	Average decimal.Decimal `json:"average"`
+	Count   int             `json:"count"`
+}
+
+type ConversionResponse struct {
+	From          string          `json:"from"`
+	To            string          `json:"to"`
+	Amount        decimal.Decimal `json:"amount"`
+	Rate          decimal.Decimal `json:"rate"`
+	Result        decimal.Decimal `json:"result"`
+	Date          string          `json:"date"`
+	EffectiveDate string          `json:"effective_date,omitempty"`
+	Source        string          `json:"source"`
+	Fallback      bool            `json:"fallback"`
+}
+
+// ConversionItem is one line of a batch conversion; a zero Date means today.
+type ConversionItem struct {
+	From   string
+	To     string
+	Amount decimal.Decimal
+	Date   time.Time
+}
+
+// ConversionResult holds either the conversion of an item or why it failed.
+type ConversionResult struct {
+	Result *ConversionResponse
+	Err    error
+}
+
+type CurrencyListResponse struct {
+	Count      int                `json:"count"`
+	Currencies []CurrencyListItem `json:"currencies"`
+}
+
+type CurrencyListItem struct {
+	CharCode   string `json:"char_code"`
+	NumCode    string `json:"num_code,omitempty"`
+	CbrID      string `json:"cbr_id"`
+	Name       string `json:"name"`
+	EngName    string `json:"eng_name"`
+	Nominal    int    `json:"nominal"`
+	ParentCode string `json:"parent_code"`
+	Monthly    bool   `json:"monthly"`
+}
+
+type MetalPriceResponse struct {
+	Code string          `json:"code"`
+	Name string          `json:"name"`
+	Date string          `json:"date"`
+	Buy  decimal.Decimal `json:"buy"`
+	Sell decimal.Decimal `json:"sell"`
+}
+
+type MetalPriceHistoryResponse struct {
+	Code   string            `json:"code"`
+	Name   string            `json:"name"`
+	From   string            `json:"from"`
+	To     string            `json:"to"`
+	Prices []MetalPricePoint `json:"prices"`
+}
+
+type MetalPricePoint struct {
+	Date string          `json:"date"`
+	Buy  decimal.Decimal `json:"buy"`
+	Sell decimal.Decimal `json:"sell"`
+}
+
+type KeyRateHistoryResponse struct {
+	From  string         `json:"from"`
+	To    string         `json:"to"`
+	Rates []KeyRatePoint `json:"rates"`
+}
+
+type KeyRatePoint struct {
+	Date string          `json:"date"`
+	Rate decimal.Decimal `json:"rate"`
+}
+
+type RuoniaHistoryResponse struct {
+	From  string        `json:"from"`
+	To    string        `json:"to"`
+	Rates []RuoniaPoint `json:"rates"`
+}
+
+type RuoniaPoint struct {
+	Date   string          `json:"date"`
+	Rate   decimal.Decimal `json:"rate"`
+	Volume decimal.Decimal `json:"volume"`
+}
+
+type BackfillJobResponse struct {
+	ID         int64      `json:"id"`
+	From       string     `json:"from"`
+	To         string     `json:"to"`
+	CharCodes  []string   `json:"char_codes,omitempty"`
+	Status     string     `json:"status"`
+	NextDate   string     `json:"next_date"`
+	DaysTotal  int        `json:"days_total"`
+	DaysDone   int        `json:"days_done"`
+	Progress   float64    `json:"progress"`
+	Error      string     `json:"error,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+	FinishedAt *time.Time `json:"finished_at,omitempty"`
+}
+
+// JobListResponse lists the scheduled jobs as seen by Instance; only the
+// leader replica runs them on schedule.
+type JobListResponse struct {
+	Instance string        `json:"instance"`
+	Leader   bool          `json:"leader"`
+	Timezone string        `json:"timezone"`
+	Jobs     []JobResponse `json:"jobs"`
+}
+
+type JobResponse struct {
+	Name       string          `json:"name"`
+	Schedule   string          `json:"schedule,omitempty"`
+	RunOnStart bool            `json:"run_on_start"`
+	NextRun    *time.Time      `json:"next_run,omitempty"`
+	Running    bool            `json:"running"`
+	LastRun    *JobRunResponse `json:"last_run,omitempty"`
+}
+
+type JobRunResponse struct {
+	ID          int64      `json:"id"`
+	Job         string     `json:"job"`
+	TriggeredBy string     `json:"triggered_by"`
+	Instance    string     `json:"instance"`
+	Status      string     `json:"status"`
+	Attempts    int        `json:"attempts"`
+	Error       string     `json:"error,omitempty"`
+	StartedAt   time.Time  `json:"started_at"`
+	FinishedAt  *time.Time `json:"finished_at,omitempty"`
+}
+
+type ReconciliationResponse struct {
+	Date          string            `json:"date"`
+	Discrepancies []DiscrepancyItem `json:"discrepancies"`
+}
+
+type DiscrepancyListResponse struct {
+	From          string            `json:"from"`
+	To            string            `json:"to"`
+	Discrepancies []DiscrepancyItem `json:"discrepancies"`
+}
+
+type DiscrepancyItem struct {
+	Date            string          `json:"date"`
+	CharCode        string          `json:"char_code"`
+	PrimarySource   string          `json:"primary_source"`
+	SecondarySource string          `json:"secondary_source"`
+	Base            string          `json:"base"`
+	PrimaryValue    decimal.Decimal `json:"primary_value"`
+	SecondaryValue  decimal.Decimal `json:"secondary_value"`
+	DiffPercent     decimal.Decimal `json:"diff_percent"`
+	DetectedAt      time.Time       `json:"detected_at"`
+}
+
+// AlertRuleInput describes a rule to create or replace. An empty Source is
+// cbr, an empty Secret is generated on create and kept on update, and a nil
+// Enabled enables the rule.
+type AlertRuleInput struct {
+	Source        string
+	CharCode      string
+	ThresholdType string
+	Threshold     decimal.Decimal
+	Direction     string
+	WebhookURL    string
+	Secret        string
+	Enabled       *bool
+}
+
+// AlertRuleResponse carries Secret only when the rule is created.
+type AlertRuleResponse struct {
+	ID            int64           `json:"id"`
+	Source        string          `json:"source"`
+	CharCode      string          `json:"char_code"`
+	ThresholdType string          `json:"threshold_type"`
+	Threshold     decimal.Decimal `json:"threshold"`
+	Direction     string          `json:"direction"`
+	WebhookURL    string          `json:"webhook_url"`
+	Secret        string          `json:"secret,omitempty"`
+	Enabled       bool            `json:"enabled"`
+	CreatedAt     time.Time       `json:"created_at"`
+	UpdatedAt     time.Time       `json:"updated_at"`
+}
+
+type AlertListResponse struct {
+	From   string          `json:"from"`
+	To     string          `json:"to"`
+	Alerts []AlertResponse `json:"alerts"`
+}
+
+type AlertResponse struct {
+	ID            int64           `json:"id"`
+	RuleID        int64           `json:"rule_id"`
+	Source        string          `json:"source"`
+	CharCode      string          `json:"char_code"`
+	Date          string          `json:"date"`
+	PreviousDate  string          `json:"previous_date"`
+	PreviousValue decimal.Decimal `json:"previous_value"`
+	Value         decimal.Decimal `json:"value"`
+	Change        decimal.Decimal `json:"change"`
+	ChangePercent decimal.Decimal `json:"change_percent"`
+	Status        string          `json:"status"`
+	Attempts      int             `json:"attempts"`
+	LastError     string          `json:"last_error,omitempty"`
+	TriggeredAt   time.Time       `json:"triggered_at"`
+	DeliveredAt   *time.Time      `json:"delivered_at,omitempty"`
+}
+
+type DeadLetterResponse struct {
+	ID        int64           `json:"id"`
+	AlertID   int64           `json:"alert_id"`
+	URL       string          `json:"url"`
+	Payload   json.RawMessage `json:"payload"`
+	Attempts  int             `json:"attempts"`
+	LastError string          `json:"last_error"`
+	CreatedAt time.Time       `json:"created_at"`
+}
+
+const (
+	HealthOK       = "ok"
+	HealthDegraded = "degraded"
+	HealthDown     = "down"
+	HealthStale    = "stale"
+	HealthFailed   = "failed"
+	HealthPending  = "pending"
+)
+
+// ReadinessResponse maps each readiness check to "ok" or the reason it failed.
+type ReadinessResponse struct {
+	Ready  bool              `json:"ready"`
+	Checks map[string]string `json:"checks"`
+}
+
+// HealthDetailsResponse is down when the database or schema check fails and
+// degraded when rates are stale or the last sync failed.
+type HealthDetailsResponse struct {
+	Status     string                  `json:"status"`
+	Ready      bool                    `json:"ready"`
+	CheckedAt  time.Time               `json:"checked_at"`
+	Database   DatabaseHealthResponse  `json:"database"`
+	Migrations MigrationHealthResponse `json:"migrations"`
+	Rates      RateFreshnessResponse   `json:"rates"`
+	Sync       SyncStatusResponse      `json:"sync"`
+}
+
+type DatabaseHealthResponse struct {
+	Status    string  `json:"status"`
+	LatencyMS float64 `json:"latency_ms"`
+	Error     string  `json:"error,omitempty"`
+}
+
+type MigrationHealthResponse struct {
+	Status  string `json:"status"`
+	Version int64  `json:"version"`
+	Latest  int64  `json:"latest"`
+	Dirty   bool   `json:"dirty"`
+	Error   string `json:"error,omitempty"`
+}
+
+// RateFreshnessResponse: LagHours is how far LatestDate is behind ExpectedDate,
+// the newest date the source has published by now.
+type RateFreshnessResponse struct {
+	Status       string `json:"status"`
+	Source       string `json:"source"`
+	LatestDate   string `json:"latest_date,omitempty"`
+	ExpectedDate string `json:"expected_date"`
+	LagHours     int64  `json:"lag_hours"`
+	MaxLagHours  int64  `json:"max_lag_hours"`
+	Error        string `json:"error,omitempty"`
+}
+
+// SyncStatusResponse is pending until the sync job has finished once.
+type SyncStatusResponse struct {
+	Status    string     `json:"status"`
+	LastAt    *time.Time `json:"last_at,omitempty"`
+	LastError string     `json:"last_error,omitempty"`
+	NextAt    *time.Time `json:"next_at,omitempty"`
 }
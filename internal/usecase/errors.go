@@ -0,0 +1,27 @@
+package usecase
+
+import (
+	"RnD-service/internal/service"
+	"errors"
+)
+
+var (
+	ErrInvalidCharCode = errors.New("invalid char code format")
+	ErrInvalidAmount   = errors.New("invalid 'amount' parameter, must be a positive number")
+
+	ErrFutureDate           = service.ErrFutureDate
+	ErrInvalidDateRange     = service.ErrInvalidDateRange
+	ErrRateNotFound         = service.ErrRateNotFound
+	ErrUnknownCurrency      = service.ErrUnknownCurrency
+	ErrUnknownMetal         = service.ErrUnknownMetal
+	ErrUnknownSource        = service.ErrUnknownSource
+	ErrUpstreamUnavailable  = service.ErrUpstreamUnavailable
+	ErrUpstreamDateMismatch = service.ErrUpstreamDateMismatch
+	ErrNoCommonCurrency     = service.ErrNoCommonCurrency
+	ErrBackfillJobNotFound  = service.ErrBackfillJobNotFound
+	ErrBackfillJobRunning   = service.ErrBackfillJobRunning
+	ErrInvalidAlertRule     = service.ErrInvalidAlertRule
+	ErrAlertRuleNotFound    = service.ErrAlertRuleNotFound
+	ErrJobNotFound          = service.ErrJobNotFound
+	ErrJobRunning           = service.ErrJobRunning
+)
@@ -0,0 +1,111 @@
+package usecase
+
+import (
+	"RnD-service/internal/entity"
+	"RnD-service/internal/service"
+	"context"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+type HealthCheckUsecase struct {
+	service service.HealthCheckService
+	logger  *logrus.Logger
+}
+
+func NewHealthCheckUsecase(service service.HealthCheckService, logger *logrus.Logger) *HealthCheckUsecase {
+	return &HealthCheckUsecase{
+		service: service,
+		logger:  logger,
+	}
+}
+
+func (uc *HealthCheckUsecase) GetReadiness(ctx context.Context) *ReadinessResponse {
+	report := uc.service.Check(ctx)
+
+	rates := HealthOK
+	switch {
+	case report.Rates.Error != "":
+		rates = report.Rates.Error
+	case report.Rates.Stale:
+		rates = HealthStale
+	}
+
+	return &ReadinessResponse{
+		Ready: report.Ready,
+		Checks: map[string]string{
+			"database":   checkResult(report.Database.OK, report.Database.Error),
+			"migrations": checkResult(report.Schema.OK, report.Schema.Error),
+			"rates":      rates,
+		},
+	}
+}
+
+func (uc *HealthCheckUsecase) GetHealthDetails(ctx context.Context) *HealthDetailsResponse {
+	report := uc.service.Check(ctx)
+
+	result := &HealthDetailsResponse{
+		Status:    HealthOK,
+		Ready:     report.Ready,
+		CheckedAt: report.CheckedAt,
+		Database: DatabaseHealthResponse{
+			Status:    statusOf(report.Database.OK, HealthDown),
+			LatencyMS: float64(report.Database.Latency.Microseconds()) / 1000,
+			Error:     report.Database.Error,
+		},
+		Migrations: MigrationHealthResponse{
+			Status:  statusOf(report.Schema.OK, HealthDown),
+			Version: report.Schema.Version,
+			Latest:  report.Schema.Latest,
+			Dirty:   report.Schema.Dirty,
+			Error:   report.Schema.Error,
+		},
+		Rates: RateFreshnessResponse{
+			Status:       statusOf(!report.Rates.Stale, HealthStale),
+			Source:       report.Rates.Source,
+			ExpectedDate: report.Rates.ExpectedDate.Format("2006-01-02"),
+			LagHours:     int64(report.Rates.Lag / time.Hour),
+			MaxLagHours:  int64(report.Rates.MaxLag / time.Hour),
+			Error:        report.Rates.Error,
+		},
+		Sync: toSyncStatusResponse(report.Sync),
+	}
+	if !report.Rates.LatestDate.IsZero() {
+		result.Rates.LatestDate = report.Rates.LatestDate.Format("2006-01-02")
+	}
+
+	switch {
+	case !report.Database.OK || !report.Schema.OK:
+		result.Status = HealthDown
+	case report.Rates.Stale || result.Sync.Status == HealthFailed:
+		result.Status = HealthDegraded
+	}
+	return result
+}
+
+func toSyncStatusResponse(sync entity.SyncStatus) SyncStatusResponse {
+	result := SyncStatusResponse{Status: HealthPending, LastError: sync.LastError}
+	if !sync.LastAt.IsZero() {
+		result.LastAt = &sync.LastAt
+		result.Status = statusOf(sync.LastError == "", HealthFailed)
+	}
+	if !sync.NextAt.IsZero() {
+		result.NextAt = &sync.NextAt
+	}
+	return result
+}
+
+func statusOf(ok bool, failed string) string {
+	if ok {
+		return HealthOK
+	}
+	return failed
+}
+
+func checkResult(ok bool, reason string) string {
+	if ok {
+		return HealthOK
+	}
+	return reason
+}
@@ -0,0 +1,122 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"RnD-service/internal/entity"
+
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type mockHealthService struct {
+	mock.Mock
+}
+
+func (m *mockHealthService) Check(ctx context.Context) *entity.HealthReport {
+	args := m.Called(ctx)
+	return args.Get(0).(*entity.HealthReport)
+}
+
+func setupHealthUsecase(report *entity.HealthReport) *HealthCheckUsecase {
+	mockService := new(mockHealthService)
+	mockService.On("Check", mock.Anything).Return(report)
+	logger, _ := test.NewNullLogger()
+	return NewHealthCheckUsecase(mockService, logger)
+}
+
+func healthyReport() *entity.HealthReport {
+	checkedAt := time.Date(2025, 8, 4, 12, 0, 0, 0, time.UTC)
+	return &entity.HealthReport{
+		CheckedAt: checkedAt,
+		Ready:     true,
+		Database:  entity.DatabaseHealth{OK: true, Latency: 1500 * time.Microsecond},
+		Schema:    entity.SchemaHealth{OK: true, Version: 12, Latest: 12},
+		Rates: entity.RateFreshness{
+			Source:       "cbr",
+			LatestDate:   time.Date(2025, 8, 2, 0, 0, 0, 0, time.UTC),
+			ExpectedDate: time.Date(2025, 8, 5, 0, 0, 0, 0, time.UTC),
+			Lag:          72 * time.Hour,
+			MaxLag:       72 * time.Hour,
+		},
+		Sync: entity.SyncStatus{
+			LastAt: checkedAt.Add(-time.Hour),
+			NextAt: time.Date(2025, 8, 5, 13, 0, 0, 0, time.UTC),
+		},
+	}
+}
+
+func TestGetHealthDetails(t *testing.T) {
+	uc := setupHealthUsecase(healthyReport())
+
+	result := uc.GetHealthDetails(context.Background())
+	assert.Equal(t, HealthOK, result.Status)
+	assert.True(t, result.Ready)
+	assert.Equal(t, 1.5, result.Database.LatencyMS)
+	assert.Equal(t, HealthOK, result.Migrations.Status)
+	assert.Equal(t, "2025-08-02", result.Rates.LatestDate)
+	assert.Equal(t, "2025-08-05", result.Rates.ExpectedDate)
+	assert.Equal(t, int64(72), result.Rates.LagHours)
+	assert.Equal(t, HealthOK, result.Rates.Status)
+	assert.Equal(t, HealthOK, result.Sync.Status)
+	require.NotNil(t, result.Sync.NextAt)
+	assert.Equal(t, time.Date(2025, 8, 5, 13, 0, 0, 0, time.UTC), *result.Sync.NextAt)
+}
+
+func TestGetHealthDetails_Degraded(t *testing.T) {
+	report := healthyReport()
+	report.Sync.LastError = "CBR answered 503"
+
+	result := setupHealthUsecase(report).GetHealthDetails(context.Background())
+	assert.Equal(t, HealthDegraded, result.Status)
+	assert.Equal(t, HealthFailed, result.Sync.Status)
+	assert.Equal(t, "CBR answered 503", result.Sync.LastError)
+}
+
+func TestGetHealthDetails_Down(t *testing.T) {
+	report := &entity.HealthReport{
+		Database: entity.DatabaseHealth{Error: "connection refused"},
+		Schema:   entity.SchemaHealth{Error: "database unavailable"},
+		Rates:    entity.RateFreshness{Source: "cbr", Error: "database unavailable"},
+	}
+
+	result := setupHealthUsecase(report).GetHealthDetails(context.Background())
+	assert.Equal(t, HealthDown, result.Status)
+	assert.Equal(t, HealthDown, result.Database.Status)
+	assert.Empty(t, result.Rates.LatestDate)
+	assert.Equal(t, HealthPending, result.Sync.Status)
+	assert.Nil(t, result.Sync.LastAt)
+}
+
+func TestGetReadiness(t *testing.T) {
+	t.Run("ready", func(t *testing.T) {
+		result := setupHealthUsecase(healthyReport()).GetReadiness(context.Background())
+		assert.True(t, result.Ready)
+		assert.Equal(t, map[string]string{"database": "ok", "migrations": "ok", "rates": "ok"}, result.Checks)
+	})
+
+	t.Run("stale rates", func(t *testing.T) {
+		report := healthyReport()
+		report.Ready = false
+		report.Rates.Stale = true
+
+		result := setupHealthUsecase(report).GetReadiness(context.Background())
+		assert.False(t, result.Ready)
+		assert.Equal(t, HealthStale, result.Checks["rates"])
+		assert.Equal(t, HealthOK, result.Checks["database"])
+	})
+
+	t.Run("pending migrations", func(t *testing.T) {
+		report := healthyReport()
+		report.Ready = false
+		report.Schema = entity.SchemaHealth{Version: 11, Latest: 12, Error: "schema is not at the latest migration"}
+
+		result := setupHealthUsecase(report).GetReadiness(context.Background())
+		assert.False(t, result.Ready)
+		assert.Equal(t, "schema is not at the latest migration", result.Checks["migrations"])
+	})
+}
@@ -0,0 +1,36 @@
+package usecase
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+const maxHistoryRangeDays = 366
+
+// validateHistoryRange defaults an empty end date to today and returns it.
+func validateHistoryRange(logger logrus.FieldLogger, dateFrom, dateTo time.Time) (time.Time, error) {
+	today := time.Now().Truncate(24 * time.Hour)
+	if dateTo.IsZero() {
+		dateTo = today
+		logger.Debugf("No end date provided, using today: %s", dateTo.Format("2006-01-02"))
+	}
+
+	if dateFrom.After(dateTo) {
+		logger.Warnf("Invalid date range: %s > %s", dateFrom.Format("2006-01-02"), dateTo.Format("2006-01-02"))
+		return time.Time{}, fmt.Errorf("%w: 'from' is after 'to'", ErrInvalidDateRange)
+	}
+
+	if dateTo.After(today) {
+		logger.Warnf("Requested future date: %s", dateTo.Format("2006-01-02"))
+		return time.Time{}, ErrFutureDate
+	}
+
+	if dateTo.Sub(dateFrom) > maxHistoryRangeDays*24*time.Hour {
+		logger.Warnf("Requested date range too long: %s - %s", dateFrom.Format("2006-01-02"), dateTo.Format("2006-01-02"))
+		return time.Time{}, fmt.Errorf("%w: must not exceed %d days", ErrInvalidDateRange, maxHistoryRangeDays)
+	}
+
+	return dateTo, nil
+}
@@ -0,0 +1,83 @@
+package usecase
+
+import (
+	"RnD-service/internal/service"
+	"context"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+type IndicatorRateUsecase struct {
+	service service.IndicatorRateService
+	logger  *logrus.Logger
+}
+
+func NewIndicatorRateUsecase(service service.IndicatorRateService, logger *logrus.Logger) *IndicatorRateUsecase {
+	return &IndicatorRateUsecase{
+		service: service,
+		logger:  logger,
+	}
+}
+
+func (uc *IndicatorRateUsecase) SyncIndicatorsFromCBR(ctx context.Context) error {
+	uc.logger.Info("Syncing key rate and RUONIA from API...")
+	return uc.service.SyncIndicators(ctx)
+}
+
+func (uc *IndicatorRateUsecase) GetKeyRateHistory(ctx context.Context, dateFrom, dateTo time.Time) (*KeyRateHistoryResponse, error) {
+	dateTo, err := validateHistoryRange(uc.logger.WithContext(ctx), dateFrom, dateTo)
+	if err != nil {
+		return nil, err
+	}
+
+	rates, err := uc.service.GetKeyRates(ctx, dateFrom, dateTo)
+	if err != nil {
+		uc.logger.WithError(err).Errorf("Failed to get key rate history for %s - %s", dateFrom.Format("2006-01-02"), dateTo.Format("2006-01-02"))
+		return nil, err
+	}
+
+	result := &KeyRateHistoryResponse{
+		From:  dateFrom.Format("2006-01-02"),
+		To:    dateTo.Format("2006-01-02"),
+		Rates: make([]KeyRatePoint, 0, len(rates)),
+	}
+	for _, rate := range rates {
+		result.Rates = append(result.Rates, KeyRatePoint{
+			Date: rate.Date.Format("2006-01-02"),
+			Rate: rate.Rate,
+		})
+	}
+
+	uc.logger.Infof("Successfully fetched %d key rates between %s and %s", len(result.Rates), result.From, result.To)
+	return result, nil
+}
+
+func (uc *IndicatorRateUsecase) GetRuoniaHistory(ctx context.Context, dateFrom, dateTo time.Time) (*RuoniaHistoryResponse, error) {
+	dateTo, err := validateHistoryRange(uc.logger.WithContext(ctx), dateFrom, dateTo)
+	if err != nil {
+		return nil, err
+	}
+
+	rates, err := uc.service.GetRuoniaRates(ctx, dateFrom, dateTo)
+	if err != nil {
+		uc.logger.WithError(err).Errorf("Failed to get RUONIA history for %s - %s", dateFrom.Format("2006-01-02"), dateTo.Format("2006-01-02"))
+		return nil, err
+	}
+
+	result := &RuoniaHistoryResponse{
+		From:  dateFrom.Format("2006-01-02"),
+		To:    dateTo.Format("2006-01-02"),
+		Rates: make([]RuoniaPoint, 0, len(rates)),
+	}
+	for _, rate := range rates {
+		result.Rates = append(result.Rates, RuoniaPoint{
+			Date:   rate.Date.Format("2006-01-02"),
+			Rate:   rate.Rate,
+			Volume: rate.Volume,
+		})
+	}
+
+	uc.logger.Infof("Successfully fetched %d RUONIA rates between %s and %s", len(result.Rates), result.From, result.To)
+	return result, nil
+}
@@ -0,0 +1,114 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"RnD-service/internal/entity"
+
+	"github.com/shopspring/decimal"
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type mockIndicatorService struct {
+	mock.Mock
+}
+
+func (m *mockIndicatorService) SyncIndicators(ctx context.Context) error {
+	args := m.Called(ctx)
+	return args.Error(0)
+}
+
+func (m *mockIndicatorService) GetKeyRates(ctx context.Context, dateFrom, dateTo time.Time) ([]entity.KeyRate, error) {
+	args := m.Called(ctx, dateFrom, dateTo)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]entity.KeyRate), args.Error(1)
+}
+
+func (m *mockIndicatorService) GetRuoniaRates(ctx context.Context, dateFrom, dateTo time.Time) ([]entity.RuoniaRate, error) {
+	args := m.Called(ctx, dateFrom, dateTo)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]entity.RuoniaRate), args.Error(1)
+}
+
+func setupIndicatorUsecase() (*IndicatorRateUsecase, *mockIndicatorService) {
+	mockService := new(mockIndicatorService)
+	logger, _ := test.NewNullLogger()
+	return NewIndicatorRateUsecase(mockService, logger), mockService
+}
+
+func TestGetKeyRateHistory(t *testing.T) {
+	ctx := context.Background()
+	uc, mockService := setupIndicatorUsecase()
+
+	from := time.Date(2025, 7, 25, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2025, 7, 28, 0, 0, 0, 0, time.UTC)
+	mockService.On("GetKeyRates", ctx, from, to).Return([]entity.KeyRate{
+		{Date: from, Rate: decimal.RequireFromString("20")},
+		{Date: to, Rate: decimal.RequireFromString("18")},
+	}, nil)
+
+	result, err := uc.GetKeyRateHistory(ctx, from, to)
+	require.NoError(t, err)
+	assert.Equal(t, "2025-07-25", result.From)
+	assert.Equal(t, "2025-07-28", result.To)
+	require.Len(t, result.Rates, 2)
+	assert.Equal(t, "2025-07-28", result.Rates[1].Date)
+	assert.Equal(t, "18", result.Rates[1].Rate.String())
+	mockService.AssertExpectations(t)
+}
+
+func TestGetKeyRateHistory_Validation(t *testing.T) {
+	ctx := context.Background()
+	uc, mockService := setupIndicatorUsecase()
+
+	_, err := uc.GetKeyRateHistory(ctx, time.Date(2025, 7, 28, 0, 0, 0, 0, time.UTC), time.Date(2025, 7, 25, 0, 0, 0, 0, time.UTC))
+	assert.ErrorIs(t, err, ErrInvalidDateRange)
+
+	_, err = uc.GetKeyRateHistory(ctx, time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(2025, 7, 25, 0, 0, 0, 0, time.UTC))
+	assert.ErrorIs(t, err, ErrInvalidDateRange)
+
+	_, err = uc.GetKeyRateHistory(ctx, time.Now().Truncate(24*time.Hour), time.Now().AddDate(0, 0, 2))
+	assert.ErrorIs(t, err, ErrFutureDate)
+
+	mockService.AssertNotCalled(t, "GetKeyRates", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestGetRuoniaHistory_DefaultsToToday(t *testing.T) {
+	ctx := context.Background()
+	uc, mockService := setupIndicatorUsecase()
+
+	today := time.Now().Truncate(24 * time.Hour)
+	from := today.AddDate(0, 0, -7)
+	mockService.On("GetRuoniaRates", ctx, from, today).Return([]entity.RuoniaRate{
+		{Date: from, Rate: decimal.RequireFromString("17.85"), Volume: decimal.RequireFromString("512.34")},
+	}, nil)
+
+	result, err := uc.GetRuoniaHistory(ctx, from, time.Time{})
+	require.NoError(t, err)
+	assert.Equal(t, today.Format("2006-01-02"), result.To)
+	require.Len(t, result.Rates, 1)
+	assert.Equal(t, "512.34", result.Rates[0].Volume.String())
+	mockService.AssertExpectations(t)
+}
+
+func TestGetRuoniaHistory_ServiceError(t *testing.T) {
+	ctx := context.Background()
+	uc, mockService := setupIndicatorUsecase()
+
+	from := time.Date(2025, 7, 25, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2025, 7, 28, 0, 0, 0, 0, time.UTC)
+	mockService.On("GetRuoniaRates", ctx, from, to).Return(nil, ErrUpstreamUnavailable)
+
+	_, err := uc.GetRuoniaHistory(ctx, from, to)
+	assert.True(t, errors.Is(err, ErrUpstreamUnavailable))
+}
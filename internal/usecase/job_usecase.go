@@ -0,0 +1,78 @@
+package usecase
+
+import (
+	"RnD-service/internal/entity"
+	"RnD-service/internal/service"
+	"context"
+
+	"github.com/sirupsen/logrus"
+)
+
+type ScheduledJobUsecase struct {
+	service service.JobScheduler
+	logger  *logrus.Logger
+}
+
+func NewScheduledJobUsecase(service service.JobScheduler, logger *logrus.Logger) *ScheduledJobUsecase {
+	return &ScheduledJobUsecase{
+		service: service,
+		logger:  logger,
+	}
+}
+
+func (uc *ScheduledJobUsecase) ListJobs(ctx context.Context) (*JobListResponse, error) {
+	status, err := uc.service.Status(ctx)
+	if err != nil {
+		uc.logger.WithContext(ctx).WithError(err).Error("Failed to list scheduled jobs")
+		return nil, err
+	}
+
+	result := &JobListResponse{
+		Instance: status.Instance,
+		Leader:   status.Leader,
+		Timezone: status.Timezone,
+		Jobs:     make([]JobResponse, 0, len(status.Jobs)),
+	}
+	for _, job := range status.Jobs {
+		item := JobResponse{
+			Name:       job.Name,
+			Schedule:   job.Schedule,
+			RunOnStart: job.RunOnStart,
+			Running:    job.Running,
+		}
+		if !job.NextRun.IsZero() {
+			next := job.NextRun
+			item.NextRun = &next
+		}
+		if job.LastRun != nil {
+			item.LastRun = toJobRunResponse(job.LastRun)
+		}
+		result.Jobs = append(result.Jobs, item)
+	}
+	return result, nil
+}
+
+func (uc *ScheduledJobUsecase) RunJob(ctx context.Context, name string) (*JobRunResponse, error) {
+	run, err := uc.service.RunJob(ctx, name)
+	if err != nil {
+		uc.logger.WithContext(ctx).WithError(err).Errorf("Failed to run job %s", name)
+		return nil, err
+	}
+
+	uc.logger.WithContext(ctx).Infof("Triggered job %s, run %d", name, run.ID)
+	return toJobRunResponse(run), nil
+}
+
+func toJobRunResponse(run *entity.JobRun) *JobRunResponse {
+	return &JobRunResponse{
+		ID:          run.ID,
+		Job:         run.Job,
+		TriggeredBy: run.Trigger,
+		Instance:    run.Instance,
+		Status:      run.Status,
+		Attempts:    run.Attempts,
+		Error:       run.Error,
+		StartedAt:   run.StartedAt,
+		FinishedAt:  run.FinishedAt,
+	}
+}
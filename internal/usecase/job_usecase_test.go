@@ -0,0 +1,96 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"RnD-service/internal/entity"
+
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type mockJobScheduler struct {
+	mock.Mock
+}
+
+func (m *mockJobScheduler) Status(ctx context.Context) (*entity.SchedulerStatus, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.SchedulerStatus), args.Error(1)
+}
+
+func (m *mockJobScheduler) RunJob(ctx context.Context, name string) (*entity.JobRun, error) {
+	args := m.Called(ctx, name)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.JobRun), args.Error(1)
+}
+
+func setupJobUsecase() (*ScheduledJobUsecase, *mockJobScheduler) {
+	mockScheduler := new(mockJobScheduler)
+	logger, _ := test.NewNullLogger()
+	return NewScheduledJobUsecase(mockScheduler, logger), mockScheduler
+}
+
+func TestListJobs(t *testing.T) {
+	ctx := context.Background()
+	uc, mockScheduler := setupJobUsecase()
+
+	next := time.Date(2025, 8, 5, 13, 0, 0, 0, time.UTC)
+	finished := time.Date(2025, 8, 4, 13, 0, 12, 0, time.UTC)
+	mockScheduler.On("Status", ctx).Return(&entity.SchedulerStatus{
+		Instance: "api-1",
+		Leader:   true,
+		Timezone: "Europe/Moscow",
+		Jobs: []entity.JobInfo{
+			{Name: "cbr_rates", Schedule: "0 16 * * *", RunOnStart: true, NextRun: next, LastRun: &entity.JobRun{
+				ID: 7, Job: "cbr_rates", Trigger: entity.JobTriggerSchedule, Status: entity.JobRunSucceeded, Attempts: 1, FinishedAt: &finished,
+			}},
+			{Name: "reconcile"},
+		},
+	}, nil)
+
+	result, err := uc.ListJobs(ctx)
+	require.NoError(t, err)
+	assert.True(t, result.Leader)
+	assert.Equal(t, "Europe/Moscow", result.Timezone)
+	require.Len(t, result.Jobs, 2)
+	require.NotNil(t, result.Jobs[0].NextRun)
+	assert.Equal(t, next, *result.Jobs[0].NextRun)
+	require.NotNil(t, result.Jobs[0].LastRun)
+	assert.Equal(t, entity.JobTriggerSchedule, result.Jobs[0].LastRun.TriggeredBy)
+	assert.Equal(t, &finished, result.Jobs[0].LastRun.FinishedAt)
+	assert.Nil(t, result.Jobs[1].NextRun)
+	assert.Nil(t, result.Jobs[1].LastRun)
+}
+
+func TestRunJob(t *testing.T) {
+	ctx := context.Background()
+	uc, mockScheduler := setupJobUsecase()
+
+	mockScheduler.On("RunJob", ctx, "cbr_rates").Return(&entity.JobRun{ID: 8, Job: "cbr_rates", Trigger: entity.JobTriggerManual, Status: entity.JobRunRunning}, nil)
+
+	result, err := uc.RunJob(ctx, "cbr_rates")
+	require.NoError(t, err)
+	assert.Equal(t, int64(8), result.ID)
+	assert.Equal(t, entity.JobTriggerManual, result.TriggeredBy)
+	assert.Equal(t, entity.JobRunRunning, result.Status)
+}
+
+func TestRunJob_Running(t *testing.T) {
+	ctx := context.Background()
+	uc, mockScheduler := setupJobUsecase()
+
+	mockScheduler.On("RunJob", ctx, "cbr_rates").Return(nil, fmt.Errorf("%w: cbr_rates", ErrJobRunning))
+
+	_, err := uc.RunJob(ctx, "cbr_rates")
+	assert.ErrorIs(t, err, ErrJobRunning)
+}
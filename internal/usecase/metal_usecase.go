@@ -0,0 +1,102 @@
+package usecase
+
+import (
+	"RnD-service/internal/entity"
+	"RnD-service/internal/service"
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+type MetalPriceUsecase struct {
+	service service.MetalPriceService
+	logger  *logrus.Logger
+}
+
+func NewMetalPriceUsecase(service service.MetalPriceService, logger *logrus.Logger) *MetalPriceUsecase {
+	return &MetalPriceUsecase{
+		service: service,
+		logger:  logger,
+	}
+}
+
+func (uc *MetalPriceUsecase) FetchAndStoreMetalPricesFromCBR(ctx context.Context) error {
+	uc.logger.Info("Fetching metal prices from API...")
+	return uc.service.StoreMetalPricesFromCbr(ctx)
+}
+
+func (uc *MetalPriceUsecase) GetMetalPrice(ctx context.Context, code string, date time.Time) (*MetalPriceResponse, error) {
+	code = strings.ToUpper(code)
+	name, ok := entity.MetalNames[code]
+	if !ok {
+		uc.logger.Errorf("Unknown metal code: %s", code)
+		return nil, fmt.Errorf("%w: %s, expected one of AU, AG, PT, PD", ErrUnknownMetal, code)
+	}
+
+	today := time.Now().Truncate(24 * time.Hour)
+	if date.IsZero() {
+		date = today
+		uc.logger.Debugf("No date provided, using today: %s", date.Format("2006-01-02"))
+	}
+
+	if date.After(today) {
+		uc.logger.Warnf("Requested future date: %s", date.Format("2006-01-02"))
+		return nil, ErrFutureDate
+	}
+
+	price, err := uc.service.GetMetalPrice(ctx, code, date)
+	if err != nil {
+		uc.logger.WithError(err).Errorf("Failed to get metal price for %s on %s", code, date.Format("2006-01-02"))
+		return nil, err
+	}
+
+	uc.logger.Infof("Successfully fetched metal price for %s on %s", code, price.Date.Format("2006-01-02"))
+	return &MetalPriceResponse{
+		Code: price.Code,
+		Name: name,
+		Date: price.Date.Format("2006-01-02"),
+		Buy:  price.Buy,
+		Sell: price.Sell,
+	}, nil
+}
+
+func (uc *MetalPriceUsecase) GetMetalPriceHistory(ctx context.Context, code string, dateFrom, dateTo time.Time) (*MetalPriceHistoryResponse, error) {
+	code = strings.ToUpper(code)
+	name, ok := entity.MetalNames[code]
+	if !ok {
+		uc.logger.Errorf("Unknown metal code: %s", code)
+		return nil, fmt.Errorf("%w: %s, expected one of AU, AG, PT, PD", ErrUnknownMetal, code)
+	}
+
+	dateTo, err := validateHistoryRange(uc.logger.WithContext(ctx), dateFrom, dateTo)
+	if err != nil {
+		return nil, err
+	}
+
+	prices, err := uc.service.GetMetalPricesByDateRange(ctx, code, dateFrom, dateTo)
+	if err != nil {
+		uc.logger.WithError(err).Errorf("Failed to get metal price history for %s for %s - %s", code, dateFrom.Format("2006-01-02"), dateTo.Format("2006-01-02"))
+		return nil, err
+	}
+
+	result := &MetalPriceHistoryResponse{
+		Code:   code,
+		Name:   name,
+		From:   dateFrom.Format("2006-01-02"),
+		To:     dateTo.Format("2006-01-02"),
+		Prices: make([]MetalPricePoint, 0, len(prices)),
+	}
+	for _, price := range prices {
+		result.Prices = append(result.Prices, MetalPricePoint{
+			Date: price.Date.Format("2006-01-02"),
+			Buy:  price.Buy,
+			Sell: price.Sell,
+		})
+	}
+
+	uc.logger.Infof("Successfully fetched %d metal prices for %s between %s and %s", len(result.Prices), code, result.From, result.To)
+	return result, nil
+}
@@ -0,0 +1,124 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"RnD-service/internal/entity"
+
+	"github.com/shopspring/decimal"
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type mockMetalService struct {
+	mock.Mock
+}
+
+func (m *mockMetalService) StoreMetalPricesFromCbr(ctx context.Context) error {
+	args := m.Called(ctx)
+	return args.Error(0)
+}
+
+func (m *mockMetalService) GetMetalPrice(ctx context.Context, code string, date time.Time) (*entity.MetalPrice, error) {
+	args := m.Called(ctx, code, date)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.MetalPrice), args.Error(1)
+}
+
+func (m *mockMetalService) GetMetalPricesByDateRange(ctx context.Context, code string, dateFrom, dateTo time.Time) ([]entity.MetalPrice, error) {
+	args := m.Called(ctx, code, dateFrom, dateTo)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]entity.MetalPrice), args.Error(1)
+}
+
+func setupMetalUsecase() (*MetalPriceUsecase, *mockMetalService) {
+	mockService := new(mockMetalService)
+	logger, _ := test.NewNullLogger()
+	return NewMetalPriceUsecase(mockService, logger), mockService
+}
+
+func TestGetMetalPrice(t *testing.T) {
+	ctx := context.Background()
+	uc, mockService := setupMetalUsecase()
+
+	date := time.Date(2025, 7, 27, 0, 0, 0, 0, time.UTC)
+	mockService.On("GetMetalPrice", ctx, "AU", date).Return(&entity.MetalPrice{
+		Code: "AU",
+		Date: time.Date(2025, 7, 26, 0, 0, 0, 0, time.UTC),
+		Buy:  decimal.RequireFromString("8563.47"),
+		Sell: decimal.RequireFromString("8563.47"),
+	}, nil)
+
+	result, err := uc.GetMetalPrice(ctx, "au", date)
+	require.NoError(t, err)
+	assert.Equal(t, "AU", result.Code)
+	assert.Equal(t, "Золото", result.Name)
+	assert.Equal(t, "2025-07-26", result.Date, "date of the price actually set, not the requested one")
+	assert.Equal(t, "8563.47", result.Sell.String())
+	mockService.AssertExpectations(t)
+}
+
+func TestGetMetalPrice_Validation(t *testing.T) {
+	ctx := context.Background()
+	uc, mockService := setupMetalUsecase()
+
+	_, err := uc.GetMetalPrice(ctx, "GOLD", time.Date(2025, 7, 27, 0, 0, 0, 0, time.UTC))
+	assert.ErrorIs(t, err, ErrUnknownMetal)
+
+	_, err = uc.GetMetalPrice(ctx, "AU", time.Now().AddDate(0, 0, 2))
+	assert.ErrorIs(t, err, ErrFutureDate)
+
+	mockService.AssertNotCalled(t, "GetMetalPrice", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestGetMetalPrice_ServiceError(t *testing.T) {
+	ctx := context.Background()
+	uc, mockService := setupMetalUsecase()
+
+	date := time.Date(2025, 7, 27, 0, 0, 0, 0, time.UTC)
+	mockService.On("GetMetalPrice", ctx, "AG", date).Return(nil, ErrUpstreamUnavailable)
+
+	_, err := uc.GetMetalPrice(ctx, "AG", date)
+	assert.ErrorIs(t, err, ErrUpstreamUnavailable)
+}
+
+func TestGetMetalPriceHistory(t *testing.T) {
+	ctx := context.Background()
+	uc, mockService := setupMetalUsecase()
+
+	from := time.Date(2025, 7, 30, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2025, 7, 31, 0, 0, 0, 0, time.UTC)
+	mockService.On("GetMetalPricesByDateRange", ctx, "PD", from, to).Return([]entity.MetalPrice{
+		{Code: "PD", Date: from, Buy: decimal.RequireFromString("2950.1"), Sell: decimal.RequireFromString("2950.1")},
+		{Code: "PD", Date: to, Buy: decimal.RequireFromString("2961.5"), Sell: decimal.RequireFromString("2961.5")},
+	}, nil)
+
+	result, err := uc.GetMetalPriceHistory(ctx, "pd", from, to)
+	require.NoError(t, err)
+	assert.Equal(t, "Палладий", result.Name)
+	assert.Equal(t, "2025-07-30", result.From)
+	require.Len(t, result.Prices, 2)
+	assert.Equal(t, "2025-07-31", result.Prices[1].Date)
+	assert.Equal(t, "2961.5", result.Prices[1].Buy.String())
+}
+
+func TestGetMetalPriceHistory_InvalidRange(t *testing.T) {
+	ctx := context.Background()
+	uc, mockService := setupMetalUsecase()
+
+	_, err := uc.GetMetalPriceHistory(ctx, "AU", time.Date(2025, 7, 31, 0, 0, 0, 0, time.UTC), time.Date(2025, 7, 30, 0, 0, 0, 0, time.UTC))
+	assert.ErrorIs(t, err, ErrInvalidDateRange)
+
+	_, err = uc.GetMetalPriceHistory(ctx, "AU", time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(2025, 7, 30, 0, 0, 0, 0, time.UTC))
+	assert.ErrorIs(t, err, ErrInvalidDateRange)
+
+	mockService.AssertNotCalled(t, "GetMetalPricesByDateRange", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
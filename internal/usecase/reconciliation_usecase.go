@@ -0,0 +1,83 @@
+package usecase
+
+import (
+	"RnD-service/internal/entity"
+	"RnD-service/internal/service"
+	"context"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+type RateReconciliationUsecase struct {
+	service service.RateReconciliationService
+	logger  *logrus.Logger
+}
+
+func NewRateReconciliationUsecase(service service.RateReconciliationService, logger *logrus.Logger) *RateReconciliationUsecase {
+	return &RateReconciliationUsecase{
+		service: service,
+		logger:  logger,
+	}
+}
+
+// Reconcile defaults an empty date to today.
+func (uc *RateReconciliationUsecase) Reconcile(ctx context.Context, date time.Time) (*ReconciliationResponse, error) {
+	if date.IsZero() {
+		date = time.Now().Truncate(24 * time.Hour)
+	}
+
+	discrepancies, err := uc.service.Reconcile(ctx, date)
+	if err != nil {
+		uc.logger.WithError(err).Errorf("Failed to reconcile rates for %s", date.Format("2006-01-02"))
+		return nil, err
+	}
+
+	result := &ReconciliationResponse{
+		Date:          date.Format("2006-01-02"),
+		Discrepancies: toDiscrepancyItems(discrepancies),
+	}
+
+	uc.logger.Infof("Reconciled rates for %s: %d discrepancies", result.Date, len(result.Discrepancies))
+	return result, nil
+}
+
+func (uc *RateReconciliationUsecase) GetDiscrepancies(ctx context.Context, dateFrom, dateTo time.Time) (*DiscrepancyListResponse, error) {
+	dateTo, err := validateHistoryRange(uc.logger.WithContext(ctx), dateFrom, dateTo)
+	if err != nil {
+		return nil, err
+	}
+
+	discrepancies, err := uc.service.ListDiscrepancies(ctx, dateFrom, dateTo)
+	if err != nil {
+		uc.logger.WithError(err).Errorf("Failed to get rate discrepancies for %s - %s", dateFrom.Format("2006-01-02"), dateTo.Format("2006-01-02"))
+		return nil, err
+	}
+
+	result := &DiscrepancyListResponse{
+		From:          dateFrom.Format("2006-01-02"),
+		To:            dateTo.Format("2006-01-02"),
+		Discrepancies: toDiscrepancyItems(discrepancies),
+	}
+
+	uc.logger.Infof("Successfully fetched %d rate discrepancies between %s and %s", len(result.Discrepancies), result.From, result.To)
+	return result, nil
+}
+
+func toDiscrepancyItems(discrepancies []entity.RateDiscrepancy) []DiscrepancyItem {
+	items := make([]DiscrepancyItem, 0, len(discrepancies))
+	for _, d := range discrepancies {
+		items = append(items, DiscrepancyItem{
+			Date:            d.Date.Format("2006-01-02"),
+			CharCode:        d.CharCode,
+			PrimarySource:   d.PrimarySource,
+			SecondarySource: d.SecondarySource,
+			Base:            d.Base,
+			PrimaryValue:    d.PrimaryValue,
+			SecondaryValue:  d.SecondaryValue,
+			DiffPercent:     d.DiffPercent,
+			DetectedAt:      d.DetectedAt,
+		})
+	}
+	return items
+}
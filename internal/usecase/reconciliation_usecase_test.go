@@ -0,0 +1,100 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"RnD-service/internal/entity"
+
+	"github.com/shopspring/decimal"
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type mockReconciliationService struct {
+	mock.Mock
+}
+
+func (m *mockReconciliationService) Reconcile(ctx context.Context, date time.Time) ([]entity.RateDiscrepancy, error) {
+	args := m.Called(ctx, date)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]entity.RateDiscrepancy), args.Error(1)
+}
+
+func (m *mockReconciliationService) ListDiscrepancies(ctx context.Context, dateFrom, dateTo time.Time) ([]entity.RateDiscrepancy, error) {
+	args := m.Called(ctx, dateFrom, dateTo)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]entity.RateDiscrepancy), args.Error(1)
+}
+
+func setupReconciliationUsecase() (*RateReconciliationUsecase, *mockReconciliationService) {
+	mockService := new(mockReconciliationService)
+	logger, _ := test.NewNullLogger()
+	return NewRateReconciliationUsecase(mockService, logger), mockService
+}
+
+func TestReconcile_DefaultsToToday(t *testing.T) {
+	ctx := context.Background()
+	uc, mockService := setupReconciliationUsecase()
+
+	today := time.Now().Truncate(24 * time.Hour)
+	mockService.On("Reconcile", ctx, today).Return([]entity.RateDiscrepancy{
+		{Date: today, CharCode: "USD", PrimarySource: "cbr", SecondarySource: "ecb", Base: "EUR", PrimaryValue: decimal.RequireFromString("0.86956522"), SecondaryValue: decimal.RequireFromString("0.8769"), DiffPercent: decimal.RequireFromString("0.8364")},
+	}, nil)
+
+	result, err := uc.Reconcile(ctx, time.Time{})
+	require.NoError(t, err)
+	assert.Equal(t, today.Format("2006-01-02"), result.Date)
+	require.Len(t, result.Discrepancies, 1)
+	assert.Equal(t, "0.8364", result.Discrepancies[0].DiffPercent.String())
+	mockService.AssertExpectations(t)
+}
+
+func TestReconcile_NoDiscrepanciesIsEmptyList(t *testing.T) {
+	ctx := context.Background()
+	uc, mockService := setupReconciliationUsecase()
+
+	date := time.Date(2025, 8, 1, 0, 0, 0, 0, time.UTC)
+	mockService.On("Reconcile", ctx, date).Return([]entity.RateDiscrepancy(nil), nil)
+
+	result, err := uc.Reconcile(ctx, date)
+	require.NoError(t, err)
+	assert.NotNil(t, result.Discrepancies)
+	assert.Empty(t, result.Discrepancies)
+}
+
+func TestGetDiscrepancies_Validation(t *testing.T) {
+	ctx := context.Background()
+	uc, mockService := setupReconciliationUsecase()
+
+	_, err := uc.GetDiscrepancies(ctx, time.Date(2025, 8, 4, 0, 0, 0, 0, time.UTC), time.Date(2025, 8, 1, 0, 0, 0, 0, time.UTC))
+	assert.ErrorIs(t, err, ErrInvalidDateRange)
+
+	mockService.AssertNotCalled(t, "ListDiscrepancies", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestGetDiscrepancies(t *testing.T) {
+	ctx := context.Background()
+	uc, mockService := setupReconciliationUsecase()
+
+	from := time.Date(2025, 8, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2025, 8, 4, 0, 0, 0, 0, time.UTC)
+	mockService.On("ListDiscrepancies", ctx, from, to).Return([]entity.RateDiscrepancy{
+		{Date: from, CharCode: "USD", PrimarySource: "cbr", SecondarySource: "ecb", Base: "EUR"},
+	}, nil)
+
+	result, err := uc.GetDiscrepancies(ctx, from, to)
+	require.NoError(t, err)
+	assert.Equal(t, "2025-08-01", result.From)
+	assert.Equal(t, "2025-08-04", result.To)
+	require.Len(t, result.Discrepancies, 1)
+	assert.Equal(t, "2025-08-01", result.Discrepancies[0].Date)
+	mockService.AssertExpectations(t)
+}
@@ -0,0 +1,109 @@
+package usecase
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/shopspring/decimal"
+)
+
+type RoundingMode string
+
+const (
+	RoundHalfUp   RoundingMode = "half_up"
+	RoundHalfEven RoundingMode = "half_even"
+	RoundHalfDown RoundingMode = "half_down"
+	RoundUp       RoundingMode = "up"
+	RoundDown     RoundingMode = "down"
+	RoundCeil     RoundingMode = "ceil"
+	RoundFloor    RoundingMode = "floor"
+)
+
+// Rounding describes how converted amounts (Scale) and exchange rates (RateScale) are rounded.
+type Rounding struct {
+	Scale     int32
+	RateScale int32
+	Mode      RoundingMode
+}
+
+var DefaultRounding = Rounding{Scale: 4, RateScale: 6, Mode: RoundHalfUp}
+
+func NewRounding(scale, rateScale int32, mode string) (Rounding, error) {
+	if scale < 0 || rateScale < 0 {
+		return Rounding{}, fmt.Errorf("rounding scale must not be negative")
+	}
+
+	m := RoundingMode(strings.ToLower(mode))
+	switch m {
+	case "":
+		m = DefaultRounding.Mode
+	case RoundHalfUp, RoundHalfEven, RoundHalfDown, RoundUp, RoundDown, RoundCeil, RoundFloor:
+	default:
+		return Rounding{}, fmt.Errorf("unknown rounding mode %q", mode)
+	}
+
+	return Rounding{Scale: scale, RateScale: rateScale, Mode: m}, nil
+}
+
+// Amount returns a/b rounded to the amount scale.
+func (r Rounding) Amount(a, b decimal.Decimal) decimal.Decimal {
+	return divRound(a, b, r.Scale, r.Mode)
+}
+
+// Rate returns a/b rounded to the rate scale.
+func (r Rounding) Rate(a, b decimal.Decimal) decimal.Decimal {
+	return divRound(a, b, r.RateScale, r.Mode)
+}
+
+// divRound divides exactly: the truncated quotient and the remainder decide the last digit,
+// so no intermediate rounding happens before the configured mode is applied.
+func divRound(a, b decimal.Decimal, scale int32, mode RoundingMode) decimal.Decimal {
+	q, rem := a.QuoRem(b, scale)
+	if rem.IsZero() {
+		return q
+	}
+
+	negative := a.Sign()*b.Sign() < 0
+	unit := decimal.New(1, -scale)
+	if negative {
+		unit = unit.Neg()
+	}
+	away := q.Add(unit)
+
+	switch mode {
+	case RoundDown:
+		return q
+	case RoundUp:
+		return away
+	case RoundCeil:
+		if negative {
+			return q
+		}
+		return away
+	case RoundFloor:
+		if negative {
+			return away
+		}
+		return q
+	}
+
+	// rem is bounded by |b| * 10^-scale, so compare it with half of that
+	switch rem.Abs().Mul(decimal.NewFromInt(2)).Cmp(b.Abs().Shift(-scale)) {
+	case 1:
+		return away
+	case -1:
+		return q
+	}
+
+	switch mode {
+	case RoundHalfDown:
+		return q
+	case RoundHalfEven:
+		if q.Shift(scale).Mod(decimal.NewFromInt(2)).IsZero() {
+			return q
+		}
+		return away
+	default:
+		return away
+	}
+}
@@ -0,0 +1,120 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"RnD-service/internal/entity"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewRounding(t *testing.T) {
+	r, err := NewRounding(2, 6, "HALF_EVEN")
+	require.NoError(t, err)
+	assert.Equal(t, Rounding{Scale: 2, RateScale: 6, Mode: RoundHalfEven}, r)
+
+	r, err = NewRounding(4, 6, "")
+	require.NoError(t, err)
+	assert.Equal(t, RoundHalfUp, r.Mode)
+
+	_, err = NewRounding(4, 6, "bankers")
+	assert.ErrorContains(t, err, "unknown rounding mode")
+
+	_, err = NewRounding(-1, 6, "half_up")
+	assert.Error(t, err)
+}
+
+func TestDivRound_Modes(t *testing.T) {
+	tests := []struct {
+		a, b     string
+		scale    int32
+		mode     RoundingMode
+		expected string
+	}{
+		{"1", "8", 2, RoundHalfUp, "0.13"},
+		{"1", "8", 2, RoundHalfEven, "0.12"},
+		{"1", "8", 2, RoundHalfDown, "0.12"},
+		{"3", "8", 2, RoundHalfEven, "0.38"},
+		{"1", "3", 2, RoundUp, "0.34"},
+		{"1", "3", 2, RoundDown, "0.33"},
+		{"-1", "3", 2, RoundCeil, "-0.33"},
+		{"-1", "3", 2, RoundFloor, "-0.34"},
+		{"-1", "8", 2, RoundHalfUp, "-0.13"},
+		{"2", "3", 4, RoundHalfUp, "0.6667"},
+		{"10", "4", 4, RoundHalfUp, "2.5"},
+	}
+
+	for _, tt := range tests {
+		got := divRound(decimal.RequireFromString(tt.a), decimal.RequireFromString(tt.b), tt.scale, tt.mode)
+		assert.Equal(t, tt.expected, got.String(), "%s / %s at scale %d (%s)", tt.a, tt.b, tt.scale, tt.mode)
+	}
+}
+
+// Golden values from real CBR quotes where (Value / Nominal) * amount in float64
+// lands just below the rounding midpoint and rounds the wrong way.
+func TestGetHistoricalRateByCharCode_GoldenCBRValues(t *testing.T) {
+	date := time.Date(2025, 8, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		charCode string
+		value    string
+		nominal  int
+		amount   string
+		rounding Rounding
+		expected string
+	}{
+		// float64: 7.8641499999999995 -> 7.8641
+		{"CNY 0.7 units", "CNY", "11.2345", 1, "0.7", Rounding{Scale: 4, RateScale: 6, Mode: RoundHalfUp}, "7.8642"},
+		// float64: 201.48499999999999 -> 201.48
+		{"AMD per 100 units", "AMD", "20.1485", 100, "1000", Rounding{Scale: 2, RateScale: 6, Mode: RoundHalfUp}, "201.49"},
+		{"AMD per 100 units half even", "AMD", "20.1485", 100, "1000", Rounding{Scale: 2, RateScale: 6, Mode: RoundHalfEven}, "201.48"},
+		// float64: 1.5284499999999999 -> 1.5284
+		{"TRY 0.7 units", "TRY", "2.1835", 1, "0.7", Rounding{Scale: 4, RateScale: 6, Mode: RoundHalfUp}, "1.5285"},
+		{"USD 100 units", "USD", "69.0202", 1, "100", DefaultRounding, "6902.02"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			mockService := new(mockCurrencyService)
+			_, _, logger, _ := setupTestUsecase()
+			uc := NewCurrencyUsecase(mockService, tt.rounding, logger)
+			knownCurrencies(mockService)
+			cbrBase(mockService)
+
+			mockService.On("GetRateByCharCodeAndDate", ctx, "cbr", tt.charCode, date).Return(&entity.Currency{
+				CharCode: tt.charCode,
+				Nominal:  tt.nominal,
+				Value:    decimal.RequireFromString(tt.value),
+				Date:     date,
+			}, nil)
+
+			result, err := uc.GetHistoricalRateByCharCode(ctx, "", tt.charCode, date, decimal.RequireFromString(tt.amount))
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, result.ValueRUB.String())
+		})
+	}
+}
+
+func TestConvertCurrency_GoldenCrossRate(t *testing.T) {
+	ctx := context.Background()
+	usecase, mockService, _, _ := setupTestUsecase()
+	knownCurrencies(mockService)
+
+	date := time.Date(2025, 8, 1, 0, 0, 0, 0, time.UTC)
+	mockService.On("GetRateByCharCodeAndDate", ctx, "cbr", "USD", date).Return(&entity.Currency{CharCode: "USD", Nominal: 1, Value: decimal.RequireFromString("78.8320"), Date: date}, nil)
+	mockService.On("GetRateByCharCodeAndDate", ctx, "cbr", "KZT", date).Return(&entity.Currency{CharCode: "KZT", Nominal: 100, Value: decimal.RequireFromString("14.6072"), Date: date}, nil)
+
+	result, err := usecase.ConvertCurrency(ctx, "", "USD", "KZT", decimal.RequireFromString("3"), date)
+	require.NoError(t, err)
+	// 78.8320 * 100 / 14.6072 = 539.679062...
+	assert.Equal(t, "539.679062", result.Rate.String())
+	assert.Equal(t, "1619.0372", result.Result.String())
+
+	mockService.AssertExpectations(t)
+}
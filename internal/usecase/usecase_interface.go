@@ -3,10 +3,62 @@ package usecase
 import (
 	"context"
 	"time"
+
+	"github.com/shopspring/decimal"
 )
 
 type RateUsecase interface {
 	FetchAndStoreRatesFromCBR(ctx context.Context) error
-	GetRateByCharCode(ctx context.Context, charCode string, amount float64) (*CurrencyResponse, error)
-	GetHistoricalRateByCharCode(ctx context.Context, charCode string, date time.Time, amount float64) (*CurrencyResponse, error)
+	FetchAndStoreRates(ctx context.Context, source string) error
+	GetRateByCharCode(ctx context.Context, source, charCode string, amount decimal.Decimal) (*CurrencyResponse, error)
+	GetHistoricalRateByCharCode(ctx context.Context, source, charCode string, date time.Time, amount decimal.Decimal) (*CurrencyResponse, error)
+	GetRateHistoryByCharCode(ctx context.Context, source, charCode string, dateFrom, dateTo time.Time) (*RateHistoryResponse, error)
+	GetRateStats(ctx context.Context, source, charCode string, dateFrom, dateTo time.Time) (*RateStatsResponse, error)
+	ConvertCurrency(ctx context.Context, source, from, to string, amount decimal.Decimal, date time.Time) (*ConversionResponse, error)
+	ConvertBatch(ctx context.Context, source string, items []ConversionItem) ([]ConversionResult, error)
+	GetCurrencyList(ctx context.Context) (*CurrencyListResponse, error)
+}
+
+type MetalUsecase interface {
+	FetchAndStoreMetalPricesFromCBR(ctx context.Context) error
+	GetMetalPrice(ctx context.Context, code string, date time.Time) (*MetalPriceResponse, error)
+	GetMetalPriceHistory(ctx context.Context, code string, dateFrom, dateTo time.Time) (*MetalPriceHistoryResponse, error)
+}
+
+type IndicatorUsecase interface {
+	SyncIndicatorsFromCBR(ctx context.Context) error
+	GetKeyRateHistory(ctx context.Context, dateFrom, dateTo time.Time) (*KeyRateHistoryResponse, error)
+	GetRuoniaHistory(ctx context.Context, dateFrom, dateTo time.Time) (*RuoniaHistoryResponse, error)
+}
+
+type ReconciliationUsecase interface {
+	Reconcile(ctx context.Context, date time.Time) (*ReconciliationResponse, error)
+	GetDiscrepancies(ctx context.Context, dateFrom, dateTo time.Time) (*DiscrepancyListResponse, error)
+}
+
+type BackfillJobUsecase interface {
+	StartBackfill(ctx context.Context, dateFrom, dateTo time.Time, charCodes []string) (*BackfillJobResponse, error)
+	ResumeBackfill(ctx context.Context, id int64) (*BackfillJobResponse, error)
+	GetBackfillJob(ctx context.Context, id int64) (*BackfillJobResponse, error)
+	ListBackfillJobs(ctx context.Context) ([]BackfillJobResponse, error)
+}
+
+type AlertUsecase interface {
+	CreateAlertRule(ctx context.Context, input AlertRuleInput) (*AlertRuleResponse, error)
+	UpdateAlertRule(ctx context.Context, id int64, input AlertRuleInput) (*AlertRuleResponse, error)
+	DeleteAlertRule(ctx context.Context, id int64) error
+	GetAlertRule(ctx context.Context, id int64) (*AlertRuleResponse, error)
+	ListAlertRules(ctx context.Context) ([]AlertRuleResponse, error)
+	GetAlerts(ctx context.Context, dateFrom, dateTo time.Time) (*AlertListResponse, error)
+	GetDeadLetters(ctx context.Context) ([]DeadLetterResponse, error)
+}
+
+type JobUsecase interface {
+	ListJobs(ctx context.Context) (*JobListResponse, error)
+	RunJob(ctx context.Context, name string) (*JobRunResponse, error)
+}
+
+type HealthUsecase interface {
+	GetReadiness(ctx context.Context) *ReadinessResponse
+	GetHealthDetails(ctx context.Context) *HealthDetailsResponse
 }
@@ -1 +1,3 @@
-DROP TABLE IF EXISTS historical_currency_rates;
\ No newline at end of file
+DROP INDEX IF EXISTS idx_historical_currency_char_code;
+DROP INDEX IF EXISTS idx_historical_currency_date;
+DROP TABLE IF EXISTS historical_currency_rates;
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_currencies_char_code;
+DROP TABLE IF EXISTS currencies;
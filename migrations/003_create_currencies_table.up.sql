@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS currencies (
+    cbr_id      VARCHAR(16) PRIMARY KEY,
+    char_code   VARCHAR(3)  NOT NULL,
+    num_code    VARCHAR(3),
+    name        TEXT        NOT NULL,
+    eng_name    TEXT        NOT NULL,
+    nominal     INTEGER     NOT NULL CHECK (nominal > 0),
+    parent_code VARCHAR(16) NOT NULL,
+    monthly     BOOLEAN     NOT NULL DEFAULT FALSE,
+    updated_at  TIMESTAMP   NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_currencies_char_code ON currencies(char_code);
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_backfill_jobs_status;
+DROP TABLE IF EXISTS backfill_jobs;
@@ -0,0 +1,16 @@
+CREATE TABLE IF NOT EXISTS backfill_jobs (
+    id          BIGSERIAL   PRIMARY KEY,
+    date_from   DATE        NOT NULL,
+    date_to     DATE        NOT NULL CHECK (date_to >= date_from),
+    char_codes  TEXT[]      NOT NULL DEFAULT '{}',
+    status      VARCHAR(16) NOT NULL,
+    next_date   DATE        NOT NULL,
+    days_total  INTEGER     NOT NULL CHECK (days_total > 0),
+    days_done   INTEGER     NOT NULL DEFAULT 0,
+    error       TEXT        NOT NULL DEFAULT '',
+    created_at  TIMESTAMP   NOT NULL,
+    updated_at  TIMESTAMP   NOT NULL,
+    finished_at TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_backfill_jobs_status ON backfill_jobs(status);
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_metal_prices_date;
+DROP TABLE IF EXISTS metal_prices;
@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS metal_prices (
+    code        VARCHAR(2)     NOT NULL,
+    date        DATE           NOT NULL,
+    buy         NUMERIC(20, 4) NOT NULL CHECK (buy >= 0),
+    sell        NUMERIC(20, 4) NOT NULL CHECK (sell >= 0),
+    updated_at  TIMESTAMP      NOT NULL,
+    PRIMARY KEY (code, date)
+);
+
+CREATE INDEX IF NOT EXISTS idx_metal_prices_date ON metal_prices(date);
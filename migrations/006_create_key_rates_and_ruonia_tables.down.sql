@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS ruonia_rates;
+DROP TABLE IF EXISTS key_rates;
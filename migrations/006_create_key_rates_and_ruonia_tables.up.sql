@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS key_rates (
+    date        DATE          PRIMARY KEY,
+    rate        NUMERIC(8, 4) NOT NULL CHECK (rate >= 0),
+    updated_at  TIMESTAMP     NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS ruonia_rates (
+    date        DATE           PRIMARY KEY,
+    rate        NUMERIC(8, 4)  NOT NULL CHECK (rate >= 0),
+    volume      NUMERIC(20, 2) NOT NULL DEFAULT 0 CHECK (volume >= 0),
+    updated_at  TIMESTAMP      NOT NULL
+);
@@ -0,0 +1,10 @@
+DELETE FROM historical_currency_rates WHERE provider <> 'cbr';
+ALTER TABLE historical_currency_rates DROP CONSTRAINT IF EXISTS historical_currency_rates_pkey;
+ALTER TABLE historical_currency_rates ADD PRIMARY KEY (char_code, date);
+ALTER TABLE historical_currency_rates DROP COLUMN IF EXISTS provider;
+
+DELETE FROM currency_rates WHERE provider <> 'cbr';
+ALTER TABLE currency_rates DROP CONSTRAINT IF EXISTS currency_rates_pkey;
+ALTER TABLE currency_rates ADD PRIMARY KEY (char_code);
+ALTER TABLE currency_rates DROP COLUMN IF EXISTS provider;
+CREATE UNIQUE INDEX IF NOT EXISTS uniq_currency_char_code ON currency_rates(char_code);
@@ -0,0 +1,8 @@
+ALTER TABLE currency_rates ADD COLUMN IF NOT EXISTS provider VARCHAR(16) NOT NULL DEFAULT 'cbr';
+DROP INDEX IF EXISTS uniq_currency_char_code;
+ALTER TABLE currency_rates DROP CONSTRAINT IF EXISTS currency_rates_pkey;
+ALTER TABLE currency_rates ADD PRIMARY KEY (provider, char_code);
+
+ALTER TABLE historical_currency_rates ADD COLUMN IF NOT EXISTS provider VARCHAR(16) NOT NULL DEFAULT 'cbr';
+ALTER TABLE historical_currency_rates DROP CONSTRAINT IF EXISTS historical_currency_rates_pkey;
+ALTER TABLE historical_currency_rates ADD PRIMARY KEY (provider, char_code, date);
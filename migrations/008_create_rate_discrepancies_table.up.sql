@@ -0,0 +1,14 @@
+CREATE TABLE IF NOT EXISTS rate_discrepancies (
+    date              DATE           NOT NULL,
+    char_code         VARCHAR(3)     NOT NULL,
+    primary_source    VARCHAR(16)    NOT NULL,
+    secondary_source  VARCHAR(16)    NOT NULL,
+    base              VARCHAR(3)     NOT NULL,
+    primary_value     NUMERIC(24, 8) NOT NULL,
+    secondary_value   NUMERIC(24, 8) NOT NULL,
+    diff_percent      NUMERIC(12, 4) NOT NULL,
+    detected_at       TIMESTAMP      NOT NULL,
+    PRIMARY KEY (date, char_code, primary_source, secondary_source)
+);
+
+CREATE INDEX IF NOT EXISTS idx_rate_discrepancies_detected_at ON rate_discrepancies(detected_at);
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_historical_currency_effective_date;
+ALTER TABLE historical_currency_rates DROP COLUMN IF EXISTS effective_date;
@@ -0,0 +1,16 @@
+ALTER TABLE historical_currency_rates ADD COLUMN IF NOT EXISTS effective_date DATE;
+
+-- rows stored for days without a publication hold the last publication before them:
+-- Saturday's CBR rates on Sunday and Monday, Friday's ECB rates over the weekend
+UPDATE historical_currency_rates
+SET effective_date = CASE
+    WHEN provider IN ('cbr', 'cbr_mirror') AND EXTRACT(ISODOW FROM date) = 7 THEN date - 1
+    WHEN provider IN ('cbr', 'cbr_mirror') AND EXTRACT(ISODOW FROM date) = 1 THEN date - 2
+    WHEN provider = 'ecb' AND EXTRACT(ISODOW FROM date) = 6 THEN date - 1
+    WHEN provider = 'ecb' AND EXTRACT(ISODOW FROM date) = 7 THEN date - 2
+    ELSE date
+END
+WHERE effective_date IS NULL;
+
+ALTER TABLE historical_currency_rates ALTER COLUMN effective_date SET NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_historical_currency_effective_date ON historical_currency_rates(provider, char_code, effective_date);
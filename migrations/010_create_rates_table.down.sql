@@ -0,0 +1,47 @@
+CREATE TABLE IF NOT EXISTS currency_rates (
+    provider    VARCHAR(16) NOT NULL DEFAULT 'cbr',
+    char_code   VARCHAR(3)  NOT NULL,
+    name        TEXT        NOT NULL,
+    nominal     INTEGER     NOT NULL CHECK (nominal > 0),
+    value       NUMERIC(20, 4) NOT NULL CHECK (value >= 0),
+    num_code    VARCHAR(3),
+    updated_at  TIMESTAMP   NOT NULL,
+    PRIMARY KEY (provider, char_code)
+);
+
+CREATE TABLE IF NOT EXISTS historical_currency_rates (
+    provider       VARCHAR(16) NOT NULL DEFAULT 'cbr',
+    char_code      VARCHAR(3)  NOT NULL,
+    date           DATE        NOT NULL,
+    effective_date DATE        NOT NULL,
+    name           TEXT        NOT NULL,
+    nominal        INTEGER     NOT NULL CHECK (nominal > 0),
+    value          NUMERIC(20, 4) NOT NULL CHECK (value >= 0),
+    num_code       VARCHAR(3),
+    PRIMARY KEY (provider, char_code, date)
+);
+
+CREATE INDEX IF NOT EXISTS idx_historical_currency_date ON historical_currency_rates(date);
+CREATE INDEX IF NOT EXISTS idx_historical_currency_char_code ON historical_currency_rates(char_code);
+CREATE INDEX IF NOT EXISTS idx_historical_currency_effective_date ON historical_currency_rates(provider, char_code, effective_date);
+
+-- every day up to the next publication repeats it, as the old table stored
+-- weekends and holidays; longer gaps are missing history and stay missing
+INSERT INTO historical_currency_rates (provider, char_code, date, effective_date, name, nominal, value, num_code)
+SELECT r.source, r.char_code, d::date, r.effective_date, r.name, r.nominal, r.value, r.num_code
+FROM (
+    SELECT *, LEAD(effective_date) OVER (PARTITION BY source, char_code ORDER BY effective_date) AS next_date
+    FROM rates
+) r
+CROSS JOIN LATERAL generate_series(
+    r.effective_date,
+    CASE WHEN r.next_date - r.effective_date <= 14 THEN r.next_date - 1 ELSE r.effective_date END,
+    INTERVAL '1 day'
+) AS d;
+
+INSERT INTO currency_rates (provider, char_code, name, nominal, value, num_code, updated_at)
+SELECT source, char_code, name, nominal, value, num_code, fetched_at
+FROM latest_rates;
+
+DROP TABLE IF EXISTS latest_rates;
+DROP TABLE IF EXISTS rates;
@@ -0,0 +1,58 @@
+CREATE TABLE IF NOT EXISTS rates (
+    source         VARCHAR(16)    NOT NULL,
+    char_code      VARCHAR(3)     NOT NULL,
+    effective_date DATE           NOT NULL,
+    name           TEXT           NOT NULL,
+    nominal        INTEGER        NOT NULL CHECK (nominal > 0),
+    value          NUMERIC(20, 4) NOT NULL CHECK (value >= 0),
+    num_code       VARCHAR(3),
+    fetched_at     TIMESTAMP      NOT NULL,
+    -- SHA-256 of the upstream response; NULL for rows moved from the old tables
+    payload_hash   CHAR(64),
+    PRIMARY KEY (source, char_code, effective_date)
+);
+
+CREATE INDEX IF NOT EXISTS idx_rates_effective_date ON rates(effective_date);
+
+-- one row per publication: weekend rows of historical_currency_rates repeat it
+INSERT INTO rates (source, char_code, effective_date, name, nominal, value, num_code, fetched_at)
+SELECT DISTINCT ON (provider, char_code, effective_date)
+    provider, char_code, effective_date, name, nominal, value, num_code, NOW()
+FROM historical_currency_rates
+ORDER BY provider, char_code, effective_date, (date = effective_date) DESC
+ON CONFLICT DO NOTHING;
+
+-- currency_rates has no publication date, so its rows are kept, dated by
+-- their update, only for currencies that have no history at all
+INSERT INTO rates (source, char_code, effective_date, name, nominal, value, num_code, fetched_at)
+SELECT c.provider, c.char_code, c.updated_at::date, c.name, c.nominal, c.value, c.num_code, c.updated_at
+FROM currency_rates c
+WHERE NOT EXISTS (
+    SELECT 1 FROM rates r WHERE r.source = c.provider AND r.char_code = c.char_code
+)
+ON CONFLICT DO NOTHING;
+
+-- a table rather than a materialized view: StoreRates keeps it current in the
+-- write transaction, where a view would be refreshed in full on every store
+CREATE TABLE IF NOT EXISTS latest_rates (
+    source         VARCHAR(16)    NOT NULL,
+    char_code      VARCHAR(3)     NOT NULL,
+    effective_date DATE           NOT NULL,
+    name           TEXT           NOT NULL,
+    nominal        INTEGER        NOT NULL CHECK (nominal > 0),
+    value          NUMERIC(20, 4) NOT NULL CHECK (value >= 0),
+    num_code       VARCHAR(3),
+    fetched_at     TIMESTAMP      NOT NULL,
+    payload_hash   CHAR(64),
+    PRIMARY KEY (source, char_code)
+);
+
+INSERT INTO latest_rates (source, char_code, effective_date, name, nominal, value, num_code, fetched_at, payload_hash)
+SELECT DISTINCT ON (source, char_code)
+    source, char_code, effective_date, name, nominal, value, num_code, fetched_at, payload_hash
+FROM rates
+ORDER BY source, char_code, effective_date DESC
+ON CONFLICT DO NOTHING;
+
+DROP TABLE IF EXISTS currency_rates;
+DROP TABLE IF EXISTS historical_currency_rates;
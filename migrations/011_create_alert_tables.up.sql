@@ -0,0 +1,49 @@
+CREATE TABLE IF NOT EXISTS alert_rules (
+    id             BIGSERIAL      PRIMARY KEY,
+    source         VARCHAR(16)    NOT NULL,
+    char_code      VARCHAR(3)     NOT NULL,
+    threshold_type VARCHAR(16)    NOT NULL CHECK (threshold_type IN ('percent', 'absolute')),
+    threshold      NUMERIC(20, 4) NOT NULL CHECK (threshold > 0),
+    direction      VARCHAR(8)     NOT NULL CHECK (direction IN ('up', 'down', 'both')),
+    webhook_url    TEXT           NOT NULL,
+    secret         TEXT           NOT NULL,
+    enabled        BOOLEAN        NOT NULL DEFAULT TRUE,
+    created_at     TIMESTAMP      NOT NULL,
+    updated_at     TIMESTAMP      NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_alert_rules_source ON alert_rules(source) WHERE enabled;
+
+-- rule_id has no foreign key: the history outlives deleted rules
+CREATE TABLE IF NOT EXISTS alerts (
+    id             BIGSERIAL      PRIMARY KEY,
+    rule_id        BIGINT         NOT NULL,
+    source         VARCHAR(16)    NOT NULL,
+    char_code      VARCHAR(3)     NOT NULL,
+    date           DATE           NOT NULL,
+    previous_date  DATE           NOT NULL,
+    previous_value NUMERIC(24, 8) NOT NULL,
+    value          NUMERIC(24, 8) NOT NULL,
+    change         NUMERIC(24, 8) NOT NULL,
+    change_percent NUMERIC(12, 4) NOT NULL,
+    status         VARCHAR(16)    NOT NULL,
+    attempts       INTEGER        NOT NULL DEFAULT 0,
+    last_error     TEXT           NOT NULL DEFAULT '',
+    triggered_at   TIMESTAMP      NOT NULL,
+    delivered_at   TIMESTAMP,
+    UNIQUE (rule_id, date)
+);
+
+CREATE INDEX IF NOT EXISTS idx_alerts_date ON alerts(date);
+
+CREATE TABLE IF NOT EXISTS webhook_dead_letters (
+    id          BIGSERIAL PRIMARY KEY,
+    alert_id    BIGINT    NOT NULL REFERENCES alerts(id) ON DELETE CASCADE,
+    url         TEXT      NOT NULL,
+    payload     JSONB     NOT NULL,
+    attempts    INTEGER   NOT NULL,
+    last_error  TEXT      NOT NULL,
+    created_at  TIMESTAMP NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_webhook_dead_letters_created_at ON webhook_dead_letters(created_at);
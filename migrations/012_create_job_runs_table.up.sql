@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS job_runs (
+    id           BIGSERIAL   PRIMARY KEY,
+    job          VARCHAR(64) NOT NULL,
+    triggered_by VARCHAR(16) NOT NULL,
+    instance     TEXT        NOT NULL DEFAULT '',
+    status       VARCHAR(16) NOT NULL,
+    attempts     INTEGER     NOT NULL DEFAULT 0,
+    error        TEXT        NOT NULL DEFAULT '',
+    started_at   TIMESTAMP   NOT NULL,
+    finished_at  TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_job_runs_job_started_at ON job_runs(job, started_at DESC);
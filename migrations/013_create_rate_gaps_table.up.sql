@@ -0,0 +1,8 @@
+-- days a range answer of the source had no rate for, so they are not asked again
+CREATE TABLE IF NOT EXISTS rate_gaps (
+    source     VARCHAR(16) NOT NULL,
+    char_code  VARCHAR(3)  NOT NULL,
+    date       DATE        NOT NULL,
+    checked_at TIMESTAMP   NOT NULL,
+    PRIMARY KEY (source, char_code, date)
+);
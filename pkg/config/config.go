@@ -2,6 +2,7 @@ package config
 
 import (
 	"strings"
+	"time"
 
 	"github.com/spf13/viper"
 )
@@ -14,6 +15,8 @@ type Config struct {
 
 	Log struct {
 		Level string `mapstructure:"level"`
+		// Format is "text" or "json".
+		Format string `mapstructure:"format"`
 	} `mapstructure:"log"`
 
 	Postgres struct {
@@ -23,7 +26,148 @@ type Config struct {
 		User     string `mapstructure:"user"`
 		Password string `mapstructure:"password"`
 		SSLMode  string `mapstructure:"sslmode"`
+		// AutoMigrate applies pending migrations on startup; when off, startup
+		// only checks that the schema is not newer than the binary.
+		AutoMigrate bool `mapstructure:"auto_migrate"`
 	} `mapstructure:"postgres"`
+
+	Admin struct {
+		// Token is the bearer token required by /admin endpoints; they reject
+		// every request while it is empty.
+		Token string `mapstructure:"token"`
+	} `mapstructure:"admin"`
+
+	Conversion struct {
+		Scale     int32  `mapstructure:"scale"`
+		RateScale int32  `mapstructure:"rate_scale"`
+		Rounding  string `mapstructure:"rounding"`
+	} `mapstructure:"conversion"`
+
+	Backfill struct {
+		ChunkDays         int     `mapstructure:"chunk_days"`
+		Concurrency       int     `mapstructure:"concurrency"`
+		RequestsPerSecond float64 `mapstructure:"requests_per_second"`
+	} `mapstructure:"backfill"`
+
+	CBR struct {
+		BaseURL               string        `mapstructure:"base_url"`
+		DailyInfoURL          string        `mapstructure:"daily_info_url"`
+		Timeout               time.Duration `mapstructure:"timeout"`
+		ConnectTimeout        time.Duration `mapstructure:"connect_timeout"`
+		ResponseHeaderTimeout time.Duration `mapstructure:"response_header_timeout"`
+		UserAgent             string        `mapstructure:"user_agent"`
+		Proxy                 string        `mapstructure:"proxy"`
+		CABundle              string        `mapstructure:"ca_bundle"`
+
+		Retry struct {
+			MaxAttempts int           `mapstructure:"max_attempts"`
+			BaseDelay   time.Duration `mapstructure:"base_delay"`
+			MaxDelay    time.Duration `mapstructure:"max_delay"`
+		} `mapstructure:"retry"`
+		CircuitBreaker struct {
+			FailureThreshold int           `mapstructure:"failure_threshold"`
+			OpenTimeout      time.Duration `mapstructure:"open_timeout"`
+		} `mapstructure:"circuit_breaker"`
+		RateLimit struct {
+			RequestsPerSecond float64 `mapstructure:"requests_per_second"`
+			Burst             int     `mapstructure:"burst"`
+		} `mapstructure:"rate_limit"`
+		Mirror struct {
+			BaseURL      string `mapstructure:"base_url"`
+			DailyInfoURL string `mapstructure:"daily_info_url"`
+		} `mapstructure:"mirror"`
+	} `mapstructure:"cbr"`
+
+	ECB struct {
+		Enabled   bool          `mapstructure:"enabled"`
+		BaseURL   string        `mapstructure:"base_url"`
+		Timeout   time.Duration `mapstructure:"timeout"`
+		UserAgent string        `mapstructure:"user_agent"`
+	} `mapstructure:"ecb"`
+
+	NBK struct {
+		Enabled   bool          `mapstructure:"enabled"`
+		BaseURL   string        `mapstructure:"base_url"`
+		Timeout   time.Duration `mapstructure:"timeout"`
+		UserAgent string        `mapstructure:"user_agent"`
+
+		Retry struct {
+			MaxAttempts int           `mapstructure:"max_attempts"`
+			BaseDelay   time.Duration `mapstructure:"base_delay"`
+			MaxDelay    time.Duration `mapstructure:"max_delay"`
+		} `mapstructure:"retry"`
+		RateLimit struct {
+			RequestsPerSecond float64 `mapstructure:"requests_per_second"`
+			Burst             int     `mapstructure:"burst"`
+		} `mapstructure:"rate_limit"`
+	} `mapstructure:"nbk"`
+
+	// Fallback maps a source to the sources asked in order when it is unavailable.
+	Fallback map[string][]string `mapstructure:"fallback"`
+
+	Reconciliation struct {
+		Enabled          bool    `mapstructure:"enabled"`
+		Primary          string  `mapstructure:"primary"`
+		Secondary        string  `mapstructure:"secondary"`
+		ThresholdPercent float64 `mapstructure:"threshold_percent"`
+	} `mapstructure:"reconciliation"`
+
+	Alerts struct {
+		Enabled bool `mapstructure:"enabled"`
+
+		Webhook struct {
+			Timeout     time.Duration `mapstructure:"timeout"`
+			MaxAttempts int           `mapstructure:"max_attempts"`
+			BaseDelay   time.Duration `mapstructure:"base_delay"`
+			MaxDelay    time.Duration `mapstructure:"max_delay"`
+			UserAgent   string        `mapstructure:"user_agent"`
+			// AllowedNetworks are internal CIDRs webhooks may point to; other
+			// loopback, private and link-local addresses are rejected.
+			AllowedNetworks []string `mapstructure:"allowed_networks"`
+		} `mapstructure:"webhook"`
+	} `mapstructure:"alerts"`
+
+	Metrics struct {
+		Enabled bool `mapstructure:"enabled"`
+	} `mapstructure:"metrics"`
+
+	Tracing struct {
+		Enabled     bool    `mapstructure:"enabled"`
+		ServiceName string  `mapstructure:"service_name"`
+		Endpoint    string  `mapstructure:"endpoint"`
+		Insecure    bool    `mapstructure:"insecure"`
+		SampleRatio float64 `mapstructure:"sample_ratio"`
+	} `mapstructure:"tracing"`
+
+	Health struct {
+		// MaxRateLag is how far the newest CBR rate may trail the expected
+		// publication before the service reports itself not ready.
+		MaxRateLag time.Duration `mapstructure:"max_rate_lag"`
+	} `mapstructure:"health"`
+
+	Scheduler struct {
+		// Timezone is the IANA zone the job schedules are read in.
+		Timezone string `mapstructure:"timezone"`
+		// LeaderCheckInterval is how often replicas try to take over the
+		// scheduler lock; only its holder runs jobs on schedule.
+		LeaderCheckInterval time.Duration `mapstructure:"leader_check_interval"`
+
+		Retry struct {
+			MaxAttempts int           `mapstructure:"max_attempts"`
+			BaseDelay   time.Duration `mapstructure:"base_delay"`
+			MaxDelay    time.Duration `mapstructure:"max_delay"`
+		} `mapstructure:"retry"`
+
+		Jobs map[string]JobConfig `mapstructure:"jobs"`
+	} `mapstructure:"scheduler"`
+}
+
+type JobConfig struct {
+	Enabled bool `mapstructure:"enabled"`
+	// Schedule is a five-field cron expression; empty means the job runs
+	// only on start or when triggered.
+	Schedule   string `mapstructure:"schedule"`
+	RunOnStart bool   `mapstructure:"run_on_start"`
 }
 
 func LoadConfig() (*Config, error) {
@@ -39,6 +183,66 @@ func LoadConfig() (*Config, error) {
 	v.AutomaticEnv()
 	v.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
 
+	v.SetDefault("log.format", "text")
+	v.SetDefault("postgres.auto_migrate", true)
+	v.SetDefault("admin.token", "")
+	v.SetDefault("conversion.scale", 4)
+	v.SetDefault("conversion.rate_scale", 6)
+	v.SetDefault("conversion.rounding", "half_up")
+	v.SetDefault("backfill.chunk_days", 31)
+	v.SetDefault("backfill.concurrency", 4)
+	v.SetDefault("backfill.requests_per_second", 5)
+	v.SetDefault("cbr.base_url", "https://www.cbr.ru/scripts")
+	v.SetDefault("cbr.daily_info_url", "https://www.cbr.ru/DailyInfoWebServ/DailyInfo.asmx")
+	v.SetDefault("cbr.timeout", "30s")
+	v.SetDefault("cbr.connect_timeout", "10s")
+	v.SetDefault("cbr.response_header_timeout", "30s")
+	v.SetDefault("cbr.retry.max_attempts", 4)
+	v.SetDefault("cbr.retry.base_delay", "500ms")
+	v.SetDefault("cbr.retry.max_delay", "10s")
+	v.SetDefault("cbr.circuit_breaker.failure_threshold", 5)
+	v.SetDefault("cbr.circuit_breaker.open_timeout", "30s")
+	v.SetDefault("cbr.rate_limit.requests_per_second", 5)
+	v.SetDefault("cbr.rate_limit.burst", 1)
+	v.SetDefault("ecb.enabled", true)
+	v.SetDefault("ecb.base_url", "https://www.ecb.europa.eu/stats/eurofxref")
+	v.SetDefault("ecb.timeout", "60s")
+	v.SetDefault("nbk.enabled", true)
+	v.SetDefault("nbk.base_url", "https://nationalbank.kz/rss")
+	v.SetDefault("nbk.timeout", "30s")
+	v.SetDefault("nbk.retry.max_attempts", 3)
+	v.SetDefault("nbk.retry.base_delay", "500ms")
+	v.SetDefault("nbk.retry.max_delay", "5s")
+	v.SetDefault("nbk.rate_limit.requests_per_second", 10)
+	v.SetDefault("nbk.rate_limit.burst", 1)
+	v.SetDefault("reconciliation.primary", "cbr")
+	v.SetDefault("reconciliation.secondary", "ecb")
+	v.SetDefault("reconciliation.threshold_percent", 1.0)
+	v.SetDefault("alerts.enabled", true)
+	v.SetDefault("alerts.webhook.timeout", "10s")
+	v.SetDefault("alerts.webhook.max_attempts", 5)
+	v.SetDefault("alerts.webhook.base_delay", "1s")
+	v.SetDefault("alerts.webhook.max_delay", "1m")
+	v.SetDefault("alerts.webhook.allowed_networks", []string{})
+	v.SetDefault("metrics.enabled", true)
+	v.SetDefault("tracing.enabled", false)
+	v.SetDefault("tracing.service_name", "RnD-service")
+	v.SetDefault("tracing.insecure", true)
+	v.SetDefault("tracing.sample_ratio", 1.0)
+	v.SetDefault("health.max_rate_lag", "72h")
+	v.SetDefault("scheduler.timezone", "Europe/Moscow")
+	v.SetDefault("scheduler.leader_check_interval", "15s")
+	v.SetDefault("scheduler.retry.max_attempts", 3)
+	v.SetDefault("scheduler.retry.base_delay", "1m")
+	v.SetDefault("scheduler.retry.max_delay", "10m")
+	setJobDefaults(v, "cbr_rates", "0 10 * * *", true)
+	setJobDefaults(v, "cbr_catalog", "0 10 * * *", true)
+	setJobDefaults(v, "cbr_metals", "0 10 * * *", true)
+	setJobDefaults(v, "cbr_indicators", "0 10 * * *", true)
+	setJobDefaults(v, "ecb_rates", "30 18 * * 1-5", true)
+	setJobDefaults(v, "nbk_rates", "0 16 * * 1-5", true)
+	setJobDefaults(v, "reconcile", "0 19 * * 1-5", false)
+
 	if err := v.ReadInConfig(); err != nil {
 		return nil, err
 	}
@@ -50,3 +254,9 @@ func LoadConfig() (*Config, error) {
 
 	return &cfg, nil
 }
+
+func setJobDefaults(v *viper.Viper, name, schedule string, runOnStart bool) {
+	v.SetDefault("scheduler.jobs."+name+".enabled", true)
+	v.SetDefault("scheduler.jobs."+name+".schedule", schedule)
+	v.SetDefault("scheduler.jobs."+name+".run_on_start", runOnStart)
+}
@@ -0,0 +1,150 @@
+package fakecbr
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// KeyRateDecision is a key rate change effective from Date.
+type KeyRateDecision struct {
+	Date time.Time
+	Rate decimal.Decimal
+}
+
+var DefaultKeyRateDecisions = []KeyRateDecision{
+	{Date: time.Date(2022, 9, 19, 0, 0, 0, 0, time.UTC), Rate: decimal.RequireFromString("7.50")},
+	{Date: time.Date(2023, 7, 24, 0, 0, 0, 0, time.UTC), Rate: decimal.RequireFromString("8.50")},
+	{Date: time.Date(2023, 8, 15, 0, 0, 0, 0, time.UTC), Rate: decimal.RequireFromString("12.00")},
+	{Date: time.Date(2023, 9, 18, 0, 0, 0, 0, time.UTC), Rate: decimal.RequireFromString("13.00")},
+	{Date: time.Date(2023, 10, 30, 0, 0, 0, 0, time.UTC), Rate: decimal.RequireFromString("15.00")},
+	{Date: time.Date(2023, 12, 18, 0, 0, 0, 0, time.UTC), Rate: decimal.RequireFromString("16.00")},
+	{Date: time.Date(2024, 7, 29, 0, 0, 0, 0, time.UTC), Rate: decimal.RequireFromString("18.00")},
+	{Date: time.Date(2024, 10, 28, 0, 0, 0, 0, time.UTC), Rate: decimal.RequireFromString("21.00")},
+	{Date: time.Date(2025, 6, 9, 0, 0, 0, 0, time.UTC), Rate: decimal.RequireFromString("20.00")},
+	{Date: time.Date(2025, 7, 28, 0, 0, 0, 0, time.UTC), Rate: decimal.RequireFromString("18.00")},
+}
+
+const (
+	soapResponseHeader = `<?xml version="1.0" encoding="utf-8"?><soap:Envelope xmlns:soap="http://schemas.xmlsoap.org/soap/envelope/" xmlns:xsi="http://www.w3.org/2001/XMLSchema-instance" xmlns:xsd="http://www.w3.org/2001/XMLSchema"><soap:Body>`
+	soapResponseFooter = `</soap:Body></soap:Envelope>`
+	diffgramHeader     = `<diffgr:diffgram xmlns:msdata="urn:schemas-microsoft-com:xml-msdata" xmlns:diffgr="urn:schemas-microsoft-com:xml-diffgram-v1">`
+	diffgramFooter     = `</diffgr:diffgram>`
+)
+
+// dailyInfoRequest matches a DailyInfo call with a fromDate/ToDate pair,
+// whatever the method element is called.
+type dailyInfoRequest struct {
+	Body struct {
+		Call struct {
+			XMLName  xml.Name
+			FromDate string `xml:"fromDate"`
+			ToDate   string `xml:"ToDate"`
+		} `xml:",any"`
+	} `xml:"Body"`
+}
+
+// dailyInfo serves KeyRate and Ruonia from the DailyInfo SOAP service. Rows
+// exist only for Monday to Friday and come newest first, like the real service.
+func (s *Server) dailyInfo(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		s.writeSOAPFault(w, "soap:Client", "Only SOAP POST requests are supported")
+		return
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		s.writeSOAPFault(w, "soap:Client", "Unable to read request")
+		return
+	}
+	var req dailyInfoRequest
+	if err := xml.Unmarshal(body, &req); err != nil {
+		s.writeSOAPFault(w, "soap:Client", "Server was unable to read request. ---> "+err.Error())
+		return
+	}
+
+	from, errFrom := time.Parse("2006-01-02T15:04:05", req.Body.Call.FromDate)
+	to, errTo := time.Parse("2006-01-02T15:04:05", req.Body.Call.ToDate)
+	if errFrom != nil || errTo != nil {
+		s.writeSOAPFault(w, "soap:Client", "Server was unable to read request. ---> The string was not recognized as a valid DateTime.")
+		return
+	}
+	if latest := s.today(); to.After(latest) {
+		to = latest
+	}
+
+	var rows strings.Builder
+	method := req.Body.Call.XMLName.Local
+	switch method {
+	case "KeyRate":
+		rows.WriteString(`<KeyRate xmlns="">`)
+		for d := to; !d.Before(from); d = d.AddDate(0, 0, -1) {
+			if !isBusinessDay(d) {
+				continue
+			}
+			fmt.Fprintf(&rows, `<KR><DT>%s</DT><Rate>%s</Rate></KR>`, dailyInfoDate(d), s.keyRateOn(d).StringFixed(2))
+		}
+		rows.WriteString(`</KeyRate>`)
+	case "Ruonia":
+		// RUONIA for a day is published on the next business day
+		if latest := s.today().AddDate(0, 0, -1); to.After(latest) {
+			to = latest
+		}
+		rows.WriteString(`<Ruonia xmlns="">`)
+		for d := to; !d.Before(from); d = d.AddDate(0, 0, -1) {
+			if !isBusinessDay(d) {
+				continue
+			}
+			fmt.Fprintf(&rows, `<ro><D0>%s</D0><ruo>%s</ruo><vol>%s</vol><DateUpdate>%s</DateUpdate></ro>`,
+				dailyInfoDate(d), s.ruoniaOn(d).StringFixed(2), oscillate("ruonia-vol", decimal.NewFromInt(500), d).StringFixed(2), dailyInfoDate(d.AddDate(0, 0, 1)))
+		}
+		rows.WriteString(`</Ruonia>`)
+	default:
+		s.writeSOAPFault(w, "soap:Client", fmt.Sprintf("Server did not recognize the value of HTTP Header SOAPAction: http://web.cbr.ru/%s.", method))
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/xml; charset=utf-8")
+	fmt.Fprintf(w, "%s<%sResponse xmlns=\"http://web.cbr.ru/\"><%sResult>%s%s%s</%sResult></%sResponse>%s",
+		soapResponseHeader, method, method, diffgramHeader, rows.String(), diffgramFooter, method, method, soapResponseFooter)
+}
+
+func (s *Server) writeSOAPFault(w http.ResponseWriter, code, message string) {
+	var escaped strings.Builder
+	xml.EscapeText(&escaped, []byte(message))
+
+	w.Header().Set("Content-Type", "text/xml; charset=utf-8")
+	w.WriteHeader(http.StatusInternalServerError)
+	fmt.Fprintf(w, "%s<soap:Fault><faultcode>%s</faultcode><faultstring>%s</faultstring><detail /></soap:Fault>%s",
+		soapResponseHeader, code, escaped.String(), soapResponseFooter)
+}
+
+func (s *Server) keyRateOn(date time.Time) decimal.Decimal {
+	rate := decimal.Zero
+	for _, decision := range s.cfg.KeyRateDecisions {
+		if decision.Date.After(date) {
+			break
+		}
+		rate = decision.Rate
+	}
+	return rate
+}
+
+// ruoniaOn keeps RUONIA a little below the key rate, as it usually is.
+func (s *Server) ruoniaOn(date time.Time) decimal.Decimal {
+	spread := oscillate("ruonia", decimal.RequireFromString("0.2"), date)
+	return s.keyRateOn(date).Sub(spread)
+}
+
+func isBusinessDay(date time.Time) bool {
+	return date.Weekday() != time.Saturday && date.Weekday() != time.Sunday
+}
+
+func dailyInfoDate(date time.Time) string {
+	return date.Format("2006-01-02") + "T00:00:00+03:00"
+}
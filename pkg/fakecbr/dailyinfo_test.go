@@ -0,0 +1,61 @@
+package fakecbr
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestServer_KeyRate(t *testing.T) {
+	server, client := setupFakeCBR(t, Config{})
+
+	// Friday 25 July to Friday 1 August 2025, across the 28 July decision.
+	resp, err := client.FetchKeyRate(context.Background(), time.Date(2025, 7, 25, 0, 0, 0, 0, time.UTC), time.Date(2025, 8, 10, 0, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+
+	records := resp.Records()
+	require.Len(t, records, 6, "business days only, nothing after today")
+	assert.Equal(t, "2025-08-01T00:00:00+03:00", records[0].Date, "newest first")
+	assert.Equal(t, "18.00", records[0].Rate)
+	assert.Equal(t, "2025-07-25T00:00:00+03:00", records[5].Date)
+	assert.Equal(t, "20.00", records[5].Rate)
+	assert.Equal(t, 1, server.Requests("DailyInfo.asmx"))
+}
+
+func TestServer_Ruonia(t *testing.T) {
+	_, client := setupFakeCBR(t, Config{})
+
+	resp, err := client.FetchRuonia(context.Background(), time.Date(2025, 7, 28, 0, 0, 0, 0, time.UTC), time.Date(2025, 8, 1, 0, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+
+	records := resp.Records()
+	require.Len(t, records, 4, "today's RUONIA is not published yet")
+	assert.Equal(t, "2025-07-31T00:00:00+03:00", records[0].Date)
+	rate, err := records[0].GetRate()
+	require.NoError(t, err)
+	assert.True(t, rate.LessThan(decimal.RequireFromString("18")), "RUONIA stays below the key rate")
+	volume, err := records[0].GetVolume()
+	require.NoError(t, err)
+	assert.True(t, volume.IsPositive())
+}
+
+func TestServer_DailyInfoFault(t *testing.T) {
+	server, client := setupFakeCBR(t, Config{})
+
+	rec := httptest.NewRecorder()
+	server.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/DailyInfoWebServ/DailyInfo.asmx", strings.NewReader(
+		`<soap:Envelope xmlns:soap="http://schemas.xmlsoap.org/soap/envelope/"><soap:Body><GetCursOnDate xmlns="http://web.cbr.ru/"><fromDate>2025-07-28T00:00:00</fromDate><ToDate>2025-07-28T00:00:00</ToDate></GetCursOnDate></soap:Body></soap:Envelope>`)))
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+	assert.Contains(t, rec.Body.String(), "<faultcode>soap:Client</faultcode>")
+
+	server.FailNext(1, http.StatusBadGateway)
+	_, err := client.FetchKeyRate(context.Background(), time.Date(2025, 7, 28, 0, 0, 0, 0, time.UTC), time.Date(2025, 7, 28, 0, 0, 0, 0, time.UTC))
+	require.NoError(t, err, "client retries through injected failures")
+}
@@ -0,0 +1,69 @@
+package fakecbr
+
+import (
+	"bytes"
+	"embed"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"io/fs"
+	"path"
+	"strings"
+	"time"
+
+	"RnD-service/internal/adapter/cbr"
+
+	"golang.org/x/text/encoding/charmap"
+)
+
+//go:embed fixtures
+var embeddedFixtures embed.FS
+
+// DefaultFixtures holds USD and EUR rates for 10-12 January 2023, used by the e2e suite.
+var DefaultFixtures = func() fs.FS {
+	sub, err := fs.Sub(embeddedFixtures, "fixtures")
+	if err != nil {
+		panic(err)
+	}
+	return sub
+}()
+
+func loadFixtures(fsys fs.FS) (map[string]*cbr.ValCurs, error) {
+	fixtures := make(map[string]*cbr.ValCurs)
+	if fsys == nil {
+		return fixtures, nil
+	}
+
+	files, err := fs.Glob(fsys, "daily/*.xml")
+	if err != nil {
+		return nil, err
+	}
+
+	for _, name := range files {
+		key := strings.TrimSuffix(path.Base(name), ".xml")
+		if _, err := time.Parse("2006-01-02", key); err != nil {
+			return nil, fmt.Errorf("fixture %s: file name must be YYYY-MM-DD.xml", name)
+		}
+
+		data, err := fs.ReadFile(fsys, name)
+		if err != nil {
+			return nil, err
+		}
+
+		var valCurs cbr.ValCurs
+		decoder := xml.NewDecoder(bytes.NewReader(data))
+		decoder.CharsetReader = func(charset string, input io.Reader) (io.Reader, error) {
+			if strings.EqualFold(charset, "windows-1251") {
+				return charmap.Windows1251.NewDecoder().Reader(input), nil
+			}
+			return nil, fmt.Errorf("unsupported charset: %s", charset)
+		}
+		if err := decoder.Decode(&valCurs); err != nil {
+			return nil, fmt.Errorf("fixture %s: %w", name, err)
+		}
+		valCurs.Message = strings.TrimSpace(valCurs.Message)
+		fixtures[key] = &valCurs
+	}
+
+	return fixtures, nil
+}
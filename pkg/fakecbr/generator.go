@@ -0,0 +1,158 @@
+package fakecbr
+
+import (
+	"fmt"
+	"hash/fnv"
+	"math"
+	"strings"
+	"time"
+
+	"RnD-service/internal/adapter/cbr"
+
+	"github.com/shopspring/decimal"
+)
+
+type Currency struct {
+	ID       string
+	NumCode  string
+	CharCode string
+	Nominal  int
+	Name     string
+	EngName  string
+	// Base is the rate per Nominal units around which generated values oscillate.
+	Base    decimal.Decimal
+	Monthly bool
+}
+
+var DefaultCurrencies = []Currency{
+	{ID: "R01010", NumCode: "036", CharCode: "AUD", Nominal: 1, Name: "Австралийский доллар", EngName: "Australian Dollar", Base: decimal.RequireFromString("52.1040")},
+	{ID: "R01060", NumCode: "051", CharCode: "AMD", Nominal: 100, Name: "Армянских драмов", EngName: "Armenian Dram", Base: decimal.RequireFromString("20.1485")},
+	{ID: "R01035", NumCode: "826", CharCode: "GBP", Nominal: 1, Name: "Фунт стерлингов Соединенного королевства", EngName: "British Pound Sterling", Base: decimal.RequireFromString("104.7310")},
+	{ID: "R01235", NumCode: "840", CharCode: "USD", Nominal: 1, Name: "Доллар США", EngName: "US Dollar", Base: decimal.RequireFromString("78.8320")},
+	{ID: "R01239", NumCode: "978", CharCode: "EUR", Nominal: 1, Name: "Евро", EngName: "Euro", Base: decimal.RequireFromString("90.3270")},
+	{ID: "R01335", NumCode: "398", CharCode: "KZT", Nominal: 100, Name: "Казахстанских тенге", EngName: "Kazakhstan Tenge", Base: decimal.RequireFromString("14.6072")},
+	{ID: "R01375", NumCode: "156", CharCode: "CNY", Nominal: 1, Name: "Юань", EngName: "China Yuan", Base: decimal.RequireFromString("10.9542")},
+	{ID: "R01700J", NumCode: "949", CharCode: "TRY", Nominal: 10, Name: "Турецких лир", EngName: "Turkish Lira", Base: decimal.RequireFromString("19.3865")},
+	{ID: "R01820", NumCode: "392", CharCode: "JPY", Nominal: 100, Name: "Японских иен", EngName: "Japanese Yen", Base: decimal.RequireFromString("53.4217")},
+	{ID: "R01080", NumCode: "068", CharCode: "BOB", Nominal: 1, Name: "Боливиано", EngName: "Boliviano", Base: decimal.RequireFromString("11.4020"), Monthly: true},
+}
+
+// Metal is a precious metal in XML_metall.asp; Code is CBR's numeric code
+// (1 gold, 2 silver, 3 platinum, 4 palladium).
+type Metal struct {
+	Code int
+	// Base is the price per gram around which generated prices oscillate.
+	Base decimal.Decimal
+}
+
+var DefaultMetals = []Metal{
+	{Code: 1, Base: decimal.RequireFromString("8563.47")},
+	{Code: 2, Base: decimal.RequireFromString("96.12")},
+	{Code: 3, Base: decimal.RequireFromString("3400.50")},
+	{Code: 4, Base: decimal.RequireFromString("2961.50")},
+}
+
+var epoch = time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// generateValue is deterministic: the same currency and date always give the
+// same value, so tests can compare against a second request.
+func generateValue(c Currency, date time.Time) decimal.Decimal {
+	return oscillate(c.CharCode, c.Base, date).Round(4)
+}
+
+func oscillate(key string, base decimal.Decimal, date time.Time) decimal.Decimal {
+	h := fnv.New32a()
+	h.Write([]byte(key))
+	phase := float64(h.Sum32()%1000) / 1000 * 2 * math.Pi
+
+	days := date.Sub(epoch).Hours() / 24
+	factor := 1 + 0.03*math.Sin(2*math.Pi*days/97+phase) + 0.01*math.Sin(2*math.Pi*days/13+phase)
+
+	return base.Mul(decimal.NewFromFloat(factor))
+}
+
+// publicationDate maps a requested date to the date of the rates CBR would
+// return for it. With weekend shifts enabled rates are only set for Tuesday
+// to Saturday: Sunday and Monday requests get Saturday's rates.
+func publicationDate(date time.Time, weekendShift bool) time.Time {
+	if !weekendShift {
+		return date
+	}
+	switch date.Weekday() {
+	case time.Sunday:
+		return date.AddDate(0, 0, -1)
+	case time.Monday:
+		return date.AddDate(0, 0, -2)
+	}
+	return date
+}
+
+func formatValue(d decimal.Decimal) string {
+	return strings.Replace(d.StringFixed(4), ".", ",", 1)
+}
+
+func generateDaily(currencies []Currency, date time.Time) *cbr.ValCurs {
+	valCurs := &cbr.ValCurs{
+		Date: date.Format("02.01.2006"),
+		Name: "Foreign Currency Market",
+	}
+	for _, c := range currencies {
+		if c.Monthly {
+			continue
+		}
+		value := generateValue(c, date)
+		valCurs.Valutes = append(valCurs.Valutes, cbr.Valute{
+			ID:        c.ID,
+			NumCode:   c.NumCode,
+			CharCode:  c.CharCode,
+			Nominal:   c.Nominal,
+			Name:      c.Name,
+			Value:     formatValue(value),
+			VunitRate: formatValue(value.Div(decimal.NewFromInt(int64(c.Nominal)))),
+		})
+	}
+	return valCurs
+}
+
+// generateMetals returns the prices set on date; CBR quotes buy and sell at
+// the same discount price.
+func generateMetals(metals []Metal, date time.Time) []cbr.MetallRecord {
+	records := make([]cbr.MetallRecord, 0, len(metals))
+	for _, m := range metals {
+		price := strings.Replace(oscillate(fmt.Sprintf("metal-%d", m.Code), m.Base, date).StringFixed(2), ".", ",", 1)
+		records = append(records, cbr.MetallRecord{
+			Date: date.Format("02.01.2006"),
+			Code: m.Code,
+			Buy:  price,
+			Sell: price,
+		})
+	}
+	return records
+}
+
+func generateCatalog(currencies []Currency, monthly bool) *cbr.Valuta {
+	valuta := &cbr.Valuta{Name: "Foreign Currency Market Lib"}
+	for _, c := range currencies {
+		if c.Monthly != monthly {
+			continue
+		}
+		valuta.Items = append(valuta.Items, cbr.ValutaItem{
+			ID:          c.ID,
+			Name:        c.Name,
+			EngName:     c.EngName,
+			Nominal:     c.Nominal,
+			ParentCode:  padParentCode(c.ID),
+			ISONumCode:  c.NumCode,
+			ISOCharCode: c.CharCode,
+		})
+	}
+	return valuta
+}
+
+// padParentCode mimics CBR, which pads ParentCode with spaces to 10 characters.
+func padParentCode(id string) string {
+	if len(id) >= 10 {
+		return id
+	}
+	return id + strings.Repeat(" ", 10-len(id))
+}
@@ -0,0 +1,322 @@
+// Package fakecbr is a stand-in for the CBR XML API (www.cbr.ru/scripts) for
+// local development and end-to-end tests. It serves XML_daily.asp,
+// XML_dynamic.asp, XML_val.asp and XML_metall.asp in windows-1251 like the
+// real service, from fixtures or a deterministic generator, and can inject
+// latency and failures. The KeyRate and Ruonia methods of the DailyInfo SOAP
+// service are served at /DailyInfoWebServ/DailyInfo.asmx.
+package fakecbr
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io/fs"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"RnD-service/internal/adapter/cbr"
+
+	"github.com/sirupsen/logrus"
+	"golang.org/x/text/encoding/charmap"
+)
+
+const maintenancePage = `<!DOCTYPE html>
+<html lang="ru">
+<head><meta charset="windows-1251"><title>Банк России</title></head>
+<body><h1>Сервис временно недоступен</h1><p>Ведутся технические работы. Попробуйте повторить запрос позже.</p></body>
+</html>`
+
+type Config struct {
+	// Currencies drives the generator and XML_val.asp; DefaultCurrencies when empty.
+	Currencies []Currency
+	// Metals drives XML_metall.asp; DefaultMetals when empty.
+	Metals []Metal
+	// KeyRateDecisions drives KeyRate and Ruonia, sorted by date;
+	// DefaultKeyRateDecisions when empty.
+	KeyRateDecisions []KeyRateDecision
+	// Fixtures holds daily/<YYYY-MM-DD>.xml files in CBR format; they take
+	// precedence over generated rates for their dates.
+	Fixtures fs.FS
+
+	// WeekendShift serves Saturday's rates for Sunday and Monday, as CBR does.
+	WeekendShift bool
+	Latency      time.Duration
+	Jitter       time.Duration
+	// ErrorRate is the share of requests, 0..1, answered with ErrorStatus and
+	// CBR's HTML maintenance page.
+	ErrorRate   float64
+	ErrorStatus int
+	Seed        uint64
+
+	// Now is used for requests without date_req; time.Now when nil.
+	Now func() time.Time
+}
+
+type Server struct {
+	cfg      Config
+	fixtures map[string]*cbr.ValCurs
+	mux      *http.ServeMux
+	logger   *logrus.Logger
+
+	mu         sync.Mutex
+	rnd        *rand.Rand
+	failNext   int
+	failStatus int
+	requests   map[string]int
+}
+
+func NewServer(cfg Config, logger *logrus.Logger) (*Server, error) {
+	if len(cfg.Currencies) == 0 {
+		cfg.Currencies = DefaultCurrencies
+	}
+	if len(cfg.Metals) == 0 {
+		cfg.Metals = DefaultMetals
+	}
+	if len(cfg.KeyRateDecisions) == 0 {
+		cfg.KeyRateDecisions = DefaultKeyRateDecisions
+	}
+	if cfg.ErrorStatus == 0 {
+		cfg.ErrorStatus = http.StatusServiceUnavailable
+	}
+	if cfg.Now == nil {
+		cfg.Now = time.Now
+	}
+
+	fixtures, err := loadFixtures(cfg.Fixtures)
+	if err != nil {
+		return nil, fmt.Errorf("load fixtures: %w", err)
+	}
+
+	s := &Server{
+		cfg:      cfg,
+		fixtures: fixtures,
+		mux:      http.NewServeMux(),
+		logger:   logger,
+		rnd:      rand.New(rand.NewPCG(cfg.Seed, cfg.Seed)),
+		requests: make(map[string]int),
+	}
+	s.mux.HandleFunc("/scripts/XML_daily.asp", s.withFaults("XML_daily.asp", s.daily))
+	s.mux.HandleFunc("/scripts/XML_dynamic.asp", s.withFaults("XML_dynamic.asp", s.dynamic))
+	s.mux.HandleFunc("/scripts/XML_val.asp", s.withFaults("XML_val.asp", s.catalog))
+	s.mux.HandleFunc("/scripts/XML_metall.asp", s.withFaults("XML_metall.asp", s.metals))
+	s.mux.HandleFunc("/DailyInfoWebServ/DailyInfo.asmx", s.withFaults("DailyInfo.asmx", s.dailyInfo))
+	s.mux.HandleFunc("/_fake/fail", s.control)
+
+	return s, nil
+}
+
+// FailNext makes the next n CBR requests fail with status, regardless of ErrorRate.
+func (s *Server) FailNext(n, status int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.failNext = n
+	s.failStatus = status
+}
+
+// Requests returns how many requests were made to the given script, e.g. "XML_daily.asp".
+func (s *Server) Requests(script string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.requests[script]
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mux.ServeHTTP(w, r)
+}
+
+func (s *Server) withFaults(script string, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		s.logger.Infof("Fake CBR request: %s", r.URL.RequestURI())
+
+		delay, status := s.nextFault(script)
+		if delay > 0 {
+			select {
+			case <-time.After(delay):
+			case <-r.Context().Done():
+				return
+			}
+		}
+		if status != 0 {
+			s.logger.Warnf("Fake CBR injecting status %d for %s", status, r.URL.RequestURI())
+			body, _ := charmap.Windows1251.NewEncoder().String(maintenancePage)
+			w.Header().Set("Content-Type", "text/html; charset=windows-1251")
+			if status == http.StatusTooManyRequests || status == http.StatusServiceUnavailable {
+				w.Header().Set("Retry-After", "1")
+			}
+			w.WriteHeader(status)
+			fmt.Fprint(w, body)
+			return
+		}
+
+		next(w, r)
+	}
+}
+
+func (s *Server) nextFault(script string) (time.Duration, int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.requests[script]++
+
+	delay := s.cfg.Latency
+	if s.cfg.Jitter > 0 {
+		delay += time.Duration(s.rnd.Int64N(int64(s.cfg.Jitter)))
+	}
+
+	if s.failNext > 0 {
+		s.failNext--
+		return delay, s.failStatus
+	}
+	if s.cfg.ErrorRate > 0 && s.rnd.Float64() < s.cfg.ErrorRate {
+		return delay, s.cfg.ErrorStatus
+	}
+	return delay, 0
+}
+
+// control handles POST /_fake/fail?count=3&status=503 so failures can be
+// injected into a running container.
+func (s *Server) control(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+	count, err := strconv.Atoi(r.URL.Query().Get("count"))
+	if err != nil || count < 0 {
+		http.Error(w, "count must be a non-negative integer", http.StatusBadRequest)
+		return
+	}
+	status := s.cfg.ErrorStatus
+	if v := r.URL.Query().Get("status"); v != "" {
+		if status, err = strconv.Atoi(v); err != nil || status < 100 || status > 599 {
+			http.Error(w, "invalid status", http.StatusBadRequest)
+			return
+		}
+	}
+
+	s.FailNext(count, status)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) daily(w http.ResponseWriter, r *http.Request) {
+	date := s.today()
+	if v := r.URL.Query().Get("date_req"); v != "" {
+		parsed, err := time.Parse("02/01/2006", v)
+		if err != nil {
+			s.writeXML(w, &cbr.ValCurs{Message: "Error in parameters"})
+			return
+		}
+		date = parsed
+	}
+
+	// CBR publishes tomorrow's rates in the afternoon; anything later gets the latest set.
+	if latest := s.today().AddDate(0, 0, 1); date.After(latest) {
+		date = latest
+	}
+
+	s.writeXML(w, s.ratesFor(publicationDate(date, s.cfg.WeekendShift)))
+}
+
+func (s *Server) dynamic(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	from, errFrom := time.Parse("02/01/2006", q.Get("date_req1"))
+	to, errTo := time.Parse("02/01/2006", q.Get("date_req2"))
+	id := q.Get("VAL_NM_RQ")
+	if errFrom != nil || errTo != nil || id == "" {
+		s.writeXML(w, &cbr.ValCursDynamic{Message: "Error in parameters"})
+		return
+	}
+
+	resp := &cbr.ValCursDynamic{
+		ID:         id,
+		DateRange1: from.Format("02.01.2006"),
+		DateRange2: to.Format("02.01.2006"),
+		Name:       "Foreign Currency Market Dynamic",
+	}
+	for d := from; !d.After(to); d = d.AddDate(0, 0, 1) {
+		if !publicationDate(d, s.cfg.WeekendShift).Equal(d) {
+			continue
+		}
+		for _, v := range s.ratesFor(d).Valutes {
+			if v.ID == id {
+				resp.Records = append(resp.Records, cbr.Record{
+					Date:      d.Format("02.01.2006"),
+					ID:        id,
+					Nominal:   v.Nominal,
+					Value:     v.Value,
+					VunitRate: v.VunitRate,
+				})
+			}
+		}
+	}
+
+	s.writeXML(w, resp)
+}
+
+func (s *Server) catalog(w http.ResponseWriter, r *http.Request) {
+	s.writeXML(w, generateCatalog(s.cfg.Currencies, r.URL.Query().Get("d") == "1"))
+}
+
+func (s *Server) metals(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	from, errFrom := time.Parse("02/01/2006", q.Get("date_req1"))
+	to, errTo := time.Parse("02/01/2006", q.Get("date_req2"))
+	if errFrom != nil || errTo != nil {
+		s.writeXML(w, &cbr.Metall{Message: "Error in parameters"})
+		return
+	}
+
+	resp := &cbr.Metall{
+		FromDate: from.Format("20060102"),
+		ToDate:   to.Format("20060102"),
+		Name:     "Precious metals quotations",
+	}
+	if latest := s.today().AddDate(0, 0, 1); to.After(latest) {
+		to = latest
+	}
+	// metal prices follow the same publication days as currency rates
+	for d := from; !d.After(to); d = d.AddDate(0, 0, 1) {
+		if !publicationDate(d, s.cfg.WeekendShift).Equal(d) {
+			continue
+		}
+		resp.Records = append(resp.Records, generateMetals(s.cfg.Metals, d)...)
+	}
+
+	s.writeXML(w, resp)
+}
+
+func (s *Server) ratesFor(date time.Time) *cbr.ValCurs {
+	if fixture, ok := s.fixtures[date.Format("2006-01-02")]; ok {
+		return fixture
+	}
+	return generateDaily(s.cfg.Currencies, date)
+}
+
+func (s *Server) today() time.Time {
+	now := s.cfg.Now()
+	return time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+func (s *Server) writeXML(w http.ResponseWriter, v any) {
+	var buf bytes.Buffer
+	buf.WriteString(`<?xml version="1.0" encoding="windows-1251"?>`)
+	if err := xml.NewEncoder(&buf).Encode(v); err != nil {
+		s.logger.Errorf("Fake CBR failed to encode response: %v", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	body, err := charmap.Windows1251.NewEncoder().Bytes(buf.Bytes())
+	if err != nil {
+		s.logger.Errorf("Fake CBR failed to encode response as windows-1251: %v", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/xml; charset=windows-1251")
+	w.Write(body)
+}
@@ -0,0 +1,213 @@
+package fakecbr
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"RnD-service/internal/adapter/cbr"
+
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Friday, 1 August 2025.
+var testNow = time.Date(2025, 8, 1, 15, 0, 0, 0, time.UTC)
+
+func setupFakeCBR(t *testing.T, cfg Config) (*Server, *cbr.Client) {
+	logger, _ := test.NewNullLogger()
+	if cfg.Now == nil {
+		cfg.Now = func() time.Time { return testNow }
+	}
+	server, err := NewServer(cfg, logger)
+	require.NoError(t, err)
+
+	srv := httptest.NewServer(server)
+	t.Cleanup(srv.Close)
+
+	client, err := cbr.NewClient(logger,
+		cbr.WithBaseURL(srv.URL+"/scripts"),
+		cbr.WithDailyInfoURL(srv.URL+"/DailyInfoWebServ/DailyInfo.asmx"),
+		cbr.WithPolicy(cbr.Policy{Retry: cbr.RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond, MaxDelay: time.Second}}),
+	)
+	require.NoError(t, err)
+	return server, client
+}
+
+func TestServer_DailyFromFixtures(t *testing.T) {
+	_, client := setupFakeCBR(t, Config{Fixtures: DefaultFixtures})
+
+	vc, err := client.FetchRates(context.Background(), "12/01/2023")
+	require.NoError(t, err)
+	assert.Equal(t, "12.01.2023", vc.Date)
+	require.Len(t, vc.Valutes, 2)
+	assert.Equal(t, "USD", vc.Valutes[0].CharCode)
+	assert.Equal(t, "Доллар США", vc.Valutes[0].Name)
+	assert.Equal(t, "69,0202", vc.Valutes[0].Value)
+}
+
+func TestServer_DailyGenerated(t *testing.T) {
+	_, client := setupFakeCBR(t, Config{})
+
+	first, err := client.FetchRates(context.Background(), "15/07/2025")
+	require.NoError(t, err)
+	assert.Equal(t, "15.07.2025", first.Date)
+	assert.Len(t, first.Valutes, len(DefaultCurrencies)-1, "monthly currencies are not in the daily list")
+
+	second, err := client.FetchRates(context.Background(), "15/07/2025")
+	require.NoError(t, err)
+	assert.Equal(t, first, second, "generator must be deterministic")
+
+	other, err := client.FetchRates(context.Background(), "16/07/2025")
+	require.NoError(t, err)
+	assert.NotEqual(t, first.Valutes[0].Value, other.Valutes[0].Value)
+}
+
+func TestServer_DailyEncoding(t *testing.T) {
+	server, _ := setupFakeCBR(t, Config{})
+
+	rec := httptest.NewRecorder()
+	server.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/scripts/XML_daily.asp?date_req=15/07/2025", nil))
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "application/xml; charset=windows-1251", rec.Header().Get("Content-Type"))
+	body, _ := io.ReadAll(rec.Body)
+	assert.Contains(t, string(body), `<?xml version="1.0" encoding="windows-1251"?><ValCurs Date="15.07.2025" name="Foreign Currency Market">`)
+	assert.False(t, utf8.Valid(body), "Cyrillic names must be windows-1251, not UTF-8")
+}
+
+func TestServer_WeekendShift(t *testing.T) {
+	_, client := setupFakeCBR(t, Config{WeekendShift: true})
+
+	// Sunday and Monday get Saturday's rates.
+	for _, date := range []string{"27/07/2025", "28/07/2025"} {
+		vc, err := client.FetchRates(context.Background(), date)
+		require.NoError(t, err)
+		assert.Equal(t, "26.07.2025", vc.Date, date)
+	}
+
+	vc, err := client.FetchRates(context.Background(), "29/07/2025")
+	require.NoError(t, err)
+	assert.Equal(t, "29.07.2025", vc.Date)
+}
+
+func TestServer_FutureDate(t *testing.T) {
+	_, client := setupFakeCBR(t, Config{})
+
+	vc, err := client.FetchRates(context.Background(), "20/08/2025")
+	require.NoError(t, err)
+	assert.Equal(t, "02.08.2025", vc.Date, "latest published set is tomorrow's")
+}
+
+func TestServer_Dynamic(t *testing.T) {
+	_, client := setupFakeCBR(t, Config{WeekendShift: true})
+
+	dyn, err := client.FetchDynamicRates(context.Background(), "R01235", "25/07/2025", "29/07/2025")
+	require.NoError(t, err)
+	require.Len(t, dyn.Records, 3, "no publications for Sunday and Monday")
+	assert.Equal(t, "25.07.2025", dyn.Records[0].Date)
+	assert.Equal(t, "26.07.2025", dyn.Records[1].Date)
+	assert.Equal(t, "29.07.2025", dyn.Records[2].Date)
+
+	daily, err := client.FetchRates(context.Background(), "26/07/2025")
+	require.NoError(t, err)
+	for _, v := range daily.Valutes {
+		if v.CharCode == "USD" {
+			assert.Equal(t, v.Value, dyn.Records[1].Value)
+		}
+	}
+
+	_, err = client.FetchDynamicRates(context.Background(), "R01235", "bad", "29/07/2025")
+	assert.ErrorIs(t, err, cbr.ErrNoData)
+}
+
+func TestServer_Catalog(t *testing.T) {
+	_, client := setupFakeCBR(t, Config{})
+
+	daily, err := client.FetchCurrencyCatalog(context.Background(), false)
+	require.NoError(t, err)
+	assert.Len(t, daily.Items, len(DefaultCurrencies)-1)
+	assert.Equal(t, "R01010    ", daily.Items[0].ParentCode)
+
+	monthly, err := client.FetchCurrencyCatalog(context.Background(), true)
+	require.NoError(t, err)
+	require.Len(t, monthly.Items, 1)
+	assert.Equal(t, "BOB", monthly.Items[0].ISOCharCode)
+}
+
+func TestServer_FailNext(t *testing.T) {
+	server, client := setupFakeCBR(t, Config{})
+	server.FailNext(2, http.StatusBadGateway)
+
+	_, err := client.FetchRates(context.Background(), "15/07/2025")
+	require.NoError(t, err, "client retries through injected failures")
+	assert.Equal(t, 3, server.Requests("XML_daily.asp"))
+}
+
+func TestServer_ErrorRate(t *testing.T) {
+	_, client := setupFakeCBR(t, Config{ErrorRate: 1, ErrorStatus: http.StatusInternalServerError})
+
+	_, err := client.FetchRates(context.Background(), "15/07/2025")
+	require.Error(t, err)
+	assert.ErrorIs(t, err, cbr.ErrUnexpectedStatus)
+	assert.ErrorContains(t, err, "Сервис временно недоступен")
+}
+
+func TestServer_Latency(t *testing.T) {
+	_, client := setupFakeCBR(t, Config{Latency: 100 * time.Millisecond})
+
+	start := time.Now()
+	_, err := client.FetchRates(context.Background(), "15/07/2025")
+	require.NoError(t, err)
+	assert.GreaterOrEqual(t, time.Since(start), 100*time.Millisecond)
+}
+
+func TestServer_ControlEndpoint(t *testing.T) {
+	server, _ := setupFakeCBR(t, Config{})
+
+	rec := httptest.NewRecorder()
+	server.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/_fake/fail?count=1&status=429", nil))
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+
+	rec = httptest.NewRecorder()
+	server.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/scripts/XML_val.asp?d=0", nil))
+	assert.Equal(t, http.StatusTooManyRequests, rec.Code)
+	assert.Equal(t, "1", rec.Header().Get("Retry-After"))
+
+	rec = httptest.NewRecorder()
+	server.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/scripts/XML_val.asp?d=0", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	rec = httptest.NewRecorder()
+	server.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/_fake/fail?count=x", nil))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func TestServer_Metals(t *testing.T) {
+	server, client := setupFakeCBR(t, Config{WeekendShift: true})
+
+	metall, err := client.FetchMetalPrices(context.Background(), "25/07/2025", "29/07/2025")
+	require.NoError(t, err)
+	require.Len(t, metall.Records, 3*len(DefaultMetals), "no prices for Sunday and Monday")
+	assert.Equal(t, "25.07.2025", metall.Records[0].Date)
+	assert.Equal(t, "AU", metall.Records[0].MetalCode())
+	assert.Equal(t, metall.Records[0].Buy, metall.Records[0].Sell)
+	assert.Equal(t, "29.07.2025", metall.Records[len(metall.Records)-1].Date)
+	assert.Equal(t, 1, server.Requests("XML_metall.asp"))
+
+	again, err := client.FetchMetalPrices(context.Background(), "25/07/2025", "25/07/2025")
+	require.NoError(t, err)
+	assert.Equal(t, metall.Records[:len(DefaultMetals)], again.Records)
+
+	future, err := client.FetchMetalPrices(context.Background(), "10/08/2025", "12/08/2025")
+	require.NoError(t, err)
+	assert.Empty(t, future.Records)
+
+	_, err = client.FetchMetalPrices(context.Background(), "bad", "29/07/2025")
+	assert.ErrorIs(t, err, cbr.ErrNoData)
+}
@@ -4,11 +4,14 @@ import (
 	"log"
 	"os"
 	"strings"
+	"time"
 
 	"github.com/sirupsen/logrus"
 )
 
-func Init(level string) *logrus.Logger {
+// Init builds the logger passed to every layer. format is "text" or "json";
+// entries logged with a context carrying a request ID get a request_id field.
+func Init(level, format string) *logrus.Logger {
 	logger := logrus.New()
 
 	lvl, err := logrus.ParseLevel(strings.ToLower(level))
@@ -18,9 +21,23 @@ func Init(level string) *logrus.Logger {
 	}
 	logger.SetLevel(lvl)
 
-	logger.SetFormatter(&logrus.TextFormatter{
-		FullTimestamp: true,
-	})
+	switch strings.ToLower(format) {
+	case "json":
+		logger.SetFormatter(&logrus.JSONFormatter{
+			TimestampFormat: time.RFC3339Nano,
+		})
+	case "", "text":
+		logger.SetFormatter(&logrus.TextFormatter{
+			FullTimestamp: true,
+		})
+	default:
+		logger.SetFormatter(&logrus.TextFormatter{
+			FullTimestamp: true,
+		})
+		logger.Warnf("bad log format %q, set default 'text'", format)
+	}
+
+	logger.AddHook(ContextHook{})
 
 	log.SetOutput(os.Stdout)
 
@@ -0,0 +1,109 @@
+package logger
+
+import (
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+)
+
+const redacted = "xxxxx"
+
+// RedactHook masks secrets, such as the database password, in the message and
+// in string and error fields of every entry.
+type RedactHook struct {
+	mu       sync.RWMutex
+	secrets  map[string]struct{}
+	replacer *strings.Replacer
+}
+
+// NewRedactHook ignores empty secrets.
+func NewRedactHook(secrets ...string) *RedactHook {
+	h := &RedactHook{secrets: make(map[string]struct{}), replacer: strings.NewReplacer()}
+	h.Add(secrets...)
+	return h
+}
+
+// Add registers secrets that are only known at runtime, such as webhook
+// signing secrets. Empty and already registered secrets are ignored.
+func (h *RedactHook) Add(secrets ...string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	added := false
+	for _, secret := range secrets {
+		if _, ok := h.secrets[secret]; ok || secret == "" {
+			continue
+		}
+		h.secrets[secret] = struct{}{}
+		added = true
+	}
+	if !added {
+		return
+	}
+
+	// longer secrets first, so one that contains another is masked whole
+	ordered := make([]string, 0, len(h.secrets))
+	for secret := range h.secrets {
+		ordered = append(ordered, secret)
+	}
+	sort.Slice(ordered, func(i, j int) bool { return len(ordered[i]) > len(ordered[j]) })
+
+	pairs := make([]string, 0, 2*len(ordered))
+	for _, secret := range ordered {
+		pairs = append(pairs, secret, redacted)
+	}
+	h.replacer = strings.NewReplacer(pairs...)
+}
+
+func (h *RedactHook) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+func (h *RedactHook) Fire(entry *logrus.Entry) error {
+	h.mu.RLock()
+	replacer := h.replacer
+	h.mu.RUnlock()
+
+	entry.Message = replacer.Replace(entry.Message)
+	for key, value := range entry.Data {
+		switch v := value.(type) {
+		case string:
+			entry.Data[key] = replacer.Replace(v)
+		case error:
+			if msg := v.Error(); replacer.Replace(msg) != msg {
+				entry.Data[key] = replacer.Replace(msg)
+			}
+		}
+	}
+	return nil
+}
+
+// RedactURL masks the userinfo and the query of a URL before it is logged,
+// both often carry credentials. Unparsable input is masked entirely.
+func RedactURL(raw string) string {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return redacted
+	}
+	if u.User != nil {
+		u.User = url.User(redacted)
+	}
+	if u.RawQuery != "" {
+		u.RawQuery = redacted
+	}
+	return u.String()
+}
+
+// URLPassword returns the password in the userinfo of a URL, such as a proxy
+// URL, so it can be registered with NewRedactHook.
+func URLPassword(raw string) string {
+	u, err := url.Parse(raw)
+	if err != nil || u.User == nil {
+		return ""
+	}
+	password, _ := u.User.Password()
+	return password
+}
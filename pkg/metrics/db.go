@@ -0,0 +1,127 @@
+package metrics
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// QueryTracer times every query and batch for db_query_duration_seconds; set
+// it as the pool's tracer with postgres.WithQueryTracer.
+func (m *Metrics) QueryTracer() pgx.QueryTracer {
+	return &queryTracer{queries: m.dbQueries}
+}
+
+type queryTracer struct {
+	queries *prometheus.HistogramVec
+}
+
+type traceKey struct{}
+
+type traceStart struct {
+	operation string
+	at        time.Time
+}
+
+func (t *queryTracer) TraceQueryStart(ctx context.Context, _ *pgx.Conn, data pgx.TraceQueryStartData) context.Context {
+	return context.WithValue(ctx, traceKey{}, traceStart{operation: operationOf(data.SQL), at: time.Now()})
+}
+
+func (t *queryTracer) TraceQueryEnd(ctx context.Context, _ *pgx.Conn, data pgx.TraceQueryEndData) {
+	t.observe(ctx, data.Err)
+}
+
+// pgx traces batches separately from single queries; a batch is observed once.
+func (t *queryTracer) TraceBatchStart(ctx context.Context, _ *pgx.Conn, _ pgx.TraceBatchStartData) context.Context {
+	return context.WithValue(ctx, traceKey{}, traceStart{operation: "batch", at: time.Now()})
+}
+
+func (t *queryTracer) TraceBatchQuery(context.Context, *pgx.Conn, pgx.TraceBatchQueryData) {}
+
+func (t *queryTracer) TraceBatchEnd(ctx context.Context, _ *pgx.Conn, data pgx.TraceBatchEndData) {
+	t.observe(ctx, data.Err)
+}
+
+func (t *queryTracer) observe(ctx context.Context, err error) {
+	start, ok := ctx.Value(traceKey{}).(traceStart)
+	if !ok {
+		return
+	}
+	status := "ok"
+	if err != nil {
+		status = "error"
+	}
+	t.queries.WithLabelValues(start.operation, status).Observe(time.Since(start.at).Seconds())
+}
+
+var operations = map[string]bool{
+	"select": true, "insert": true, "update": true, "delete": true,
+	"with": true, "refresh": true, "create": true, "drop": true, "alter": true,
+}
+
+// operationOf returns the leading SQL keyword, or "other" for anything
+// unexpected, keeping the label bounded.
+func operationOf(sql string) string {
+	fields := strings.Fields(sql)
+	if len(fields) == 0 {
+		return "other"
+	}
+	op := strings.ToLower(fields[0])
+	if !operations[op] {
+		return "other"
+	}
+	return op
+}
+
+// poolCollector reads pgxpool statistics on every scrape.
+type poolCollector struct {
+	stat func() *pgxpool.Stat
+
+	acquired      *prometheus.Desc
+	idle          *prometheus.Desc
+	total         *prometheus.Desc
+	max           *prometheus.Desc
+	acquires      *prometheus.Desc
+	emptyAcquires *prometheus.Desc
+	canceled      *prometheus.Desc
+	acquireTime   *prometheus.Desc
+}
+
+func newPoolCollector(stat func() *pgxpool.Stat) *poolCollector {
+	desc := func(name, help string) *prometheus.Desc {
+		return prometheus.NewDesc(prometheus.BuildFQName(namespace, "db_pool", name), help, nil, nil)
+	}
+	return &poolCollector{
+		stat:          stat,
+		acquired:      desc("acquired_connections", "Connections currently checked out of the pool."),
+		idle:          desc("idle_connections", "Idle connections in the pool."),
+		total:         desc("total_connections", "Open connections, acquired, idle or being established."),
+		max:           desc("max_connections", "Maximum size of the pool."),
+		acquires:      desc("acquires_total", "Successful connection acquisitions."),
+		emptyAcquires: desc("empty_acquires_total", "Acquisitions that had to wait for a connection."),
+		canceled:      desc("canceled_acquires_total", "Acquisitions canceled by their context."),
+		acquireTime:   desc("acquire_duration_seconds_total", "Total time spent acquiring connections."),
+	}
+}
+
+func (c *poolCollector) Describe(ch chan<- *prometheus.Desc) {
+	for _, d := range []*prometheus.Desc{c.acquired, c.idle, c.total, c.max, c.acquires, c.emptyAcquires, c.canceled, c.acquireTime} {
+		ch <- d
+	}
+}
+
+func (c *poolCollector) Collect(ch chan<- prometheus.Metric) {
+	s := c.stat()
+	ch <- prometheus.MustNewConstMetric(c.acquired, prometheus.GaugeValue, float64(s.AcquiredConns()))
+	ch <- prometheus.MustNewConstMetric(c.idle, prometheus.GaugeValue, float64(s.IdleConns()))
+	ch <- prometheus.MustNewConstMetric(c.total, prometheus.GaugeValue, float64(s.TotalConns()))
+	ch <- prometheus.MustNewConstMetric(c.max, prometheus.GaugeValue, float64(s.MaxConns()))
+	ch <- prometheus.MustNewConstMetric(c.acquires, prometheus.CounterValue, float64(s.AcquireCount()))
+	ch <- prometheus.MustNewConstMetric(c.emptyAcquires, prometheus.CounterValue, float64(s.EmptyAcquireCount()))
+	ch <- prometheus.MustNewConstMetric(c.canceled, prometheus.CounterValue, float64(s.CanceledAcquireCount()))
+	ch <- prometheus.MustNewConstMetric(c.acquireTime, prometheus.CounterValue, s.AcquireDuration().Seconds())
+}
@@ -0,0 +1,216 @@
+package metrics
+
+import (
+	"net/http"
+	"net/url"
+	"path"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "rnd"
+
+// Metrics owns the registry served on /metrics. Its Observe methods satisfy the
+// observer interfaces of the instrumented packages, which stay free of Prometheus.
+type Metrics struct {
+	registry *prometheus.Registry
+
+	httpRequests *prometheus.CounterVec
+	httpDuration *prometheus.HistogramVec
+
+	cbrAttempts *prometheus.CounterVec
+	cbrFailures *prometheus.CounterVec
+	cbrDuration *prometheus.HistogramVec
+	cbrPayload  *prometheus.HistogramVec
+
+	dbQueries *prometheus.HistogramVec
+
+	rateLookups *prometheus.CounterVec
+	lastSync    *prometheus.GaugeVec
+	latest      *latestRateCollector
+}
+
+func New() *Metrics {
+	m := &Metrics{
+		registry: prometheus.NewRegistry(),
+
+		httpRequests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "http_requests_total",
+			Help:      "HTTP requests by route template, method and status.",
+		}, []string{"method", "route", "status"}),
+		httpDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "http_request_duration_seconds",
+			Help:      "HTTP request latency by route template, method and status.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"method", "route", "status"}),
+
+		cbrAttempts: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "cbr_fetch_attempts_total",
+			Help:      "Requests sent to CBR, counting every retry.",
+		}, []string{"endpoint"}),
+		cbrFailures: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "cbr_fetch_failures_total",
+			Help:      "CBR requests that failed at the transport or HTTP level.",
+		}, []string{"endpoint"}),
+		cbrDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "cbr_fetch_duration_seconds",
+			Help:      "Latency of a single CBR request.",
+			Buckets:   []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30},
+		}, []string{"endpoint"}),
+		cbrPayload: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "cbr_response_size_bytes",
+			Help:      "Size of successful CBR response bodies.",
+			Buckets:   prometheus.ExponentialBuckets(1024, 4, 8),
+		}, []string{"endpoint"}),
+
+		dbQueries: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "db_query_duration_seconds",
+			Help:      "Latency of DB queries by SQL statement kind and outcome.",
+			Buckets:   []float64{0.001, 0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 5},
+		}, []string{"operation", "status"}),
+
+		rateLookups: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "rate_lookups_total",
+			Help:      "Historical rate lookups answered from the DB (hit) or by fetching the source (miss).",
+		}, []string{"lookup", "result"}),
+		lastSync: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "rate_last_sync_timestamp_seconds",
+			Help:      "Unix time of the last successful daily rate sync.",
+		}, []string{"source"}),
+		latest: newLatestRateCollector(time.Now),
+	}
+
+	m.registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		m.httpRequests, m.httpDuration,
+		m.cbrAttempts, m.cbrFailures, m.cbrDuration, m.cbrPayload,
+		m.dbQueries,
+		m.rateLookups, m.lastSync, m.latest,
+	)
+	return m
+}
+
+// Handler serves the registry in the Prometheus text format.
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{})
+}
+
+// RegisterPool exports the pool statistics, read on every scrape.
+func (m *Metrics) RegisterPool(pool *pgxpool.Pool) {
+	m.registry.MustRegister(newPoolCollector(pool.Stat))
+}
+
+// Middleware labels requests by route template, so path parameters do not
+// multiply series; requests matching no route share the "unmatched" route. It
+// must be registered before ErrorMiddleware to see the status it writes.
+func (m *Metrics) Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+
+		route := c.FullPath()
+		if route == "" {
+			route = "unmatched"
+		}
+		status := strconv.Itoa(c.Writer.Status())
+		m.httpRequests.WithLabelValues(c.Request.Method, route, status).Inc()
+		m.httpDuration.WithLabelValues(c.Request.Method, route, status).Observe(time.Since(start).Seconds())
+	}
+}
+
+// ObserveFetch records one CBR request attempt; the endpoint label is the last
+// path element of rawURL, e.g. XML_daily.asp.
+func (m *Metrics) ObserveFetch(rawURL string, duration time.Duration, size int, err error) {
+	endpoint := endpointOf(rawURL)
+	m.cbrAttempts.WithLabelValues(endpoint).Inc()
+	m.cbrDuration.WithLabelValues(endpoint).Observe(duration.Seconds())
+	if err != nil {
+		m.cbrFailures.WithLabelValues(endpoint).Inc()
+		return
+	}
+	m.cbrPayload.WithLabelValues(endpoint).Observe(float64(size))
+}
+
+func (m *Metrics) ObserveRateLookup(lookup string, hit bool) {
+	result := "miss"
+	if hit {
+		result = "hit"
+	}
+	m.rateLookups.WithLabelValues(lookup, result).Inc()
+}
+
+func (m *Metrics) ObserveRateSync(source string, at time.Time) {
+	m.lastSync.WithLabelValues(source).Set(float64(at.Unix()))
+}
+
+func (m *Metrics) ObserveRatesStored(source string, latest time.Time) {
+	m.latest.observe(source, latest)
+}
+
+func endpointOf(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Path == "" {
+		return "unknown"
+	}
+	return path.Base(u.Path)
+}
+
+// latestRateCollector reports the age of the newest stored publication per
+// source at scrape time, so the gauge keeps growing while no rates arrive.
+type latestRateCollector struct {
+	desc *prometheus.Desc
+	now  func() time.Time
+
+	mu     sync.Mutex
+	latest map[string]time.Time
+}
+
+func newLatestRateCollector(now func() time.Time) *latestRateCollector {
+	return &latestRateCollector{
+		desc: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "rate_latest_age_seconds"),
+			"Seconds since the effective date of the newest stored rate.",
+			[]string{"source"}, nil,
+		),
+		now:    now,
+		latest: map[string]time.Time{},
+	}
+}
+
+func (c *latestRateCollector) observe(source string, date time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if date.After(c.latest[source]) {
+		c.latest[source] = date
+	}
+}
+
+func (c *latestRateCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.desc
+}
+
+func (c *latestRateCollector) Collect(ch chan<- prometheus.Metric) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	now := c.now()
+	for source, date := range c.latest {
+		ch <- prometheus.MustNewConstMetric(c.desc, prometheus.GaugeValue, now.Sub(date).Seconds(), source)
+	}
+}
@@ -0,0 +1,178 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMiddleware_LabelsByRouteTemplate(t *testing.T) {
+	m := New()
+
+	_, r := gin.CreateTestContext(httptest.NewRecorder())
+	r.Use(m.Middleware())
+	r.GET("/admin/alerts/rules/:id", func(c *gin.Context) { c.Status(http.StatusNoContent) })
+
+	for _, target := range []string{"/admin/alerts/rules/1", "/admin/alerts/rules/2", "/nope"} {
+		r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, target, nil))
+	}
+
+	expected := `
+# HELP rnd_http_requests_total HTTP requests by route template, method and status.
+# TYPE rnd_http_requests_total counter
+rnd_http_requests_total{method="GET",route="/admin/alerts/rules/:id",status="204"} 2
+rnd_http_requests_total{method="GET",route="unmatched",status="404"} 1
+`
+	require.NoError(t, testutil.GatherAndCompare(m.registry, strings.NewReader(expected), "rnd_http_requests_total"))
+	assert.Equal(t, 2, testutil.CollectAndCount(m.httpDuration, "rnd_http_request_duration_seconds"))
+}
+
+func TestObserveFetch(t *testing.T) {
+	m := New()
+
+	m.ObserveFetch("https://www.cbr.ru/scripts/XML_daily.asp?date_req=02/01/2006", 200*time.Millisecond, 0, errors.New("503"))
+	m.ObserveFetch("https://www.cbr.ru/scripts/XML_daily.asp?date_req=02/01/2006", 100*time.Millisecond, 4096, nil)
+	m.ObserveFetch("https://www.cbr.ru/DailyInfoWebServ/DailyInfo.asmx", 100*time.Millisecond, 512, nil)
+
+	expected := `
+# HELP rnd_cbr_fetch_attempts_total Requests sent to CBR, counting every retry.
+# TYPE rnd_cbr_fetch_attempts_total counter
+rnd_cbr_fetch_attempts_total{endpoint="DailyInfo.asmx"} 1
+rnd_cbr_fetch_attempts_total{endpoint="XML_daily.asp"} 2
+# HELP rnd_cbr_fetch_failures_total CBR requests that failed at the transport or HTTP level.
+# TYPE rnd_cbr_fetch_failures_total counter
+rnd_cbr_fetch_failures_total{endpoint="XML_daily.asp"} 1
+`
+	require.NoError(t, testutil.GatherAndCompare(m.registry, strings.NewReader(expected), "rnd_cbr_fetch_attempts_total", "rnd_cbr_fetch_failures_total"))
+	assert.Equal(t, 2, testutil.CollectAndCount(m.cbrDuration, "rnd_cbr_fetch_duration_seconds"))
+	assert.Equal(t, 2, testutil.CollectAndCount(m.cbrPayload, "rnd_cbr_response_size_bytes"))
+}
+
+func TestRateObserver(t *testing.T) {
+	m := New()
+	now := time.Date(2025, 8, 2, 12, 0, 0, 0, time.UTC)
+	m.latest.now = func() time.Time { return now }
+
+	m.ObserveRateLookup("date", true)
+	m.ObserveRateLookup("date", true)
+	m.ObserveRateLookup("range", false)
+	m.ObserveRateSync("cbr", time.Unix(1754136000, 0))
+	m.ObserveRatesStored("cbr", time.Date(2025, 8, 2, 0, 0, 0, 0, time.UTC))
+	// an older publication stored later does not lower the latest one
+	m.ObserveRatesStored("cbr", time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC))
+
+	expected := `
+# HELP rnd_rate_last_sync_timestamp_seconds Unix time of the last successful daily rate sync.
+# TYPE rnd_rate_last_sync_timestamp_seconds gauge
+rnd_rate_last_sync_timestamp_seconds{source="cbr"} 1.754136e+09
+# HELP rnd_rate_latest_age_seconds Seconds since the effective date of the newest stored rate.
+# TYPE rnd_rate_latest_age_seconds gauge
+rnd_rate_latest_age_seconds{source="cbr"} 43200
+# HELP rnd_rate_lookups_total Historical rate lookups answered from the DB (hit) or by fetching the source (miss).
+# TYPE rnd_rate_lookups_total counter
+rnd_rate_lookups_total{lookup="date",result="hit"} 2
+rnd_rate_lookups_total{lookup="range",result="miss"} 1
+`
+	require.NoError(t, testutil.GatherAndCompare(m.registry, strings.NewReader(expected),
+		"rnd_rate_last_sync_timestamp_seconds", "rnd_rate_latest_age_seconds", "rnd_rate_lookups_total"))
+}
+
+func TestQueryTracer(t *testing.T) {
+	m := New()
+	tracer := m.QueryTracer()
+
+	ctx := tracer.TraceQueryStart(context.Background(), nil, pgx.TraceQueryStartData{SQL: "SELECT source FROM rates"})
+	tracer.TraceQueryEnd(ctx, nil, pgx.TraceQueryEndData{})
+	ctx = tracer.TraceQueryStart(context.Background(), nil, pgx.TraceQueryStartData{SQL: "\n  INSERT INTO rates VALUES ($1)"})
+	tracer.TraceQueryEnd(ctx, nil, pgx.TraceQueryEndData{Err: errors.New("conflict")})
+
+	batches, ok := tracer.(pgx.BatchTracer)
+	require.True(t, ok)
+	ctx = batches.TraceBatchStart(context.Background(), nil, pgx.TraceBatchStartData{})
+	batches.TraceBatchEnd(ctx, nil, pgx.TraceBatchEndData{})
+
+	assert.Equal(t, 3, testutil.CollectAndCount(m.dbQueries, "rnd_db_query_duration_seconds"))
+	for _, labels := range [][]string{{"select", "ok"}, {"insert", "error"}, {"batch", "ok"}} {
+		assert.Equal(t, uint64(1), histogramCount(t, m, labels...), labels)
+	}
+}
+
+func histogramCount(t *testing.T, m *Metrics, labels ...string) uint64 {
+	families, err := m.registry.Gather()
+	require.NoError(t, err)
+	for _, f := range families {
+		if f.GetName() != "rnd_db_query_duration_seconds" {
+			continue
+		}
+		for _, metric := range f.GetMetric() {
+			pairs := metric.GetLabel()
+			if pairs[0].GetValue() == labels[0] && pairs[1].GetValue() == labels[1] {
+				return metric.GetHistogram().GetSampleCount()
+			}
+		}
+	}
+	return 0
+}
+
+func TestOperationOf(t *testing.T) {
+	tests := map[string]string{
+		"SELECT 1":                           "select",
+		"  with latest AS (SELECT 1) SELECT": "with",
+		"REFRESH MATERIALIZED VIEW latest":   "refresh",
+		"select pg_try_advisory_lock($1)":    "select",
+		"LISTEN channel":                     "other",
+		"":                                   "other",
+	}
+	for sql, want := range tests {
+		assert.Equal(t, want, operationOf(sql), sql)
+	}
+}
+
+func TestRegisterPool(t *testing.T) {
+	m := New()
+
+	// the pool connects lazily, so no server is needed to read its stats
+	config, err := pgxpool.ParseConfig("postgres://user@127.0.0.1:1/db?pool_max_conns=7")
+	require.NoError(t, err)
+	pool, err := pgxpool.NewWithConfig(context.Background(), config)
+	require.NoError(t, err)
+	defer pool.Close()
+
+	m.RegisterPool(pool)
+
+	expected := `
+# HELP rnd_db_pool_acquired_connections Connections currently checked out of the pool.
+# TYPE rnd_db_pool_acquired_connections gauge
+rnd_db_pool_acquired_connections 0
+# HELP rnd_db_pool_max_connections Maximum size of the pool.
+# TYPE rnd_db_pool_max_connections gauge
+rnd_db_pool_max_connections 7
+`
+	require.NoError(t, testutil.GatherAndCompare(m.registry, strings.NewReader(expected), "rnd_db_pool_acquired_connections", "rnd_db_pool_max_connections"))
+	for _, name := range []string{"idle_connections", "total_connections", "acquires_total", "empty_acquires_total", "canceled_acquires_total", "acquire_duration_seconds_total"} {
+		assert.Equal(t, 1, testutil.CollectAndCount(newPoolCollector(pool.Stat), "rnd_db_pool_"+name), name)
+	}
+}
+
+func TestHandler_ServesTextFormat(t *testing.T) {
+	m := New()
+	m.ObserveRateLookup("batch", false)
+
+	w := httptest.NewRecorder()
+	m.Handler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `rnd_rate_lookups_total{lookup="batch",result="miss"} 1`)
+	assert.Contains(t, w.Body.String(), "go_goroutines")
+}
@@ -0,0 +1,70 @@
+// Package netguard keeps requests to user-supplied URLs off internal networks.
+package netguard
+
+import (
+	"errors"
+	"fmt"
+	"net/netip"
+	"strings"
+	"syscall"
+)
+
+var ErrForbiddenAddress = errors.New("address is not allowed")
+
+// sharedAddressSpace is the carrier-grade NAT range, RFC 6598.
+var sharedAddressSpace = netip.MustParsePrefix("100.64.0.0/10")
+
+// Allowlist lists the internal networks requests may still reach.
+type Allowlist []netip.Prefix
+
+// ParseAllowlist reads CIDRs such as "10.20.0.0/16"; a bare address allows just itself.
+func ParseAllowlist(cidrs []string) (Allowlist, error) {
+	list := make(Allowlist, 0, len(cidrs))
+	for _, cidr := range cidrs {
+		cidr = strings.TrimSpace(cidr)
+		if !strings.Contains(cidr, "/") {
+			addr, err := netip.ParseAddr(cidr)
+			if err != nil {
+				return nil, fmt.Errorf("parse %q: %w", cidr, err)
+			}
+			list = append(list, netip.PrefixFrom(addr.Unmap(), addr.Unmap().BitLen()))
+			continue
+		}
+		prefix, err := netip.ParsePrefix(cidr)
+		if err != nil {
+			return nil, fmt.Errorf("parse %q: %w", cidr, err)
+		}
+		list = append(list, prefix.Masked())
+	}
+	return list, nil
+}
+
+// Check rejects loopback, private, link-local, unspecified and multicast
+// addresses that no allowlisted network contains.
+func (a Allowlist) Check(addr netip.Addr) error {
+	addr = addr.Unmap()
+	if !internal(addr) {
+		return nil
+	}
+	for _, prefix := range a {
+		if prefix.Contains(addr) {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: %s is an internal address", ErrForbiddenAddress, addr)
+}
+
+// Control is a net.Dialer Control function checking the address actually
+// dialed, so a host name re-resolved to an internal address is refused too.
+func (a Allowlist) Control(network, address string, _ syscall.RawConn) error {
+	addrPort, err := netip.ParseAddrPort(address)
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrForbiddenAddress, address)
+	}
+	return a.Check(addrPort.Addr())
+}
+
+func internal(addr netip.Addr) bool {
+	return addr.IsLoopback() || addr.IsPrivate() || addr.IsLinkLocalUnicast() || addr.IsLinkLocalMulticast() ||
+		addr.IsInterfaceLocalMulticast() || addr.IsMulticast() || addr.IsUnspecified() || sharedAddressSpace.Contains(addr)
+}
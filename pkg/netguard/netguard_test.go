@@ -0,0 +1,60 @@
+package netguard
+
+import (
+	"net/netip"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAllowlistCheck(t *testing.T) {
+	allow, err := ParseAllowlist([]string{"10.20.0.0/16", "127.0.0.1"})
+	require.NoError(t, err)
+
+	tests := []struct {
+		addr    string
+		allowed bool
+	}{
+		{"93.184.216.34", true},
+		{"2606:2800:220:1:248:1893:25c8:1946", true},
+		{"127.0.0.1", true},
+		{"127.0.0.2", false},
+		{"::1", false},
+		{"10.20.3.4", true},
+		{"10.21.3.4", false},
+		{"192.168.1.10", false},
+		{"172.16.0.1", false},
+		{"169.254.169.254", false},
+		{"fe80::1", false},
+		{"fd00::1", false},
+		{"100.64.0.1", false},
+		{"0.0.0.0", false},
+		{"::ffff:192.168.1.10", false},
+		{"::ffff:10.20.0.1", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.addr, func(t *testing.T) {
+			err := allow.Check(netip.MustParseAddr(tt.addr))
+			if tt.allowed {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, ErrForbiddenAddress)
+			}
+		})
+	}
+}
+
+func TestAllowlistControl(t *testing.T) {
+	var allow Allowlist
+	assert.NoError(t, allow.Control("tcp4", "93.184.216.34:443", nil))
+	assert.ErrorIs(t, allow.Control("tcp4", "127.0.0.1:8080", nil), ErrForbiddenAddress)
+	assert.ErrorIs(t, allow.Control("tcp6", "[::1]:8080", nil), ErrForbiddenAddress)
+}
+
+func TestParseAllowlist_Invalid(t *testing.T) {
+	_, err := ParseAllowlist([]string{"10.0.0.0/33"})
+	assert.Error(t, err)
+	_, err = ParseAllowlist([]string{"intranet"})
+	assert.Error(t, err)
+}
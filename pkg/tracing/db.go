@@ -0,0 +1,77 @@
+package tracing
+
+import (
+	"context"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"go.opentelemetry.io/otel/attribute"
+	semconv "go.opentelemetry.io/otel/semconv/v1.30.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// QueryTracer starts a client span for every query and batch; set it as the
+// pool's tracer with postgres.WithQueryTracer.
+func QueryTracer(tracer trace.Tracer) pgx.QueryTracer {
+	return &queryTracer{tracer: tracer}
+}
+
+type queryTracer struct {
+	tracer trace.Tracer
+}
+
+func (t *queryTracer) TraceQueryStart(ctx context.Context, _ *pgx.Conn, data pgx.TraceQueryStartData) context.Context {
+	operation := operationOf(data.SQL)
+	return t.startSpan(ctx, "postgres "+operation,
+		semconv.DBOperationName(operation),
+		semconv.DBQueryText(data.SQL),
+	)
+}
+
+func (t *queryTracer) TraceQueryEnd(ctx context.Context, _ *pgx.Conn, data pgx.TraceQueryEndData) {
+	endDBSpan(ctx, data.Err)
+}
+
+func (t *queryTracer) TraceBatchStart(ctx context.Context, _ *pgx.Conn, data pgx.TraceBatchStartData) context.Context {
+	size := 0
+	if data.Batch != nil {
+		size = data.Batch.Len()
+	}
+	return t.startSpan(ctx, "postgres batch", semconv.DBOperationBatchSize(size))
+}
+
+func (t *queryTracer) TraceBatchQuery(ctx context.Context, _ *pgx.Conn, data pgx.TraceBatchQueryData) {
+	if data.Err != nil {
+		RecordError(trace.SpanFromContext(ctx), data.Err)
+	}
+}
+
+func (t *queryTracer) TraceBatchEnd(ctx context.Context, _ *pgx.Conn, data pgx.TraceBatchEndData) {
+	endDBSpan(ctx, data.Err)
+}
+
+func (t *queryTracer) startSpan(ctx context.Context, name string, attrs ...attribute.KeyValue) context.Context {
+	ctx, _ = t.tracer.Start(ctx, name,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(semconv.DBSystemNamePostgreSQL),
+		trace.WithAttributes(attrs...),
+	)
+	return ctx
+}
+
+func endDBSpan(ctx context.Context, err error) {
+	span := trace.SpanFromContext(ctx)
+	if err != nil {
+		RecordError(span, err)
+	}
+	span.End()
+}
+
+// operationOf returns the lowercased leading SQL keyword.
+func operationOf(sql string) string {
+	fields := strings.Fields(sql)
+	if len(fields) == 0 {
+		return "query"
+	}
+	return strings.ToLower(fields[0])
+}
@@ -0,0 +1,109 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	semconv "go.opentelemetry.io/otel/semconv/v1.30.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func setupRecorder(sampleRatio float64) (trace.Tracer, *tracetest.SpanRecorder) {
+	recorder := tracetest.NewSpanRecorder()
+	return NewProvider(recorder, "RnD-service-test", sampleRatio).Tracer("test"), recorder
+}
+
+func TestStart_ChildOfContextSpan(t *testing.T) {
+	tracer, recorder := setupRecorder(1)
+
+	ctx, parent := Start(context.Background(), tracer, "parent")
+	_, child := Start(ctx, tracer, "child", attribute.String("rate.source", "cbr"))
+	RecordError(child, errors.New("boom"))
+	child.End()
+	parent.End()
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 2)
+	assert.Equal(t, "child", spans[0].Name())
+	assert.Equal(t, spans[1].SpanContext().SpanID(), spans[0].Parent().SpanID())
+	assert.Contains(t, spans[0].Attributes(), attribute.String("rate.source", "cbr"))
+	assert.Equal(t, codes.Error, spans[0].Status().Code)
+	serviceName, _ := spans[0].Resource().Set().Value(semconv.ServiceNameKey)
+	assert.Equal(t, "RnD-service-test", serviceName.AsString())
+}
+
+func TestStart_NilTracer(t *testing.T) {
+	ctx := context.Background()
+
+	spanCtx, span := Start(ctx, nil, "untraced")
+	span.End()
+
+	assert.Equal(t, ctx, spanCtx)
+	assert.False(t, span.SpanContext().IsValid())
+}
+
+func TestNewProvider_SampleRatio(t *testing.T) {
+	tracer, recorder := setupRecorder(0)
+
+	_, span := Start(context.Background(), tracer, "dropped")
+	span.End()
+
+	assert.False(t, span.SpanContext().IsSampled())
+	assert.Empty(t, recorder.Ended())
+}
+
+func TestQueryTracer(t *testing.T) {
+	tracer, recorder := setupRecorder(1)
+	queries := QueryTracer(tracer)
+
+	ctx, parent := Start(context.Background(), tracer, "RateService.GetRateByCharCodeAndDate")
+	queryCtx := queries.TraceQueryStart(ctx, nil, pgx.TraceQueryStartData{SQL: "SELECT value FROM rates WHERE char_code = $1"})
+	queries.TraceQueryEnd(queryCtx, nil, pgx.TraceQueryEndData{})
+
+	batches := queries.(pgx.BatchTracer)
+	batch := &pgx.Batch{}
+	batch.Queue("INSERT INTO rates VALUES ($1)", 1)
+	batchCtx := batches.TraceBatchStart(ctx, nil, pgx.TraceBatchStartData{Batch: batch})
+	batches.TraceBatchEnd(batchCtx, nil, pgx.TraceBatchEndData{Err: errors.New("conflict")})
+	parent.End()
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 3)
+
+	query := spans[0]
+	assert.Equal(t, "postgres select", query.Name())
+	assert.Equal(t, parent.SpanContext().SpanID(), query.Parent().SpanID())
+	assert.Contains(t, query.Attributes(), attribute.String("db.system.name", "postgresql"))
+	assert.Contains(t, query.Attributes(), attribute.String("db.query.text", "SELECT value FROM rates WHERE char_code = $1"))
+
+	assert.Equal(t, "postgres batch", spans[1].Name())
+	assert.Contains(t, spans[1].Attributes(), attribute.Int("db.operation.batch.size", 1))
+	assert.Equal(t, codes.Error, spans[1].Status().Code)
+}
+
+func TestLogHook(t *testing.T) {
+	tracer, _ := setupRecorder(1)
+	logger, hook := test.NewNullLogger()
+	logger.AddHook(LogHook{})
+
+	ctx, span := Start(context.Background(), tracer, "request")
+	defer span.End()
+
+	logger.WithContext(ctx).Info("traced")
+	logger.Info("untraced")
+
+	require.Len(t, hook.Entries, 2)
+	assert.Equal(t, span.SpanContext().TraceID().String(), hook.Entries[0].Data["trace_id"])
+	assert.Equal(t, span.SpanContext().SpanID().String(), hook.Entries[0].Data["span_id"])
+	assert.NotContains(t, hook.Entries[1].Data, "trace_id")
+	assert.Equal(t, logrus.InfoLevel, hook.Entries[1].Level)
+}
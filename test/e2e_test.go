@@ -5,21 +5,26 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 
 	"RnD-service/internal/adapter/cbr"
 	projectpostgres "RnD-service/internal/adapter/postgres"
+	"RnD-service/internal/adapter/provider"
 	"RnD-service/internal/handler"
 	"RnD-service/internal/service"
 	"RnD-service/internal/usecase"
+	"RnD-service/migrations"
 	"RnD-service/pkg/config"
+	"RnD-service/pkg/fakecbr"
 	"RnD-service/pkg/logger"
 
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
 	"github.com/jackc/pgx/v5/pgxpool"
-	"github.com/sirupsen/logrus"
+	"github.com/shopspring/decimal"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/testcontainers/testcontainers-go"
@@ -27,32 +32,6 @@ import (
 	"github.com/testcontainers/testcontainers-go/wait"
 )
 
-type mockCbrClient struct {
-	logger *logrus.Logger
-}
-
-func (m *mockCbrClient) FetchRates(ctx context.Context, date string) (*cbr.ValCurs, error) {
-	m.logger.Infof("Mock FetchRates called for date: %s", date)
-	if date == "03/08/2025" || date == "12/01/2023" {
-		return &cbr.ValCurs{
-			Date: "12.01.2023",
-			Name: "Foreign Currency Market",
-			Valutes: []cbr.Valute{
-				{
-					ID:        "R01235",
-					NumCode:   "840",
-					CharCode:  "USD",
-					Nominal:   1,
-					Name:      "US Dollar",
-					Value:     "69,0202",
-					VunitRate: "69,0202",
-				},
-			},
-		}, nil
-	}
-	return nil, fmt.Errorf("no data for date %s", date)
-}
-
 func TestE2E(t *testing.T) {
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
@@ -79,7 +58,7 @@ func TestE2E(t *testing.T) {
 	cfg.Log.Level = "debug"
 	cfg.Postgres.SSLMode = "disable" // not used since we use dsn directly
 
-	log := logger.Init(cfg.Log.Level)
+	log := logger.Init(cfg.Log.Level, cfg.Log.Format)
 
 	// Init DB pool with test dsn
 	poolConfig, err := pgxpool.ParseConfig(dsn)
@@ -90,52 +69,50 @@ func TestE2E(t *testing.T) {
 		dbPool.Close()
 	})
 
-	// Run migrations (execute CREATE TABLE statements)
-	conn, err := dbPool.Acquire(ctx)
-	require.NoError(t, err)
-	defer conn.Release()
-
-	_, err = conn.Exec(ctx, `
-		CREATE TABLE IF NOT EXISTS currency_rates (
-		    char_code   VARCHAR(3) PRIMARY KEY,
-		    name        TEXT        NOT NULL,
-		    nominal     INTEGER     NOT NULL CHECK (nominal > 0),
-		    value       NUMERIC(20, 4) NOT NULL CHECK (value >= 0),
-		    num_code    VARCHAR(3),
-		    updated_at  TIMESTAMP   NOT NULL
-		);
-		CREATE UNIQUE INDEX IF NOT EXISTS uniq_currency_char_code ON currency_rates(char_code);
-	`)
+	// Run the embedded migrations
+	migrator, err := projectpostgres.NewMigrator(dbPool, migrations.FS, log)
 	require.NoError(t, err)
+	require.NoError(t, migrator.Up(ctx))
 
-	_, err = conn.Exec(ctx, `
-		CREATE TABLE IF NOT EXISTS historical_currency_rates (
-		    char_code   VARCHAR(3) NOT NULL,
-		    date        DATE NOT NULL,
-		    name        TEXT        NOT NULL,
-		    nominal     INTEGER     NOT NULL CHECK (nominal > 0),
-		    value       NUMERIC(20, 4) NOT NULL CHECK (value >= 0),
-		    num_code    VARCHAR(3),
-		    PRIMARY KEY (char_code, date)
-		);
-		CREATE INDEX IF NOT EXISTS idx_historical_currency_date ON historical_currency_rates(date);
-		CREATE INDEX IF NOT EXISTS idx_historical_currency_char_code ON historical_currency_rates(char_code);
-	`)
+	// Init adapters
+	fakeCBR, err := fakecbr.NewServer(fakecbr.Config{
+		Currencies: []fakecbr.Currency{
+			{ID: "R01235", NumCode: "840", CharCode: "USD", Nominal: 1, Name: "Доллар США", EngName: "US Dollar", Base: decimal.RequireFromString("69.0202")},
+			{ID: "R01239", NumCode: "978", CharCode: "EUR", Nominal: 1, Name: "Евро", EngName: "Euro", Base: decimal.RequireFromString("74.2900")},
+		},
+		Fixtures:     fakecbr.DefaultFixtures,
+		Metals:       []fakecbr.Metal{{Code: 1, Base: decimal.RequireFromString("5000.00")}},
+		WeekendShift: true,
+	}, log)
 	require.NoError(t, err)
+	cbrServer := httptest.NewServer(fakeCBR)
+	t.Cleanup(cbrServer.Close)
 
-	// Init adapters
-	cbrClient := &mockCbrClient{logger: log}
+	cbrClient, err := cbr.NewClient(log,
+		cbr.WithBaseURL(cbrServer.URL+"/scripts"),
+		cbr.WithDailyInfoURL(cbrServer.URL+"/DailyInfoWebServ/DailyInfo.asmx"),
+		cbr.WithPolicy(cbr.Policy{Retry: cbr.RetryPolicy{MaxAttempts: 1}}),
+	)
+	require.NoError(t, err)
 	dbRepo := projectpostgres.NewPostgresRepo(dbPool, log)
 
 	// Init service
 	currencyService := service.NewRateService(cbrClient, dbRepo, log)
 
 	// Init usecase
-	currencyUsecase := usecase.NewCurrencyUsecase(currencyService, log)
+	currencyUsecase := usecase.NewCurrencyUsecase(currencyService, usecase.DefaultRounding, log)
 
 	// Init handler
 	currencyHandler := handler.NewRateHandler(currencyUsecase, log)
 
+	metalHandler := handler.NewMetalHandler(usecase.NewMetalPriceUsecase(service.NewMetalService(cbrClient, dbRepo, log), log), log)
+
+	indicatorHandler := handler.NewIndicatorHandler(usecase.NewIndicatorRateUsecase(service.NewIndicatorService(cbrClient, dbRepo, log), log), log)
+
+	backfillService := service.NewBackfillService(provider.NewCBRProvider(cbrClient, log), dbRepo, dbRepo, service.BackfillOptions{ChunkDays: 1, Concurrency: 2, RequestInterval: time.Millisecond}, log)
+	t.Cleanup(backfillService.Shutdown)
+	backfillHandler := handler.NewBackfillHandler(usecase.NewBackfillUsecase(backfillService, log), log)
+
 	// Setup Gin router
 	r := gin.Default()
 	r.Use(cors.New(cors.Config{
@@ -145,9 +122,19 @@ func TestE2E(t *testing.T) {
 		ExposeHeaders:    []string{"Content-Length"},
 		AllowCredentials: false,
 	}))
+	r.Use(handler.ErrorMiddleware(log))
 
 	r.GET("/currency/rates", currencyHandler.StoreRatesFromCBR)
 	r.GET("/currency/rate", currencyHandler.GetHistoricalRateByCharCode)
+	r.GET("/currency/rates/history", currencyHandler.GetRateHistoryByCharCode)
+	r.GET("/currency/convert", currencyHandler.ConvertCurrency)
+	r.GET("/currency/list", currencyHandler.GetCurrencyList)
+	r.GET("/metals/price", metalHandler.GetMetalPrice)
+	r.GET("/metals/price/history", metalHandler.GetMetalPriceHistory)
+	r.GET("/indicators/keyrate", indicatorHandler.GetKeyRateHistory)
+	r.GET("/indicators/ruonia", indicatorHandler.GetRuoniaHistory)
+	r.POST("/admin/backfill", backfillHandler.StartBackfill)
+	r.GET("/admin/backfill/:id", backfillHandler.GetBackfillJob)
 
 	// Start server in goroutine
 	srv := &http.Server{
@@ -194,13 +181,172 @@ func TestE2E(t *testing.T) {
 		assert.Equal(t, http.StatusOK, resp.StatusCode)
 
 		var result struct {
-			CharName string  `json:"char_name"`
-			ValueRUB float64 `json:"value_rub"`
+			CharName string `json:"char_name"`
+			ValueRUB string `json:"value_rub"`
+		}
+		err = json.NewDecoder(resp.Body).Decode(&result)
+		require.NoError(t, err)
+		assert.Equal(t, "USD", result.CharName)
+		assert.Equal(t, "69.0202", result.ValueRUB)
+	})
+
+	t.Run("GetRateHistoryByCharCode", func(t *testing.T) {
+		resp, err := http.Get("http://localhost:8081/currency/rates/history?val=USD&from=2023-01-10&to=2023-01-12")
+		require.NoError(t, err)
+		defer resp.Body.Close()
+
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var result struct {
+			CharName string `json:"char_name"`
+			Rates    []struct {
+				Date  string `json:"date"`
+				Value string `json:"value"`
+			} `json:"rates"`
 		}
 		err = json.NewDecoder(resp.Body).Decode(&result)
 		require.NoError(t, err)
 		assert.Equal(t, "USD", result.CharName)
-		assert.InDelta(t, 69.0202, result.ValueRUB, 0.0001)
+		require.Len(t, result.Rates, 3)
+		assert.Equal(t, "2023-01-10", result.Rates[0].Date)
+		assert.Equal(t, "69.468", result.Rates[0].Value)
+		assert.Equal(t, "2023-01-12", result.Rates[2].Date)
+	})
+
+	t.Run("ConvertCurrency", func(t *testing.T) {
+		resp, err := http.Get("http://localhost:8081/currency/convert?from=RUB&to=USD&amount=690.202&date=2023-01-12")
+		require.NoError(t, err)
+		defer resp.Body.Close()
+
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var result struct {
+			From   string `json:"from"`
+			To     string `json:"to"`
+			Result string `json:"result"`
+		}
+		err = json.NewDecoder(resp.Body).Decode(&result)
+		require.NoError(t, err)
+		assert.Equal(t, "RUB", result.From)
+		assert.Equal(t, "USD", result.To)
+		assert.Equal(t, "10", result.Result)
+	})
+
+	t.Run("GetCurrencyList", func(t *testing.T) {
+		resp, err := http.Get("http://localhost:8081/currency/list")
+		require.NoError(t, err)
+		defer resp.Body.Close()
+
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var result usecase.CurrencyListResponse
+		err = json.NewDecoder(resp.Body).Decode(&result)
+		require.NoError(t, err)
+		require.Equal(t, 2, result.Count)
+		assert.Equal(t, "EUR", result.Currencies[0].CharCode)
+		assert.Equal(t, "R01235", result.Currencies[1].CbrID)
+	})
+
+	t.Run("GetHistoricalRateByCharCode_UnknownCurrency", func(t *testing.T) {
+		resp, err := http.Get("http://localhost:8081/currency/rate?val=XYZ&date=2023-01-12")
+		require.NoError(t, err)
+		defer resp.Body.Close()
+
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+		var errResp handler.ErrorResponse
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&errResp))
+		assert.Equal(t, handler.CodeUnknownCurrency, errResp.Code)
+	})
+
+	t.Run("Backfill", func(t *testing.T) {
+		resp, err := http.Post("http://localhost:8081/admin/backfill", "application/json", strings.NewReader(`{"from":"2023-01-11","to":"2023-01-12","char_codes":["USD"]}`))
+		require.NoError(t, err)
+		defer resp.Body.Close()
+
+		assert.Equal(t, http.StatusAccepted, resp.StatusCode)
+
+		var job usecase.BackfillJobResponse
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&job))
+		assert.Equal(t, 2, job.DaysTotal)
+
+		require.Eventually(t, func() bool {
+			resp, err := http.Get(fmt.Sprintf("http://localhost:8081/admin/backfill/%d", job.ID))
+			if err != nil {
+				return false
+			}
+			defer resp.Body.Close()
+			var status usecase.BackfillJobResponse
+			if err := json.NewDecoder(resp.Body).Decode(&status); err != nil {
+				return false
+			}
+			return status.Status == "completed" && status.DaysDone == 2
+		}, 5*time.Second, 100*time.Millisecond)
+	})
+
+	t.Run("GetMetalPrice", func(t *testing.T) {
+		// Sunday: the last price was set on Saturday.
+		resp, err := http.Get("http://localhost:8081/metals/price?code=au&date=2023-01-15")
+		require.NoError(t, err)
+		defer resp.Body.Close()
+
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		var result usecase.MetalPriceResponse
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&result))
+		assert.Equal(t, "AU", result.Code)
+		assert.Equal(t, "2023-01-14", result.Date)
+		assert.True(t, result.Sell.IsPositive())
+	})
+
+	t.Run("GetMetalPriceHistory", func(t *testing.T) {
+		resp, err := http.Get("http://localhost:8081/metals/price/history?code=AU&from=2023-01-10&to=2023-01-16")
+		require.NoError(t, err)
+		defer resp.Body.Close()
+
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		var result usecase.MetalPriceHistoryResponse
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&result))
+		require.Len(t, result.Prices, 5, "no prices for Sunday and Monday")
+		assert.Equal(t, "2023-01-10", result.Prices[0].Date)
+		assert.Equal(t, "2023-01-14", result.Prices[4].Date)
+	})
+
+	t.Run("GetMetalPrice_UnknownMetal", func(t *testing.T) {
+		resp, err := http.Get("http://localhost:8081/metals/price?code=CU&date=2023-01-12")
+		require.NoError(t, err)
+		defer resp.Body.Close()
+
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+		var errResp handler.ErrorResponse
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&errResp))
+		assert.Equal(t, handler.CodeUnknownMetal, errResp.Code)
+	})
+
+	t.Run("GetKeyRateHistory", func(t *testing.T) {
+		resp, err := http.Get("http://localhost:8081/indicators/keyrate?from=2023-07-20&to=2023-07-25")
+		require.NoError(t, err)
+		defer resp.Body.Close()
+
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		var result usecase.KeyRateHistoryResponse
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&result))
+		require.Len(t, result.Rates, 4, "no key rate for the weekend")
+		assert.Equal(t, "2023-07-21", result.Rates[1].Date)
+		assert.Equal(t, "7.5", result.Rates[1].Rate.String())
+		assert.Equal(t, "2023-07-24", result.Rates[2].Date)
+		assert.Equal(t, "8.5", result.Rates[2].Rate.String())
+	})
+
+	t.Run("GetRuoniaHistory", func(t *testing.T) {
+		resp, err := http.Get("http://localhost:8081/indicators/ruonia?from=2023-07-20&to=2023-07-25")
+		require.NoError(t, err)
+		defer resp.Body.Close()
+
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		var result usecase.RuoniaHistoryResponse
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&result))
+		require.Len(t, result.Rates, 4)
+		assert.True(t, result.Rates[0].Rate.LessThan(decimal.RequireFromString("7.5")))
+		assert.True(t, result.Rates[0].Volume.IsPositive())
 	})
 
 	t.Run("GetHistoricalRateByCharCode_InvalidDate", func(t *testing.T) {
@@ -209,5 +355,8 @@ func TestE2E(t *testing.T) {
 		defer resp.Body.Close()
 
 		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+		var errResp handler.ErrorResponse
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&errResp))
+		assert.Equal(t, handler.CodeInvalidDate, errResp.Code)
 	})
 }